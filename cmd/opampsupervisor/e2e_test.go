@@ -8,7 +8,12 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
@@ -1262,6 +1267,92 @@ func TestSupervisorWritesAgentFilesToStorageDir(t *testing.T) {
 	require.FileExists(t, filepath.Join(storageDir, "effective.yaml"))
 }
 
+func TestSupervisorInstallsAgentPackage(t *testing.T) {
+	executable, err := os.ReadFile(fmt.Sprintf("../../bin/otelcontribcol_%s_%s", runtime.GOOS, runtime.GOARCH))
+	require.NoError(t, err)
+	testSupervisorAgentPackage(t, executable, protobufs.PackageStatusEnum_PackageStatusEnum_Installed)
+}
+
+func TestSupervisorRollsBackUnhealthyAgentPackage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake agent executable is a shell script")
+	}
+
+	testSupervisorAgentPackage(t, []byte("#!/bin/sh\nexit 1\n"), protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed)
+}
+
+// testSupervisorAgentPackage offers executable as the top level package, and waits for the
+// Supervisor to report its status.
+func testSupervisorAgentPackage(t *testing.T, executable []byte, expectedStatus protobufs.PackageStatusEnum) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	publicKeyFile := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	contentHash := sha256.Sum256(executable)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, contentHash[:])
+	require.NoError(t, err)
+
+	downloadServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(executable)
+	}))
+	t.Cleanup(downloadServer.Close)
+
+	var packageStatus atomic.Value
+	server := newOpAMPServer(
+		t,
+		defaultConnectingHandler,
+		server.ConnectionCallbacksStruct{
+			OnMessageFunc: func(_ context.Context, _ types.Connection, message *protobufs.AgentToServer) *protobufs.ServerToAgent {
+				if status := message.GetPackageStatuses().GetPackages()["otelcol"]; status != nil {
+					packageStatus.Store(status)
+				}
+
+				return &protobufs.ServerToAgent{}
+			},
+		})
+
+	s := newSupervisor(t, "packages", map[string]string{"url": server.addr, "public_key_file": publicKeyFile})
+
+	require.Nil(t, s.Start())
+	defer s.Shutdown()
+
+	waitForSupervisorConnection(server.supervisorConnected, true)
+
+	packageHash := sha256.Sum256([]byte("otelcol-v2"))
+	server.sendToSupervisor(&protobufs.ServerToAgent{
+		PackagesAvailable: &protobufs.PackagesAvailable{
+			Packages: map[string]*protobufs.PackageAvailable{
+				"otelcol": {
+					Type:    protobufs.PackageType_PackageType_TopLevel,
+					Version: "v2",
+					File: &protobufs.DownloadableFile{
+						DownloadUrl: downloadServer.URL,
+						ContentHash: contentHash[:],
+						Signature:   signature,
+					},
+					Hash: packageHash[:],
+				},
+			},
+			AllPackagesHash: packageHash[:],
+		},
+	})
+
+	require.Eventually(t, func() bool {
+		status, ok := packageStatus.Load().(*protobufs.PackageStatus)
+		return ok && status.Status == protobufs.PackageStatusEnum_PackageStatusEnum_Installed
+	}, 10*time.Second, 250*time.Millisecond, "Package was never installed")
+
+	if expectedStatus == protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed {
+		require.Eventually(t, func() bool {
+			status := packageStatus.Load().(*protobufs.PackageStatus)
+			return status.Status == expectedStatus && strings.Contains(status.ErrorMessage, "rolled back")
+		}, 20*time.Second, 250*time.Millisecond, "Package was never rolled back")
+	}
+}
+
 func findRandomPort() (int, error) {
 	l, err := net.Listen("tcp", "localhost:0")
 
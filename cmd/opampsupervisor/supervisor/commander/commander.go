@@ -21,29 +21,44 @@ import (
 // Commander can start/stop/restart the Agent executable and also watch for a signal
 // for the Agent process to finish.
 type Commander struct {
-	logger  *zap.Logger
-	cfg     config.Agent
-	logsDir string
-	args    []string
-	cmd     *exec.Cmd
-	doneCh  chan struct{}
-	exitCh  chan struct{}
-	running *atomic.Int64
+	logger *zap.Logger
+	cfg    config.Agent
+	// executable is the path of the Agent executable, which changes when a new Agent package is installed.
+	executable *atomic.Value
+	logsDir    string
+	args       []string
+	cmd        *exec.Cmd
+	doneCh     chan struct{}
+	exitCh     chan struct{}
+	running    *atomic.Int64
 }
 
 func NewCommander(logger *zap.Logger, logsDir string, cfg config.Agent, args ...string) (*Commander, error) {
+	executable := &atomic.Value{}
+	executable.Store(cfg.Executable)
 	return &Commander{
-		logger:  logger,
-		logsDir: logsDir,
-		cfg:     cfg,
-		args:    args,
-		running: &atomic.Int64{},
+		logger:     logger,
+		logsDir:    logsDir,
+		cfg:        cfg,
+		executable: executable,
+		args:       args,
+		running:    &atomic.Int64{},
 		// Buffer channels so we can send messages without blocking on listeners.
 		doneCh: make(chan struct{}, 1),
 		exitCh: make(chan struct{}, 1),
 	}, nil
 }
 
+// SetExecutable sets the path of the Agent executable used the next time the Agent is started.
+func (c *Commander) SetExecutable(executable string) {
+	c.executable.Store(executable)
+}
+
+// Executable returns the path of the Agent executable.
+func (c *Commander) Executable() string {
+	return c.executable.Load().(string)
+}
+
 // Start the Agent and begin watching the process.
 // Agent's stdout and stderr are written to a file.
 // Calling this method when a command is already running
@@ -69,7 +84,7 @@ func (c *Commander) Start(ctx context.Context) error {
 		}
 	}
 
-	c.logger.Debug("Starting agent", zap.String("agent", c.cfg.Executable))
+	c.logger.Debug("Starting agent", zap.String("agent", c.Executable()))
 
 	logFilePath := filepath.Join(c.logsDir, "agent.log")
 	stdoutFile, err := os.Create(logFilePath)
@@ -77,7 +92,7 @@ func (c *Commander) Start(ctx context.Context) error {
 		return fmt.Errorf("cannot create %s: %w", logFilePath, err)
 	}
 
-	c.cmd = exec.CommandContext(ctx, c.cfg.Executable, c.args...) // #nosec G204
+	c.cmd = exec.CommandContext(ctx, c.Executable(), c.args...) // #nosec G204
 	c.cmd.SysProcAttr = sysProcAttrs()
 
 	// Capture standard output and standard error.
@@ -99,7 +114,7 @@ func (c *Commander) Start(ctx context.Context) error {
 }
 
 func (c *Commander) Restart(ctx context.Context) error {
-	c.logger.Debug("Restarting agent", zap.String("agent", c.cfg.Executable))
+	c.logger.Debug("Restarting agent", zap.String("agent", c.Executable()))
 	if err := c.Stop(ctx); err != nil {
 		return err
 	}
@@ -34,6 +34,12 @@ func (s Supervisor) Validate() error {
 		return err
 	}
 
+	if s.Capabilities.AcceptsPackages {
+		if err := s.Agent.Packages.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -47,6 +53,7 @@ type Capabilities struct {
 	AcceptsRemoteConfig            bool `mapstructure:"accepts_remote_config"`
 	AcceptsRestartCommand          bool `mapstructure:"accepts_restart_command"`
 	AcceptsOpAMPConnectionSettings bool `mapstructure:"accepts_opamp_connection_settings"`
+	AcceptsPackages                bool `mapstructure:"accepts_packages"`
 	ReportsEffectiveConfig         bool `mapstructure:"reports_effective_config"`
 	ReportsOwnMetrics              bool `mapstructure:"reports_own_metrics"`
 	ReportsHealth                  bool `mapstructure:"reports_health"`
@@ -84,6 +91,12 @@ func (c Capabilities) SupportedCapabilities() protobufs.AgentCapabilities {
 		supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsOpAMPConnectionSettings
 	}
 
+	if c.AcceptsPackages {
+		// Package statuses are always reported when accepting packages.
+		supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsPackages |
+			protobufs.AgentCapabilities_AgentCapabilities_ReportsPackageStatuses
+	}
+
 	return supportedCapabilities
 }
 
@@ -122,6 +135,8 @@ type Agent struct {
 	OrphanDetectionInterval time.Duration    `mapstructure:"orphan_detection_interval"`
 	Description             AgentDescription `mapstructure:"description"`
 	HealthCheckPort         int              `mapstructure:"health_check_port"`
+	ConfigRollback          ConfigRollback   `mapstructure:"config_rollback"`
+	Packages                Packages         `mapstructure:"packages"`
 }
 
 func (a Agent) Validate() error {
@@ -133,6 +148,10 @@ func (a Agent) Validate() error {
 		return errors.New("agent::health_check_port must be a valid port number")
 	}
 
+	if err := a.ConfigRollback.Validate(); err != nil {
+		return err
+	}
+
 	if a.Executable == "" {
 		return errors.New("agent::executable must be specified")
 	}
@@ -145,6 +164,56 @@ func (a Agent) Validate() error {
 	return nil
 }
 
+// ConfigRollback configures the rollback of remote configs that leave the agent unhealthy.
+type ConfigRollback struct {
+	// Enabled reverts the agent to its last known good remote config when a new one
+	// doesn't result in a healthy agent within GracePeriod.
+	Enabled     bool          `mapstructure:"enabled"`
+	GracePeriod time.Duration `mapstructure:"grace_period"`
+}
+
+func (c ConfigRollback) Validate() error {
+	if c.Enabled && c.GracePeriod <= 0 {
+		return errors.New("agent::config_rollback::grace_period must be positive")
+	}
+
+	return nil
+}
+
+// Packages configures the installation of the agent packages offered by the OpAMP server.
+type Packages struct {
+	// PublicKeyFiles are the paths to the PEM encoded ECDSA or Ed25519 public keys
+	// the signatures of the packages are verified with.
+	PublicKeyFiles []string `mapstructure:"public_key_files"`
+	// GracePeriod is how long the agent has to become healthy after being started with a new package
+	// before it is rolled back to the previous one.
+	GracePeriod time.Duration `mapstructure:"grace_period"`
+	// DownloadTimeout is how long the download of a package content may take before it is abandoned.
+	DownloadTimeout time.Duration `mapstructure:"download_timeout"`
+}
+
+func (p Packages) Validate() error {
+	if len(p.PublicKeyFiles) == 0 {
+		return errors.New("agent::packages::public_key_files must be specified when accepting packages")
+	}
+
+	for _, f := range p.PublicKeyFiles {
+		if _, err := os.Stat(f); err != nil {
+			return fmt.Errorf("could not stat agent::packages::public_key_files path: %w", err)
+		}
+	}
+
+	if p.GracePeriod <= 0 {
+		return errors.New("agent::packages::grace_period must be positive")
+	}
+
+	if p.DownloadTimeout <= 0 {
+		return errors.New("agent::packages::download_timeout must be positive")
+	}
+
+	return nil
+}
+
 type AgentDescription struct {
 	IdentifyingAttributes    map[string]string `mapstructure:"identifying_attributes"`
 	NonIdentifyingAttributes map[string]string `mapstructure:"non_identifying_attributes"`
@@ -170,6 +239,7 @@ func DefaultSupervisor() Supervisor {
 			AcceptsRemoteConfig:            false,
 			AcceptsRestartCommand:          false,
 			AcceptsOpAMPConnectionSettings: false,
+			AcceptsPackages:                false,
 			ReportsEffectiveConfig:         true,
 			ReportsOwnMetrics:              true,
 			ReportsHealth:                  true,
@@ -180,6 +250,13 @@ func DefaultSupervisor() Supervisor {
 		},
 		Agent: Agent{
 			OrphanDetectionInterval: 5 * time.Second,
+			ConfigRollback: ConfigRollback{
+				GracePeriod: 30 * time.Second,
+			},
+			Packages: Packages{
+				GracePeriod:     30 * time.Second,
+				DownloadTimeout: 5 * time.Minute,
+			},
 		},
 	}
 }
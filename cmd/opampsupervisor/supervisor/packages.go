@@ -0,0 +1,823 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	packagesDirName             = "packages"
+	packagesStateFileName       = "packages.yaml"
+	lastReportedPackageStatuses = "last_reported_package_statuses.dat"
+	agentPackageFilePrefix      = "agent-"
+
+	// maxRejectedPackageContentHashes is the number of rolled back package content hashes kept in the packages state
+	maxRejectedPackageContentHashes = 16
+)
+
+var _ types.PackagesStateProvider = (*packageManager)(nil)
+
+// installedPackage is a top level package, holding an agent executable.
+type installedPackage struct {
+	Name        string `yaml:"name"`
+	Hash        string `yaml:"hash,omitempty"`
+	Version     string `yaml:"version,omitempty"`
+	ContentHash string `yaml:"content_hash,omitempty"`
+	// Path to the agent executable of the package, empty until the package content is downloaded.
+	Executable string `yaml:"executable,omitempty"`
+}
+
+// packagesState is the persistent state of the agent packages.
+type packagesState struct {
+	AllPackagesHash string `yaml:"all_packages_hash,omitempty"`
+
+	// The package the agent runs with, nil if it runs with the configured executable.
+	Active *installedPackage `yaml:"active,omitempty"`
+
+	// The package being synced from the server. It replaces the active package once
+	// its content is downloaded and verified, and its state is set.
+	Staged *installedPackage `yaml:"staged,omitempty"`
+	Ready  bool              `yaml:"ready,omitempty"`
+
+	// Pending is true while the active package waits for the agent to be healthy,
+	// in which case Previous is the package to roll back to.
+	Pending  bool              `yaml:"pending,omitempty"`
+	Previous *installedPackage `yaml:"previous,omitempty"`
+
+	// Hex encoded content hashes of the packages that were rolled back.
+	RejectedContentHashes []string `yaml:"rejected_content_hashes,omitempty"`
+}
+
+// packageManager implements types.PackagesStateProvider for the top level package offered by the
+// OpAMP server, which is the agent executable. The content of the package is verified against its
+// content hash and its signature before it is staged in the storage directory.
+type packageManager struct {
+	logger *zap.Logger
+	dir    string
+	// Configured agent executable, used when no package is active.
+	defaultExecutable string
+	publicKeys        []crypto.PublicKey
+
+	mu    sync.Mutex
+	state packagesState
+
+	// Guards the last reported statuses, which are updated while syncing and rolling back packages.
+	statusesMu sync.Mutex
+
+	// Signaled when a staged package is ready to be started.
+	installed chan struct{}
+}
+
+func newPackageManager(logger *zap.Logger, dir, defaultExecutable string, publicKeyFiles []string) (*packageManager, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating packages dir: %w", err)
+	}
+
+	m := &packageManager{
+		logger:            logger,
+		dir:               dir,
+		defaultExecutable: defaultExecutable,
+		installed:         make(chan struct{}, 1),
+	}
+
+	for _, f := range publicKeyFiles {
+		key, err := loadPublicKey(f)
+		if err != nil {
+			return nil, err
+		}
+		m.publicKeys = append(m.publicKeys, key)
+	}
+
+	by, err := os.ReadFile(filepath.Join(dir, packagesStateFileName))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err = yaml.Unmarshal(by, &m.state); err != nil {
+			return nil, fmt.Errorf("cannot parse packages state: %w", err)
+		}
+	}
+
+	if m.state.Ready {
+		m.installed <- struct{}{}
+	}
+
+	return m, nil
+}
+
+func loadPublicKey(file string) (crypto.PublicKey, error) {
+	by, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read public key: %w", err)
+	}
+
+	block, _ := pem.Decode(by)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded public key found in %s", file)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse public key in %s: %w", file, err)
+	}
+
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T in %s, must be ECDSA or Ed25519", key, file)
+	}
+}
+
+// verifySignature checks that signature is a signature of the SHA-256 digest of the package content
+// made with one of the trusted keys. The signature may be base64 encoded.
+func (m *packageManager) verifySignature(digest, signature []byte) error {
+	if len(signature) == 0 {
+		return errors.New("package is not signed")
+	}
+
+	signatures := [][]byte{signature}
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature))); err == nil {
+		signatures = append(signatures, decoded)
+	}
+
+	for _, key := range m.publicKeys {
+		for _, sig := range signatures {
+			switch key := key.(type) {
+			case *ecdsa.PublicKey:
+				if ecdsa.VerifyASN1(key, digest, sig) {
+					return nil
+				}
+			case ed25519.PublicKey:
+				if ed25519.Verify(key, digest, sig) {
+					return nil
+				}
+			}
+		}
+	}
+
+	return errors.New("package signature does not match any of the public keys")
+}
+
+// executable returns the path of the agent executable to run.
+func (m *packageManager) executable() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.executableLocked()
+}
+
+func (m *packageManager) executableLocked() string {
+	if m.state.Active == nil {
+		return m.defaultExecutable
+	}
+	return m.state.Active.Executable
+}
+
+// isPending returns true if the active package waits for the agent to be healthy.
+func (m *packageManager) isPending() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state.Pending
+}
+
+// activate makes the staged package the active one, and returns the path of its executable.
+// The previously active package is kept until the new one is confirmed or rolled back.
+func (m *packageManager) activate() (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.state.Ready || m.state.Staged == nil {
+		return "", false
+	}
+
+	if m.state.Pending {
+		// The package that was waiting is replaced without being confirmed, so the rollback
+		// still goes to the package before it.
+		m.removeExecutable(m.state.Active)
+	} else {
+		m.state.Previous = m.state.Active
+	}
+	m.state.Active = m.state.Staged
+	m.state.Staged = nil
+	m.state.Ready = false
+	m.state.Pending = true
+	m.writeStateLocked()
+
+	return m.state.Active.Executable, true
+}
+
+// confirm keeps the active package, as the agent is healthy with it.
+func (m *packageManager) confirm() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.state.Pending {
+		return
+	}
+
+	m.removeExecutable(m.state.Previous)
+	m.state.Previous = nil
+	m.state.Pending = false
+	m.writeStateLocked()
+}
+
+// rollback reverts to the package that was active before the pending one, and rejects the content
+// of the pending one so that it isn't installed again. It returns the rolled back package, and the
+// path of the executable to run instead.
+func (m *packageManager) rollback() (*installedPackage, string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.state.Pending {
+		return nil, "", false
+	}
+
+	bad := m.state.Active
+	if !slices.Contains(m.state.RejectedContentHashes, bad.ContentHash) {
+		m.state.RejectedContentHashes = append(m.state.RejectedContentHashes, bad.ContentHash)
+		if n := len(m.state.RejectedContentHashes); n > maxRejectedPackageContentHashes {
+			m.state.RejectedContentHashes = m.state.RejectedContentHashes[n-maxRejectedPackageContentHashes:]
+		}
+	}
+	m.removeExecutable(bad)
+	m.state.Active = m.state.Previous
+	m.state.Previous = nil
+	m.state.Pending = false
+	m.writeStateLocked()
+
+	return bad, m.executableLocked(), true
+}
+
+// current returns the package with the given name as known by the package syncer.
+// A staged package takes precedence over the active one.
+func (m *packageManager) current(name string) *installedPackage {
+	if m.state.Staged != nil && m.state.Staged.Name == name {
+		return m.state.Staged
+	}
+	if m.state.Active != nil && m.state.Active.Name == name {
+		return m.state.Active
+	}
+	return nil
+}
+
+// removeExecutable deletes the executable of p if it was downloaded and is no longer in use.
+func (m *packageManager) removeExecutable(p *installedPackage) {
+	if p == nil || p.Executable == "" || filepath.Dir(p.Executable) != m.dir {
+		return
+	}
+	for _, other := range []*installedPackage{m.state.Active, m.state.Staged, m.state.Previous} {
+		if other != nil && other != p && other.Executable == p.Executable {
+			return
+		}
+	}
+
+	if err := os.Remove(p.Executable); err != nil && !errors.Is(err, os.ErrNotExist) {
+		m.logger.Error("Could not remove agent package executable", zap.String("path", p.Executable), zap.Error(err))
+	}
+}
+
+func (m *packageManager) writeStateLocked() {
+	if err := m.writeState(); err != nil {
+		m.logger.Error("Failed to persist packages state", zap.Error(err))
+	}
+}
+
+func (m *packageManager) writeState() error {
+	by, err := yaml.Marshal(&m.state)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(m.dir, packagesStateFileName), by, 0600)
+}
+
+// AllPackagesHash implements types.PackagesStateProvider.
+func (m *packageManager) AllPackagesHash() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return hex.DecodeString(m.state.AllPackagesHash)
+}
+
+// SetAllPackagesHash implements types.PackagesStateProvider.
+func (m *packageManager) SetAllPackagesHash(hash []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state.AllPackagesHash = hex.EncodeToString(hash)
+	return m.writeState()
+}
+
+// Packages implements types.PackagesStateProvider.
+func (m *packageManager) Packages() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var names []string
+	for _, p := range []*installedPackage{m.state.Active, m.state.Staged} {
+		if p != nil && !slices.Contains(names, p.Name) {
+			names = append(names, p.Name)
+		}
+	}
+	return names, nil
+}
+
+// PackageState implements types.PackagesStateProvider.
+func (m *packageManager) PackageState(packageName string) (types.PackageState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := m.current(packageName)
+	if p == nil {
+		return types.PackageState{Exists: false}, nil
+	}
+
+	hash, err := hex.DecodeString(p.Hash)
+	if err != nil {
+		return types.PackageState{}, err
+	}
+	return types.PackageState{
+		Exists:  true,
+		Type:    protobufs.PackageType_PackageType_TopLevel,
+		Hash:    hash,
+		Version: p.Version,
+	}, nil
+}
+
+// SetPackageState implements types.PackagesStateProvider.
+func (m *packageManager) SetPackageState(packageName string, state types.PackageState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if state.Type != protobufs.PackageType_PackageType_TopLevel {
+		return fmt.Errorf("package %q is not a top level package", packageName)
+	}
+
+	p := m.current(packageName)
+	if p == nil {
+		return fmt.Errorf("package %q does not exist", packageName)
+	}
+	p.Hash = hex.EncodeToString(state.Hash)
+	p.Version = state.Version
+	return m.writeState()
+}
+
+// markReady signals that the staged package is ready to be started, if its content was updated.
+func (m *packageManager) markReady() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state.Staged == nil || m.state.Staged.Executable == "" {
+		return
+	}
+
+	m.state.Ready = true
+	m.writeStateLocked()
+	select {
+	case m.installed <- struct{}{}:
+	default:
+	}
+}
+
+// CreatePackage implements types.PackagesStateProvider. Only top level packages are supported.
+func (m *packageManager) CreatePackage(packageName string, typ protobufs.PackageType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if typ != protobufs.PackageType_PackageType_TopLevel {
+		return fmt.Errorf("cannot create package %q, only top level packages are supported", packageName)
+	}
+	if m.current(packageName) != nil {
+		return fmt.Errorf("package %q already exists", packageName)
+	}
+
+	m.removeExecutable(m.state.Staged)
+	m.state.Staged = &installedPackage{Name: packageName}
+	m.state.Ready = false
+	return m.writeState()
+}
+
+// FileContentHash implements types.PackagesStateProvider.
+func (m *packageManager) FileContentHash(packageName string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := m.current(packageName)
+	if p == nil || p.ContentHash == "" {
+		return nil, nil
+	}
+	return hex.DecodeString(p.ContentHash)
+}
+
+// UpdateContent implements types.PackagesStateProvider. It always fails, as the signature
+// of the content is unknown.
+func (m *packageManager) UpdateContent(ctx context.Context, packageName string, data io.Reader, contentHash []byte) error {
+	return m.updateContent(ctx, packageName, data, contentHash, nil)
+}
+
+// updateContent writes the content of a package to a new executable in the packages directory,
+// which is staged once its content hash and signature are verified.
+func (m *packageManager) updateContent(ctx context.Context, packageName string, data io.Reader, contentHash, signature []byte) error {
+	m.mu.Lock()
+	rejected := slices.Contains(m.state.RejectedContentHashes, hex.EncodeToString(contentHash))
+	m.mu.Unlock()
+
+	if rejected {
+		return fmt.Errorf("package content with hash %x was rolled back because the agent was not healthy with it", contentHash)
+	}
+
+	f, err := os.CreateTemp(m.dir, agentPackageFilePrefix+"*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The temporary file is renamed on success, so this is a no-op then.
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	digest := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(f, digest), &contextReader{ctx: ctx, r: data}); err != nil {
+		return fmt.Errorf("cannot write package content: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	sum := digest.Sum(nil)
+	if !bytes.Equal(sum, contentHash) {
+		return fmt.Errorf("package content hash %x does not match the expected hash %x", sum, contentHash)
+	}
+	if err = m.verifySignature(sum, signature); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(f.Name(), 0700); err != nil {
+		return err
+	}
+	executable := filepath.Join(m.dir, agentPackageFilePrefix+hex.EncodeToString(contentHash))
+	if runtime.GOOS == "windows" {
+		executable += ".exe"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// An executable with the same content may still be in use, in which case it is kept as it is.
+	if _, err = os.Stat(executable); errors.Is(err, os.ErrNotExist) {
+		if err = os.Rename(f.Name(), executable); err != nil {
+			return err
+		}
+	}
+
+	staged := m.current(packageName)
+	if staged == nil {
+		return fmt.Errorf("package %q does not exist", packageName)
+	}
+	if staged != m.state.Staged {
+		// The content of the active package is updated, which is staged as a new package.
+		copied := *staged
+		staged = &copied
+		m.removeExecutable(m.state.Staged)
+		m.state.Staged = staged
+	}
+	if staged.Executable != executable {
+		m.removeExecutable(staged)
+	}
+	staged.ContentHash = hex.EncodeToString(contentHash)
+	staged.Executable = executable
+	m.state.Ready = false
+	return m.writeState()
+}
+
+// DeletePackage implements types.PackagesStateProvider. The active package can't be deleted,
+// as the agent runs with it.
+func (m *packageManager) DeletePackage(packageName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state.Staged != nil && m.state.Staged.Name == packageName {
+		m.removeExecutable(m.state.Staged)
+		m.state.Staged = nil
+		m.state.Ready = false
+		return m.writeState()
+	}
+	if m.state.Active != nil && m.state.Active.Name == packageName {
+		return fmt.Errorf("cannot delete package %q, the agent is running it", packageName)
+	}
+	return nil
+}
+
+// LastReportedStatuses implements types.PackagesStateProvider.
+func (m *packageManager) LastReportedStatuses() (*protobufs.PackageStatuses, error) {
+	by, err := os.ReadFile(filepath.Join(m.dir, lastReportedPackageStatuses))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := &protobufs.PackageStatuses{}
+	if err = proto.Unmarshal(by, statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// SetLastReportedStatuses implements types.PackagesStateProvider.
+func (m *packageManager) SetLastReportedStatuses(statuses *protobufs.PackageStatuses) error {
+	by, err := proto.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(m.dir, lastReportedPackageStatuses), by, 0600)
+}
+
+// contextReader stops reading once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// resetPackageTimer starts the grace period of the pending package, if any.
+func (s *Supervisor) resetPackageTimer(packageTimer *time.Timer) {
+	if !packageTimer.Stop() {
+		select {
+		case <-packageTimer.C: // Try to drain the channel
+		default:
+		}
+	}
+
+	if s.packageManager.isPending() {
+		packageTimer.Reset(s.config.Agent.Packages.GracePeriod)
+	}
+}
+
+// updatePackageStatuses applies update to the last reported package statuses, and reports them to the server.
+func (s *Supervisor) updatePackageStatuses(update func(statuses *protobufs.PackageStatuses)) {
+	s.packageManager.statusesMu.Lock()
+	defer s.packageManager.statusesMu.Unlock()
+
+	statuses, err := s.packageManager.LastReportedStatuses()
+	if err != nil {
+		s.logger.Error("Cannot load last reported package statuses", zap.Error(err))
+	}
+	if statuses == nil {
+		statuses = &protobufs.PackageStatuses{}
+	}
+	if statuses.Packages == nil {
+		statuses.Packages = map[string]*protobufs.PackageStatus{}
+	}
+
+	update(statuses)
+
+	if err = s.packageManager.SetLastReportedStatuses(statuses); err != nil {
+		s.logger.Error("Cannot save last reported package statuses", zap.Error(err))
+	}
+	if statuses.ServerProvidedAllPackagesHash == nil {
+		// Statuses can't be reported before the server offers packages.
+		return
+	}
+	if err = s.opampClient.SetPackageStatuses(statuses); err != nil {
+		s.logger.Error("Could not report OpAMP package statuses", zap.Error(err))
+	}
+}
+
+// updatePackageStatus applies update to the status of a package, and reports the statuses to the server.
+func (s *Supervisor) updatePackageStatus(name string, update func(status *protobufs.PackageStatus)) {
+	s.updatePackageStatuses(func(statuses *protobufs.PackageStatuses) {
+		status := statuses.Packages[name]
+		if status == nil {
+			status = &protobufs.PackageStatus{Name: name}
+			statuses.Packages[name] = status
+		}
+		update(status)
+	})
+}
+
+// syncPackages installs the packages offered by the server, and reports their statuses along the way.
+// Once a new top level package is reported as installed, the agent is restarted with it.
+// Syncs are serialized, as the server may offer packages again while they are downloaded.
+func (s *Supervisor) syncPackages(ctx context.Context, available *protobufs.PackagesAvailable) {
+	s.packageSyncMu.Lock()
+	defer s.packageSyncMu.Unlock()
+
+	m := s.packageManager
+	s.updatePackageStatuses(func(statuses *protobufs.PackageStatuses) {
+		statuses.ServerProvidedAllPackagesHash = available.AllPackagesHash
+		statuses.ErrorMessage = ""
+	})
+
+	hash, err := m.AllPackagesHash()
+	if err == nil && bytes.Equal(hash, available.AllPackagesHash) {
+		s.logger.Debug("All agent packages are already up to date")
+		return
+	}
+
+	failed := false
+	names, err := m.Packages()
+	if err != nil {
+		s.logger.Error("Cannot list agent packages", zap.Error(err))
+		failed = true
+	}
+	for _, name := range names {
+		if _, offered := available.Packages[name]; offered {
+			continue
+		}
+		if err = m.DeletePackage(name); err != nil {
+			s.logger.Error("Cannot delete agent package that is no longer offered", zap.String("package", name), zap.Error(err))
+			failed = true
+		}
+	}
+	s.updatePackageStatuses(func(statuses *protobufs.PackageStatuses) {
+		for name := range statuses.Packages {
+			if _, offered := available.Packages[name]; !offered {
+				delete(statuses.Packages, name)
+			}
+		}
+	})
+
+	for name, pkg := range available.Packages {
+		if err = s.syncPackage(ctx, name, pkg); err != nil {
+			s.logger.Error("Cannot install agent package", zap.String("package", name), zap.Error(err))
+			failed = true
+		}
+	}
+
+	if failed {
+		s.updatePackageStatuses(func(statuses *protobufs.PackageStatuses) {
+			statuses.ErrorMessage = "not all packages could be installed"
+		})
+	} else if err = m.SetAllPackagesHash(available.AllPackagesHash); err != nil {
+		s.logger.Error("Cannot save agent packages hash", zap.Error(err))
+	}
+
+	m.markReady()
+}
+
+// syncPackage installs a package offered by the server, unless it is already installed.
+func (s *Supervisor) syncPackage(ctx context.Context, name string, pkg *protobufs.PackageAvailable) error {
+	m := s.packageManager
+	s.updatePackageStatus(name, func(status *protobufs.PackageStatus) {
+		status.ServerOfferedHash = pkg.Hash
+		status.ServerOfferedVersion = pkg.Version
+	})
+
+	err := s.installPackage(ctx, name, pkg)
+	if err != nil {
+		s.updatePackageStatus(name, func(status *protobufs.PackageStatus) {
+			status.Status = protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed
+			status.ErrorMessage = err.Error()
+		})
+		return err
+	}
+
+	state, err := m.PackageState(name)
+	if err != nil {
+		return err
+	}
+	s.updatePackageStatus(name, func(status *protobufs.PackageStatus) {
+		status.Status = protobufs.PackageStatusEnum_PackageStatusEnum_Installed
+		status.ErrorMessage = ""
+		status.AgentHasHash = state.Hash
+		status.AgentHasVersion = state.Version
+	})
+	return nil
+}
+
+func (s *Supervisor) installPackage(ctx context.Context, name string, pkg *protobufs.PackageAvailable) error {
+	m := s.packageManager
+	if pkg.Type != protobufs.PackageType_PackageType_TopLevel {
+		return fmt.Errorf("cannot install package %q, only top level packages are supported", name)
+	}
+
+	state, err := m.PackageState(name)
+	if err != nil {
+		return err
+	}
+	if state.Exists && bytes.Equal(state.Hash, pkg.Hash) {
+		return nil
+	}
+
+	s.updatePackageStatus(name, func(status *protobufs.PackageStatus) {
+		status.Status = protobufs.PackageStatusEnum_PackageStatusEnum_Installing
+		status.ErrorMessage = ""
+	})
+
+	if !state.Exists {
+		if err = m.CreatePackage(name, pkg.Type); err != nil {
+			return err
+		}
+	}
+
+	contentHash, err := m.FileContentHash(name)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(contentHash, pkg.GetFile().GetContentHash()) {
+		if err = s.downloadPackage(ctx, name, pkg.GetFile()); err != nil {
+			return err
+		}
+	}
+
+	return m.SetPackageState(name, types.PackageState{
+		Exists:  true,
+		Type:    pkg.Type,
+		Hash:    pkg.Hash,
+		Version: pkg.Version,
+	})
+}
+
+func (s *Supervisor) downloadPackage(ctx context.Context, name string, file *protobufs.DownloadableFile) error {
+	if file.GetDownloadUrl() == "" {
+		return fmt.Errorf("package %q has no file to download", name)
+	}
+
+	// The timeout covers reading the content, as the server may stall in the middle of the response.
+	ctx, cancel := context.WithTimeout(ctx, s.config.Agent.Packages.DownloadTimeout)
+	defer cancel()
+
+	s.logger.Debug("Downloading agent package", zap.String("package", name), zap.String("url", file.DownloadUrl))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, file.DownloadUrl, nil)
+	if err != nil {
+		return fmt.Errorf("cannot download package from %s: %w", file.DownloadUrl, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot download package from %s: %w", file.DownloadUrl, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot download package from %s, HTTP response=%d", file.DownloadUrl, resp.StatusCode)
+	}
+
+	return s.packageManager.updateContent(ctx, name, resp.Body, file.ContentHash, file.Signature)
+}
+
+// rollbackPackage reverts the agent to the package it ran with before the pending one, and reports
+// the pending one as failed with cause. It returns the path of the agent executable to restart the agent with.
+func (s *Supervisor) rollbackPackage(cause error) (string, bool) {
+	bad, executable, ok := s.packageManager.rollback()
+	if !ok {
+		return "", false
+	}
+
+	s.logger.Warn("Rolling back agent package",
+		zap.String("package", bad.Name),
+		zap.String("version", bad.Version),
+		zap.Error(cause))
+
+	active, err := s.packageManager.PackageState(bad.Name)
+	if err != nil {
+		s.logger.Error("Cannot get agent package state", zap.Error(err))
+	}
+	s.updatePackageStatus(bad.Name, func(status *protobufs.PackageStatus) {
+		status.Status = protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed
+		status.ErrorMessage = fmt.Sprintf("package was rolled back: %v", cause)
+		status.AgentHasHash = nil
+		status.AgentHasVersion = ""
+		if active.Exists {
+			status.AgentHasHash = active.Hash
+			status.AgentHasVersion = active.Version
+		}
+	})
+
+	return executable, true
+}
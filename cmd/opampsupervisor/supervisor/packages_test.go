@@ -0,0 +1,428 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/opampsupervisor/supervisor/config"
+)
+
+const testPackageName = "otelcol"
+
+func writePublicKey(t *testing.T, key crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	f := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(f, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	return f
+}
+
+func newTestPackageManager(t *testing.T, dir string) (*packageManager, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	m, err := newPackageManager(zap.NewNop(), dir, "/opt/otelcol/otelcol", []string{writePublicKey(t, key.Public())})
+	require.NoError(t, err)
+	return m, key
+}
+
+func signPackage(t *testing.T, key *ecdsa.PrivateKey, content []byte) []byte {
+	digest := sha256.Sum256(content)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return sig
+}
+
+// installPackage installs a top level package, and marks it ready to be started.
+func installPackage(t *testing.T, m *packageManager, content, signature []byte, version string) error {
+	contentHash := sha256.Sum256(content)
+	hash := sha256.Sum256([]byte(version))
+
+	state, err := m.PackageState(testPackageName)
+	require.NoError(t, err)
+	if !state.Exists {
+		require.NoError(t, m.CreatePackage(testPackageName, protobufs.PackageType_PackageType_TopLevel))
+	}
+
+	if err = m.updateContent(context.Background(), testPackageName, bytes.NewReader(content), contentHash[:], signature); err != nil {
+		return err
+	}
+
+	err = m.SetPackageState(testPackageName, types.PackageState{
+		Exists:  true,
+		Type:    protobufs.PackageType_PackageType_TopLevel,
+		Hash:    hash[:],
+		Version: version,
+	})
+	m.markReady()
+	return err
+}
+
+func TestPackageManager_Install(t *testing.T) {
+	dir := t.TempDir()
+	m, key := newTestPackageManager(t, dir)
+	require.Equal(t, "/opt/otelcol/otelcol", m.executable())
+
+	content := []byte("#!/bin/sh\necho v1\n")
+	require.NoError(t, installPackage(t, m, content, signPackage(t, key, content), "v1"))
+
+	select {
+	case <-m.installed:
+	default:
+		require.Fail(t, "package installation was not signaled")
+	}
+
+	executable, ok := m.activate()
+	require.True(t, ok)
+	require.Equal(t, executable, m.executable())
+	require.True(t, m.isPending())
+
+	got, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	require.Equal(t, content, got)
+	info, err := os.Stat(executable)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	m.confirm()
+	require.False(t, m.isPending())
+
+	// The package state is persisted
+	loaded, err := newPackageManager(zap.NewNop(), dir, "/opt/otelcol/otelcol", nil)
+	require.NoError(t, err)
+	require.Equal(t, executable, loaded.executable())
+	state, err := loaded.PackageState(testPackageName)
+	require.NoError(t, err)
+	require.True(t, state.Exists)
+	require.Equal(t, "v1", state.Version)
+
+	// Upgrading removes the executable of the previous package once confirmed
+	content2 := []byte("#!/bin/sh\necho v2\n")
+	require.NoError(t, installPackage(t, m, content2, signPackage(t, key, content2), "v2"))
+	executable2, ok := m.activate()
+	require.True(t, ok)
+	require.NotEqual(t, executable, executable2)
+	require.FileExists(t, executable)
+
+	m.confirm()
+	require.NoFileExists(t, executable)
+	require.FileExists(t, executable2)
+}
+
+func TestPackageManager_Verification(t *testing.T) {
+	content := []byte("#!/bin/sh\necho v1\n")
+
+	t.Run("Content hash mismatch", func(t *testing.T) {
+		m, key := newTestPackageManager(t, t.TempDir())
+		require.NoError(t, m.CreatePackage(testPackageName, protobufs.PackageType_PackageType_TopLevel))
+
+		err := m.updateContent(context.Background(), testPackageName, bytes.NewReader(content), []byte("wrong"), signPackage(t, key, content))
+		require.ErrorContains(t, err, "does not match the expected hash")
+
+		hash, err := m.FileContentHash(testPackageName)
+		require.NoError(t, err)
+		require.Nil(t, hash)
+	})
+
+	t.Run("Missing signature", func(t *testing.T) {
+		m, _ := newTestPackageManager(t, t.TempDir())
+		require.ErrorContains(t, installPackage(t, m, content, nil, "v1"), "package is not signed")
+
+		// The content can't be updated through the PackagesStateProvider, as its signature is unknown
+		contentHash := sha256.Sum256(content)
+		err := m.UpdateContent(context.Background(), testPackageName, bytes.NewReader(content), contentHash[:])
+		require.ErrorContains(t, err, "package is not signed")
+	})
+
+	t.Run("Signature from an unknown key", func(t *testing.T) {
+		m, _ := newTestPackageManager(t, t.TempDir())
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		require.ErrorContains(t, installPackage(t, m, content, signPackage(t, otherKey, content), "v1"),
+			"package signature does not match any of the public keys")
+	})
+
+	t.Run("Base64 encoded Ed25519 signature", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		m, err := newPackageManager(zap.NewNop(), t.TempDir(), "/opt/otelcol/otelcol", []string{writePublicKey(t, pub)})
+		require.NoError(t, err)
+
+		digest := sha256.Sum256(content)
+		signature := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, digest[:]))
+		require.NoError(t, installPackage(t, m, content, []byte(signature), "v1"))
+	})
+
+	t.Run("Canceled download", func(t *testing.T) {
+		m, key := newTestPackageManager(t, t.TempDir())
+		require.NoError(t, m.CreatePackage(testPackageName, protobufs.PackageType_PackageType_TopLevel))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		contentHash := sha256.Sum256(content)
+		err := m.updateContent(ctx, testPackageName, bytes.NewReader(content), contentHash[:], signPackage(t, key, content))
+		require.ErrorIs(t, err, context.Canceled)
+
+		// No temporary file is left behind
+		entries, err := os.ReadDir(m.dir)
+		require.NoError(t, err)
+		for _, e := range entries {
+			require.NotContains(t, e.Name(), agentPackageFilePrefix)
+		}
+	})
+
+	t.Run("Unsupported public key", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = newPackageManager(zap.NewNop(), t.TempDir(), "/opt/otelcol/otelcol", []string{writePublicKey(t, key.Public())})
+		require.ErrorContains(t, err, "unsupported public key type")
+	})
+
+	t.Run("Addon packages are not supported", func(t *testing.T) {
+		m, _ := newTestPackageManager(t, t.TempDir())
+		require.Error(t, m.CreatePackage("addon", protobufs.PackageType_PackageType_Addon))
+	})
+}
+
+func TestPackageManager_Rollback(t *testing.T) {
+	dir := t.TempDir()
+	m, key := newTestPackageManager(t, dir)
+
+	good := []byte("#!/bin/sh\necho good\n")
+	require.NoError(t, installPackage(t, m, good, signPackage(t, key, good), "v1"))
+	goodExecutable, ok := m.activate()
+	require.True(t, ok)
+	m.confirm()
+
+	bad := []byte("#!/bin/sh\nexit 1\n")
+	require.NoError(t, installPackage(t, m, bad, signPackage(t, key, bad), "v2"))
+	badExecutable, ok := m.activate()
+	require.True(t, ok)
+
+	rolledBack, executable, ok := m.rollback()
+	require.True(t, ok)
+	require.Equal(t, "v2", rolledBack.Version)
+	require.Equal(t, goodExecutable, executable)
+	require.Equal(t, goodExecutable, m.executable())
+	require.NoFileExists(t, badExecutable)
+	require.False(t, m.isPending())
+
+	state, err := m.PackageState(testPackageName)
+	require.NoError(t, err)
+	require.Equal(t, "v1", state.Version)
+
+	// The rolled back package content is rejected, even after a restart
+	loaded, err := newPackageManager(zap.NewNop(), dir, "/opt/otelcol/otelcol", []string{writePublicKey(t, key.Public())})
+	require.NoError(t, err)
+	require.ErrorContains(t, installPackage(t, loaded, bad, signPackage(t, key, bad), "v2"), "was rolled back")
+
+	// Nothing to roll back once confirmed
+	_, _, ok = loaded.rollback()
+	require.False(t, ok)
+}
+
+func TestPackageManager_RollbackToDefaultExecutable(t *testing.T) {
+	m, key := newTestPackageManager(t, t.TempDir())
+
+	bad := []byte("#!/bin/sh\nexit 1\n")
+	require.NoError(t, installPackage(t, m, bad, signPackage(t, key, bad), "v1"))
+	_, ok := m.activate()
+	require.True(t, ok)
+
+	_, executable, ok := m.rollback()
+	require.True(t, ok)
+	require.Equal(t, "/opt/otelcol/otelcol", executable)
+
+	names, err := m.Packages()
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
+
+func Test_rollbackPackage(t *testing.T) {
+	m, key := newTestPackageManager(t, t.TempDir())
+
+	var reported *protobufs.PackageStatuses
+	s := &Supervisor{
+		logger:         zap.NewNop(),
+		config:         config.Supervisor{Capabilities: config.Capabilities{AcceptsPackages: true}},
+		packageManager: m,
+		opampClient: &mockOpAMPClient{
+			setPackageStatusesFunc: func(statuses *protobufs.PackageStatuses) error {
+				reported = statuses
+				return nil
+			},
+		},
+	}
+
+	_, ok := s.rollbackPackage(errors.New("agent is not healthy"))
+	require.False(t, ok, "nothing to roll back")
+
+	bad := []byte("#!/bin/sh\nexit 1\n")
+	require.NoError(t, installPackage(t, m, bad, signPackage(t, key, bad), "v1"))
+	require.NoError(t, m.SetLastReportedStatuses(&protobufs.PackageStatuses{
+		Packages: map[string]*protobufs.PackageStatus{
+			testPackageName: {
+				Name:            testPackageName,
+				AgentHasVersion: "v1",
+				Status:          protobufs.PackageStatusEnum_PackageStatusEnum_Installed,
+			},
+		},
+		ServerProvidedAllPackagesHash: []byte{0x01},
+	}))
+	_, ok = m.activate()
+	require.True(t, ok)
+
+	executable, ok := s.rollbackPackage(errors.New("agent is not healthy"))
+	require.True(t, ok)
+	require.Equal(t, "/opt/otelcol/otelcol", executable)
+
+	require.NotNil(t, reported)
+	status := reported.Packages[testPackageName]
+	require.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, status.Status)
+	require.Contains(t, status.ErrorMessage, "agent is not healthy")
+	require.Empty(t, status.AgentHasVersion)
+
+	// The failed status is kept, so that it is reported again when the packages are synced
+	statuses, err := m.LastReportedStatuses()
+	require.NoError(t, err)
+	require.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, statuses.Packages[testPackageName].Status)
+}
+
+func Test_syncPackages(t *testing.T) {
+	m, key := newTestPackageManager(t, t.TempDir())
+
+	executable := []byte("#!/bin/sh\necho fake collector\n")
+	var downloads atomic.Int64
+	downloadServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/otelcol":
+		case "/stalled":
+			// Part of the content is sent, and the rest never comes
+			_, _ = w.Write(executable[:8])
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		downloads.Add(1)
+		_, _ = w.Write(executable)
+	}))
+	t.Cleanup(downloadServer.Close)
+
+	var reported []*protobufs.PackageStatuses
+	s := &Supervisor{
+		logger: zap.NewNop(),
+		config: config.Supervisor{
+			Capabilities: config.Capabilities{AcceptsPackages: true},
+			Agent:        config.Agent{Packages: config.Packages{DownloadTimeout: time.Minute}},
+		},
+		packageManager: m,
+		opampClient: &mockOpAMPClient{
+			setPackageStatusesFunc: func(statuses *protobufs.PackageStatuses) error {
+				reported = append(reported, statuses)
+				return nil
+			},
+		},
+	}
+
+	contentHash := sha256.Sum256(executable)
+	offer := func(allHash byte, url string) *protobufs.PackagesAvailable {
+		return &protobufs.PackagesAvailable{
+			Packages: map[string]*protobufs.PackageAvailable{
+				testPackageName: {
+					Type:    protobufs.PackageType_PackageType_TopLevel,
+					Version: "v2",
+					File: &protobufs.DownloadableFile{
+						DownloadUrl: downloadServer.URL + url,
+						ContentHash: contentHash[:],
+						Signature:   signPackage(t, key, executable),
+					},
+					Hash: []byte{allHash},
+				},
+			},
+			AllPackagesHash: []byte{allHash},
+		}
+	}
+
+	t.Run("Download fails", func(t *testing.T) {
+		s.syncPackages(context.Background(), offer(0x01, "/missing"))
+
+		status := reported[len(reported)-1].Packages[testPackageName]
+		require.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, status.Status)
+		require.Contains(t, status.ErrorMessage, "HTTP response=404")
+		require.Equal(t, "not all packages could be installed", reported[len(reported)-1].ErrorMessage)
+		require.Len(t, m.installed, 0)
+	})
+
+	t.Run("Download times out", func(t *testing.T) {
+		s.config.Agent.Packages.DownloadTimeout = 100 * time.Millisecond
+		t.Cleanup(func() { s.config.Agent.Packages.DownloadTimeout = time.Minute })
+		s.syncPackages(context.Background(), offer(0x01, "/stalled"))
+
+		status := reported[len(reported)-1].Packages[testPackageName]
+		require.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, status.Status)
+		require.Contains(t, status.ErrorMessage, context.DeadlineExceeded.Error())
+		require.Len(t, m.installed, 0)
+	})
+
+	t.Run("Package is installed", func(t *testing.T) {
+		reported = nil
+		s.syncPackages(context.Background(), offer(0x02, "/otelcol"))
+		require.Equal(t, int64(1), downloads.Load())
+
+		var statuses []protobufs.PackageStatusEnum
+		for _, r := range reported {
+			require.Equal(t, []byte{0x02}, r.ServerProvidedAllPackagesHash)
+			if status := r.Packages[testPackageName]; status != nil {
+				statuses = append(statuses, status.Status)
+			}
+		}
+		require.Contains(t, statuses, protobufs.PackageStatusEnum_PackageStatusEnum_Installing)
+		last := reported[len(reported)-1]
+		require.Empty(t, last.ErrorMessage)
+		require.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_Installed, last.Packages[testPackageName].Status)
+		require.Equal(t, "v2", last.Packages[testPackageName].AgentHasVersion)
+
+		// The agent is restarted with the package once its status is reported
+		require.Len(t, m.installed, 1)
+		path, ok := m.activate()
+		require.True(t, ok)
+		got, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, executable, got)
+	})
+
+	t.Run("Packages are up to date", func(t *testing.T) {
+		s.syncPackages(context.Background(), offer(0x02, "/otelcol"))
+		require.Equal(t, int64(1), downloads.Load())
+	})
+}
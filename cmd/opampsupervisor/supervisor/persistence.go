@@ -4,17 +4,26 @@
 package supervisor
 
 import (
+	"encoding/hex"
 	"errors"
 	"os"
+	"slices"
 
 	"github.com/google/uuid"
 	"gopkg.in/yaml.v3"
 )
 
+// maxRejectedRemoteConfigHashes is the number of rolled back remote config hashes kept in the persistent state
+const maxRejectedRemoteConfigHashes = 16
+
 // persistentState represents persistent state for the supervisor
 type persistentState struct {
 	InstanceID uuid.UUID `yaml:"instance_id"`
 
+	// Hex encoded hashes of the remote configs that were rolled back,
+	// from the least to the most recently rolled back.
+	RejectedRemoteConfigHashes []string `yaml:"rejected_remote_config_hashes,omitempty"`
+
 	// Path to the config file that the state should be saved to.
 	// This is not marshaled.
 	configPath string `yaml:"-"`
@@ -25,6 +34,25 @@ func (p *persistentState) SetInstanceID(id uuid.UUID) error {
 	return p.writeState()
 }
 
+// RejectRemoteConfigHash records the hash of a remote config that must not be applied again.
+// Only the most recent maxRejectedRemoteConfigHashes hashes are kept.
+func (p *persistentState) RejectRemoteConfigHash(hash []byte) error {
+	if p.IsRemoteConfigHashRejected(hash) {
+		return nil
+	}
+
+	p.RejectedRemoteConfigHashes = append(p.RejectedRemoteConfigHashes, hex.EncodeToString(hash))
+	if n := len(p.RejectedRemoteConfigHashes); n > maxRejectedRemoteConfigHashes {
+		p.RejectedRemoteConfigHashes = p.RejectedRemoteConfigHashes[n-maxRejectedRemoteConfigHashes:]
+	}
+	return p.writeState()
+}
+
+// IsRemoteConfigHashRejected returns true if the remote config with the given hash was rolled back.
+func (p *persistentState) IsRemoteConfigHashRejected(hash []byte) bool {
+	return len(hash) > 0 && slices.Contains(p.RejectedRemoteConfigHashes, hex.EncodeToString(hash))
+}
+
 func (p *persistentState) writeState() error {
 	by, err := yaml.Marshal(p)
 	if err != nil {
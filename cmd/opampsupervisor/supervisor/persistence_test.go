@@ -60,3 +60,30 @@ func TestPersistentState_SetInstanceID(t *testing.T) {
 
 	require.Equal(t, newUUID, loadedState.InstanceID)
 }
+
+func TestPersistentState_RejectRemoteConfigHash(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "state.yaml")
+	state, err := createNewPersistentState(f)
+	require.NoError(t, err)
+
+	require.False(t, state.IsRemoteConfigHashRejected([]byte{0x01}))
+	require.False(t, state.IsRemoteConfigHashRejected(nil))
+
+	require.NoError(t, state.RejectRemoteConfigHash([]byte{0x01}))
+	require.NoError(t, state.RejectRemoteConfigHash([]byte{0x01}))
+	require.True(t, state.IsRemoteConfigHashRejected([]byte{0x01}))
+	require.Equal(t, []string{"01"}, state.RejectedRemoteConfigHashes)
+
+	// Test that loading the state keeps the rejected hashes
+	loadedState, err := loadPersistentState(f)
+	require.NoError(t, err)
+	require.True(t, loadedState.IsRemoteConfigHashRejected([]byte{0x01}))
+
+	// Only the most recently rejected hashes are kept
+	for i := 0; i < maxRejectedRemoteConfigHashes; i++ {
+		require.NoError(t, state.RejectRemoteConfigHash([]byte{0x02, byte(i)}))
+	}
+	require.Len(t, state.RejectedRemoteConfigHashes, maxRejectedRemoteConfigHashes)
+	require.False(t, state.IsRemoteConfigHashRejected([]byte{0x01}))
+	require.True(t, state.IsRemoteConfigHashRejected([]byte{0x02, 0x00}))
+}
@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const lastGoodRemoteConfigFile = "last_good_remote_config.dat"
+
+// loadLastGoodRemoteConfig loads the last remote config the agent was healthy with, and decides
+// whether the last received remote config still has to go through the rollback grace period.
+// It must be called after the last received remote config is loaded.
+func (s *Supervisor) loadLastGoodRemoteConfig() {
+	if !s.config.Agent.ConfigRollback.Enabled {
+		return
+	}
+
+	lastGood, err := os.ReadFile(filepath.Join(s.config.Storage.Directory, lastGoodRemoteConfigFile))
+	switch {
+	case err == nil:
+		config := &protobufs.AgentRemoteConfig{}
+		if err = proto.Unmarshal(lastGood, config); err != nil {
+			s.logger.Error("Cannot parse last good remote config", zap.Error(err))
+		} else {
+			s.lastGoodRemoteConfig = config
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		s.logger.Error("error while reading last good remote config", zap.Error(err))
+	}
+
+	switch {
+	case s.remoteConfig == nil:
+	case s.persistentState.IsRemoteConfigHashRejected(s.remoteConfig.ConfigHash):
+		s.logger.Warn("Last received remote config was rolled back, using the last good remote config",
+			zap.String("hash", fmt.Sprintf("%x", s.remoteConfig.ConfigHash)))
+		s.remoteConfig = s.lastGoodRemoteConfig
+	case !proto.Equal(s.remoteConfig, s.lastGoodRemoteConfig):
+		s.pendingRemoteConfig = s.remoteConfig
+	}
+}
+
+func (s *Supervisor) saveLastGoodRemoteConfig(config *protobufs.AgentRemoteConfig) error {
+	path := filepath.Join(s.config.Storage.Directory, lastGoodRemoteConfigFile)
+	if config == nil {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	cfg, err := proto.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, cfg, 0600)
+}
+
+// hasPendingRemoteConfig returns true if a remote config is waiting for the agent to be healthy.
+func (s *Supervisor) hasPendingRemoteConfig() bool {
+	s.remoteConfigMu.Lock()
+	defer s.remoteConfigMu.Unlock()
+	return s.pendingRemoteConfig != nil
+}
+
+// resetRollbackTimer starts the grace period of the pending remote config, if any.
+func (s *Supervisor) resetRollbackTimer(rollbackTimer *time.Timer) {
+	if !rollbackTimer.Stop() {
+		select {
+		case <-rollbackTimer.C: // Try to drain the channel
+		default:
+		}
+	}
+
+	if s.hasPendingRemoteConfig() {
+		rollbackTimer.Reset(s.config.Agent.ConfigRollback.GracePeriod)
+	}
+}
+
+// checkPendingRemoteConfig checks the health of the agent at the end of the grace period
+// of the pending remote config. It returns true if the remote config was rolled back,
+// in which case the agent must be restarted.
+func (s *Supervisor) checkPendingRemoteConfig() bool {
+	if err := s.checkAgentHealth(); err != nil {
+		return s.rollbackRemoteConfig(fmt.Errorf("agent is not healthy %s after applying the config: %w",
+			s.config.Agent.ConfigRollback.GracePeriod, err))
+	}
+
+	s.confirmRemoteConfig()
+	return false
+}
+
+// checkAgentHealth returns an error if the agent is not running or not healthy.
+func (s *Supervisor) checkAgentHealth() error {
+	if s.healthChecker == nil || !s.commander.IsRunning() {
+		return errors.New("agent is not running")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	return s.healthChecker.Check(ctx)
+}
+
+// confirmRemoteConfig makes the pending remote config the last good one, and reports it as applied.
+func (s *Supervisor) confirmRemoteConfig() {
+	s.remoteConfigMu.Lock()
+	config := s.pendingRemoteConfig
+	if config == nil {
+		s.remoteConfigMu.Unlock()
+		return
+	}
+	s.pendingRemoteConfig = nil
+	s.lastGoodRemoteConfig = config
+	s.remoteConfigMu.Unlock()
+
+	s.logger.Debug("Agent is healthy with remote config", zap.String("hash", fmt.Sprintf("%x", config.ConfigHash)))
+	if err := s.saveLastGoodRemoteConfig(config); err != nil {
+		s.logger.Error("Could not save last good remote config", zap.Error(err))
+	}
+
+	err := s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
+		LastRemoteConfigHash: config.ConfigHash,
+		Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED,
+	})
+	if err != nil {
+		s.logger.Error("Could not report applied OpAMP remote config status", zap.Error(err))
+	}
+}
+
+// rollbackRemoteConfig reverts the pending remote config to the last good one, and reports the
+// pending one as failed with cause. The hash of the pending remote config is rejected, so that it
+// isn't applied again. It returns true if the merged config changed, in which case the agent must be restarted.
+func (s *Supervisor) rollbackRemoteConfig(cause error) bool {
+	s.remoteConfigMu.Lock()
+	bad := s.pendingRemoteConfig
+	if bad == nil {
+		s.remoteConfigMu.Unlock()
+		return false
+	}
+	s.pendingRemoteConfig = nil
+	s.remoteConfig = s.lastGoodRemoteConfig
+
+	s.logger.Warn("Rolling back remote config",
+		zap.String("hash", fmt.Sprintf("%x", bad.ConfigHash)),
+		zap.Error(cause))
+
+	if err := s.persistentState.RejectRemoteConfigHash(bad.ConfigHash); err != nil {
+		s.logger.Error("Failed to persist rejected remote config hash, it may be applied again on restart.", zap.Error(err))
+	}
+
+	if s.remoteConfig == nil {
+		err := os.Remove(filepath.Join(s.config.Storage.Directory, lastRecvRemoteConfigFile))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.logger.Error("Could not remove last received remote config", zap.Error(err))
+		}
+	} else if err := s.saveLastReceivedConfig(s.remoteConfig); err != nil {
+		s.logger.Error("Could not save last received remote config", zap.Error(err))
+	}
+
+	configChanged, err := s.composeMergedConfig(s.remoteConfig)
+	s.remoteConfigMu.Unlock()
+	if err != nil {
+		s.logger.Error("Error composing merged config with the last good remote config", zap.Error(err))
+	}
+
+	err = s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
+		LastRemoteConfigHash: bad.ConfigHash,
+		Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED,
+		ErrorMessage:         fmt.Sprintf("remote config was rolled back: %v", cause),
+	})
+	if err != nil {
+		s.logger.Error("Could not report failed OpAMP remote config status", zap.Error(err))
+	}
+
+	if configChanged {
+		if err = s.opampClient.UpdateEffectiveConfig(context.Background()); err != nil {
+			s.logger.Error("The OpAMP client failed to update the effective config", zap.Error(err))
+		}
+	}
+
+	return configChanged
+}
@@ -0,0 +1,199 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/opampsupervisor/supervisor/config"
+)
+
+func newRollbackTestSupervisor(t *testing.T, statuses *[]*protobufs.RemoteConfigStatus) *Supervisor {
+	storageDir := t.TempDir()
+	state, err := createNewPersistentState(filepath.Join(storageDir, persistentStateFileName))
+	require.NoError(t, err)
+
+	agentDesc := &atomic.Value{}
+	agentDesc.Store(&protobufs.AgentDescription{})
+
+	s := &Supervisor{
+		logger:      zap.NewNop(),
+		pidProvider: staticPIDProvider(1234),
+		config: config.Supervisor{
+			Capabilities: config.Capabilities{AcceptsRemoteConfig: true},
+			Storage:      config.Storage{Directory: storageDir},
+			Agent: config.Agent{
+				ConfigRollback: config.ConfigRollback{Enabled: true, GracePeriod: time.Second},
+			},
+		},
+		hasNewConfig:                 make(chan struct{}, 1),
+		persistentState:              state,
+		agentDescription:             agentDesc,
+		agentConfigOwnMetricsSection: &atomic.Value{},
+		mergedConfig:                 &atomic.Value{},
+		effectiveConfig:              &atomic.Value{},
+		agentHealthCheckEndpoint:     "localhost:8000",
+		opampClient: &mockOpAMPClient{
+			setRemoteConfigStatusFunc: func(status *protobufs.RemoteConfigStatus) error {
+				*statuses = append(*statuses, status)
+				return nil
+			},
+		},
+	}
+	require.NoError(t, s.createTemplates())
+	return s
+}
+
+func newRemoteConfig(hash byte, body string) *protobufs.AgentRemoteConfig {
+	return &protobufs.AgentRemoteConfig{
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"": {Body: []byte(body)},
+			},
+		},
+		ConfigHash: []byte{hash},
+	}
+}
+
+func Test_remoteConfigRollback(t *testing.T) {
+	good := newRemoteConfig(0x01, "receivers:\n  nop:\n")
+	bad := newRemoteConfig(0x02, "receivers:\n  bad:\n")
+
+	t.Run("New remote config is applying until confirmed", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newRollbackTestSupervisor(t, &statuses)
+
+		require.True(t, s.processRemoteConfigMessage(good))
+		require.True(t, s.hasPendingRemoteConfig())
+		require.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING, statuses[0].Status)
+
+		s.confirmRemoteConfig()
+		require.False(t, s.hasPendingRemoteConfig())
+		require.Equal(t, good, s.lastGoodRemoteConfig)
+		require.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, statuses[1].Status)
+		require.Equal(t, good.ConfigHash, statuses[1].LastRemoteConfigHash)
+		require.FileExists(t, filepath.Join(s.config.Storage.Directory, lastGoodRemoteConfigFile))
+	})
+
+	t.Run("Failing remote config is rolled back to the last good one", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newRollbackTestSupervisor(t, &statuses)
+
+		require.True(t, s.processRemoteConfigMessage(good))
+		s.confirmRemoteConfig()
+		goodMergedConfig := s.mergedConfig.Load().(string)
+
+		require.True(t, s.processRemoteConfigMessage(bad))
+		require.Contains(t, s.mergedConfig.Load(), "bad")
+
+		require.True(t, s.rollbackRemoteConfig(errors.New("agent is not healthy")))
+		require.False(t, s.hasPendingRemoteConfig())
+		require.Equal(t, good, s.remoteConfig)
+		require.Equal(t, goodMergedConfig, s.mergedConfig.Load())
+		require.True(t, s.persistentState.IsRemoteConfigHashRejected(bad.ConfigHash))
+
+		status := statuses[len(statuses)-1]
+		require.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, status.Status)
+		require.Equal(t, bad.ConfigHash, status.LastRemoteConfigHash)
+		require.Contains(t, status.ErrorMessage, "agent is not healthy")
+
+		// The last received remote config is the last good one, so that it is used on restart
+		lastRecv, err := os.ReadFile(filepath.Join(s.config.Storage.Directory, lastRecvRemoteConfigFile))
+		require.NoError(t, err)
+		lastRecvConfig := &protobufs.AgentRemoteConfig{}
+		require.NoError(t, proto.Unmarshal(lastRecv, lastRecvConfig))
+		require.True(t, proto.Equal(good, lastRecvConfig))
+	})
+
+	t.Run("Failing first remote config is rolled back to the noop pipeline", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newRollbackTestSupervisor(t, &statuses)
+
+		require.True(t, s.processRemoteConfigMessage(bad))
+		require.True(t, s.rollbackRemoteConfig(errors.New("agent process exited unexpectedly")))
+		require.Nil(t, s.remoteConfig)
+		require.Contains(t, s.mergedConfig.Load(), "nop")
+		require.NotContains(t, s.mergedConfig.Load(), "bad")
+		require.NoFileExists(t, filepath.Join(s.config.Storage.Directory, lastRecvRemoteConfigFile))
+	})
+
+	t.Run("Rejected remote config is not applied again", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newRollbackTestSupervisor(t, &statuses)
+		require.NoError(t, s.persistentState.RejectRemoteConfigHash(bad.ConfigHash))
+
+		require.False(t, s.processRemoteConfigMessage(bad))
+		require.Nil(t, s.remoteConfig)
+		require.False(t, s.hasPendingRemoteConfig())
+		require.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, statuses[0].Status)
+		require.Equal(t, bad.ConfigHash, statuses[0].LastRemoteConfigHash)
+	})
+
+	t.Run("Rollback without pending remote config does nothing", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newRollbackTestSupervisor(t, &statuses)
+
+		require.False(t, s.rollbackRemoteConfig(errors.New("agent is not healthy")))
+		require.Empty(t, statuses)
+	})
+
+	t.Run("Remote config is applied right away when rollback is disabled", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newRollbackTestSupervisor(t, &statuses)
+		s.config.Agent.ConfigRollback.Enabled = false
+
+		require.True(t, s.processRemoteConfigMessage(bad))
+		require.False(t, s.hasPendingRemoteConfig())
+		require.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, statuses[0].Status)
+	})
+}
+
+func Test_loadLastGoodRemoteConfig(t *testing.T) {
+	good := newRemoteConfig(0x01, "receivers:\n  nop:\n")
+	bad := newRemoteConfig(0x02, "receivers:\n  bad:\n")
+
+	t.Run("Received remote config different from the last good one is pending", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newRollbackTestSupervisor(t, &statuses)
+		require.NoError(t, s.saveLastGoodRemoteConfig(good))
+		s.remoteConfig = bad
+
+		s.loadLastGoodRemoteConfig()
+		require.True(t, proto.Equal(good, s.lastGoodRemoteConfig))
+		require.Equal(t, bad, s.pendingRemoteConfig)
+		require.Equal(t, bad, s.remoteConfig)
+	})
+
+	t.Run("Received remote config equal to the last good one is not pending", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newRollbackTestSupervisor(t, &statuses)
+		require.NoError(t, s.saveLastGoodRemoteConfig(good))
+		s.remoteConfig = good
+
+		s.loadLastGoodRemoteConfig()
+		require.Nil(t, s.pendingRemoteConfig)
+	})
+
+	t.Run("Rejected received remote config is replaced by the last good one", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newRollbackTestSupervisor(t, &statuses)
+		require.NoError(t, s.saveLastGoodRemoteConfig(good))
+		require.NoError(t, s.persistentState.RejectRemoteConfigHash(bad.ConfigHash))
+		s.remoteConfig = bad
+
+		s.loadLastGoodRemoteConfig()
+		require.True(t, proto.Equal(good, s.remoteConfig))
+		require.Nil(t, s.pendingRemoteConfig)
+	})
+}
@@ -115,6 +115,20 @@ type Supervisor struct {
 	// Last received remote config.
 	remoteConfig *protobufs.AgentRemoteConfig
 
+	// Last remote config the agent was healthy with, when config rollback is enabled.
+	lastGoodRemoteConfig *protobufs.AgentRemoteConfig
+
+	// Remote config waiting for the agent to be healthy before being considered good,
+	// when config rollback is enabled.
+	pendingRemoteConfig *protobufs.AgentRemoteConfig
+
+	// Guards the remote configs, as they are also rolled back by the agent process loop.
+	remoteConfigMu sync.Mutex
+
+	// Installs the agent packages offered by the server, nil unless packages are accepted.
+	packageManager *packageManager
+	packageSyncMu  sync.Mutex
+
 	// A channel to indicate there is a new config to apply.
 	hasNewConfig chan struct{}
 
@@ -175,6 +189,19 @@ func (s *Supervisor) Start() error {
 		return err
 	}
 
+	if s.config.Capabilities.AcceptsPackages {
+		s.packageManager, err = newPackageManager(s.logger,
+			filepath.Join(s.config.Storage.Directory, packagesDirName),
+			s.config.Agent.Executable,
+			s.config.Agent.Packages.PublicKeyFiles)
+		if err != nil {
+			return fmt.Errorf("could not load agent packages: %w", err)
+		}
+
+		// The agent runs with the executable of the last installed package.
+		s.config.Agent.Executable = s.packageManager.executable()
+	}
+
 	if err = s.getBootstrapInfo(); err != nil {
 		return fmt.Errorf("could not get bootstrap info from the Collector: %w", err)
 	}
@@ -435,6 +462,9 @@ func (s *Supervisor) startOpAMPClient() error {
 		},
 		Capabilities: s.config.Capabilities.SupportedCapabilities(),
 	}
+	if s.packageManager != nil {
+		settings.PackagesStateProvider = s.packageManager
+	}
 	ad := s.agentDescription.Load().(*protobufs.AgentDescription)
 	if err = s.opampClient.SetAgentDescription(ad); err != nil {
 		return err
@@ -781,6 +811,8 @@ func (s *Supervisor) loadAndWriteInitialMergedConfig() error {
 		s.logger.Debug("Remote config is not supported, will not attempt to load config from fil")
 	}
 
+	s.loadLastGoodRemoteConfig()
+
 	if s.config.Capabilities.ReportsOwnMetrics {
 		// Try to load the last received own metrics config if it exists.
 		lastRecvOwnMetricsConfig, err = os.ReadFile(filepath.Join(s.config.Storage.Directory, lastRecvOwnMetricsConfigFile))
@@ -1058,6 +1090,20 @@ func (s *Supervisor) runAgentProcess() {
 	restartTimer := time.NewTimer(0)
 	restartTimer.Stop()
 
+	// Fires at the end of the grace period of a remote config waiting for the agent to be healthy.
+	rollbackTimer := time.NewTimer(0)
+	rollbackTimer.Stop()
+	s.resetRollbackTimer(rollbackTimer)
+
+	// Fires at the end of the grace period of an agent package waiting for the agent to be healthy.
+	packageTimer := time.NewTimer(0)
+	packageTimer.Stop()
+	var packageInstalled <-chan struct{}
+	if s.packageManager != nil {
+		packageInstalled = s.packageManager.installed
+		s.resetPackageTimer(packageTimer)
+	}
+
 	for {
 		select {
 		case <-s.hasNewConfig:
@@ -1065,6 +1111,7 @@ func (s *Supervisor) runAgentProcess() {
 			restartTimer.Stop()
 			s.stopAgentApplyConfig()
 			s.startAgent()
+			s.resetRollbackTimer(rollbackTimer)
 
 		case <-s.commander.Exited():
 			// the agent process exit is expected for restart command and will not attempt to restart
@@ -1083,8 +1130,27 @@ func (s *Supervisor) runAgentProcess() {
 				s.logger.Error("Could not report health to OpAMP server", zap.Error(err))
 			}
 
-			// TODO: decide why the agent stopped. If it was due to bad config, report it to server.
-			// https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/21079
+			// An agent exiting during the grace period of a package is considered to be broken by it.
+			if s.packageManager != nil && s.packageManager.isPending() {
+				packageTimer.Stop()
+				if executable, ok := s.rollbackPackage(fmt.Errorf("agent process exited unexpectedly, exit code=%d", s.commander.ExitCode())); ok {
+					restartTimer.Stop()
+					s.commander.SetExecutable(executable)
+					s.startAgent()
+					continue
+				}
+			}
+
+			// An agent exiting during the grace period of a remote config is considered to be broken by it.
+			if s.hasPendingRemoteConfig() {
+				rollbackTimer.Stop()
+				if s.rollbackRemoteConfig(fmt.Errorf("agent process exited unexpectedly, exit code=%d", s.commander.ExitCode())) {
+					s.stopAgentApplyConfig()
+					restartTimer.Stop()
+					s.startAgent()
+					continue
+				}
+			}
 
 			// Wait 5 seconds before starting again.
 			if !restartTimer.Stop() {
@@ -1099,6 +1165,39 @@ func (s *Supervisor) runAgentProcess() {
 			s.logger.Debug("Agent starting after start backoff")
 			s.startAgent()
 
+		case <-rollbackTimer.C:
+			if s.checkPendingRemoteConfig() {
+				s.logger.Debug("Restarting agent with the last good remote config")
+				restartTimer.Stop()
+				s.stopAgentApplyConfig()
+				s.startAgent()
+			}
+
+		case <-packageInstalled:
+			if executable, ok := s.packageManager.activate(); ok {
+				s.logger.Info("Restarting agent with new package", zap.String("executable", executable))
+				restartTimer.Stop()
+				s.stopAgentApplyConfig()
+				s.commander.SetExecutable(executable)
+				s.startAgent()
+				s.resetPackageTimer(packageTimer)
+			}
+
+		case <-packageTimer.C:
+			if err := s.checkAgentHealth(); err != nil {
+				cause := fmt.Errorf("agent is not healthy %s after installing the package: %w", s.config.Agent.Packages.GracePeriod, err)
+				if executable, ok := s.rollbackPackage(cause); ok {
+					s.logger.Debug("Restarting agent with the previous package")
+					restartTimer.Stop()
+					s.stopAgentApplyConfig()
+					s.commander.SetExecutable(executable)
+					s.startAgent()
+				}
+			} else {
+				s.logger.Debug("Agent is healthy with new package")
+				s.packageManager.confirm()
+			}
+
 		case <-s.healthCheckTicker.C:
 			s.healthCheck()
 
@@ -1191,6 +1290,7 @@ func (s *Supervisor) saveLastReceivedOwnTelemetrySettings(set *protobufs.Telemet
 func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 	configChanged := false
 
+	s.remoteConfigMu.Lock()
 	if msg.AgentIdentification != nil {
 		configChanged = s.processAgentIdentificationMessage(msg.AgentIdentification) || configChanged
 	}
@@ -1202,6 +1302,7 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 	if msg.OwnMetricsConnSettings != nil {
 		configChanged = s.processOwnMetricsConnSettingsMessage(ctx, msg.OwnMetricsConnSettings) || configChanged
 	}
+	s.remoteConfigMu.Unlock()
 
 	// Update the agent config if any messages have touched the config
 	if configChanged {
@@ -1222,6 +1323,12 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 		InstanceUid: s.persistentState.InstanceID[:],
 	}
 	haveMessageForAgent := false
+	// The packages are synced by the Supervisor rather than by the OpAMP client,
+	// so that their signatures are verified and the agent is restarted once they are installed.
+	if msg.PackagesAvailable != nil && s.packageManager != nil {
+		go s.syncPackages(ctx, msg.PackagesAvailable)
+	}
+
 	// Proxy server capabilities to opamp extension
 	if msg.CustomCapabilities != nil {
 		messageToAgent.CustomCapabilities = msg.CustomCapabilities
@@ -1248,6 +1355,19 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 
 // processRemoteConfigMessage processes an AgentRemoteConfig message, returning true if the agent config has changed.
 func (s *Supervisor) processRemoteConfigMessage(msg *protobufs.AgentRemoteConfig) bool {
+	if s.persistentState.IsRemoteConfigHashRejected(msg.ConfigHash) {
+		s.logger.Warn("Received remote config that was previously rolled back, ignoring it", zap.String("hash", fmt.Sprintf("%x", msg.ConfigHash)))
+		err := s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
+			LastRemoteConfigHash: msg.ConfigHash,
+			Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED,
+			ErrorMessage:         "remote config was previously rolled back because the agent was not healthy with it",
+		})
+		if err != nil {
+			s.logger.Error("Could not report failed OpAMP remote config status", zap.Error(err))
+		}
+		return false
+	}
+
 	if err := s.saveLastReceivedConfig(msg); err != nil {
 		s.logger.Error("Could not save last received remote config", zap.Error(err))
 	}
@@ -1267,7 +1387,24 @@ func (s *Supervisor) processRemoteConfigMessage(msg *protobufs.AgentRemoteConfig
 		if err != nil {
 			s.logger.Error("Could not report failed OpAMP remote config status", zap.Error(err))
 		}
+	} else if s.config.Agent.ConfigRollback.Enabled && (configChanged || s.pendingRemoteConfig != nil) {
+		// The config is only considered applied once the agent is healthy with it.
+		s.pendingRemoteConfig = msg
+		err = s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
+			LastRemoteConfigHash: msg.ConfigHash,
+			Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING,
+		})
+		if err != nil {
+			s.logger.Error("Could not report applying OpAMP remote config status", zap.Error(err))
+		}
 	} else {
+		if s.config.Agent.ConfigRollback.Enabled {
+			s.lastGoodRemoteConfig = msg
+			if err = s.saveLastGoodRemoteConfig(msg); err != nil {
+				s.logger.Error("Could not save last good remote config", zap.Error(err))
+			}
+		}
+
 		err = s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
 			LastRemoteConfigHash: msg.ConfigHash,
 			Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED,
@@ -28,7 +28,7 @@ func Test_composeEffectiveConfig(t *testing.T) {
 	s := Supervisor{
 		logger:                       zap.NewNop(),
 		persistentState:              &persistentState{},
-		config:                       config.Supervisor{Capabilities: config.Capabilities{AcceptsRemoteConfig: acceptsRemoteConfig}},
+		config:                       config.Supervisor{Capabilities: config.Capabilities{AcceptsRemoteConfig: acceptsRemoteConfig}, Storage: config.Storage{Directory: t.TempDir()}},
 		pidProvider:                  staticPIDProvider(1234),
 		hasNewConfig:                 make(chan struct{}, 1),
 		agentConfigOwnMetricsSection: &atomic.Value{},
@@ -99,7 +99,7 @@ func Test_onMessage(t *testing.T) {
 		s := Supervisor{
 			logger:                       zap.NewNop(),
 			pidProvider:                  defaultPIDProvider{},
-			config:                       config.Supervisor{},
+			config:                       config.Supervisor{Storage: config.Storage{Directory: t.TempDir()}},
 			hasNewConfig:                 make(chan struct{}, 1),
 			persistentState:              &persistentState{InstanceID: initialID},
 			agentDescription:             agentDesc,
@@ -128,7 +128,7 @@ func Test_onMessage(t *testing.T) {
 		s := Supervisor{
 			logger:                       zap.NewNop(),
 			pidProvider:                  defaultPIDProvider{},
-			config:                       config.Supervisor{},
+			config:                       config.Supervisor{Storage: config.Storage{Directory: t.TempDir()}},
 			hasNewConfig:                 make(chan struct{}, 1),
 			persistentState:              &persistentState{InstanceID: testUUID},
 			agentDescription:             agentDesc,
@@ -175,7 +175,7 @@ func Test_onMessage(t *testing.T) {
 		s := Supervisor{
 			logger:                       zap.NewNop(),
 			pidProvider:                  defaultPIDProvider{},
-			config:                       config.Supervisor{},
+			config:                       config.Supervisor{Storage: config.Storage{Directory: t.TempDir()}},
 			hasNewConfig:                 make(chan struct{}, 1),
 			persistentState:              &persistentState{InstanceID: testUUID},
 			agentConfigOwnMetricsSection: &atomic.Value{},
@@ -218,7 +218,7 @@ func Test_onMessage(t *testing.T) {
 		s := Supervisor{
 			logger:                       zap.NewNop(),
 			pidProvider:                  defaultPIDProvider{},
-			config:                       config.Supervisor{},
+			config:                       config.Supervisor{Storage: config.Storage{Directory: t.TempDir()}},
 			hasNewConfig:                 make(chan struct{}, 1),
 			persistentState:              &persistentState{InstanceID: testUUID},
 			agentConfigOwnMetricsSection: &atomic.Value{},
@@ -255,7 +255,7 @@ func Test_onMessage(t *testing.T) {
 		s := Supervisor{
 			logger:                       zap.NewNop(),
 			pidProvider:                  defaultPIDProvider{},
-			config:                       config.Supervisor{},
+			config:                       config.Supervisor{Storage: config.Storage{Directory: t.TempDir()}},
 			hasNewConfig:                 make(chan struct{}, 1),
 			persistentState:              &persistentState{InstanceID: initialID},
 			agentDescription:             agentDesc,
@@ -317,7 +317,7 @@ func Test_handleAgentOpAMPMessage(t *testing.T) {
 		s := Supervisor{
 			logger:                       zap.NewNop(),
 			pidProvider:                  defaultPIDProvider{},
-			config:                       config.Supervisor{},
+			config:                       config.Supervisor{Storage: config.Storage{Directory: t.TempDir()}},
 			hasNewConfig:                 make(chan struct{}, 1),
 			persistentState:              &persistentState{InstanceID: testUUID},
 			agentConfigOwnMetricsSection: &atomic.Value{},
@@ -370,7 +370,7 @@ func Test_handleAgentOpAMPMessage(t *testing.T) {
 		s := Supervisor{
 			logger:                       zap.NewNop(),
 			pidProvider:                  defaultPIDProvider{},
-			config:                       config.Supervisor{},
+			config:                       config.Supervisor{Storage: config.Storage{Directory: t.TempDir()}},
 			hasNewConfig:                 make(chan struct{}, 1),
 			persistentState:              &persistentState{InstanceID: testUUID},
 			agentConfigOwnMetricsSection: &atomic.Value{},
@@ -398,6 +398,8 @@ type mockOpAMPClient struct {
 	agentDesc                 *protobufs.AgentDescription
 	sendCustomMessageFunc     func(message *protobufs.CustomMessage) (messageSendingChannel chan struct{}, err error)
 	setCustomCapabilitiesFunc func(customCapabilities *protobufs.CustomCapabilities) error
+	setRemoteConfigStatusFunc func(status *protobufs.RemoteConfigStatus) error
+	setPackageStatusesFunc    func(statuses *protobufs.PackageStatuses) error
 }
 
 func (mockOpAMPClient) Start(_ context.Context, _ types.StartSettings) error {
@@ -425,11 +427,17 @@ func (mockOpAMPClient) UpdateEffectiveConfig(_ context.Context) error {
 	return nil
 }
 
-func (mockOpAMPClient) SetRemoteConfigStatus(_ *protobufs.RemoteConfigStatus) error {
+func (m mockOpAMPClient) SetRemoteConfigStatus(status *protobufs.RemoteConfigStatus) error {
+	if m.setRemoteConfigStatusFunc != nil {
+		return m.setRemoteConfigStatusFunc(status)
+	}
 	return nil
 }
 
-func (mockOpAMPClient) SetPackageStatuses(_ *protobufs.PackageStatuses) error {
+func (m mockOpAMPClient) SetPackageStatuses(statuses *protobufs.PackageStatuses) error {
+	if m.setPackageStatusesFunc != nil {
+		return m.setPackageStatusesFunc(statuses)
+	}
 	return nil
 }
 
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter"
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// unknownAttributeValue is the partition value of the resources missing a
+// partitioning attribute.
+const unknownAttributeValue = "unknown"
+
+var pathSeparatorReplacer = strings.NewReplacer("/", "_", "\\", "_")
+
+// generate the time key of the blob names based on partition configuration
+func getTimeKey(t time.Time, partition string) string {
+	year, month, day := t.Date()
+	hour, minute, _ := t.Clock()
+
+	if partition == "hour" {
+		return fmt.Sprintf("year=%d/month=%02d/day=%02d/hour=%02d", year, month, day, hour)
+	}
+	return fmt.Sprintf("year=%d/month=%02d/day=%02d/hour=%02d/minute=%02d", year, month, day, hour, minute)
+}
+
+// getResourceKey returns the key of the blob names for the resource, with one
+// <attribute>=<value> segment per partitioning attribute.
+func getResourceKey(resource pcommon.Resource, attributes []string) string {
+	segments := make([]string, 0, len(attributes))
+	for _, attr := range attributes {
+		value := unknownAttributeValue
+		if v, ok := resource.Attributes().Get(attr); ok && v.AsString() != "" {
+			value = pathSeparatorReplacer.Replace(v.AsString())
+		}
+		segments = append(segments, pathSeparatorReplacer.Replace(attr)+"="+value)
+	}
+	return strings.Join(segments, "/")
+}
+
+func randomID() string {
+	return strconv.Itoa(100000000 + rand.Intn(899999999))
+}
+
+// getBlobName returns the name of the blob as
+// [<prefix>/][<resource key>/]<time key>/<file prefix><signal>[_<id>][.<format>][.gz]
+func getBlobName(config BlobConfig, t time.Time, resourceKey string, signal string, id string, fileFormat string, compression configcompression.Type) string {
+	segments := make([]string, 0, 4)
+	if prefix := strings.Trim(config.Prefix, "/"); prefix != "" {
+		segments = append(segments, prefix)
+	}
+	if resourceKey != "" {
+		segments = append(segments, resourceKey)
+	}
+	segments = append(segments, getTimeKey(t.UTC(), config.TimePartition))
+
+	file := config.FilePrefix + signal
+	if id != "" {
+		file += "_" + id
+	}
+	if fileFormat != "" {
+		file += "." + fileFormat
+	}
+	// add ".gz" extension to files if compression is enabled
+	if compression == configcompression.TypeGzip {
+		file += ".gz"
+	}
+
+	return strings.Join(append(segments, file), "/")
+}
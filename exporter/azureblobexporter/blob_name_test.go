@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+var testTime = time.Date(2024, 9, 3, 7, 5, 42, 0, time.UTC)
+
+func TestGetTimeKey(t *testing.T) {
+	assert.Equal(t, "year=2024/month=09/day=03/hour=07/minute=05", getTimeKey(testTime, "minute"))
+	assert.Equal(t, "year=2024/month=09/day=03/hour=07", getTimeKey(testTime, "hour"))
+}
+
+func TestGetResourceKey(t *testing.T) {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "checkout")
+	resource.Attributes().PutStr("k8s.namespace.name", "shop/prod")
+	resource.Attributes().PutStr("host.name", "")
+	resource.Attributes().PutInt("shard", 3)
+
+	assert.Equal(t, "", getResourceKey(resource, nil))
+	assert.Equal(t,
+		"service.name=checkout/k8s.namespace.name=shop_prod/host.name=unknown/shard=3/cloud.region=unknown",
+		getResourceKey(resource, []string{"service.name", "k8s.namespace.name", "host.name", "shard", "cloud.region"}))
+}
+
+func TestGetBlobName(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      BlobConfig
+		resourceKey string
+		id          string
+		fileFormat  string
+		compression configcompression.Type
+		expected    string
+	}{
+		{
+			name:       "default",
+			config:     BlobConfig{TimePartition: "minute"},
+			id:         "123456789",
+			fileFormat: "json",
+			expected:   "year=2024/month=09/day=03/hour=07/minute=05/logs_123456789.json",
+		},
+		{
+			name: "all_segments",
+			config: BlobConfig{
+				Prefix:        "/telemetry/",
+				TimePartition: "hour",
+				FilePrefix:    "collector-",
+			},
+			resourceKey: "service.name=checkout",
+			id:          "123456789",
+			fileFormat:  "binpb",
+			compression: configcompression.TypeGzip,
+			expected:    "telemetry/service.name=checkout/year=2024/month=09/day=03/hour=07/collector-logs_123456789.binpb.gz",
+		},
+		{
+			name:     "without_id_and_format",
+			config:   BlobConfig{TimePartition: "minute"},
+			expected: "year=2024/month=09/day=03/hour=07/minute=05/logs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getBlobName(tt.config, testTime, tt.resourceKey, "logs", tt.id, tt.fileFormat, tt.compression))
+		})
+	}
+}
+
+func TestGetBlobNameUTC(t *testing.T) {
+	local := testTime.In(time.FixedZone("UTC+2", 2*60*60))
+	assert.Equal(t, "year=2024/month=09/day=03/hour=07/traces", getBlobName(BlobConfig{TimePartition: "hour"}, local, "", "traces", "", "", ""))
+}
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/appendblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+)
+
+// maxAppendBlockBytes is the maximum size of a block appended to an append
+// blob, larger data is appended in several blocks.
+const maxAppendBlockBytes = 4 * 1024 * 1024
+
+type blobWriter interface {
+	uploadBlockBlob(ctx context.Context, containerName string, blobName string, data []byte, headers blob.HTTPHeaders) error
+	// appendBlock appends the data to the append blob, and returns the number of blocks appended.
+	appendBlock(ctx context.Context, containerName string, blobName string, data []byte, headers blob.HTTPHeaders) (int, error)
+}
+
+type azureBlobWriter struct {
+	serviceClient *azblob.Client
+}
+
+var _ blobWriter = (*azureBlobWriter)(nil)
+
+func (bw *azureBlobWriter) uploadBlockBlob(ctx context.Context, containerName string, blobName string, data []byte, headers blob.HTTPHeaders) error {
+	_, err := bw.serviceClient.UploadBuffer(ctx, containerName, blobName, data, &azblob.UploadBufferOptions{
+		HTTPHeaders: &headers,
+	})
+	return err
+}
+
+// appendBlock appends the data to the blob, creating it when it doesn't exist
+// yet. The blob isn't overwritten when it already exists.
+func (bw *azureBlobWriter) appendBlock(ctx context.Context, containerName string, blobName string, data []byte, headers blob.HTTPHeaders) (int, error) {
+	client := bw.serviceClient.ServiceClient().NewContainerClient(containerName).NewAppendBlobClient(blobName)
+	created := false
+	appended := 0
+	for len(data) > 0 {
+		block := data[:min(len(data), maxAppendBlockBytes)]
+		_, err := client.AppendBlock(ctx, streaming.NopCloser(bytes.NewReader(block)), nil)
+		if err != nil && !created && bloberror.HasCode(err, bloberror.BlobNotFound) {
+			if err = createAppendBlob(ctx, client, headers); err != nil {
+				return appended, err
+			}
+			created = true
+			continue
+		}
+		if err != nil {
+			return appended, err
+		}
+		appended++
+		data = data[len(block):]
+	}
+	return appended, nil
+}
+
+func createAppendBlob(ctx context.Context, client *appendblob.Client, headers blob.HTTPHeaders) error {
+	etagAny := azcore.ETagAny
+	_, err := client.Create(ctx, &appendblob.CreateOptions{
+		HTTPHeaders: &headers,
+		AccessConditions: &blob.AccessConditions{
+			ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfNoneMatch: &etagAny},
+		},
+	})
+	if err != nil && !bloberror.HasCode(err, bloberror.BlobAlreadyExists, bloberror.ConditionNotMet) {
+		return fmt.Errorf("failed to create the append blob: %w", err)
+	}
+	return nil
+}
+
+func newBlobWriter(cfg *Config) (*azureBlobWriter, error) {
+	// The failed requests are retried by the exporter helper, according to
+	// the retry_on_failure settings.
+	clientOptions := azcore.ClientOptions{
+		Cloud: cloud.AzurePublic,
+		Retry: policy.RetryOptions{MaxRetries: -1},
+	}
+	if cfg.Cloud == AzureGovernmentCloudType {
+		clientOptions.Cloud = cloud.AzureGovernment
+	}
+
+	var serviceClient *azblob.Client
+	var err error
+	switch cfg.Authentication {
+	case ConnectionStringAuth:
+		serviceClient, err = azblob.NewClientFromConnectionString(string(cfg.ConnectionString), &azblob.ClientOptions{
+			ClientOptions: clientOptions,
+		})
+		if err != nil {
+			return nil, err
+		}
+	case ServicePrincipalAuth:
+		cred, err := azidentity.NewClientSecretCredential(cfg.ServicePrincipal.TenantID, cfg.ServicePrincipal.ClientID, string(cfg.ServicePrincipal.ClientSecret), &azidentity.ClientSecretCredentialOptions{
+			ClientOptions: azcore.ClientOptions{Cloud: clientOptions.Cloud},
+		})
+		if err != nil {
+			return nil, err
+		}
+		serviceClient, err = azblob.NewClient(cfg.StorageAccountURL, cred, &azblob.ClientOptions{
+			ClientOptions: clientOptions,
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown authentication %v", cfg.Authentication)
+	}
+
+	return &azureBlobWriter{serviceClient: serviceClient}, nil
+}
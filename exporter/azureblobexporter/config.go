@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/multierr"
+)
+
+var (
+	// Predefined error responses for configuration validation failures
+	errMissingTenantID          = errors.New(`"TenantID" is not specified in config`)
+	errMissingClientID          = errors.New(`"ClientID" is not specified in config`)
+	errMissingClientSecret      = errors.New(`"ClientSecret" is not specified in config`)
+	errMissingStorageAccountURL = errors.New(`"StorageAccountURL" is not specified in config`)
+	errMissingConnectionString  = errors.New(`"ConnectionString" is not specified in config`)
+)
+
+// Config contains the main configuration options for the Azure Blob exporter
+type Config struct {
+	// Type of authentication to use
+	Authentication AuthType `mapstructure:"auth"`
+	// Azure Blob Storage connection string,
+	// which can be found in the Azure Blob Storage resource on the Azure Portal. (no default)
+	ConnectionString configopaque.String `mapstructure:"connection_string"`
+	// Storage Account URL, used with Service Principal authentication
+	StorageAccountURL string `mapstructure:"storage_account_url"`
+	// Configuration for the Service Principal credentials
+	ServicePrincipal ServicePrincipalConfig `mapstructure:"service_principal"`
+	// Azure Cloud to authenticate against, used with Service Principal authentication
+	Cloud CloudType `mapstructure:"cloud"`
+
+	// Names of the blob containers, per signal
+	Container ContainerConfig `mapstructure:"container"`
+	// Layout of the blob names
+	Blob BlobConfig `mapstructure:"blob"`
+
+	// Marshaler to use, either "otlp_json" (default) or "otlp_proto"
+	MarshalerName MarshalerType `mapstructure:"marshaler"`
+	// Encoding to apply. If present, overrides the marshaler configuration option.
+	Encoding              *component.ID `mapstructure:"encoding"`
+	EncodingFileExtension string        `mapstructure:"encoding_file_extension"`
+
+	// Compression of the blobs, either "none" (default) or "gzip"
+	Compression configcompression.Type `mapstructure:"compression"`
+
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`     // squash ensures fields are correctly decoded in embedded struct.
+	QueueConfig                    exporterhelper.QueueSettings `mapstructure:"sending_queue"`
+	RetryConfig                    configretry.BackOffConfig    `mapstructure:"retry_on_failure"`
+}
+
+type ContainerConfig struct {
+	// Name of the blob container for the logs (default = "logs")
+	Logs string `mapstructure:"logs"`
+	// Name of the blob container for the metrics (default = "metrics")
+	Metrics string `mapstructure:"metrics"`
+	// Name of the blob container for the traces (default = "traces")
+	Traces string `mapstructure:"traces"`
+}
+
+type BlobConfig struct {
+	// Type of the blobs, either "block" (default) or "append"
+	Type BlobType `mapstructure:"type"`
+	// Prefix of the blob names, before the partitions
+	Prefix string `mapstructure:"prefix"`
+	// Time partitioning of the blob names, either "hour" or "minute" (default)
+	TimePartition string `mapstructure:"time_partition"`
+	// Resource attributes partitioning the blob names, in order
+	ResourceAttributes []string `mapstructure:"resource_attributes"`
+	// Prefix of the file part of the blob names
+	FilePrefix string `mapstructure:"file_prefix"`
+}
+
+type ServicePrincipalConfig struct {
+	// Tenant ID, used with Service Principal authentication
+	TenantID string `mapstructure:"tenant_id"`
+	// Client ID, used with Service Principal authentication
+	ClientID string `mapstructure:"client_id"`
+	// Client secret, used with Service Principal authentication
+	ClientSecret configopaque.String `mapstructure:"client_secret"`
+}
+
+type AuthType string
+
+const (
+	ServicePrincipalAuth AuthType = "service_principal"
+	ConnectionStringAuth AuthType = "connection_string"
+)
+
+func (e *AuthType) UnmarshalText(text []byte) error {
+	str := AuthType(text)
+	switch str {
+	case ServicePrincipalAuth, ConnectionStringAuth:
+		*e = str
+		return nil
+	default:
+		return fmt.Errorf("authentication %v is not supported. supported authentications include [%v,%v]", str, ServicePrincipalAuth, ConnectionStringAuth)
+	}
+}
+
+type CloudType string
+
+const (
+	AzureCloudType           = "AzureCloud"
+	AzureGovernmentCloudType = "AzureUSGovernment"
+)
+
+func (e *CloudType) UnmarshalText(text []byte) error {
+	str := CloudType(text)
+	switch str {
+	case AzureCloudType, AzureGovernmentCloudType:
+		*e = str
+		return nil
+	default:
+		return fmt.Errorf("cloud %v is not supported. supported options include [%v,%v]", str, AzureCloudType, AzureGovernmentCloudType)
+	}
+}
+
+type BlobType string
+
+const (
+	BlockBlob  BlobType = "block"
+	AppendBlob BlobType = "append"
+)
+
+func (e *BlobType) UnmarshalText(text []byte) error {
+	str := BlobType(text)
+	switch str {
+	case BlockBlob, AppendBlob:
+		*e = str
+		return nil
+	default:
+		return fmt.Errorf("blob type %v is not supported. supported types include [%v,%v]", str, BlockBlob, AppendBlob)
+	}
+}
+
+type MarshalerType string
+
+const (
+	OtlpProtobuf MarshalerType = "otlp_proto"
+	OtlpJSON     MarshalerType = "otlp_json"
+)
+
+// Validate validates the configuration by checking for missing or invalid fields
+func (c *Config) Validate() (err error) {
+	switch c.Authentication {
+	case ServicePrincipalAuth:
+		if c.ServicePrincipal.TenantID == "" {
+			err = multierr.Append(err, errMissingTenantID)
+		}
+
+		if c.ServicePrincipal.ClientID == "" {
+			err = multierr.Append(err, errMissingClientID)
+		}
+
+		if c.ServicePrincipal.ClientSecret == "" {
+			err = multierr.Append(err, errMissingClientSecret)
+		}
+
+		if c.StorageAccountURL == "" {
+			err = multierr.Append(err, errMissingStorageAccountURL)
+		}
+	case ConnectionStringAuth:
+		if c.ConnectionString == "" {
+			err = multierr.Append(err, errMissingConnectionString)
+		}
+	}
+
+	if c.Container.Logs == "" || c.Container.Metrics == "" || c.Container.Traces == "" {
+		err = multierr.Append(err, errors.New("container names must not be empty"))
+	}
+
+	if c.Blob.TimePartition != "hour" && c.Blob.TimePartition != "minute" {
+		err = multierr.Append(err, fmt.Errorf("time partition %q is not supported. supported options include [hour,minute]", c.Blob.TimePartition))
+	}
+
+	for _, attr := range c.Blob.ResourceAttributes {
+		if attr == "" {
+			err = multierr.Append(err, errors.New("resource attributes must not be empty"))
+			break
+		}
+	}
+
+	if c.Encoding == nil && c.MarshalerName != OtlpJSON && c.MarshalerName != OtlpProtobuf {
+		err = multierr.Append(err, fmt.Errorf("marshaler %q is not supported. supported marshalers include [%v,%v]", c.MarshalerName, OtlpJSON, OtlpProtobuf))
+	}
+
+	if c.Compression.IsCompressed() && c.Compression != configcompression.TypeGzip {
+		err = multierr.Append(err, fmt.Errorf("compression %q is not supported. supported options include [none,gzip]", c.Compression))
+	}
+
+	if c.Timeout < 0 {
+		err = multierr.Append(err, errors.New("'timeout' must be non-negative"))
+	}
+
+	return
+}
@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter/internal/metadata"
+)
+
+const testConnectionString = "DefaultEndpointsProtocol=https;AccountName=accountName;AccountKey=+idLkHYcL0MUWIKYHm2j4Q==;EndpointSuffix=core.windows.net"
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id       component.ID
+		expected component.Config
+	}{
+		{
+			id: component.NewIDWithName(metadata.Type, ""),
+			expected: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.ConnectionString = testConnectionString
+				return cfg
+			}(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "allsettings"),
+			expected: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Authentication = ServicePrincipalAuth
+				cfg.ServicePrincipal = ServicePrincipalConfig{
+					TenantID:     "mock-tenant-id",
+					ClientID:     "mock-client-id",
+					ClientSecret: "mock-client-secret",
+				}
+				cfg.StorageAccountURL = "https://accountName.blob.core.usgovcloudapi.net"
+				cfg.Cloud = AzureGovernmentCloudType
+				cfg.Container = ContainerConfig{
+					Logs:    "otel-logs",
+					Metrics: "otel-metrics",
+					Traces:  "otel-traces",
+				}
+				cfg.Blob = BlobConfig{
+					Type:               AppendBlob,
+					Prefix:             "telemetry",
+					TimePartition:      "hour",
+					ResourceAttributes: []string{"service.name", "host.name"},
+					FilePrefix:         "collector-1-",
+				}
+				cfg.MarshalerName = OtlpProtobuf
+				cfg.Compression = configcompression.TypeGzip
+				cfg.Timeout = 10 * time.Second
+				cfg.QueueConfig = exporterhelper.QueueSettings{
+					Enabled:      true,
+					NumConsumers: 2,
+					QueueSize:    10,
+				}
+				cfg.RetryConfig = configretry.BackOffConfig{
+					Enabled:             true,
+					InitialInterval:     10 * time.Second,
+					RandomizationFactor: 0.7,
+					Multiplier:          3.14,
+					MaxInterval:         1 * time.Minute,
+					MaxElapsedTime:      10 * time.Minute,
+				}
+				return cfg
+			}(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "encoding"),
+			expected: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.ConnectionString = testConnectionString
+				encoding := component.MustNewID("text_encoding")
+				cfg.Encoding = &encoding
+				cfg.EncodingFileExtension = "txt"
+				return cfg
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestUnmarshalInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]any
+		wantErr string
+	}{
+		{
+			name:    "auth",
+			config:  map[string]any{"auth": "anonymous"},
+			wantErr: "authentication anonymous is not supported",
+		},
+		{
+			name:    "cloud",
+			config:  map[string]any{"cloud": "AzureChinaCloud"},
+			wantErr: "cloud AzureChinaCloud is not supported",
+		},
+		{
+			name:    "blob_type",
+			config:  map[string]any{"blob": map[string]any{"type": "page"}},
+			wantErr: "blob type page is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig()
+			err := confmap.NewFromStringMap(tt.config).Unmarshal(cfg)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:   "connection_string",
+			config: func(*Config) {},
+		},
+		{
+			name:    "missing_connection_string",
+			config:  func(cfg *Config) { cfg.ConnectionString = "" },
+			wantErr: `"ConnectionString" is not specified in config`,
+		},
+		{
+			name: "missing_service_principal",
+			config: func(cfg *Config) {
+				cfg.Authentication = ServicePrincipalAuth
+			},
+			wantErr: `"TenantID" is not specified in config; "ClientID" is not specified in config; "ClientSecret" is not specified in config; "StorageAccountURL" is not specified in config`,
+		},
+		{
+			name:    "empty_container",
+			config:  func(cfg *Config) { cfg.Container.Metrics = "" },
+			wantErr: "container names must not be empty",
+		},
+		{
+			name:    "invalid_time_partition",
+			config:  func(cfg *Config) { cfg.Blob.TimePartition = "day" },
+			wantErr: `time partition "day" is not supported`,
+		},
+		{
+			name:    "empty_resource_attribute",
+			config:  func(cfg *Config) { cfg.Blob.ResourceAttributes = []string{"service.name", ""} },
+			wantErr: "resource attributes must not be empty",
+		},
+		{
+			name:    "invalid_marshaler",
+			config:  func(cfg *Config) { cfg.MarshalerName = "sumo_ic" },
+			wantErr: `marshaler "sumo_ic" is not supported`,
+		},
+		{
+			name: "encoding",
+			config: func(cfg *Config) {
+				encoding := component.MustNewID("text_encoding")
+				cfg.Encoding = &encoding
+				cfg.MarshalerName = ""
+			},
+		},
+		{
+			name:    "invalid_compression",
+			config:  func(cfg *Config) { cfg.Compression = configcompression.TypeZstd },
+			wantErr: `compression "zstd" is not supported`,
+		},
+		{
+			name:    "invalid_timeout",
+			config:  func(cfg *Config) { cfg.Timeout = -5 * time.Second },
+			wantErr: "'timeout' must be non-negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.ConnectionString = testConnectionString
+			tt.config(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// maxAppendBlobBlocks is the maximum number of blocks of an append blob.
+const maxAppendBlobBlocks = 50000
+
+type blobExporter struct {
+	config    *Config
+	logger    *zap.Logger
+	writer    blobWriter
+	marshaler *marshaler
+	now       func() time.Time
+
+	// writerID is the <id> of the append blobs, so that each exporter appends to its own blobs.
+	writerID string
+	mu       sync.Mutex
+	// appendBlobs are the append blobs being written, by signal and resource key.
+	appendBlobs map[string]*appendBlob
+}
+
+// appendBlob is the append blob of a partition. The appends to the blob are serialized by its
+// lock, so that the blocks of an export aren't interleaved with those of another export.
+type appendBlob struct {
+	mu sync.Mutex
+	// name is the name of the blob before it's rolled over, which changes with the time partition.
+	name string
+	// seq is the number of times the blob was rolled over.
+	seq int
+	// blocks is the number of blocks appended to the blob.
+	blocks int
+}
+
+func newBlobExporter(config *Config, params exporter.Settings) *blobExporter {
+	return &blobExporter{
+		config:      config,
+		logger:      params.Logger,
+		now:         time.Now,
+		writerID:    randomID(),
+		appendBlobs: map[string]*appendBlob{},
+	}
+}
+
+func (e *blobExporter) start(_ context.Context, host component.Host) error {
+	var m *marshaler
+	var err error
+	if e.config.Encoding != nil {
+		if m, err = newMarshalerFromEncoding(e.config.Encoding, e.config.EncodingFileExtension, host); err != nil {
+			return err
+		}
+	} else {
+		if m, err = newMarshaler(e.config.MarshalerName); err != nil {
+			return fmt.Errorf("unknown marshaler %q", e.config.MarshalerName)
+		}
+	}
+	e.marshaler = m
+
+	if e.writer == nil {
+		writer, err := newBlobWriter(e.config)
+		if err != nil {
+			return err
+		}
+		e.writer = writer
+	}
+	return nil
+}
+
+func (e *blobExporter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	partitions := partitionLogs(ld, e.config.Blob.ResourceAttributes)
+	bufs := make([][]byte, len(partitions))
+	for i, p := range partitions {
+		buf, err := e.marshaler.marshalLogs(p.logs)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		bufs[i] = buf
+	}
+
+	now := e.now()
+	var errs error
+	failed := plog.NewLogs()
+	for i, p := range partitions {
+		if err := e.write(ctx, e.config.Container.Logs, "logs", p.resourceKey, now, bufs[i]); err != nil {
+			errs = multierr.Append(errs, err)
+			for j := 0; j < p.logs.ResourceLogs().Len(); j++ {
+				p.logs.ResourceLogs().At(j).CopyTo(failed.ResourceLogs().AppendEmpty())
+			}
+		}
+	}
+	if errs != nil {
+		// Only the failed partitions are retried, as the others were written.
+		return consumererror.NewLogs(errs, failed)
+	}
+	return nil
+}
+
+func (e *blobExporter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	partitions := partitionMetrics(md, e.config.Blob.ResourceAttributes)
+	bufs := make([][]byte, len(partitions))
+	for i, p := range partitions {
+		buf, err := e.marshaler.marshalMetrics(p.metrics)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		bufs[i] = buf
+	}
+
+	now := e.now()
+	var errs error
+	failed := pmetric.NewMetrics()
+	for i, p := range partitions {
+		if err := e.write(ctx, e.config.Container.Metrics, "metrics", p.resourceKey, now, bufs[i]); err != nil {
+			errs = multierr.Append(errs, err)
+			for j := 0; j < p.metrics.ResourceMetrics().Len(); j++ {
+				p.metrics.ResourceMetrics().At(j).CopyTo(failed.ResourceMetrics().AppendEmpty())
+			}
+		}
+	}
+	if errs != nil {
+		// Only the failed partitions are retried, as the others were written.
+		return consumererror.NewMetrics(errs, failed)
+	}
+	return nil
+}
+
+func (e *blobExporter) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	partitions := partitionTraces(td, e.config.Blob.ResourceAttributes)
+	bufs := make([][]byte, len(partitions))
+	for i, p := range partitions {
+		buf, err := e.marshaler.marshalTraces(p.traces)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		bufs[i] = buf
+	}
+
+	now := e.now()
+	var errs error
+	failed := ptrace.NewTraces()
+	for i, p := range partitions {
+		if err := e.write(ctx, e.config.Container.Traces, "traces", p.resourceKey, now, bufs[i]); err != nil {
+			errs = multierr.Append(errs, err)
+			for j := 0; j < p.traces.ResourceSpans().Len(); j++ {
+				p.traces.ResourceSpans().At(j).CopyTo(failed.ResourceSpans().AppendEmpty())
+			}
+		}
+	}
+	if errs != nil {
+		// Only the failed partitions are retried, as the others were written.
+		return consumererror.NewTraces(errs, failed)
+	}
+	return nil
+}
+
+// write writes the data to a new block blob, or appends it to the append blob
+// of the partition.
+func (e *blobExporter) write(ctx context.Context, containerName string, signal string, resourceKey string, now time.Time, data []byte) error {
+	var headers blob.HTTPHeaders
+	if e.marshaler.contentType != "" {
+		headers.BlobContentType = &e.marshaler.contentType
+	}
+	if e.config.Blob.Type == AppendBlob {
+		data = append(data, e.marshaler.separator...)
+	}
+	if e.config.Compression == configcompression.TypeGzip {
+		var err error
+		if data, err = gzipData(data); err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		encoding := "gzip"
+		headers.BlobContentEncoding = &encoding
+	}
+
+	if e.config.Blob.Type == AppendBlob {
+		return e.appendToBlob(ctx, containerName, signal, resourceKey, now, data, headers)
+	}
+	name := getBlobName(e.config.Blob, now, resourceKey, signal, randomID(), e.marshaler.fileFormat, e.config.Compression)
+	return e.writer.uploadBlockBlob(ctx, containerName, name, data, headers)
+}
+
+// appendToBlob appends the data to the append blob of the partition. The blob is rolled over
+// to a new one before exceeding the maximum number of blocks, and after an append failing
+// part way, so that the data appended again doesn't follow a partial record.
+func (e *blobExporter) appendToBlob(ctx context.Context, containerName string, signal string, resourceKey string, now time.Time, data []byte, headers blob.HTTPHeaders) error {
+	e.mu.Lock()
+	b, ok := e.appendBlobs[signal+"/"+resourceKey]
+	if !ok {
+		b = &appendBlob{}
+		e.appendBlobs[signal+"/"+resourceKey] = b
+	}
+	e.mu.Unlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	name := getBlobName(e.config.Blob, now, resourceKey, signal, e.writerID, e.marshaler.fileFormat, e.config.Compression)
+	if b.name != name {
+		b.name, b.seq, b.blocks = name, 0, 0
+	}
+	blocks := (len(data) + maxAppendBlockBytes - 1) / maxAppendBlockBytes
+	if b.blocks > 0 && b.blocks+blocks > maxAppendBlobBlocks {
+		b.seq++
+		b.blocks = 0
+	}
+	if b.seq > 0 {
+		name = getBlobName(e.config.Blob, now, resourceKey, signal, e.writerID+"_"+strconv.Itoa(b.seq), e.marshaler.fileFormat, e.config.Compression)
+	}
+
+	appended, err := e.writer.appendBlock(ctx, containerName, name, data, headers)
+	b.blocks += appended
+	if err != nil && appended > 0 {
+		b.seq++
+		b.blocks = 0
+	}
+	return err
+}
+
+// gzipData compresses the data as a gzip member. The members appended to an
+// append blob are read back as a single gzip stream.
+func gzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	if _, err := gzipWriter.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,423 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const testAccount = "devstoreaccount1"
+
+type testBlob struct {
+	blobType        string
+	contentType     string
+	contentEncoding string
+	data            []byte
+}
+
+// fakeBlobServer implements the subset of the Blob service REST API used by
+// the exporter, in the same way as the Azurite emulator.
+type fakeBlobServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	blobs map[string]*testBlob
+	// failingPrefix is the prefix of the blob paths whose writes fail.
+	failingPrefix string
+}
+
+func newFakeBlobServer(t *testing.T) *fakeBlobServer {
+	s := &fakeBlobServer{blobs: map[string]*testBlob{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeBlobServer) connectionString() string {
+	return "DefaultEndpointsProtocol=http;AccountName=" + testAccount + ";AccountKey=+idLkHYcL0MUWIKYHm2j4Q==;BlobEndpoint=" + s.URL + "/" + testAccount + ";"
+}
+
+func (s *fakeBlobServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/"+testAccount+"/")
+	if r.Method != http.MethodPut {
+		writeBlobError(w, http.StatusMethodNotAllowed, "UnsupportedHttpVerb")
+		return
+	}
+	if s.failingPrefix != "" && strings.HasPrefix(path, s.failingPrefix) {
+		writeBlobError(w, http.StatusInternalServerError, "InternalError")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeBlobError(w, http.StatusBadRequest, "InvalidInput")
+		return
+	}
+
+	existing, exists := s.blobs[path]
+	switch {
+	case r.URL.Query().Get("comp") == "appendblock":
+		if !exists {
+			writeBlobError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		if existing.blobType != "AppendBlob" {
+			writeBlobError(w, http.StatusConflict, "InvalidBlobType")
+			return
+		}
+		existing.data = append(existing.data, body...)
+	case r.Header.Get("x-ms-blob-type") == "AppendBlob":
+		if exists && r.Header.Get("If-None-Match") == "*" {
+			writeBlobError(w, http.StatusConflict, "BlobAlreadyExists")
+			return
+		}
+		s.blobs[path] = newTestBlob(r, nil)
+	case r.Header.Get("x-ms-blob-type") == "BlockBlob":
+		s.blobs[path] = newTestBlob(r, body)
+	default:
+		writeBlobError(w, http.StatusBadRequest, "InvalidHeaderValue")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func newTestBlob(r *http.Request, data []byte) *testBlob {
+	return &testBlob{
+		blobType:        r.Header.Get("x-ms-blob-type"),
+		contentType:     r.Header.Get("x-ms-blob-content-type"),
+		contentEncoding: r.Header.Get("x-ms-blob-content-encoding"),
+		data:            data,
+	}
+}
+
+func writeBlobError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+}
+
+func (s *fakeBlobServer) blobNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.blobs))
+	for name := range s.blobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *fakeBlobServer) blob(t *testing.T, name string) *testBlob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.blobs[name]
+	require.True(t, ok, "blob %q not found", name)
+	return b
+}
+
+func newTestExporter(t *testing.T, server *fakeBlobServer, configure func(cfg *Config)) *blobExporter {
+	cfg := createDefaultConfig().(*Config)
+	if server != nil {
+		cfg.ConnectionString = configopaque.String(server.connectionString())
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+	require.NoError(t, cfg.Validate())
+
+	exp := newBlobExporter(cfg, exportertest.NewNopSettings())
+	exp.now = func() time.Time { return testTime }
+	exp.writerID = "123456789"
+	return exp
+}
+
+func TestExportBlockBlobs(t *testing.T) {
+	server := newFakeBlobServer(t)
+	exp := newTestExporter(t, server, nil)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, exp.ConsumeLogs(context.Background(), generateLogs()))
+	require.NoError(t, exp.ConsumeMetrics(context.Background(), generateMetrics()))
+	require.NoError(t, exp.ConsumeTraces(context.Background(), generateTraces()))
+	require.NoError(t, exp.ConsumeLogs(context.Background(), generateLogs()))
+
+	names := server.blobNames()
+	require.Len(t, names, 4)
+	for _, name := range names {
+		assert.Regexp(t, `^(logs|metrics|traces)/year=2024/month=09/day=03/hour=07/minute=05/(logs|metrics|traces)_\d{9}\.json$`, name)
+		assert.Equal(t, strings.Split(name, "/")[0], strings.Split(strings.Split(name, "/")[6], "_")[0])
+	}
+
+	// The blobs are read the same way as the Azure Blob receiver does.
+	b := server.blob(t, names[0])
+	assert.Equal(t, "BlockBlob", b.blobType)
+	assert.Equal(t, "application/json", b.contentType)
+	assert.Equal(t, "", b.contentEncoding)
+	logs, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(b.data)
+	require.NoError(t, err)
+	assert.Equal(t, generateLogs(), logs)
+
+	b = server.blob(t, names[2])
+	metrics, err := (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(b.data)
+	require.NoError(t, err)
+	assert.Equal(t, generateMetrics(), metrics)
+
+	b = server.blob(t, names[3])
+	traces, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(b.data)
+	require.NoError(t, err)
+	assert.Equal(t, generateTraces(), traces)
+}
+
+func TestExportPartitionedGzipProtoBlobs(t *testing.T) {
+	server := newFakeBlobServer(t)
+	exp := newTestExporter(t, server, func(cfg *Config) {
+		cfg.Container.Traces = "otel-traces"
+		cfg.Blob.Prefix = "telemetry"
+		cfg.Blob.TimePartition = "hour"
+		cfg.Blob.ResourceAttributes = []string{"service.name"}
+		cfg.MarshalerName = OtlpProtobuf
+		cfg.Compression = configcompression.TypeGzip
+	})
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, exp.ConsumeTraces(context.Background(), generateTraces()))
+
+	names := server.blobNames()
+	require.Len(t, names, 2)
+	assert.Regexp(t, `^otel-traces/telemetry/service.name=cart/year=2024/month=09/day=03/hour=07/traces_\d{9}\.binpb\.gz$`, names[0])
+	assert.Regexp(t, `^otel-traces/telemetry/service.name=checkout/year=2024/month=09/day=03/hour=07/traces_\d{9}\.binpb\.gz$`, names[1])
+
+	b := server.blob(t, names[1])
+	assert.Equal(t, "application/x-protobuf", b.contentType)
+	assert.Equal(t, "gzip", b.contentEncoding)
+	traces, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(gunzip(t, b.data))
+	require.NoError(t, err)
+	require.Equal(t, 2, traces.ResourceSpans().Len())
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		serviceName, _ := traces.ResourceSpans().At(i).Resource().Attributes().Get("service.name")
+		assert.Equal(t, "checkout", serviceName.Str())
+	}
+}
+
+func TestExportAppendBlobs(t *testing.T) {
+	tests := []struct {
+		name      string
+		marshaler MarshalerType
+		blobName  string
+		read      func(t *testing.T, data []byte)
+	}{
+		{
+			name:      "json",
+			marshaler: OtlpJSON,
+			blobName:  "logs/year=2024/month=09/day=03/hour=07/minute=05/logs_123456789.json.gz",
+			read: func(t *testing.T, data []byte) {
+				lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+				require.Len(t, lines, 2)
+				for _, line := range lines {
+					logs, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs([]byte(line))
+					require.NoError(t, err)
+					assert.Equal(t, generateLogs(), logs)
+				}
+			},
+		},
+		{
+			name:      "proto",
+			marshaler: OtlpProtobuf,
+			blobName:  "logs/year=2024/month=09/day=03/hour=07/minute=05/logs_123456789.binpb.gz",
+			read: func(t *testing.T, data []byte) {
+				// The appended messages are read back as a single message.
+				logs, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(data)
+				require.NoError(t, err)
+				assert.Equal(t, 2*generateLogs().ResourceLogs().Len(), logs.ResourceLogs().Len())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeBlobServer(t)
+			exp := newTestExporter(t, server, func(cfg *Config) {
+				cfg.Blob.Type = AppendBlob
+				cfg.MarshalerName = tt.marshaler
+				cfg.Compression = configcompression.TypeGzip
+			})
+			require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+			require.NoError(t, exp.ConsumeLogs(context.Background(), generateLogs()))
+			require.NoError(t, exp.ConsumeLogs(context.Background(), generateLogs()))
+
+			assert.Equal(t, []string{tt.blobName}, server.blobNames())
+			b := server.blob(t, tt.blobName)
+			assert.Equal(t, "AppendBlob", b.blobType)
+			assert.Equal(t, "gzip", b.contentEncoding)
+			tt.read(t, gunzip(t, b.data))
+		})
+	}
+}
+
+func TestAppendLargeBlock(t *testing.T) {
+	server := newFakeBlobServer(t)
+	exp := newTestExporter(t, server, func(cfg *Config) {
+		cfg.Blob.Type = AppendBlob
+	})
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	data := bytes.Repeat([]byte("a"), 2*maxAppendBlockBytes+1)
+	appended, err := exp.writer.appendBlock(context.Background(), "logs", "large", data, blob.HTTPHeaders{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, appended)
+	assert.Equal(t, data, server.blob(t, "logs/large").data)
+}
+
+func TestAppendBlobRollover(t *testing.T) {
+	server := newFakeBlobServer(t)
+	exp := newTestExporter(t, server, func(cfg *Config) {
+		cfg.Blob.Type = AppendBlob
+	})
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, exp.ConsumeLogs(context.Background(), generateLogs()))
+	assert.Equal(t, 1, exp.appendBlobs["logs/"].blocks)
+
+	// The next append would exceed the maximum number of blocks of the blob.
+	exp.appendBlobs["logs/"].blocks = maxAppendBlobBlocks
+	require.NoError(t, exp.ConsumeLogs(context.Background(), generateLogs()))
+	require.NoError(t, exp.ConsumeLogs(context.Background(), generateLogs()))
+	assert.Equal(t, []string{
+		"logs/year=2024/month=09/day=03/hour=07/minute=05/logs_123456789.json",
+		"logs/year=2024/month=09/day=03/hour=07/minute=05/logs_123456789_1.json",
+	}, server.blobNames())
+	assert.Equal(t, 2, exp.appendBlobs["logs/"].blocks)
+
+	// The blob of a new time partition isn't rolled over.
+	exp.now = func() time.Time { return testTime.Add(time.Minute) }
+	require.NoError(t, exp.ConsumeLogs(context.Background(), generateLogs()))
+	assert.Contains(t, server.blobNames(), "logs/year=2024/month=09/day=03/hour=07/minute=06/logs_123456789.json")
+}
+
+func TestExportFailedPartitions(t *testing.T) {
+	server := newFakeBlobServer(t)
+	exp := newTestExporter(t, server, func(cfg *Config) {
+		cfg.Blob.ResourceAttributes = []string{"host.name"}
+	})
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	server.failingPrefix = "metrics/"
+	md := generateMetrics()
+	err := exp.ConsumeMetrics(context.Background(), md)
+	assert.False(t, consumererror.IsPermanent(err))
+	var metricsErr consumererror.Metrics
+	require.ErrorAs(t, err, &metricsErr)
+	assert.Equal(t, md, metricsErr.Data())
+	assert.Empty(t, server.blobNames())
+
+	// Only the failed partitions are retried.
+	server.failingPrefix = "traces/host.name=web-2/"
+	err = exp.ConsumeTraces(context.Background(), generateTraces())
+	var tracesErr consumererror.Traces
+	require.ErrorAs(t, err, &tracesErr)
+	require.Equal(t, 1, tracesErr.Data().ResourceSpans().Len())
+	hostName, _ := tracesErr.Data().ResourceSpans().At(0).Resource().Attributes().Get("host.name")
+	assert.Equal(t, "web-2", hostName.Str())
+	require.Len(t, server.blobNames(), 1)
+	assert.True(t, strings.HasPrefix(server.blobNames()[0], "traces/host.name=web-1/"))
+
+	server.failingPrefix = ""
+	require.NoError(t, exp.ConsumeTraces(context.Background(), tracesErr.Data()))
+	names := server.blobNames()
+	require.Len(t, names, 2)
+	assert.True(t, strings.HasPrefix(names[1], "traces/host.name=web-2/"))
+}
+
+func TestExportEncoding(t *testing.T) {
+	server := newFakeBlobServer(t)
+	encodingID := component.MustNewID("test_encoding")
+	exp := newTestExporter(t, server, func(cfg *Config) {
+		cfg.Encoding = &encodingID
+		cfg.EncodingFileExtension = "txt"
+	})
+	host := &hostWithExtensions{extensions: map[component.ID]component.Component{encodingID: &bodyEncoding{}}}
+	require.NoError(t, exp.start(context.Background(), host))
+
+	require.NoError(t, exp.ConsumeLogs(context.Background(), generateLogs()))
+	names := server.blobNames()
+	require.Len(t, names, 1)
+	assert.Regexp(t, `^logs/year=2024/month=09/day=03/hour=07/minute=05/logs_\d{9}\.txt$`, names[0])
+	b := server.blob(t, names[0])
+	assert.Equal(t, "", b.contentType)
+	assert.Equal(t, "order placed on web-1\norder placed on web-1\norder placed on web-2\n", string(b.data))
+
+	err := exp.ConsumeTraces(context.Background(), generateTraces())
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.ErrorContains(t, err, "traces are not supported by the encoding")
+}
+
+func TestStartUnknownEncoding(t *testing.T) {
+	encodingID := component.MustNewID("test_encoding")
+	exp := newTestExporter(t, nil, func(cfg *Config) {
+		cfg.ConnectionString = testConnectionString
+		cfg.Encoding = &encodingID
+	})
+	assert.EqualError(t, exp.start(context.Background(), componenttest.NewNopHost()), `unknown encoding "test_encoding"`)
+}
+
+func gunzip(t *testing.T, data []byte) []byte {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return decompressed
+}
+
+type hostWithExtensions struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *hostWithExtensions) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+// bodyEncoding is a logs only encoding extension writing the log bodies.
+type bodyEncoding struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+func (*bodyEncoding) MarshalLogs(ld plog.Logs) ([]byte, error) {
+	var buf bytes.Buffer
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		sls := ld.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			lrs := sls.At(j).LogRecords()
+			for k := 0; k < lrs.Len(); k++ {
+				buf.WriteString(lrs.At(k).Body().AsString())
+				buf.WriteByte('\n')
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
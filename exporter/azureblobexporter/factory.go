@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter/internal/metadata"
+)
+
+const (
+	logsContainerName    = "logs"
+	metricsContainerName = "metrics"
+	tracesContainerName  = "traces"
+	defaultCloud         = AzureCloudType
+)
+
+// NewFactory creates a factory for Azure Blob exporter.
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		exporter.WithTraces(createTracesExporter, metadata.TracesStability),
+		exporter.WithLogs(createLogsExporter, metadata.LogsStability),
+		exporter.WithMetrics(createMetricsExporter, metadata.MetricsStability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Authentication: ConnectionStringAuth,
+		Cloud:          defaultCloud,
+		Container: ContainerConfig{
+			Logs:    logsContainerName,
+			Metrics: metricsContainerName,
+			Traces:  tracesContainerName,
+		},
+		Blob: BlobConfig{
+			Type:          BlockBlob,
+			TimePartition: "minute",
+		},
+		MarshalerName:   OtlpJSON,
+		TimeoutSettings: exporterhelper.NewDefaultTimeoutSettings(),
+		QueueConfig:     exporterhelper.NewDefaultQueueSettings(),
+		RetryConfig:     configretry.NewDefaultBackOffConfig(),
+	}
+}
+
+func exporterOptions(cfg *Config, blobExporter *blobExporter) []exporterhelper.Option {
+	return []exporterhelper.Option{
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
+		exporterhelper.WithTimeout(cfg.TimeoutSettings),
+		exporterhelper.WithQueue(cfg.QueueConfig),
+		exporterhelper.WithRetry(cfg.RetryConfig),
+		exporterhelper.WithStart(blobExporter.start),
+	}
+}
+
+func createLogsExporter(ctx context.Context,
+	params exporter.Settings,
+	config component.Config) (exporter.Logs, error) {
+	cfg := config.(*Config)
+	blobExporter := newBlobExporter(cfg, params)
+
+	return exporterhelper.NewLogsExporter(ctx, params,
+		config,
+		blobExporter.ConsumeLogs,
+		exporterOptions(cfg, blobExporter)...)
+}
+
+func createMetricsExporter(ctx context.Context,
+	params exporter.Settings,
+	config component.Config) (exporter.Metrics, error) {
+	cfg := config.(*Config)
+	blobExporter := newBlobExporter(cfg, params)
+
+	return exporterhelper.NewMetricsExporter(ctx, params,
+		config,
+		blobExporter.ConsumeMetrics,
+		exporterOptions(cfg, blobExporter)...)
+}
+
+func createTracesExporter(ctx context.Context,
+	params exporter.Settings,
+	config component.Config) (exporter.Traces, error) {
+	cfg := config.(*Config)
+	blobExporter := newBlobExporter(cfg, params)
+
+	return exporterhelper.NewTracesExporter(ctx,
+		params,
+		config,
+		blobExporter.ConsumeTraces,
+		exporterOptions(cfg, blobExporter)...)
+}
@@ -0,0 +1,18 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/component"
+)
+
+var (
+	Type      = component.MustNewType("azureblob")
+	ScopeName = "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter"
+)
+
+const (
+	TracesStability  = component.StabilityLevelDevelopment
+	MetricsStability = component.StabilityLevelDevelopment
+	LogsStability    = component.StabilityLevelDevelopment
+)
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type marshaler struct {
+	logsMarshaler    plog.Marshaler
+	metricsMarshaler pmetric.Marshaler
+	tracesMarshaler  ptrace.Marshaler
+	fileFormat       string
+	contentType      string
+	// separator is appended to the marshaled data in append blobs, so
+	// consecutive appends can be told apart.
+	separator []byte
+}
+
+var errUnknownMarshaler = errors.New("unknown marshaler")
+
+func newMarshalerFromEncoding(encoding *component.ID, fileFormat string, host component.Host) (*marshaler, error) {
+	e, ok := host.GetExtensions()[*encoding]
+	if !ok {
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+	m := &marshaler{fileFormat: fileFormat}
+	// cast with ok to avoid panics.
+	m.logsMarshaler, _ = e.(plog.Marshaler)
+	m.metricsMarshaler, _ = e.(pmetric.Marshaler)
+	m.tracesMarshaler, _ = e.(ptrace.Marshaler)
+	return m, nil
+}
+
+func newMarshaler(mType MarshalerType) (*marshaler, error) {
+	switch mType {
+	case OtlpProtobuf:
+		// Concatenated OTLP protobuf messages decode as a single message
+		// holding all the resources, so no separator is needed.
+		return &marshaler{
+			logsMarshaler:    &plog.ProtoMarshaler{},
+			metricsMarshaler: &pmetric.ProtoMarshaler{},
+			tracesMarshaler:  &ptrace.ProtoMarshaler{},
+			fileFormat:       "binpb",
+			contentType:      "application/x-protobuf",
+		}, nil
+	case OtlpJSON:
+		return &marshaler{
+			logsMarshaler:    &plog.JSONMarshaler{},
+			metricsMarshaler: &pmetric.JSONMarshaler{},
+			tracesMarshaler:  &ptrace.JSONMarshaler{},
+			fileFormat:       "json",
+			contentType:      "application/json",
+			separator:        []byte("\n"),
+		}, nil
+	default:
+		return nil, errUnknownMarshaler
+	}
+}
+
+func (m *marshaler) marshalLogs(ld plog.Logs) ([]byte, error) {
+	if m.logsMarshaler == nil {
+		return nil, errors.New("logs are not supported by the encoding")
+	}
+	return m.logsMarshaler.MarshalLogs(ld)
+}
+
+func (m *marshaler) marshalMetrics(md pmetric.Metrics) ([]byte, error) {
+	if m.metricsMarshaler == nil {
+		return nil, errors.New("metrics are not supported by the encoding")
+	}
+	return m.metricsMarshaler.MarshalMetrics(md)
+}
+
+func (m *marshaler) marshalTraces(td ptrace.Traces) ([]byte, error) {
+	if m.tracesMarshaler == nil {
+		return nil, errors.New("traces are not supported by the encoding")
+	}
+	return m.tracesMarshaler.MarshalTraces(td)
+}
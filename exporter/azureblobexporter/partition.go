@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azureblobexporter"
+
+import (
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type logsPartition struct {
+	resourceKey string
+	logs        plog.Logs
+}
+
+type metricsPartition struct {
+	resourceKey string
+	metrics     pmetric.Metrics
+}
+
+type tracesPartition struct {
+	resourceKey string
+	traces      ptrace.Traces
+}
+
+// partitionLogs groups the resources by their values of the partitioning
+// attributes, in the order they are first seen. The logs aren't copied
+// without partitioning attributes.
+func partitionLogs(ld plog.Logs, attributes []string) []logsPartition {
+	if len(attributes) == 0 {
+		return []logsPartition{{logs: ld}}
+	}
+
+	var partitions []logsPartition
+	indexes := map[string]int{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		key := getResourceKey(rls.At(i).Resource(), attributes)
+		idx, ok := indexes[key]
+		if !ok {
+			idx = len(partitions)
+			indexes[key] = idx
+			partitions = append(partitions, logsPartition{resourceKey: key, logs: plog.NewLogs()})
+		}
+		rls.At(i).CopyTo(partitions[idx].logs.ResourceLogs().AppendEmpty())
+	}
+	return partitions
+}
+
+// partitionMetrics groups the resources by their values of the partitioning
+// attributes, in the order they are first seen. The metrics aren't copied
+// without partitioning attributes.
+func partitionMetrics(md pmetric.Metrics, attributes []string) []metricsPartition {
+	if len(attributes) == 0 {
+		return []metricsPartition{{metrics: md}}
+	}
+
+	var partitions []metricsPartition
+	indexes := map[string]int{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		key := getResourceKey(rms.At(i).Resource(), attributes)
+		idx, ok := indexes[key]
+		if !ok {
+			idx = len(partitions)
+			indexes[key] = idx
+			partitions = append(partitions, metricsPartition{resourceKey: key, metrics: pmetric.NewMetrics()})
+		}
+		rms.At(i).CopyTo(partitions[idx].metrics.ResourceMetrics().AppendEmpty())
+	}
+	return partitions
+}
+
+// partitionTraces groups the resources by their values of the partitioning
+// attributes, in the order they are first seen. The traces aren't copied
+// without partitioning attributes.
+func partitionTraces(td ptrace.Traces, attributes []string) []tracesPartition {
+	if len(attributes) == 0 {
+		return []tracesPartition{{traces: td}}
+	}
+
+	var partitions []tracesPartition
+	indexes := map[string]int{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		key := getResourceKey(rss.At(i).Resource(), attributes)
+		idx, ok := indexes[key]
+		if !ok {
+			idx = len(partitions)
+			indexes[key] = idx
+			partitions = append(partitions, tracesPartition{resourceKey: key, traces: ptrace.NewTraces()})
+		}
+		rss.At(i).CopyTo(partitions[idx].traces.ResourceSpans().AppendEmpty())
+	}
+	return partitions
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azureblobexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestPartitionLogs(t *testing.T) {
+	ld := generateLogs()
+
+	partitions := partitionLogs(ld, nil)
+	require.Len(t, partitions, 1)
+	assert.Equal(t, "", partitions[0].resourceKey)
+	assert.Equal(t, ld, partitions[0].logs)
+
+	partitions = partitionLogs(ld, []string{"service.name"})
+	require.Len(t, partitions, 2)
+	assert.Equal(t, "service.name=checkout", partitions[0].resourceKey)
+	assert.Equal(t, 2, partitions[0].logs.ResourceLogs().Len())
+	assert.Equal(t, "service.name=cart", partitions[1].resourceKey)
+	assert.Equal(t, 1, partitions[1].logs.ResourceLogs().Len())
+	assert.Equal(t, 3, ld.ResourceLogs().Len())
+}
+
+func TestPartitionMetrics(t *testing.T) {
+	md := generateMetrics()
+
+	partitions := partitionMetrics(md, nil)
+	require.Len(t, partitions, 1)
+	assert.Equal(t, md, partitions[0].metrics)
+
+	partitions = partitionMetrics(md, []string{"service.name", "host.name"})
+	require.Len(t, partitions, 3)
+	assert.Equal(t, "service.name=checkout/host.name=web-1", partitions[0].resourceKey)
+	assert.Equal(t, "service.name=cart/host.name=web-1", partitions[1].resourceKey)
+	assert.Equal(t, "service.name=checkout/host.name=web-2", partitions[2].resourceKey)
+	for _, p := range partitions {
+		assert.Equal(t, 1, p.metrics.ResourceMetrics().Len())
+	}
+}
+
+func TestPartitionTraces(t *testing.T) {
+	td := generateTraces()
+
+	partitions := partitionTraces(td, nil)
+	require.Len(t, partitions, 1)
+	assert.Equal(t, td, partitions[0].traces)
+
+	partitions = partitionTraces(td, []string{"cloud.region"})
+	require.Len(t, partitions, 1)
+	assert.Equal(t, "cloud.region=unknown", partitions[0].resourceKey)
+	assert.Equal(t, 3, partitions[0].traces.ResourceSpans().Len())
+}
+
+// The test data have three resources: checkout on web-1, cart on web-1 and
+// checkout on web-2.
+var testResources = []struct {
+	serviceName string
+	hostName    string
+}{
+	{"checkout", "web-1"},
+	{"cart", "web-1"},
+	{"checkout", "web-2"},
+}
+
+func generateLogs() plog.Logs {
+	ld := plog.NewLogs()
+	for _, attrs := range testResources {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", attrs.serviceName)
+		rl.Resource().Attributes().PutStr("host.name", attrs.hostName)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("order placed on " + attrs.hostName)
+	}
+	return ld
+}
+
+func generateMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for _, attrs := range testResources {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", attrs.serviceName)
+		rm.Resource().Attributes().PutStr("host.name", attrs.hostName)
+		m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("orders")
+		m.SetEmptySum().DataPoints().AppendEmpty().SetIntValue(42)
+	}
+	return md
+}
+
+func generateTraces() ptrace.Traces {
+	td := ptrace.NewTraces()
+	for _, attrs := range testResources {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", attrs.serviceName)
+		rs.Resource().Attributes().PutStr("host.name", attrs.hostName)
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("place order")
+	}
+	return td
+}
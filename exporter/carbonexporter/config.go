@@ -12,6 +12,7 @@ import (
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
 )
 
@@ -31,6 +32,10 @@ type Config struct {
 
 	// ResourceToTelemetrySettings defines configuration for converting resource attributes to metric labels.
 	ResourceToTelemetryConfig resourcetotelemetry.Settings `mapstructure:"resource_to_telemetry_conversion"`
+
+	// Naming defines the normalization of the metric names and tag keys, before they are
+	// sanitized following the Graphite tagged series rules.
+	Naming metricnaming.Config `mapstructure:"naming"`
 }
 
 func (cfg *Config) Validate() error {
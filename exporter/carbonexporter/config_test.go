@@ -17,6 +17,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/carbonexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
 )
 
@@ -62,6 +63,10 @@ func TestLoadConfig(t *testing.T) {
 				ResourceToTelemetryConfig: resourcetotelemetry.Settings{
 					Enabled: true,
 				},
+				Naming: metricnaming.Config{
+					Normalization:     metricnaming.NormalizationPrometheus,
+					AddMetricSuffixes: false,
+				},
 			},
 		},
 	}
@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/multierr"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
 )
 
@@ -23,6 +24,7 @@ func newCarbonExporter(ctx context.Context, cfg *Config, set exporter.Settings)
 	sender := carbonSender{
 		writeTimeout: cfg.Timeout,
 		conns:        newConnPool(cfg.TCPAddrConfig, cfg.Timeout, cfg.MaxIdleConns),
+		sanitizer:    newSanitizer(cfg.Naming),
 	}
 
 	exp, err := exporterhelper.NewMetricsExporter(
@@ -47,10 +49,11 @@ func newCarbonExporter(ctx context.Context, cfg *Config, set exporter.Settings)
 type carbonSender struct {
 	writeTimeout time.Duration
 	conns        connPool
+	sanitizer    *metricnaming.Sanitizer
 }
 
 func (cs *carbonSender) pushMetricsData(_ context.Context, md pmetric.Metrics) error {
-	lines := metricDataToPlaintext(md)
+	lines := metricDataToPlaintext(md, cs.sanitizer)
 
 	// There is no way to do a call equivalent to recvfrom with an empty buffer
 	// to check if the connection was terminated (if the size of the buffer is
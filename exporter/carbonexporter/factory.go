@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/carbonexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
 )
 
 // Defaults for not specified configuration settings.
@@ -37,6 +38,7 @@ func createDefaultConfig() component.Config {
 		TimeoutSettings: exporterhelper.NewDefaultTimeoutSettings(),
 		QueueConfig:     exporterhelper.NewDefaultQueueSettings(),
 		RetryConfig:     configretry.NewDefaultBackOffConfig(),
+		Naming:          metricnaming.NewDefaultConfig(),
 	}
 }
 
@@ -8,9 +8,12 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"unicode"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
 )
 
 const (
@@ -43,6 +46,18 @@ const (
 	infinityCarbonValue = "inf"
 )
 
+// newSanitizer returns a sanitizer following the Graphite 1.1 rules for the metric names and
+// tags: tag keys can't contain ";!^=", and tag values and metric names can't contain ";" nor
+// start with "~". None of them can contain whitespace or control characters, which would break
+// the plaintext lines.
+func newSanitizer(cfg metricnaming.Config) *metricnaming.Sanitizer {
+	return metricnaming.NewSanitizer(cfg, metricnaming.Rules{
+		Name:     sanitizeTagValue,
+		TagKey:   sanitizeTagKey,
+		TagValue: sanitizeTagValue,
+	})
+}
+
 var writerPool = sync.Pool{
 	New: func() any {
 		// Start with a buffer of 1KB.
@@ -59,8 +74,8 @@ var writerPool = sync.Pool{
 //
 //	"<path> <value> <timestamp>"
 //
-// The <path> contains the metric name and its tags and has the following,
-// format:
+// The <path> contains the metric name and its tags, following the Graphite 1.1
+// tagged series format:
 //
 //	<metric_name>[;tag0;...;tagN]
 //
@@ -68,7 +83,9 @@ var writerPool = sync.Pool{
 // or at the end of the path.
 //
 // <tag> is of the form "key=val", where key can contain any char except ";!^=" and
-// val can contain any char except ";~".
+// val can contain any char except ";~". Neither can contain whitespace nor control chars. The metric name, tag keys and tag values
+// are normalized and sanitized by the given sanitizer, and tags with an empty key
+// are dropped.
 //
 // The <value> is the textual representation of the metric value.
 //
@@ -79,7 +96,7 @@ var writerPool = sync.Pool{
 //     a single Carbon metric.
 //   - number of time series successfully converted to carbon.
 //   - number of time series that could not be converted to Carbon.
-func metricDataToPlaintext(md pmetric.Metrics) string {
+func metricDataToPlaintext(md pmetric.Metrics, sanitizer *metricnaming.Sanitizer) string {
 	if md.DataPointCount() == 0 {
 		return ""
 	}
@@ -98,15 +115,16 @@ func metricDataToPlaintext(md pmetric.Metrics) string {
 					// TODO: log error info
 					continue
 				}
+				name := sanitizer.MetricName(metric)
 				switch metric.Type() {
 				case pmetric.MetricTypeGauge:
-					writeNumberDataPoints(buf, metric.Name(), metric.Gauge().DataPoints())
+					writeNumberDataPoints(buf, sanitizer, name, metric.Gauge().DataPoints())
 				case pmetric.MetricTypeSum:
-					writeNumberDataPoints(buf, metric.Name(), metric.Sum().DataPoints())
+					writeNumberDataPoints(buf, sanitizer, name, metric.Sum().DataPoints())
 				case pmetric.MetricTypeHistogram:
-					formatHistogramDataPoints(buf, metric.Name(), metric.Histogram().DataPoints())
+					formatHistogramDataPoints(buf, sanitizer, name, metric.Histogram().DataPoints())
 				case pmetric.MetricTypeSummary:
-					formatSummaryDataPoints(buf, metric.Name(), metric.Summary().DataPoints())
+					formatSummaryDataPoints(buf, sanitizer, name, metric.Summary().DataPoints())
 				}
 			}
 		}
@@ -115,7 +133,7 @@ func metricDataToPlaintext(md pmetric.Metrics) string {
 	return buf.String()
 }
 
-func writeNumberDataPoints(buf *bytes.Buffer, metricName string, dps pmetric.NumberDataPointSlice) {
+func writeNumberDataPoints(buf *bytes.Buffer, sanitizer *metricnaming.Sanitizer, metricName string, dps pmetric.NumberDataPointSlice) {
 	for i := 0; i < dps.Len(); i++ {
 		dp := dps.At(i)
 		var valueStr string
@@ -129,7 +147,7 @@ func writeNumberDataPoints(buf *bytes.Buffer, metricName string, dps pmetric.Num
 		}
 		writeLine(
 			buf,
-			buildPath(metricName, dp.Attributes()),
+			buildPath(sanitizer, metricName, dp.Attributes()),
 			valueStr,
 			formatTimestamp(dp.Timestamp()))
 	}
@@ -151,6 +169,7 @@ func writeNumberDataPoints(buf *bytes.Buffer, metricName string, dps pmetric.Num
 // less than or equal to the upper bound.
 func formatHistogramDataPoints(
 	buf *bytes.Buffer,
+	sanitizer *metricnaming.Sanitizer,
 	metricName string,
 	dps pmetric.HistogramDataPointSlice,
 ) {
@@ -158,7 +177,7 @@ func formatHistogramDataPoints(
 		dp := dps.At(i)
 
 		timestampStr := formatTimestamp(dp.Timestamp())
-		formatCountAndSum(buf, metricName, dp.Attributes(), dp.Count(), dp.Sum(), timestampStr)
+		formatCountAndSum(buf, sanitizer, metricName, dp.Attributes(), dp.Count(), dp.Sum(), timestampStr)
 		if dp.ExplicitBounds().Len() == 0 {
 			continue
 		}
@@ -170,7 +189,7 @@ func formatHistogramDataPoints(
 		}
 		carbonBounds[len(carbonBounds)-1] = infinityCarbonValue
 
-		bucketPath := buildPath(metricName+distributionBucketSuffix, dp.Attributes())
+		bucketPath := buildPath(sanitizer, metricName+distributionBucketSuffix, dp.Attributes())
 		for j := 0; j < dp.BucketCounts().Len(); j++ {
 			writeLine(
 				buf,
@@ -195,6 +214,7 @@ func formatHistogramDataPoints(
 // and will include a tag key "quantile" that specifies the quantile value.
 func formatSummaryDataPoints(
 	buf *bytes.Buffer,
+	sanitizer *metricnaming.Sanitizer,
 	metricName string,
 	dps pmetric.SummaryDataPointSlice,
 ) {
@@ -202,13 +222,13 @@ func formatSummaryDataPoints(
 		dp := dps.At(i)
 
 		timestampStr := formatTimestamp(dp.Timestamp())
-		formatCountAndSum(buf, metricName, dp.Attributes(), dp.Count(), dp.Sum(), timestampStr)
+		formatCountAndSum(buf, sanitizer, metricName, dp.Attributes(), dp.Count(), dp.Sum(), timestampStr)
 
 		if dp.QuantileValues().Len() == 0 {
 			continue
 		}
 
-		quantilePath := buildPath(metricName+summaryQuantileSuffix, dp.Attributes())
+		quantilePath := buildPath(sanitizer, metricName+summaryQuantileSuffix, dp.Attributes())
 		for j := 0; j < dp.QuantileValues().Len(); j++ {
 			writeLine(
 				buf,
@@ -228,6 +248,7 @@ func formatSummaryDataPoints(
 // 2. The total sum will be represented by a metruc with the original "<metricName>".
 func formatCountAndSum(
 	buf *bytes.Buffer,
+	sanitizer *metricnaming.Sanitizer,
 	metricName string,
 	attributes pcommon.Map,
 	count uint64,
@@ -237,19 +258,19 @@ func formatCountAndSum(
 	// Write count and sum metrics.
 	writeLine(
 		buf,
-		buildPath(metricName+countSuffix, attributes),
+		buildPath(sanitizer, metricName+countSuffix, attributes),
 		formatUint64(count),
 		timestampStr)
 
 	writeLine(
 		buf,
-		buildPath(metricName, attributes),
+		buildPath(sanitizer, metricName, attributes),
 		formatFloatForValue(sum),
 		timestampStr)
 }
 
 // buildPath is used to build the <metric_path> per description above.
-func buildPath(name string, attributes pcommon.Map) string {
+func buildPath(sanitizer *metricnaming.Sanitizer, name string, attributes pcommon.Map) string {
 	if attributes.Len() == 0 {
 		return name
 	}
@@ -260,12 +281,16 @@ func buildPath(name string, attributes pcommon.Map) string {
 
 	buf.WriteString(name)
 	attributes.Range(func(k string, v pcommon.Value) bool {
-		value := v.AsString()
+		key := sanitizer.TagKey(k)
+		if key == "" {
+			return true
+		}
+		value := sanitizer.TagValue(v.AsString())
 		if value == "" {
 			value = tagValueEmptyPlaceholder
 		}
 		buf.WriteString(tagPrefix)
-		buf.WriteString(sanitizeTagKey(k))
+		buf.WriteString(key)
 		buf.WriteString(tagKeyValueSeparator)
 		buf.WriteString(value)
 		return true
@@ -286,14 +311,14 @@ func writeLine(buf *bytes.Buffer, path, value, timestamp string) {
 }
 
 // sanitizeTagKey removes any invalid character from the tag key, the invalid
-// characters are ";!^=".
+// characters are ";!^=", whitespace and control characters.
 func sanitizeTagKey(key string) string {
 	mapRune := func(r rune) rune {
 		switch r {
 		case ';', '!', '^', '=':
 			return sanitizedRune
 		default:
-			return r
+			return sanitizeSpaceOrControl(r)
 		}
 	}
 
@@ -301,20 +326,29 @@ func sanitizeTagKey(key string) string {
 }
 
 // sanitizeTagValue removes any invalid character from the tag value, the invalid
-// characters are ";~".
+// characters are ";~", whitespace and control characters.
 func sanitizeTagValue(value string) string {
 	mapRune := func(r rune) rune {
 		switch r {
 		case ';', '~':
 			return sanitizedRune
 		default:
-			return r
+			return sanitizeSpaceOrControl(r)
 		}
 	}
 
 	return strings.Map(mapRune, value)
 }
 
+// sanitizeSpaceOrControl replaces whitespace and control characters, which separate the fields
+// and the lines of the plaintext protocol.
+func sanitizeSpaceOrControl(r rune) rune {
+	if unicode.IsSpace(r) || unicode.IsControl(r) {
+		return sanitizedRune
+	}
+	return r
+}
+
 // Formats a float64 per Prometheus label value. This is an attempt to keep other
 // the label values with different formats of metrics.
 func formatFloatForLabel(f float64) string {
@@ -12,6 +12,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
 )
 
 func TestSanitizeTagKey(t *testing.T) {
@@ -22,14 +24,19 @@ func TestSanitizeTagKey(t *testing.T) {
 	}{
 		{
 			name: "no_changes",
-			key:  "a valid tag key",
-			want: "a valid tag key",
+			key:  "a.valid-tag_key",
+			want: "a.valid-tag_key",
 		},
 		{
 			name: "remove_tag_set",
 			key:  "a" + tagKeyValueSeparator + "c",
 			want: "a" + string(sanitizedRune) + "c",
 		},
+		{
+			name: "replace_whitespace",
+			key:  "a b\tc",
+			want: "a" + string(sanitizedRune) + "b" + string(sanitizedRune) + "c",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -47,8 +54,13 @@ func TestSanitizeTagValue(t *testing.T) {
 	}{
 		{
 			name:  "no_changes",
-			value: "a valid tag value",
-			want:  "a valid tag value",
+			value: "a.valid-tag_value",
+			want:  "a.valid-tag_value",
+		},
+		{
+			name:  "replace_whitespace_and_control",
+			value: "a b\nc\x00",
+			want:  "a" + string(sanitizedRune) + "b" + string(sanitizedRune) + "c" + string(sanitizedRune),
 		},
 		{
 			name:  "replace_tilde",
@@ -103,10 +115,32 @@ func TestBuildPath(t *testing.T) {
 			}(),
 			want: "int_value;k=1",
 		},
+		{
+			name: "sanitized_tags",
+			attributes: func() pcommon.Map {
+				attr := pcommon.NewMap()
+				attr.PutStr("k=0", "~v;0")
+				attr.PutStr("", "dropped")
+				return attr
+			}(),
+			want: "sanitized_tags;k_0=_v_0",
+		},
+		{
+			name: "my metric",
+			attributes: func() pcommon.Map {
+				attr := pcommon.NewMap()
+				attr.PutStr("user agent", "curl 8.0\nx")
+				return attr
+			}(),
+			want: "my_metric;user_agent=curl_8.0_x",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildPath(tt.name, tt.attributes)
+			sanitizer := newSanitizer(metricnaming.NewDefaultConfig())
+			metric := pmetric.NewMetric()
+			metric.SetName(tt.name)
+			got := buildPath(sanitizer, sanitizer.MetricName(metric), tt.attributes)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -270,7 +304,7 @@ func TestToPlaintext(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotLines := metricDataToPlaintext(tt.metricsDataFn())
+			gotLines := metricDataToPlaintext(tt.metricsDataFn(), newSanitizer(metricnaming.NewDefaultConfig()))
 			got := strings.Split(gotLines, "\n")
 			got = got[:len(got)-1]
 			assert.Len(t, got, len(tt.wantLines)+tt.wantExtraLinesCount)
@@ -279,6 +313,43 @@ func TestToPlaintext(t *testing.T) {
 	}
 }
 
+func TestToPlaintextNormalization(t *testing.T) {
+	md := pmetric.NewMetrics()
+	ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	m := ms.AppendEmpty()
+	m.SetName("http.server.requests")
+	m.SetUnit("1")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1574092046, 0)))
+	dp.SetIntValue(3)
+	dp.Attributes().PutStr("http.route", "/users;list")
+
+	tests := []struct {
+		name   string
+		config metricnaming.Config
+		want   string
+	}{
+		{
+			name:   "none",
+			config: metricnaming.NewDefaultConfig(),
+			want:   "http.server.requests;http.route=/users_list 3 1574092046\n",
+		},
+		{
+			name:   "prometheus",
+			config: metricnaming.Config{Normalization: metricnaming.NormalizationPrometheus, AddMetricSuffixes: true},
+			want:   "http_server_requests_total;http_route=/users_list 3 1574092046\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, metricDataToPlaintext(md, newSanitizer(tt.config)))
+		})
+	}
+}
+
 func expectedDistributionLines(
 	metricName string,
 	tags string,
@@ -328,6 +399,6 @@ func BenchmarkConsumeMetricsDefault(b *testing.B) {
 	b.ResetTimer()
 	b.ReportAllocs()
 	for n := 0; n < b.N; n++ {
-		assert.Len(b, metricDataToPlaintext(md), 62)
+		assert.Len(b, metricDataToPlaintext(md, newSanitizer(metricnaming.NewDefaultConfig())), 62)
 	}
 }
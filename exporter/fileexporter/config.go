@@ -38,8 +38,12 @@ type Config struct {
 	// Options:
 	// - json[default]:  OTLP json bytes.
 	// - proto:  OTLP binary protobuf bytes.
+	// - parquet:  flattened rows in parquet files, one file per signal type and metric type.
 	FormatType string `mapstructure:"format"`
 
+	// Parquet defines the settings of the parquet files written when FormatType is parquet.
+	Parquet *Parquet `mapstructure:"parquet"`
+
 	// Encoding defines the encoding of the telemetry data.
 	// If specified, it overrides `FormatType` and applies an encoding extension.
 	Encoding *component.ID `mapstructure:"encoding"`
@@ -54,6 +58,10 @@ type Config struct {
 
 	// GroupBy enables writing to separate files based on a resource attribute.
 	GroupBy *GroupBy `mapstructure:"group_by"`
+
+	// Partition enables writing to separate files based on a path template
+	// rendered from the time, resource attributes and signal type.
+	Partition *Partition `mapstructure:"partition"`
 }
 
 // Rotation an option to rolling log files
@@ -79,6 +87,17 @@ type Rotation struct {
 	LocalTime bool `mapstructure:"localtime"`
 }
 
+// Parquet defines the settings of parquet files.
+type Parquet struct {
+	// RowGroupSize is the maximum number of rows of a row group. Rows are buffered in
+	// memory until the row group is complete. The default is 10000.
+	RowGroupSize int64 `mapstructure:"row_group_size"`
+
+	// Compression is the codec used to compress the pages of the files.
+	// Options: snappy[default], zstd, gzip, lz4 and none.
+	Compression string `mapstructure:"compression"`
+}
+
 type GroupBy struct {
 	// Enables group_by. When group_by is enabled, rotation setting is ignored.  Default is false.
 	Enabled bool `mapstructure:"enabled"`
@@ -94,6 +113,38 @@ type GroupBy struct {
 	MaxOpenFiles int `mapstructure:"max_open_files"`
 }
 
+type Partition struct {
+	// Enables partitioning. When partitioning is enabled, Path is a template supporting
+	// strftime-style time verbs, {resource:<name>} and {signal} placeholders, and the
+	// rotation setting is ignored. Default is false.
+	Enabled bool `mapstructure:"enabled"`
+
+	// RolloverInterval starts a new file at every multiple of the interval, in addition
+	// to the new files started whenever the time verbs of the path render differently.
+	// The default is to only roll over when the path changes.
+	RolloverInterval time.Duration `mapstructure:"rollover_interval"`
+
+	// MaxMegabytes is the maximum size in megabytes of a file before a new one is started.
+	// The default is not to roll over based on the size.
+	MaxMegabytes int `mapstructure:"max_megabytes"`
+
+	// MaxOpenFiles specifies the maximum number of open file descriptors for the output files.
+	// The default is 100.
+	MaxOpenFiles int `mapstructure:"max_open_files"`
+
+	// LocalTime determines if the time used for rendering the path is the computer's
+	// local time. The default is to use UTC time.
+	LocalTime bool `mapstructure:"localtime"`
+
+	// Finalize writes files with the PendingSuffix appended to their path, and atomically
+	// renames them to their final path once they are complete. Default is false.
+	Finalize bool `mapstructure:"finalize"`
+
+	// PendingSuffix is appended to the path of files being written when Finalize is enabled.
+	// Default is ".pending".
+	PendingSuffix string `mapstructure:"pending_suffix"`
+}
+
 var _ component.Config = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
@@ -107,7 +158,7 @@ func (cfg *Config) Validate() error {
 	if cfg.Append && cfg.Rotation != nil {
 		return fmt.Errorf("append and rotation enabled at the same time is not supported")
 	}
-	if cfg.FormatType != formatTypeJSON && cfg.FormatType != formatTypeProto {
+	if cfg.FormatType != formatTypeJSON && cfg.FormatType != formatTypeProto && cfg.FormatType != formatTypeParquet {
 		return errors.New("format type is not supported")
 	}
 	if cfg.Compression != "" && cfg.Compression != compressionZSTD {
@@ -117,6 +168,27 @@ func (cfg *Config) Validate() error {
 		return errors.New("flush_interval must be larger than zero")
 	}
 
+	if cfg.FormatType == formatTypeParquet {
+		if cfg.Encoding != nil {
+			return errors.New("encoding and parquet format at the same time is not supported")
+		}
+		if cfg.Compression != "" {
+			return errors.New("compression is not supported by the parquet format, use parquet::compression instead")
+		}
+		if cfg.Append {
+			return errors.New("append and parquet format at the same time is not supported")
+		}
+		if cfg.Parquet == nil {
+			return errors.New("parquet settings must be set when the format is parquet")
+		}
+		if cfg.Parquet.RowGroupSize <= 0 {
+			return errors.New("row_group_size must be larger than zero")
+		}
+		if _, ok := parquetCompressionCodecs[cfg.Parquet.Compression]; !ok {
+			return errors.New("parquet compression is not supported")
+		}
+	}
+
 	if cfg.GroupBy != nil && cfg.GroupBy.Enabled {
 		pathParts := strings.Split(cfg.Path, "*")
 		if len(pathParts) != 2 {
@@ -132,6 +204,30 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
+	if cfg.Partition != nil && cfg.Partition.Enabled {
+		if cfg.GroupBy != nil && cfg.GroupBy.Enabled {
+			return errors.New("group_by and partition enabled at the same time is not supported")
+		}
+		if cfg.Append {
+			return errors.New("append and partition enabled at the same time is not supported")
+		}
+		if _, err := parsePathTemplate(cfg.Path); err != nil {
+			return err
+		}
+		if cfg.Partition.RolloverInterval < 0 {
+			return errors.New("rollover_interval must not be negative")
+		}
+		if cfg.Partition.MaxMegabytes < 0 {
+			return errors.New("max_megabytes must not be negative")
+		}
+		if cfg.Partition.MaxOpenFiles <= 0 {
+			return errors.New("max_open_files must be larger than zero")
+		}
+		if cfg.Partition.Finalize && cfg.Partition.PendingSuffix == "" {
+			return errors.New("pending_suffix must not be empty when finalize is enabled")
+		}
+	}
+
 	return nil
 }
 
@@ -37,12 +37,20 @@ func TestLoadConfig(t *testing.T) {
 					MaxBackups:   3,
 					LocalTime:    true,
 				},
-				FormatType:    formatTypeJSON,
+				FormatType: formatTypeJSON,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
 				FlushInterval: time.Second,
 				GroupBy: &GroupBy{
 					MaxOpenFiles:      defaultMaxOpenFiles,
 					ResourceAttribute: defaultResourceAttribute,
 				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
 			},
 		},
 		{
@@ -55,13 +63,21 @@ func TestLoadConfig(t *testing.T) {
 					MaxBackups:   3,
 					LocalTime:    true,
 				},
-				FormatType:    formatTypeProto,
+				FormatType: formatTypeProto,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
 				Compression:   compressionZSTD,
 				FlushInterval: time.Second,
 				GroupBy: &GroupBy{
 					MaxOpenFiles:      defaultMaxOpenFiles,
 					ResourceAttribute: defaultResourceAttribute,
 				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
 			},
 		},
 		{
@@ -69,6 +85,10 @@ func TestLoadConfig(t *testing.T) {
 			expected: &Config{
 				Path:       "./foo",
 				FormatType: formatTypeJSON,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
 				Rotation: &Rotation{
 					MaxBackups: defaultMaxBackups,
 				},
@@ -77,6 +97,10 @@ func TestLoadConfig(t *testing.T) {
 					MaxOpenFiles:      defaultMaxOpenFiles,
 					ResourceAttribute: defaultResourceAttribute,
 				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
 			},
 		},
 		{
@@ -87,12 +111,20 @@ func TestLoadConfig(t *testing.T) {
 					MaxMegabytes: 1234,
 					MaxBackups:   defaultMaxBackups,
 				},
-				FormatType:    formatTypeJSON,
+				FormatType: formatTypeJSON,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
 				FlushInterval: time.Second,
 				GroupBy: &GroupBy{
 					MaxOpenFiles:      defaultMaxOpenFiles,
 					ResourceAttribute: defaultResourceAttribute,
 				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
 			},
 		},
 		{
@@ -109,10 +141,18 @@ func TestLoadConfig(t *testing.T) {
 				Path:          "./flushed",
 				FlushInterval: 5,
 				FormatType:    formatTypeJSON,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
 				GroupBy: &GroupBy{
 					MaxOpenFiles:      defaultMaxOpenFiles,
 					ResourceAttribute: defaultResourceAttribute,
 				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
 			},
 		},
 		{
@@ -121,10 +161,18 @@ func TestLoadConfig(t *testing.T) {
 				Path:          "./flushed",
 				FlushInterval: 5 * time.Second,
 				FormatType:    formatTypeJSON,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
 				GroupBy: &GroupBy{
 					MaxOpenFiles:      defaultMaxOpenFiles,
 					ResourceAttribute: defaultResourceAttribute,
 				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
 			},
 		},
 		{
@@ -133,10 +181,18 @@ func TestLoadConfig(t *testing.T) {
 				Path:          "./flushed",
 				FlushInterval: 500 * time.Millisecond,
 				FormatType:    formatTypeJSON,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
 				GroupBy: &GroupBy{
 					MaxOpenFiles:      defaultMaxOpenFiles,
 					ResourceAttribute: defaultResourceAttribute,
 				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
 			},
 		},
 		{
@@ -153,11 +209,19 @@ func TestLoadConfig(t *testing.T) {
 				Path:          "./group_by/*.json",
 				FlushInterval: time.Second,
 				FormatType:    formatTypeJSON,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
 				GroupBy: &GroupBy{
 					Enabled:           true,
 					MaxOpenFiles:      10,
 					ResourceAttribute: "dummy",
 				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
 			},
 		},
 		{
@@ -166,11 +230,19 @@ func TestLoadConfig(t *testing.T) {
 				Path:          "./group_by/*.json",
 				FlushInterval: time.Second,
 				FormatType:    formatTypeJSON,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
 				GroupBy: &GroupBy{
 					Enabled:           true,
 					MaxOpenFiles:      defaultMaxOpenFiles,
 					ResourceAttribute: defaultResourceAttribute,
 				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
 			},
 		},
 		{
@@ -185,6 +257,87 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(metadata.Type, "group_by_empty_resource_attribute"),
 			errorMessage: "resource_attribute must not be empty when group_by is enabled",
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "partition"),
+			expected: &Config{
+				Path:       "./data/%Y/%m/%d/%H/{resource:service.name}/{signal}.json",
+				FormatType: formatTypeJSON,
+				Parquet: &Parquet{
+					RowGroupSize: defaultParquetRowGroupSize,
+					Compression:  parquetCompressionSnappy,
+				},
+				FlushInterval: time.Second,
+				GroupBy: &GroupBy{
+					MaxOpenFiles:      defaultMaxOpenFiles,
+					ResourceAttribute: defaultResourceAttribute,
+				},
+				Partition: &Partition{
+					Enabled:          true,
+					RolloverInterval: 15 * time.Minute,
+					MaxMegabytes:     50,
+					MaxOpenFiles:     20,
+					LocalTime:        true,
+					Finalize:         true,
+					PendingSuffix:    ".tmp",
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "partition_invalid_verb"),
+			errorMessage: "unsupported time verb %Q in path",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "partition_invalid_placeholder"),
+			errorMessage: "unsupported placeholder {host} in path",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "partition_with_group_by"),
+			errorMessage: "group_by and partition enabled at the same time is not supported",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "partition_with_append"),
+			errorMessage: "append and partition enabled at the same time is not supported",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "partition_empty_pending_suffix"),
+			errorMessage: "pending_suffix must not be empty when finalize is enabled",
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "parquet"),
+			expected: &Config{
+				Path:       "./data/telemetry.parquet",
+				FormatType: formatTypeParquet,
+				Parquet: &Parquet{
+					RowGroupSize: 500,
+					Compression:  parquetCompressionZSTD,
+				},
+				FlushInterval: time.Second,
+				GroupBy: &GroupBy{
+					MaxOpenFiles:      defaultMaxOpenFiles,
+					ResourceAttribute: defaultResourceAttribute,
+				},
+				Partition: &Partition{
+					MaxOpenFiles:  defaultMaxOpenFiles,
+					PendingSuffix: defaultPendingSuffix,
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "parquet_with_compression"),
+			errorMessage: "compression is not supported by the parquet format, use parquet::compression instead",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "parquet_with_append"),
+			errorMessage: "append and parquet format at the same time is not supported",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "parquet_invalid_compression"),
+			errorMessage: "parquet compression is not supported",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "parquet_invalid_row_group_size"),
+			errorMessage: "row_group_size must be larger than zero",
+		},
 	}
 
 	for _, tt := range tests {
@@ -28,8 +28,9 @@ const (
 	defaultMaxBackups = 100
 
 	// the format of encoded telemetry data
-	formatTypeJSON  = "json"
-	formatTypeProto = "proto"
+	formatTypeJSON    = "json"
+	formatTypeProto   = "proto"
+	formatTypeParquet = "parquet"
 
 	// the type of compression codec
 	compressionZSTD = "zstd"
@@ -37,6 +38,8 @@ const (
 	defaultMaxOpenFiles = 100
 
 	defaultResourceAttribute = "fileexporter.path_segment"
+
+	defaultPendingSuffix = ".pending"
 )
 
 type FileExporter interface {
@@ -60,10 +63,18 @@ func createDefaultConfig() component.Config {
 	return &Config{
 		FormatType: formatTypeJSON,
 		Rotation:   &Rotation{MaxBackups: defaultMaxBackups},
+		Parquet: &Parquet{
+			RowGroupSize: defaultParquetRowGroupSize,
+			Compression:  parquetCompressionSnappy,
+		},
 		GroupBy: &GroupBy{
 			ResourceAttribute: defaultResourceAttribute,
 			MaxOpenFiles:      defaultMaxOpenFiles,
 		},
+		Partition: &Partition{
+			MaxOpenFiles:  defaultMaxOpenFiles,
+			PendingSuffix: defaultPendingSuffix,
+		},
 	}
 }
 
@@ -133,6 +144,14 @@ func getOrCreateFileExporter(cfg component.Config, logger *zap.Logger) FileExpor
 }
 
 func newFileExporter(conf *Config, logger *zap.Logger) FileExporter {
+	if conf.Partition != nil && conf.Partition.Enabled {
+		return &partitioningFileExporter{
+			conf:   conf,
+			logger: logger,
+			now:    time.Now,
+		}
+	}
+
 	if conf.GroupBy == nil || !conf.GroupBy.Enabled {
 		return &fileExporter{
 			conf: conf,
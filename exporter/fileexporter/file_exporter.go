@@ -5,6 +5,8 @@ package fileexporter // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"context"
+	"errors"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -17,9 +19,16 @@ type fileExporter struct {
 	conf       *Config
 	marshaller *marshaller
 	writer     *fileWriter
+
+	// parquetMutex guards parquetWriters, the writers of the files of each parquet table.
+	parquetMutex   sync.Mutex
+	parquetWriters map[*parquetTable]*fileWriter
 }
 
 func (e *fileExporter) consumeTraces(_ context.Context, td ptrace.Traces) error {
+	if e.parquetWriters != nil {
+		return e.exportParquet(tracesToParquet(td))
+	}
 	buf, err := e.marshaller.marshalTraces(td)
 	if err != nil {
 		return err
@@ -28,6 +37,9 @@ func (e *fileExporter) consumeTraces(_ context.Context, td ptrace.Traces) error
 }
 
 func (e *fileExporter) consumeMetrics(_ context.Context, md pmetric.Metrics) error {
+	if e.parquetWriters != nil {
+		return e.exportParquet(metricsToParquet(md))
+	}
 	buf, err := e.marshaller.marshalMetrics(md)
 	if err != nil {
 		return err
@@ -36,6 +48,9 @@ func (e *fileExporter) consumeMetrics(_ context.Context, md pmetric.Metrics) err
 }
 
 func (e *fileExporter) consumeLogs(_ context.Context, ld plog.Logs) error {
+	if e.parquetWriters != nil {
+		return e.exportParquet(logsToParquet(ld))
+	}
 	buf, err := e.marshaller.marshalLogs(ld)
 	if err != nil {
 		return err
@@ -43,6 +58,37 @@ func (e *fileExporter) consumeLogs(_ context.Context, ld plog.Logs) error {
 	return e.writer.export(buf)
 }
 
+func (e *fileExporter) exportParquet(batches []parquetBatch) error {
+	var errs error
+	for _, batch := range batches {
+		writer, err := e.parquetWriter(batch.table)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		errs = errors.Join(errs, writer.exportRows(batch.table, batch.rows))
+	}
+	return errs
+}
+
+// parquetWriter returns the writer of the table, creating the file on the first write
+// so that no file is written for tables without rows.
+func (e *fileExporter) parquetWriter(table *parquetTable) (*fileWriter, error) {
+	e.parquetMutex.Lock()
+	defer e.parquetMutex.Unlock()
+
+	if writer, ok := e.parquetWriters[table]; ok {
+		return writer, nil
+	}
+	writer, err := newParquetFileWriter(parquetTablePath(e.conf.Path, table), e.conf, e.conf.Rotation)
+	if err != nil {
+		return nil, err
+	}
+	writer.start()
+	e.parquetWriters[table] = writer
+	return writer, nil
+}
+
 // Start starts the flush timer if set.
 func (e *fileExporter) Start(_ context.Context, host component.Host) error {
 	var err error
@@ -50,6 +96,10 @@ func (e *fileExporter) Start(_ context.Context, host component.Host) error {
 	if err != nil {
 		return err
 	}
+	if e.conf.FormatType == formatTypeParquet {
+		e.parquetWriters = make(map[*parquetTable]*fileWriter)
+		return nil
+	}
 	export := buildExportFunc(e.conf)
 
 	e.writer, err = newFileWriter(e.conf.Path, e.conf.Append, e.conf.Rotation, e.conf.FlushInterval, export)
@@ -63,6 +113,9 @@ func (e *fileExporter) Start(_ context.Context, host component.Host) error {
 // Shutdown stops the exporter and is invoked during shutdown.
 // It stops the flush ticker if set.
 func (e *fileExporter) Shutdown(context.Context) error {
+	if e.parquetWriters != nil {
+		return e.shutdownParquetWriters()
+	}
 	if e.writer == nil {
 		return nil
 	}
@@ -70,3 +123,15 @@ func (e *fileExporter) Shutdown(context.Context) error {
 	e.writer = nil
 	return w.shutdown()
 }
+
+func (e *fileExporter) shutdownParquetWriters() error {
+	e.parquetMutex.Lock()
+	defer e.parquetMutex.Unlock()
+
+	var errs error
+	for table, writer := range e.parquetWriters {
+		errs = errors.Join(errs, writer.shutdown())
+		delete(e.parquetWriters, table)
+	}
+	return errs
+}
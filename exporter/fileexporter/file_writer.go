@@ -19,6 +19,8 @@ type fileWriter struct {
 	mutex sync.Mutex
 
 	exporter exportFunc
+	// parquet encodes rows when writing parquet files, nil otherwise.
+	parquet *parquetEncoder
 
 	flushInterval time.Duration
 	flushTicker   *time.Ticker
@@ -103,6 +105,16 @@ func (w *fileWriter) shutdown() error {
 		close(w.stopTicker)
 		w.mutex.Unlock()
 	}
+	if w.parquet != nil {
+		// the footer must be written before closing the file for it to be a valid parquet file.
+		w.mutex.Lock()
+		err := w.parquet.close()
+		w.mutex.Unlock()
+		if err != nil {
+			_ = w.file.Close()
+			return err
+		}
+	}
 	return w.file.Close()
 }
 
@@ -55,6 +55,11 @@ func (e *groupingFileExporter) consumeTraces(ctx context.Context, td ptrace.Trac
 			rSpans.CopyTo(traces.ResourceSpans().AppendEmpty())
 		}
 
+		if e.conf.FormatType == formatTypeParquet {
+			errs = errors.Join(errs, e.writeParquet(pathSegment, tracesToParquet(traces)))
+			continue
+		}
+
 		buf, err := e.marshaller.marshalTraces(traces)
 		if err != nil {
 			errs = errors.Join(errs, err)
@@ -93,6 +98,11 @@ func (e *groupingFileExporter) consumeMetrics(ctx context.Context, md pmetric.Me
 			rMetrics.CopyTo(metrics.ResourceMetrics().AppendEmpty())
 		}
 
+		if e.conf.FormatType == formatTypeParquet {
+			errs = errors.Join(errs, e.writeParquet(pathSegment, metricsToParquet(metrics)))
+			continue
+		}
+
 		buf, err := e.marshaller.marshalMetrics(metrics)
 		if err != nil {
 			errs = errors.Join(errs, err)
@@ -131,6 +141,11 @@ func (e *groupingFileExporter) consumeLogs(ctx context.Context, ld plog.Logs) er
 			rlogs.CopyTo(logs.ResourceLogs().AppendEmpty())
 		}
 
+		if e.conf.FormatType == formatTypeParquet {
+			errs = errors.Join(errs, e.writeParquet(pathSegment, logsToParquet(logs)))
+			continue
+		}
+
 		buf, err := e.marshaller.marshalLogs(logs)
 		if err != nil {
 			errs = errors.Join(errs, err)
@@ -164,9 +179,25 @@ func (e *groupingFileExporter) write(_ context.Context, pathSegment string, buf
 	return nil
 }
 
+// writeParquet writes the rows of each table to a separate file of the group.
+func (e *groupingFileExporter) writeParquet(pathSegment string, batches []parquetBatch) error {
+	var errs error
+	for _, batch := range batches {
+		writer, err := e.getWriterForPath(parquetTablePath(e.fullPath(pathSegment), batch.table))
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		errs = errors.Join(errs, writer.exportRows(batch.table, batch.rows))
+	}
+	return errs
+}
+
 func (e *groupingFileExporter) getWriter(pathSegment string) (*fileWriter, error) {
-	fullPath := e.fullPath(pathSegment)
+	return e.getWriterForPath(e.fullPath(pathSegment))
+}
 
+func (e *groupingFileExporter) getWriterForPath(fullPath string) (*fileWriter, error) {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
@@ -259,6 +290,15 @@ func (e *groupingFileExporter) Start(_ context.Context, host component.Host) err
 	e.pathSuffix = pathParts[1]
 	e.maxOpenFiles = e.conf.GroupBy.MaxOpenFiles
 	e.newFileWriter = func(path string) (*fileWriter, error) {
+		if e.conf.FormatType == formatTypeParquet {
+			// The file is finalized when the writer is evicted, so the rows written to the group
+			// afterwards go to a new file.
+			path, err := unusedPath(path)
+			if err != nil {
+				return nil, err
+			}
+			return newParquetFileWriter(path, e.conf, nil)
+		}
 		return newFileWriter(path, e.conf.Append, nil, e.conf.FlushInterval, export)
 	}
 
@@ -287,3 +327,17 @@ func (e *groupingFileExporter) Shutdown(context.Context) error {
 
 	return nil
 }
+
+// unusedPath returns the first path of the sequence starting with path which doesn't exist.
+func unusedPath(path string) (string, error) {
+	for n := 0; ; n++ {
+		p := sequencedPath(path, n)
+		exists, err := fileExists(p)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return p, nil
+		}
+	}
+}
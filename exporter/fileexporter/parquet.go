@@ -0,0 +1,443 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"github.com/parquet-go/parquet-go"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+)
+
+// parquetTable is a flattened schema written to its own parquet files. Telemetry data is
+// flattened to one row per log record, span or metric data point.
+type parquetTable struct {
+	name   string
+	schema *parquet.Schema
+}
+
+func newParquetTable(name string, model any) *parquetTable {
+	return &parquetTable{name: name, schema: parquet.SchemaOf(model)}
+}
+
+var (
+	parquetLogsTable                 = newParquetTable("logs", logRow{})
+	parquetSpansTable                = newParquetTable("spans", spanRow{})
+	parquetGaugeTable                = newParquetTable("gauge", gaugeRow{})
+	parquetSumTable                  = newParquetTable("sum", sumRow{})
+	parquetHistogramTable            = newParquetTable("histogram", histogramRow{})
+	parquetExponentialHistogramTable = newParquetTable("exponential_histogram", exponentialHistogramRow{})
+	parquetSummaryTable              = newParquetTable("summary", summaryRow{})
+)
+
+// parquetBatch holds rows of a table.
+type parquetBatch struct {
+	table *parquetTable
+	rows  []any
+}
+
+// resourceColumns are the resource and scope columns hoisted into every row.
+type resourceColumns struct {
+	ServiceName        string            `parquet:"service_name,dict"`
+	ResourceAttributes map[string]string `parquet:"resource_attributes"`
+	ResourceSchemaURL  string            `parquet:"resource_schema_url,dict"`
+	ScopeName          string            `parquet:"scope_name,dict"`
+	ScopeVersion       string            `parquet:"scope_version,dict"`
+	ScopeAttributes    map[string]string `parquet:"scope_attributes"`
+	ScopeSchemaURL     string            `parquet:"scope_schema_url,dict"`
+}
+
+type logRow struct {
+	resourceColumns
+	TimeUnixNano           int64             `parquet:"time_unix_nano,timestamp(nanosecond)"`
+	ObservedTimeUnixNano   int64             `parquet:"observed_time_unix_nano,timestamp(nanosecond)"`
+	SeverityNumber         int32             `parquet:"severity_number"`
+	SeverityText           string            `parquet:"severity_text,dict"`
+	Body                   string            `parquet:"body"`
+	Attributes             map[string]string `parquet:"attributes"`
+	DroppedAttributesCount uint32            `parquet:"dropped_attributes_count"`
+	Flags                  uint32            `parquet:"flags"`
+	TraceID                string            `parquet:"trace_id"`
+	SpanID                 string            `parquet:"span_id"`
+}
+
+type spanEvent struct {
+	TimeUnixNano           int64             `parquet:"time_unix_nano,timestamp(nanosecond)"`
+	Name                   string            `parquet:"name"`
+	Attributes             map[string]string `parquet:"attributes"`
+	DroppedAttributesCount uint32            `parquet:"dropped_attributes_count"`
+}
+
+type spanLink struct {
+	TraceID                string            `parquet:"trace_id"`
+	SpanID                 string            `parquet:"span_id"`
+	TraceState             string            `parquet:"trace_state"`
+	Attributes             map[string]string `parquet:"attributes"`
+	DroppedAttributesCount uint32            `parquet:"dropped_attributes_count"`
+}
+
+type spanRow struct {
+	resourceColumns
+	TraceID                string            `parquet:"trace_id"`
+	SpanID                 string            `parquet:"span_id"`
+	TraceState             string            `parquet:"trace_state"`
+	ParentSpanID           string            `parquet:"parent_span_id"`
+	Flags                  uint32            `parquet:"flags"`
+	Name                   string            `parquet:"name,dict"`
+	Kind                   string            `parquet:"kind,dict"`
+	StartTimeUnixNano      int64             `parquet:"start_time_unix_nano,timestamp(nanosecond)"`
+	EndTimeUnixNano        int64             `parquet:"end_time_unix_nano,timestamp(nanosecond)"`
+	DurationNano           int64             `parquet:"duration_nano"`
+	Attributes             map[string]string `parquet:"attributes"`
+	DroppedAttributesCount uint32            `parquet:"dropped_attributes_count"`
+	Events                 []spanEvent       `parquet:"events,list"`
+	DroppedEventsCount     uint32            `parquet:"dropped_events_count"`
+	Links                  []spanLink        `parquet:"links,list"`
+	DroppedLinksCount      uint32            `parquet:"dropped_links_count"`
+	StatusCode             string            `parquet:"status_code,dict"`
+	StatusMessage          string            `parquet:"status_message"`
+}
+
+// dataPointColumns are the metric and data point columns shared by all metric types.
+type dataPointColumns struct {
+	MetricName        string            `parquet:"metric_name,dict"`
+	MetricDescription string            `parquet:"metric_description,dict"`
+	MetricUnit        string            `parquet:"metric_unit,dict"`
+	Attributes        map[string]string `parquet:"attributes"`
+	StartTimeUnixNano int64             `parquet:"start_time_unix_nano,timestamp(nanosecond)"`
+	TimeUnixNano      int64             `parquet:"time_unix_nano,timestamp(nanosecond)"`
+	Flags             uint32            `parquet:"flags"`
+}
+
+type gaugeRow struct {
+	resourceColumns
+	dataPointColumns
+	ValueDouble *float64 `parquet:"value_double,optional"`
+	ValueInt    *int64   `parquet:"value_int,optional"`
+}
+
+type sumRow struct {
+	resourceColumns
+	dataPointColumns
+	ValueDouble            *float64 `parquet:"value_double,optional"`
+	ValueInt               *int64   `parquet:"value_int,optional"`
+	AggregationTemporality string   `parquet:"aggregation_temporality,dict"`
+	IsMonotonic            bool     `parquet:"is_monotonic"`
+}
+
+type histogramRow struct {
+	resourceColumns
+	dataPointColumns
+	Count                  uint64    `parquet:"count"`
+	Sum                    *float64  `parquet:"sum,optional"`
+	Min                    *float64  `parquet:"min,optional"`
+	Max                    *float64  `parquet:"max,optional"`
+	BucketCounts           []uint64  `parquet:"bucket_counts,list"`
+	ExplicitBounds         []float64 `parquet:"explicit_bounds,list"`
+	AggregationTemporality string    `parquet:"aggregation_temporality,dict"`
+}
+
+type exponentialHistogramRow struct {
+	resourceColumns
+	dataPointColumns
+	Count                  uint64   `parquet:"count"`
+	Sum                    *float64 `parquet:"sum,optional"`
+	Min                    *float64 `parquet:"min,optional"`
+	Max                    *float64 `parquet:"max,optional"`
+	Scale                  int32    `parquet:"scale"`
+	ZeroCount              uint64   `parquet:"zero_count"`
+	ZeroThreshold          float64  `parquet:"zero_threshold"`
+	PositiveOffset         int32    `parquet:"positive_offset"`
+	PositiveBucketCounts   []uint64 `parquet:"positive_bucket_counts,list"`
+	NegativeOffset         int32    `parquet:"negative_offset"`
+	NegativeBucketCounts   []uint64 `parquet:"negative_bucket_counts,list"`
+	AggregationTemporality string   `parquet:"aggregation_temporality,dict"`
+}
+
+type quantileValue struct {
+	Quantile float64 `parquet:"quantile"`
+	Value    float64 `parquet:"value"`
+}
+
+type summaryRow struct {
+	resourceColumns
+	dataPointColumns
+	Count          uint64          `parquet:"count"`
+	Sum            float64         `parquet:"sum"`
+	QuantileValues []quantileValue `parquet:"quantile_values,list"`
+}
+
+// attributesToMap flattens attributes to their string representation, so that
+// the schema doesn't depend on the type of the attribute values.
+func attributesToMap(attributes pcommon.Map) map[string]string {
+	m := make(map[string]string, attributes.Len())
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		m[k] = v.AsString()
+		return true
+	})
+	return m
+}
+
+func newResourceColumns(resource pcommon.Resource, resourceSchemaURL string, scope pcommon.InstrumentationScope, scopeSchemaURL string) resourceColumns {
+	columns := resourceColumns{
+		ResourceAttributes: attributesToMap(resource.Attributes()),
+		ResourceSchemaURL:  resourceSchemaURL,
+		ScopeName:          scope.Name(),
+		ScopeVersion:       scope.Version(),
+		ScopeAttributes:    attributesToMap(scope.Attributes()),
+		ScopeSchemaURL:     scopeSchemaURL,
+	}
+	if serviceName, ok := resource.Attributes().Get(conventions.AttributeServiceName); ok {
+		columns.ServiceName = serviceName.AsString()
+	}
+	return columns
+}
+
+func traceIDToHex(id pcommon.TraceID) string {
+	if id.IsEmpty() {
+		return ""
+	}
+	return id.String()
+}
+
+func spanIDToHex(id pcommon.SpanID) string {
+	if id.IsEmpty() {
+		return ""
+	}
+	return id.String()
+}
+
+func logsToParquet(ld plog.Logs) []parquetBatch {
+	rows := make([]any, 0, ld.LogRecordCount())
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			columns := newResourceColumns(rl.Resource(), rl.SchemaUrl(), sl.Scope(), sl.SchemaUrl())
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lr := sl.LogRecords().At(k)
+				rows = append(rows, logRow{
+					resourceColumns:        columns,
+					TimeUnixNano:           int64(lr.Timestamp()),
+					ObservedTimeUnixNano:   int64(lr.ObservedTimestamp()),
+					SeverityNumber:         int32(lr.SeverityNumber()),
+					SeverityText:           lr.SeverityText(),
+					Body:                   lr.Body().AsString(),
+					Attributes:             attributesToMap(lr.Attributes()),
+					DroppedAttributesCount: lr.DroppedAttributesCount(),
+					Flags:                  uint32(lr.Flags()),
+					TraceID:                traceIDToHex(lr.TraceID()),
+					SpanID:                 spanIDToHex(lr.SpanID()),
+				})
+			}
+		}
+	}
+	return nonEmptyBatches(parquetBatch{table: parquetLogsTable, rows: rows})
+}
+
+func tracesToParquet(td ptrace.Traces) []parquetBatch {
+	rows := make([]any, 0, td.SpanCount())
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			columns := newResourceColumns(rs.Resource(), rs.SchemaUrl(), ss.Scope(), ss.SchemaUrl())
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				row := spanRow{
+					resourceColumns:        columns,
+					TraceID:                traceIDToHex(span.TraceID()),
+					SpanID:                 spanIDToHex(span.SpanID()),
+					TraceState:             span.TraceState().AsRaw(),
+					ParentSpanID:           spanIDToHex(span.ParentSpanID()),
+					Flags:                  span.Flags(),
+					Name:                   span.Name(),
+					Kind:                   span.Kind().String(),
+					StartTimeUnixNano:      int64(span.StartTimestamp()),
+					EndTimeUnixNano:        int64(span.EndTimestamp()),
+					DurationNano:           int64(span.EndTimestamp()) - int64(span.StartTimestamp()),
+					Attributes:             attributesToMap(span.Attributes()),
+					DroppedAttributesCount: span.DroppedAttributesCount(),
+					DroppedEventsCount:     span.DroppedEventsCount(),
+					DroppedLinksCount:      span.DroppedLinksCount(),
+					StatusCode:             span.Status().Code().String(),
+					StatusMessage:          span.Status().Message(),
+				}
+				for l := 0; l < span.Events().Len(); l++ {
+					event := span.Events().At(l)
+					row.Events = append(row.Events, spanEvent{
+						TimeUnixNano:           int64(event.Timestamp()),
+						Name:                   event.Name(),
+						Attributes:             attributesToMap(event.Attributes()),
+						DroppedAttributesCount: event.DroppedAttributesCount(),
+					})
+				}
+				for l := 0; l < span.Links().Len(); l++ {
+					link := span.Links().At(l)
+					row.Links = append(row.Links, spanLink{
+						TraceID:                traceIDToHex(link.TraceID()),
+						SpanID:                 spanIDToHex(link.SpanID()),
+						TraceState:             link.TraceState().AsRaw(),
+						Attributes:             attributesToMap(link.Attributes()),
+						DroppedAttributesCount: link.DroppedAttributesCount(),
+					})
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	return nonEmptyBatches(parquetBatch{table: parquetSpansTable, rows: rows})
+}
+
+func metricsToParquet(md pmetric.Metrics) []parquetBatch {
+	gauges := parquetBatch{table: parquetGaugeTable}
+	sums := parquetBatch{table: parquetSumTable}
+	histograms := parquetBatch{table: parquetHistogramTable}
+	exponentialHistograms := parquetBatch{table: parquetExponentialHistogramTable}
+	summaries := parquetBatch{table: parquetSummaryTable}
+
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			columns := newResourceColumns(rm.Resource(), rm.SchemaUrl(), sm.Scope(), sm.SchemaUrl())
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				metric := sm.Metrics().At(k)
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dps := metric.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						row := gaugeRow{resourceColumns: columns, dataPointColumns: newDataPointColumns(metric, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags())}
+						row.ValueDouble, row.ValueInt = numberValue(dp)
+						gauges.rows = append(gauges.rows, row)
+					}
+				case pmetric.MetricTypeSum:
+					sum := metric.Sum()
+					dps := sum.DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						row := sumRow{
+							resourceColumns:        columns,
+							dataPointColumns:       newDataPointColumns(metric, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags()),
+							AggregationTemporality: sum.AggregationTemporality().String(),
+							IsMonotonic:            sum.IsMonotonic(),
+						}
+						row.ValueDouble, row.ValueInt = numberValue(dp)
+						sums.rows = append(sums.rows, row)
+					}
+				case pmetric.MetricTypeHistogram:
+					histogram := metric.Histogram()
+					dps := histogram.DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						row := histogramRow{
+							resourceColumns:        columns,
+							dataPointColumns:       newDataPointColumns(metric, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags()),
+							Count:                  dp.Count(),
+							BucketCounts:           dp.BucketCounts().AsRaw(),
+							ExplicitBounds:         dp.ExplicitBounds().AsRaw(),
+							AggregationTemporality: histogram.AggregationTemporality().String(),
+						}
+						if dp.HasSum() {
+							row.Sum = ptr(dp.Sum())
+						}
+						if dp.HasMin() {
+							row.Min = ptr(dp.Min())
+						}
+						if dp.HasMax() {
+							row.Max = ptr(dp.Max())
+						}
+						histograms.rows = append(histograms.rows, row)
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					histogram := metric.ExponentialHistogram()
+					dps := histogram.DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						row := exponentialHistogramRow{
+							resourceColumns:        columns,
+							dataPointColumns:       newDataPointColumns(metric, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags()),
+							Count:                  dp.Count(),
+							Scale:                  dp.Scale(),
+							ZeroCount:              dp.ZeroCount(),
+							ZeroThreshold:          dp.ZeroThreshold(),
+							PositiveOffset:         dp.Positive().Offset(),
+							PositiveBucketCounts:   dp.Positive().BucketCounts().AsRaw(),
+							NegativeOffset:         dp.Negative().Offset(),
+							NegativeBucketCounts:   dp.Negative().BucketCounts().AsRaw(),
+							AggregationTemporality: histogram.AggregationTemporality().String(),
+						}
+						if dp.HasSum() {
+							row.Sum = ptr(dp.Sum())
+						}
+						if dp.HasMin() {
+							row.Min = ptr(dp.Min())
+						}
+						if dp.HasMax() {
+							row.Max = ptr(dp.Max())
+						}
+						exponentialHistograms.rows = append(exponentialHistograms.rows, row)
+					}
+				case pmetric.MetricTypeSummary:
+					dps := metric.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						row := summaryRow{
+							resourceColumns:  columns,
+							dataPointColumns: newDataPointColumns(metric, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags()),
+							Count:            dp.Count(),
+							Sum:              dp.Sum(),
+						}
+						for m := 0; m < dp.QuantileValues().Len(); m++ {
+							qv := dp.QuantileValues().At(m)
+							row.QuantileValues = append(row.QuantileValues, quantileValue{Quantile: qv.Quantile(), Value: qv.Value()})
+						}
+						summaries.rows = append(summaries.rows, row)
+					}
+				}
+			}
+		}
+	}
+	return nonEmptyBatches(gauges, sums, histograms, exponentialHistograms, summaries)
+}
+
+func newDataPointColumns(metric pmetric.Metric, attributes pcommon.Map, start, timestamp pcommon.Timestamp, flags pmetric.DataPointFlags) dataPointColumns {
+	return dataPointColumns{
+		MetricName:        metric.Name(),
+		MetricDescription: metric.Description(),
+		MetricUnit:        metric.Unit(),
+		Attributes:        attributesToMap(attributes),
+		StartTimeUnixNano: int64(start),
+		TimeUnixNano:      int64(timestamp),
+		Flags:             uint32(flags),
+	}
+}
+
+func numberValue(dp pmetric.NumberDataPoint) (*float64, *int64) {
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeDouble:
+		return ptr(dp.DoubleValue()), nil
+	case pmetric.NumberDataPointValueTypeInt:
+		return nil, ptr(dp.IntValue())
+	default:
+		return nil, nil
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func nonEmptyBatches(batches ...parquetBatch) []parquetBatch {
+	result := make([]parquetBatch, 0, len(batches))
+	for _, batch := range batches {
+		if len(batch.rows) > 0 {
+			result = append(result, batch)
+		}
+	}
+	return result
+}
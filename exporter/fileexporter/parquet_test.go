@@ -0,0 +1,327 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexporter
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+func newParquetConfig(path string) *Config {
+	return &Config{
+		Path:       path,
+		FormatType: formatTypeParquet,
+		Parquet: &Parquet{
+			RowGroupSize: defaultParquetRowGroupSize,
+			Compression:  parquetCompressionSnappy,
+		},
+	}
+}
+
+func generateMetricsAllTypes() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("scope")
+	sm.Scope().SetVersion("1.0")
+	start := pcommon.NewTimestampFromTime(time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC))
+	ts := pcommon.NewTimestampFromTime(time.Date(2024, time.March, 5, 7, 1, 0, 0, time.UTC))
+
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("gauge")
+	gauge.SetUnit("1")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(1.5)
+	dp.Attributes().PutInt("cpu", 1)
+
+	sum := sm.Metrics().AppendEmpty()
+	sum.SetName("sum")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp = sum.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(42)
+
+	histogram := sm.Metrics().AppendEmpty()
+	histogram.SetName("histogram")
+	histogram.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	hdp := histogram.Histogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(ts)
+	hdp.SetCount(3)
+	hdp.SetSum(6)
+	hdp.BucketCounts().FromRaw([]uint64{1, 2})
+	hdp.ExplicitBounds().FromRaw([]float64{2})
+
+	exponentialHistogram := sm.Metrics().AppendEmpty()
+	exponentialHistogram.SetName("exponential_histogram")
+	edp := exponentialHistogram.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	edp.SetTimestamp(ts)
+	edp.SetCount(2)
+	edp.SetScale(3)
+	edp.Positive().SetOffset(1)
+	edp.Positive().BucketCounts().FromRaw([]uint64{2})
+
+	summary := sm.Metrics().AppendEmpty()
+	summary.SetName("summary")
+	sdp := summary.SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetTimestamp(ts)
+	sdp.SetCount(10)
+	sdp.SetSum(100)
+	qv := sdp.QuantileValues().AppendEmpty()
+	qv.SetQuantile(0.5)
+	qv.SetValue(9)
+	return md
+}
+
+func TestParquetFileExporter(t *testing.T) {
+	dir := t.TempDir()
+	conf := newParquetConfig(filepath.Join(dir, "telemetry.parquet"))
+	require.NoError(t, conf.Validate())
+	fe := newFileExporter(conf, zap.NewNop())
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("checkout")))
+	require.NoError(t, fe.consumeTraces(context.Background(), tracesWithService("checkout")))
+	require.NoError(t, fe.consumeMetrics(context.Background(), generateMetricsAllTypes()))
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{
+		"telemetry.exponential_histogram.parquet",
+		"telemetry.gauge.parquet",
+		"telemetry.histogram.parquet",
+		"telemetry.logs.parquet",
+		"telemetry.spans.parquet",
+		"telemetry.sum.parquet",
+		"telemetry.summary.parquet",
+	}, listFiles(t, dir))
+
+	logs, err := parquet.ReadFile[logRow](filepath.Join(dir, "telemetry.logs.parquet"))
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "checkout", logs[0].ServiceName)
+	assert.Equal(t, "checkout", logs[0].ResourceAttributes["service.name"])
+	assert.Equal(t, "This is a log message", logs[0].Body)
+	assert.Equal(t, "Info", logs[0].SeverityText)
+	assert.Equal(t, "server", logs[0].Attributes["app"])
+
+	spans, err := parquet.ReadFile[spanRow](filepath.Join(dir, "telemetry.spans.parquet"))
+	require.NoError(t, err)
+	require.Len(t, spans, 2)
+	assert.Equal(t, "operationA", spans[0].Name)
+	assert.Equal(t, "Error", spans[0].StatusCode)
+	require.Len(t, spans[0].Events, 2)
+	assert.Equal(t, "event-with-attr", spans[0].Events[0].Name)
+	assert.Equal(t, "span-event-attr-val", spans[0].Events[0].Attributes["span-event-attr"])
+	require.Len(t, spans[1].Links, 2)
+
+	gauges, err := parquet.ReadFile[gaugeRow](filepath.Join(dir, "telemetry.gauge.parquet"))
+	require.NoError(t, err)
+	require.Len(t, gauges, 1)
+	assert.Equal(t, "gauge", gauges[0].MetricName)
+	assert.Equal(t, "scope", gauges[0].ScopeName)
+	assert.Equal(t, "1", gauges[0].Attributes["cpu"])
+	require.NotNil(t, gauges[0].ValueDouble)
+	assert.Equal(t, 1.5, *gauges[0].ValueDouble)
+	assert.Nil(t, gauges[0].ValueInt)
+
+	sums, err := parquet.ReadFile[sumRow](filepath.Join(dir, "telemetry.sum.parquet"))
+	require.NoError(t, err)
+	require.Len(t, sums, 1)
+	require.NotNil(t, sums[0].ValueInt)
+	assert.Equal(t, int64(42), *sums[0].ValueInt)
+	assert.Equal(t, "Cumulative", sums[0].AggregationTemporality)
+	assert.True(t, sums[0].IsMonotonic)
+	assert.Equal(t, time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC).UnixNano(), sums[0].StartTimeUnixNano)
+
+	histograms, err := parquet.ReadFile[histogramRow](filepath.Join(dir, "telemetry.histogram.parquet"))
+	require.NoError(t, err)
+	require.Len(t, histograms, 1)
+	assert.Equal(t, []uint64{1, 2}, histograms[0].BucketCounts)
+	assert.Equal(t, []float64{2}, histograms[0].ExplicitBounds)
+	require.NotNil(t, histograms[0].Sum)
+	assert.Equal(t, 6.0, *histograms[0].Sum)
+	assert.Nil(t, histograms[0].Min)
+
+	exponentialHistograms, err := parquet.ReadFile[exponentialHistogramRow](filepath.Join(dir, "telemetry.exponential_histogram.parquet"))
+	require.NoError(t, err)
+	require.Len(t, exponentialHistograms, 1)
+	assert.Equal(t, int32(3), exponentialHistograms[0].Scale)
+	assert.Equal(t, int32(1), exponentialHistograms[0].PositiveOffset)
+	assert.Equal(t, []uint64{2}, exponentialHistograms[0].PositiveBucketCounts)
+
+	summaries, err := parquet.ReadFile[summaryRow](filepath.Join(dir, "telemetry.summary.parquet"))
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, []quantileValue{{Quantile: 0.5, Value: 9}}, summaries[0].QuantileValues)
+}
+
+func TestParquetFileExporterRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "telemetry.logs.parquet")
+	conf := newParquetConfig(filepath.Join(dir, "telemetry.parquet"))
+	conf.Parquet.RowGroupSize = 50
+	conf.Parquet.Compression = parquetCompressionNone
+
+	writer, err := newParquetFileWriter(path, conf, &Rotation{MaxMegabytes: 1, MaxBackups: defaultMaxBackups})
+	require.NoError(t, err)
+	// rotate as soon as a row group is written
+	writer.parquet.maxSize = 1
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 200; i++ {
+		records.AppendEmpty().Body().SetStr(strings.Repeat(strconv.Itoa(i), 1000))
+	}
+	for i := 0; i < 3; i++ {
+		batches := logsToParquet(ld)
+		require.Len(t, batches, 1)
+		require.NoError(t, writer.exportRows(batches[0].table, batches[0].rows))
+	}
+	require.NoError(t, writer.shutdown())
+
+	files := listFiles(t, dir)
+	require.Len(t, files, 3)
+	for _, file := range files {
+		assert.True(t, strings.HasPrefix(file, "telemetry.logs"))
+		rows, err := parquet.ReadFile[logRow](filepath.Join(dir, file))
+		require.NoError(t, err, file)
+		assert.Len(t, rows, 200)
+	}
+}
+
+func TestParquetFileExporterMixedTables(t *testing.T) {
+	dir := t.TempDir()
+	conf := newParquetConfig(filepath.Join(dir, "telemetry.parquet"))
+	writer, err := newParquetFileWriter(conf.Path, conf, nil)
+	require.NoError(t, err)
+	logs := logsToParquet(logsWithService("checkout"))
+	spans := tracesToParquet(tracesWithService("checkout"))
+
+	require.NoError(t, writer.exportRows(logs[0].table, logs[0].rows))
+	assert.EqualError(t, writer.exportRows(spans[0].table, spans[0].rows), "parquet file can't contain both logs and spans rows")
+	require.NoError(t, writer.shutdown())
+}
+
+func TestParquetGroupingFileExporter(t *testing.T) {
+	dir := t.TempDir()
+	conf := newParquetConfig(filepath.Join(dir, "*.parquet"))
+	conf.GroupBy = &GroupBy{
+		Enabled:           true,
+		ResourceAttribute: "service.name",
+		MaxOpenFiles:      defaultMaxOpenFiles,
+	}
+	require.NoError(t, conf.Validate())
+	fe := newFileExporter(conf, zap.NewNop())
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+
+	ld := logsWithService("checkout")
+	logsWithService("cart").ResourceLogs().MoveAndAppendTo(ld.ResourceLogs())
+	require.NoError(t, fe.consumeLogs(context.Background(), ld))
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"cart.logs.parquet", "checkout.logs.parquet"}, listFiles(t, dir))
+	rows, err := parquet.ReadFile[logRow](filepath.Join(dir, "cart.logs.parquet"))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "cart", rows[0].ServiceName)
+}
+
+func TestParquetGroupingFileExporterEviction(t *testing.T) {
+	dir := t.TempDir()
+	conf := newParquetConfig(filepath.Join(dir, "*.parquet"))
+	conf.GroupBy = &GroupBy{
+		Enabled:           true,
+		ResourceAttribute: "service.name",
+		MaxOpenFiles:      1,
+	}
+	require.NoError(t, conf.Validate())
+	fe := newFileExporter(conf, zap.NewNop())
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Each write evicts the writer of the other service
+	for i := 0; i < 2; i++ {
+		require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("checkout")))
+		require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("cart")))
+	}
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"cart.1.logs.parquet", "cart.logs.parquet", "checkout.1.logs.parquet", "checkout.logs.parquet"}, listFiles(t, dir))
+	for _, file := range listFiles(t, dir) {
+		rows, err := parquet.ReadFile[logRow](filepath.Join(dir, file))
+		require.NoError(t, err, file)
+		assert.Len(t, rows, 2, file)
+	}
+}
+
+func TestParquetPartitioningFileExporter(t *testing.T) {
+	dir := t.TempDir()
+	conf := newParquetConfig(filepath.Join(dir, "%H", "{signal}.parquet"))
+	conf.Partition = &Partition{
+		Enabled:       true,
+		MaxOpenFiles:  defaultMaxOpenFiles,
+		Finalize:      true,
+		PendingSuffix: defaultPendingSuffix,
+	}
+	require.NoError(t, conf.Validate())
+	fe := newFileExporter(conf, zap.NewNop()).(*partitioningFileExporter)
+	clock := &testClock{now: time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)}
+	fe.now = clock.Now
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, fe.consumeMetrics(context.Background(), testdata.GenerateMetricsTwoMetrics()))
+	clock.Set(clock.Now().Add(time.Hour))
+	require.NoError(t, fe.consumeMetrics(context.Background(), testdata.GenerateMetricsTwoMetrics()))
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"07/metrics.sum.parquet", "08/metrics.sum.parquet"}, listFiles(t, dir))
+	for _, file := range listFiles(t, dir) {
+		rows, err := parquet.ReadFile[sumRow](filepath.Join(dir, file))
+		require.NoError(t, err)
+		assert.Len(t, rows, 4)
+	}
+}
+
+func TestParquetPartitioningFileExporterEviction(t *testing.T) {
+	dir := t.TempDir()
+	conf := newParquetConfig(filepath.Join(dir, "{resource:service.name}.parquet"))
+	conf.Partition = &Partition{
+		Enabled:      true,
+		MaxOpenFiles: 1,
+	}
+	require.NoError(t, conf.Validate())
+	fe := newFileExporter(conf, zap.NewNop())
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Each write evicts the file of the other service
+	for i := 0; i < 2; i++ {
+		require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("checkout")))
+		require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("cart")))
+	}
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"cart.1.logs.parquet", "cart.logs.parquet", "checkout.1.logs.parquet", "checkout.logs.parquet"}, listFiles(t, dir))
+	for _, file := range listFiles(t, dir) {
+		rows, err := parquet.ReadFile[logRow](filepath.Join(dir, file))
+		require.NoError(t, err, file)
+		assert.Len(t, rows, 2, file)
+	}
+}
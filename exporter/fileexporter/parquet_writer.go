@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// the compression codecs of parquet files
+	parquetCompressionSnappy = "snappy"
+	parquetCompressionZSTD   = "zstd"
+	parquetCompressionGzip   = "gzip"
+	parquetCompressionLZ4    = "lz4"
+	parquetCompressionNone   = "none"
+
+	defaultParquetRowGroupSize = 10000
+
+	// defaultRotationMaxMegabytes matches the default maximum size of files rotated by lumberjack.
+	defaultRotationMaxMegabytes = 100
+
+	megabyte = 1024 * 1024
+)
+
+var parquetCompressionCodecs = map[string]compress.Codec{
+	parquetCompressionSnappy: &parquet.Snappy,
+	parquetCompressionZSTD:   &parquet.Zstd,
+	parquetCompressionGzip:   &parquet.Gzip,
+	parquetCompressionLZ4:    &parquet.Lz4Raw,
+	parquetCompressionNone:   &parquet.Uncompressed,
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// parquetEncoder writes the rows of a single table to a file in the parquet format.
+// Rows are buffered until a row group is complete, and the footer of the file is
+// only written when the encoder is closed.
+type parquetEncoder struct {
+	conf *Parquet
+	out  *countingWriter
+
+	table  *parquetTable
+	writer *parquet.Writer
+
+	// rotate starts a new file, nil if the file is never rotated.
+	rotate func() error
+	// maxSize is the size in bytes of the file after which it is rotated. Only the row groups
+	// written to the file are counted, so files exceed it by up to a row group.
+	maxSize int64
+	// rotatePending tells whether a new file must be started before writing rows. Rotation
+	// is delayed until rows are written so that no empty, invalid, file is left behind.
+	rotatePending bool
+}
+
+func (p *parquetEncoder) write(table *parquetTable, rows []any) error {
+	if p.table != nil && p.table != table {
+		return fmt.Errorf("parquet file can't contain both %s and %s rows", p.table.name, table.name)
+	}
+	if p.writer == nil {
+		if p.rotatePending {
+			if err := p.rotate(); err != nil {
+				return err
+			}
+			p.rotatePending = false
+			p.out.n = 0
+		}
+		p.table = table
+		p.writer = parquet.NewWriter(p.out, table.schema,
+			parquet.MaxRowsPerRowGroup(p.conf.RowGroupSize),
+			parquet.Compression(parquetCompressionCodecs[p.conf.Compression]))
+	}
+
+	for _, row := range rows {
+		if err := p.writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	if p.rotate != nil && p.out.n >= p.maxSize {
+		p.rotatePending = true
+		return p.close()
+	}
+	return nil
+}
+
+// written returns the number of bytes written to the current file.
+func (p *parquetEncoder) written() int64 {
+	return p.out.n
+}
+
+// close writes the buffered rows and the footer of the file.
+func (p *parquetEncoder) close() error {
+	if p.writer == nil {
+		return nil
+	}
+	w := p.writer
+	p.writer = nil
+	return w.Close()
+}
+
+// exportRows writes the rows of a table to a parquet file.
+func (w *fileWriter) exportRows(table *parquetTable, rows []any) error {
+	// Ensure only one write operation happens at a time.
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.parquet.write(table, rows)
+}
+
+// newParquetFileWriter creates a writer of parquet files. Files are never appended to,
+// and the existing file is backed up rather than truncated when the file is rotated.
+func newParquetFileWriter(path string, conf *Config, rotation *Rotation) (*fileWriter, error) {
+	if rotation == nil {
+		w, err := newFileWriter(path, false, nil, conf.FlushInterval, nil)
+		if err != nil {
+			return nil, err
+		}
+		w.parquet = &parquetEncoder{conf: conf.Parquet, out: &countingWriter{w: w.file}}
+		return w, nil
+	}
+
+	maxMegabytes := rotation.MaxMegabytes
+	if maxMegabytes == 0 {
+		maxMegabytes = defaultRotationMaxMegabytes
+	}
+	// the file is rotated by the encoder once the footer is written, never by lumberjack itself.
+	logger := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    math.MaxInt32,
+		MaxAge:     rotation.MaxDays,
+		MaxBackups: rotation.MaxBackups,
+		LocalTime:  rotation.LocalTime,
+	}
+	return &fileWriter{
+		path:          path,
+		file:          logger,
+		flushInterval: conf.FlushInterval,
+		parquet: &parquetEncoder{
+			conf:          conf.Parquet,
+			out:           &countingWriter{w: logger},
+			rotate:        logger.Rotate,
+			maxSize:       int64(maxMegabytes) * megabyte,
+			rotatePending: true,
+		},
+	}, nil
+}
+
+// parquetTablePath returns the path of the files of a table, inserting the name of the
+// table before the extension of the file.
+func parquetTablePath(path string, table *parquetTable) string {
+	return insertBeforeExtension(path, table.name)
+}
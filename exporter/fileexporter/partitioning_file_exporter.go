@@ -0,0 +1,399 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// expireInterval is the interval at which files of past time windows are closed.
+const expireInterval = time.Second
+
+// partitionFile is a file written to a partition during a time window.
+type partitionFile struct {
+	writer    *fileWriter
+	finalPath string
+	window    string
+	size      int64
+}
+
+// partitioningFileExporter writes telemetry data to files whose path is rendered from a template,
+// rolling over to new files on time boundaries and when the files reach their maximum size.
+type partitioningFileExporter struct {
+	conf       *Config
+	logger     *zap.Logger
+	marshaller *marshaller
+	template   *pathTemplate
+	export     exportFunc
+	now        func() time.Time
+
+	mutex sync.Mutex
+	files *simplelru.LRU[string, *partitionFile]
+
+	stopExpiry chan struct{}
+	expiryDone chan struct{}
+}
+
+func (e *partitioningFileExporter) consumeTraces(_ context.Context, td ptrace.Traces) error {
+	if td.ResourceSpans().Len() == 0 {
+		return nil
+	}
+
+	now := e.timestamp()
+	groups := make(map[string][]ptrace.ResourceSpans)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rSpans := td.ResourceSpans().At(i)
+		partition := e.template.render(now, rSpans.Resource(), signalTraces)
+		groups[partition] = append(groups[partition], rSpans)
+	}
+
+	var errs error
+	for partition, rSpansSlice := range groups {
+		traces := ptrace.NewTraces()
+		for _, rSpans := range rSpansSlice {
+			rSpans.CopyTo(traces.ResourceSpans().AppendEmpty())
+		}
+
+		if e.conf.FormatType == formatTypeParquet {
+			errs = errors.Join(errs, e.writeParquet(partition, now, tracesToParquet(traces)))
+			continue
+		}
+
+		buf, err := e.marshaller.marshalTraces(traces)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		errs = errors.Join(errs, e.write(partition, now, buf))
+	}
+
+	if errs != nil {
+		return consumererror.NewPermanent(errs)
+	}
+	return nil
+}
+
+func (e *partitioningFileExporter) consumeMetrics(_ context.Context, md pmetric.Metrics) error {
+	if md.ResourceMetrics().Len() == 0 {
+		return nil
+	}
+
+	now := e.timestamp()
+	groups := make(map[string][]pmetric.ResourceMetrics)
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rMetrics := md.ResourceMetrics().At(i)
+		partition := e.template.render(now, rMetrics.Resource(), signalMetrics)
+		groups[partition] = append(groups[partition], rMetrics)
+	}
+
+	var errs error
+	for partition, rMetricsSlice := range groups {
+		metrics := pmetric.NewMetrics()
+		for _, rMetrics := range rMetricsSlice {
+			rMetrics.CopyTo(metrics.ResourceMetrics().AppendEmpty())
+		}
+
+		if e.conf.FormatType == formatTypeParquet {
+			errs = errors.Join(errs, e.writeParquet(partition, now, metricsToParquet(metrics)))
+			continue
+		}
+
+		buf, err := e.marshaller.marshalMetrics(metrics)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		errs = errors.Join(errs, e.write(partition, now, buf))
+	}
+
+	if errs != nil {
+		return consumererror.NewPermanent(errs)
+	}
+	return nil
+}
+
+func (e *partitioningFileExporter) consumeLogs(_ context.Context, ld plog.Logs) error {
+	if ld.ResourceLogs().Len() == 0 {
+		return nil
+	}
+
+	now := e.timestamp()
+	groups := make(map[string][]plog.ResourceLogs)
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rLogs := ld.ResourceLogs().At(i)
+		partition := e.template.render(now, rLogs.Resource(), signalLogs)
+		groups[partition] = append(groups[partition], rLogs)
+	}
+
+	var errs error
+	for partition, rLogsSlice := range groups {
+		logs := plog.NewLogs()
+		for _, rLogs := range rLogsSlice {
+			rLogs.CopyTo(logs.ResourceLogs().AppendEmpty())
+		}
+
+		if e.conf.FormatType == formatTypeParquet {
+			errs = errors.Join(errs, e.writeParquet(partition, now, logsToParquet(logs)))
+			continue
+		}
+
+		buf, err := e.marshaller.marshalLogs(logs)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		errs = errors.Join(errs, e.write(partition, now, buf))
+	}
+
+	if errs != nil {
+		return consumererror.NewPermanent(errs)
+	}
+	return nil
+}
+
+func (e *partitioningFileExporter) timestamp() time.Time {
+	if e.conf.Partition.LocalTime {
+		return e.now().Local()
+	}
+	return e.now().UTC()
+}
+
+// window identifies the time window of the given time. Files are rolled over
+// whenever the time window changes.
+func (e *partitioningFileExporter) window(now time.Time) string {
+	window := e.template.renderTime(now)
+	if e.conf.Partition.RolloverInterval > 0 {
+		window += "/" + strconv.FormatInt(now.Truncate(e.conf.Partition.RolloverInterval).Unix(), 10)
+	}
+	return window
+}
+
+func (e *partitioningFileExporter) write(partition string, now time.Time, buf []byte) error {
+	return e.writeFile(partition, now, len(buf), func(writer *fileWriter) error {
+		return writer.export(buf)
+	})
+}
+
+// writeParquet writes the rows of each table to a separate file of the partition.
+func (e *partitioningFileExporter) writeParquet(partition string, now time.Time, batches []parquetBatch) error {
+	var errs error
+	for _, batch := range batches {
+		errs = errors.Join(errs, e.writeFile(parquetTablePath(partition, batch.table), now, 0, func(writer *fileWriter) error {
+			return writer.exportRows(batch.table, batch.rows)
+		}))
+	}
+	return errs
+}
+
+// writeFile writes size bytes to the current file of the partition, rolling over to a new file
+// first if needed. The size of parquet files is only known once their row groups are written.
+func (e *partitioningFileExporter) writeFile(partition string, now time.Time, size int, write func(writer *fileWriter) error) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	window := e.window(now)
+	file, ok := e.files.Get(partition)
+	if ok && (file.window != window || e.exceedsMaxSize(file, size)) {
+		// removing the file from the cache finalizes it.
+		e.files.Remove(partition)
+		ok = false
+	}
+	if !ok {
+		var err error
+		file, err = e.openFile(partition, window)
+		if err != nil {
+			return err
+		}
+		e.files.Add(partition, file)
+	}
+
+	if err := write(file.writer); err != nil {
+		return err
+	}
+	if file.writer.parquet != nil {
+		file.size = file.writer.parquet.written()
+	} else {
+		file.size += int64(size)
+	}
+	return nil
+}
+
+func (e *partitioningFileExporter) exceedsMaxSize(file *partitionFile, size int) bool {
+	if e.conf.Partition.MaxMegabytes == 0 || file.size == 0 {
+		return false
+	}
+	return file.size+int64(size) > int64(e.conf.Partition.MaxMegabytes)*1024*1024
+}
+
+// openFile creates the next file of the partition. Files already present in the
+// partition, complete or not, are never overwritten.
+func (e *partitioningFileExporter) openFile(partition string, window string) (*partitionFile, error) {
+	if err := os.MkdirAll(filepath.Dir(partition), 0755); err != nil {
+		return nil, err
+	}
+
+	var finalPath, writePath string
+	for n := 0; ; n++ {
+		finalPath = sequencedPath(partition, n)
+		writePath = e.pendingPath(finalPath)
+		finalExists, err := fileExists(finalPath)
+		if err != nil {
+			return nil, err
+		}
+		pendingExists, err := fileExists(writePath)
+		if err != nil {
+			return nil, err
+		}
+		if !finalExists && !pendingExists {
+			break
+		}
+	}
+
+	var writer *fileWriter
+	var err error
+	if e.conf.FormatType == formatTypeParquet {
+		writer, err = newParquetFileWriter(writePath, e.conf, nil)
+	} else {
+		writer, err = newFileWriter(writePath, false, nil, e.conf.FlushInterval, e.export)
+	}
+	if err != nil {
+		return nil, err
+	}
+	writer.start()
+
+	return &partitionFile{
+		writer:    writer,
+		finalPath: finalPath,
+		window:    window,
+	}, nil
+}
+
+func (e *partitioningFileExporter) pendingPath(finalPath string) string {
+	if !e.conf.Partition.Finalize {
+		return finalPath
+	}
+	return finalPath + e.conf.Partition.PendingSuffix
+}
+
+func fileExists(path string) (bool, error) {
+	_, err := os.Lstat(path)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// finalize closes the file and renames it to its final path, if it was written with a pending suffix.
+func (e *partitioningFileExporter) finalize(file *partitionFile) error {
+	if err := file.writer.shutdown(); err != nil {
+		return err
+	}
+	if file.writer.path == file.finalPath {
+		return nil
+	}
+	return os.Rename(file.writer.path, file.finalPath)
+}
+
+func (e *partitioningFileExporter) onEvict(_ string, file *partitionFile) {
+	if err := e.finalize(file); err != nil {
+		e.logger.Warn("Failed to finalize file", zap.Error(err), zap.String("path", file.finalPath))
+	}
+}
+
+// closeExpired finalizes the files of past time windows, so that they don't remain
+// open or pending until new data is written to their partition.
+func (e *partitioningFileExporter) closeExpired() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	window := e.window(e.timestamp())
+	for _, partition := range e.files.Keys() {
+		if file, ok := e.files.Peek(partition); ok && file.window != window {
+			e.files.Remove(partition)
+		}
+	}
+}
+
+func (e *partitioningFileExporter) startExpiry() {
+	e.stopExpiry = make(chan struct{})
+	e.expiryDone = make(chan struct{})
+	go func() {
+		defer close(e.expiryDone)
+		ticker := time.NewTicker(expireInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				e.closeExpired()
+			case <-e.stopExpiry:
+				return
+			}
+		}
+	}()
+}
+
+// Start initializes and starts the exporter.
+func (e *partitioningFileExporter) Start(_ context.Context, host component.Host) error {
+	var err error
+	e.marshaller, err = newMarshaller(e.conf, host)
+	if err != nil {
+		return err
+	}
+	e.export = buildExportFunc(e.conf)
+
+	e.template, err = parsePathTemplate(e.conf.Path)
+	if err != nil {
+		return err
+	}
+
+	e.files, err = simplelru.NewLRU(e.conf.Partition.MaxOpenFiles, e.onEvict)
+	if err != nil {
+		return err
+	}
+
+	if e.conf.Partition.RolloverInterval > 0 || e.template.hasTime() {
+		e.startExpiry()
+	}
+	return nil
+}
+
+// Shutdown stops the exporter and is invoked during shutdown.
+// It finalizes all the files being written.
+func (e *partitioningFileExporter) Shutdown(context.Context) error {
+	if e.stopExpiry != nil {
+		close(e.stopExpiry)
+		<-e.expiryDone
+		e.stopExpiry = nil
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.files == nil {
+		return nil
+	}
+
+	e.files.Purge()
+	e.files = nil
+
+	return nil
+}
@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexporter
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+type testClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *testClock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = now
+}
+
+func newTestPartitioningExporter(t *testing.T, path string, partition *Partition, clock *testClock) *partitioningFileExporter {
+	conf := &Config{
+		Path:       path,
+		FormatType: formatTypeJSON,
+		Partition:  partition,
+	}
+	require.NoError(t, conf.Validate())
+	fe := newFileExporter(conf, zap.NewNop()).(*partitioningFileExporter)
+	fe.now = clock.Now
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	return fe
+}
+
+// listFiles returns the paths of all files in dir, relative to dir.
+func listFiles(t *testing.T, dir string) []string {
+	var files []string
+	require.NoError(t, filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	}))
+	sort.Strings(files)
+	return files
+}
+
+func tracesWithService(service string) ptrace.Traces {
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	td.ResourceSpans().At(0).Resource().Attributes().PutStr("service.name", service)
+	return td
+}
+
+func logsWithService(service string) plog.Logs {
+	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
+	ld.ResourceLogs().At(0).Resource().Attributes().PutStr("service.name", service)
+	return ld
+}
+
+func TestPartitioningExporterTemplate(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)}
+	fe := newTestPartitioningExporter(t, filepath.Join(dir, "%Y", "%m", "%d", "%H", "{resource:service.name}", "{signal}.json"), &Partition{
+		Enabled:       true,
+		MaxOpenFiles:  defaultMaxOpenFiles,
+		PendingSuffix: defaultPendingSuffix,
+	}, clock)
+
+	td := tracesWithService("checkout")
+	tracesWithService("cart").ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	require.NoError(t, fe.consumeTraces(context.Background(), td))
+	require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("checkout")))
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{
+		"2024/03/05/07/cart/traces.json",
+		"2024/03/05/07/checkout/logs.json",
+		"2024/03/05/07/checkout/traces.json",
+	}, listFiles(t, dir))
+
+	content, err := os.ReadFile(filepath.Join(dir, "2024/03/05/07/cart/traces.json"))
+	require.NoError(t, err)
+	traces, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(bytes.TrimSuffix(content, []byte("\n")))
+	require.NoError(t, err)
+	require.Equal(t, 1, traces.ResourceSpans().Len())
+	service, _ := traces.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "cart", service.Str())
+}
+
+func TestPartitioningExporterFinalize(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)}
+	fe := newTestPartitioningExporter(t, filepath.Join(dir, "%H", "{signal}.json"), &Partition{
+		Enabled:       true,
+		MaxOpenFiles:  defaultMaxOpenFiles,
+		Finalize:      true,
+		PendingSuffix: ".tmp",
+	}, clock)
+
+	require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("checkout")))
+	assert.Equal(t, []string{"07/logs.json.tmp"}, listFiles(t, dir))
+
+	// the file of the previous hour is finalized once the hour is over.
+	clock.Set(clock.Now().Add(time.Hour))
+	fe.closeExpired()
+	assert.Equal(t, []string{"07/logs.json"}, listFiles(t, dir))
+
+	require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("checkout")))
+	assert.Equal(t, []string{"07/logs.json", "08/logs.json.tmp"}, listFiles(t, dir))
+
+	require.NoError(t, fe.Shutdown(context.Background()))
+	assert.Equal(t, []string{"07/logs.json", "08/logs.json"}, listFiles(t, dir))
+}
+
+func TestPartitioningExporterRolloverInterval(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)}
+	fe := newTestPartitioningExporter(t, filepath.Join(dir, "%H", "{signal}.json"), &Partition{
+		Enabled:          true,
+		RolloverInterval: 15 * time.Minute,
+		MaxOpenFiles:     defaultMaxOpenFiles,
+		PendingSuffix:    defaultPendingSuffix,
+	}, clock)
+
+	require.NoError(t, fe.consumeTraces(context.Background(), tracesWithService("checkout")))
+	clock.Set(clock.Now().Add(5 * time.Minute))
+	require.NoError(t, fe.consumeTraces(context.Background(), tracesWithService("checkout")))
+	clock.Set(clock.Now().Add(10 * time.Minute))
+	require.NoError(t, fe.consumeTraces(context.Background(), tracesWithService("checkout")))
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"07/traces.1.json", "07/traces.json"}, listFiles(t, dir))
+	content, err := os.ReadFile(filepath.Join(dir, "07/traces.json"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(content, []byte("\n")))
+}
+
+func TestPartitioningExporterMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)}
+	fe := newTestPartitioningExporter(t, filepath.Join(dir, "{signal}.json"), &Partition{
+		Enabled:       true,
+		MaxMegabytes:  1,
+		MaxOpenFiles:  defaultMaxOpenFiles,
+		PendingSuffix: defaultPendingSuffix,
+	}, clock)
+	tm := &testMarshaller{content: bytes.Repeat([]byte{'a'}, 600*1024)}
+	fe.marshaller = &marshaller{
+		tracesMarshaler:  tm,
+		metricsMarshaler: tm,
+		logsMarshaler:    tm,
+		compressor:       noneCompress,
+		formatType:       "test",
+	}
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("checkout")))
+	}
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"logs.1.json", "logs.2.json", "logs.json"}, listFiles(t, dir))
+}
+
+func TestPartitioningExporterDoesNotOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logs.json"), []byte("existing\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logs.1.json.pending"), []byte("incomplete\n"), 0600))
+
+	clock := &testClock{now: time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)}
+	fe := newTestPartitioningExporter(t, filepath.Join(dir, "{signal}.json"), &Partition{
+		Enabled:       true,
+		MaxOpenFiles:  defaultMaxOpenFiles,
+		Finalize:      true,
+		PendingSuffix: defaultPendingSuffix,
+	}, clock)
+	require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("checkout")))
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"logs.1.json.pending", "logs.2.json", "logs.json"}, listFiles(t, dir))
+	content, err := os.ReadFile(filepath.Join(dir, "logs.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "existing\n", string(content))
+}
+
+func TestPartitioningExporterMaxOpenFiles(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)}
+	fe := newTestPartitioningExporter(t, filepath.Join(dir, "{resource:service.name}.json"), &Partition{
+		Enabled:       true,
+		MaxOpenFiles:  1,
+		Finalize:      true,
+		PendingSuffix: defaultPendingSuffix,
+	}, clock)
+
+	require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("cart")))
+	require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("checkout")))
+	// the least recently used file was finalized to keep a single file open.
+	assert.Equal(t, []string{"cart.json", "checkout.json.pending"}, listFiles(t, dir))
+	require.NoError(t, fe.Shutdown(context.Background()))
+	assert.Equal(t, []string{"cart.json", "checkout.json"}, listFiles(t, dir))
+}
+
+func TestPartitioningExporterExpiry(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)}
+	fe := newTestPartitioningExporter(t, filepath.Join(dir, "%M", "{signal}.json"), &Partition{
+		Enabled:       true,
+		MaxOpenFiles:  defaultMaxOpenFiles,
+		Finalize:      true,
+		PendingSuffix: defaultPendingSuffix,
+	}, clock)
+
+	require.NoError(t, fe.consumeLogs(context.Background(), logsWithService("cart")))
+	clock.Set(clock.Now().Add(time.Minute))
+	assert.Eventually(t, func() bool {
+		files := listFiles(t, dir)
+		return len(files) == 1 && files[0] == "08/logs.json"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, fe.Shutdown(context.Background()))
+}
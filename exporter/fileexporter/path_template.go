@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const (
+	signalTraces  = "traces"
+	signalMetrics = "metrics"
+	signalLogs    = "logs"
+
+	// missingAttributeValue replaces resource attributes missing from the resource in paths.
+	missingAttributeValue = "unknown"
+
+	resourceAttributePrefix = "resource:"
+)
+
+type templatePartKind int
+
+const (
+	templatePartLiteral templatePartKind = iota
+	templatePartTime
+	templatePartResourceAttribute
+	templatePartSignal
+)
+
+type templatePart struct {
+	kind templatePartKind
+	// value is the literal text, the strftime verb or the resource attribute name.
+	value string
+}
+
+// pathTemplate renders the path of partitioned files. It supports the following placeholders:
+// - strftime-style verbs: %Y, %y, %m, %d, %j, %H, %M, %S and %% for a literal %.
+// - {resource:<name>} for the value of a resource attribute.
+// - {signal} for the signal type: traces, metrics or logs.
+type pathTemplate struct {
+	parts []templatePart
+}
+
+var timeVerbs = map[byte]func(t time.Time) string{
+	'Y': func(t time.Time) string { return fmt.Sprintf("%04d", t.Year()) },
+	'y': func(t time.Time) string { return fmt.Sprintf("%02d", t.Year()%100) },
+	'm': func(t time.Time) string { return fmt.Sprintf("%02d", int(t.Month())) },
+	'd': func(t time.Time) string { return fmt.Sprintf("%02d", t.Day()) },
+	'j': func(t time.Time) string { return fmt.Sprintf("%03d", t.YearDay()) },
+	'H': func(t time.Time) string { return fmt.Sprintf("%02d", t.Hour()) },
+	'M': func(t time.Time) string { return fmt.Sprintf("%02d", t.Minute()) },
+	'S': func(t time.Time) string { return fmt.Sprintf("%02d", t.Second()) },
+}
+
+func parsePathTemplate(template string) (*pathTemplate, error) {
+	t := &pathTemplate{}
+	var literal strings.Builder
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			t.parts = append(t.parts, templatePart{kind: templatePartLiteral, value: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(template); i++ {
+		switch c := template[i]; c {
+		case '%':
+			if i+1 == len(template) {
+				return nil, fmt.Errorf("path ends with an incomplete time verb")
+			}
+			i++
+			verb := template[i]
+			if verb == '%' {
+				literal.WriteByte('%')
+				continue
+			}
+			if _, ok := timeVerbs[verb]; !ok {
+				return nil, fmt.Errorf("unsupported time verb %%%c in path", verb)
+			}
+			flushLiteral()
+			t.parts = append(t.parts, templatePart{kind: templatePartTime, value: string(verb)})
+		case '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated placeholder in path at position %d", i)
+			}
+			placeholder := template[i+1 : i+end]
+			i += end
+			flushLiteral()
+			switch {
+			case placeholder == "signal":
+				t.parts = append(t.parts, templatePart{kind: templatePartSignal})
+			case strings.HasPrefix(placeholder, resourceAttributePrefix) && len(placeholder) > len(resourceAttributePrefix):
+				t.parts = append(t.parts, templatePart{kind: templatePartResourceAttribute, value: strings.TrimPrefix(placeholder, resourceAttributePrefix)})
+			default:
+				return nil, fmt.Errorf("unsupported placeholder {%s} in path", placeholder)
+			}
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flushLiteral()
+	return t, nil
+}
+
+// render returns the path for the given time, resource and signal type.
+func (t *pathTemplate) render(now time.Time, resource pcommon.Resource, signal string) string {
+	var sb strings.Builder
+	for _, part := range t.parts {
+		switch part.kind {
+		case templatePartLiteral:
+			sb.WriteString(part.value)
+		case templatePartTime:
+			sb.WriteString(timeVerbs[part.value[0]](now))
+		case templatePartSignal:
+			sb.WriteString(signal)
+		case templatePartResourceAttribute:
+			value := missingAttributeValue
+			if v, ok := resource.Attributes().Get(part.value); ok {
+				value = v.AsString()
+			}
+			sb.WriteString(sanitizePathSegment(value))
+		}
+	}
+	return sb.String()
+}
+
+// renderTime returns the time dependent parts of the path only. Two times belong to
+// the same partition of the path if they render the same.
+func (t *pathTemplate) renderTime(now time.Time) string {
+	var sb strings.Builder
+	for _, part := range t.parts {
+		if part.kind == templatePartTime {
+			sb.WriteString(timeVerbs[part.value[0]](now))
+		}
+	}
+	return sb.String()
+}
+
+// hasTime tells whether the path contains time verbs.
+func (t *pathTemplate) hasTime() bool {
+	for _, part := range t.parts {
+		if part.kind == templatePartTime {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizePathSegment prevents attribute values from adding directories to the path
+// or traversing outside of the configured directory.
+func sanitizePathSegment(value string) string {
+	value = strings.NewReplacer("/", "_", "\\", "_").Replace(value)
+	if value == "" || value == "." || value == ".." {
+		return "_"
+	}
+	return value
+}
+
+// sequencedPath returns the path of the n-th file written to a partition, inserting
+// the sequence number before the extension of the file.
+func sequencedPath(path string, n int) string {
+	if n == 0 {
+		return path
+	}
+	return insertBeforeExtension(path, strconv.Itoa(n))
+}
+
+// insertBeforeExtension inserts value, separated by a dot, before the extension of the file.
+func insertBeforeExtension(path string, value string) string {
+	dir, base := filepath.Split(path)
+	if i := strings.IndexByte(base, '.'); i > 0 {
+		return dir + base[:i] + "." + value + base[i:]
+	}
+	return dir + base + "." + value
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestPathTemplateRender(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "checkout")
+	resource.Attributes().PutStr("tenant", "../../etc")
+	resource.Attributes().PutInt("shard", 3)
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{
+			template: "/data/%Y/%m/%d/%H/%M/%S/{signal}.json",
+			expected: "/data/2024/03/05/07/08/09/traces.json",
+		},
+		{
+			template: "/data/%y-%j/100%%/{signal}",
+			expected: "/data/24-065/100%/traces",
+		},
+		{
+			template: "/data/{resource:service.name}/{resource:shard}/{signal}.json",
+			expected: "/data/checkout/3/traces.json",
+		},
+		{
+			template: "/data/{resource:tenant}/{resource:missing}.json",
+			expected: "/data/.._.._etc/unknown.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			template, err := parsePathTemplate(tt.template)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, template.render(now, resource, signalTraces))
+		})
+	}
+}
+
+func TestPathTemplateErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		errMsg   string
+	}{
+		{template: "/data/%", errMsg: "path ends with an incomplete time verb"},
+		{template: "/data/%Q", errMsg: "unsupported time verb %Q in path"},
+		{template: "/data/{signal", errMsg: "unterminated placeholder in path at position 6"},
+		{template: "/data/{resource:}", errMsg: "unsupported placeholder {resource:} in path"},
+		{template: "/data/{host}", errMsg: "unsupported placeholder {host} in path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := parsePathTemplate(tt.template)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestPathTemplateRenderTime(t *testing.T) {
+	template, err := parsePathTemplate("/data/%Y/%m/{signal}-%H.json")
+	require.NoError(t, err)
+	assert.True(t, template.hasTime())
+	assert.Equal(t, "20240307", template.renderTime(time.Date(2024, time.March, 5, 7, 8, 9, 0, time.UTC)))
+
+	template, err = parsePathTemplate("/data/{signal}.json")
+	require.NoError(t, err)
+	assert.False(t, template.hasTime())
+	assert.Empty(t, template.renderTime(time.Now()))
+}
+
+func TestSequencedPath(t *testing.T) {
+	assert.Equal(t, "/data/traces.json", sequencedPath("/data/traces.json", 0))
+	assert.Equal(t, "/data/traces.2.json", sequencedPath("/data/traces.json", 2))
+	assert.Equal(t, "/data/traces.1.json.zst", sequencedPath("/data/traces.json.zst", 1))
+	assert.Equal(t, "/data.d/traces.1", sequencedPath("/data.d/traces", 1))
+	assert.Equal(t, "/data/.hidden.1", sequencedPath("/data/.hidden", 1))
+}
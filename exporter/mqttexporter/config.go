@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/mqttexporter"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+// Config defines configuration for the MQTT exporter.
+type Config struct {
+	mqtt.ClientConfig              `mapstructure:",squash"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	configretry.BackOffConfig      `mapstructure:"retry_on_failure"`
+
+	// Topic is the template of the topic the messages are published to. It supports the
+	// {resource:<name>} and {signal} placeholders, the data of the resources rendering
+	// different topics are published in separate messages.
+	Topic string `mapstructure:"topic"`
+
+	// QoS is the quality of service of the published messages, 0, 1 or 2.
+	QoS byte `mapstructure:"qos"`
+
+	// Retain tells the broker to keep the last message of each topic for the future subscribers.
+	Retain bool `mapstructure:"retain"`
+
+	// FormatType is the OTLP encoding of the payloads of the messages, json or proto.
+	FormatType string `mapstructure:"format"`
+
+	// Encoding is the ID of the encoding extension encoding the payloads of the messages.
+	// If specified, it overrides `FormatType`.
+	Encoding *component.ID `mapstructure:"encoding"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the exporter configuration is valid.
+func (cfg *Config) Validate() error {
+	var errs error
+	if _, err := parseTopicTemplate(cfg.Topic); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.QoS > 2 {
+		errs = errors.Join(errs, errors.New("qos must be 0, 1 or 2"))
+	}
+	if cfg.FormatType != messaging.FormatTypeJSON && cfg.FormatType != messaging.FormatTypeProto {
+		errs = errors.Join(errs, fmt.Errorf("format %q is not supported", cfg.FormatType))
+	}
+	return errs
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/mqttexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	clientConfig := mqtt.NewDefaultClientConfig()
+	clientConfig.Brokers = []string{"mqtts://broker:8883"}
+	clientConfig.ClientID = "collector-1"
+	clientConfig.Username = "collector"
+	clientConfig.Password = "secret"
+	clientConfig.CleanSession = false
+	encoding := component.MustNewID("text_encoding")
+
+	tests := []struct {
+		id          component.ID
+		expected    component.Config
+		expectedErr string
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expected: &Config{
+				ClientConfig:    clientConfig,
+				TimeoutSettings: exporterhelper.TimeoutSettings{Timeout: 10 * time.Second},
+				QueueSettings: exporterhelper.QueueSettings{
+					Enabled:      true,
+					NumConsumers: 2,
+					QueueSize:    100,
+				},
+				BackOffConfig: func() configretry.BackOffConfig {
+					cfg := configretry.NewDefaultBackOffConfig()
+					cfg.Enabled = false
+					return cfg
+				}(),
+				Topic:      "telemetry/{resource:service.name}/{signal}",
+				QoS:        2,
+				Retain:     true,
+				FormatType: messaging.FormatTypeProto,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "encoding"),
+			expected: func() component.Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Topic = "devices/{resource:host.name}/logs"
+				cfg.Encoding = &encoding
+				return cfg
+			}(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "invalid"),
+			expectedErr: "topic can't contain the wildcard '#'\n" +
+				"qos must be 0, 1 or 2\n" +
+				"format \"xml\" is not supported; " +
+				"at least one broker must be specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
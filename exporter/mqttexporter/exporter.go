@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/mqttexporter"
+
+import (
+	"context"
+	"fmt"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+// mqttExporter publishes the data of a signal to the topics rendered from their resources.
+type mqttExporter struct {
+	settings exporter.Settings
+	config   *Config
+	signal   string
+	topic    *messaging.Template
+
+	logsMarshaler    plog.Marshaler
+	metricsMarshaler pmetric.Marshaler
+	tracesMarshaler  ptrace.Marshaler
+	conn             *mqtt.Connection
+}
+
+func newMQTTExporter(set exporter.Settings, cfg *Config, signal string) (*mqttExporter, error) {
+	topic, err := parseTopicTemplate(cfg.Topic)
+	if err != nil {
+		return nil, err
+	}
+	return &mqttExporter{
+		settings: set,
+		config:   cfg,
+		signal:   signal,
+		topic:    topic,
+	}, nil
+}
+
+func (e *mqttExporter) start(ctx context.Context, host component.Host) error {
+	if err := e.loadMarshaler(host); err != nil {
+		return err
+	}
+	opts, err := e.config.NewClientOptions(ctx)
+	if err != nil {
+		return err
+	}
+	opts.SetConnectionLostHandler(func(_ paho.Client, err error) {
+		e.settings.Logger.Warn("Lost the connection to the MQTT broker", zap.Error(err))
+	})
+	e.conn = mqtt.NewConnection(paho.NewClient(opts), e.settings.Logger)
+	e.conn.Start()
+	return nil
+}
+
+// loadMarshaler loads the marshaler of the signal, from the encoding extension of the
+// configuration if set.
+func (e *mqttExporter) loadMarshaler(host component.Host) error {
+	var err error
+	switch e.signal {
+	case messaging.SignalLogs:
+		e.logsMarshaler, err = messaging.LoadLogsMarshaler(host, e.config.Encoding, e.config.FormatType)
+	case messaging.SignalMetrics:
+		e.metricsMarshaler, err = messaging.LoadMetricsMarshaler(host, e.config.Encoding, e.config.FormatType)
+	case messaging.SignalTraces:
+		e.tracesMarshaler, err = messaging.LoadTracesMarshaler(host, e.config.Encoding, e.config.FormatType)
+	}
+	return err
+}
+
+func (e *mqttExporter) shutdown(context.Context) error {
+	if e.conn != nil {
+		e.conn.Close()
+	}
+	return nil
+}
+
+func (e *mqttExporter) consumeLogs(ctx context.Context, ld plog.Logs) error {
+	return messaging.PublishLogs(ctx, ld, e.topic, e.logsMarshaler, e.publish)
+}
+
+func (e *mqttExporter) consumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return messaging.PublishMetrics(ctx, md, e.topic, e.metricsMarshaler, e.publish)
+}
+
+func (e *mqttExporter) consumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return messaging.PublishTraces(ctx, td, e.topic, e.tracesMarshaler, e.publish)
+}
+
+// publish publishes a message, and waits for its acknowledgement by the broker for the QoS 1 and 2.
+func (e *mqttExporter) publish(ctx context.Context, topic string, payload []byte) error {
+	if !e.conn.IsConnectionOpen() {
+		return fmt.Errorf("failed to publish to topic %q: not connected to the MQTT broker", topic)
+	}
+	token := e.conn.Publish(topic, e.config.QoS, e.config.Retain, payload)
+	select {
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("failed to publish to topic %q: %w", topic, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to publish to topic %q: %w", topic, ctx.Err())
+	}
+}
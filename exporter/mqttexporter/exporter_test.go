@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/textencodingextension"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging/messagingtest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt/mqtttest"
+)
+
+func newTestConfig(broker string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{broker}
+	cfg.QueueSettings.Enabled = false
+	cfg.BackOffConfig.Enabled = false
+	return cfg
+}
+
+func TestExportLogs(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	messages := broker.Subscribe(t, "#")
+	cfg := newTestConfig(broker.Endpoint)
+	cfg.Topic = "devices/{resource:host.name}/{signal}"
+	cfg.Retain = true
+	exp, err := NewFactory().CreateLogsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, componenttest.NewNopHost())
+
+	// The resources of the same host are published in a single message.
+	ld := plog.NewLogs()
+	for _, host := range []string{"gateway-1", "gateway-2", "gateway-1"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("host.name", host)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("started " + host)
+	}
+	messagingtest.Export(t, func() error { return exp.ConsumeLogs(context.Background(), ld) })
+
+	received := map[string]plog.Logs{}
+	for i := 0; i < 2; i++ {
+		msg := messagingtest.Receive(t, messages)
+		assert.Equal(t, byte(1), msg.QoS)
+		assert.True(t, msg.Retain)
+		logs, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(msg.Payload)
+		require.NoError(t, err)
+		received[msg.Topic] = logs
+	}
+	require.Len(t, received, 2)
+	assert.Equal(t, 2, received["devices/gateway-1/logs"].ResourceLogs().Len())
+	assert.Equal(t, 1, received["devices/gateway-2/logs"].ResourceLogs().Len())
+	assert.Equal(t, "started gateway-2",
+		received["devices/gateway-2/logs"].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
+
+func TestExportMetricsProto(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	messages := broker.Subscribe(t, "#")
+	cfg := newTestConfig(broker.Endpoint)
+	cfg.FormatType = messaging.FormatTypeProto
+	cfg.QoS = 2
+	exp, err := NewFactory().CreateMetricsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, componenttest.NewNopHost())
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("temperature")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(21.5)
+	messagingtest.Export(t, func() error { return exp.ConsumeMetrics(context.Background(), md) })
+
+	msg := messagingtest.Receive(t, messages)
+	assert.Equal(t, "otlp/metrics", msg.Topic)
+	assert.Equal(t, byte(2), msg.QoS)
+	assert.False(t, msg.Retain)
+	received, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(msg.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, md, received)
+}
+
+func TestExportTraces(t *testing.T) {
+	broker := mqtttest.NewBroker(t, mqtttest.WithUser("collector", "secret"))
+	messages := broker.Subscribe(t, "#")
+	cfg := newTestConfig(broker.Endpoint)
+	cfg.Username, cfg.Password = "collector", "secret"
+	cfg.Topic = "services/{resource:service.name}"
+	exp, err := NewFactory().CreateTracesExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, componenttest.NewNopHost())
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("checkout")
+	messagingtest.Export(t, func() error { return exp.ConsumeTraces(context.Background(), td) })
+
+	msg := messagingtest.Receive(t, messages)
+	assert.Equal(t, "services/unknown", msg.Topic)
+	received, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(msg.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, td, received)
+}
+
+func TestExportWithEncodingExtension(t *testing.T) {
+	factory := textencodingextension.NewFactory()
+	ext, err := factory.CreateExtension(context.Background(), extensiontest.NewNopSettings(), factory.CreateDefaultConfig())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, ext.Shutdown(context.Background()))
+	})
+	id := component.MustNewID("text_encoding")
+	host := messagingtest.NewHost(map[component.ID]component.Component{id: ext})
+
+	broker := mqtttest.NewBroker(t)
+	messages := broker.Subscribe(t, "#")
+	cfg := newTestConfig(broker.Endpoint)
+	cfg.Encoding = &id
+	exp, err := NewFactory().CreateLogsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, host)
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("door opened")
+	messagingtest.Export(t, func() error { return exp.ConsumeLogs(context.Background(), ld) })
+	msg := messagingtest.Receive(t, messages)
+	assert.Equal(t, "otlp/logs", msg.Topic)
+	assert.Equal(t, "door opened", string(msg.Payload))
+
+	// The text encoding doesn't marshal traces.
+	traces, err := NewFactory().CreateTracesExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	assert.EqualError(t, traces.Start(context.Background(), host), `encoding "text_encoding" can't marshal traces`)
+	assert.NoError(t, traces.Shutdown(context.Background()))
+}
+
+func TestExportNotConnected(t *testing.T) {
+	// The port of the closed listener refuses the connections.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	cfg := newTestConfig("tcp://" + ln.Addr().String())
+	exp, err := NewFactory().CreateLogsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, componenttest.NewNopHost())
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty()
+	assert.EqualError(t, exp.ConsumeLogs(context.Background(), ld),
+		`failed to publish to topic "otlp/logs": not connected to the MQTT broker`)
+}
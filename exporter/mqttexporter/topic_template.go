@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/mqttexporter"
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+)
+
+// topicSyntax rejects the wildcards of the topic filters in the topics, and keeps the attribute
+// values within a single topic level.
+var topicSyntax = messaging.Syntax{
+	Kind: "topic",
+	ValidateLiteral: func(c byte) error {
+		if c == '+' || c == '#' {
+			return fmt.Errorf("topic can't contain the wildcard %q", c)
+		}
+		return nil
+	},
+	Sanitize: sanitizeTopicLevel,
+}
+
+// parseTopicTemplate parses the template of the topics, see messaging.Template for the placeholders.
+func parseTopicTemplate(template string) (*messaging.Template, error) {
+	return messaging.ParseTemplate(template, topicSyntax)
+}
+
+// sanitizeTopicLevel prevents attribute values from adding levels or wildcards to the topic.
+func sanitizeTopicLevel(value string) string {
+	value = strings.NewReplacer("/", "_", "+", "_", "#", "_", "\x00", "_").Replace(value)
+	if value == "" {
+		return "_"
+	}
+	return value
+}
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+)
+
+func TestTopicTemplateRender(t *testing.T) {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "checkout")
+	resource.Attributes().PutStr("host.name", "site/a+b#c")
+	resource.Attributes().PutStr("empty", "")
+	resource.Attributes().PutInt("device.id", 42)
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "otlp/{signal}", expected: "otlp/logs"},
+		{template: "services/{resource:service.name}/{signal}", expected: "services/checkout/logs"},
+		{template: "{resource:host.name}/{resource:device.id}", expected: "site_a_b_c/42"},
+		{template: "devices/{resource:device.name}", expected: "devices/unknown"},
+		{template: "devices/{resource:empty}/status", expected: "devices/_/status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			template, err := parseTopicTemplate(tt.template)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, template.Render(resource, messaging.SignalLogs))
+		})
+	}
+}
+
+func TestTopicTemplateErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "", expected: "topic must be specified"},
+		{template: "otlp/+/{signal}", expected: "topic can't contain the wildcard '+'"},
+		{template: "otlp/#", expected: "topic can't contain the wildcard '#'"},
+		{template: "otlp/{signal", expected: "unterminated placeholder in topic at position 5"},
+		{template: "otlp/{resource:}", expected: "unsupported placeholder {resource:} in topic"},
+		{template: "otlp/{time}", expected: "unsupported placeholder {time} in topic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := parseTopicTemplate(tt.template)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/natsexporter"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	internalnats "github.com/open-telemetry/opentelemetry-collector-contrib/internal/nats"
+)
+
+// Config defines configuration for the NATS exporter.
+type Config struct {
+	internalnats.ClientConfig      `mapstructure:",squash"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	configretry.BackOffConfig      `mapstructure:"retry_on_failure"`
+
+	// Subject is the template of the subject the messages are published to. It supports the
+	// {resource:<name>} and {signal} placeholders, the data of the resources rendering
+	// different subjects are published in separate messages.
+	Subject string `mapstructure:"subject"`
+
+	// JetStream publishes the messages to JetStream, and waits for their acknowledgement by the
+	// stream storing their subject.
+	JetStream bool `mapstructure:"jetstream"`
+
+	// FormatType is the OTLP encoding of the payloads of the messages, json or proto.
+	FormatType string `mapstructure:"format"`
+
+	// Encoding is the ID of the encoding extension encoding the payloads of the messages.
+	// If specified, it overrides `FormatType`.
+	Encoding *component.ID `mapstructure:"encoding"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the exporter configuration is valid.
+func (cfg *Config) Validate() error {
+	var errs error
+	if _, err := parseSubjectTemplate(cfg.Subject); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.FormatType != messaging.FormatTypeJSON && cfg.FormatType != messaging.FormatTypeProto {
+		errs = errors.Join(errs, fmt.Errorf("format %q is not supported", cfg.FormatType))
+	}
+	return errs
+}
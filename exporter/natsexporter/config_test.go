@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsexporter
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/natsexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	internalnats "github.com/open-telemetry/opentelemetry-collector-contrib/internal/nats"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	clientConfig := internalnats.NewDefaultClientConfig()
+	clientConfig.Servers = []string{"tls://nats:4222"}
+	clientConfig.Name = "collector-1"
+	clientConfig.Auth.Token = "secret"
+	encoding := component.MustNewID("text_encoding")
+
+	tests := []struct {
+		id          component.ID
+		expected    component.Config
+		expectedErr string
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expected: &Config{
+				ClientConfig:    clientConfig,
+				TimeoutSettings: exporterhelper.TimeoutSettings{Timeout: 10 * time.Second},
+				QueueSettings: exporterhelper.QueueSettings{
+					Enabled:      true,
+					NumConsumers: 2,
+					QueueSize:    100,
+				},
+				BackOffConfig: func() configretry.BackOffConfig {
+					cfg := configretry.NewDefaultBackOffConfig()
+					cfg.Enabled = false
+					return cfg
+				}(),
+				Subject:    "telemetry.{resource:service.name}.{signal}",
+				JetStream:  true,
+				FormatType: messaging.FormatTypeProto,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "encoding"),
+			expected: func() component.Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Subject = "devices.{resource:host.name}.logs"
+				cfg.Encoding = &encoding
+				return cfg
+			}(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "invalid"),
+			expectedErr: "subject can't contain the wildcard '>'\n" +
+				"format \"xml\" is not supported; " +
+				"at least one server must be specified\n" +
+				"auth password requires a username",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/natsexporter"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+)
+
+// flushTimeout is the timeout of the flush of the messages buffered by the connection on shutdown.
+const flushTimeout = 5 * time.Second
+
+// natsExporter publishes the data of a signal to the subjects rendered from their resources.
+type natsExporter struct {
+	settings exporter.Settings
+	config   *Config
+	signal   string
+	subject  *messaging.Template
+
+	logsMarshaler    plog.Marshaler
+	metricsMarshaler pmetric.Marshaler
+	tracesMarshaler  ptrace.Marshaler
+	conn             *nats.Conn
+	js               jetstream.JetStream
+}
+
+func newNATSExporter(set exporter.Settings, cfg *Config, signal string) (*natsExporter, error) {
+	subject, err := parseSubjectTemplate(cfg.Subject)
+	if err != nil {
+		return nil, err
+	}
+	return &natsExporter{
+		settings: set,
+		config:   cfg,
+		signal:   signal,
+		subject:  subject,
+	}, nil
+}
+
+func (e *natsExporter) start(ctx context.Context, host component.Host) error {
+	if err := e.loadMarshaler(host); err != nil {
+		return err
+	}
+	var err error
+	if e.conn, err = e.config.Connect(ctx, e.settings.Logger); err != nil {
+		return err
+	}
+	if e.config.JetStream {
+		e.js, err = jetstream.New(e.conn)
+	}
+	return err
+}
+
+// loadMarshaler loads the marshaler of the signal, from the encoding extension of the
+// configuration if set.
+func (e *natsExporter) loadMarshaler(host component.Host) error {
+	var err error
+	switch e.signal {
+	case messaging.SignalLogs:
+		e.logsMarshaler, err = messaging.LoadLogsMarshaler(host, e.config.Encoding, e.config.FormatType)
+	case messaging.SignalMetrics:
+		e.metricsMarshaler, err = messaging.LoadMetricsMarshaler(host, e.config.Encoding, e.config.FormatType)
+	case messaging.SignalTraces:
+		e.tracesMarshaler, err = messaging.LoadTracesMarshaler(host, e.config.Encoding, e.config.FormatType)
+	}
+	return err
+}
+
+func (e *natsExporter) shutdown(context.Context) error {
+	if e.conn == nil {
+		return nil
+	}
+	if e.conn.IsConnected() {
+		// The messages buffered by the connection are flushed before it's closed.
+		if err := e.conn.FlushTimeout(flushTimeout); err != nil {
+			e.settings.Logger.Warn("Failed to flush the messages to the NATS server", zap.Error(err))
+		}
+	}
+	e.conn.Close()
+	return nil
+}
+
+func (e *natsExporter) consumeLogs(ctx context.Context, ld plog.Logs) error {
+	return messaging.PublishLogs(ctx, ld, e.subject, e.logsMarshaler, e.publish)
+}
+
+func (e *natsExporter) consumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return messaging.PublishMetrics(ctx, md, e.subject, e.metricsMarshaler, e.publish)
+}
+
+func (e *natsExporter) consumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return messaging.PublishTraces(ctx, td, e.subject, e.tracesMarshaler, e.publish)
+}
+
+// publish publishes a message. With JetStream, it waits for the acknowledgement of the stream
+// storing the subject.
+func (e *natsExporter) publish(ctx context.Context, subject string, payload []byte) error {
+	if e.js != nil {
+		if _, err := e.js.Publish(ctx, subject, payload); err != nil {
+			return fmt.Errorf("failed to publish to subject %q: %w", subject, err)
+		}
+		return nil
+	}
+	// The connection buffers the messages while reconnecting, they are lost if it fails to
+	// reconnect, so the data is retried instead.
+	if !e.conn.IsConnected() {
+		return fmt.Errorf("failed to publish to subject %q: not connected to the NATS server", subject)
+	}
+	if err := e.conn.Publish(subject, payload); err != nil {
+		return fmt.Errorf("failed to publish to subject %q: %w", subject, err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsexporter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/textencodingextension"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging/messagingtest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/nats/natstest"
+)
+
+func newTestConfig(server string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Servers = []string{server}
+	cfg.ReconnectWait = 10 * time.Millisecond
+	cfg.QueueSettings.Enabled = false
+	cfg.BackOffConfig.Enabled = false
+	return cfg
+}
+
+func TestExportLogs(t *testing.T) {
+	server := natstest.NewServer(t)
+	messages := server.Subscribe(t, ">")
+	cfg := newTestConfig(server.URL)
+	cfg.Subject = "devices.{resource:host.name}.{signal}"
+	exp, err := NewFactory().CreateLogsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, componenttest.NewNopHost())
+
+	// The resources of the same host are published in a single message.
+	ld := plog.NewLogs()
+	for _, host := range []string{"gateway-1", "gateway.2", "gateway-1"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("host.name", host)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("started " + host)
+	}
+	messagingtest.Export(t, func() error { return exp.ConsumeLogs(context.Background(), ld) })
+
+	received := map[string]plog.Logs{}
+	for i := 0; i < 2; i++ {
+		msg := messagingtest.Receive(t, messages)
+		logs, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(msg.Data)
+		require.NoError(t, err)
+		received[msg.Subject] = logs
+	}
+	require.Len(t, received, 2)
+	assert.Equal(t, 2, received["devices.gateway-1.logs"].ResourceLogs().Len())
+	assert.Equal(t, 1, received["devices.gateway_2.logs"].ResourceLogs().Len())
+	assert.Equal(t, "started gateway.2",
+		received["devices.gateway_2.logs"].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
+
+func TestExportMetricsProto(t *testing.T) {
+	server := natstest.NewServer(t)
+	messages := server.Subscribe(t, ">")
+	cfg := newTestConfig(server.URL)
+	cfg.FormatType = messaging.FormatTypeProto
+	exp, err := NewFactory().CreateMetricsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, componenttest.NewNopHost())
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("temperature")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(21.5)
+	messagingtest.Export(t, func() error { return exp.ConsumeMetrics(context.Background(), md) })
+
+	msg := messagingtest.Receive(t, messages)
+	assert.Equal(t, "otlp.metrics", msg.Subject)
+	received, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(msg.Data)
+	require.NoError(t, err)
+	assert.Equal(t, md, received)
+}
+
+func TestExportTraces(t *testing.T) {
+	server := natstest.NewServer(t, natstest.WithUser("collector", "secret"))
+	messages := server.Subscribe(t, ">")
+	cfg := newTestConfig(server.URL)
+	cfg.Auth.Username, cfg.Auth.Password = "collector", "secret"
+	cfg.Subject = "services.{resource:service.name}"
+	exp, err := NewFactory().CreateTracesExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, componenttest.NewNopHost())
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("checkout")
+	messagingtest.Export(t, func() error { return exp.ConsumeTraces(context.Background(), td) })
+
+	msg := messagingtest.Receive(t, messages)
+	assert.Equal(t, "services.unknown", msg.Subject)
+	received, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(msg.Data)
+	require.NoError(t, err)
+	assert.Equal(t, td, received)
+}
+
+func TestExportJetStream(t *testing.T) {
+	server := natstest.NewServer(t)
+	stream := server.CreateStream(t, "TELEMETRY", "telemetry.>")
+	cfg := newTestConfig(server.URL)
+	cfg.Subject = "telemetry.{signal}"
+	cfg.JetStream = true
+	exp, err := NewFactory().CreateLogsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, componenttest.NewNopHost())
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("stored")
+	messagingtest.Export(t, func() error { return exp.ConsumeLogs(context.Background(), ld) })
+
+	// The export succeeds once the stream stored the message.
+	msg, err := stream.GetLastMsgForSubject(context.Background(), "telemetry.logs")
+	require.NoError(t, err)
+	received, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(msg.Data)
+	require.NoError(t, err)
+	assert.Equal(t, ld, received)
+
+	// No stream stores the subject, the server doesn't acknowledge the message.
+	cfg.Subject = "other.{signal}"
+	other, err := NewFactory().CreateLogsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, other, componenttest.NewNopHost())
+	assert.ErrorContains(t, other.ConsumeLogs(context.Background(), ld), `failed to publish to subject "other.logs"`)
+}
+
+func TestExportWithEncodingExtension(t *testing.T) {
+	factory := textencodingextension.NewFactory()
+	ext, err := factory.CreateExtension(context.Background(), extensiontest.NewNopSettings(), factory.CreateDefaultConfig())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, ext.Shutdown(context.Background()))
+	})
+	id := component.MustNewID("text_encoding")
+	host := messagingtest.NewHost(map[component.ID]component.Component{id: ext})
+
+	server := natstest.NewServer(t)
+	messages := server.Subscribe(t, ">")
+	cfg := newTestConfig(server.URL)
+	cfg.Encoding = &id
+	exp, err := NewFactory().CreateLogsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, host)
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("door opened")
+	messagingtest.Export(t, func() error { return exp.ConsumeLogs(context.Background(), ld) })
+	msg := messagingtest.Receive(t, messages)
+	assert.Equal(t, "otlp.logs", msg.Subject)
+	assert.Equal(t, "door opened", string(msg.Data))
+
+	// The text encoding doesn't marshal traces.
+	traces, err := NewFactory().CreateTracesExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	assert.EqualError(t, traces.Start(context.Background(), host), `encoding "text_encoding" can't marshal traces`)
+	assert.NoError(t, traces.Shutdown(context.Background()))
+}
+
+func TestExportNotConnected(t *testing.T) {
+	// The port of the closed listener refuses the connections.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	cfg := newTestConfig("nats://" + ln.Addr().String())
+	exp, err := NewFactory().CreateLogsExporter(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	messagingtest.Start(t, exp, componenttest.NewNopHost())
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty()
+	assert.EqualError(t, exp.ConsumeLogs(context.Background(), ld),
+		`failed to publish to subject "otlp.logs": not connected to the NATS server`)
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/natsexporter"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+)
+
+// subjectSyntax rejects the wildcards and whitespaces in the subjects, and keeps the attribute
+// values within a single token.
+var subjectSyntax = messaging.Syntax{
+	Kind: "subject",
+	ValidateLiteral: func(c byte) error {
+		switch {
+		case c == '*' || c == '>':
+			return fmt.Errorf("subject can't contain the wildcard %q", c)
+		case unicode.IsSpace(rune(c)):
+			return errors.New("subject can't contain whitespaces")
+		}
+		return nil
+	},
+	Sanitize: sanitizeSubjectToken,
+}
+
+// parseSubjectTemplate parses the template of the subjects, see messaging.Template for the placeholders.
+func parseSubjectTemplate(template string) (*messaging.Template, error) {
+	t, err := messaging.ParseTemplate(template, subjectSyntax)
+	if err != nil {
+		return nil, err
+	}
+	// The placeholders never render empty tokens, so the tokens of the literals are checked
+	// with any value.
+	for _, token := range strings.Split(t.Render(pcommon.NewResource(), messaging.SignalLogs), ".") {
+		if token == "" {
+			return nil, fmt.Errorf("subject %q has an empty token", template)
+		}
+	}
+	return t, nil
+}
+
+// sanitizeSubjectToken prevents attribute values from adding tokens or wildcards to the subject.
+func sanitizeSubjectToken(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r == '.' || r == '*' || r == '>' || unicode.IsSpace(r) {
+			return '_'
+		}
+		return r
+	}, value)
+	if value == "" {
+		return "_"
+	}
+	return value
+}
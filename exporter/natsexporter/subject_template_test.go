@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+)
+
+func TestSubjectTemplateRender(t *testing.T) {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "checkout")
+	resource.Attributes().PutStr("host.name", "node-1.eu west>*")
+	resource.Attributes().PutStr("empty", "")
+	resource.Attributes().PutInt("device.id", 42)
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "otlp.{signal}", expected: "otlp.logs"},
+		{template: "services.{resource:service.name}.{signal}", expected: "services.checkout.logs"},
+		{template: "{resource:host.name}.{resource:device.id}", expected: "node-1_eu_west__.42"},
+		{template: "devices.{resource:device.name}", expected: "devices.unknown"},
+		{template: "devices.{resource:empty}.status", expected: "devices._.status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			template, err := parseSubjectTemplate(tt.template)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, template.Render(resource, messaging.SignalLogs))
+		})
+	}
+}
+
+func TestSubjectTemplateErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "", expected: "subject must be specified"},
+		{template: "otlp.*.{signal}", expected: "subject can't contain the wildcard '*'"},
+		{template: "otlp.>", expected: "subject can't contain the wildcard '>'"},
+		{template: "otlp {signal}", expected: "subject can't contain whitespaces"},
+		{template: "otlp..{signal}", expected: `subject "otlp..{signal}" has an empty token`},
+		{template: "otlp.{signal}.", expected: `subject "otlp.{signal}." has an empty token`},
+		{template: "otlp.{signal", expected: "unterminated placeholder in subject at position 5"},
+		{template: "otlp.{resource:}", expected: "unsupported placeholder {resource:} in subject"},
+		{template: "otlp.{time}", expected: "unsupported placeholder {time} in subject"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := parseSubjectTemplate(tt.template)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
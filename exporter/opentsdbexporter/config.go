@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opentsdbexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opentsdbexporter"
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
+)
+
+// Config defines configuration for the OpenTSDB exporter.
+type Config struct {
+	// HTTP configures the export with the HTTP API, to the /api/put endpoint. It is used
+	// by default.
+	HTTP *HTTPConfig `mapstructure:"http"`
+
+	// Telnet configures the export with the telnet-style API, sending put commands over
+	// TCP. It is exclusive with HTTP.
+	Telnet *confignet.TCPAddrConfig `mapstructure:"telnet"`
+
+	// Timeout is the maximum duration allowed to send the data to OpenTSDB. The default
+	// value is 5s.
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`     // squash ensures fields are correctly decoded in embedded struct.
+	QueueConfig                    exporterhelper.QueueSettings `mapstructure:"sending_queue"`
+	RetryConfig                    configretry.BackOffConfig    `mapstructure:"retry_on_failure"`
+
+	// ResourceToTelemetrySettings defines configuration for converting resource attributes to metric labels.
+	ResourceToTelemetryConfig resourcetotelemetry.Settings `mapstructure:"resource_to_telemetry_conversion"`
+
+	// Naming defines the normalization of the metric names and tag keys, before they are
+	// sanitized following the OpenTSDB rules.
+	Naming metricnaming.Config `mapstructure:"naming"`
+}
+
+// HTTPConfig defines the configuration of the export with the HTTP API.
+type HTTPConfig struct {
+	confighttp.ClientConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// BatchSize is the maximum number of data points sent per request. The default value
+	// is 50, which keeps the requests below the 4KiB accepted by OpenTSDB when chunked
+	// requests aren't enabled.
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// Unmarshal clears the default HTTP configuration when only the telnet one is set.
+func (cfg *Config) Unmarshal(componentParser *confmap.Conf) error {
+	if componentParser == nil {
+		// Nothing to do if there is no config given.
+		return nil
+	}
+
+	if componentParser.IsSet("telnet") && !componentParser.IsSet("http") {
+		cfg.HTTP = nil
+	}
+
+	return componentParser.Unmarshal(cfg)
+}
+
+func (cfg *Config) Validate() error {
+	switch {
+	case cfg.HTTP == nil && cfg.Telnet == nil:
+		return errors.New("either 'http' or 'telnet' must be set")
+	case cfg.HTTP != nil && cfg.Telnet != nil:
+		return errors.New("only one of 'http' and 'telnet' can be set")
+	}
+
+	if cfg.HTTP != nil {
+		u, err := url.Parse(cfg.HTTP.Endpoint)
+		if err != nil {
+			return fmt.Errorf("exporter has an invalid HTTP endpoint: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("exporter has an invalid HTTP endpoint %q, the scheme must be http or https", cfg.HTTP.Endpoint)
+		}
+		if cfg.HTTP.BatchSize <= 0 {
+			return errors.New("'http::batch_size' must be positive")
+		}
+	}
+
+	if cfg.Telnet != nil {
+		// Resolve TCP address just to ensure that it is a valid one. It is better
+		// to fail here than at when the exporter is started.
+		if _, err := net.ResolveTCPAddr("tcp", cfg.Telnet.Endpoint); err != nil {
+			return fmt.Errorf("exporter has an invalid telnet endpoint: %w", err)
+		}
+	}
+
+	// Negative timeouts are not acceptable, since all sends will fail.
+	if cfg.Timeout < 0 {
+		return errors.New("'timeout' must be non-negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opentsdbexporter
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opentsdbexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id       component.ID
+		expected component.Config
+	}{
+		{
+			id:       component.NewIDWithName(metadata.Type, ""),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "http"),
+			expected: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.HTTP.Endpoint = "https://opentsdb.example.com:4242"
+				cfg.HTTP.BatchSize = 20
+				cfg.HTTP.Headers = map[string]configopaque.String{"X-Scope": "metrics"}
+				cfg.Timeout = 10 * time.Second
+				cfg.Naming = metricnaming.Config{
+					Normalization:     metricnaming.NormalizationPrometheus,
+					AddMetricSuffixes: false,
+				}
+				return cfg
+			}(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "telnet"),
+			expected: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.HTTP = nil
+				cfg.Telnet = &confignet.TCPAddrConfig{Endpoint: "localhost:4243"}
+				cfg.QueueConfig = exporterhelper.QueueSettings{
+					Enabled:      true,
+					NumConsumers: 2,
+					QueueSize:    10,
+				}
+				cfg.RetryConfig = configretry.BackOffConfig{
+					Enabled:             true,
+					InitialInterval:     10 * time.Second,
+					RandomizationFactor: 0.7,
+					Multiplier:          3.14,
+					MaxInterval:         1 * time.Minute,
+					MaxElapsedTime:      10 * time.Minute,
+				}
+				cfg.ResourceToTelemetryConfig = resourcetotelemetry.Settings{Enabled: true}
+				return cfg
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:   "default_config",
+			config: func(*Config) {},
+		},
+		{
+			name:    "no_api",
+			config:  func(cfg *Config) { cfg.HTTP = nil },
+			wantErr: "either 'http' or 'telnet' must be set",
+		},
+		{
+			name:    "both_apis",
+			config:  func(cfg *Config) { cfg.Telnet = &confignet.TCPAddrConfig{Endpoint: "localhost:4242"} },
+			wantErr: "only one of 'http' and 'telnet' can be set",
+		},
+		{
+			name:    "invalid_http_endpoint",
+			config:  func(cfg *Config) { cfg.HTTP.Endpoint = "localhost:4242" },
+			wantErr: `exporter has an invalid HTTP endpoint "localhost:4242", the scheme must be http or https`,
+		},
+		{
+			name:    "invalid_batch_size",
+			config:  func(cfg *Config) { cfg.HTTP.BatchSize = 0 },
+			wantErr: "'http::batch_size' must be positive",
+		},
+		{
+			name: "invalid_telnet_endpoint",
+			config: func(cfg *Config) {
+				cfg.HTTP = nil
+				cfg.Telnet = &confignet.TCPAddrConfig{Endpoint: "http://localhost:4242"}
+			},
+			wantErr: "exporter has an invalid telnet endpoint",
+		},
+		{
+			name:    "invalid_timeout",
+			config:  func(cfg *Config) { cfg.Timeout = -5 * time.Second },
+			wantErr: "'timeout' must be non-negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.config(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
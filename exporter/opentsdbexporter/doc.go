@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package opentsdbexporter exports metrics to OpenTSDB, with its HTTP API or its telnet-style API.
+package opentsdbexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opentsdbexporter"
@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opentsdbexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opentsdbexporter"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
+)
+
+// newOpenTSDBExporter returns a new OpenTSDB exporter.
+func newOpenTSDBExporter(ctx context.Context, cfg *Config, set exporter.Settings) (exporter.Metrics, error) {
+	var s sender
+	if cfg.Telnet != nil {
+		s = &telnetSender{
+			tcpConfig: *cfg.Telnet,
+			timeout:   cfg.Timeout,
+		}
+	} else {
+		s = &httpSender{
+			clientConfig: cfg.HTTP.ClientConfig,
+			putURL:       strings.TrimSuffix(cfg.HTTP.Endpoint, "/") + "/api/put?details",
+			batchSize:    cfg.HTTP.BatchSize,
+			settings:     set.TelemetrySettings,
+		}
+	}
+	exp := &openTSDBExporter{
+		logger:    set.Logger,
+		sanitizer: newSanitizer(cfg.Naming),
+		sender:    s,
+	}
+
+	opts := []exporterhelper.Option{
+		exporterhelper.WithQueue(cfg.QueueConfig),
+		exporterhelper.WithRetry(cfg.RetryConfig),
+		exporterhelper.WithStart(s.start),
+		exporterhelper.WithShutdown(s.shutdown),
+	}
+	if cfg.Telnet == nil {
+		// The telnet sender sets write deadlines instead, since the TCP connection
+		// does not accept writing with context.
+		opts = append(opts, exporterhelper.WithTimeout(cfg.TimeoutSettings))
+	}
+	metricsExp, err := exporterhelper.NewMetricsExporter(ctx, set, cfg, exp.pushMetricsData, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourcetotelemetry.WrapMetricsExporter(cfg.ResourceToTelemetryConfig, metricsExp), nil
+}
+
+// sender sends the data points to OpenTSDB with one of its APIs.
+type sender interface {
+	start(ctx context.Context, host component.Host) error
+	send(ctx context.Context, dps []dataPoint) error
+	shutdown(ctx context.Context) error
+}
+
+type openTSDBExporter struct {
+	logger    *zap.Logger
+	sanitizer *metricnaming.Sanitizer
+	sender    sender
+}
+
+func (e *openTSDBExporter) pushMetricsData(ctx context.Context, md pmetric.Metrics) error {
+	dps, dropped := metricDataToDataPoints(md, e.sanitizer)
+	if dropped > 0 {
+		e.logger.Warn("Dropped data points without tags, OpenTSDB requires at least one tag", zap.Int("dropped", dropped))
+	}
+	if len(dps) == 0 {
+		return nil
+	}
+	return e.sender.send(ctx, dps)
+}
+
+// httpSender sends the data points to the /api/put endpoint, in batches.
+type httpSender struct {
+	clientConfig confighttp.ClientConfig
+	putURL       string
+	batchSize    int
+	settings     component.TelemetrySettings
+	client       *http.Client
+}
+
+func (s *httpSender) start(ctx context.Context, host component.Host) error {
+	client, err := s.clientConfig.ToClient(ctx, host, s.settings)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	return nil
+}
+
+func (s *httpSender) send(ctx context.Context, dps []dataPoint) error {
+	for len(dps) > 0 {
+		batch := dps[:min(s.batchSize, len(dps))]
+		dps = dps[len(batch):]
+		if err := s.sendBatch(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putResponse is the response of the /api/put endpoint with the details parameter.
+type putResponse struct {
+	Failed  int `json:"failed"`
+	Success int `json:"success"`
+	Errors  []struct {
+		Error string `json:"error"`
+	} `json:"errors"`
+}
+
+func (s *httpSender) sendBatch(ctx context.Context, batch []dataPoint) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.putURL, bytes.NewReader(body))
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	respBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case res.StatusCode/100 == 2:
+		return nil
+	case res.StatusCode == http.StatusBadRequest:
+		// The data points OpenTSDB failed to parse or store are detailed in the response,
+		// the others were stored.
+		var resp putResponse
+		if err := json.Unmarshal(respBody, &resp); err == nil && len(resp.Errors) > 0 {
+			return consumererror.NewPermanent(fmt.Errorf("OpenTSDB failed to store %d of %d data points: %s", resp.Failed, resp.Failed+resp.Success, resp.Errors[0].Error))
+		}
+		return consumererror.NewPermanent(fmt.Errorf("OpenTSDB put returned %q %q", res.Status, string(respBody)))
+	case res.StatusCode/100 == 5, res.StatusCode == http.StatusTooManyRequests, res.StatusCode == http.StatusRequestTimeout:
+		return fmt.Errorf("OpenTSDB put returned %q %q", res.Status, string(respBody))
+	default:
+		return consumererror.NewPermanent(fmt.Errorf("OpenTSDB put returned %q %q", res.Status, string(respBody)))
+	}
+}
+
+func (s *httpSender) shutdown(context.Context) error {
+	if s.client != nil {
+		s.client.CloseIdleConnections()
+	}
+	return nil
+}
+
+// telnetSender sends the data points as put commands over a TCP connection, which is kept
+// open between the sends.
+type telnetSender struct {
+	tcpConfig confignet.TCPAddrConfig
+	timeout   time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (s *telnetSender) start(context.Context, component.Host) error {
+	return nil
+}
+
+func (s *telnetSender) send(_ context.Context, dps []dataPoint) error {
+	var buf bytes.Buffer
+	for _, dp := range dps {
+		writePutCommand(&buf, dp)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.DialTimeout("tcp", s.tcpConfig.Endpoint, s.timeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	// OpenTSDB only answers the put commands it fails to handle, the connection isn't read.
+	if s.timeout > 0 {
+		if err := s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+			return errors.Join(err, s.closeConn())
+		}
+	}
+	if _, err := s.conn.Write(buf.Bytes()); err != nil {
+		// The connection is closed, and opened again on the next send.
+		return errors.Join(err, s.closeConn())
+	}
+	return nil
+}
+
+func (s *telnetSender) closeConn() error {
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *telnetSender) shutdown(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	return s.closeConn()
+}
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opentsdbexporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+)
+
+func newTestExporter(t *testing.T, cfg *Config) func() {
+	cfg.QueueConfig.Enabled = false
+	cfg.RetryConfig.Enabled = false
+	exp, err := newOpenTSDBExporter(context.Background(), cfg, exportertest.NewNopSettings())
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	})
+	return func() {
+		require.NoError(t, exp.ConsumeMetrics(context.Background(), generateMetrics()))
+	}
+}
+
+func TestHTTPExport(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/put", r.URL.Path)
+		assert.True(t, r.URL.Query().Has("details"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var batch []map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = server.URL + "/"
+	cfg.HTTP.BatchSize = 4
+	consume := newTestExporter(t, cfg)
+	consume()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, batches, 3)
+	assert.Len(t, batches[0], 4)
+	assert.Len(t, batches[1], 4)
+	assert.Len(t, batches[2], 2)
+	assert.Equal(t, map[string]any{
+		"metric":    "system.cpu.load_average",
+		"timestamp": float64(1574092046011),
+		"value":     1.5,
+		"tags":      map[string]any{"host": "web-1_8080"},
+	}, batches[0][0])
+}
+
+func TestHTTPExportErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantErr       string
+		wantPermanent bool
+	}{
+		{
+			name:          "invalid_data_points",
+			status:        http.StatusBadRequest,
+			body:          `{"failed":1,"success":9,"errors":[{"datapoint":{},"error":"Unable to find UID for metric"}]}`,
+			wantErr:       "Permanent error: OpenTSDB failed to store 1 of 10 data points: Unable to find UID for metric",
+			wantPermanent: true,
+		},
+		{
+			name:          "bad_request",
+			status:        http.StatusBadRequest,
+			body:          "invalid",
+			wantErr:       `Permanent error: OpenTSDB put returned "400 Bad Request" "invalid"`,
+			wantPermanent: true,
+		},
+		{
+			name:          "not_found",
+			status:        http.StatusNotFound,
+			wantErr:       `Permanent error: OpenTSDB put returned "404 Not Found" ""`,
+			wantPermanent: true,
+		},
+		{
+			name:    "unavailable",
+			status:  http.StatusServiceUnavailable,
+			wantErr: `OpenTSDB put returned "503 Service Unavailable" ""`,
+		},
+		{
+			name:    "too_many_requests",
+			status:  http.StatusTooManyRequests,
+			wantErr: `OpenTSDB put returned "429 Too Many Requests" ""`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			s := &httpSender{
+				clientConfig: createDefaultConfig().(*Config).HTTP.ClientConfig,
+				putURL:       server.URL + "/api/put?details",
+				batchSize:    50,
+				settings:     componenttest.NewNopTelemetrySettings(),
+			}
+			require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				assert.NoError(t, s.shutdown(context.Background()))
+			}()
+
+			dps, _ := metricDataToDataPoints(generateMetrics(), newSanitizer(createDefaultConfig().(*Config).Naming))
+			err := s.send(context.Background(), dps)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantPermanent, consumererror.IsPermanent(err))
+		})
+	}
+}
+
+func TestTelnetExport(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 100)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		conn, err := ln.Accept()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTP = nil
+	cfg.Telnet = &confignet.TCPAddrConfig{Endpoint: ln.Addr().String()}
+	consume := newTestExporter(t, cfg)
+	// The connection is kept open between the sends.
+	consume()
+	consume()
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, "put system.cpu.load_average 1574092046011 1.5 host=web-1_8080", <-lines)
+		assert.Equal(t, "put http.server.requests 1574092046011 42 http.route=/users", <-lines)
+		for j := 0; j < 8; j++ {
+			<-lines
+		}
+	}
+	assert.Empty(t, lines)
+}
+
+func TestTelnetExportReconnects(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	s := &telnetSender{tcpConfig: confignet.TCPAddrConfig{Endpoint: addr}}
+	dps, _ := metricDataToDataPoints(generateMetrics(), newSanitizer(createDefaultConfig().(*Config).Naming))
+	assert.Error(t, s.send(context.Background(), dps))
+
+	ln, err = net.Listen("tcp", addr)
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}()
+	assert.NoError(t, s.send(context.Background(), dps))
+	assert.NoError(t, s.shutdown(context.Background()))
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opentsdbexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opentsdbexporter"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opentsdbexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
+)
+
+// Defaults for not specified configuration settings.
+const (
+	defaultHTTPEndpoint = "http://localhost:4242"
+	defaultBatchSize    = 50
+)
+
+// NewFactory creates a factory for the OpenTSDB exporter.
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		exporter.WithMetrics(createMetricsExporter, metadata.MetricsStability))
+}
+
+func createDefaultConfig() component.Config {
+	clientConfig := confighttp.NewDefaultClientConfig()
+	clientConfig.Endpoint = defaultHTTPEndpoint
+	return &Config{
+		HTTP: &HTTPConfig{
+			ClientConfig: clientConfig,
+			BatchSize:    defaultBatchSize,
+		},
+		TimeoutSettings: exporterhelper.NewDefaultTimeoutSettings(),
+		QueueConfig:     exporterhelper.NewDefaultQueueSettings(),
+		RetryConfig:     configretry.NewDefaultBackOffConfig(),
+		Naming:          metricnaming.NewDefaultConfig(),
+	}
+}
+
+func createMetricsExporter(
+	ctx context.Context,
+	params exporter.Settings,
+	config component.Config,
+) (exporter.Metrics, error) {
+	return newOpenTSDBExporter(ctx, config.(*Config), params)
+}
@@ -0,0 +1,16 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/component"
+)
+
+var (
+	Type      = component.MustNewType("opentsdb")
+	ScopeName = "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opentsdbexporter"
+)
+
+const (
+	MetricsStability = component.StabilityLevelDevelopment
+)
@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opentsdbexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opentsdbexporter"
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"unicode"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
+)
+
+const (
+	// sanitizedRune is used to replace any invalid char per OpenTSDB format.
+	sanitizedRune = '_'
+
+	// Constants used when converting from distribution metrics to OpenTSDB data points,
+	// following the conversion of the carbon exporter.
+	distributionBucketSuffix     = ".bucket"
+	distributionUpperBoundTagKey = "upper_bound"
+	summaryQuantileSuffix        = ".quantile"
+	summaryQuantileTagKey        = "quantile"
+	countSuffix                  = ".count"
+	infinityValue                = "inf"
+)
+
+// dataPoint is an OpenTSDB data point, as accepted by the /api/put endpoint.
+type dataPoint struct {
+	Metric    string            `json:"metric"`
+	Timestamp int64             `json:"timestamp"`
+	Value     json.Number       `json:"value"`
+	Tags      map[string]string `json:"tags"`
+}
+
+// isAllowedRune reports whether OpenTSDB accepts the rune in metric names, tag keys and tag
+// values: letters, digits, and "-_./".
+func isAllowedRune(r rune) bool {
+	switch r {
+	case '-', '_', '.', '/':
+		return true
+	default:
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+}
+
+// newSanitizer returns a sanitizer following the OpenTSDB rules for the metric names and
+// tags.
+func newSanitizer(cfg metricnaming.Config) *metricnaming.Sanitizer {
+	sanitize := metricnaming.ReplaceFunc(isAllowedRune, sanitizedRune)
+	return metricnaming.NewSanitizer(cfg, metricnaming.Rules{
+		Name:     sanitize,
+		TagKey:   sanitize,
+		TagValue: sanitize,
+	})
+}
+
+// metricDataToDataPoints converts metrics data to OpenTSDB data points, with millisecond
+// timestamps.
+//
+// OpenTSDB doesn't support distributions, the histogram and summary data points are converted
+// to a series of data points, as the carbon exporter does:
+//
+//   - the count is represented by a data point named "<metric_name>.count",
+//   - the sum is represented by a data point with the original "<metric_name>",
+//   - each histogram bucket is represented by a data point named "<metric_name>.bucket",
+//     with an "upper_bound" tag,
+//   - each summary quantile is represented by a data point named "<metric_name>.quantile",
+//     with a "quantile" tag.
+//
+// OpenTSDB requires at least one tag per data point: the data points without tags are
+// dropped, and their number is returned with the converted data points. The data points
+// with a NaN or infinite value, which OpenTSDB doesn't accept, are skipped.
+func metricDataToDataPoints(md pmetric.Metrics, sanitizer *metricnaming.Sanitizer) ([]dataPoint, int) {
+	c := converter{sanitizer: sanitizer}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				metric := sm.Metrics().At(k)
+				if metric.Name() == "" {
+					continue
+				}
+				name := sanitizer.MetricName(metric)
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					c.addNumberDataPoints(name, metric.Gauge().DataPoints())
+				case pmetric.MetricTypeSum:
+					c.addNumberDataPoints(name, metric.Sum().DataPoints())
+				case pmetric.MetricTypeHistogram:
+					c.addHistogramDataPoints(name, metric.Histogram().DataPoints())
+				case pmetric.MetricTypeSummary:
+					c.addSummaryDataPoints(name, metric.Summary().DataPoints())
+				}
+			}
+		}
+	}
+	return c.dataPoints, c.dropped
+}
+
+type converter struct {
+	sanitizer  *metricnaming.Sanitizer
+	dataPoints []dataPoint
+	dropped    int
+}
+
+func (c *converter) addNumberDataPoints(name string, dps pmetric.NumberDataPointSlice) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		switch dp.ValueType() {
+		case pmetric.NumberDataPointValueTypeInt:
+			c.add(name, c.tags(dp.Attributes()), dp.Timestamp(), json.Number(strconv.FormatInt(dp.IntValue(), 10)))
+		case pmetric.NumberDataPointValueTypeDouble:
+			c.addFloat(name, c.tags(dp.Attributes()), dp.Timestamp(), dp.DoubleValue())
+		}
+	}
+}
+
+func (c *converter) addHistogramDataPoints(name string, dps pmetric.HistogramDataPointSlice) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		tags := c.tags(dp.Attributes())
+		c.addCountAndSum(name, tags, dp.Timestamp(), dp.Count(), dp.Sum())
+
+		bounds := dp.ExplicitBounds()
+		if bounds.Len() == 0 {
+			continue
+		}
+		for j := 0; j < dp.BucketCounts().Len(); j++ {
+			upperBound := infinityValue
+			if j < bounds.Len() {
+				upperBound = strconv.FormatFloat(bounds.At(j), 'f', -1, 64)
+			}
+			c.add(name+distributionBucketSuffix, withTag(tags, distributionUpperBoundTagKey, upperBound), dp.Timestamp(),
+				json.Number(strconv.FormatUint(dp.BucketCounts().At(j), 10)))
+		}
+	}
+}
+
+func (c *converter) addSummaryDataPoints(name string, dps pmetric.SummaryDataPointSlice) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		tags := c.tags(dp.Attributes())
+		c.addCountAndSum(name, tags, dp.Timestamp(), dp.Count(), dp.Sum())
+
+		for j := 0; j < dp.QuantileValues().Len(); j++ {
+			qv := dp.QuantileValues().At(j)
+			quantile := strconv.FormatFloat(qv.Quantile()*100, 'f', -1, 64)
+			c.addFloat(name+summaryQuantileSuffix, withTag(tags, summaryQuantileTagKey, quantile), dp.Timestamp(), qv.Value())
+		}
+	}
+}
+
+func (c *converter) addCountAndSum(name string, tags map[string]string, timestamp pcommon.Timestamp, count uint64, sum float64) {
+	c.add(name+countSuffix, tags, timestamp, json.Number(strconv.FormatUint(count, 10)))
+	c.addFloat(name, tags, timestamp, sum)
+}
+
+func (c *converter) addFloat(name string, tags map[string]string, timestamp pcommon.Timestamp, value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+	c.add(name, tags, timestamp, json.Number(strconv.FormatFloat(value, 'g', -1, 64)))
+}
+
+func (c *converter) add(name string, tags map[string]string, timestamp pcommon.Timestamp, value json.Number) {
+	if len(tags) == 0 {
+		c.dropped++
+		return
+	}
+	c.dataPoints = append(c.dataPoints, dataPoint{
+		Metric:    name,
+		Timestamp: int64(timestamp) / 1e6,
+		Value:     value,
+		Tags:      tags,
+	})
+}
+
+// tags converts the attributes to OpenTSDB tags, dropping the ones with an empty key or value.
+func (c *converter) tags(attributes pcommon.Map) map[string]string {
+	tags := make(map[string]string, attributes.Len())
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		key, value := c.sanitizer.TagKey(k), c.sanitizer.TagValue(v.AsString())
+		if key != "" && value != "" {
+			tags[key] = value
+		}
+		return true
+	})
+	return tags
+}
+
+func withTag(tags map[string]string, key, value string) map[string]string {
+	result := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		result[k] = v
+	}
+	result[key] = value
+	return result
+}
+
+// writePutCommand writes a data point as a put command of the telnet-style API:
+//
+//	put <metric> <timestamp> <value> <tagk1=tagv1 ...[tagkN=tagvN]>
+func writePutCommand(buf *bytes.Buffer, dp dataPoint) {
+	buf.WriteString("put ")
+	buf.WriteString(dp.Metric)
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatInt(dp.Timestamp, 10))
+	buf.WriteByte(' ')
+	buf.WriteString(dp.Value.String())
+
+	keys := make([]string, 0, len(dp.Tags))
+	for k := range dp.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		buf.WriteByte(' ')
+		buf.WriteString(k)
+		buf.WriteByte('=')
+		buf.WriteString(dp.Tags[k])
+	}
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opentsdbexporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
+)
+
+var testTimestamp = pcommon.NewTimestampFromTime(time.UnixMilli(1574092046011))
+
+func generateMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+
+	gauge := ms.AppendEmpty()
+	gauge.SetName("system.cpu.load average")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(testTimestamp)
+	dp.SetDoubleValue(1.5)
+	dp.Attributes().PutStr("host", "web-1:8080")
+	dp.Attributes().PutStr("empty", "")
+
+	sum := ms.AppendEmpty()
+	sum.SetName("http.server.requests")
+	sum.SetUnit("1")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp = sum.Sum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(testTimestamp)
+	dp.SetIntValue(42)
+	dp.Attributes().PutStr("http.route", "/users")
+	// OpenTSDB doesn't accept data points without tags, nor NaN values.
+	dp = sum.Sum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(testTimestamp)
+	dp.SetIntValue(1)
+	dp = sum.Sum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(testTimestamp)
+	dp.SetDoubleValue(math.NaN())
+	dp.Attributes().PutStr("http.route", "/users")
+
+	histogram := ms.AppendEmpty()
+	histogram.SetName("latency")
+	hdp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(testTimestamp)
+	hdp.SetCount(6)
+	hdp.SetSum(12.5)
+	hdp.ExplicitBounds().FromRaw([]float64{1, 2.5})
+	hdp.BucketCounts().FromRaw([]uint64{1, 2, 3})
+	hdp.Attributes().PutStr("service", "api")
+
+	summary := ms.AppendEmpty()
+	summary.SetName("duration")
+	sdp := summary.SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetTimestamp(testTimestamp)
+	sdp.SetCount(4)
+	sdp.SetSum(8)
+	qv := sdp.QuantileValues().AppendEmpty()
+	qv.SetQuantile(0.99)
+	qv.SetValue(3.25)
+	sdp.Attributes().PutStr("service", "api")
+
+	return md
+}
+
+func TestMetricDataToDataPoints(t *testing.T) {
+	dps, dropped := metricDataToDataPoints(generateMetrics(), newSanitizer(metricnaming.NewDefaultConfig()))
+	assert.Equal(t, 1, dropped)
+
+	ts := int64(1574092046011)
+	apiTags := map[string]string{"service": "api"}
+	assert.Equal(t, []dataPoint{
+		{Metric: "system.cpu.load_average", Timestamp: ts, Value: "1.5", Tags: map[string]string{"host": "web-1_8080"}},
+		{Metric: "http.server.requests", Timestamp: ts, Value: "42", Tags: map[string]string{"http.route": "/users"}},
+		{Metric: "latency.count", Timestamp: ts, Value: "6", Tags: apiTags},
+		{Metric: "latency", Timestamp: ts, Value: "12.5", Tags: apiTags},
+		{Metric: "latency.bucket", Timestamp: ts, Value: "1", Tags: map[string]string{"service": "api", "upper_bound": "1"}},
+		{Metric: "latency.bucket", Timestamp: ts, Value: "2", Tags: map[string]string{"service": "api", "upper_bound": "2.5"}},
+		{Metric: "latency.bucket", Timestamp: ts, Value: "3", Tags: map[string]string{"service": "api", "upper_bound": "inf"}},
+		{Metric: "duration.count", Timestamp: ts, Value: "4", Tags: apiTags},
+		{Metric: "duration", Timestamp: ts, Value: "8", Tags: apiTags},
+		{Metric: "duration.quantile", Timestamp: ts, Value: "3.25", Tags: map[string]string{"service": "api", "quantile": "99"}},
+	}, dps)
+}
+
+func TestMetricDataToDataPointsPrometheusNormalization(t *testing.T) {
+	cfg := metricnaming.Config{Normalization: metricnaming.NormalizationPrometheus, AddMetricSuffixes: true}
+	dps, _ := metricDataToDataPoints(generateMetrics(), newSanitizer(cfg))
+	assert.Equal(t, dataPoint{
+		Metric:    "http_server_requests_total",
+		Timestamp: 1574092046011,
+		Value:     "42",
+		Tags:      map[string]string{"http_route": "/users"},
+	}, dps[1])
+}
+
+func TestDataPointEncoding(t *testing.T) {
+	dp := dataPoint{
+		Metric:    "http.server.requests",
+		Timestamp: 1574092046011,
+		Value:     "42",
+		Tags:      map[string]string{"http.route": "/users", "host": "web-1"},
+	}
+
+	body, err := json.Marshal(dp)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"metric":"http.server.requests","timestamp":1574092046011,"value":42,"tags":{"host":"web-1","http.route":"/users"}}`, string(body))
+
+	var buf bytes.Buffer
+	writePutCommand(&buf, dp)
+	assert.Equal(t, "put http.server.requests 1574092046011 42 host=web-1 http.route=/users\n", buf.String())
+}
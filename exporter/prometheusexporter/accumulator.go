@@ -87,6 +87,8 @@ func (a *lastValueAccumulator) addMetric(metric pmetric.Metric, il pcommon.Instr
 		return a.accumulateHistogram(metric, il, resourceAttrs, now)
 	case pmetric.MetricTypeSummary:
 		return a.accumulateSummary(metric, il, resourceAttrs, now)
+	case pmetric.MetricTypeExponentialHistogram:
+		return a.accumulateExponentialHistogram(metric, il, resourceAttrs, now)
 	default:
 		a.logger.With(
 			zap.String("data_type", string(metric.Type())),
@@ -291,6 +293,76 @@ func (a *lastValueAccumulator) accumulateHistogram(metric pmetric.Metric, il pco
 	return
 }
 
+func (a *lastValueAccumulator) accumulateExponentialHistogram(metric pmetric.Metric, il pcommon.InstrumentationScope, resourceAttrs pcommon.Map, now time.Time) (n int) {
+	expHistogram := metric.ExponentialHistogram()
+	a.logger.Debug("Accumulate exponential histogram.....")
+	dps := expHistogram.DataPoints()
+
+	for i := 0; i < dps.Len(); i++ {
+		ip := dps.At(i)
+
+		signature := timeseriesSignature(il.Name(), metric, ip.Attributes(), resourceAttrs) // uniquely identify this time series you are accumulating for
+		if ip.Flags().NoRecordedValue() {
+			a.registeredMetrics.Delete(signature)
+			return 0
+		}
+
+		v, ok := a.registeredMetrics.Load(signature) // a accumulates metric values for all times series. Get value for particular time series
+		if !ok {
+			// first data point
+			m := copyMetricMetadata(metric)
+			ip.CopyTo(m.SetEmptyExponentialHistogram().DataPoints().AppendEmpty())
+			m.ExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resourceAttrs: resourceAttrs, scope: il, updated: now})
+			n++
+			continue
+		}
+		mv := v.(*accumulatedValue)
+
+		m := copyMetricMetadata(metric)
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+		switch expHistogram.AggregationTemporality() {
+		case pmetric.AggregationTemporalityDelta:
+			pp := mv.value.ExponentialHistogram().DataPoints().At(0) // previous aggregated value for time range
+			if ip.StartTimestamp().AsTime() != pp.Timestamp().AsTime() {
+				// treat misalignment as restart and reset, or violation of single-writer principle and drop
+				a.logger.With(
+					zap.String("ip_start_time", ip.StartTimestamp().String()),
+					zap.String("pp_start_time", pp.StartTimestamp().String()),
+					zap.String("pp_timestamp", pp.Timestamp().String()),
+					zap.String("ip_timestamp", ip.Timestamp().String()),
+				).Warn("Misaligned starting timestamps")
+				if ip.StartTimestamp().AsTime().After(pp.Timestamp().AsTime()) {
+					a.logger.Debug("treating it like reset")
+					ip.CopyTo(m.ExponentialHistogram().DataPoints().AppendEmpty())
+				} else {
+					a.logger.With(
+						zap.String("metric_name", metric.Name()),
+					).Warn("Dropped misaligned exponential histogram datapoint")
+					continue
+				}
+			} else {
+				a.logger.Debug("Accumulate another exponential histogram datapoint")
+				accumulateExponentialHistogramValues(pp, ip, m.ExponentialHistogram().DataPoints().AppendEmpty())
+			}
+		case pmetric.AggregationTemporalityCumulative:
+			if ip.Timestamp().AsTime().Before(mv.value.ExponentialHistogram().DataPoints().At(0).Timestamp().AsTime()) {
+				// only keep datapoint with latest timestamp
+				continue
+			}
+
+			ip.CopyTo(m.ExponentialHistogram().DataPoints().AppendEmpty())
+		default:
+			// unsupported temporality
+			continue
+		}
+		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resourceAttrs: resourceAttrs, scope: il, updated: now})
+		n++
+	}
+	return
+}
+
 // Collect returns a slice with relevant aggregated metrics and their resource attributes.
 func (a *lastValueAccumulator) Collect() ([]pmetric.Metric, []pcommon.Map) {
 	a.logger.Debug("Accumulator collect called")
@@ -383,3 +455,61 @@ func accumulateHistogramValues(prev, current, dest pmetric.HistogramDataPoint) {
 
 	dest.ExplicitBounds().FromRaw(newer.ExplicitBounds().AsRaw())
 }
+
+// accumulateExponentialHistogramValues adds two exponential histograms, their buckets are
+// downscaled to the lower of their scales.
+func accumulateExponentialHistogramValues(prev, current, dest pmetric.ExponentialHistogramDataPoint) {
+	dest.SetStartTimestamp(prev.StartTimestamp())
+
+	older := prev
+	newer := current
+	if current.Timestamp().AsTime().Before(prev.Timestamp().AsTime()) {
+		older = current
+		newer = prev
+	}
+
+	newer.Attributes().CopyTo(dest.Attributes())
+	dest.SetTimestamp(newer.Timestamp())
+
+	scale := min(older.Scale(), newer.Scale())
+	dest.SetScale(scale)
+	dest.SetCount(newer.Count() + older.Count())
+	dest.SetSum(newer.Sum() + older.Sum())
+	dest.SetZeroCount(newer.ZeroCount() + older.ZeroCount())
+	dest.SetZeroThreshold(max(newer.ZeroThreshold(), older.ZeroThreshold()))
+	if newer.HasMin() && older.HasMin() {
+		dest.SetMin(min(newer.Min(), older.Min()))
+	}
+	if newer.HasMax() && older.HasMax() {
+		dest.SetMax(max(newer.Max(), older.Max()))
+	}
+	addExponentialBuckets(older.Positive(), older.Scale()-scale, newer.Positive(), newer.Scale()-scale, dest.Positive())
+	addExponentialBuckets(older.Negative(), older.Scale()-scale, newer.Negative(), newer.Scale()-scale, dest.Negative())
+}
+
+func addExponentialBuckets(a pmetric.ExponentialHistogramDataPointBuckets, aScaleDown int32, b pmetric.ExponentialHistogramDataPointBuckets, bScaleDown int32, dest pmetric.ExponentialHistogramDataPointBuckets) {
+	aOffset, aCounts := downscaleBuckets(a.Offset(), a.BucketCounts().AsRaw(), aScaleDown)
+	bOffset, bCounts := downscaleBuckets(b.Offset(), b.BucketCounts().AsRaw(), bScaleDown)
+	switch {
+	case len(aCounts) == 0:
+		dest.SetOffset(bOffset)
+		dest.BucketCounts().FromRaw(bCounts)
+		return
+	case len(bCounts) == 0:
+		dest.SetOffset(aOffset)
+		dest.BucketCounts().FromRaw(aCounts)
+		return
+	}
+
+	offset := min(aOffset, bOffset)
+	end := max(aOffset+int32(len(aCounts)), bOffset+int32(len(bCounts)))
+	counts := make([]uint64, end-offset)
+	for i, count := range aCounts {
+		counts[aOffset-offset+int32(i)] += count
+	}
+	for i, count := range bCounts {
+		counts[bOffset-offset+int32(i)] += count
+	}
+	dest.SetOffset(offset)
+	dest.BucketCounts().FromRaw(counts)
+}
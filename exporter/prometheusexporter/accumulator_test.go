@@ -568,6 +568,82 @@ func TestAccumulateDeltaToCumulativeHistogram(t *testing.T) {
 	})
 }
 
+func TestAccumulateDeltaToCumulativeExponentialHistogram(t *testing.T) {
+	appendDeltaExponentialHistogram := func(startTs time.Time, ts time.Time, scale int32, count uint64, sum float64, offset int32, counts []uint64, metrics pmetric.MetricSlice) {
+		metric := metrics.AppendEmpty()
+		metric.SetName("test_metric")
+		metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		metric.SetDescription("test description")
+		dp := metric.ExponentialHistogram().DataPoints().AppendEmpty()
+		dp.SetScale(scale)
+		dp.Positive().SetOffset(offset)
+		dp.Positive().BucketCounts().FromRaw(counts)
+		dp.SetZeroCount(1)
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		dp.Attributes().PutStr("label_1", "1")
+		dp.Attributes().PutStr("label_2", "2")
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(startTs))
+	}
+
+	t.Run("AccumulateHappyPath", func(t *testing.T) {
+		startTs := time.Now().Add(-5 * time.Second)
+		ts1 := time.Now().Add(-4 * time.Second)
+		ts2 := time.Now().Add(-3 * time.Second)
+		resourceMetrics := pmetric.NewResourceMetrics()
+		ilm := resourceMetrics.ScopeMetrics().AppendEmpty()
+		ilm.Scope().SetName("test")
+		appendDeltaExponentialHistogram(startTs, ts1, 1, 5, 2.5, 2, []uint64{1, 3}, ilm.Metrics())
+		appendDeltaExponentialHistogram(ts1, ts2, 0, 4, 8.3, -1, []uint64{1, 2}, ilm.Metrics())
+
+		signature := timeseriesSignature(ilm.Scope().Name(), ilm.Metrics().At(0), ilm.Metrics().At(0).ExponentialHistogram().DataPoints().At(0).Attributes(), pcommon.NewMap())
+
+		a := newAccumulator(zap.NewNop(), 1*time.Hour).(*lastValueAccumulator)
+		n := a.Accumulate(resourceMetrics)
+		require.Equal(t, 2, n)
+
+		m, ok := a.registeredMetrics.Load(signature)
+		require.True(t, ok)
+		v := m.(*accumulatedValue).value.ExponentialHistogram().DataPoints().At(0)
+
+		// The buckets 2 and 3 of scale 1 are merged into the bucket 1 of scale 0.
+		require.Equal(t, int32(0), v.Scale())
+		require.Equal(t, int32(-1), v.Positive().Offset())
+		require.Equal(t, []uint64{1, 2, 4}, v.Positive().BucketCounts().AsRaw())
+		require.Equal(t, uint64(2), v.ZeroCount())
+		require.Equal(t, uint64(9), v.Count())
+		require.InDelta(t, 10.8, v.Sum(), 1e-9)
+		require.Equal(t, pcommon.NewTimestampFromTime(startTs), v.StartTimestamp())
+		require.Equal(t, pcommon.NewTimestampFromTime(ts2), v.Timestamp())
+	})
+
+	t.Run("ResetMisalignedStart", func(t *testing.T) {
+		startTs := time.Now().Add(-5 * time.Second)
+		ts1 := time.Now().Add(-4 * time.Second)
+		ts2 := time.Now().Add(-3 * time.Second)
+		ts3 := time.Now().Add(-2 * time.Second)
+		resourceMetrics := pmetric.NewResourceMetrics()
+		ilm := resourceMetrics.ScopeMetrics().AppendEmpty()
+		ilm.Scope().SetName("test")
+		appendDeltaExponentialHistogram(startTs, ts1, 0, 5, 2.5, 0, []uint64{1, 3}, ilm.Metrics())
+		appendDeltaExponentialHistogram(ts2, ts3, 0, 4, 8.3, 0, []uint64{1, 2}, ilm.Metrics())
+
+		signature := timeseriesSignature(ilm.Scope().Name(), ilm.Metrics().At(0), ilm.Metrics().At(0).ExponentialHistogram().DataPoints().At(0).Attributes(), pcommon.NewMap())
+
+		a := newAccumulator(zap.NewNop(), 1*time.Hour).(*lastValueAccumulator)
+		n := a.Accumulate(resourceMetrics)
+		require.Equal(t, 2, n)
+
+		m, ok := a.registeredMetrics.Load(signature)
+		require.True(t, ok)
+		v := m.(*accumulatedValue).value.ExponentialHistogram().DataPoints().At(0)
+		require.Equal(t, uint64(4), v.Count())
+		require.Equal(t, []uint64{1, 2}, v.Positive().BucketCounts().AsRaw())
+		require.Equal(t, pcommon.NewTimestampFromTime(ts2), v.StartTimestamp())
+	})
+}
+
 func TestAccumulateDroppedMetrics(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -30,6 +30,9 @@ type collector struct {
 	addMetricSuffixes bool
 	namespace         string
 	constLabels       prometheus.Labels
+	// explicitBuckets is true if exponential histograms are exposed with explicit buckets
+	// in the text formats.
+	explicitBuckets bool
 }
 
 func newCollector(config *Config, logger *zap.Logger) *collector {
@@ -40,6 +43,7 @@ func newCollector(config *Config, logger *zap.Logger) *collector {
 		sendTimestamps:    config.SendTimestamps,
 		constLabels:       config.ConstLabels,
 		addMetricSuffixes: config.AddMetricSuffixes,
+		explicitBuckets:   config.ExponentialHistogramFallback == ExplicitBucketsFallback,
 	}
 }
 
@@ -100,6 +104,8 @@ func (c *collector) convertMetric(metric pmetric.Metric, resourceAttrs pcommon.M
 		return c.convertDoubleHistogram(metric, resourceAttrs)
 	case pmetric.MetricTypeSummary:
 		return c.convertSummary(metric, resourceAttrs)
+	case pmetric.MetricTypeExponentialHistogram:
+		return c.convertExponentialHistogram(metric, resourceAttrs)
 	}
 
 	return nil, errUnknownMetricType
@@ -290,6 +296,21 @@ func (c *collector) convertDoubleHistogram(metric pmetric.Metric, resourceAttrs
 	return m, nil
 }
 
+func (c *collector) convertExponentialHistogram(metric pmetric.Metric, resourceAttrs pcommon.Map) (prometheus.Metric, error) {
+	ip := metric.ExponentialHistogram().DataPoints().At(0)
+	desc, attributes := c.getMetricMetadata(metric, ip.Attributes(), resourceAttrs)
+
+	m, err := newNativeHistogram(desc, ip, c.explicitBuckets, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.sendTimestamps {
+		return prometheus.NewMetricWithTimestamp(ip.Timestamp().AsTime(), m), nil
+	}
+	return m, nil
+}
+
 func (c *collector) createTargetInfoMetrics(resourceAttrs []pcommon.Map) ([]prometheus.Metric, error) {
 	var lastErr error
 
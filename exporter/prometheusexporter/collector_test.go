@@ -71,6 +71,7 @@ func TestConvertInvalidMetric(t *testing.T) {
 		pmetric.MetricTypeHistogram,
 		pmetric.MetricTypeSum,
 		pmetric.MetricTypeGauge,
+		pmetric.MetricTypeExponentialHistogram,
 	} {
 		metric := pmetric.NewMetric()
 		switch mType {
@@ -80,6 +81,8 @@ func TestConvertInvalidMetric(t *testing.T) {
 			metric.SetEmptySum().DataPoints().AppendEmpty()
 		case pmetric.MetricTypeHistogram:
 			metric.SetEmptyHistogram().DataPoints().AppendEmpty()
+		case pmetric.MetricTypeExponentialHistogram:
+			metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
 		}
 		c := collector{}
 
@@ -181,6 +184,111 @@ func TestConvertDoubleHistogramExemplar(t *testing.T) {
 	exemplarsEqual(t, promExporterExemplars, buckets[0].GetExemplar())
 }
 
+func TestConvertExponentialHistogram(t *testing.T) {
+	metric := pmetric.NewMetric()
+	metric.SetName("test_metric")
+	metric.SetDescription("this is test metric")
+	metric.SetUnit("T")
+	dp := metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Unix(1700000000, 0)))
+	dp.SetScale(0)
+	dp.SetCount(10)
+	dp.SetSum(42)
+	dp.SetZeroCount(1)
+	// Positive buckets (1, 2], (2, 4], (32, 64].
+	dp.Positive().SetOffset(0)
+	dp.Positive().BucketCounts().FromRaw([]uint64{2, 3, 0, 0, 0, 4})
+	// Negative bucket [-2, -1).
+	dp.Negative().SetOffset(0)
+	dp.Negative().BucketCounts().FromRaw([]uint64{0})
+	exemplar := dp.Exemplars().AppendEmpty()
+	setTestExemplarWithDoubleValue(exemplar, 3.0)
+
+	tests := []struct {
+		name            string
+		explicitBuckets bool
+		wantBuckets     [][2]float64
+	}{
+		{
+			name:            "explicit buckets",
+			explicitBuckets: true,
+			wantBuckets:     [][2]float64{{-1, 0}, {0, 1}, {2, 3}, {4, 6}, {8, 6}, {16, 6}, {32, 6}, {64, 10}},
+		},
+		{
+			name: "no buckets",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := collector{
+				logger:          zap.NewNop(),
+				explicitBuckets: tt.explicitBuckets,
+			}
+
+			pbMetric, err := c.convertMetric(metric, pcommon.NewMap())
+			require.NoError(t, err)
+			m := io_prometheus_client.Metric{}
+			require.NoError(t, pbMetric.Write(&m))
+
+			h := m.GetHistogram()
+			require.Equal(t, uint64(10), h.GetSampleCount())
+			require.Equal(t, 42.0, h.GetSampleSum())
+			require.Equal(t, int32(0), h.GetSchema())
+			require.Equal(t, uint64(1), h.GetZeroCount())
+			require.Equal(t, time.Unix(1700000000, 0).UTC(), h.GetCreatedTimestamp().AsTime())
+			require.Len(t, h.GetPositiveSpan(), 2)
+			require.Equal(t, int32(1), h.GetPositiveSpan()[0].GetOffset())
+			require.Equal(t, uint32(2), h.GetPositiveSpan()[0].GetLength())
+			require.Equal(t, int32(3), h.GetPositiveSpan()[1].GetOffset())
+			require.Equal(t, uint32(1), h.GetPositiveSpan()[1].GetLength())
+			require.Equal(t, []int64{2, 1, 1}, h.GetPositiveDelta())
+			require.Empty(t, h.GetNegativeSpan())
+			require.Len(t, h.GetExemplars(), 1)
+			exemplarsEqual(t, exemplar, h.GetExemplars()[0])
+
+			buckets := make([][2]float64, 0, len(h.GetBucket()))
+			for _, b := range h.GetBucket() {
+				buckets = append(buckets, [2]float64{b.GetUpperBound(), float64(b.GetCumulativeCount())})
+			}
+			if tt.wantBuckets == nil {
+				require.Empty(t, buckets)
+				return
+			}
+			require.Equal(t, tt.wantBuckets, buckets)
+			require.Nil(t, h.GetBucket()[2].GetExemplar())
+			require.Equal(t, 3.0, h.GetBucket()[3].GetExemplar().GetValue())
+		})
+	}
+}
+
+func TestConvertExponentialHistogramScale(t *testing.T) {
+	metric := pmetric.NewMetric()
+	metric.SetName("test_metric")
+	dp := metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetCount(4)
+	dp.SetScale(10)
+	dp.Positive().SetOffset(3)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 1, 2})
+
+	c := collector{logger: zap.NewNop()}
+	pbMetric, err := c.convertMetric(metric, pcommon.NewMap())
+	require.NoError(t, err)
+	m := io_prometheus_client.Metric{}
+	require.NoError(t, pbMetric.Write(&m))
+
+	// The buckets 3 to 5 of scale 10 are merged into the buckets 0 and 1 of scale 8.
+	h := m.GetHistogram()
+	require.Equal(t, int32(8), h.GetSchema())
+	require.Len(t, h.GetPositiveSpan(), 1)
+	require.Equal(t, int32(1), h.GetPositiveSpan()[0].GetOffset())
+	require.Equal(t, uint32(2), h.GetPositiveSpan()[0].GetLength())
+	require.Equal(t, []int64{1, 2}, h.GetPositiveDelta())
+
+	dp.SetScale(-5)
+	_, err = c.convertMetric(metric, pcommon.NewMap())
+	require.Error(t, err)
+}
+
 func TestConvertMonotonicSumExemplar(t *testing.T) {
 	// initialize empty metric
 	metric := pmetric.NewMetric()
@@ -4,6 +4,7 @@
 package prometheusexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusexporter"
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -37,11 +38,29 @@ type Config struct {
 
 	// AddMetricSuffixes controls whether suffixes are added to metric names. Defaults to true.
 	AddMetricSuffixes bool `mapstructure:"add_metric_suffixes"`
+
+	// ExponentialHistogramFallback controls how exponential histograms are exposed in the text formats,
+	// which don't support the native histograms exposed by the protobuf format. Defaults to "explicit_buckets".
+	ExponentialHistogramFallback ExponentialHistogramFallback `mapstructure:"exponential_histogram_fallback"`
 }
 
+// ExponentialHistogramFallback is how exponential histograms are exposed in the text formats.
+type ExponentialHistogramFallback string
+
+const (
+	// ExplicitBucketsFallback exposes the buckets of exponential histograms as explicit buckets.
+	ExplicitBucketsFallback ExponentialHistogramFallback = "explicit_buckets"
+	// NoBucketsFallback only exposes the count and sum of exponential histograms.
+	NoBucketsFallback ExponentialHistogramFallback = "none"
+)
+
 var _ component.Config = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
-	return nil
+	switch cfg.ExponentialHistogramFallback {
+	case ExplicitBucketsFallback, NoBucketsFallback:
+		return nil
+	}
+	return fmt.Errorf("unknown exponential_histogram_fallback %q, supported: %q, %q", cfg.ExponentialHistogramFallback, ExplicitBucketsFallback, NoBucketsFallback)
 }
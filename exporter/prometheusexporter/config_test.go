@@ -50,9 +50,10 @@ func TestLoadConfig(t *testing.T) {
 					"label1":        "value1",
 					"another label": "spaced value",
 				},
-				SendTimestamps:    true,
-				MetricExpiration:  60 * time.Minute,
-				AddMetricSuffixes: false,
+				SendTimestamps:               true,
+				MetricExpiration:             60 * time.Minute,
+				AddMetricSuffixes:            false,
+				ExponentialHistogramFallback: NoBucketsFallback,
 			},
 		},
 	}
@@ -71,3 +72,9 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestInvalidExponentialHistogramFallback(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ExponentialHistogramFallback = "classic"
+	assert.EqualError(t, component.ValidateConfig(cfg), `unknown exponential_histogram_fallback "classic", supported: "explicit_buckets", "none"`)
+}
@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusexporter"
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// The schemas of native histograms range from -4 to 8, they are the scales of exponential histograms.
+	minNativeHistogramSchema = -4
+	maxNativeHistogramSchema = 8
+)
+
+// nativeHistogram is a prometheus.Metric exposing an exponential histogram as a native histogram.
+// Native histograms are only exposed by the protobuf format, the classic buckets are exposed by
+// the text formats.
+type nativeHistogram struct {
+	desc       *prometheus.Desc
+	labelPairs []*dto.LabelPair
+	histogram  *dto.Histogram
+}
+
+var _ prometheus.Metric = (*nativeHistogram)(nil)
+
+func (h *nativeHistogram) Desc() *prometheus.Desc {
+	return h.desc
+}
+
+func (h *nativeHistogram) Write(m *dto.Metric) error {
+	m.Label = h.labelPairs
+	m.Histogram = h.histogram
+	return nil
+}
+
+// newNativeHistogram converts an exponential histogram data point to a native histogram, with
+// classic buckets converted from its exponential buckets if explicitBuckets is true.
+func newNativeHistogram(desc *prometheus.Desc, ip pmetric.ExponentialHistogramDataPoint, explicitBuckets bool, labelValues []string) (prometheus.Metric, error) {
+	// The descriptor and the label values are validated like those of the const metrics.
+	if _, err := prometheus.NewConstMetric(desc, prometheus.UntypedValue, 0, labelValues...); err != nil {
+		return nil, err
+	}
+	scale := ip.Scale()
+	if scale < minNativeHistogramSchema {
+		return nil, fmt.Errorf("cannot convert exponential histogram with scale %d to native histogram, scale must be >= %d", scale, minNativeHistogramSchema)
+	}
+	var scaleDown int32
+	if scale > maxNativeHistogramSchema {
+		scaleDown = scale - maxNativeHistogramSchema
+		scale = maxNativeHistogramSchema
+	}
+	positiveOffset, positive := downscaleBuckets(ip.Positive().Offset(), ip.Positive().BucketCounts().AsRaw(), scaleDown)
+	negativeOffset, negative := downscaleBuckets(ip.Negative().Offset(), ip.Negative().BucketCounts().AsRaw(), scaleDown)
+
+	h := &dto.Histogram{
+		SampleCount:   proto.Uint64(ip.Count()),
+		SampleSum:     proto.Float64(ip.Sum()),
+		Schema:        proto.Int32(scale),
+		ZeroThreshold: proto.Float64(ip.ZeroThreshold()),
+		ZeroCount:     proto.Uint64(ip.ZeroCount()),
+	}
+	h.PositiveSpan, h.PositiveDelta = nativeBuckets(positiveOffset, positive)
+	h.NegativeSpan, h.NegativeDelta = nativeBuckets(negativeOffset, negative)
+	if len(h.PositiveSpan) == 0 && len(h.NegativeSpan) == 0 && ip.ZeroThreshold() == 0 && ip.ZeroCount() == 0 {
+		// Histograms without spans, zero threshold or zero count are taken for classic histograms,
+		// an empty span marks them as native histograms.
+		h.PositiveSpan = []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(0)}}
+	}
+	if ip.StartTimestamp().AsTime().Unix() > 0 {
+		h.CreatedTimestamp = timestamppb.New(ip.StartTimestamp().AsTime())
+	}
+	if explicitBuckets {
+		h.Bucket = classicBuckets(scale, ip.ZeroThreshold(), ip.ZeroCount(), positiveOffset, positive, negativeOffset, negative)
+	}
+
+	exemplars, err := nativeExemplars(ip.Exemplars())
+	if err != nil {
+		return nil, err
+	}
+	h.Exemplars = exemplars
+	for _, e := range exemplars {
+		// The buckets are sorted by upper bound, the exemplars of the +Inf bucket are only native.
+		i := sort.Search(len(h.Bucket), func(i int) bool {
+			return h.Bucket[i].GetUpperBound() >= e.GetValue()
+		})
+		if i < len(h.Bucket) {
+			h.Bucket[i].Exemplar = e
+		}
+	}
+
+	return &nativeHistogram{
+		desc:       desc,
+		labelPairs: prometheus.MakeLabelPairs(desc, labelValues),
+		histogram:  h,
+	}, nil
+}
+
+// nativeBuckets converts the counts of exponential histogram buckets to the spans and deltas of
+// native histogram buckets. The index of a native histogram bucket is one more than the index of
+// the exponential histogram bucket with the same bounds.
+func nativeBuckets(offset int32, counts []uint64) ([]*dto.BucketSpan, []int64) {
+	var spans []*dto.BucketSpan
+	var deltas []int64
+	var previous int64
+	// Runs of more than two empty buckets start a new span.
+	emptyBuckets := int32(0)
+	for i, count := range counts {
+		if count == 0 {
+			emptyBuckets++
+			continue
+		}
+		switch {
+		case len(spans) == 0:
+			spans = append(spans, &dto.BucketSpan{Offset: proto.Int32(offset + int32(i) + 1), Length: proto.Uint32(0)})
+		case emptyBuckets > 2:
+			spans = append(spans, &dto.BucketSpan{Offset: proto.Int32(emptyBuckets), Length: proto.Uint32(0)})
+		default:
+			for ; emptyBuckets > 0; emptyBuckets-- {
+				*spans[len(spans)-1].Length++
+				deltas = append(deltas, -previous)
+				previous = 0
+			}
+		}
+		emptyBuckets = 0
+		*spans[len(spans)-1].Length++
+		deltas = append(deltas, int64(count)-previous)
+		previous = int64(count)
+	}
+	return spans, deltas
+}
+
+// classicBuckets converts the exponential buckets to cumulative classic buckets, whose upper bounds are
+// the bounds of the exponential buckets. The +Inf bucket is implicit.
+func classicBuckets(scale int32, zeroThreshold float64, zeroCount uint64, positiveOffset int32, positive []uint64, negativeOffset int32, negative []uint64) []*dto.Bucket {
+	base := math.Exp2(math.Exp2(-float64(scale)))
+	buckets := make([]*dto.Bucket, 0, len(negative)+len(positive)+1)
+	var cumulative uint64
+	// The negative bucket of index i covers [-base^(i+1), -base^i).
+	for i := len(negative) - 1; i >= 0; i-- {
+		cumulative += negative[i]
+		buckets = append(buckets, &dto.Bucket{
+			UpperBound:      proto.Float64(-math.Pow(base, float64(negativeOffset+int32(i)))),
+			CumulativeCount: proto.Uint64(cumulative),
+		})
+	}
+	cumulative += zeroCount
+	buckets = append(buckets, &dto.Bucket{
+		UpperBound:      proto.Float64(zeroThreshold),
+		CumulativeCount: proto.Uint64(cumulative),
+	})
+	// The positive bucket of index i covers (base^i, base^(i+1)].
+	for i, count := range positive {
+		cumulative += count
+		buckets = append(buckets, &dto.Bucket{
+			UpperBound:      proto.Float64(math.Pow(base, float64(positiveOffset+int32(i)+1))),
+			CumulativeCount: proto.Uint64(cumulative),
+		})
+	}
+	return buckets
+}
+
+func nativeExemplars(exemplars pmetric.ExemplarSlice) ([]*dto.Exemplar, error) {
+	if exemplars.Len() == 0 {
+		return nil, nil
+	}
+	result := make([]*dto.Exemplar, 0, exemplars.Len())
+	for _, e := range convertExemplars(exemplars) {
+		ts := timestamppb.New(e.Timestamp)
+		if err := ts.CheckValid(); err != nil {
+			return nil, err
+		}
+		labels := make([]*dto.LabelPair, 0, len(e.Labels))
+		for name, value := range e.Labels {
+			labels = append(labels, &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)})
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			return labels[i].GetName() < labels[j].GetName()
+		})
+		result = append(result, &dto.Exemplar{
+			Label:     labels,
+			Value:     proto.Float64(e.Value),
+			Timestamp: ts,
+		})
+	}
+	return result, nil
+}
+
+// downscaleBuckets returns the offset and counts of exponential histogram buckets
+// downscaled by scaleDown, merging 2^scaleDown buckets into one.
+func downscaleBuckets(offset int32, counts []uint64, scaleDown int32) (int32, []uint64) {
+	if scaleDown <= 0 || len(counts) == 0 {
+		return offset, counts
+	}
+	newOffset := offset >> scaleDown
+	last := (offset + int32(len(counts)) - 1) >> scaleDown
+	merged := make([]uint64, last-newOffset+1)
+	for i, count := range counts {
+		merged[((offset+int32(i))>>scaleDown)-newOffset] += count
+	}
+	return newOffset, merged
+}
@@ -52,6 +52,16 @@ type Config struct {
 
 	// SendMetadata controls whether prometheus metadata will be generated and sent
 	SendMetadata bool `mapstructure:"send_metadata"`
+
+	// RemoteWriteProtoMsg is the protobuf message sent to the endpoint, either "prometheus.WriteRequest"
+	// for remote write 1.0 or "io.prometheus.write.v2.Request" for remote write 2.0. Remote write 2.0
+	// requests carry the metadata and created timestamp of each series, and fall back to remote write 1.0
+	// if the endpoint doesn't support them.
+	RemoteWriteProtoMsg RemoteWriteProtoMsg `mapstructure:"protobuf_message"`
+
+	// Tenant routes the metrics to the tenants of multi-tenant endpoints, each tenant having
+	// its own queue and WAL.
+	Tenant *TenantConfig `mapstructure:"tenant"`
 }
 
 type CreatedMetric struct {
@@ -64,6 +74,16 @@ type TargetInfo struct {
 	Enabled bool `mapstructure:"enabled"`
 }
 
+// RemoteWriteProtoMsg is the protobuf message of the remote write requests.
+type RemoteWriteProtoMsg string
+
+const (
+	// RemoteWriteProtoMsgV1 is the message of the remote write 1.0 protocol.
+	RemoteWriteProtoMsgV1 RemoteWriteProtoMsg = "prometheus.WriteRequest"
+	// RemoteWriteProtoMsgV2 is the message of the remote write 2.0 protocol.
+	RemoteWriteProtoMsgV2 RemoteWriteProtoMsg = "io.prometheus.write.v2.Request"
+)
+
 // RemoteWriteQueue allows to configure the remote write queue.
 type RemoteWriteQueue struct {
 	// Enabled if false the queue is not enabled, the export requests
@@ -107,6 +127,19 @@ func (cfg *Config) Validate() error {
 			Enabled: false,
 		}
 	}
+	if cfg.RemoteWriteProtoMsg != RemoteWriteProtoMsgV1 && cfg.RemoteWriteProtoMsg != RemoteWriteProtoMsgV2 {
+		return fmt.Errorf("unknown protobuf_message %q, supported: %q, %q", cfg.RemoteWriteProtoMsg, RemoteWriteProtoMsgV1, RemoteWriteProtoMsgV2)
+	}
+	if cfg.RemoteWriteProtoMsg == RemoteWriteProtoMsgV2 && cfg.WAL != nil {
+		return fmt.Errorf("the WAL isn't supported with remote write 2.0")
+	}
+
+	if cfg.Tenant != nil {
+		if err := cfg.Tenant.validate(cfg.ClientConfig.Headers); err != nil {
+			return err
+		}
+	}
+
 	if cfg.MaxBatchSizeBytes < 0 {
 		return fmt.Errorf("max_batch_byte_size must be greater than 0")
 	}
@@ -78,7 +78,8 @@ func TestLoadConfig(t *testing.T) {
 				TargetInfo: &TargetInfo{
 					Enabled: true,
 				},
-				CreatedMetric: &CreatedMetric{Enabled: true},
+				CreatedMetric:       &CreatedMetric{Enabled: true},
+				RemoteWriteProtoMsg: RemoteWriteProtoMsgV1,
 			},
 		},
 		{
@@ -89,6 +90,30 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(metadata.Type, "negative_num_consumers"),
 			errorMessage: "remote write consumer number can't be negative",
 		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "unknown_protobuf_message"),
+			errorMessage: `unknown protobuf_message "io.prometheus.write.v3.Request", supported: "prometheus.WriteRequest", "io.prometheus.write.v2.Request"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "remote_write_v2_with_wal"),
+			errorMessage: "the WAL isn't supported with remote write 2.0",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "tenant_without_resource_attribute"),
+			errorMessage: "tenant resource_attribute must be set",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "tenant_header_in_headers"),
+			errorMessage: `the tenant header "x-scope-orgid" can't be set in headers`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_default_tenant"),
+			errorMessage: `invalid tenant default_tenant: tenant "team/a" contains unsupported character '/'`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "negative_tenant_idle_timeout"),
+			errorMessage: "tenant idle_timeout can't be negative",
+		},
 	}
 
 	for _, tt := range tests {
@@ -135,3 +160,25 @@ func TestDisabledTargetInfo(t *testing.T) {
 
 	assert.False(t, cfg.(*Config).TargetInfo.Enabled)
 }
+
+func TestTenantConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "tenant").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	assert.Equal(t, &TenantConfig{
+		ResourceAttribute: "tenant.id",
+		DefaultTenant:     "anonymous",
+		QueueSize:         50,
+		IdleTimeout:       5 * time.Minute,
+	}, cfg.(*Config).Tenant)
+	assert.Equal(t, "X-Scope-OrgID", cfg.(*Config).Tenant.header())
+	assert.Equal(t, 50, cfg.(*Config).Tenant.queueSize())
+	assert.Equal(t, 5*time.Minute, cfg.(*Config).Tenant.idleTimeout())
+}
@@ -14,6 +14,7 @@ type ctxKey int
 
 const (
 	loggerCtxKey ctxKey = iota
+	tenantCtxKey
 )
 
 func contextWithLogger(ctx context.Context, log *zap.Logger) context.Context {
@@ -33,3 +34,12 @@ func loggerFromContext(ctx context.Context) (*zap.Logger, error) {
 
 	return l, nil
 }
+
+func contextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey, tenant)
+}
+
+func tenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantCtxKey).(string)
+	return tenant, ok
+}
@@ -14,11 +14,13 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configretry"
@@ -35,9 +37,16 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
 )
 
+// errUnsupportedProtoMsg is returned when the endpoint rejects the protobuf message of the requests.
+var errUnsupportedProtoMsg = errors.New("remote write protobuf message not supported by the endpoint")
+
 type prwTelemetry interface {
 	recordTranslationFailure(ctx context.Context)
 	recordTranslatedTimeSeries(ctx context.Context, numTS int)
+	recordTenantSentBatch(ctx context.Context)
+	recordTenantFailedBatch(ctx context.Context)
+	recordTenantDroppedBatch(ctx context.Context)
+	recordTenantQueuedBatches(ctx context.Context, delta int)
 }
 
 type prwTelemetryOtel struct {
@@ -45,12 +54,38 @@ type prwTelemetryOtel struct {
 	otelAttrs        []attribute.KeyValue
 }
 
+// attributes returns the attributes of the measurements, with the tenant of ctx if any.
+func (p *prwTelemetryOtel) attributes(ctx context.Context) metric.MeasurementOption {
+	if tenant, ok := tenantFromContext(ctx); ok {
+		attrs := make([]attribute.KeyValue, 0, len(p.otelAttrs)+1)
+		attrs = append(attrs, p.otelAttrs...)
+		return metric.WithAttributes(append(attrs, attribute.String("tenant", tenant))...)
+	}
+	return metric.WithAttributes(p.otelAttrs...)
+}
+
 func (p *prwTelemetryOtel) recordTranslationFailure(ctx context.Context) {
-	p.telemetryBuilder.ExporterPrometheusremotewriteFailedTranslations.Add(ctx, 1, metric.WithAttributes(p.otelAttrs...))
+	p.telemetryBuilder.ExporterPrometheusremotewriteFailedTranslations.Add(ctx, 1, p.attributes(ctx))
 }
 
 func (p *prwTelemetryOtel) recordTranslatedTimeSeries(ctx context.Context, numTS int) {
-	p.telemetryBuilder.ExporterPrometheusremotewriteTranslatedTimeSeries.Add(ctx, int64(numTS), metric.WithAttributes(p.otelAttrs...))
+	p.telemetryBuilder.ExporterPrometheusremotewriteTranslatedTimeSeries.Add(ctx, int64(numTS), p.attributes(ctx))
+}
+
+func (p *prwTelemetryOtel) recordTenantSentBatch(ctx context.Context) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteTenantSentBatches.Add(ctx, 1, p.attributes(ctx))
+}
+
+func (p *prwTelemetryOtel) recordTenantFailedBatch(ctx context.Context) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteTenantFailedBatches.Add(ctx, 1, p.attributes(ctx))
+}
+
+func (p *prwTelemetryOtel) recordTenantDroppedBatch(ctx context.Context) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteTenantDroppedBatches.Add(ctx, 1, p.attributes(ctx))
+}
+
+func (p *prwTelemetryOtel) recordTenantQueuedBatches(ctx context.Context, delta int) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteTenantQueuedBatches.Add(ctx, int64(delta), p.attributes(ctx))
 }
 
 // prwExporter converts OTLP metrics to Prometheus remote write TimeSeries and sends them to a remote endpoint.
@@ -70,6 +105,12 @@ type prwExporter struct {
 	exporterSettings     prometheusremotewrite.Settings
 	telemetry            prwTelemetry
 	batchTimeSeriesState batchTimeSeriesState
+	protoMsg             RemoteWriteProtoMsg
+	// fallbackToV1 is set once the endpoint rejected a remote write 2.0 request.
+	fallbackToV1 atomic.Bool
+	// tenants routes the metrics to their tenant, nil unless tenant routing is enabled.
+	tenants      *tenantRouter
+	tenantHeader string
 }
 
 func newPRWTelemetry(set exporter.Settings) (prwTelemetry, error) {
@@ -126,9 +167,26 @@ func newPRWExporter(cfg *Config, set exporter.Settings) (*prwExporter, error) {
 		},
 		telemetry:            prwTelemetry,
 		batchTimeSeriesState: newBatchTimeSericesState(),
+		protoMsg:             cfg.RemoteWriteProtoMsg,
+	}
+
+	if cfg.Tenant == nil {
+		prwe.wal = newWAL(cfg.WAL, prwe.export)
+		return prwe, nil
 	}
 
-	prwe.wal = newWAL(cfg.WAL, prwe.export)
+	// Each tenant has its own WAL, the requests read from it are sent with the tenant of their context.
+	prwe.tenantHeader = cfg.Tenant.header()
+	timeout := cfg.TimeoutSettings.Timeout
+	pushTenant := func(ctx context.Context, md pmetric.Metrics, wal *prweWAL, state *batchTimeSeriesState) error {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return prwe.pushMetrics(ctx, md, wal, state)
+	}
+	prwe.tenants = newTenantRouter(cfg.Tenant, cfg.WAL, cfg.BackOffConfig, set.Logger, prwTelemetry, pushTenant, prwe.export)
 	return prwe, nil
 }
 
@@ -138,6 +196,9 @@ func (prwe *prwExporter) Start(ctx context.Context, host component.Host) (err er
 	if err != nil {
 		return err
 	}
+	if prwe.tenants != nil {
+		return prwe.tenants.start()
+	}
 	return prwe.turnOnWALIfEnabled(contextWithLogger(ctx, prwe.settings.Logger.Named("prw.wal")))
 }
 
@@ -150,12 +211,17 @@ func (prwe *prwExporter) shutdownWALIfEnabled() error {
 
 // Shutdown stops the exporter from accepting incoming calls(and return error), and wait for current export operations
 // to finish before returning
-func (prwe *prwExporter) Shutdown(context.Context) error {
+func (prwe *prwExporter) Shutdown(ctx context.Context) error {
 	select {
 	case <-prwe.closeChan:
 	default:
 		close(prwe.closeChan)
 	}
+	if prwe.tenants != nil {
+		err := prwe.tenants.shutdown(ctx)
+		prwe.wg.Wait()
+		return err
+	}
 	err := prwe.shutdownWALIfEnabled()
 	prwe.wg.Wait()
 	return err
@@ -172,23 +238,66 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	case <-prwe.closeChan:
 		return errors.New("shutdown has been called")
 	default:
+		if prwe.tenants != nil {
+			// The metrics of each tenant are sent by its queue.
+			return prwe.tenants.route(ctx, md)
+		}
+		return prwe.pushMetrics(ctx, md, prwe.wal, &prwe.batchTimeSeriesState)
+	}
+}
 
-		tsMap, err := prometheusremotewrite.FromMetrics(md, prwe.exporterSettings)
-		if err != nil {
-			prwe.telemetry.recordTranslationFailure(ctx)
-			prwe.settings.Logger.Debug("failed to translate metrics, exporting remaining metrics", zap.Error(err), zap.Int("translated", len(tsMap)))
+// pushMetrics converts metrics to Prometheus remote write requests and sends them to the remote endpoint,
+// or persists them to wal if not nil.
+func (prwe *prwExporter) pushMetrics(ctx context.Context, md pmetric.Metrics, wal *prweWAL, state *batchTimeSeriesState) error {
+	if prwe.protoMsg == RemoteWriteProtoMsgV2 && !prwe.fallbackToV1.Load() {
+		err := prwe.pushMetricsV2(ctx, md, state)
+		if !errors.Is(err, errUnsupportedProtoMsg) {
+			return err
 		}
+		// The whole batch is sent again with remote write 1.0, the samples already
+		// written by successful requests are identical and ignored by the endpoint.
+		prwe.settings.Logger.Warn("remote write 2.0 is not supported by the endpoint, falling back to remote write 1.0", zap.Error(err))
+		prwe.fallbackToV1.Store(true)
+	}
 
-		prwe.telemetry.recordTranslatedTimeSeries(ctx, len(tsMap))
+	tsMap, err := prometheusremotewrite.FromMetrics(md, prwe.exporterSettings)
+	if err != nil {
+		prwe.telemetry.recordTranslationFailure(ctx)
+		prwe.settings.Logger.Debug("failed to translate metrics, exporting remaining metrics", zap.Error(err), zap.Int("translated", len(tsMap)))
+	}
 
-		var m []*prompb.MetricMetadata
-		if prwe.exporterSettings.SendMetadata {
-			m = prometheusremotewrite.OtelMetricsToMetadata(md, prwe.exporterSettings.AddMetricSuffixes)
-		}
+	prwe.telemetry.recordTranslatedTimeSeries(ctx, len(tsMap))
+
+	var m []*prompb.MetricMetadata
+	if prwe.exporterSettings.SendMetadata {
+		m = prometheusremotewrite.OtelMetricsToMetadata(md, prwe.exporterSettings.AddMetricSuffixes)
+	}
+
+	// Call export even if a conversion error, since there may be points that were successfully converted.
+	return prwe.handleExportWithWAL(ctx, tsMap, m, wal, state)
+}
+
+// pushMetricsV2 converts metrics to Prometheus remote write 2.0 series and sends them to the remote endpoint.
+func (prwe *prwExporter) pushMetricsV2(ctx context.Context, md pmetric.Metrics, state *batchTimeSeriesState) error {
+	tsMap, symbols, err := prometheusremotewrite.FromMetricsV2(md, prwe.exporterSettings)
+	if err != nil {
+		prwe.telemetry.recordTranslationFailure(ctx)
+		prwe.settings.Logger.Debug("failed to translate metrics, exporting remaining metrics", zap.Error(err), zap.Int("translated", len(tsMap)))
+	}
 
-		// Call export even if a conversion error, since there may be points that were successfully converted.
-		return prwe.handleExport(ctx, tsMap, m)
+	prwe.telemetry.recordTranslatedTimeSeries(ctx, len(tsMap))
+
+	// There are no metrics to export, so return.
+	if len(tsMap) == 0 {
+		return nil
 	}
+
+	// Export even if a conversion error, since there may be points that were successfully converted.
+	requests, err := batchTimeSeriesV2(tsMap, symbols, prwe.maxBatchSizeBytes, state)
+	if err != nil {
+		return err
+	}
+	return prwe.exportV2(ctx, requests)
 }
 
 func validateAndSanitizeExternalLabels(cfg *Config) (map[string]string, error) {
@@ -204,24 +313,29 @@ func validateAndSanitizeExternalLabels(cfg *Config) (map[string]string, error) {
 }
 
 func (prwe *prwExporter) handleExport(ctx context.Context, tsMap map[string]*prompb.TimeSeries, m []*prompb.MetricMetadata) error {
+	return prwe.handleExportWithWAL(ctx, tsMap, m, prwe.wal, &prwe.batchTimeSeriesState)
+}
+
+// handleExportWithWAL exports the time series, or persists them to wal if not nil.
+func (prwe *prwExporter) handleExportWithWAL(ctx context.Context, tsMap map[string]*prompb.TimeSeries, m []*prompb.MetricMetadata, wal *prweWAL, state *batchTimeSeriesState) error {
 	// There are no metrics to export, so return.
 	if len(tsMap) == 0 {
 		return nil
 	}
 
 	// Calls the helper function to convert and batch the TsMap to the desired format
-	requests, err := batchTimeSeries(tsMap, prwe.maxBatchSizeBytes, m, &prwe.batchTimeSeriesState)
+	requests, err := batchTimeSeries(tsMap, prwe.maxBatchSizeBytes, m, state)
 	if err != nil {
 		return err
 	}
-	if !prwe.walEnabled() {
+	if wal == nil {
 		// Perform a direct export otherwise.
 		return prwe.export(ctx, requests)
 	}
 
 	// Otherwise the WAL is enabled, and just persist the requests to the WAL
 	// and they'll be exported in another goroutine to the RemoteWrite endpoint.
-	if err = prwe.wal.persistToWAL(requests); err != nil {
+	if err = wal.persistToWAL(requests); err != nil {
 		return consumererror.NewPermanent(err)
 	}
 	return nil
@@ -229,7 +343,16 @@ func (prwe *prwExporter) handleExport(ctx context.Context, tsMap map[string]*pro
 
 // export sends a Snappy-compressed WriteRequest containing TimeSeries to a remote write endpoint in order
 func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteRequest) error {
-	input := make(chan *prompb.WriteRequest, len(requests))
+	return exportConcurrently(ctx, prwe.concurrency, requests, prwe.execute)
+}
+
+// exportV2 sends Snappy-compressed remote write 2.0 requests to a remote write endpoint in order
+func (prwe *prwExporter) exportV2(ctx context.Context, requests []*writev2.Request) error {
+	return exportConcurrently(ctx, prwe.concurrency, requests, prwe.executeV2)
+}
+
+func exportConcurrently[T any](ctx context.Context, concurrency int, requests []T, execute func(context.Context, T) error) error {
+	input := make(chan T, len(requests))
 	for _, request := range requests {
 		input <- request
 	}
@@ -237,7 +360,7 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 
 	var wg sync.WaitGroup
 
-	concurrencyLimit := int(math.Min(float64(prwe.concurrency), float64(len(requests))))
+	concurrencyLimit := int(math.Min(float64(concurrency), float64(len(requests))))
 	wg.Add(concurrencyLimit) // used to wait for workers to be finished
 
 	var mu sync.Mutex
@@ -256,7 +379,7 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 					if !ok {
 						return
 					}
-					if errExecute := prwe.execute(ctx, request); errExecute != nil {
+					if errExecute := execute(ctx, request); errExecute != nil {
 						mu.Lock()
 						errs = multierr.Append(errs, consumererror.NewPermanent(errExecute))
 						mu.Unlock()
@@ -276,6 +399,19 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 	if errMarshal != nil {
 		return consumererror.NewPermanent(errMarshal)
 	}
+	return prwe.send(ctx, data, RemoteWriteProtoMsgV1)
+}
+
+func (prwe *prwExporter) executeV2(ctx context.Context, writeReq *writev2.Request) error {
+	data, errMarshal := writeReq.Marshal()
+	if errMarshal != nil {
+		return consumererror.NewPermanent(errMarshal)
+	}
+	return prwe.send(ctx, data, RemoteWriteProtoMsgV2)
+}
+
+// send sends a marshaled remote write request of the protoMsg protobuf message to the remote endpoint.
+func (prwe *prwExporter) send(ctx context.Context, data []byte, protoMsg RemoteWriteProtoMsg) error {
 	// If we don't pass a buffer large enough, Snappy Encode function will not use it and instead will allocate a new buffer.
 	// Therefore we always let Snappy decide the size of the buffer.
 	compressedData := snappy.Encode(nil, data)
@@ -299,10 +435,19 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 
 		// Add necessary headers specified by:
 		// https://cortexmetrics.io/docs/apis/#remote-api
+		// and https://prometheus.io/docs/specs/remote_write_spec_2_0/
 		req.Header.Add("Content-Encoding", "snappy")
-		req.Header.Set("Content-Type", "application/x-protobuf")
-		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		if protoMsg == RemoteWriteProtoMsgV2 {
+			req.Header.Set("Content-Type", "application/x-protobuf;proto="+string(RemoteWriteProtoMsgV2))
+			req.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
+		} else {
+			req.Header.Set("Content-Type", "application/x-protobuf")
+			req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		}
 		req.Header.Set("User-Agent", prwe.userAgentHeader)
+		if tenant, ok := tenantFromContext(ctx); ok {
+			req.Header.Set(prwe.tenantHeader, tenant)
+		}
 
 		resp, err := prwe.client.Do(req)
 		if err != nil {
@@ -330,13 +475,27 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 			return rerr
 		}
 
+		// Endpoints that don't support the protobuf message of remote write 2.0 requests reject them
+		// with 415, the requests can be sent again with remote write 1.0.
+		if protoMsg == RemoteWriteProtoMsgV2 && resp.StatusCode == http.StatusUnsupportedMediaType {
+			return backoff.Permanent(consumererror.NewPermanent(fmt.Errorf("%w: %w", errUnsupportedProtoMsg, rerr)))
+		}
+
 		return backoff.Permanent(consumererror.NewPermanent(rerr))
 	}
 
+	// lastErr is the error of the last attempt, which is a backoff.PermanentError unless the
+	// request may succeed when sent again.
+	var lastErr error
+	attempt := func() error {
+		lastErr = executeFunc()
+		return lastErr
+	}
+
 	var err error
 	if prwe.retrySettings.Enabled {
 		// Use the BackOff instance to retry the func with exponential backoff.
-		err = backoff.Retry(executeFunc, &backoff.ExponentialBackOff{
+		err = backoff.Retry(attempt, &backoff.ExponentialBackOff{
 			InitialInterval:     prwe.retrySettings.InitialInterval,
 			RandomizationFactor: prwe.retrySettings.RandomizationFactor,
 			Multiplier:          prwe.retrySettings.Multiplier,
@@ -346,16 +505,34 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 			Clock:               backoff.SystemClock,
 		})
 	} else {
-		err = executeFunc()
+		err = attempt()
 	}
 
 	if err != nil {
+		var permanent *backoff.PermanentError
+		if !errors.As(lastErr, &permanent) {
+			err = &recoverableError{err: err}
+		}
 		return consumererror.NewPermanent(err)
 	}
 
 	return err
 }
 
+// recoverableError is the error of a request which failed after its retries, but may succeed
+// when sent again later, such as when the endpoint is unavailable.
+type recoverableError struct {
+	err error
+}
+
+func (e *recoverableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *recoverableError) Unwrap() error {
+	return e.err
+}
+
 func (prwe *prwExporter) walEnabled() bool { return prwe.wal != nil }
 
 func (prwe *prwExporter) turnOnWALIfEnabled(ctx context.Context) error {
@@ -17,6 +17,7 @@ import (
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -29,6 +30,7 @@ import (
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -804,6 +806,105 @@ func Test_PushMetrics(t *testing.T) {
 	}
 }
 
+func Test_PushMetricsV2(t *testing.T) {
+	md := getMetricsFromMetricList(validMetrics1[validSum], validMetrics1[validIntGauge])
+	counter := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	counter.SetDescription("Number of things")
+	counter.Sum().SetIsMonotonic(true)
+	counter.Sum().DataPoints().At(0).SetStartTimestamp(pcommon.Timestamp(time1 - 1000000))
+
+	tests := []struct {
+		name       string
+		supportsV2 bool
+		expectedV1 int
+		expectedV2 int
+		fallenBack bool
+	}{
+		{
+			name:       "remote write 2.0",
+			supportsV2: true,
+			expectedV2: 2,
+		},
+		{
+			name:       "fall back to remote write 1.0",
+			expectedV1: 2,
+			expectedV2: 1,
+			fallenBack: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var v1Requests, v2Requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				buf, err := snappy.Decode(nil, body)
+				require.NoError(t, err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				switch r.Header.Get("Content-Type") {
+				case "application/x-protobuf;proto=io.prometheus.write.v2.Request":
+					v2Requests++
+					assert.Equal(t, "2.0.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+					if !tt.supportsV2 {
+						w.WriteHeader(http.StatusUnsupportedMediaType)
+						return
+					}
+					var req writev2.Request
+					require.NoError(t, req.Unmarshal(buf))
+					require.Len(t, req.Timeseries, 2)
+					types := map[writev2.Metadata_MetricType]writev2.TimeSeries{}
+					for _, ts := range req.Timeseries {
+						types[ts.Metadata.Type] = ts
+					}
+					require.Contains(t, types, writev2.Metadata_METRIC_TYPE_COUNTER)
+					require.Contains(t, types, writev2.Metadata_METRIC_TYPE_GAUGE)
+					assert.Equal(t, "Number of things", req.Symbols[types[writev2.Metadata_METRIC_TYPE_COUNTER].Metadata.HelpRef])
+					assert.Equal(t, int64(time1/1000000-1), types[writev2.Metadata_METRIC_TYPE_COUNTER].CreatedTimestamp)
+				case "application/x-protobuf":
+					v1Requests++
+					assert.Equal(t, "0.1.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+					var req prompb.WriteRequest
+					require.NoError(t, proto.Unmarshal(buf, &req))
+					assert.Len(t, req.Timeseries, 2)
+				default:
+					t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			cfg := &Config{
+				ClientConfig: confighttp.ClientConfig{
+					Endpoint: server.URL,
+				},
+				MaxBatchSizeBytes:   3000000,
+				RemoteWriteQueue:    RemoteWriteQueue{NumConsumers: 1},
+				TargetInfo:          &TargetInfo{Enabled: true},
+				CreatedMetric:       &CreatedMetric{Enabled: false},
+				RemoteWriteProtoMsg: RemoteWriteProtoMsgV2,
+			}
+			prwe, err := newPRWExporter(cfg, exportertest.NewNopSettings())
+			require.NoError(t, err)
+			ctx := context.Background()
+			require.NoError(t, prwe.Start(ctx, componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, prwe.Shutdown(ctx))
+			}()
+
+			// The second push is sent with remote write 1.0 right away once fallen back.
+			require.NoError(t, prwe.PushMetrics(ctx, md))
+			require.NoError(t, prwe.PushMetrics(ctx, md))
+			assert.Equal(t, tt.expectedV1, v1Requests)
+			assert.Equal(t, tt.expectedV2, v2Requests)
+			assert.Equal(t, tt.fallenBack, prwe.fallbackToV1.Load())
+		})
+	}
+}
+
 func Test_validateAndSanitizeExternalLabels(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -9,6 +9,7 @@ import (
 	"sort"
 
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 )
 
 type batchTimeSeriesState struct {
@@ -93,6 +94,51 @@ func batchTimeSeries(tsMap map[string]*prompb.TimeSeries, maxBatchByteSize int,
 	return requests, nil
 }
 
+// batchTimeSeriesV2 splits remote write 2.0 series into multiple batch write requests, each of them
+// with the symbols referenced by its series.
+func batchTimeSeriesV2(tsMap map[string]*writev2.TimeSeries, symbols []string, maxBatchByteSize int, state *batchTimeSeriesState) ([]*writev2.Request, error) {
+	if len(tsMap) == 0 {
+		return nil, errors.New("invalid tsMap: cannot be empty map")
+	}
+
+	// Allocate a buffer size of at least 10, or twice the last # of requests we sent
+	requests := make([]*writev2.Request, 0, max(10, state.nextRequestBufferSize))
+
+	// Allocate a time series buffer 2x the last time series batch size or the length of the input if smaller
+	tsArray := make([]writev2.TimeSeries, 0, min(state.nextTimeSeriesBufferSize, len(tsMap)))
+	batchSymbols := writev2.NewSymbolTable()
+	sizeOfCurrentBatch := 0
+
+	i := 0
+	for _, v := range tsMap {
+		batchSymbolsLen := len(batchSymbols.Symbols())
+		ts, sizeOfSeries := resymbolize(v, symbols, &batchSymbols)
+
+		if sizeOfCurrentBatch+sizeOfSeries >= maxBatchByteSize && len(tsArray) > 0 {
+			state.nextTimeSeriesBufferSize = max(10, 2*len(tsArray))
+			// Leave out the symbols only referenced by the series, which starts the next batch.
+			wrapped := convertTimeseriesToRequestV2(tsArray, batchSymbols.Symbols()[:batchSymbolsLen])
+			requests = append(requests, wrapped)
+
+			tsArray = make([]writev2.TimeSeries, 0, min(state.nextTimeSeriesBufferSize, len(tsMap)-i))
+			batchSymbols = writev2.NewSymbolTable()
+			ts, sizeOfSeries = resymbolize(v, symbols, &batchSymbols)
+			sizeOfCurrentBatch = 0
+		}
+
+		tsArray = append(tsArray, ts)
+		sizeOfCurrentBatch += sizeOfSeries
+		i++
+	}
+
+	if len(tsArray) != 0 {
+		requests = append(requests, convertTimeseriesToRequestV2(tsArray, batchSymbols.Symbols()))
+	}
+
+	state.nextRequestBufferSize = 2 * len(requests)
+	return requests, nil
+}
+
 func convertTimeseriesToRequest(tsArray []prompb.TimeSeries) *prompb.WriteRequest {
 	// the remote_write endpoint only requires the timeseries.
 	// otlp defines it's own way to handle metric metadata
@@ -111,6 +157,50 @@ func convertMetadataToRequest(m []prompb.MetricMetadata) *prompb.WriteRequest {
 	}
 }
 
+func convertTimeseriesToRequestV2(tsArray []writev2.TimeSeries, symbols []string) *writev2.Request {
+	for i := range tsArray {
+		sL := tsArray[i].Samples
+		sort.Slice(sL, func(i, j int) bool {
+			return sL[i].Timestamp < sL[j].Timestamp
+		})
+	}
+	return &writev2.Request{
+		Symbols:    symbols,
+		Timeseries: tsArray,
+	}
+}
+
+// resymbolize returns a copy of a series referencing the symbols of batchSymbols instead of symbols,
+// with its size including the symbols it added to batchSymbols.
+func resymbolize(ts *writev2.TimeSeries, symbols []string, batchSymbols *writev2.SymbolsTable) (writev2.TimeSeries, int) {
+	symbolsLen := len(batchSymbols.Symbols())
+	refs := func(from []uint32) []uint32 {
+		to := make([]uint32, len(from))
+		for i, ref := range from {
+			to[i] = batchSymbols.Symbolize(symbols[ref])
+		}
+		return to
+	}
+
+	out := *ts
+	out.LabelsRefs = refs(ts.LabelsRefs)
+	out.Metadata.HelpRef = batchSymbols.Symbolize(symbols[ts.Metadata.HelpRef])
+	out.Metadata.UnitRef = batchSymbols.Symbolize(symbols[ts.Metadata.UnitRef])
+	if len(ts.Exemplars) > 0 {
+		out.Exemplars = make([]writev2.Exemplar, len(ts.Exemplars))
+		for i, e := range ts.Exemplars {
+			e.LabelsRefs = refs(e.LabelsRefs)
+			out.Exemplars[i] = e
+		}
+	}
+
+	size := out.Size()
+	for _, symbol := range batchSymbols.Symbols()[symbolsLen:] {
+		size += len(symbol)
+	}
+	return out, size
+}
+
 func orderBySampleTimestamp(tsArray []prompb.TimeSeries) []prompb.TimeSeries {
 	for i := range tsArray {
 		sL := tsArray[i].Samples
@@ -7,8 +7,11 @@ import (
 	"math"
 	"testing"
 
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Test_batchTimeSeries checks batchTimeSeries return the correct number of requests
@@ -80,6 +83,49 @@ func Test_batchTimeSeries(t *testing.T) {
 	}
 }
 
+// Test_batchTimeSeriesV2 checks batchTimeSeriesV2 splits the series depending on byte size,
+// each request with the symbols of its series.
+func Test_batchTimeSeriesV2(t *testing.T) {
+	symbols := writev2.NewSymbolTable()
+	tsMap := map[string]*writev2.TimeSeries{}
+	for i, name := range []string{"first", "second", "third"} {
+		tsMap[name] = &writev2.TimeSeries{
+			LabelsRefs: symbols.SymbolizeLabels(labels.FromStrings("__name__", name, label11, value11), nil),
+			Samples:    []writev2.Sample{{Value: floatVal1, Timestamp: msTime1}},
+			Metadata: writev2.Metadata{
+				Type:    writev2.Metadata_METRIC_TYPE_GAUGE,
+				HelpRef: symbols.Symbolize("help of " + name),
+			},
+			CreatedTimestamp: int64(i),
+		}
+	}
+
+	state := newBatchTimeSericesState()
+	_, err := batchTimeSeriesV2(map[string]*writev2.TimeSeries{}, symbols.Symbols(), 100, &state)
+	assert.Error(t, err)
+
+	requests, err := batchTimeSeriesV2(tsMap, symbols.Symbols(), 3000000, &state)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Len(t, requests[0].Timeseries, 3)
+	assert.Len(t, requests[0].Symbols, len(symbols.Symbols()))
+
+	requests, err = batchTimeSeriesV2(tsMap, symbols.Symbols(), 60, &state)
+	require.NoError(t, err)
+	require.Len(t, requests, 3)
+	b := labels.NewScratchBuilder(0)
+	for _, req := range requests {
+		require.Len(t, req.Timeseries, 1)
+		ts := req.Timeseries[0]
+		name := ts.ToLabels(&b, req.Symbols).Get("__name__")
+		assert.Equal(t, labels.FromStrings("__name__", name, label11, value11), ts.ToLabels(&b, req.Symbols))
+		assert.Equal(t, "help of "+name, req.Symbols[ts.Metadata.HelpRef])
+		assert.Equal(t, "", req.Symbols[ts.Metadata.UnitRef])
+		assert.Equal(t, tsMap[name].CreatedTimestamp, ts.CreatedTimestamp)
+		assert.Len(t, req.Symbols, 6, "only the symbols of the series are sent")
+	}
+}
+
 func Test_batchTimeSeriesUpdatesStateForLargeBatches(t *testing.T) {
 	labels := getPromLabels(label11, value11, label12, value12, label21, value21, label22, value22)
 	sample1 := getSample(floatVal1, msTime1)
@@ -30,6 +30,10 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 type TelemetryBuilder struct {
 	meter                                             metric.Meter
 	ExporterPrometheusremotewriteFailedTranslations   metric.Int64Counter
+	ExporterPrometheusremotewriteTenantDroppedBatches metric.Int64Counter
+	ExporterPrometheusremotewriteTenantFailedBatches  metric.Int64Counter
+	ExporterPrometheusremotewriteTenantQueuedBatches  metric.Int64UpDownCounter
+	ExporterPrometheusremotewriteTenantSentBatches    metric.Int64Counter
 	ExporterPrometheusremotewriteTranslatedTimeSeries metric.Int64Counter
 	meters                                            map[configtelemetry.Level]metric.Meter
 }
@@ -52,6 +56,30 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteTenantDroppedBatches, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_exporter_prometheusremotewrite_tenant_dropped_batches",
+		metric.WithDescription("Number of metric batches dropped because they have no valid tenant or because the queue of their tenant is full"),
+		metric.WithUnit("{batches}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteTenantFailedBatches, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_exporter_prometheusremotewrite_tenant_failed_batches",
+		metric.WithDescription("Number of metric batches of each tenant that failed to be sent to the endpoint"),
+		metric.WithUnit("{batches}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteTenantQueuedBatches, err = builder.meters[configtelemetry.LevelBasic].Int64UpDownCounter(
+		"otelcol_exporter_prometheusremotewrite_tenant_queued_batches",
+		metric.WithDescription("Number of metric batches waiting in the queue of each tenant"),
+		metric.WithUnit("{batches}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteTenantSentBatches, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_exporter_prometheusremotewrite_tenant_sent_batches",
+		metric.WithDescription("Number of metric batches of each tenant sent to the endpoint, or written to the WAL of the tenant"),
+		metric.WithUnit("{batches}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ExporterPrometheusremotewriteTranslatedTimeSeries, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
 		"otelcol_exporter_prometheusremotewrite_translated_time_series",
 		metric.WithDescription("Number of Prometheus time series that were translated from OTel metrics"),
@@ -0,0 +1,410 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTenantHeader    = "X-Scope-OrgID"
+	defaultTenantQueueSize = 100
+	// defaultTenantIdleTimeout is the time after which the queue of a tenant without metrics is stopped.
+	defaultTenantIdleTimeout = 15 * time.Minute
+	// maxTenantLength is the maximum length of the tenants of Cortex and Mimir.
+	maxTenantLength = 150
+	// tenantWALDirectory is the directory of the WAL of each tenant, under the WAL directory.
+	tenantWALDirectory = "prom_remotewrite_tenants"
+)
+
+// TenantConfig configures the routing of the metrics to the tenants of multi-tenant endpoints
+// such as Cortex and Mimir.
+type TenantConfig struct {
+	// ResourceAttribute is the resource attribute whose value is the tenant of the metrics.
+	ResourceAttribute string `mapstructure:"resource_attribute"`
+
+	// Header is the HTTP header carrying the tenant of the requests, X-Scope-OrgID by default.
+	Header string `mapstructure:"header"`
+
+	// DefaultTenant is the tenant of the metrics without the resource attribute,
+	// such metrics are dropped if it is empty.
+	DefaultTenant string `mapstructure:"default_tenant"`
+
+	// QueueSize is the maximum number of metric batches waiting to be sent for each tenant,
+	// the batches of a tenant whose queue is full are dropped.
+	QueueSize int `mapstructure:"queue_size"`
+
+	// IdleTimeout is the time after which the queue of a tenant without metrics is stopped,
+	// along with its worker and WAL, 15 minutes by default.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+}
+
+func (tc *TenantConfig) header() string {
+	if tc.Header != "" {
+		return tc.Header
+	}
+	return defaultTenantHeader
+}
+
+func (tc *TenantConfig) queueSize() int {
+	if tc.QueueSize > 0 {
+		return tc.QueueSize
+	}
+	return defaultTenantQueueSize
+}
+
+func (tc *TenantConfig) idleTimeout() time.Duration {
+	if tc.IdleTimeout > 0 {
+		return tc.IdleTimeout
+	}
+	return defaultTenantIdleTimeout
+}
+
+func (tc *TenantConfig) validate(headers map[string]configopaque.String) error {
+	if tc.ResourceAttribute == "" {
+		return errors.New("tenant resource_attribute must be set")
+	}
+	if tc.QueueSize < 0 {
+		return errors.New("tenant queue_size can't be negative")
+	}
+	if tc.IdleTimeout < 0 {
+		return errors.New("tenant idle_timeout can't be negative")
+	}
+	for name := range headers {
+		// The headers of the client settings override those of the requests.
+		if http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(tc.header()) {
+			return fmt.Errorf("the tenant header %q can't be set in headers", name)
+		}
+	}
+	if tc.DefaultTenant != "" {
+		if err := validateTenant(tc.DefaultTenant); err != nil {
+			return fmt.Errorf("invalid tenant default_tenant: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateTenant checks that the tenant is supported by Cortex and Mimir, the tenants made of
+// the supported characters are also valid file names once escaped.
+func validateTenant(tenant string) error {
+	if tenant == "" {
+		return errors.New("empty tenant")
+	}
+	if len(tenant) > maxTenantLength {
+		return fmt.Errorf("tenant %q is longer than %d characters", tenant, maxTenantLength)
+	}
+	if tenant == "." || tenant == ".." {
+		return fmt.Errorf("tenant %q is not supported", tenant)
+	}
+	for _, r := range tenant {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '!', r == '-', r == '_', r == '.', r == '*', r == '\'', r == '(', r == ')':
+		default:
+			return fmt.Errorf("tenant %q contains unsupported character %q", tenant, r)
+		}
+	}
+	return nil
+}
+
+// tenantRouter routes the metrics to the queues of their tenants. The queue of each tenant is
+// sent by its own goroutine, so tenants whose requests are slow or failing don't hold back the others.
+// The queues of the tenants without metrics for the idle timeout are stopped.
+type tenantRouter struct {
+	cfg       *TenantConfig
+	walConfig *WALConfig
+	// retry configures how the batches failing with a recoverable error are sent again.
+	retry     configretry.BackOffConfig
+	logger    *zap.Logger
+	telemetry prwTelemetry
+	// push sends the metrics of a tenant, persisting them to its WAL if not nil.
+	push func(ctx context.Context, md pmetric.Metrics, wal *prweWAL, state *batchTimeSeriesState) error
+	// export sends the requests read from the WAL of a tenant.
+	export func(ctx context.Context, requests []*prompb.WriteRequest) error
+
+	// ctx is the context of the queues, it is cancelled once the exporter is shut down.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex // mu protects the fields below.
+	queues  map[string]*tenantQueue
+	stopped bool
+
+	wg sync.WaitGroup
+}
+
+type tenantQueue struct {
+	tenant  string
+	batches chan pmetric.Metrics
+	wal     *prweWAL
+	state   batchTimeSeriesState
+}
+
+func newTenantRouter(cfg *TenantConfig, walConfig *WALConfig, retry configretry.BackOffConfig, logger *zap.Logger, telemetry prwTelemetry,
+	push func(context.Context, pmetric.Metrics, *prweWAL, *batchTimeSeriesState) error, export func(context.Context, []*prompb.WriteRequest) error) *tenantRouter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &tenantRouter{
+		cfg:       cfg,
+		walConfig: walConfig,
+		retry:     retry,
+		logger:    logger,
+		telemetry: telemetry,
+		push:      push,
+		export:    export,
+		ctx:       ctx,
+		cancel:    cancel,
+		queues:    map[string]*tenantQueue{},
+	}
+}
+
+// start starts sending the WAL of the tenants written before the exporter was stopped.
+func (r *tenantRouter) start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.walConfig == nil {
+		return nil
+	}
+	entries, err := os.ReadDir(filepath.Join(r.walConfig.Directory, tenantWALDirectory))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("prometheusremotewriteexporter: failed to list the tenant WALs: %w", err)
+	}
+	for _, entry := range entries {
+		tenant, err := url.QueryUnescape(entry.Name())
+		if err != nil || !entry.IsDir() || validateTenant(tenant) != nil {
+			r.logger.Warn("ignoring unexpected entry of the tenant WAL directory", zap.String("name", entry.Name()))
+			continue
+		}
+		if _, err := r.queueLocked(tenant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// route adds the metrics of each tenant to its queue. The metrics without tenant and the metrics of
+// tenants whose queue is full are dropped, the other tenants are unaffected.
+func (r *tenantRouter) route(ctx context.Context, md pmetric.Metrics) error {
+	byTenant, errs := r.split(ctx, md)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return errors.New("shutdown has been called")
+	}
+
+	for tenant, tmd := range byTenant {
+		tctx := contextWithTenant(ctx, tenant)
+		q, err := r.queueLocked(tenant)
+		if err != nil {
+			r.telemetry.recordTenantDroppedBatch(tctx)
+			errs = errors.Join(errs, err)
+			continue
+		}
+		r.telemetry.recordTenantQueuedBatches(tctx, 1)
+		select {
+		case q.batches <- tmd:
+		default:
+			r.telemetry.recordTenantQueuedBatches(tctx, -1)
+			r.telemetry.recordTenantDroppedBatch(tctx)
+			errs = errors.Join(errs, fmt.Errorf("queue of tenant %q is full, dropping metrics", tenant))
+		}
+	}
+	if errs != nil {
+		// The metrics of the other tenants are already queued, retrying would duplicate them.
+		return consumererror.NewPermanent(errs)
+	}
+	return nil
+}
+
+// split splits the metrics by the tenant of their resource.
+func (r *tenantRouter) split(ctx context.Context, md pmetric.Metrics) (map[string]pmetric.Metrics, error) {
+	var errs error
+	byTenant := map[string]pmetric.Metrics{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		tenant := r.cfg.DefaultTenant
+		if v, ok := rm.Resource().Attributes().Get(r.cfg.ResourceAttribute); ok {
+			tenant = v.AsString()
+		}
+		if tenant == "" {
+			r.telemetry.recordTenantDroppedBatch(ctx)
+			errs = errors.Join(errs, fmt.Errorf("dropping metrics without the %q resource attribute", r.cfg.ResourceAttribute))
+			continue
+		}
+		if err := validateTenant(tenant); err != nil {
+			r.telemetry.recordTenantDroppedBatch(ctx)
+			errs = errors.Join(errs, fmt.Errorf("dropping metrics: %w", err))
+			continue
+		}
+		tmd, ok := byTenant[tenant]
+		if !ok {
+			tmd = pmetric.NewMetrics()
+			byTenant[tenant] = tmd
+		}
+		rm.CopyTo(tmd.ResourceMetrics().AppendEmpty())
+	}
+	return byTenant, errs
+}
+
+// queueLocked returns the queue of the tenant, starting it if needed. r.mu must be held.
+func (r *tenantRouter) queueLocked(tenant string) (*tenantQueue, error) {
+	if q, ok := r.queues[tenant]; ok {
+		return q, nil
+	}
+
+	q := &tenantQueue{
+		tenant:  tenant,
+		batches: make(chan pmetric.Metrics, r.cfg.queueSize()),
+		state:   newBatchTimeSericesState(),
+	}
+	tctx := contextWithTenant(r.ctx, tenant)
+	if r.walConfig != nil {
+		walPath := filepath.Join(r.walConfig.Directory, tenantWALDirectory, url.QueryEscape(tenant))
+		q.wal = newWALAt(r.walConfig, walPath, r.export)
+		if err := q.wal.run(contextWithLogger(tctx, r.logger.Named("prw.wal").With(zap.String("tenant", tenant)))); err != nil {
+			return nil, fmt.Errorf("failed to start the WAL of tenant %q: %w", tenant, err)
+		}
+	}
+	r.queues[tenant] = q
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.send(tctx, q)
+	}()
+	return q, nil
+}
+
+// send sends the batches of the queue in order until it is closed, or until it is idle.
+func (r *tenantRouter) send(ctx context.Context, q *tenantQueue) {
+	idle := time.NewTimer(r.cfg.idleTimeout())
+	defer idle.Stop()
+	for {
+		select {
+		case md, ok := <-q.batches:
+			if !ok {
+				return
+			}
+			r.telemetry.recordTenantQueuedBatches(ctx, -1)
+			r.sendBatch(ctx, q, md)
+		case <-idle.C:
+			if r.evict(q) {
+				return
+			}
+		}
+		idle.Reset(r.cfg.idleTimeout())
+	}
+}
+
+// sendBatch sends a batch of the queue. The batches failing with a recoverable error are sent
+// again until they succeed or the exporter is shut down, the following batches of the tenant
+// wait in the queue meanwhile.
+func (r *tenantRouter) sendBatch(ctx context.Context, q *tenantQueue, md pmetric.Metrics) {
+	retry := &backoff.ExponentialBackOff{
+		InitialInterval:     r.retry.InitialInterval,
+		RandomizationFactor: r.retry.RandomizationFactor,
+		Multiplier:          r.retry.Multiplier,
+		MaxInterval:         r.retry.MaxInterval,
+		Stop:                backoff.Stop,
+		Clock:               backoff.SystemClock,
+	}
+	retry.Reset()
+	for {
+		err := r.push(ctx, md, q.wal, &q.state)
+		if err == nil {
+			r.telemetry.recordTenantSentBatch(ctx)
+			return
+		}
+
+		var recoverable *recoverableError
+		if !r.retry.Enabled || !errors.As(err, &recoverable) {
+			r.telemetry.recordTenantFailedBatch(ctx)
+			r.logger.Error("failed to send the metrics of tenant", zap.String("tenant", q.tenant), zap.Error(err))
+			return
+		}
+		delay := retry.NextBackOff()
+		r.logger.Warn("failed to send the metrics of tenant, sending them again", zap.String("tenant", q.tenant), zap.Duration("delay", delay), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			r.telemetry.recordTenantFailedBatch(ctx)
+			r.logger.Error("failed to send the metrics of tenant before shutdown", zap.String("tenant", q.tenant), zap.Error(err))
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// evict stops the queue if no batch was added to it, it returns false otherwise.
+// The WAL of the tenant is stopped too, its remaining requests are sent once the
+// queue is started again.
+func (r *tenantRouter) evict(q *tenantQueue) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped || len(q.batches) > 0 {
+		return false
+	}
+	delete(r.queues, q.tenant)
+	if q.wal != nil {
+		if err := q.wal.stop(); err != nil {
+			r.logger.Warn("failed to stop the WAL of tenant", zap.String("tenant", q.tenant), zap.Error(err))
+		}
+	}
+	return true
+}
+
+// shutdown sends the queued batches and stops the WAL of the tenants. The batches
+// still queued when ctx is done are dropped.
+func (r *tenantRouter) shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil
+	}
+	r.stopped = true
+	for _, q := range r.queues {
+		close(q.batches)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	r.cancel()
+	<-done
+
+	var errs error
+	for _, q := range r.queues {
+		if q.wal != nil {
+			errs = errors.Join(errs, q.wal.stop())
+		}
+	}
+	return errs
+}
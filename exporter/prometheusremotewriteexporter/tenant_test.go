@@ -0,0 +1,302 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestValidateTenant(t *testing.T) {
+	tests := []struct {
+		tenant  string
+		wantErr string
+	}{
+		{tenant: "team-a"},
+		{tenant: "Team_A.prod"},
+		{tenant: "a!*'()"},
+		{tenant: "", wantErr: "empty tenant"},
+		{tenant: "..", wantErr: `tenant ".." is not supported`},
+		{tenant: "team/a", wantErr: `tenant "team/a" contains unsupported character '/'`},
+		{tenant: "team a", wantErr: `tenant "team a" contains unsupported character ' '`},
+		{tenant: strings.Repeat("a", 151), wantErr: "is longer than 150 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tenant, func(t *testing.T) {
+			err := validateTenant(tt.tenant)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+// tenantServer is a remote write endpoint counting the requests of each tenant,
+// the requests of the "slow" tenant are blocked until release is closed, and the
+// first requests of the "unavailable" tenant fail.
+type tenantServer struct {
+	*httptest.Server
+	release chan struct{}
+	started chan struct{}
+
+	mu          sync.Mutex
+	received    map[string]int
+	unavailable int
+}
+
+func newTenantServer(t *testing.T) *tenantServer {
+	s := &tenantServer{
+		release:  make(chan struct{}),
+		started:  make(chan struct{}, 10),
+		received: map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get("X-Scope-OrgID")
+		if tenant == "slow" {
+			s.started <- struct{}{}
+			<-s.release
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if tenant == "unavailable" && s.unavailable > 0 {
+			s.unavailable--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		s.received[tenant]++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tenantServer) requests(tenant string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received[tenant]
+}
+
+func newTenantExporter(t *testing.T, endpoint string, tt componentTestTelemetry, configure func(*Config)) *prwExporter {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClientConfig.Endpoint = endpoint
+	cfg.BackOffConfig.Enabled = false
+	cfg.Tenant = &TenantConfig{ResourceAttribute: "tenant.id"}
+	if configure != nil {
+		configure(cfg)
+	}
+	require.NoError(t, cfg.Validate())
+
+	prwe, err := newPRWExporter(cfg, tt.NewSettings())
+	require.NoError(t, err)
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+	return prwe
+}
+
+func tenantMetrics(tenants ...string) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for _, tenant := range tenants {
+		rm := md.ResourceMetrics().AppendEmpty()
+		if tenant != "" {
+			rm.Resource().Attributes().PutStr("tenant.id", tenant)
+		}
+		m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("test_gauge")
+		dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+		dp.SetDoubleValue(1)
+	}
+	return md
+}
+
+func tenantBatches(t *testing.T, tt componentTestTelemetry, name string, tenant string) int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, tt.reader.Collect(context.Background(), &rm))
+	sum, ok := tt.getMetric(name, rm).Data.(metricdata.Sum[int64])
+	if !ok {
+		return 0
+	}
+	for _, dp := range sum.DataPoints {
+		if v, ok := dp.Attributes.Value(attribute.Key("tenant")); ok && v.AsString() == tenant {
+			return dp.Value
+		}
+	}
+	return 0
+}
+
+func TestTenantRouting(t *testing.T) {
+	server := newTenantServer(t)
+	tt := setupTestTelemetry()
+	prwe := newTenantExporter(t, server.URL, tt, nil)
+
+	err := prwe.PushMetrics(context.Background(), tenantMetrics("slow", "fast", ""))
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.ErrorContains(t, err, `dropping metrics without the "tenant.id" resource attribute`)
+
+	// The requests of the slow tenant don't hold back those of the other tenants.
+	<-server.started
+	assert.Eventually(t, func() bool { return server.requests("fast") == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, server.requests("slow"))
+	assert.Equal(t, 0, server.requests(""))
+
+	close(server.release)
+	assert.Eventually(t, func() bool { return server.requests("slow") == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, prwe.Shutdown(context.Background()))
+
+	assert.Equal(t, int64(1), tenantBatches(t, tt, "otelcol_exporter_prometheusremotewrite_tenant_sent_batches", "fast"))
+	assert.Equal(t, int64(1), tenantBatches(t, tt, "otelcol_exporter_prometheusremotewrite_tenant_sent_batches", "slow"))
+	assert.Equal(t, int64(0), tenantBatches(t, tt, "otelcol_exporter_prometheusremotewrite_tenant_queued_batches", "slow"))
+	require.NoError(t, tt.Shutdown(context.Background()))
+}
+
+func TestTenantRoutingDefaultTenant(t *testing.T) {
+	server := newTenantServer(t)
+	tt := setupTestTelemetry()
+	prwe := newTenantExporter(t, server.URL, tt, func(cfg *Config) {
+		cfg.Tenant.DefaultTenant = "anonymous"
+	})
+
+	require.NoError(t, prwe.PushMetrics(context.Background(), tenantMetrics("", "fast")))
+	assert.Eventually(t, func() bool {
+		return server.requests("anonymous") == 1 && server.requests("fast") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, prwe.Shutdown(context.Background()))
+	require.NoError(t, tt.Shutdown(context.Background()))
+}
+
+func TestTenantQueueFull(t *testing.T) {
+	server := newTenantServer(t)
+	tt := setupTestTelemetry()
+	prwe := newTenantExporter(t, server.URL, tt, func(cfg *Config) {
+		cfg.Tenant.QueueSize = 1
+	})
+
+	// The first batch is being sent, the second one is queued.
+	require.NoError(t, prwe.PushMetrics(context.Background(), tenantMetrics("slow")))
+	<-server.started
+	require.NoError(t, prwe.PushMetrics(context.Background(), tenantMetrics("slow")))
+
+	err := prwe.PushMetrics(context.Background(), tenantMetrics("slow", "fast"))
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.ErrorContains(t, err, `queue of tenant "slow" is full`)
+	assert.Eventually(t, func() bool { return server.requests("fast") == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(1), tenantBatches(t, tt, "otelcol_exporter_prometheusremotewrite_tenant_dropped_batches", "slow"))
+	assert.Equal(t, int64(1), tenantBatches(t, tt, "otelcol_exporter_prometheusremotewrite_tenant_queued_batches", "slow"))
+
+	close(server.release)
+	require.NoError(t, prwe.Shutdown(context.Background()))
+	assert.Equal(t, 2, server.requests("slow"))
+	require.NoError(t, tt.Shutdown(context.Background()))
+}
+
+func TestTenantRetry(t *testing.T) {
+	server := newTenantServer(t)
+	server.unavailable = 5
+	tt := setupTestTelemetry()
+	prwe := newTenantExporter(t, server.URL, tt, func(cfg *Config) {
+		cfg.BackOffConfig.Enabled = true
+		cfg.BackOffConfig.InitialInterval = 10 * time.Millisecond
+		cfg.BackOffConfig.MaxInterval = 10 * time.Millisecond
+		cfg.BackOffConfig.MaxElapsedTime = 15 * time.Millisecond
+	})
+
+	// The batch is sent again by the queue once the retries of the request are exhausted.
+	require.NoError(t, prwe.PushMetrics(context.Background(), tenantMetrics("unavailable")))
+	assert.Eventually(t, func() bool { return server.requests("unavailable") == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, prwe.Shutdown(context.Background()))
+
+	assert.Equal(t, int64(1), tenantBatches(t, tt, "otelcol_exporter_prometheusremotewrite_tenant_sent_batches", "unavailable"))
+	assert.Equal(t, int64(0), tenantBatches(t, tt, "otelcol_exporter_prometheusremotewrite_tenant_failed_batches", "unavailable"))
+	require.NoError(t, tt.Shutdown(context.Background()))
+}
+
+func TestTenantRetryDisabled(t *testing.T) {
+	server := newTenantServer(t)
+	server.unavailable = 1
+	tt := setupTestTelemetry()
+	prwe := newTenantExporter(t, server.URL, tt, nil)
+
+	require.NoError(t, prwe.PushMetrics(context.Background(), tenantMetrics("unavailable")))
+	assert.Eventually(t, func() bool {
+		return tenantBatches(t, tt, "otelcol_exporter_prometheusremotewrite_tenant_failed_batches", "unavailable") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, prwe.Shutdown(context.Background()))
+	assert.Equal(t, 0, server.requests("unavailable"))
+	require.NoError(t, tt.Shutdown(context.Background()))
+}
+
+func TestTenantIdleQueue(t *testing.T) {
+	server := newTenantServer(t)
+	tt := setupTestTelemetry()
+	prwe := newTenantExporter(t, server.URL, tt, func(cfg *Config) {
+		cfg.Tenant.IdleTimeout = 20 * time.Millisecond
+		cfg.WAL = &WALConfig{Directory: t.TempDir(), BufferSize: 1}
+	})
+
+	queues := func() int {
+		prwe.tenants.mu.Lock()
+		defer prwe.tenants.mu.Unlock()
+		return len(prwe.tenants.queues)
+	}
+
+	require.NoError(t, prwe.PushMetrics(context.Background(), tenantMetrics("fast")))
+	assert.Eventually(t, func() bool { return server.requests("fast") >= 1 }, 10*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return queues() == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// The queue of the tenant is started again with its next metrics.
+	sent := server.requests("fast")
+	require.NoError(t, prwe.PushMetrics(context.Background(), tenantMetrics("fast")))
+	assert.Eventually(t, func() bool { return server.requests("fast") > sent }, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, prwe.Shutdown(context.Background()))
+	require.NoError(t, tt.Shutdown(context.Background()))
+}
+
+func TestTenantWAL(t *testing.T) {
+	server := newTenantServer(t)
+	dir := t.TempDir()
+	configure := func(cfg *Config) {
+		cfg.WAL = &WALConfig{Directory: dir, BufferSize: 1}
+	}
+
+	tt := setupTestTelemetry()
+	prwe := newTenantExporter(t, server.URL, tt, configure)
+	require.NoError(t, prwe.PushMetrics(context.Background(), tenantMetrics("team-a", "team(b)")))
+	assert.Eventually(t, func() bool {
+		// The WAL is sent at least once.
+		return server.requests("team-a") >= 1 && server.requests("team(b)") >= 1
+	}, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, prwe.Shutdown(context.Background()))
+	require.NoError(t, tt.Shutdown(context.Background()))
+
+	assert.DirExists(t, filepath.Join(dir, tenantWALDirectory, "team-a"))
+	assert.DirExists(t, filepath.Join(dir, tenantWALDirectory, "team%28b%29"))
+
+	// The WAL of the tenants are sent again once the exporter is restarted.
+	tt = setupTestTelemetry()
+	prwe = newTenantExporter(t, server.URL, tt, configure)
+	prwe.tenants.mu.Lock()
+	assert.Len(t, prwe.tenants.queues, 2)
+	assert.Contains(t, prwe.tenants.queues, "team(b)")
+	prwe.tenants.mu.Unlock()
+	require.NoError(t, prwe.Shutdown(context.Background()))
+	require.NoError(t, tt.Shutdown(context.Background()))
+}
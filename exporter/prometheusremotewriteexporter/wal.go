@@ -66,17 +66,30 @@ func newWAL(walConfig *WALConfig, exportSink func(context.Context, []*prompb.Wri
 		return nil
 	}
 
+	return newWALAt(walConfig, walConfig.path(), exportSink)
+}
+
+// newWALAt creates a WAL stored in walPath rather than in the WAL directory.
+func newWALAt(walConfig *WALConfig, walPath string, exportSink func(context.Context, []*prompb.WriteRequest) error) *prweWAL {
 	return &prweWAL{
 		exportSink: exportSink,
 		walConfig:  walConfig,
+		walPath:    walPath,
 		stopChan:   make(chan struct{}),
 		rWALIndex:  &atomic.Uint64{},
 		wWALIndex:  &atomic.Uint64{},
 	}
 }
 
+func (wc *WALConfig) path() string {
+	return filepath.Join(wc.Directory, "prom_remotewrite")
+}
+
 func (wc *WALConfig) createWAL() (*wal.Log, string, error) {
-	walPath := filepath.Join(wc.Directory, "prom_remotewrite")
+	return wc.openWAL(wc.path())
+}
+
+func (wc *WALConfig) openWAL(walPath string) (*wal.Log, string, error) {
 	log, err := wal.Open(walPath, &wal.Options{
 		SegmentCacheSize: wc.bufferSize(),
 		NoCopy:           true,
@@ -97,18 +110,24 @@ func (prwe *prweWAL) retrieveWALIndices() (err error) {
 	prwe.mu.Lock()
 	defer prwe.mu.Unlock()
 
+	// The WAL isn't opened again once stopped, when its reading fails while stopping.
+	select {
+	case <-prwe.stopChan:
+		return errAlreadyClosed
+	default:
+	}
+
 	err = prwe.closeWAL()
 	if err != nil {
 		return err
 	}
 
-	log, walPath, err := prwe.walConfig.createWAL()
+	log, _, err := prwe.walConfig.openWAL(prwe.walPath)
 	if err != nil {
 		return err
 	}
 
 	prwe.wal = log
-	prwe.walPath = walPath
 
 	rIndex, err := prwe.wal.FirstIndex()
 	if err != nil {
@@ -272,8 +291,9 @@ func (prwe *prweWAL) syncAndTruncateFront() error {
 		return err
 	}
 	// Truncate the WAL from the front for the entries that we already
-	// read from the WAL and had already exported.
-	if err := prwe.wal.TruncateFront(prwe.rWALIndex.Load()); err != nil && !errors.Is(err, wal.ErrOutOfRange) {
+	// read from the WAL and had already exported. The WAL can't be emptied,
+	// so the last entry read is kept.
+	if err := prwe.wal.TruncateFront(prwe.rWALIndex.Load() - 1); err != nil && !errors.Is(err, wal.ErrOutOfRange) {
 		return err
 	}
 	return nil
@@ -290,11 +310,9 @@ func (prwe *prweWAL) exportThenFrontTruncateWAL(ctx context.Context, reqL []*pro
 	if errL := prwe.exportSink(ctx, reqL); errL != nil {
 		return errL
 	}
-	if err := prwe.syncAndTruncateFront(); err != nil {
-		return err
-	}
-	// Reset by retrieving the respective read and write WAL indices.
-	return prwe.retrieveWALIndices()
+	// The read index is kept, resetting it to the first index would read the last
+	// entry, which is kept by the truncation, once again.
+	return prwe.syncAndTruncateFront()
 }
 
 // persistToWAL is the routine that'll be hooked into the exporter's receiving side and it'll
@@ -319,10 +337,6 @@ func (prwe *prweWAL) persistToWAL(requests []*prompb.WriteRequest) error {
 }
 
 func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq *prompb.WriteRequest, err error) {
-	prwe.mu.Lock()
-	defer prwe.mu.Unlock()
-
-	var protoBlob []byte
 	for i := 0; i < 12; i++ {
 		// Firstly check if we've been terminated, then exit if so.
 		select {
@@ -337,21 +351,12 @@ func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq
 			index = 1
 		}
 
-		if prwe.wal == nil {
-			return nil, fmt.Errorf("attempt to read from closed WAL")
-		}
-
-		protoBlob, err = prwe.wal.Read(index)
+		wreq, err = prwe.read(index)
 		if err == nil { // The read succeeded.
-			req := new(prompb.WriteRequest)
-			if err = proto.Unmarshal(protoBlob, req); err != nil {
-				return nil, err
-			}
+			// Now move the WAL's read index to the next entry.
+			prwe.rWALIndex.Store(index + 1)
 
-			// Now increment the WAL's read index.
-			prwe.rWALIndex.Add(1)
-
-			return req, nil
+			return wreq, nil
 		}
 
 		if !errors.Is(err, wal.ErrNotFound) {
@@ -422,3 +427,24 @@ func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq
 	}
 	return nil, err
 }
+
+// read reads the request at index from the WAL. The lock is only held during the read,
+// so that the requests can be persisted while readPrompbFromWAL waits for them.
+func (prwe *prweWAL) read(index uint64) (*prompb.WriteRequest, error) {
+	prwe.mu.Lock()
+	defer prwe.mu.Unlock()
+
+	if prwe.wal == nil {
+		return nil, fmt.Errorf("attempt to read from closed WAL")
+	}
+
+	protoBlob, err := prwe.wal.Read(index)
+	if err != nil {
+		return nil, err
+	}
+	req := new(prompb.WriteRequest)
+	if err = proto.Unmarshal(protoBlob, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
@@ -6,12 +6,15 @@ package prometheusremotewriteexporter
 import (
 	"context"
 	"sort"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func doNothingExportSink(_ context.Context, reqL []*prompb.WriteRequest) error {
@@ -149,3 +152,52 @@ func TestWAL_persist(t *testing.T) {
 	require.Equal(t, reqLFromWAL[0], reqL[0])
 	require.Equal(t, reqLFromWAL[1], reqL[1])
 }
+
+func TestWAL_exportsEachRequestOnce(t *testing.T) {
+	config := &WALConfig{
+		Directory:         t.TempDir(),
+		TruncateFrequency: 10 * time.Millisecond,
+		BufferSize:        1,
+	}
+
+	var mu sync.Mutex
+	var exported []string
+	exportSink := func(_ context.Context, reqL []*prompb.WriteRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, req := range reqL {
+			exported = append(exported, req.Timeseries[0].Labels[0].Value)
+		}
+		return nil
+	}
+
+	pwal := newWAL(config, exportSink)
+	require.NotNil(t, pwal)
+	require.NoError(t, pwal.run(contextWithLogger(context.Background(), zap.NewNop())))
+	t.Cleanup(func() {
+		assert.NoError(t, pwal.stop())
+	})
+
+	var expected []string
+	for i := 0; i < 5; i++ {
+		value := strconv.Itoa(i)
+		expected = append(expected, value)
+		require.NoError(t, pwal.persistToWAL([]*prompb.WriteRequest{{
+			Timeseries: []prompb.TimeSeries{{
+				Labels:  []prompb.Label{{Name: "n", Value: value}},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: 100}},
+			}},
+		}}))
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(exported) >= len(expected)
+		}, 5*time.Second, 5*time.Millisecond)
+	}
+
+	// Wait for a few more truncations, the requests already exported aren't exported again
+	time.Sleep(10 * config.TruncateFrequency)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, expected, exported)
+}
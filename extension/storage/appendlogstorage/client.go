@@ -0,0 +1,392 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	segmentExtension = ".seg"
+
+	directoryKey = "directory"
+	segmentKey   = "segment"
+)
+
+var (
+	errClientClosed = errors.New("client closed")
+	errStorageFull  = errors.New("storage is full")
+)
+
+// clientOptions are the settings of a client, with sizes in bytes
+type clientOptions struct {
+	segmentSize   int64
+	maxSize       int64
+	dropOldest    bool
+	fsync         bool
+	checkInterval time.Duration
+	garbageRatio  float64
+}
+
+// segment is a file of the log. Only the last segment, the active one, is written to.
+type segment struct {
+	id   uint64
+	file *os.File
+	// size is the number of bytes of the segment
+	size int64
+	// live is the number of bytes of the records holding the current value of their key
+	live int64
+}
+
+// location is where the current value of a key is stored
+type location struct {
+	segment uint64
+	offset  int64
+	length  int
+	// size is the number of bytes of the record holding the value
+	size int
+}
+
+// appendLogClient stores values in an append only log split into segments, keeping the
+// location of the current value of each key in memory. Segments holding mostly replaced
+// or deleted values are compacted in the background.
+type appendLogClient struct {
+	logger    *zap.Logger
+	directory string
+	opts      clientOptions
+
+	mutex    sync.RWMutex
+	index    map[string]location
+	segments map[uint64]*segment
+	// order has the IDs of the segments in ascending order, the last one being the active segment
+	order  []uint64
+	size   int64
+	closed bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newClient(logger *zap.Logger, directory string, opts clientOptions) (*appendLogClient, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, err
+	}
+
+	c := &appendLogClient{
+		logger:    logger,
+		directory: directory,
+		opts:      opts,
+		index:     make(map[string]location),
+		segments:  make(map[uint64]*segment),
+	}
+	if err := c.load(); err != nil {
+		_ = c.closeSegments()
+		return nil, err
+	}
+	if len(c.order) == 0 {
+		if err := c.createSegment(0); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.checkInterval > 0 {
+		c.startCompactionLoop(context.Background())
+	}
+	return c, nil
+}
+
+// load replays the segments of the directory to rebuild the index
+func (c *appendLogClient) load() error {
+	entries, err := os.ReadDir(c.directory)
+	if err != nil {
+		return err
+	}
+	var ids []uint64
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), segmentExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		id, err := strconv.ParseUint(name, 16, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		if err := c.loadSegment(id); err != nil {
+			return fmt.Errorf("failed to load segment %s: %w", segmentPath(c.directory, id), err)
+		}
+	}
+	return nil
+}
+
+func (c *appendLogClient) loadSegment(id uint64) error {
+	path := segmentPath(c.directory, id)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	seg := &segment{id: id, file: file}
+	c.segments[id] = seg
+	c.order = append(c.order, id)
+
+	for int(seg.size) < len(data) {
+		n, records, err := decodeFrame(data[seg.size:])
+		if err != nil {
+			// The end of the segment was not completely written, typically because of a crash
+			c.logger.Warn("discarding invalid end of segment",
+				zap.String(segmentKey, path),
+				zap.Int64("offset", seg.size),
+				zap.Int("discarded_bytes", len(data)-int(seg.size)))
+			if err = file.Truncate(seg.size); err != nil {
+				return err
+			}
+			break
+		}
+		c.apply(seg, seg.size, records)
+		seg.size += int64(n)
+	}
+	c.size += seg.size
+	return nil
+}
+
+// Get will retrieve data from storage that corresponds to the specified key
+func (c *appendLogClient) Get(_ context.Context, key string) ([]byte, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.closed {
+		return nil, errClientClosed
+	}
+	return c.read(key)
+}
+
+// Set will store data. The data can be retrieved using the same key
+func (c *appendLogClient) Set(ctx context.Context, key string, value []byte) error {
+	return c.Batch(ctx, storage.SetOperation(key, value))
+}
+
+// Delete will delete data associated with the specified key
+func (c *appendLogClient) Delete(ctx context.Context, key string) error {
+	return c.Batch(ctx, storage.DeleteOperation(key))
+}
+
+// Batch executes the specified operations in order. Get operation results are updated in place.
+// All the set and delete operations are written at once, and are all applied or none of them.
+func (c *appendLogClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return errClientClosed
+	}
+
+	var frame frameEncoder
+	// pending has the values set or deleted by the previous operations of the batch, for the following gets
+	pending := make(map[string][]byte)
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Get:
+			if value, ok := pending[op.Key]; ok {
+				op.Value = slices.Clone(value)
+				continue
+			}
+			value, err := c.read(op.Key)
+			if err != nil {
+				return err
+			}
+			op.Value = value
+		case storage.Set:
+			frame.set(op.Key, op.Value)
+			pending[op.Key] = op.Value
+			if op.Value == nil {
+				pending[op.Key] = []byte{}
+			}
+		case storage.Delete:
+			frame.delete(op.Key)
+			pending[op.Key] = nil
+		default:
+			return errors.New("wrong operation type")
+		}
+	}
+
+	if frame.empty() {
+		return nil
+	}
+	// Batches only deleting keys are accepted when the storage is full, so that it can be emptied
+	return c.write(&frame, !frame.deletesOnly())
+}
+
+// Close will close the segments
+func (c *appendLogClient) Close(_ context.Context) error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.wg.Wait()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closed = true
+	return c.closeSegments()
+}
+
+func (c *appendLogClient) closeSegments() error {
+	var errs []error
+	for _, seg := range c.segments {
+		errs = append(errs, seg.file.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// read returns a copy of the current value of key, or nil if it has none
+func (c *appendLogClient) read(key string) ([]byte, error) {
+	loc, ok := c.index[key]
+	if !ok {
+		return nil, nil
+	}
+	value := make([]byte, loc.length)
+	if _, err := c.segments[loc.segment].file.ReadAt(value, loc.offset); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// write appends the frame to the active segment, and applies its records to the index.
+// When enforceLimit is set, room is made for the frame if it would exceed the maximum size.
+func (c *appendLogClient) write(frame *frameEncoder, enforceLimit bool) error {
+	data := frame.bytes()
+	if enforceLimit && c.opts.maxSize > 0 {
+		if err := c.makeRoom(int64(len(data))); err != nil {
+			return err
+		}
+	}
+
+	active := c.segments[c.order[len(c.order)-1]]
+	if active.size > 0 && active.size+int64(len(data)) > c.opts.segmentSize {
+		if err := c.createSegment(active.id + 1); err != nil {
+			return err
+		}
+		active = c.segments[c.order[len(c.order)-1]]
+	}
+
+	offset := active.size
+	if _, err := active.file.WriteAt(data, offset); err != nil {
+		// Leave no partial frame behind, so that the following frames are readable
+		_ = active.file.Truncate(offset)
+		return err
+	}
+	if c.opts.fsync {
+		if err := active.file.Sync(); err != nil {
+			return err
+		}
+	}
+	active.size += int64(len(data))
+	c.size += int64(len(data))
+	c.apply(active, offset, frame.records)
+	return nil
+}
+
+// apply updates the index with the records of the frame written at offset in seg
+func (c *appendLogClient) apply(seg *segment, offset int64, records []record) {
+	for _, r := range records {
+		if previous, ok := c.index[r.key]; ok {
+			c.segments[previous.segment].live -= int64(previous.size)
+		}
+		if r.op == recordDelete {
+			delete(c.index, r.key)
+			continue
+		}
+		c.index[r.key] = location{segment: seg.id, offset: offset + int64(r.valueOffset), length: r.valueLength, size: r.size}
+		seg.live += int64(r.size)
+	}
+}
+
+// makeRoom drops the oldest segments if allowed, until size more bytes fit within the maximum size
+func (c *appendLogClient) makeRoom(size int64) error {
+	if size > c.opts.maxSize {
+		return fmt.Errorf("%w: batch of %d bytes exceeds the maximum size", errStorageFull, size)
+	}
+	for c.size+size > c.opts.maxSize {
+		if !c.opts.dropOldest {
+			return fmt.Errorf("%w: maximum size of %d bytes reached", errStorageFull, c.opts.maxSize)
+		}
+		if len(c.order) == 1 {
+			// The active segment is dropped as well, once a new one is started
+			active := c.segments[c.order[0]]
+			if err := c.createSegment(active.id + 1); err != nil {
+				return err
+			}
+		}
+		if err := c.dropSegment(c.order[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createSegment creates a new active segment
+func (c *appendLogClient) createSegment(id uint64) error {
+	if len(c.order) > 0 && c.opts.fsync {
+		if err := c.segments[c.order[len(c.order)-1]].file.Sync(); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(segmentPath(c.directory, id), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	c.segments[id] = &segment{id: id, file: file}
+	c.order = append(c.order, id)
+	return nil
+}
+
+// dropSegment removes the oldest segment, along with the values it holds. Segments are always
+// removed from the oldest, so that the deletions of the removed segments are no longer needed.
+func (c *appendLogClient) dropSegment(id uint64) error {
+	seg := c.segments[id]
+	if seg.live > 0 {
+		dropped := 0
+		for key, loc := range c.index {
+			if loc.segment == id {
+				delete(c.index, key)
+				dropped++
+			}
+		}
+		c.logger.Warn("dropped oldest values to stay within the maximum size",
+			zap.String(segmentKey, seg.file.Name()),
+			zap.Int("dropped", dropped))
+	}
+
+	if err := seg.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Remove(seg.file.Name()); err != nil {
+		return err
+	}
+	delete(c.segments, id)
+	c.order = c.order[1:]
+	c.size -= seg.size
+	return nil
+}
+
+func segmentPath(directory string, id uint64) string {
+	return filepath.Join(directory, fmt.Sprintf("%016x%s", id, segmentExtension))
+}
@@ -0,0 +1,415 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+// newTestClient opens a client without background compaction, so that tests can compact when needed
+func newTestClient(t testing.TB, directory string, opts clientOptions) *appendLogClient {
+	if opts.segmentSize == 0 {
+		opts.segmentSize = oneMiB
+	}
+	if opts.garbageRatio == 0 {
+		opts.garbageRatio = defaultGarbageRatio
+	}
+	client, err := newClient(zap.NewNop(), directory, opts)
+	require.NoError(t, err)
+	return client
+}
+
+func segmentFiles(t *testing.T, directory string) []string {
+	files, err := filepath.Glob(filepath.Join(directory, "*"+segmentExtension))
+	require.NoError(t, err)
+	return files
+}
+
+func TestClientOperations(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, t.TempDir(), clientOptions{})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	require.NoError(t, client.Set(ctx, "key", []byte("new value")))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new value"), value)
+
+	require.NoError(t, client.Set(ctx, "empty", nil))
+	value, err = client.Get(ctx, "empty")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{}, value)
+
+	require.NoError(t, client.Delete(ctx, "key"))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestClientBatchOperations(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, t.TempDir(), clientOptions{})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+	require.NoError(t, client.Set(ctx, "existing", []byte("existing value")))
+
+	getExisting := storage.GetOperation("existing")
+	getBeforeSet := storage.GetOperation("key")
+	getAfterSet := storage.GetOperation("key")
+	getAfterDelete := storage.GetOperation("existing")
+	require.NoError(t, client.Batch(ctx,
+		getExisting,
+		getBeforeSet,
+		storage.SetOperation("key", []byte("value")),
+		getAfterSet,
+		storage.DeleteOperation("existing"),
+		getAfterDelete,
+	))
+	assert.Equal(t, []byte("existing value"), getExisting.Value)
+	assert.Nil(t, getBeforeSet.Value)
+	assert.Equal(t, []byte("value"), getAfterSet.Value)
+	assert.Nil(t, getAfterDelete.Value)
+
+	// The batch is written as a single frame
+	assert.Len(t, client.order, 1)
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	invalid := storage.GetOperation("key")
+	invalid.Type = 10
+	assert.EqualError(t, client.Batch(ctx, invalid), "wrong operation type")
+}
+
+func TestClientClosed(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, t.TempDir(), clientOptions{})
+	require.NoError(t, client.Close(ctx))
+
+	_, err := client.Get(ctx, "key")
+	assert.ErrorIs(t, err, errClientClosed)
+	assert.ErrorIs(t, client.Set(ctx, "key", []byte("value")), errClientClosed)
+	assert.ErrorIs(t, client.Delete(ctx, "key"), errClientClosed)
+}
+
+func TestClientReopen(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+
+	client := newTestClient(t, directory, clientOptions{segmentSize: 256})
+	for i := 0; i < 20; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), []byte(fmt.Sprintf("value_%d", i))))
+	}
+	for i := 0; i < 20; i += 2 {
+		require.NoError(t, client.Delete(ctx, fmt.Sprintf("key_%d", i)))
+	}
+	require.NoError(t, client.Set(ctx, "key_1", []byte("new value")))
+	require.NoError(t, client.Close(ctx))
+	require.Greater(t, len(segmentFiles(t, directory)), 1)
+
+	client = newTestClient(t, directory, clientOptions{segmentSize: 256})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+	for i := 0; i < 20; i++ {
+		value, err := client.Get(ctx, fmt.Sprintf("key_%d", i))
+		require.NoError(t, err)
+		switch {
+		case i == 1:
+			assert.Equal(t, []byte("new value"), value)
+		case i%2 == 0:
+			assert.Nil(t, value)
+		default:
+			assert.Equal(t, []byte(fmt.Sprintf("value_%d", i)), value)
+		}
+	}
+
+	// Writes continue in the last segment
+	require.NoError(t, client.Set(ctx, "key_0", []byte("value")))
+	value, err := client.Get(ctx, "key_0")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestClientDiscardsIncompleteFrame(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+
+	client := newTestClient(t, directory, clientOptions{})
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	require.NoError(t, client.Close(ctx))
+
+	// Simulate a crash in the middle of writing a batch
+	var frame frameEncoder
+	frame.set("key", []byte("lost value"))
+	frame.set("other", []byte("lost value"))
+	files := segmentFiles(t, directory)
+	require.Len(t, files, 1)
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	file, err := os.OpenFile(files[0], os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = file.Write(frame.bytes()[:len(frame.buf)-3])
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	client = newTestClient(t, directory, clientOptions{})
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	value, err = client.Get(ctx, "other")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// The incomplete frame is removed, so that the following ones can be read
+	require.NoError(t, client.Set(ctx, "other", []byte("other value")))
+	require.NoError(t, client.Close(ctx))
+	client = newTestClient(t, directory, clientOptions{})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+	value, err = client.Get(ctx, "other")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("other value"), value)
+	assert.Greater(t, client.size, info.Size())
+}
+
+func TestClientSegments(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	client := newTestClient(t, directory, clientOptions{segmentSize: 120})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	// Each frame is 56 bytes long, so that segments hold two of them
+	for i := 0; i < 10; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), make([]byte, 40)))
+	}
+	assert.Len(t, segmentFiles(t, directory), 5)
+
+	// Frames larger than the segment size get a segment of their own
+	require.NoError(t, client.Set(ctx, "large", make([]byte, 200)))
+	assert.Len(t, segmentFiles(t, directory), 6)
+	value, err := client.Get(ctx, "large")
+	require.NoError(t, err)
+	assert.Len(t, value, 200)
+}
+
+func TestClientMaxSizeError(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, t.TempDir(), clientOptions{segmentSize: 120, maxSize: 240})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), make([]byte, 40)))
+	}
+	err := client.Set(ctx, "key_4", make([]byte, 40))
+	assert.ErrorIs(t, err, errStorageFull)
+	assert.EqualError(t, err, "storage is full: maximum size of 240 bytes reached")
+	assert.EqualError(t, client.Set(ctx, "large", make([]byte, 300)), "storage is full: batch of 317 bytes exceeds the maximum size")
+
+	// Deleting is allowed, so that the storage can be emptied
+	for i := 0; i < 4; i++ {
+		require.NoError(t, client.Delete(ctx, fmt.Sprintf("key_%d", i)))
+	}
+	require.NoError(t, client.compact())
+	require.NoError(t, client.Set(ctx, "key_4", make([]byte, 40)))
+}
+
+func TestClientMaxSizeDropOldest(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	client := newTestClient(t, directory, clientOptions{segmentSize: 120, maxSize: 240, dropOldest: true})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), make([]byte, 40)))
+		assert.LessOrEqual(t, client.size, int64(240))
+	}
+	assert.Len(t, segmentFiles(t, directory), 2)
+
+	for i := 0; i < 10; i++ {
+		value, err := client.Get(ctx, fmt.Sprintf("key_%d", i))
+		require.NoError(t, err)
+		if i < 6 {
+			assert.Nil(t, value, i)
+		} else {
+			assert.Len(t, value, 40, i)
+		}
+	}
+}
+
+func TestClientRemovesDeadSegments(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	client := newTestClient(t, directory, clientOptions{segmentSize: 120, garbageRatio: 1})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	// Values are deleted in the order they were written, as with a queue
+	for i := 0; i < 10; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), make([]byte, 40)))
+	}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, client.Delete(ctx, fmt.Sprintf("key_%d", i)))
+	}
+	before := len(segmentFiles(t, directory))
+
+	require.NoError(t, client.compact())
+	assert.Len(t, segmentFiles(t, directory), before-2)
+
+	// The values of the removed segments aren't restored when reopening
+	require.NoError(t, client.Close(ctx))
+	client = newTestClient(t, directory, clientOptions{segmentSize: 120})
+	for i := 0; i < 10; i++ {
+		value, err := client.Get(ctx, fmt.Sprintf("key_%d", i))
+		require.NoError(t, err)
+		if i < 5 {
+			assert.Nil(t, value, i)
+		} else {
+			assert.Len(t, value, 40, i)
+		}
+	}
+}
+
+func TestClientCompaction(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	client := newTestClient(t, directory, clientOptions{segmentSize: 1200})
+
+	// Overwriting the same keys leaves mostly garbage in the sealed segments
+	for i := 0; i < 100; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i%10), []byte(fmt.Sprintf("value_%d", i))))
+	}
+	require.NoError(t, client.Delete(ctx, "key_0"))
+	sizeBefore := client.size
+	require.Greater(t, len(segmentFiles(t, directory)), 1)
+
+	require.NoError(t, client.compact())
+	assert.Len(t, segmentFiles(t, directory), 1)
+	assert.Less(t, client.size, sizeBefore)
+
+	check := func() {
+		value, err := client.Get(ctx, "key_0")
+		require.NoError(t, err)
+		assert.Nil(t, value)
+		for i := 1; i < 10; i++ {
+			value, err := client.Get(ctx, fmt.Sprintf("key_%d", i))
+			require.NoError(t, err)
+			assert.Equal(t, []byte(fmt.Sprintf("value_%d", 90+i)), value)
+		}
+	}
+	check()
+	require.NoError(t, client.Close(ctx))
+
+	client = newTestClient(t, directory, clientOptions{segmentSize: 1200})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+	check()
+}
+
+func TestClientCompactionBelowGarbageRatio(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	client := newTestClient(t, directory, clientOptions{segmentSize: 120})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), make([]byte, 40)))
+	}
+	require.NoError(t, client.Set(ctx, "key_9", make([]byte, 40)))
+	before := segmentFiles(t, directory)
+
+	require.NoError(t, client.compact())
+	assert.Equal(t, before, segmentFiles(t, directory))
+}
+
+func TestClientCompactionLoop(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	client := newTestClient(t, directory, clientOptions{segmentSize: 1200, checkInterval: 10 * time.Millisecond})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, client.Set(ctx, "key", make([]byte, 100)))
+	}
+	require.Eventually(t, func() bool {
+		return len(segmentFiles(t, directory)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestClientConcurrentCompaction(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, t.TempDir(), clientOptions{segmentSize: 1200, checkInterval: time.Millisecond})
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	var wg sync.WaitGroup
+	for thread := 0; thread < 4; thread++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := fmt.Sprintf("key_%d_%d", thread, i%10)
+				value := []byte(fmt.Sprintf("value_%d", i))
+				assert.NoError(t, client.Set(ctx, key, value))
+				got, err := client.Get(ctx, key)
+				assert.NoError(t, err)
+				assert.Equal(t, value, got)
+				if i%3 == 0 {
+					assert.NoError(t, client.Delete(ctx, key))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkStorageClient runs the benchmarks shared by the storage extensions, see storagetest.BenchmarkClient
+func BenchmarkStorageClient(b *testing.B) {
+	storagetest.BenchmarkClient(b, func(b *testing.B) storage.Client {
+		return newTestClient(b, b.TempDir(), clientOptions{
+			segmentSize:   defaultSegmentSizeMiB * oneMiB,
+			checkInterval: defaultCompactionInterval,
+			garbageRatio:  defaultGarbageRatio,
+		})
+	})
+}
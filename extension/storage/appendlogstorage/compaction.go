@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage"
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// mergeFrameSize is the size above which the values moved by a merge are written, so
+// that the client isn't locked for too long
+const mergeFrameSize = 1 << 20
+
+// startCompactionLoop provides asynchronous compaction function
+func (c *appendLogClient) startCompactionLoop(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.opts.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.compact(); err != nil {
+					c.logger.Error("compaction failure", zap.String(directoryKey, c.directory), zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// compact removes the oldest segments once they hold no value, then merges the sealed segments
+// into the active one if they hold more garbage than the configured ratio
+func (c *appendLogClient) compact() error {
+	if err := c.removeDeadSegments(); err != nil {
+		return err
+	}
+
+	c.mutex.RLock()
+	if c.closed {
+		c.mutex.RUnlock()
+		return nil
+	}
+	sealed := c.order[:len(c.order)-1]
+	var size, live int64
+	for _, id := range sealed {
+		size += c.segments[id].size
+		live += c.segments[id].live
+	}
+	sealed = append([]uint64(nil), sealed...)
+	c.mutex.RUnlock()
+
+	if size == 0 || float64(size-live)/float64(size) < c.opts.garbageRatio {
+		return nil
+	}
+	return c.merge(sealed)
+}
+
+// removeDeadSegments removes the oldest sealed segments while they hold no value. As they are the
+// oldest, the deletions they hold are no longer needed either.
+func (c *appendLogClient) removeDeadSegments() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for !c.closed && len(c.order) > 1 && c.segments[c.order[0]].live == 0 {
+		if err := c.dropSegment(c.order[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge moves the values of the sealed segments into the active segment, then removes them.
+// The values are moved in several batches, so that the client can be used meanwhile.
+func (c *appendLogClient) merge(sealed []uint64) error {
+	start := time.Now()
+	merged := make(map[uint64]bool, len(sealed))
+	for _, id := range sealed {
+		merged[id] = true
+	}
+
+	// Keys can only move out of the merged segments meanwhile, as they are no longer written to
+	c.mutex.RLock()
+	var keys []string
+	for key, loc := range c.index {
+		if merged[loc.segment] {
+			keys = append(keys, key)
+		}
+	}
+	c.mutex.RUnlock()
+
+	for len(keys) > 0 {
+		var err error
+		keys, err = c.moveValues(keys, merged)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The merged segments are the oldest ones, so they are removed from the oldest as when dropping them
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return nil
+	}
+	var reclaimed int64
+	for _, id := range sealed {
+		// Segments may have been dropped meanwhile to stay within the maximum size
+		if seg, ok := c.segments[id]; ok {
+			reclaimed += seg.size
+			if err := c.dropSegment(id); err != nil {
+				return err
+			}
+		}
+	}
+	c.logger.Debug("finished compaction",
+		zap.String(directoryKey, c.directory),
+		zap.Int("segments", len(sealed)),
+		zap.Int64("reclaimed_bytes", reclaimed),
+		zap.Duration("elapsed", time.Since(start)))
+	return nil
+}
+
+// moveValues writes the values of keys still located in the merged segments to the active segment,
+// until the frame is large enough, and returns the keys left
+func (c *appendLogClient) moveValues(keys []string, merged map[uint64]bool) ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return nil, nil
+	}
+
+	var frame frameEncoder
+	for len(keys) > 0 && len(frame.buf) < mergeFrameSize {
+		key := keys[0]
+		keys = keys[1:]
+		loc, ok := c.index[key]
+		if !ok || !merged[loc.segment] {
+			continue
+		}
+		value, err := c.read(key)
+		if err != nil {
+			return nil, err
+		}
+		frame.set(key, value)
+	}
+	if frame.empty() {
+		return keys, nil
+	}
+	// Merging may exceed the maximum size temporarily, as it is what brings the size down
+	return keys, c.write(&frame, false)
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage"
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+const (
+	onFullError      = "error"
+	onFullDropOldest = "drop_oldest"
+)
+
+// Config defines configuration for append log storage extension.
+type Config struct {
+	Directory string `mapstructure:"directory,omitempty"`
+
+	// SegmentSizeMiB specifies the size above which a new segment of the log is started
+	SegmentSizeMiB int64 `mapstructure:"segment_size_mib"`
+	// MaxSizeMiB specifies the maximum size of the segments of each component, 0 meaning no limit
+	MaxSizeMiB int64 `mapstructure:"max_size_mib"`
+	// OnFull specifies what happens to writes exceeding the maximum size: with "error" they fail,
+	// with "drop_oldest" the oldest segments are dropped along with the values they hold
+	OnFull string `mapstructure:"on_full"`
+
+	Compaction CompactionConfig `mapstructure:"compaction"`
+
+	// FSync specifies that fsync should be called after each write
+	FSync bool `mapstructure:"fsync,omitempty"`
+}
+
+// CompactionConfig defines configuration for the background compaction of the log.
+type CompactionConfig struct {
+	// CheckInterval specifies frequency of compaction check
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	// GarbageRatio specifies the ratio of replaced or deleted data in the sealed segments
+	// above which they are merged into the active segment
+	GarbageRatio float64 `mapstructure:"garbage_ratio"`
+}
+
+func (cfg *Config) Validate() error {
+	info, err := os.Stat(cfg.Directory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("directory must exist: %w", err)
+		}
+		fsErr := &fs.PathError{}
+		if errors.As(err, &fsErr) {
+			return fmt.Errorf("problem accessing configured directory: %s, err: %w", cfg.Directory, fsErr)
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", cfg.Directory)
+	}
+
+	if cfg.SegmentSizeMiB <= 0 {
+		return errors.New("segment_size_mib must be positive")
+	}
+	if cfg.MaxSizeMiB < 0 {
+		return errors.New("max_size_mib cannot be less than 0")
+	}
+	if cfg.MaxSizeMiB > 0 && cfg.MaxSizeMiB < 2*cfg.SegmentSizeMiB {
+		return errors.New("max_size_mib must be at least twice segment_size_mib")
+	}
+	switch cfg.OnFull {
+	case onFullError, onFullDropOldest:
+	default:
+		return fmt.Errorf("invalid on_full %q, must be %q or %q", cfg.OnFull, onFullError, onFullDropOldest)
+	}
+
+	if cfg.Compaction.CheckInterval <= 0 {
+		return errors.New("compaction check interval must be positive")
+	}
+	if cfg.Compaction.GarbageRatio <= 0 || cfg.Compaction.GarbageRatio > 1 {
+		return errors.New("compaction garbage ratio must be greater than 0 and at most 1")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id       component.ID
+		expected component.Config
+	}{
+		{
+			id: component.NewID(metadata.Type),
+			expected: func() component.Config {
+				ret := NewFactory().CreateDefaultConfig()
+				ret.(*Config).Directory = "."
+				return ret
+			}(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "all_settings"),
+			expected: &Config{
+				Directory:      ".",
+				SegmentSizeMiB: 4,
+				MaxSizeMiB:     512,
+				OnFull:         onFullDropOldest,
+				Compaction: CompactionConfig{
+					CheckInterval: 10 * time.Second,
+					GarbageRatio:  0.25,
+				},
+				FSync: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		err    string
+	}{
+		{
+			name:   "missing-directory",
+			modify: func(cfg *Config) { cfg.Directory = "/not/a/dir" },
+			err:    "directory must exist: stat /not/a/dir: no such file or directory",
+		},
+		{
+			name:   "file-as-directory",
+			modify: func(cfg *Config) { cfg.Directory = file.Name() },
+			err:    file.Name() + " is not a directory",
+		},
+		{
+			name:   "segment-size",
+			modify: func(cfg *Config) { cfg.SegmentSizeMiB = 0 },
+			err:    "segment_size_mib must be positive",
+		},
+		{
+			name:   "negative-max-size",
+			modify: func(cfg *Config) { cfg.MaxSizeMiB = -1 },
+			err:    "max_size_mib cannot be less than 0",
+		},
+		{
+			name:   "small-max-size",
+			modify: func(cfg *Config) { cfg.MaxSizeMiB = 16 },
+			err:    "max_size_mib must be at least twice segment_size_mib",
+		},
+		{
+			name:   "on-full",
+			modify: func(cfg *Config) { cfg.OnFull = "block" },
+			err:    `invalid on_full "block", must be "error" or "drop_oldest"`,
+		},
+		{
+			name:   "check-interval",
+			modify: func(cfg *Config) { cfg.Compaction.CheckInterval = 0 },
+			err:    "compaction check interval must be positive",
+		},
+		{
+			name:   "garbage-ratio",
+			modify: func(cfg *Config) { cfg.Compaction.GarbageRatio = 1.5 },
+			err:    "compaction garbage ratio must be greater than 0 and at most 1",
+		},
+		{
+			name:   "valid",
+			modify: func(cfg *Config) { cfg.MaxSizeMiB = 32 },
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.Directory = t.TempDir()
+			test.modify(cfg)
+			err := component.ValidateConfig(cfg)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package appendlogstorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage"
+
+func getDefaultDirectory() string {
+	return "/var/lib/otelcol/append_log_storage"
+}
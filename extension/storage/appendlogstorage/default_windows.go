@@ -0,0 +1,15 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package appendlogstorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage"
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func getDefaultDirectory() string {
+	return filepath.Join(os.Getenv("ProgramData"), "Otelcol", "AppendLogStorage")
+}
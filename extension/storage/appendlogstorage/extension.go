@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage"
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const oneMiB = 1048576
+
+type appendLogStorage struct {
+	cfg    *Config
+	logger *zap.Logger
+}
+
+// Ensure this storage extension implements the appropriate interface
+var _ storage.Extension = (*appendLogStorage)(nil)
+
+func newAppendLogStorage(logger *zap.Logger, config *Config) *appendLogStorage {
+	return &appendLogStorage{
+		cfg:    config,
+		logger: logger,
+	}
+}
+
+// Start does nothing, as the logs are opened when clients are requested
+func (s *appendLogStorage) Start(context.Context, component.Host) error {
+	return nil
+}
+
+// Shutdown does nothing, as the clients are closed by the components using them
+func (s *appendLogStorage) Shutdown(context.Context) error {
+	return nil
+}
+
+// GetClient returns a storage client for an individual component, storing its log in a dedicated directory
+func (s *appendLogStorage) GetClient(_ context.Context, kind component.Kind, ent component.ID, name string) (storage.Client, error) {
+	var rawName string
+	if name == "" {
+		rawName = fmt.Sprintf("%s_%s_%s", kindString(kind), ent.Type(), ent.Name())
+	} else {
+		rawName = fmt.Sprintf("%s_%s_%s_%s", kindString(kind), ent.Type(), ent.Name(), name)
+	}
+
+	directory := filepath.Join(s.cfg.Directory, sanitize(rawName))
+	return newClient(s.logger, directory, clientOptions{
+		segmentSize:   s.cfg.SegmentSizeMiB * oneMiB,
+		maxSize:       s.cfg.MaxSizeMiB * oneMiB,
+		dropOldest:    s.cfg.OnFull == onFullDropOldest,
+		fsync:         s.cfg.FSync,
+		checkInterval: s.cfg.Compaction.CheckInterval,
+		garbageRatio:  s.cfg.Compaction.GarbageRatio,
+	})
+}
+
+func kindString(k component.Kind) string {
+	switch k {
+	case component.KindReceiver:
+		return "receiver"
+	case component.KindProcessor:
+		return "processor"
+	case component.KindExporter:
+		return "exporter"
+	case component.KindExtension:
+		return "extension"
+	case component.KindConnector:
+		return "connector"
+	default:
+		return "other" // not expected
+	}
+}
+
+// sanitize replaces characters in name that are not safe in a file path, as the file storage extension does
+func sanitize(name string) string {
+	var sanitized strings.Builder
+	for _, character := range name {
+		if isSafe(character) {
+			sanitized.WriteRune(character)
+		} else {
+			sanitized.WriteString(fmt.Sprintf("~%04X", character))
+		}
+	}
+	return sanitized.String()
+}
+
+func isSafe(character rune) bool {
+	switch {
+	case character >= 'a' && character <= 'z',
+		character >= 'A' && character <= 'Z',
+		character >= '0' && character <= '9',
+		character == '.',
+		character == '-',
+		character == '_':
+		return true
+	}
+	return false
+}
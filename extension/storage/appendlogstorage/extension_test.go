@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func newTestExtension(t *testing.T, directory string) storage.Extension {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Directory = directory
+
+	extension, err := f.CreateExtension(context.Background(), extensiontest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, extension.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, extension.Shutdown(context.Background()))
+	})
+	return extension.(storage.Extension)
+}
+
+func TestClientsAreIsolated(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	se := newTestExtension(t, directory)
+
+	clientOne, err := se.GetClient(ctx, component.KindReceiver, component.MustNewIDWithName("nop", "one"), "")
+	require.NoError(t, err)
+	clientTwo, err := se.GetClient(ctx, component.KindExporter, component.MustNewIDWithName("nop", "two"), "queue")
+	require.NoError(t, err)
+
+	require.NoError(t, clientOne.Set(ctx, "key", []byte("one")))
+	require.NoError(t, clientTwo.Set(ctx, "key", []byte("two")))
+
+	value, err := clientOne.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("one"), value)
+	value, err = clientTwo.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("two"), value)
+
+	require.NoError(t, clientOne.Close(ctx))
+	require.NoError(t, clientTwo.Close(ctx))
+
+	entries, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		assert.True(t, entry.IsDir())
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"receiver_nop_one", "exporter_nop_two_queue"}, names)
+}
+
+func TestClientPersistsAcrossRestarts(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	id := component.MustNewIDWithName("nop", "my/component")
+
+	client, err := newTestExtension(t, directory).GetClient(ctx, component.KindReceiver, id, "")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	require.NoError(t, client.Close(ctx))
+	assert.DirExists(t, filepath.Join(directory, "receiver_nop_my~002Fcomponent"))
+
+	client, err = newTestExtension(t, directory).GetClient(ctx, component.KindReceiver, id, "")
+	require.NoError(t, err)
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	require.NoError(t, client.Close(ctx))
+}
+
+func TestSanitize(t *testing.T) {
+	assert.Equal(t, "receiver_filelog_logs~002Fcontainer~007E1", sanitize("receiver_filelog_logs/container~1"))
+	assert.Equal(t, "exporter_otlp_Name.with-safe_chars", sanitize("exporter_otlp_Name.with-safe_chars"))
+}
@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage/internal/metadata"
+)
+
+const (
+	defaultSegmentSizeMiB     = 16
+	defaultCompactionInterval = time.Second * 5
+	defaultGarbageRatio       = 0.5
+)
+
+// NewFactory creates a factory for the append log storage extension.
+func NewFactory() extension.Factory {
+	return extension.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		createExtension,
+		metadata.ExtensionStability,
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Directory:      getDefaultDirectory(),
+		SegmentSizeMiB: defaultSegmentSizeMiB,
+		OnFull:         onFullError,
+		Compaction: CompactionConfig{
+			CheckInterval: defaultCompactionInterval,
+			GarbageRatio:  defaultGarbageRatio,
+		},
+	}
+}
+
+func createExtension(
+	_ context.Context,
+	params extension.Settings,
+	cfg component.Config,
+) (extension.Extension, error) {
+	return newAppendLogStorage(params.Logger, cfg.(*Config)), nil
+}
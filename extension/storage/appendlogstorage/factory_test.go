@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage/internal/metadata"
+)
+
+func TestFactory(t *testing.T) {
+	f := NewFactory()
+	assert.Equal(t, metadata.Type, f.Type())
+
+	cfg := f.CreateDefaultConfig().(*Config)
+	assert.Equal(t, getDefaultDirectory(), cfg.Directory)
+	assert.Equal(t, int64(defaultSegmentSizeMiB), cfg.SegmentSizeMiB)
+	assert.Zero(t, cfg.MaxSizeMiB)
+	assert.Equal(t, onFullError, cfg.OnFull)
+	assert.Equal(t, defaultCompactionInterval, cfg.Compaction.CheckInterval)
+	assert.Equal(t, defaultGarbageRatio, cfg.Compaction.GarbageRatio)
+	assert.False(t, cfg.FSync)
+
+	cfg.Directory = t.TempDir()
+	e, err := f.CreateExtension(context.Background(), extensiontest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, e)
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/appendlogstorage"
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// Segments are sequences of frames, each holding the operations of a batch so that batches are
+// applied atomically. A frame starts with the CRC-32C checksum and the length of its payload, both
+// little endian uint32, followed by the payload. The payload is a sequence of records made of the
+// operation type, the uvarint length of the key and the key, followed for set operations by the
+// uvarint length of the value and the value.
+const frameHeaderSize = 8
+
+const (
+	recordSet    byte = 1
+	recordDelete byte = 2
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errInvalidFrame = errors.New("invalid frame")
+
+// record is an operation of a frame
+type record struct {
+	op  byte
+	key string
+	// valueOffset and valueLength locate the value of set operations within the frame
+	valueOffset int
+	valueLength int
+	// size is the number of bytes of the record, accounted as live while the record holds the value of its key
+	size int
+}
+
+// frameEncoder encodes operations into a frame
+type frameEncoder struct {
+	buf     []byte
+	records []record
+}
+
+func (f *frameEncoder) set(key string, value []byte) {
+	f.init()
+	start := len(f.buf)
+	f.buf = append(f.buf, recordSet)
+	f.buf = binary.AppendUvarint(f.buf, uint64(len(key)))
+	f.buf = append(f.buf, key...)
+	f.buf = binary.AppendUvarint(f.buf, uint64(len(value)))
+	valueOffset := len(f.buf)
+	f.buf = append(f.buf, value...)
+	f.records = append(f.records, record{op: recordSet, key: key, valueOffset: valueOffset, valueLength: len(value), size: len(f.buf) - start})
+}
+
+func (f *frameEncoder) delete(key string) {
+	f.init()
+	start := len(f.buf)
+	f.buf = append(f.buf, recordDelete)
+	f.buf = binary.AppendUvarint(f.buf, uint64(len(key)))
+	f.buf = append(f.buf, key...)
+	f.records = append(f.records, record{op: recordDelete, key: key, size: len(f.buf) - start})
+}
+
+func (f *frameEncoder) init() {
+	if f.buf == nil {
+		f.buf = make([]byte, frameHeaderSize, 4096)
+	}
+}
+
+func (f *frameEncoder) empty() bool {
+	return len(f.records) == 0
+}
+
+// deletesOnly returns whether the frame only deletes keys
+func (f *frameEncoder) deletesOnly() bool {
+	for _, r := range f.records {
+		if r.op != recordDelete {
+			return false
+		}
+	}
+	return true
+}
+
+// bytes completes the header of the frame and returns it
+func (f *frameEncoder) bytes() []byte {
+	payload := f.buf[frameHeaderSize:]
+	binary.LittleEndian.PutUint32(f.buf[0:4], crc32.Checksum(payload, crcTable))
+	binary.LittleEndian.PutUint32(f.buf[4:8], uint32(len(payload)))
+	return f.buf
+}
+
+// decodeFrame decodes the frame at the start of data, and returns its length and records.
+// It returns errInvalidFrame if data doesn't start with a complete and valid frame.
+func decodeFrame(data []byte) (int, []record, error) {
+	if len(data) < frameHeaderSize {
+		return 0, nil, errInvalidFrame
+	}
+	checksum := binary.LittleEndian.Uint32(data[0:4])
+	length := int(binary.LittleEndian.Uint32(data[4:8]))
+	if length > len(data)-frameHeaderSize {
+		return 0, nil, errInvalidFrame
+	}
+	end := frameHeaderSize + length
+	if crc32.Checksum(data[frameHeaderSize:end], crcTable) != checksum {
+		return 0, nil, errInvalidFrame
+	}
+
+	var records []record
+	pos := frameHeaderSize
+	for pos < end {
+		start := pos
+		op := data[pos]
+		pos++
+		keyLength, n := binary.Uvarint(data[pos:end])
+		if n <= 0 || keyLength > uint64(end-pos-n) {
+			return 0, nil, errInvalidFrame
+		}
+		pos += n
+		r := record{op: op, key: string(data[pos : pos+int(keyLength)])}
+		pos += int(keyLength)
+
+		switch op {
+		case recordSet:
+			valueLength, n := binary.Uvarint(data[pos:end])
+			if n <= 0 || valueLength > uint64(end-pos-n) {
+				return 0, nil, errInvalidFrame
+			}
+			pos += n
+			r.valueOffset = pos
+			r.valueLength = int(valueLength)
+			pos += int(valueLength)
+		case recordDelete:
+		default:
+			return 0, nil, errInvalidFrame
+		}
+		r.size = pos - start
+		records = append(records, r)
+	}
+	return end, records, nil
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package appendlogstorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	var frame frameEncoder
+	frame.set("key", []byte("value"))
+	frame.delete("other")
+	frame.set("", []byte{})
+	frame.set("large", make([]byte, 1000))
+	data := frame.bytes()
+
+	n, records, err := decodeFrame(append(data, "next frame"...))
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, frame.records, records)
+	assert.Equal(t, "value", string(data[records[0].valueOffset:records[0].valueOffset+records[0].valueLength]))
+	assert.False(t, frame.deletesOnly())
+}
+
+func TestFrameDeletesOnly(t *testing.T) {
+	var frame frameEncoder
+	assert.True(t, frame.empty())
+	frame.delete("key")
+	frame.delete("other")
+	assert.False(t, frame.empty())
+	assert.True(t, frame.deletesOnly())
+}
+
+func TestDecodeInvalidFrame(t *testing.T) {
+	var frame frameEncoder
+	frame.set("key", []byte("value"))
+	data := frame.bytes()
+
+	corrupted := append([]byte{}, data...)
+	corrupted[len(corrupted)-1] ^= 0xff
+
+	invalidOp := frameEncoder{}
+	invalidOp.set("key", []byte("value"))
+	invalidOp.buf[frameHeaderSize] = 3
+
+	for name, invalid := range map[string][]byte{
+		"empty":            {},
+		"truncated-header": data[:frameHeaderSize-1],
+		"truncated":        data[:len(data)-1],
+		"checksum":         corrupted,
+		"operation":        invalidOp.bytes(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := decodeFrame(invalid)
+			assert.ErrorIs(t, err, errInvalidFrame)
+		})
+	}
+}
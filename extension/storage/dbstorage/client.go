@@ -14,10 +14,14 @@ import (
 	// SQLite driver
 	_ "github.com/mattn/go-sqlite3"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
 )
 
 const (
 	createTable     = "create table if not exists %s (key text primary key, value blob)"
+	listQueryText   = "select key, value from %s"
+	sizeQueryText   = "select key, length(value) from %s"
 	getQueryText    = "select value from %s where key=?"
 	setQueryText    = "insert into %s(key, value) values(?,?) on conflict(key) do update set value=?"
 	deleteQueryText = "delete from %s where key=?"
@@ -25,6 +29,7 @@ const (
 
 type dbStorageClient struct {
 	db          *sql.DB
+	tableName   string
 	getQuery    *sql.Stmt
 	setQuery    *sql.Stmt
 	deleteQuery *sql.Stmt
@@ -49,7 +54,7 @@ func newClient(ctx context.Context, db *sql.DB, tableName string) (*dbStorageCli
 	if err != nil {
 		return nil, err
 	}
-	return &dbStorageClient{db, selectQuery, setQuery, deleteQuery}, nil
+	return &dbStorageClient{db, tableName, selectQuery, setQuery, deleteQuery}, nil
 }
 
 // Get will retrieve data from storage that corresponds to the specified key
@@ -104,6 +109,60 @@ func (c *dbStorageClient) Batch(ctx context.Context, ops ...storage.Operation) e
 	return err
 }
 
+// scan calls fn with the key and value size of every row of the table
+func (c *dbStorageClient) scan(ctx context.Context, fn func(key string, valueSize int)) error {
+	rows, err := c.db.QueryContext(ctx, fmt.Sprintf(sizeQueryText, c.tableName))
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var key string
+		var size sql.NullInt64
+		if err = rows.Scan(&key, &size); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		fn(key, int(size.Int64))
+	}
+	return errors.Join(rows.Err(), rows.Close())
+}
+
+// rotateKeys encrypts again with the current key the values which aren't encrypted with it,
+// and returns how many were. Values which can't be decrypted with the keyring cause an error.
+func (c *dbStorageClient) rotateKeys(ctx context.Context, keyring *encryption.Keyring) (int, error) {
+	rows, err := c.db.QueryContext(ctx, fmt.Sprintf(listQueryText, c.tableName))
+	if err != nil {
+		return 0, err
+	}
+	rotatedValues := make(map[string][]byte)
+	for rows.Next() {
+		var key string
+		var value []byte
+		if err = rows.Scan(&key, &value); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		if value == nil || !keyring.NeedsRotation(value) {
+			continue
+		}
+		if rotatedValues[key], err = keyring.Rotate(key, value); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+	}
+	// the rows are released before writing, as some drivers don't allow both at once
+	if err = errors.Join(rows.Err(), rows.Close()); err != nil {
+		return 0, err
+	}
+
+	for key, value := range rotatedValues {
+		if err = c.Set(ctx, key, value); err != nil {
+			return 0, err
+		}
+	}
+	return len(rotatedValues), nil
+}
+
 // Close will close the database
 func (c *dbStorageClient) Close(_ context.Context) error {
 	if err := c.setQuery.Close(); err != nil {
@@ -5,12 +5,19 @@ package dbstorage // import "github.com/open-telemetry/opentelemetry-collector-c
 
 import (
 	"errors"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 // Config defines configuration for dbstorage extension.
 type Config struct {
 	DriverName string `mapstructure:"driver,omitempty"`
 	DataSource string `mapstructure:"datasource,omitempty"`
+	// Encryption specifies that values are encrypted before they are written to the database
+	Encryption *encryption.Config `mapstructure:"encryption,omitempty"`
+	// Quota specifies the maximum size of the data held by all the clients of the extension
+	Quota quota.Config `mapstructure:"quota,omitempty"`
 }
 
 func (cfg *Config) Validate() error {
@@ -13,28 +13,49 @@ import (
 	"go.opentelemetry.io/collector/extension"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 type databaseStorage struct {
 	driverName     string
 	datasourceName string
+	encryption     *encryption.Config
 	logger         *zap.Logger
 	db             *sql.DB
+	keyring        *encryption.Keyring
+	tracker        *quota.Tracker
 }
 
 // Ensure this storage extension implements the appropriate interface
 var _ storage.Extension = (*databaseStorage)(nil)
 
-func newDBStorage(logger *zap.Logger, config *Config) (extension.Extension, error) {
+func newDBStorage(set extension.Settings, config *Config) (extension.Extension, error) {
+	tracker, err := quota.NewTracker(config.Quota, set)
+	if err != nil {
+		return nil, err
+	}
 	return &databaseStorage{
 		driverName:     config.DriverName,
 		datasourceName: config.DataSource,
-		logger:         logger,
+		encryption:     config.Encryption,
+		logger:         set.Logger,
+		tracker:        tracker,
 	}, nil
 }
 
-// Start opens a connection to the database
-func (ds *databaseStorage) Start(context.Context, component.Host) error {
+// Start loads the encryption keys and opens a connection to the database
+func (ds *databaseStorage) Start(_ context.Context, host component.Host) error {
+	ds.tracker.Start(host)
+	if ds.encryption != nil {
+		keyring, err := encryption.NewKeyring(ds.encryption)
+		if err != nil {
+			return err
+		}
+		ds.keyring = keyring
+	}
+
 	db, err := sql.Open(ds.driverName, ds.datasourceName)
 	if err != nil {
 		return err
@@ -64,7 +85,31 @@ func (ds *databaseStorage) GetClient(ctx context.Context, kind component.Kind, e
 		fullName = fmt.Sprintf("%s_%s_%s_%s", kindString(kind), ent.Type(), ent.Name(), name)
 	}
 	fullName = strings.ReplaceAll(fullName, " ", "")
-	return newClient(ctx, ds.db, fullName)
+	client, err := newClient(ctx, ds.db, fullName)
+	if err != nil {
+		return nil, err
+	}
+	if ds.keyring != nil {
+		// there is no compaction to take care of key rotation, so values are encrypted again when the table is opened
+		rotated, rotateErr := client.rotateKeys(ctx, ds.keyring)
+		if rotateErr != nil {
+			_ = client.Close(ctx)
+			return nil, fmt.Errorf("failed to rotate encryption keys: %w", rotateErr)
+		}
+		if rotated > 0 {
+			ds.logger.Info("rotated encryption keys", zap.String("table", fullName), zap.Int("rotated", rotated))
+		}
+	}
+
+	tracked, err := ds.tracker.NewClient(ctx, fullName, client, client.scan)
+	if err != nil {
+		_ = client.Close(ctx)
+		return nil, err
+	}
+	if ds.keyring == nil {
+		return tracked, nil
+	}
+	return encryption.NewClient(tracked, ds.keyring), nil
 }
 
 func kindString(k component.Kind) string {
@@ -5,6 +5,8 @@ package dbstorage
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
 	"fmt"
 	"sync"
 	"testing"
@@ -13,8 +15,12 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/extension/extensiontest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 func TestExtensionIntegrity(t *testing.T) {
@@ -115,3 +121,115 @@ func newTestExtension(t *testing.T) storage.Extension {
 func newTestEntity(name string) component.ID {
 	return component.MustNewIDWithName("nop", name)
 }
+
+func TestEncryption(t *testing.T) {
+	ctx := context.Background()
+	dataSource := fmt.Sprintf("file:%s/foo.db?_busy_timeout=10000&_journal=WAL&_sync=NORMAL", t.TempDir())
+	entity := newTestEntity("my_component")
+
+	// values written by the extension are encrypted at rest
+	se := newEncryptedTestExtension(t, dataSource, "1")
+	client, err := se.GetClient(ctx, component.KindReceiver, entity, "")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "key", []byte("secret value")))
+	require.NoError(t, client.Close(ctx))
+	raw := readRawValue(t, dataSource, "key")
+	assert.True(t, encryption.IsEncrypted(raw))
+	assert.NotContains(t, string(raw), "secret value")
+	require.NoError(t, se.Shutdown(ctx))
+
+	// values are encrypted again with the new current key when a client is created
+	se = newEncryptedTestExtension(t, dataSource, "2", "1")
+	client, err = se.GetClient(ctx, component.KindReceiver, entity, "")
+	require.NoError(t, err)
+	require.NoError(t, client.Close(ctx))
+	require.NoError(t, se.Shutdown(ctx))
+
+	// so the previous key is no longer needed
+	se = newEncryptedTestExtension(t, dataSource, "2")
+	client, err = se.GetClient(ctx, component.KindReceiver, entity, "")
+	require.NoError(t, err)
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret value"), value)
+	require.NoError(t, client.Close(ctx))
+	require.NoError(t, se.Shutdown(ctx))
+
+	// values can't be read without the key they were encrypted with
+	se = newEncryptedTestExtension(t, dataSource, "3")
+	_, err = se.GetClient(ctx, component.KindReceiver, entity, "")
+	assert.EqualError(t, err, `failed to rotate encryption keys: value of "key" is encrypted with unknown key "2"`)
+	require.NoError(t, se.Shutdown(ctx))
+}
+
+// newEncryptedTestExtension returns a started extension encrypting values with the key of the first ID,
+// and decrypting them with the keys of all the IDs
+func newEncryptedTestExtension(t *testing.T, dataSource string, ids ...string) storage.Extension {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.DriverName = "sqlite3"
+	cfg.DataSource = dataSource
+	cfg.Encryption = &encryption.Config{}
+	for i, id := range ids {
+		env := "DB_STORAGE_TEST_KEY_" + id
+		t.Setenv(env, base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("test key %s 0123456789abcdefghijklmnopqrstuvwxyz", id))[:32]))
+		keyCfg := encryption.KeyConfig{KeyID: id, KeyEnv: env}
+		if i == 0 {
+			cfg.Encryption.KeyConfig = keyCfg
+		} else {
+			cfg.Encryption.PreviousKeys = append(cfg.Encryption.PreviousKeys, keyCfg)
+		}
+	}
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	extension, err := f.CreateExtension(context.Background(), extensiontest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, extension.Start(context.Background(), componenttest.NewNopHost()))
+	return extension.(storage.Extension)
+}
+
+// readRawValue reads a value of the test component table as it is stored
+func readRawValue(t *testing.T, dataSource string, key string) []byte {
+	db, err := sql.Open("sqlite3", dataSource)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var value []byte
+	require.NoError(t, db.QueryRow("select value from receiver_nop_my_component where key=?", key).Scan(&value))
+	return value
+}
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.DriverName = "sqlite3"
+	cfg.DataSource = fmt.Sprintf("file:%s/foo.db?_busy_timeout=10000&_journal=WAL&_sync=NORMAL", t.TempDir())
+	cfg.Quota.MaxSizeMiB = 1
+	value := make([]byte, 400*1024)
+
+	newClient := func() (extension.Extension, storage.Client) {
+		require.NoError(t, component.ValidateConfig(cfg))
+		ext, err := f.CreateExtension(ctx, extensiontest.NewNopSettings(), cfg)
+		require.NoError(t, err)
+		require.NoError(t, ext.Start(ctx, componenttest.NewNopHost()))
+		client, err := ext.(storage.Extension).GetClient(ctx, component.KindReceiver, newTestEntity("my_component"), "")
+		require.NoError(t, err)
+		return ext, client
+	}
+
+	ext, client := newClient()
+	require.NoError(t, client.Set(ctx, "a", value))
+	require.NoError(t, client.Set(ctx, "b", value))
+	require.ErrorIs(t, client.Set(ctx, "c", value), quota.ErrStorageFull)
+	require.NoError(t, client.Close(ctx))
+	require.NoError(t, ext.Shutdown(ctx))
+
+	// the rows already stored are accounted for when the table is opened again
+	ext, client = newClient()
+	require.ErrorIs(t, client.Set(ctx, "c", value), quota.ErrStorageFull)
+	require.NoError(t, client.Delete(ctx, "a"))
+	require.NoError(t, client.Set(ctx, "c", value))
+	require.NoError(t, client.Close(ctx))
+	require.NoError(t, ext.Shutdown(ctx))
+}
@@ -12,6 +12,7 @@ import (
 	"go.opentelemetry.io/collector/extension"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/dbstorage/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 // NewFactory creates a factory for DBStorage extension.
@@ -25,7 +26,9 @@ func NewFactory() extension.Factory {
 }
 
 func createDefaultConfig() component.Config {
-	return &Config{}
+	return &Config{
+		Quota: quota.NewDefaultConfig(),
+	}
 }
 
 func createExtension(
@@ -33,5 +36,5 @@ func createExtension(
 	params extension.Settings,
 	cfg component.Config,
 ) (extension.Extension, error) {
-	return newDBStorage(params.Logger, cfg.(*Config))
+	return newDBStorage(params, cfg.(*Config))
 }
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package encryption // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+type encryptedClient struct {
+	client  storage.Client
+	keyring *Keyring
+}
+
+// NewClient wraps a storage.Client, so that values are encrypted before they are passed to it,
+// and decrypted after they are read from it. Keys are stored as they are.
+func NewClient(client storage.Client, keyring *Keyring) storage.Client {
+	return &encryptedClient{client: client, keyring: keyring}
+}
+
+// Get will retrieve data from storage that corresponds to the specified key
+func (c *encryptedClient) Get(ctx context.Context, key string) ([]byte, error) {
+	op := storage.GetOperation(key)
+	if err := c.Batch(ctx, op); err != nil {
+		return nil, err
+	}
+	return op.Value, nil
+}
+
+// Set will store data. The data can be retrieved using the same key
+func (c *encryptedClient) Set(ctx context.Context, key string, value []byte) error {
+	return c.Batch(ctx, storage.SetOperation(key, value))
+}
+
+// Delete will delete data associated with the specified key
+func (c *encryptedClient) Delete(ctx context.Context, key string) error {
+	return c.client.Delete(ctx, key)
+}
+
+// Batch executes the specified operations in order. Get operation results are updated in place
+func (c *encryptedClient) Batch(ctx context.Context, ops ...storage.Operation) error {
+	// The operations are copied, so that the values of the caller aren't replaced by encrypted ones
+	wrapped := make([]storage.Operation, len(ops))
+	for i, op := range ops {
+		switch op.Type {
+		case storage.Set:
+			value := op.Value
+			if value != nil {
+				var err error
+				if value, err = c.keyring.Encrypt(op.Key, value); err != nil {
+					return err
+				}
+			}
+			wrapped[i] = storage.SetOperation(op.Key, value)
+		case storage.Get:
+			wrapped[i] = storage.GetOperation(op.Key)
+		default:
+			wrapped[i] = op
+		}
+	}
+
+	if err := c.client.Batch(ctx, wrapped...); err != nil {
+		return err
+	}
+
+	for i, op := range ops {
+		if op.Type != storage.Get {
+			continue
+		}
+		op.Value = nil
+		if wrapped[i].Value != nil {
+			value, err := c.keyring.Decrypt(op.Key, wrapped[i].Value)
+			if err != nil {
+				return err
+			}
+			op.Value = value
+		}
+	}
+	return nil
+}
+
+// Close will close the wrapped client
+func (c *encryptedClient) Close(ctx context.Context) error {
+	return c.client.Close(ctx)
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func TestClientOperations(t *testing.T) {
+	ctx := context.Background()
+	raw := storagetest.NewInMemoryClient(component.KindReceiver, component.MustNewID("test"), "")
+	client := NewClient(raw, newTestKeyring(t, "1"))
+
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+
+	stored, err := raw.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(stored))
+	assert.NotContains(t, string(stored), "value")
+
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	require.NoError(t, client.Delete(ctx, "key"))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, client.Close(ctx))
+}
+
+func TestClientBatchOperations(t *testing.T) {
+	ctx := context.Background()
+	raw := storagetest.NewInMemoryClient(component.KindReceiver, component.MustNewID("test"), "")
+	client := NewClient(raw, newTestKeyring(t, "1"))
+
+	setFirst := storage.SetOperation("first", []byte("first value"))
+	setSecond := storage.SetOperation("second", []byte("second value"))
+	getFirst := storage.GetOperation("first")
+	deleteSecond := storage.DeleteOperation("second")
+	getSecond := storage.GetOperation("second")
+	require.NoError(t, client.Batch(ctx, setFirst, setSecond, getFirst, deleteSecond, getSecond))
+
+	// The values of the caller aren't replaced by the encrypted ones
+	assert.Equal(t, []byte("first value"), setFirst.Value)
+	assert.Equal(t, []byte("second value"), setSecond.Value)
+	assert.Equal(t, []byte("first value"), getFirst.Value)
+	assert.Nil(t, getSecond.Value)
+}
+
+func TestClientReadsPlaintext(t *testing.T) {
+	ctx := context.Background()
+	raw := storagetest.NewInMemoryClient(component.KindReceiver, component.MustNewID("test"), "")
+	require.NoError(t, raw.Set(ctx, "key", []byte("plaintext")))
+	client := NewClient(raw, newTestKeyring(t, "1"))
+
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("plaintext"), value)
+}
+
+func TestClientDecryptError(t *testing.T) {
+	ctx := context.Background()
+	raw := storagetest.NewInMemoryClient(component.KindReceiver, component.MustNewID("test"), "")
+	require.NoError(t, NewClient(raw, newTestKeyring(t, "1")).Set(ctx, "key", []byte("value")))
+
+	_, err := NewClient(raw, newTestKeyring(t, "2")).Get(ctx, "key")
+	assert.EqualError(t, err, `value of "key" is encrypted with unknown key "1"`)
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package encryption // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxKeyIDLength is the maximum length of a key ID, which is stored in a single byte of the value header
+const maxKeyIDLength = 255
+
+// KeyConfig defines where an encryption key is read from. The key is base64 encoded,
+// and must decode to 16, 24 or 32 bytes to select AES-128, AES-192 or AES-256.
+type KeyConfig struct {
+	// KeyID identifies the key in the header of the values it encrypts
+	KeyID string `mapstructure:"key_id"`
+	// KeyFile is the path of a file containing the key
+	KeyFile string `mapstructure:"key_file"`
+	// KeyEnv is the name of an environment variable containing the key
+	KeyEnv string `mapstructure:"key_env"`
+}
+
+// Config defines the configuration of value encryption.
+type Config struct {
+	// The current key encrypts all the values written
+	KeyConfig `mapstructure:",squash"`
+	// PreviousKeys are only used to decrypt values written before the current key was rotated in
+	PreviousKeys []KeyConfig `mapstructure:"previous_keys"`
+}
+
+func (cfg *KeyConfig) Validate() error {
+	if cfg.KeyID == "" {
+		return errors.New("missing key_id")
+	}
+	if len(cfg.KeyID) > maxKeyIDLength {
+		return fmt.Errorf("key_id %q is longer than %d bytes", cfg.KeyID, maxKeyIDLength)
+	}
+	if (cfg.KeyFile == "") == (cfg.KeyEnv == "") {
+		return fmt.Errorf("exactly one of key_file and key_env must be set for key %q", cfg.KeyID)
+	}
+	return nil
+}
+
+func (cfg *Config) Validate() error {
+	if err := cfg.KeyConfig.Validate(); err != nil {
+		return err
+	}
+	ids := map[string]bool{cfg.KeyID: true}
+	for _, key := range cfg.PreviousKeys {
+		if err := key.Validate(); err != nil {
+			return err
+		}
+		if ids[key.KeyID] {
+			return fmt.Errorf("duplicate key_id %q", key.KeyID)
+		}
+		ids[key.KeyID] = true
+	}
+	return nil
+}
+
+// load reads and decodes the key
+func (cfg *KeyConfig) load() ([]byte, error) {
+	var encoded string
+	if cfg.KeyFile != "" {
+		contents, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %q: %w", cfg.KeyID, err)
+		}
+		encoded = string(contents)
+	} else {
+		var ok bool
+		encoded, ok = os.LookupEnv(cfg.KeyEnv)
+		if !ok {
+			return nil, fmt.Errorf("failed to read key %q: environment variable %s is not set", cfg.KeyID, cfg.KeyEnv)
+		}
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key %q: %w", cfg.KeyID, err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("key %q is %d bytes long, must be 16, 24 or 32 bytes", cfg.KeyID, len(key))
+	}
+}
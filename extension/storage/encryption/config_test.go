@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package encryption
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		err    string
+	}{
+		{
+			name:   "key-file",
+			config: Config{KeyConfig: KeyConfig{KeyID: "1", KeyFile: "key"}},
+		},
+		{
+			name: "previous-keys",
+			config: Config{
+				KeyConfig:    KeyConfig{KeyID: "2", KeyEnv: "KEY_2"},
+				PreviousKeys: []KeyConfig{{KeyID: "1", KeyFile: "key"}},
+			},
+		},
+		{
+			name:   "missing-key-id",
+			config: Config{KeyConfig: KeyConfig{KeyFile: "key"}},
+			err:    "missing key_id",
+		},
+		{
+			name:   "long-key-id",
+			config: Config{KeyConfig: KeyConfig{KeyID: strings.Repeat("a", 256), KeyFile: "key"}},
+			err:    `key_id "` + strings.Repeat("a", 256) + `" is longer than 255 bytes`,
+		},
+		{
+			name:   "missing-key",
+			config: Config{KeyConfig: KeyConfig{KeyID: "1"}},
+			err:    `exactly one of key_file and key_env must be set for key "1"`,
+		},
+		{
+			name:   "key-file-and-env",
+			config: Config{KeyConfig: KeyConfig{KeyID: "1", KeyFile: "key", KeyEnv: "KEY"}},
+			err:    `exactly one of key_file and key_env must be set for key "1"`,
+		},
+		{
+			name: "invalid-previous-key",
+			config: Config{
+				KeyConfig:    KeyConfig{KeyID: "2", KeyEnv: "KEY_2"},
+				PreviousKeys: []KeyConfig{{KeyID: "1"}},
+			},
+			err: `exactly one of key_file and key_env must be set for key "1"`,
+		},
+		{
+			name: "duplicate-key-id",
+			config: Config{
+				KeyConfig:    KeyConfig{KeyID: "1", KeyEnv: "KEY_2"},
+				PreviousKeys: []KeyConfig{{KeyID: "1", KeyFile: "key"}},
+			},
+			err: `duplicate key_id "1"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestKeyConfigLoad(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+	t.Setenv("TEST_STORAGE_KEY", base64.StdEncoding.EncodeToString(key[:16]))
+	t.Setenv("TEST_STORAGE_INVALID_KEY", "not base64")
+	t.Setenv("TEST_STORAGE_SHORT_KEY", base64.StdEncoding.EncodeToString(key[:10]))
+
+	loaded, err := (&KeyConfig{KeyID: "file", KeyFile: keyFile}).load()
+	require.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	loaded, err = (&KeyConfig{KeyID: "env", KeyEnv: "TEST_STORAGE_KEY"}).load()
+	require.NoError(t, err)
+	assert.Equal(t, key[:16], loaded)
+
+	_, err = (&KeyConfig{KeyID: "missing", KeyFile: filepath.Join(t.TempDir(), "missing")}).load()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = (&KeyConfig{KeyID: "unset", KeyEnv: "TEST_STORAGE_UNSET_KEY"}).load()
+	assert.EqualError(t, err, `failed to read key "unset": environment variable TEST_STORAGE_UNSET_KEY is not set`)
+
+	_, err = (&KeyConfig{KeyID: "invalid", KeyEnv: "TEST_STORAGE_INVALID_KEY"}).load()
+	assert.ErrorContains(t, err, `failed to decode key "invalid"`)
+
+	_, err = (&KeyConfig{KeyID: "short", KeyEnv: "TEST_STORAGE_SHORT_KEY"}).load()
+	assert.EqualError(t, err, `key "short" is 10 bytes long, must be 16, 24 or 32 bytes`)
+}
@@ -0,0 +1,6 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package encryption implements a storage.Client wrapper encrypting the stored values
+// with AES-GCM, so that storage extensions can keep their contents encrypted at rest.
+package encryption // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package encryption // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// Encrypted values start with a header made of the magic bytes, the format version,
+// the length of the key ID and the key ID, followed by the nonce and the sealed value.
+// Values not starting with the magic bytes were stored before encryption was enabled.
+var magic = []byte{0xff, 'O', 'T', 'E'}
+
+const formatVersion = 1
+
+// Keyring encrypts values with the current key, and decrypts them with the key they were encrypted with.
+type Keyring struct {
+	currentID string
+	aeads     map[string]cipher.AEAD
+}
+
+// NewKeyring loads the keys of the configuration
+func NewKeyring(cfg *Config) (*Keyring, error) {
+	keyring := &Keyring{
+		currentID: cfg.KeyID,
+		aeads:     make(map[string]cipher.AEAD, len(cfg.PreviousKeys)+1),
+	}
+	for _, keyCfg := range append([]KeyConfig{cfg.KeyConfig}, cfg.PreviousKeys...) {
+		key, err := keyCfg.load()
+		if err != nil {
+			return nil, err
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		keyring.aeads[keyCfg.KeyID] = aead
+	}
+	return keyring, nil
+}
+
+// Encrypt encrypts the value stored under key with the current key.
+// The storage key is authenticated along with the value, so that values can't be moved between keys.
+func (k *Keyring) Encrypt(key string, value []byte) ([]byte, error) {
+	aead := k.aeads[k.currentID]
+
+	header := make([]byte, 0, len(magic)+2+len(k.currentID))
+	header = append(header, magic...)
+	header = append(header, formatVersion, byte(len(k.currentID)))
+	header = append(header, k.currentID...)
+
+	out := make([]byte, len(header)+aead.NonceSize(), len(header)+aead.NonceSize()+len(value)+aead.Overhead())
+	copy(out, header)
+	nonce := out[len(header):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(out, nonce, value, []byte(key)), nil
+}
+
+// Decrypt decrypts the value stored under key. Values which aren't encrypted are returned as is.
+func (k *Keyring) Decrypt(key string, value []byte) ([]byte, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	keyID, body, err := parseHeader(value)
+	if err != nil {
+		return nil, err
+	}
+	aead, ok := k.aeads[keyID]
+	if !ok {
+		return nil, fmt.Errorf("value of %q is encrypted with unknown key %q", key, keyID)
+	}
+	if len(body) < aead.NonceSize() {
+		return nil, fmt.Errorf("value of %q is truncated", key)
+	}
+	// Empty values are opened into a non nil slice, as nil values stand for missing keys
+	sealed := body[aead.NonceSize():]
+	plaintext, err := aead.Open(make([]byte, 0, len(sealed)), body[:aead.NonceSize()], sealed, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value of %q: %w", key, err)
+	}
+	return plaintext, nil
+}
+
+// NeedsRotation returns whether the value should be encrypted again, because it
+// isn't encrypted or is encrypted with another key than the current one
+func (k *Keyring) NeedsRotation(value []byte) bool {
+	if !IsEncrypted(value) {
+		return true
+	}
+	keyID, _, err := parseHeader(value)
+	return err != nil || keyID != k.currentID
+}
+
+// Rotate decrypts the value stored under key and encrypts it again with the current key
+func (k *Keyring) Rotate(key string, value []byte) ([]byte, error) {
+	plaintext, err := k.Decrypt(key, value)
+	if err != nil {
+		return nil, err
+	}
+	return k.Encrypt(key, plaintext)
+}
+
+// IsEncrypted returns whether the value starts with the header of encrypted values
+func IsEncrypted(value []byte) bool {
+	return bytes.HasPrefix(value, magic)
+}
+
+// parseHeader returns the key ID of an encrypted value, and the remaining bytes holding the nonce and sealed value
+func parseHeader(value []byte) (string, []byte, error) {
+	rest := value[len(magic):]
+	if len(rest) < 2 {
+		return "", nil, errors.New("encrypted value header is truncated")
+	}
+	if rest[0] != formatVersion {
+		return "", nil, fmt.Errorf("unsupported encrypted value format version %d", rest[0])
+	}
+	idLength := int(rest[1])
+	rest = rest[2:]
+	if len(rest) < idLength {
+		return "", nil, errors.New("encrypted value header is truncated")
+	}
+	return string(rest[:idLength]), rest[idLength:], nil
+}
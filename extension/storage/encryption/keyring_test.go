@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package encryption
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestConfig returns a configuration whose keys are read from environment variables,
+// with the first ID being the current key and the others the previous keys
+func newTestConfig(t *testing.T, ids ...string) *Config {
+	cfg := &Config{}
+	for i, id := range ids {
+		env := "TEST_STORAGE_KEY_" + id
+		t.Setenv(env, base64.StdEncoding.EncodeToString([]byte("key "+id+" 0123456789abcdef0123456789")[:32]))
+		if i == 0 {
+			cfg.KeyConfig = KeyConfig{KeyID: id, KeyEnv: env}
+		} else {
+			cfg.PreviousKeys = append(cfg.PreviousKeys, KeyConfig{KeyID: id, KeyEnv: env})
+		}
+	}
+	require.NoError(t, cfg.Validate())
+	return cfg
+}
+
+func newTestKeyring(t *testing.T, ids ...string) *Keyring {
+	keyring, err := NewKeyring(newTestConfig(t, ids...))
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestKeyringEncryptDecrypt(t *testing.T) {
+	keyring := newTestKeyring(t, "1")
+
+	for _, value := range [][]byte{[]byte("value"), {}, make([]byte, 1<<16)} {
+		encrypted, err := keyring.Encrypt("key", value)
+		require.NoError(t, err)
+		assert.True(t, IsEncrypted(encrypted))
+		assert.NotContains(t, string(encrypted), "value")
+
+		decrypted, err := keyring.Decrypt("key", encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, value, decrypted)
+	}
+}
+
+func TestKeyringRandomNonce(t *testing.T) {
+	keyring := newTestKeyring(t, "1")
+
+	first, err := keyring.Encrypt("key", []byte("value"))
+	require.NoError(t, err)
+	second, err := keyring.Encrypt("key", []byte("value"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
+
+func TestKeyringDecryptPlaintext(t *testing.T) {
+	keyring := newTestKeyring(t, "1")
+
+	decrypted, err := keyring.Decrypt("key", []byte("plaintext"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("plaintext"), decrypted)
+	assert.True(t, keyring.NeedsRotation([]byte("plaintext")))
+}
+
+func TestKeyringDecryptErrors(t *testing.T) {
+	keyring := newTestKeyring(t, "1")
+	encrypted, err := keyring.Encrypt("key", []byte("value"))
+	require.NoError(t, err)
+
+	// The storage key is authenticated along with the value
+	_, err = keyring.Decrypt("other", encrypted)
+	assert.ErrorContains(t, err, `failed to decrypt value of "other"`)
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = keyring.Decrypt("key", tampered)
+	assert.ErrorContains(t, err, `failed to decrypt value of "key"`)
+
+	_, err = newTestKeyring(t, "2").Decrypt("key", encrypted)
+	assert.EqualError(t, err, `value of "key" is encrypted with unknown key "1"`)
+
+	_, err = keyring.Decrypt("key", encrypted[:len(magic)+1])
+	assert.EqualError(t, err, "encrypted value header is truncated")
+
+	_, err = keyring.Decrypt("key", encrypted[:len(magic)+3+5])
+	assert.EqualError(t, err, `value of "key" is truncated`)
+
+	unsupported := append([]byte{}, encrypted...)
+	unsupported[len(magic)] = 2
+	_, err = keyring.Decrypt("key", unsupported)
+	assert.EqualError(t, err, "unsupported encrypted value format version 2")
+}
+
+func TestKeyringRotation(t *testing.T) {
+	oldKeyring := newTestKeyring(t, "1")
+	encrypted, err := oldKeyring.Encrypt("key", []byte("value"))
+	require.NoError(t, err)
+	assert.False(t, oldKeyring.NeedsRotation(encrypted))
+
+	keyring := newTestKeyring(t, "2", "1")
+	assert.True(t, keyring.NeedsRotation(encrypted))
+
+	// Values encrypted with previous keys can still be read
+	decrypted, err := keyring.Decrypt("key", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), decrypted)
+
+	rotated, err := keyring.Rotate("key", encrypted)
+	require.NoError(t, err)
+	assert.False(t, keyring.NeedsRotation(rotated))
+
+	decrypted, err = keyring.Decrypt("key", rotated)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), decrypted)
+
+	// Once rotated, the previous key is no longer needed
+	decrypted, err = newTestKeyring(t, "2").Decrypt("key", rotated)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), decrypted)
+}
@@ -15,6 +15,8 @@ import (
 	"go.etcd.io/bbolt"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
 )
 
 var defaultBucket = []byte(`default`)
@@ -35,6 +37,7 @@ type fileStorageClient struct {
 	db              *bbolt.DB
 	compactionCfg   *CompactionConfig
 	openTimeout     time.Duration
+	keyring         *encryption.Keyring
 	cancel          context.CancelFunc
 	closed          bool
 }
@@ -48,7 +51,7 @@ func bboltOptions(timeout time.Duration, noSync bool) *bbolt.Options {
 	}
 }
 
-func newClient(logger *zap.Logger, filePath string, timeout time.Duration, compactionCfg *CompactionConfig, noSync bool) (*fileStorageClient, error) {
+func newClient(logger *zap.Logger, filePath string, timeout time.Duration, compactionCfg *CompactionConfig, noSync bool, keyring *encryption.Keyring) (*fileStorageClient, error) {
 	options := bboltOptions(timeout, noSync)
 	db, err := bbolt.Open(filePath, 0600, options)
 	if err != nil {
@@ -64,7 +67,7 @@ func newClient(logger *zap.Logger, filePath string, timeout time.Duration, compa
 		return nil, err
 	}
 
-	client := &fileStorageClient{logger: logger, db: db, compactionCfg: compactionCfg, openTimeout: timeout}
+	client := &fileStorageClient{logger: logger, db: db, compactionCfg: compactionCfg, openTimeout: timeout, keyring: keyring}
 	if compactionCfg.OnRebound {
 		client.startCompactionLoop(context.Background())
 	}
@@ -187,6 +190,13 @@ func (c *fileStorageClient) Compact(compactionDirectory string, timeout time.Dur
 		zap.String(directoryKey, c.db.Path()),
 		zap.String(tempDirectoryKey, file.Name()))
 
+	// values are encrypted again before compacting, so that the space they used is reclaimed
+	if c.keyring != nil {
+		if err = c.rotateKeys(maxTransactionSize); err != nil {
+			return fmt.Errorf("failed to rotate encryption keys: %w", err)
+		}
+	}
+
 	// cannot reuse newClient as db shouldn't contain any bucket
 	compactedDb, err = bbolt.Open(file.Name(), 0600, options)
 	if err != nil {
@@ -239,6 +249,77 @@ func (c *fileStorageClient) Compact(compactionDirectory string, timeout time.Dur
 	return nil
 }
 
+// scan calls fn with the key and value size of every stored entry
+func (c *fileStorageClient) scan(_ context.Context, fn func(key string, valueSize int)) error {
+	return c.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(defaultBucket)
+		if bucket == nil {
+			return errors.New("storage not initialized")
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			fn(string(k), len(v))
+			return nil
+		})
+	})
+}
+
+// rotateKeys encrypts again with the current key the values which aren't encrypted with it, so that
+// previous keys can be retired once the database has been compacted. Like compaction, each transaction
+// holds up to maxTransactionSize bytes.
+func (c *fileStorageClient) rotateKeys(maxTransactionSize int64) error {
+	var keys [][]byte
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(defaultBucket)
+		if bucket == nil {
+			return errors.New("storage not initialized")
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			if c.keyring.NeedsRotation(v) {
+				// keys are only valid within a transaction, so we need to make a copy
+				keys = append(keys, append([]byte{}, k...))
+			}
+			return nil
+		})
+	})
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+
+	var rotated, failed int
+	for len(keys) > 0 {
+		err = c.db.Update(func(tx *bbolt.Tx) error {
+			bucket := tx.Bucket(defaultBucket)
+			var size int64
+			for len(keys) > 0 && (maxTransactionSize <= 0 || size < maxTransactionSize) {
+				key := keys[0]
+				keys = keys[1:]
+				value, rotateErr := c.keyring.Rotate(string(key), bucket.Get(key))
+				if rotateErr != nil {
+					// the value is left as is, as it can't be read with the configured keys anyway
+					c.logger.Warn("failed to rotate encryption key of value", zap.ByteString("key", key), zap.Error(rotateErr))
+					failed++
+					continue
+				}
+				if putErr := bucket.Put(key, value); putErr != nil {
+					return putErr
+				}
+				size += int64(len(key) + len(value))
+				rotated++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	c.logger.Info("rotated encryption keys",
+		zap.String(directoryKey, c.db.Path()),
+		zap.Int("rotated", rotated),
+		zap.Int("failed", failed))
+	return nil
+}
+
 // startCompactionLoop provides asynchronous compaction function
 func (c *fileStorageClient) startCompactionLoop(ctx context.Context) {
 	ctx, c.cancel = context.WithCancel(ctx)
@@ -9,6 +9,9 @@ import (
 	"io/fs"
 	"os"
 	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 // Config defines configuration for file storage extension.
@@ -20,6 +23,12 @@ type Config struct {
 
 	// FSync specifies that fsync should be called after each database write
 	FSync bool `mapstructure:"fsync,omitempty"`
+
+	// Encryption specifies that values are encrypted before they are written to the database
+	Encryption *encryption.Config `mapstructure:"encryption,omitempty"`
+
+	// Quota specifies the maximum size of the data held by all the clients of the extension
+	Quota quota.Config `mapstructure:"quota,omitempty"`
 }
 
 // CompactionConfig defines configuration for optional file storage compaction.
@@ -15,7 +15,9 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -49,8 +51,27 @@ func TestLoadConfig(t *testing.T) {
 				},
 				Timeout: 2 * time.Second,
 				FSync:   true,
+				Quota: quota.Config{
+					MaxSizeMiB:        512,
+					OnFull:            quota.OnFullDropOldest,
+					DegradedThreshold: 0.8,
+				},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "encryption"),
+			expected: func() component.Config {
+				ret := NewFactory().CreateDefaultConfig()
+				ret.(*Config).Directory = "."
+				ret.(*Config).Encryption = &encryption.Config{
+					KeyConfig: encryption.KeyConfig{KeyID: "2", KeyFile: "./key"},
+					PreviousKeys: []encryption.KeyConfig{
+						{KeyID: "1", KeyEnv: "FILE_STORAGE_PREVIOUS_KEY"},
+					},
+				}
+				return ret
+			}(),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.id.String(), func(t *testing.T) {
@@ -15,25 +15,43 @@ import (
 	"go.opentelemetry.io/collector/extension"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 type localFileStorage struct {
-	cfg    *Config
-	logger *zap.Logger
+	cfg     *Config
+	logger  *zap.Logger
+	keyring *encryption.Keyring
+	tracker *quota.Tracker
 }
 
 // Ensure this storage extension implements the appropriate interface
 var _ storage.Extension = (*localFileStorage)(nil)
 
-func newLocalFileStorage(logger *zap.Logger, config *Config) (extension.Extension, error) {
+func newLocalFileStorage(set extension.Settings, config *Config) (extension.Extension, error) {
+	tracker, err := quota.NewTracker(config.Quota, set)
+	if err != nil {
+		return nil, err
+	}
 	return &localFileStorage{
-		cfg:    config,
-		logger: logger,
+		cfg:     config,
+		logger:  set.Logger,
+		tracker: tracker,
 	}, nil
 }
 
-// Start runs cleanup if configured
-func (lfs *localFileStorage) Start(context.Context, component.Host) error {
+// Start loads the encryption keys and runs cleanup if configured
+func (lfs *localFileStorage) Start(_ context.Context, host component.Host) error {
+	lfs.tracker.Start(host)
+	if lfs.cfg.Encryption != nil {
+		keyring, err := encryption.NewKeyring(lfs.cfg.Encryption)
+		if err != nil {
+			return err
+		}
+		lfs.keyring = keyring
+	}
 	if lfs.cfg.Compaction.CleanupOnStart {
 		return lfs.cleanup(lfs.cfg.Compaction.Directory)
 	}
@@ -48,7 +66,7 @@ func (lfs *localFileStorage) Shutdown(context.Context) error {
 }
 
 // GetClient returns a storage client for an individual component
-func (lfs *localFileStorage) GetClient(_ context.Context, kind component.Kind, ent component.ID, name string) (storage.Client, error) {
+func (lfs *localFileStorage) GetClient(ctx context.Context, kind component.Kind, ent component.ID, name string) (storage.Client, error) {
 	var rawName string
 	if name == "" {
 		rawName = fmt.Sprintf("%s_%s_%s", kindString(kind), ent.Type(), ent.Name())
@@ -56,9 +74,8 @@ func (lfs *localFileStorage) GetClient(_ context.Context, kind component.Kind, e
 		rawName = fmt.Sprintf("%s_%s_%s_%s", kindString(kind), ent.Type(), ent.Name(), name)
 	}
 
-	rawName = sanitize(rawName)
-	absoluteName := filepath.Join(lfs.cfg.Directory, rawName)
-	client, err := newClient(lfs.logger, absoluteName, lfs.cfg.Timeout, lfs.cfg.Compaction, !lfs.cfg.FSync)
+	absoluteName := filepath.Join(lfs.cfg.Directory, sanitize(rawName))
+	client, err := newClient(lfs.logger, absoluteName, lfs.cfg.Timeout, lfs.cfg.Compaction, !lfs.cfg.FSync, lfs.keyring)
 
 	if err != nil {
 		return nil, err
@@ -72,7 +89,16 @@ func (lfs *localFileStorage) GetClient(_ context.Context, kind component.Kind, e
 		}
 	}
 
-	return client, nil
+	// the size of the values is accounted for once they are encrypted, as they are stored
+	tracked, err := lfs.tracker.NewClient(ctx, rawName, client, client.scan)
+	if err != nil {
+		_ = client.Close(ctx)
+		return nil, err
+	}
+	if lfs.keyring != nil {
+		return encryption.NewClient(tracked, lfs.keyring), nil
+	}
+	return tracked, nil
 }
 
 func kindString(k component.Kind) string {
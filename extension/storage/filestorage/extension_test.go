@@ -5,6 +5,7 @@ package filestorage
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,12 +14,16 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/extension/extensiontest"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/encryption"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 func TestExtensionIntegrity(t *testing.T) {
@@ -294,6 +299,15 @@ func newTestExtension(t *testing.T) storage.Extension {
 	return se
 }
 
+// fileClient returns the file storage client wrapped by a client returned by the extension
+func fileClient(t *testing.T, client storage.Client) *fileStorageClient {
+	wrapper, ok := client.(interface{ Unwrap() storage.Client })
+	require.True(t, ok)
+	c, ok := wrapper.Unwrap().(*fileStorageClient)
+	require.True(t, ok)
+	return c
+}
+
 func newTestEntity(name string) component.ID {
 	return component.MustNewIDWithName("nop", name)
 }
@@ -350,8 +364,7 @@ func TestCompaction(t *testing.T) {
 	}
 
 	// compact the db
-	c, ok := client.(*fileStorageClient)
-	require.True(t, ok)
+	c := fileClient(t, client)
 	err = c.Compact(tempDir, cfg.Timeout, 1)
 	require.NoError(t, err)
 	t.Cleanup(func() {
@@ -371,8 +384,7 @@ func TestCompaction(t *testing.T) {
 	}
 
 	// compact after data removal
-	c, ok = client.(*fileStorageClient)
-	require.True(t, ok)
+	c = fileClient(t, client)
 	err = c.Compact(tempDir, cfg.Timeout, 1)
 	require.NoError(t, err)
 	t.Cleanup(func() {
@@ -421,8 +433,7 @@ func TestCompactionRemoveTemp(t *testing.T) {
 	fileName := files[0].Name()
 
 	// perform compaction in the same directory
-	c, ok := client.(*fileStorageClient)
-	require.True(t, ok)
+	c := fileClient(t, client)
 	err = c.Compact(tempDir, cfg.Timeout, 1)
 	require.NoError(t, err)
 	t.Cleanup(func() {
@@ -438,8 +449,7 @@ func TestCompactionRemoveTemp(t *testing.T) {
 	// perform compaction in different directory
 	emptyTempDir := t.TempDir()
 
-	c, ok = client.(*fileStorageClient)
-	require.True(t, ok)
+	c = fileClient(t, client)
 	err = c.Compact(emptyTempDir, cfg.Timeout, 1)
 	require.NoError(t, err)
 	t.Cleanup(func() {
@@ -525,3 +535,160 @@ func TestCompactionOnStart(t *testing.T) {
 		require.NoError(t, client.Close(context.TODO()))
 	})
 }
+
+func TestEncryption(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	cfg := newEncryptedConfig(t, tempDir, "1")
+
+	// values written by the extension are encrypted at rest
+	writeTestValues(t, cfg, map[string][]byte{"key": []byte("secret value")})
+	raw := readRawValues(t, tempDir)
+	require.Len(t, raw, 1)
+	assert.True(t, encryption.IsEncrypted(raw["key"]))
+	assert.NotContains(t, string(raw["key"]), "secret value")
+
+	// and are decrypted when read
+	client := getTestClient(t, cfg)
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret value"), value)
+	require.NoError(t, client.Close(ctx))
+
+	// values can't be read without the key they were encrypted with
+	client = getTestClient(t, newEncryptedConfig(t, tempDir, "2"))
+	_, err = client.Get(ctx, "key")
+	assert.EqualError(t, err, `value of "key" is encrypted with unknown key "1"`)
+	require.NoError(t, client.Close(ctx))
+}
+
+func TestEncryptionKeyRotationOnCompaction(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	// values written before encryption was enabled are encrypted during compaction as well
+	plaintextCfg := NewFactory().CreateDefaultConfig().(*Config)
+	plaintextCfg.Directory = tempDir
+	writeTestValues(t, plaintextCfg, map[string][]byte{"plaintext": []byte("plaintext value")})
+	writeTestValues(t, newEncryptedConfig(t, tempDir, "1"), map[string][]byte{"encrypted": []byte("encrypted value")})
+
+	// compacting with a new current key encrypts all the values with it
+	cfg := newEncryptedConfig(t, tempDir, "2", "1")
+	cfg.Compaction.Directory = tempDir
+	cfg.Compaction.OnStart = true
+	client := getTestClient(t, cfg)
+	require.NoError(t, client.Close(ctx))
+
+	for key, value := range readRawValues(t, tempDir) {
+		assert.True(t, encryption.IsEncrypted(value), key)
+		assert.NotContains(t, string(value), "value", key)
+	}
+
+	// so the previous key is no longer needed
+	client = getTestClient(t, newEncryptedConfig(t, tempDir, "2"))
+	value, err := client.Get(ctx, "plaintext")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("plaintext value"), value)
+	value, err = client.Get(ctx, "encrypted")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("encrypted value"), value)
+	require.NoError(t, client.Close(ctx))
+}
+
+func TestEncryptionKeyErrorOnStart(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Directory = t.TempDir()
+	cfg.Encryption = &encryption.Config{
+		KeyConfig: encryption.KeyConfig{KeyID: "1", KeyFile: filepath.Join(cfg.Directory, "missing")},
+	}
+
+	extension, err := f.CreateExtension(context.Background(), extensiontest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.ErrorIs(t, extension.Start(context.Background(), componenttest.NewNopHost()), os.ErrNotExist)
+}
+
+// newEncryptedConfig returns a configuration encrypting values with the key of the first ID,
+// and decrypting them with the keys of all the IDs
+func newEncryptedConfig(t *testing.T, directory string, ids ...string) *Config {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Directory = directory
+	cfg.Encryption = &encryption.Config{}
+	for i, id := range ids {
+		keyFile := filepath.Join(t.TempDir(), "key")
+		key := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("test key %s 0123456789abcdefghijklmnopqrstuvwxyz", id))[:32])
+		require.NoError(t, os.WriteFile(keyFile, []byte(key), 0600))
+		keyCfg := encryption.KeyConfig{KeyID: id, KeyFile: keyFile}
+		if i == 0 {
+			cfg.Encryption.KeyConfig = keyCfg
+		} else {
+			cfg.Encryption.PreviousKeys = append(cfg.Encryption.PreviousKeys, keyCfg)
+		}
+	}
+	require.NoError(t, component.ValidateConfig(cfg))
+	return cfg
+}
+
+func getTestClient(t *testing.T, cfg *Config) storage.Client {
+	f := NewFactory()
+	extension, err := f.CreateExtension(context.Background(), extensiontest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, extension.Start(context.Background(), componenttest.NewNopHost()))
+
+	client, err := extension.(storage.Extension).GetClient(context.Background(), component.KindReceiver, newTestEntity("my_component"), "")
+	require.NoError(t, err)
+	return client
+}
+
+func writeTestValues(t *testing.T, cfg *Config, values map[string][]byte) {
+	client := getTestClient(t, cfg)
+	for key, value := range values {
+		require.NoError(t, client.Set(context.Background(), key, value))
+	}
+	require.NoError(t, client.Close(context.Background()))
+}
+
+// readRawValues reads the values of the database files of the directory as they are stored
+func readRawValues(t *testing.T, directory string) map[string][]byte {
+	files, err := filepath.Glob(filepath.Join(directory, "receiver_*"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	db, err := bbolt.Open(files[0], 0600, &bbolt.Options{ReadOnly: true})
+	require.NoError(t, err)
+	defer db.Close()
+
+	values := make(map[string][]byte)
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(defaultBucket).ForEach(func(k, v []byte) error {
+			values[string(k)] = append([]byte{}, v...)
+			return nil
+		})
+	}))
+	return values
+}
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Directory = t.TempDir()
+	cfg.Quota.MaxSizeMiB = 1
+	value := make([]byte, 400*1024)
+
+	client := getTestClient(t, cfg)
+	require.NoError(t, client.Set(ctx, "a", value))
+	require.NoError(t, client.Set(ctx, "b", value))
+	require.ErrorIs(t, client.Set(ctx, "c", value), quota.ErrStorageFull)
+	require.NoError(t, client.Close(ctx))
+
+	// the data already stored is accounted for when the client is opened again
+	cfg.Quota.OnFull = quota.OnFullDropOldest
+	client = getTestClient(t, cfg)
+	require.NoError(t, client.Set(ctx, "c", value))
+	for _, key := range []string{"a", "b", "c"} {
+		stored, err := client.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, key != "a", stored != nil, key)
+	}
+	require.NoError(t, client.Close(ctx))
+}
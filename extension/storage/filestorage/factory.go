@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/extension"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 const (
@@ -49,6 +50,7 @@ func createDefaultConfig() component.Config {
 		},
 		Timeout: time.Second,
 		FSync:   false,
+		Quota:   quota.NewDefaultConfig(),
 	}
 }
 
@@ -57,5 +59,5 @@ func createExtension(
 	params extension.Settings,
 	cfg component.Config,
 ) (extension.Extension, error) {
-	return newLocalFileStorage(params.Logger, cfg.(*Config))
+	return newLocalFileStorage(params, cfg.(*Config))
 }
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package quota // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
+
+import (
+	"container/list"
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type client struct {
+	tracker *Tracker
+	client  storage.Client
+	attrs   metric.MeasurementOption
+
+	// the following fields are guarded by the lock of the tracker
+	entries map[string]*list.Element
+	size    int64
+	closed  bool
+}
+
+// Get will retrieve data from storage that corresponds to the specified key
+func (c *client) Get(ctx context.Context, key string) ([]byte, error) {
+	defer c.tracker.recordDuration(ctx, c, "get", time.Now())
+	return c.client.Get(ctx, key)
+}
+
+// Set will store data. The data can be retrieved using the same key
+func (c *client) Set(ctx context.Context, key string, value []byte) error {
+	defer c.tracker.recordDuration(ctx, c, "set", time.Now())
+	return c.write(ctx, []write{{key: key, size: entrySize(key, value)}}, func() error {
+		return c.client.Set(ctx, key, value)
+	})
+}
+
+// Delete will delete data associated with the specified key
+func (c *client) Delete(ctx context.Context, key string) error {
+	defer c.tracker.recordDuration(ctx, c, "delete", time.Now())
+	return c.write(ctx, []write{{key: key, size: -1}}, func() error {
+		return c.client.Delete(ctx, key)
+	})
+}
+
+// Batch executes the specified operations in order. Get operation results are updated in place
+func (c *client) Batch(ctx context.Context, ops ...storage.Operation) error {
+	defer c.tracker.recordDuration(ctx, c, "batch", time.Now())
+	var writes []write
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Set:
+			writes = append(writes, write{key: op.Key, size: entrySize(op.Key, op.Value)})
+		case storage.Delete:
+			writes = append(writes, write{key: op.Key, size: -1})
+		}
+	}
+	return c.write(ctx, writes, func() error {
+		return c.client.Batch(ctx, ops...)
+	})
+}
+
+// Close will close the wrapped client, and stop accounting for the data it holds
+func (c *client) Close(ctx context.Context) error {
+	c.tracker.closeClient(ctx, c)
+	return c.client.Close(ctx)
+}
+
+// Unwrap returns the wrapped client
+func (c *client) Unwrap() storage.Client {
+	return c.client
+}
+
+// write runs do once there is room for writes
+func (c *client) write(ctx context.Context, writes []write, do func() error) error {
+	if len(writes) == 0 {
+		return do()
+	}
+	reserved, err := c.tracker.reserve(ctx, c, writes)
+	if err != nil {
+		return err
+	}
+	if err = do(); err != nil {
+		c.tracker.release(reserved)
+		return err
+	}
+	c.tracker.commit(ctx, c, writes, reserved)
+	return nil
+}
+
+// growth returns by how many bytes writes would increase the size of the client.
+// It must be called with the lock of the tracker held.
+func (c *client) growth(writes []write) int64 {
+	sizes := make(map[string]int64, len(writes))
+	for _, w := range writes {
+		sizes[w.key] = max(w.size, 0)
+	}
+	var growth int64
+	for key, size := range sizes {
+		growth += size
+		if elem, ok := c.entries[key]; ok {
+			growth -= elem.Value.(*entry).size
+		}
+	}
+	return growth
+}
+
+// apply accounts for writes. It must be called with the lock of the tracker held.
+func (c *client) apply(writes []write) {
+	if c.closed {
+		return
+	}
+	t := c.tracker
+	for _, w := range writes {
+		c.remove(w.key)
+		if w.size < 0 {
+			continue
+		}
+		c.entries[w.key] = t.entries.PushBack(&entry{client: c, key: w.key, size: w.size})
+		c.size += w.size
+		t.size += w.size
+	}
+}
+
+// remove stops accounting for key. It must be called with the lock of the tracker held.
+func (c *client) remove(key string) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	e := c.tracker.entries.Remove(elem).(*entry)
+	delete(c.entries, key)
+	c.size -= e.size
+	c.tracker.size -= e.size
+}
+
+func entrySize(key string, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
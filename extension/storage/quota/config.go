@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package quota // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// OnFullError rejects the writes that would exceed the maximum size
+	OnFullError = "error"
+	// OnFullDropOldest drops the least recently written entries of the writing client to make room for its writes
+	OnFullDropOldest = "drop_oldest"
+
+	defaultDegradedThreshold = 0.9
+)
+
+// Config defines the maximum size of the data held by a storage extension.
+type Config struct {
+	// MaxSizeMiB is the maximum size of the keys and values held by all the clients of the extension.
+	// There is no limit when it is 0.
+	MaxSizeMiB int64 `mapstructure:"max_size_mib"`
+	// OnFull specifies what happens to a write that would exceed the maximum size
+	OnFull string `mapstructure:"on_full"`
+	// DegradedThreshold is the share of the maximum size above which the extension reports a recoverable error
+	DegradedThreshold float64 `mapstructure:"degraded_threshold"`
+}
+
+// NewDefaultConfig returns a configuration without size limit.
+func NewDefaultConfig() Config {
+	return Config{
+		OnFull:            OnFullError,
+		DegradedThreshold: defaultDegradedThreshold,
+	}
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.MaxSizeMiB < 0 {
+		return errors.New("max_size_mib cannot be negative")
+	}
+	switch cfg.OnFull {
+	case OnFullError, OnFullDropOldest:
+	default:
+		return fmt.Errorf("invalid on_full %q, must be %q or %q", cfg.OnFull, OnFullError, OnFullDropOldest)
+	}
+	if cfg.DegradedThreshold <= 0 || cfg.DegradedThreshold > 1 {
+		return errors.New("degraded_threshold must be greater than 0 and at most 1")
+	}
+	return nil
+}
+
+func (cfg *Config) maxSize() int64 {
+	return cfg.MaxSizeMiB * 1024 * 1024
+}
@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		expectedErr string
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name: "drop oldest",
+			modify: func(cfg *Config) {
+				cfg.MaxSizeMiB = 100
+				cfg.OnFull = OnFullDropOldest
+				cfg.DegradedThreshold = 1
+			},
+		},
+		{
+			name:        "negative max size",
+			modify:      func(cfg *Config) { cfg.MaxSizeMiB = -1 },
+			expectedErr: "max_size_mib cannot be negative",
+		},
+		{
+			name:        "invalid on full",
+			modify:      func(cfg *Config) { cfg.OnFull = "block" },
+			expectedErr: `invalid on_full "block", must be "error" or "drop_oldest"`,
+		},
+		{
+			name:        "zero degraded threshold",
+			modify:      func(cfg *Config) { cfg.DegradedThreshold = 0 },
+			expectedErr: "degraded_threshold must be greater than 0 and at most 1",
+		},
+		{
+			name:        "degraded threshold above one",
+			modify:      func(cfg *Config) { cfg.DegradedThreshold = 1.5 },
+			expectedErr: "degraded_threshold must be greater than 0 and at most 1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			test.modify(&cfg)
+			err := cfg.Validate()
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package quota implements a storage.Client wrapper accounting for the size of the stored data,
+// so that storage extensions can enforce a maximum size, emit size and latency metrics and
+// report their status before the underlying storage is exhausted.
+package quota // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
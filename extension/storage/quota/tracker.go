@@ -0,0 +1,335 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package quota // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota/internal/metadata"
+)
+
+// ErrStorageFull is returned by the writes rejected because the storage reached its maximum size
+var ErrStorageFull = errors.New("storage is full")
+
+const (
+	storageKey   = "storage"
+	clientKey    = "client"
+	operationKey = "operation"
+)
+
+// ScanFunc calls fn with the key and value size of every entry already held by a client.
+// Entries are considered to be written in the order they are scanned.
+type ScanFunc func(ctx context.Context, fn func(key string, valueSize int)) error
+
+// state is the status reported by the tracker
+type state int
+
+const (
+	stateOK state = iota
+	stateDegraded
+	stateFull
+)
+
+// Tracker accounts for the size of the data held by the clients of a storage extension.
+// The size of an entry is the length of its key and value, as they are passed to the wrapped client.
+type Tracker struct {
+	cfg       Config
+	maxSize   int64
+	id        string
+	logger    *zap.Logger
+	telemetry *metadata.TelemetryBuilder
+	attrs     metric.MeasurementOption
+
+	mu       sync.Mutex
+	host     component.Host
+	size     int64
+	reserved int64
+	// entries holds the entries of all clients from the least to the most recently written
+	entries *list.List
+	state   state
+}
+
+type entry struct {
+	client *client
+	key    string
+	size   int64
+}
+
+// write is the size an operation leaves a key with, negative for a deletion
+type write struct {
+	key  string
+	size int64
+}
+
+// NewTracker creates a tracker for the clients of the extension created with set.
+func NewTracker(cfg Config, set extension.Settings) (*Tracker, error) {
+	telemetry, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tracker{
+		cfg:       cfg,
+		maxSize:   cfg.maxSize(),
+		id:        set.ID.String(),
+		logger:    set.Logger,
+		telemetry: telemetry,
+		attrs:     metric.WithAttributeSet(attribute.NewSet(attribute.String(storageKey, set.ID.String()))),
+		entries:   list.New(),
+	}
+	if t.maxSize > 0 {
+		t.telemetry.StorageMaxSize.Record(context.Background(), t.maxSize, t.attrs)
+	}
+	return t, nil
+}
+
+// Start sets the host the status of the extension is reported to.
+func (t *Tracker) Start(host component.Host) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.host = host
+}
+
+// NewClient wraps a storage.Client, so that the size of the data it holds is accounted for.
+// The data already held by the client is accounted for with scan.
+func (t *Tracker) NewClient(ctx context.Context, name string, wrapped storage.Client, scan ScanFunc) (storage.Client, error) {
+	c := &client{
+		tracker: t,
+		client:  wrapped,
+		attrs:   metric.WithAttributeSet(attribute.NewSet(attribute.String(storageKey, t.id), attribute.String(clientKey, name))),
+		entries: map[string]*list.Element{},
+	}
+
+	var writes []write
+	err := scan(ctx, func(key string, valueSize int) {
+		writes = append(writes, write{key: key, size: int64(len(key) + valueSize)})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to account for the size of the stored data: %w", err)
+	}
+
+	t.mu.Lock()
+	c.apply(writes)
+	t.updateState(false)
+	clientSize, size := c.size, t.size
+	t.mu.Unlock()
+
+	if t.maxSize > 0 && size > t.maxSize {
+		t.logger.Warn("storage holds more data than its maximum size",
+			zap.String(clientKey, name),
+			zap.Int64("size", size),
+			zap.Int64("max_size", t.maxSize))
+	}
+	t.recordSize(ctx, c, clientSize, size)
+	return c, nil
+}
+
+// reserve makes room for writes of c, and returns the number of bytes reserved for them
+func (t *Tracker) reserve(ctx context.Context, c *client, writes []write) (int64, error) {
+	t.mu.Lock()
+	growth := c.growth(writes)
+	if t.maxSize == 0 || growth <= 0 || t.size+t.reserved+growth <= t.maxSize {
+		if growth > 0 {
+			t.reserved += growth
+		}
+		t.mu.Unlock()
+		return max(growth, 0), nil
+	}
+
+	var victims []*entry
+	if t.cfg.OnFull == OnFullDropOldest {
+		victims = t.evict(c, writes, t.size+t.reserved+growth-t.maxSize)
+	}
+	if victims == nil {
+		t.updateState(true)
+		t.mu.Unlock()
+		t.telemetry.StorageRejectedWrites.Add(ctx, 1, t.attrs)
+		return 0, fmt.Errorf("%w: maximum size of %d bytes reached", ErrStorageFull, t.maxSize)
+	}
+	t.reserved += growth
+	t.mu.Unlock()
+
+	if err := t.drop(ctx, victims); err != nil {
+		t.release(growth)
+		return 0, err
+	}
+	return growth, nil
+}
+
+// evict removes the least recently written entries of c, other than the ones written by writes,
+// until at least need bytes are freed. The entries of the other clients are left alone, so that a
+// client can't drop the data of another component. It returns nil and removes nothing if not enough
+// bytes can be freed.
+func (t *Tracker) evict(c *client, writes []write, need int64) []*entry {
+	written := make(map[string]bool, len(writes))
+	for _, w := range writes {
+		written[w.key] = true
+	}
+
+	var victims []*entry
+	var freed int64
+	for elem := t.entries.Front(); elem != nil && freed < need; elem = elem.Next() {
+		e := elem.Value.(*entry)
+		if e.client != c || written[e.key] {
+			continue
+		}
+		victims = append(victims, e)
+		freed += e.size
+	}
+	if freed < need {
+		return nil
+	}
+	for _, e := range victims {
+		e.client.remove(e.key)
+	}
+	return victims
+}
+
+// drop deletes the evicted entries from their clients. The entries that can't be deleted are accounted for again.
+func (t *Tracker) drop(ctx context.Context, victims []*entry) error {
+	var clients []*client
+	deletes := map[*client][]storage.Operation{}
+	var freed int64
+	for _, e := range victims {
+		if _, ok := deletes[e.client]; !ok {
+			clients = append(clients, e.client)
+		}
+		deletes[e.client] = append(deletes[e.client], storage.DeleteOperation(e.key))
+		freed += e.size
+	}
+
+	var errs error
+	var restored []*entry
+	for _, c := range clients {
+		if err := c.client.Batch(ctx, deletes[c]...); err != nil {
+			errs = errors.Join(errs, err)
+			for _, e := range victims {
+				if e.client == c {
+					restored = append(restored, e)
+				}
+			}
+		}
+	}
+
+	t.mu.Lock()
+	// restored entries are still the oldest ones, so they are put back in front in their original order
+	evicted := int64(len(victims))
+	for i := len(restored) - 1; i >= 0; i-- {
+		e := restored[i]
+		if _, ok := e.client.entries[e.key]; !ok && !e.client.closed {
+			e.client.entries[e.key] = t.entries.PushFront(e)
+			e.client.size += e.size
+			t.size += e.size
+		}
+		freed -= e.size
+		evicted--
+	}
+	sizes := make([]int64, len(clients))
+	for i, c := range clients {
+		sizes[i] = c.size
+	}
+	size := t.size
+	t.mu.Unlock()
+
+	for i, c := range clients {
+		t.recordSize(ctx, c, sizes[i], size)
+	}
+	if evicted > 0 {
+		t.telemetry.StorageEvictedEntries.Add(ctx, evicted, t.attrs)
+		t.telemetry.StorageEvictedSize.Add(ctx, freed, t.attrs)
+	}
+	if errs != nil {
+		t.logger.Warn("failed to drop the oldest entries", zap.Error(errs))
+		return fmt.Errorf("%w: failed to drop the oldest entries: %w", ErrStorageFull, errs)
+	}
+	return nil
+}
+
+// commit accounts for the writes of c once they are done, and releases the bytes reserved for them
+func (t *Tracker) commit(ctx context.Context, c *client, writes []write, reserved int64) {
+	t.mu.Lock()
+	t.reserved -= reserved
+	c.apply(writes)
+	t.updateState(false)
+	clientSize, size := c.size, t.size
+	t.mu.Unlock()
+	t.recordSize(ctx, c, clientSize, size)
+}
+
+// release gives back the bytes reserved for writes that failed
+func (t *Tracker) release(reserved int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reserved -= reserved
+}
+
+// closeClient stops accounting for the data held by c, as it can't be dropped once c is closed
+func (t *Tracker) closeClient(ctx context.Context, c *client) {
+	t.mu.Lock()
+	c.closed = true
+	for key := range c.entries {
+		c.remove(key)
+	}
+	t.updateState(false)
+	size := t.size
+	t.mu.Unlock()
+	t.recordSize(ctx, c, 0, size)
+}
+
+func (t *Tracker) recordSize(ctx context.Context, c *client, clientSize, size int64) {
+	t.telemetry.StorageClientSize.Record(ctx, clientSize, c.attrs)
+	t.telemetry.StorageSize.Record(ctx, size, t.attrs)
+}
+
+func (t *Tracker) recordDuration(ctx context.Context, c *client, operation string, start time.Time) {
+	t.telemetry.StorageOperationDuration.Record(ctx, time.Since(start).Seconds(),
+		c.attrs, metric.WithAttributes(attribute.String(operationKey, operation)))
+}
+
+// updateState reports the status of the extension when it changes. It must be called with the lock held.
+func (t *Tracker) updateState(rejected bool) {
+	if t.maxSize == 0 {
+		return
+	}
+
+	newState := stateOK
+	switch {
+	case rejected:
+		newState = stateFull
+	case float64(t.size) >= t.cfg.DegradedThreshold*float64(t.maxSize):
+		newState = stateDegraded
+	}
+	// a full storage remains reported as such until it goes below the threshold again
+	if newState == t.state || (newState == stateDegraded && t.state == stateFull) {
+		return
+	}
+	t.state = newState
+	if t.host == nil {
+		return
+	}
+
+	switch newState {
+	case stateFull:
+		componentstatus.ReportStatus(t.host, componentstatus.NewRecoverableErrorEvent(
+			fmt.Errorf("%w: maximum size of %d bytes reached", ErrStorageFull, t.maxSize)))
+	case stateDegraded:
+		componentstatus.ReportStatus(t.host, componentstatus.NewRecoverableErrorEvent(
+			fmt.Errorf("storage holds %d bytes, above %.0f%% of its maximum size of %d bytes",
+				t.size, t.cfg.DegradedThreshold*100, t.maxSize)))
+	default:
+		componentstatus.ReportStatus(t.host, componentstatus.NewEvent(componentstatus.StatusOK))
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+type statusHost struct {
+	component.Host
+	events []*componentstatus.Event
+}
+
+func (h *statusHost) Report(event *componentstatus.Event) {
+	h.events = append(h.events, event)
+}
+
+func (h *statusHost) statuses() []componentstatus.Status {
+	statuses := make([]componentstatus.Status, len(h.events))
+	for i, event := range h.events {
+		statuses[i] = event.Status()
+	}
+	return statuses
+}
+
+func newTestTracker(t *testing.T, cfg Config, maxSize int64) (*Tracker, *componentTestTelemetry, *statusHost) {
+	tt := setupTestTelemetry()
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	set := extensiontest.NewNopSettings()
+	set.ID = component.MustNewID("file_storage")
+	set.MeterProvider = tt.meterProvider
+	set.LeveledMeterProvider = func(configtelemetry.Level) metric.MeterProvider {
+		return tt.meterProvider
+	}
+	tracker, err := NewTracker(cfg, set)
+	require.NoError(t, err)
+	tracker.maxSize = maxSize
+
+	host := &statusHost{Host: componenttest.NewNopHost()}
+	tracker.Start(host)
+	return tracker, &tt, host
+}
+
+func newTestClient(t *testing.T, tracker *Tracker, name string) (storage.Client, *storagetest.TestClient) {
+	raw := storagetest.NewInMemoryClient(component.KindReceiver, component.MustNewID("test"), name)
+	client, err := tracker.NewClient(context.Background(), name, raw, scanNothing)
+	require.NoError(t, err)
+	return client, raw
+}
+
+func scanNothing(context.Context, func(string, int)) error {
+	return nil
+}
+
+func TestClientAccounting(t *testing.T) {
+	ctx := context.Background()
+	tracker, tt, host := newTestTracker(t, NewDefaultConfig(), 0)
+	client, raw := newTestClient(t, tracker, "receiver_test")
+
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	assert.EqualValues(t, 8, tracker.size)
+
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	// overwriting a key replaces its size
+	require.NoError(t, client.Set(ctx, "key", []byte("v")))
+	assert.EqualValues(t, 4, tracker.size)
+
+	require.NoError(t, client.Batch(ctx,
+		storage.SetOperation("a", []byte("12345")),
+		storage.SetOperation("b", []byte("12345")),
+		storage.DeleteOperation("a"),
+		storage.GetOperation("key"),
+	))
+	assert.EqualValues(t, 10, tracker.size)
+
+	require.NoError(t, client.Delete(ctx, "key"))
+	require.NoError(t, client.Delete(ctx, "missing"))
+	assert.EqualValues(t, 6, tracker.size)
+
+	stored, err := raw.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("12345"), stored)
+
+	// the status is never reported without maximum size
+	assert.Empty(t, host.events)
+
+	var md metricdata.ResourceMetrics
+	require.NoError(t, tt.reader.Collect(ctx, &md))
+	storageAttrs := attribute.NewSet(attribute.String("storage", "file_storage"))
+	clientAttrs := attribute.NewSet(attribute.String("storage", "file_storage"), attribute.String("client", "receiver_test"))
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "otelcol_storage_size",
+		Description: "Size of the keys and values held by all clients of a storage extension",
+		Unit:        "By",
+		Data: metricdata.Gauge[int64]{
+			DataPoints: []metricdata.DataPoint[int64]{{Attributes: storageAttrs, Value: 6}},
+		},
+	}, tt.getMetric("otelcol_storage_size", md), metricdatatest.IgnoreTimestamp())
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "otelcol_storage_client_size",
+		Description: "Size of the keys and values held by a storage client",
+		Unit:        "By",
+		Data: metricdata.Gauge[int64]{
+			DataPoints: []metricdata.DataPoint[int64]{{Attributes: clientAttrs, Value: 6}},
+		},
+	}, tt.getMetric("otelcol_storage_client_size", md), metricdatatest.IgnoreTimestamp())
+
+	durations := tt.getMetric("otelcol_storage_operation_duration", md).Data.(metricdata.Histogram[float64])
+	counts := map[string]uint64{}
+	for _, dp := range durations.DataPoints {
+		operation, _ := dp.Attributes.Value("operation")
+		counts[operation.AsString()] = dp.Count
+	}
+	assert.Equal(t, map[string]uint64{"get": 1, "set": 2, "batch": 1, "delete": 2}, counts)
+}
+
+func TestClientScan(t *testing.T) {
+	ctx := context.Background()
+	tracker, _, host := newTestTracker(t, NewDefaultConfig(), 100)
+
+	raw := storagetest.NewInMemoryClient(component.KindReceiver, component.MustNewID("test"), "")
+	scanErr := errors.New("scan failed")
+	_, err := tracker.NewClient(ctx, "receiver_test", raw, func(context.Context, func(string, int)) error {
+		return scanErr
+	})
+	require.ErrorIs(t, err, scanErr)
+
+	_, err = tracker.NewClient(ctx, "receiver_test", raw, func(_ context.Context, fn func(string, int)) error {
+		fn("first", 45)
+		fn("second", 44)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, tracker.size)
+	assert.Equal(t, []componentstatus.Status{componentstatus.StatusRecoverableError}, host.statuses())
+}
+
+func TestOnFullError(t *testing.T) {
+	ctx := context.Background()
+	tracker, tt, host := newTestTracker(t, NewDefaultConfig(), 100)
+	client, _ := newTestClient(t, tracker, "receiver_test")
+
+	require.NoError(t, client.Set(ctx, "1", make([]byte, 49)))
+	require.NoError(t, client.Set(ctx, "2", make([]byte, 29)))
+	assert.Empty(t, host.events)
+
+	require.NoError(t, client.Set(ctx, "2", make([]byte, 49)))
+	assert.Equal(t, []componentstatus.Status{componentstatus.StatusRecoverableError}, host.statuses())
+
+	err := client.Set(ctx, "3", []byte{0})
+	require.ErrorIs(t, err, ErrStorageFull)
+	assert.EqualError(t, err, "storage is full: maximum size of 100 bytes reached")
+	err = client.Batch(ctx, storage.DeleteOperation("1"), storage.SetOperation("3", make([]byte, 60)))
+	require.ErrorIs(t, err, ErrStorageFull)
+	assert.EqualValues(t, 100, tracker.size)
+	require.Len(t, host.events, 2)
+	require.ErrorIs(t, host.events[1].Err(), ErrStorageFull)
+
+	// writes that don't grow the storage are accepted
+	require.NoError(t, client.Set(ctx, "2", make([]byte, 9)))
+	require.NoError(t, client.Batch(ctx, storage.DeleteOperation("1"), storage.SetOperation("3", make([]byte, 29))))
+	assert.EqualValues(t, 40, tracker.size)
+	assert.Equal(t, []componentstatus.Status{
+		componentstatus.StatusRecoverableError,
+		componentstatus.StatusRecoverableError,
+		componentstatus.StatusOK,
+	}, host.statuses())
+
+	var md metricdata.ResourceMetrics
+	require.NoError(t, tt.reader.Collect(ctx, &md))
+	storageAttrs := attribute.NewSet(attribute.String("storage", "file_storage"))
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "otelcol_storage_rejected_writes",
+		Description: "Number of writes rejected because the storage is full",
+		Unit:        "{writes}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints:  []metricdata.DataPoint[int64]{{Attributes: storageAttrs, Value: 2}},
+		},
+	}, tt.getMetric("otelcol_storage_rejected_writes", md), metricdatatest.IgnoreTimestamp())
+}
+
+func TestOnFullDropOldest(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewDefaultConfig()
+	cfg.OnFull = OnFullDropOldest
+	tracker, tt, host := newTestTracker(t, cfg, 100)
+	first, firstRaw := newTestClient(t, tracker, "first")
+	second, secondRaw := newTestClient(t, tracker, "second")
+
+	require.NoError(t, first.Set(ctx, "a", make([]byte, 19)))
+	require.NoError(t, second.Set(ctx, "b", make([]byte, 19)))
+	require.NoError(t, first.Set(ctx, "c", make([]byte, 19)))
+	require.NoError(t, second.Set(ctx, "d", make([]byte, 19)))
+	// rewriting a key makes it the most recently written one
+	require.NoError(t, first.Set(ctx, "a", make([]byte, 19)))
+
+	// b and d are the oldest entries of the client, and are dropped to make room, while c is older
+	// but belongs to another client
+	require.NoError(t, second.Set(ctx, "e", make([]byte, 59)))
+	assert.EqualValues(t, 100, tracker.size)
+	for _, key := range []string{"b", "d", "e"} {
+		value, err := secondRaw.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, key == "e", value != nil, key)
+	}
+	for _, key := range []string{"a", "c"} {
+		value, err := firstRaw.Get(ctx, key)
+		require.NoError(t, err)
+		assert.NotNil(t, value, key)
+	}
+
+	// a batch can't drop the entries it writes, nor be larger than the maximum size
+	err := first.Batch(ctx, storage.SetOperation("a", make([]byte, 59)), storage.SetOperation("f", make([]byte, 49)))
+	require.ErrorIs(t, err, ErrStorageFull)
+	assert.EqualValues(t, 100, tracker.size)
+
+	// a write that doesn't fit in the entries of its client is rejected rather than dropping the entries of another one
+	err = first.Set(ctx, "g", make([]byte, 49))
+	require.ErrorIs(t, err, ErrStorageFull)
+	assert.EqualValues(t, 100, tracker.size)
+	value, err := secondRaw.Get(ctx, "e")
+	require.NoError(t, err)
+	assert.NotNil(t, value)
+
+	assert.Equal(t, []componentstatus.Status{
+		componentstatus.StatusRecoverableError,
+		componentstatus.StatusRecoverableError,
+	}, host.statuses())
+
+	var md metricdata.ResourceMetrics
+	require.NoError(t, tt.reader.Collect(ctx, &md))
+	storageAttrs := attribute.NewSet(attribute.String("storage", "file_storage"))
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "otelcol_storage_evicted_entries",
+		Description: "Number of entries dropped to make room for new writes",
+		Unit:        "{entries}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints:  []metricdata.DataPoint[int64]{{Attributes: storageAttrs, Value: 2}},
+		},
+	}, tt.getMetric("otelcol_storage_evicted_entries", md), metricdatatest.IgnoreTimestamp())
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "otelcol_storage_evicted_size",
+		Description: "Size of the entries dropped to make room for new writes",
+		Unit:        "By",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints:  []metricdata.DataPoint[int64]{{Attributes: storageAttrs, Value: 40}},
+		},
+	}, tt.getMetric("otelcol_storage_evicted_size", md), metricdatatest.IgnoreTimestamp())
+}
+
+func TestDropFailure(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewDefaultConfig()
+	cfg.OnFull = OnFullDropOldest
+	tracker, _, _ := newTestTracker(t, cfg, 100)
+	first, _ := newTestClient(t, tracker, "first")
+	second, secondRaw := newTestClient(t, tracker, "second")
+
+	require.NoError(t, first.Set(ctx, "a", make([]byte, 49)))
+	require.NoError(t, second.Set(ctx, "b", make([]byte, 49)))
+
+	// the entries of a closed client can't be deleted anymore
+	require.NoError(t, secondRaw.Close(ctx))
+	err := second.Set(ctx, "c", make([]byte, 9))
+	require.ErrorIs(t, err, ErrStorageFull)
+	assert.EqualValues(t, 100, tracker.size)
+	assert.Zero(t, tracker.reserved)
+}
+
+func TestClientClose(t *testing.T) {
+	ctx := context.Background()
+	tracker, _, host := newTestTracker(t, NewDefaultConfig(), 100)
+	first, _ := newTestClient(t, tracker, "first")
+	second, _ := newTestClient(t, tracker, "second")
+
+	require.NoError(t, first.Set(ctx, "a", make([]byte, 89)))
+	require.NoError(t, second.Set(ctx, "b", make([]byte, 9)))
+	require.NoError(t, first.Close(ctx))
+	assert.EqualValues(t, 10, tracker.size)
+	assert.Equal(t, 1, tracker.entries.Len())
+	assert.Equal(t, []componentstatus.Status{
+		componentstatus.StatusRecoverableError,
+		componentstatus.StatusOK,
+	}, host.statuses())
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config/configopaque"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 // Config defines configuration for the Redis storage extension.
@@ -15,4 +17,6 @@ type Config struct {
 	Password   configopaque.String `mapstructure:"password"`
 	DB         int                 `mapstructure:"db"`
 	Expiration time.Duration       `mapstructure:"expiration"`
+	// Quota specifies the maximum size of the data held by all the clients of the extension
+	Quota quota.Config `mapstructure:"quota"`
 }
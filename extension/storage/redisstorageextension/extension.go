@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -14,26 +15,35 @@ import (
 	"go.opentelemetry.io/collector/extension"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 type redisStorage struct {
-	cfg    *Config
-	logger *zap.Logger
-	client *redis.Client
+	cfg     *Config
+	logger  *zap.Logger
+	client  *redis.Client
+	tracker *quota.Tracker
 }
 
 // Ensure this storage extension implements the appropriate interface
 var _ storage.Extension = (*redisStorage)(nil)
 
-func newRedisStorage(logger *zap.Logger, config *Config) (extension.Extension, error) {
+func newRedisStorage(set extension.Settings, config *Config) (extension.Extension, error) {
+	tracker, err := quota.NewTracker(config.Quota, set)
+	if err != nil {
+		return nil, err
+	}
 	return &redisStorage{
-		cfg:    config,
-		logger: logger,
+		cfg:     config,
+		logger:  set.Logger,
+		tracker: tracker,
 	}, nil
 }
 
 // Start runs cleanup if configured
-func (rs *redisStorage) Start(context.Context, component.Host) error {
+func (rs *redisStorage) Start(_ context.Context, host component.Host) error {
+	rs.tracker.Start(host)
 	c := redis.NewClient(&redis.Options{
 		Addr:     rs.cfg.Endpoint,
 		Password: string(rs.cfg.Password),
@@ -97,8 +107,36 @@ func (rc redisClient) Close(_ context.Context) error {
 	return nil
 }
 
+// scan calls fn with the key and value size of every entry stored with the prefix of the client
+func (rc redisClient) scan(ctx context.Context, fn func(key string, valueSize int)) error {
+	var keys []string
+	iter := rc.client.Scan(ctx, 0, patternEscaper.Replace(rc.prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil || len(keys) == 0 {
+		return err
+	}
+
+	p := rc.client.Pipeline()
+	lengths := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		lengths[i] = p.StrLen(ctx, key)
+	}
+	if _, err := p.Exec(ctx); err != nil {
+		return err
+	}
+	for i, key := range keys {
+		fn(strings.TrimPrefix(key, rc.prefix), int(lengths[i].Val()))
+	}
+	return nil
+}
+
+// patternEscaper escapes the characters with a special meaning in the patterns matched by SCAN
+var patternEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
 // GetClient returns a storage client for an individual component
-func (rs *redisStorage) GetClient(_ context.Context, kind component.Kind, ent component.ID, name string) (storage.Client, error) {
+func (rs *redisStorage) GetClient(ctx context.Context, kind component.Kind, ent component.ID, name string) (storage.Client, error) {
 	var rawName string
 	if name == "" {
 		rawName = fmt.Sprintf("%s_%s_%s", kindString(kind), ent.Type(), ent.Name())
@@ -106,11 +144,12 @@ func (rs *redisStorage) GetClient(_ context.Context, kind component.Kind, ent co
 		rawName = fmt.Sprintf("%s_%s_%s_%s", kindString(kind), ent.Type(), ent.Name(), name)
 	}
 
-	return redisClient{
+	client := redisClient{
 		client:     rs.client,
 		prefix:     rawName,
 		expiration: rs.cfg.Expiration,
-	}, nil
+	}
+	return rs.tracker.NewClient(ctx, rawName, client, client.scan)
 }
 
 func kindString(k component.Kind) string {
@@ -8,11 +8,14 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/extension/extensiontest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/quota"
 )
 
 func TestExtensionIntegrity(t *testing.T) {
@@ -203,3 +206,41 @@ func newTestExtension(t *testing.T) storage.Extension {
 func newTestEntity(name string) component.ID {
 	return component.MustNewIDWithName("nop", name)
 }
+
+func TestQuota(t *testing.T) {
+	t.Skip("Requires a Redis cluster to be present at localhost:6379")
+	ctx := context.Background()
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Quota.MaxSizeMiB = 1
+	value := make([]byte, 400*1024)
+
+	newClient := func() storage.Client {
+		se, err := f.CreateExtension(ctx, extensiontest.NewNopSettings(), cfg)
+		require.NoError(t, err)
+		require.NoError(t, se.Start(ctx, componenttest.NewNopHost()))
+		t.Cleanup(func() {
+			require.NoError(t, se.Shutdown(ctx))
+		})
+		client, err := se.(storage.Extension).GetClient(ctx, component.KindReceiver, newTestEntity("quota"), "")
+		require.NoError(t, err)
+		return client
+	}
+
+	client := newClient()
+	require.NoError(t, client.Set(ctx, "a", value))
+	require.NoError(t, client.Set(ctx, "b", value))
+	require.ErrorIs(t, client.Set(ctx, "c", value), quota.ErrStorageFull)
+	require.NoError(t, client.Close(ctx))
+
+	// the entries already stored are accounted for when the client is created again
+	client = newClient()
+	require.ErrorIs(t, client.Set(ctx, "c", value), quota.ErrStorageFull)
+	require.NoError(t, client.Delete(ctx, "a"))
+	require.NoError(t, client.Delete(ctx, "b"))
+	require.NoError(t, client.Close(ctx))
+}
+
+func TestPatternEscaper(t *testing.T) {
+	assert.Equal(t, `receiver_nop_a\*b\?c\[d\]e\\f`, patternEscaper.Replace(`receiver_nop_a*b?c[d]e\f`))
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package storagetest // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+
+import (
+	"context"
+	"encoding/binary"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+const (
+	benchmarkValueSize = 1024
+	benchmarkKeys      = 100
+	// benchmarkQueueDepth is the number of items waiting in the persistent queue benchmark
+	benchmarkQueueDepth = 1000
+)
+
+// BenchmarkClient runs the same benchmarks against any storage.Client, so that the results of
+// different storage extensions can be compared, e.g. with benchstat. newClient must return an
+// empty client, which is closed at the end of each benchmark.
+func BenchmarkClient(b *testing.B, newClient func(b *testing.B) storage.Client) {
+	ctx := context.Background()
+	value := make([]byte, benchmarkValueSize)
+
+	b.Run("Set", func(b *testing.B) {
+		client := newBenchmarkClient(b, newClient)
+		b.SetBytes(benchmarkValueSize)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			require.NoError(b, client.Set(ctx, benchmarkKey(i%benchmarkKeys), value))
+		}
+	})
+
+	b.Run("SetParallel", func(b *testing.B) {
+		client := newBenchmarkClient(b, newClient)
+		var i atomic.Int64
+		b.SetBytes(benchmarkValueSize)
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				require.NoError(b, client.Set(ctx, benchmarkKey(int(i.Add(1))%benchmarkKeys), value))
+			}
+		})
+	})
+
+	b.Run("Get", func(b *testing.B) {
+		client := newBenchmarkClient(b, newClient)
+		for i := 0; i < benchmarkKeys; i++ {
+			require.NoError(b, client.Set(ctx, benchmarkKey(i), value))
+		}
+		b.SetBytes(benchmarkValueSize)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := client.Get(ctx, benchmarkKey(i%benchmarkKeys))
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("Batch", func(b *testing.B) {
+		client := newBenchmarkClient(b, newClient)
+		ops := make([]storage.Operation, 10)
+		b.SetBytes(int64(len(ops)) * benchmarkValueSize)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for j := range ops {
+				ops[j] = storage.SetOperation(benchmarkKey((i*len(ops)+j)%benchmarkKeys), value)
+			}
+			require.NoError(b, client.Batch(ctx, ops...))
+		}
+	})
+
+	b.Run("Delete", func(b *testing.B) {
+		client := newBenchmarkClient(b, newClient)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			require.NoError(b, client.Delete(ctx, benchmarkKey(i%benchmarkKeys)))
+		}
+	})
+
+	// The operations of an exporter persistent queue: each item is written along with the write index,
+	// read along with the read index update, then deleted along with the dispatched index update.
+	b.Run("PersistentQueue", func(b *testing.B) {
+		client := newBenchmarkClient(b, newClient)
+		index := make([]byte, 8)
+		enqueue := func(i int) {
+			binary.LittleEndian.PutUint64(index, uint64(i+1))
+			require.NoError(b, client.Batch(ctx,
+				storage.SetOperation("wi", index),
+				storage.SetOperation(benchmarkKey(i), value),
+			))
+		}
+		for i := 0; i < benchmarkQueueDepth; i++ {
+			enqueue(i)
+		}
+		b.SetBytes(benchmarkValueSize)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			enqueue(benchmarkQueueDepth + i)
+
+			binary.LittleEndian.PutUint64(index, uint64(i+1))
+			get := storage.GetOperation(benchmarkKey(i))
+			require.NoError(b, client.Batch(ctx, storage.SetOperation("ri", index), get))
+			require.Len(b, get.Value, benchmarkValueSize)
+
+			require.NoError(b, client.Batch(ctx,
+				storage.SetOperation("di", index),
+				storage.DeleteOperation(benchmarkKey(i)),
+			))
+		}
+	})
+}
+
+func newBenchmarkClient(b *testing.B, newClient func(b *testing.B) storage.Client) storage.Client {
+	client := newClient(b)
+	b.Cleanup(func() {
+		require.NoError(b, client.Close(context.Background()))
+	})
+	return client
+}
+
+func benchmarkKey(i int) string {
+	return "key_" + strconv.Itoa(i)
+}
@@ -0,0 +1,17 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package storagetest
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+func BenchmarkInMemoryClient(b *testing.B) {
+	BenchmarkClient(b, func(*testing.B) storage.Client {
+		return NewInMemoryClient(component.KindReceiver, component.MustNewID("test"), "")
+	})
+}
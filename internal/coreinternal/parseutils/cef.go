@@ -0,0 +1,239 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	semconv "go.opentelemetry.io/collector/semconv/v1.25.0"
+)
+
+const (
+	// replace once conventions includes these
+	AttributeUserName = "user.name"
+
+	cefPrefix       = "CEF:"
+	cefHeaderFields = 7
+)
+
+// cefIntegerKeys are the extension keys of the CEF dictionary holding integer values.
+var cefIntegerKeys = map[string]bool{
+	"cn1": true, "cn2": true, "cn3": true, "cnt": true,
+	"dpid": true, "dpt": true, "dvcpid": true, "fsize": true,
+	"in": true, "oldFileSize": true, "out": true, "spid": true, "spt": true,
+	"destinationTranslatedPort": true, "sourceTranslatedPort": true, "type": true,
+}
+
+// cefFloatKeys are the extension keys of the CEF dictionary holding floating point values.
+var cefFloatKeys = map[string]bool{
+	"cfp1": true, "cfp2": true, "cfp3": true, "cfp4": true,
+	"dlat": true, "dlong": true, "slat": true, "slong": true,
+}
+
+// cefSemconvKeys maps the extension keys of the CEF dictionary to semantic convention attributes.
+var cefSemconvKeys = map[string]string{
+	"src":                      semconv.AttributeSourceAddress,
+	"spt":                      semconv.AttributeSourcePort,
+	"dst":                      semconv.AttributeDestinationAddress,
+	"dpt":                      semconv.AttributeDestinationPort,
+	"proto":                    semconv.AttributeNetworkTransport,
+	"app":                      semconv.AttributeNetworkProtocolName,
+	"request":                  semconv.AttributeURLFull,
+	"requestMethod":            semconv.AttributeHTTPRequestMethod,
+	"requestClientApplication": semconv.AttributeUserAgentOriginal,
+	"fname":                    semconv.AttributeFileName,
+	"filePath":                 semconv.AttributeFilePath,
+	"fsize":                    semconv.AttributeFileSize,
+	"dvchost":                  semconv.AttributeHostName,
+	"dvcpid":                   semconv.AttributeProcessPID,
+	"deviceProcessName":        semconv.AttributeProcessExecutableName,
+	"suser":                    AttributeUserName,
+}
+
+// lowercaseSemconvAttributes are the semantic convention attributes whose values are lowercase.
+var lowercaseSemconvAttributes = map[string]bool{
+	semconv.AttributeNetworkTransport:    true,
+	semconv.AttributeNetworkProtocolName: true,
+}
+
+// ParseCEF parses an ArcSight Common Event Format message. Anything preceding the CEF prefix,
+// such as a syslog header, is ignored. The header fields are returned as version, device_vendor,
+// device_product, device_version, device_event_class_id, name and severity, and the extension
+// as a map of key value pairs in extensions. Values of the integer and floating point keys of
+// the CEF dictionary are converted to numbers.
+// When semconvCompliant is set, known extension keys are moved out of extensions to the
+// semantic convention attributes they correspond to.
+func ParseCEF(input string, semconvCompliant bool) (map[string]any, error) {
+	start := strings.Index(input, cefPrefix)
+	if start < 0 {
+		return nil, errors.New("not a CEF message: missing CEF: prefix")
+	}
+
+	header, extension, err := splitHeader(input[start:], cefHeaderFields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CEF header: %w", err)
+	}
+	version, err := strconv.ParseInt(strings.TrimPrefix(header[0], cefPrefix), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CEF version %q", strings.TrimPrefix(header[0], cefPrefix))
+	}
+
+	pairs, err := parseCEFExtension(strings.TrimRight(extension, "\r\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]any{
+		"version":               version,
+		"device_vendor":         header[1],
+		"device_product":        header[2],
+		"device_version":        header[3],
+		"device_event_class_id": header[4],
+		"name":                  header[5],
+		"severity":              typedValue(header[6], true, false),
+	}
+	extensions := make(map[string]any, len(pairs))
+	for key, value := range pairs {
+		extensions[key] = typedValue(value, cefIntegerKeys[key], cefFloatKeys[key])
+	}
+	if semconvCompliant {
+		moveSemconvAttributes(extensions, m, cefSemconvKeys)
+	}
+	m["extensions"] = extensions
+	return m, nil
+}
+
+// parseCEFExtension splits the extension of a CEF message into key value pairs. Values may contain
+// spaces, so a value ends where the key of the next pair starts: at the last space preceding the
+// next unescaped equal sign.
+func parseCEFExtension(extension string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	var key string
+	valueStart, lastSpace := 0, -1
+
+	for i := 0; i < len(extension); i++ {
+		switch extension[i] {
+		case '\\':
+			// skip the escaped character
+			i++
+		case ' ':
+			lastSpace = i
+		case '=':
+			if key == "" {
+				key = strings.TrimSpace(extension[:i])
+				if key == "" || strings.ContainsAny(key, " \t") {
+					return nil, fmt.Errorf("invalid CEF extension key %q", key)
+				}
+				valueStart = i + 1
+				continue
+			}
+			if lastSpace < valueStart || lastSpace+1 == i {
+				// an unescaped equal sign in the value rather than the start of a new pair
+				continue
+			}
+			pairs[key] = unescapeCEFValue(strings.TrimSpace(extension[valueStart:lastSpace]))
+			key = extension[lastSpace+1 : i]
+			valueStart = i + 1
+		}
+	}
+
+	if key == "" {
+		if strings.TrimSpace(extension) != "" {
+			return nil, fmt.Errorf("invalid CEF extension %q", extension)
+		}
+		return pairs, nil
+	}
+	pairs[key] = unescapeCEFValue(strings.TrimSpace(extension[valueStart:]))
+	return pairs, nil
+}
+
+// unescapeCEFValue replaces the escape sequences of CEF extension values.
+func unescapeCEFValue(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			sb.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case '\\', '=', '|':
+			sb.WriteByte(value[i])
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(value[i])
+		}
+	}
+	return sb.String()
+}
+
+// splitHeader splits the first n pipe delimited header fields of CEF and LEEF messages, replacing
+// the escaped pipes and backslashes, and returns the remainder of the message following them.
+func splitHeader(input string, n int) ([]string, string, error) {
+	fields := make([]string, 0, n)
+	var field strings.Builder
+	for i := 0; i < len(input); i++ {
+		switch c := input[i]; {
+		case c == '\\' && i+1 < len(input) && (input[i+1] == '|' || input[i+1] == '\\'):
+			i++
+			field.WriteByte(input[i])
+		case c == '|':
+			fields = append(fields, field.String())
+			field.Reset()
+			if len(fields) == n {
+				return fields, input[i+1:], nil
+			}
+		default:
+			field.WriteByte(c)
+		}
+	}
+
+	// the last header field isn't followed by a pipe when the message has no extension.
+	fields = append(fields, field.String())
+	if len(fields) < n {
+		return nil, "", fmt.Errorf("expected %d fields, got %d", n, len(fields))
+	}
+	return fields, "", nil
+}
+
+// typedValue converts the value to a number if it's expected to be one, and returns
+// it unchanged if it isn't or if it can't be converted.
+func typedValue(value string, isInteger bool, isFloat bool) any {
+	switch {
+	case isInteger:
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case isFloat:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// moveSemconvAttributes moves the extensions having a corresponding semantic convention attribute to m.
+func moveSemconvAttributes(extensions map[string]any, m map[string]any, semconvKeys map[string]string) {
+	for key, attribute := range semconvKeys {
+		value, ok := extensions[key]
+		if !ok {
+			continue
+		}
+		if s, ok := value.(string); ok && lowercaseSemconvAttributes[attribute] {
+			value = strings.ToLower(s)
+		}
+		m[attribute] = value
+		delete(extensions, key)
+	}
+}
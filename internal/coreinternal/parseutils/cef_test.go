@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	semconv "go.opentelemetry.io/collector/semconv/v1.25.0"
+)
+
+func TestParseCEF(t *testing.T) {
+	cases := []struct {
+		name             string
+		input            string
+		semconvCompliant bool
+		expected         map[string]any
+		expectedErr      string
+	}{
+		{
+			name:  "header and extension",
+			input: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232",
+			expected: map[string]any{
+				"version":               int64(0),
+				"device_vendor":         "Security",
+				"device_product":        "threatmanager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "worm successfully stopped",
+				"severity":              int64(10),
+				"extensions": map[string]any{
+					"src": "10.0.0.1",
+					"dst": "2.1.2.2",
+					"spt": int64(1232),
+				},
+			},
+		},
+		{
+			name:  "syslog header",
+			input: "<134>Sep 19 08:26:10 host CEF:1|Vendor|Product|2.0|sig|name|High|",
+			expected: map[string]any{
+				"version":               int64(1),
+				"device_vendor":         "Vendor",
+				"device_product":        "Product",
+				"device_version":        "2.0",
+				"device_event_class_id": "sig",
+				"name":                  "name",
+				"severity":              "High",
+				"extensions":            map[string]any{},
+			},
+		},
+		{
+			name:  "no extension",
+			input: "CEF:0|Vendor|Product|2.0|sig|name|3",
+			expected: map[string]any{
+				"version":               int64(0),
+				"device_vendor":         "Vendor",
+				"device_product":        "Product",
+				"device_version":        "2.0",
+				"device_event_class_id": "sig",
+				"name":                  "name",
+				"severity":              int64(3),
+				"extensions":            map[string]any{},
+			},
+		},
+		{
+			name:  "escaped header",
+			input: `CEF:0|security|threat\|manager|1.0|100|detected a \\ in message|10|`,
+			expected: map[string]any{
+				"version":               int64(0),
+				"device_vendor":         "security",
+				"device_product":        "threat|manager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  `detected a \ in message`,
+				"severity":              int64(10),
+				"extensions":            map[string]any{},
+			},
+		},
+		{
+			name:  "escaped and spaced extension values",
+			input: `CEF:0|Vendor|Product|1.0|100|name|5|msg=detected a \= sign and a \\ in a | message\nsecond line act=blocked a=b cfp1=1.5 cnt=not a number`,
+			expected: map[string]any{
+				"version":               int64(0),
+				"device_vendor":         "Vendor",
+				"device_product":        "Product",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "name",
+				"severity":              int64(5),
+				"extensions": map[string]any{
+					"msg":  "detected a = sign and a \\ in a | message\nsecond line",
+					"act":  "blocked",
+					"a":    "b",
+					"cfp1": 1.5,
+					"cnt":  "not a number",
+				},
+			},
+		},
+		{
+			name:  "unescaped equal sign in value",
+			input: `CEF:0|Vendor|Product|1.0|100|name|5|request=https://example.com/?q=1 requestMethod=GET`,
+			expected: map[string]any{
+				"version":               int64(0),
+				"device_vendor":         "Vendor",
+				"device_product":        "Product",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "name",
+				"severity":              int64(5),
+				"extensions": map[string]any{
+					"request":       "https://example.com/?q=1",
+					"requestMethod": "GET",
+				},
+			},
+		},
+		{
+			name:             "semantic conventions",
+			input:            "CEF:0|Vendor|Product|1.0|100|name|5|src=10.0.0.1 spt=1232 dst=10.0.0.2 dpt=443 proto=TCP suser=admin cs1=custom cs1Label=label",
+			semconvCompliant: true,
+			expected: map[string]any{
+				"version":                           int64(0),
+				"device_vendor":                     "Vendor",
+				"device_product":                    "Product",
+				"device_version":                    "1.0",
+				"device_event_class_id":             "100",
+				"name":                              "name",
+				"severity":                          int64(5),
+				semconv.AttributeSourceAddress:      "10.0.0.1",
+				semconv.AttributeSourcePort:         int64(1232),
+				semconv.AttributeDestinationAddress: "10.0.0.2",
+				semconv.AttributeDestinationPort:    int64(443),
+				semconv.AttributeNetworkTransport:   "tcp",
+				AttributeUserName:                   "admin",
+				"extensions": map[string]any{
+					"cs1":      "custom",
+					"cs1Label": "label",
+				},
+			},
+		},
+		{
+			name:        "missing prefix",
+			input:       "LEEF:1.0|Vendor|Product|1.0|100|",
+			expectedErr: "not a CEF message: missing CEF: prefix",
+		},
+		{
+			name:        "missing header fields",
+			input:       "CEF:0|Vendor|Product|1.0",
+			expectedErr: "invalid CEF header: expected 7 fields, got 4",
+		},
+		{
+			name:        "invalid version",
+			input:       "CEF:zero|Vendor|Product|1.0|100|name|5|",
+			expectedErr: `invalid CEF version "zero"`,
+		},
+		{
+			name:        "invalid extension key",
+			input:       "CEF:0|Vendor|Product|1.0|100|name|5|invalid key=value",
+			expectedErr: `invalid CEF extension key "invalid key"`,
+		},
+		{
+			name:        "invalid extension",
+			input:       "CEF:0|Vendor|Product|1.0|100|name|5|value",
+			expectedErr: `invalid CEF extension "value"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseCEF(tc.input, tc.semconvCompliant)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
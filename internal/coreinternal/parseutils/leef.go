@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	semconv "go.opentelemetry.io/collector/semconv/v1.25.0"
+)
+
+const (
+	leefPrefix       = "LEEF:"
+	leefHeaderFields = 5
+
+	// defaultLEEFDelimiter separates the attributes of LEEF 1.0 messages, and of LEEF 2.0
+	// messages not specifying a delimiter.
+	defaultLEEFDelimiter = "\t"
+)
+
+// leefIntegerKeys are the predefined LEEF attribute keys holding integer values.
+var leefIntegerKeys = map[string]bool{
+	"dstBytes": true, "dstPackets": true, "dstPort": true, "dstPostNATPort": true, "dstPreNATPort": true,
+	"sev": true, "srcBytes": true, "srcPackets": true, "srcPort": true, "srcPostNATPort": true,
+	"srcPreNATPort": true, "totalBytes": true, "totalPackets": true,
+}
+
+// leefSemconvKeys maps the predefined LEEF attribute keys to semantic convention attributes.
+var leefSemconvKeys = map[string]string{
+	"src":       semconv.AttributeSourceAddress,
+	"srcPort":   semconv.AttributeSourcePort,
+	"dst":       semconv.AttributeDestinationAddress,
+	"dstPort":   semconv.AttributeDestinationPort,
+	"proto":     semconv.AttributeNetworkTransport,
+	"url":       semconv.AttributeURLFull,
+	"userAgent": semconv.AttributeUserAgentOriginal,
+	"usrName":   AttributeUserName,
+}
+
+// ParseLEEF parses an IBM Log Event Extended Format message, version 1.0 or 2.0. Anything preceding
+// the LEEF prefix, such as a syslog header, is ignored. The header fields are returned as version,
+// vendor, product, product_version and event_id, and the event attributes as a map of key value pairs
+// in extensions. Values of the integer keys predefined by LEEF are converted to numbers.
+// When semconvCompliant is set, known attribute keys are moved out of extensions to the
+// semantic convention attributes they correspond to.
+func ParseLEEF(input string, semconvCompliant bool) (map[string]any, error) {
+	start := strings.Index(input, leefPrefix)
+	if start < 0 {
+		return nil, errors.New("not a LEEF message: missing LEEF: prefix")
+	}
+
+	header, rest, err := splitHeader(input[start:], leefHeaderFields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LEEF header: %w", err)
+	}
+	version := strings.TrimPrefix(header[0], leefPrefix)
+
+	delimiter := defaultLEEFDelimiter
+	switch {
+	case strings.HasPrefix(version, "1."):
+	case strings.HasPrefix(version, "2."):
+		// LEEF 2.0 adds a header field holding the delimiter of the attributes.
+		end := strings.IndexByte(rest, '|')
+		if end < 0 {
+			return nil, errors.New("invalid LEEF header: missing delimiter field")
+		}
+		if delimiter, err = parseLEEFDelimiter(rest[:end]); err != nil {
+			return nil, err
+		}
+		rest = rest[end+1:]
+	default:
+		return nil, fmt.Errorf("unsupported LEEF version %q", version)
+	}
+
+	extensions := make(map[string]any)
+	for _, pair := range strings.Split(strings.TrimRight(rest, "\r\n"), delimiter) {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid LEEF attribute %q", pair)
+		}
+		extensions[key] = typedValue(value, leefIntegerKeys[key], false)
+	}
+
+	m := map[string]any{
+		"version":         version,
+		"vendor":          header[1],
+		"product":         header[2],
+		"product_version": header[3],
+		"event_id":        header[4],
+	}
+	if semconvCompliant {
+		moveSemconvAttributes(extensions, m, leefSemconvKeys)
+	}
+	m["extensions"] = extensions
+	return m, nil
+}
+
+// parseLEEFDelimiter parses the delimiter header field of LEEF 2.0 messages, which is either a
+// single character or its hexadecimal code prefixed by x or 0x.
+func parseLEEFDelimiter(field string) (string, error) {
+	switch {
+	case field == "":
+		return defaultLEEFDelimiter, nil
+	case len(field) == 1:
+		return field, nil
+	case strings.HasPrefix(field, "0x") || strings.HasPrefix(field, "x"):
+		code, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimPrefix(field, "0"), "x"), 16, 8)
+		if err != nil || code == 0 {
+			return "", fmt.Errorf("invalid LEEF delimiter %q", field)
+		}
+		return string(rune(code)), nil
+	default:
+		return "", fmt.Errorf("invalid LEEF delimiter %q", field)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	semconv "go.opentelemetry.io/collector/semconv/v1.25.0"
+)
+
+func TestParseLEEF(t *testing.T) {
+	cases := []struct {
+		name             string
+		input            string
+		semconvCompliant bool
+		expected         map[string]any
+		expectedErr      string
+	}{
+		{
+			name:  "version 1.0",
+			input: "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=10.50.1.1\tdst=2.10.20.20\tsrcPort=1200\tmsg=mail delivered",
+			expected: map[string]any{
+				"version":         "1.0",
+				"vendor":          "Microsoft",
+				"product":         "MSExchange",
+				"product_version": "4.0 SP1",
+				"event_id":        "15345",
+				"extensions": map[string]any{
+					"src":     "10.50.1.1",
+					"dst":     "2.10.20.20",
+					"srcPort": int64(1200),
+					"msg":     "mail delivered",
+				},
+			},
+		},
+		{
+			name:  "version 2.0 with delimiter",
+			input: "<13>Jan 18 11:07:53 host LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^sev=5^url=https://example.com/?a=b\n",
+			expected: map[string]any{
+				"version":         "2.0",
+				"vendor":          "Lancope",
+				"product":         "StealthWatch",
+				"product_version": "1.0",
+				"event_id":        "41",
+				"extensions": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+					"sev": int64(5),
+					"url": "https://example.com/?a=b",
+				},
+			},
+		},
+		{
+			name:  "version 2.0 with hexadecimal delimiter",
+			input: "LEEF:2.0|Vendor|Product|1.0|41|x7C|src=10.0.1.8|dstPort=not a number",
+			expected: map[string]any{
+				"version":         "2.0",
+				"vendor":          "Vendor",
+				"product":         "Product",
+				"product_version": "1.0",
+				"event_id":        "41",
+				"extensions": map[string]any{
+					"src":     "10.0.1.8",
+					"dstPort": "not a number",
+				},
+			},
+		},
+		{
+			name:  "version 2.0 with default delimiter",
+			input: "LEEF:2.0|Vendor|Product|1.0|41||src=10.0.1.8\tdst=10.0.0.5",
+			expected: map[string]any{
+				"version":         "2.0",
+				"vendor":          "Vendor",
+				"product":         "Product",
+				"product_version": "1.0",
+				"event_id":        "41",
+				"extensions": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+				},
+			},
+		},
+		{
+			name:             "semantic conventions",
+			input:            "LEEF:1.0|Vendor|Product|1.0|41|src=10.0.1.8\tsrcPort=1200\tproto=UDP\tusrName=admin\tcat=flow",
+			semconvCompliant: true,
+			expected: map[string]any{
+				"version":                         "1.0",
+				"vendor":                          "Vendor",
+				"product":                         "Product",
+				"product_version":                 "1.0",
+				"event_id":                        "41",
+				semconv.AttributeSourceAddress:    "10.0.1.8",
+				semconv.AttributeSourcePort:       int64(1200),
+				semconv.AttributeNetworkTransport: "udp",
+				AttributeUserName:                 "admin",
+				"extensions": map[string]any{
+					"cat": "flow",
+				},
+			},
+		},
+		{
+			name:        "missing prefix",
+			input:       "CEF:0|Vendor|Product|1.0|100|name|5|",
+			expectedErr: "not a LEEF message: missing LEEF: prefix",
+		},
+		{
+			name:        "unsupported version",
+			input:       "LEEF:3.0|Vendor|Product|1.0|41|",
+			expectedErr: `unsupported LEEF version "3.0"`,
+		},
+		{
+			name:        "missing delimiter field",
+			input:       "LEEF:2.0|Vendor|Product|1.0|41",
+			expectedErr: "invalid LEEF header: missing delimiter field",
+		},
+		{
+			name:        "invalid delimiter",
+			input:       "LEEF:2.0|Vendor|Product|1.0|41|xZZ|src=10.0.1.8",
+			expectedErr: `invalid LEEF delimiter "xZZ"`,
+		},
+		{
+			name:        "invalid attribute",
+			input:       "LEEF:1.0|Vendor|Product|1.0|41|src=10.0.1.8\tinvalid",
+			expectedErr: `invalid LEEF attribute "invalid"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseLEEF(tc.input, tc.semconvCompliant)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package messaging holds the parts shared by the receivers and exporters of message brokers,
+// such as MQTT and NATS.
+package messaging // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	// FormatTypeJSON is the OTLP JSON encoding of the payloads.
+	FormatTypeJSON = "json"
+	// FormatTypeProto is the OTLP protobuf encoding of the payloads.
+	FormatTypeProto = "proto"
+)
+
+const (
+	SignalLogs    = "logs"
+	SignalMetrics = "metrics"
+	SignalTraces  = "traces"
+)
+
+// loadEncoding returns the (un)marshaler of the encoding extension if set, or the OTLP one of the format.
+// The operation is either "marshal" or "unmarshal".
+func loadEncoding[T any](host component.Host, encoding *component.ID, formatType string, builtins map[string]T, operation string, signal string) (T, error) {
+	if encoding == nil {
+		return builtins[formatType], nil
+	}
+	var t T
+	extension, ok := host.GetExtensions()[*encoding]
+	if !ok {
+		return t, fmt.Errorf("unknown encoding %q", encoding)
+	}
+	t, ok = extension.(T)
+	if !ok {
+		return t, fmt.Errorf("encoding %q can't %s %s", encoding, operation, signal)
+	}
+	return t, nil
+}
+
+// formatName returns the name of the encoding of the payloads reported in the receiver telemetry.
+func formatName(encoding *component.ID, formatType string) string {
+	if encoding != nil {
+		return encoding.String()
+	}
+	return formatType
+}
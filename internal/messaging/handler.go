@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package messaging // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+// Handler decodes the payload of a message and passes it to the next consumer. The payloads failing
+// to decode are reported as permanent errors, they would fail the same way if delivered again.
+type Handler func(ctx context.Context, obsrecv *receiverhelper.ObsReport, payload []byte) error
+
+// NewHandlerFunc returns the payload handler of a signal, decoding the payloads with the encoding
+// extension if set, or else with the OTLP format.
+type NewHandlerFunc func(host component.Host, encoding *component.ID, formatType string) (Handler, error)
+
+var (
+	logsUnmarshalers = map[string]plog.Unmarshaler{
+		FormatTypeJSON:  &plog.JSONUnmarshaler{},
+		FormatTypeProto: &plog.ProtoUnmarshaler{},
+	}
+	metricsUnmarshalers = map[string]pmetric.Unmarshaler{
+		FormatTypeJSON:  &pmetric.JSONUnmarshaler{},
+		FormatTypeProto: &pmetric.ProtoUnmarshaler{},
+	}
+	tracesUnmarshalers = map[string]ptrace.Unmarshaler{
+		FormatTypeJSON:  &ptrace.JSONUnmarshaler{},
+		FormatTypeProto: &ptrace.ProtoUnmarshaler{},
+	}
+)
+
+// NewLogsHandler returns the handlers of the logs payloads, passing them to the next consumer.
+func NewLogsHandler(nextConsumer consumer.Logs) NewHandlerFunc {
+	return func(host component.Host, encoding *component.ID, formatType string) (Handler, error) {
+		unmarshaler, err := loadEncoding(host, encoding, formatType, logsUnmarshalers, "unmarshal", SignalLogs)
+		if err != nil {
+			return nil, err
+		}
+		format := formatName(encoding, formatType)
+		return func(ctx context.Context, obsrecv *receiverhelper.ObsReport, payload []byte) error {
+			ld, err := unmarshaler.UnmarshalLogs(payload)
+			if err != nil {
+				return consumererror.NewPermanent(fmt.Errorf("failed to unmarshal the logs: %w", err))
+			}
+			ctx = obsrecv.StartLogsOp(ctx)
+			err = nextConsumer.ConsumeLogs(ctx, ld)
+			obsrecv.EndLogsOp(ctx, format, ld.LogRecordCount(), err)
+			return err
+		}, nil
+	}
+}
+
+// NewMetricsHandler returns the handlers of the metrics payloads, passing them to the next consumer.
+func NewMetricsHandler(nextConsumer consumer.Metrics) NewHandlerFunc {
+	return func(host component.Host, encoding *component.ID, formatType string) (Handler, error) {
+		unmarshaler, err := loadEncoding(host, encoding, formatType, metricsUnmarshalers, "unmarshal", SignalMetrics)
+		if err != nil {
+			return nil, err
+		}
+		format := formatName(encoding, formatType)
+		return func(ctx context.Context, obsrecv *receiverhelper.ObsReport, payload []byte) error {
+			md, err := unmarshaler.UnmarshalMetrics(payload)
+			if err != nil {
+				return consumererror.NewPermanent(fmt.Errorf("failed to unmarshal the metrics: %w", err))
+			}
+			ctx = obsrecv.StartMetricsOp(ctx)
+			err = nextConsumer.ConsumeMetrics(ctx, md)
+			obsrecv.EndMetricsOp(ctx, format, md.DataPointCount(), err)
+			return err
+		}, nil
+	}
+}
+
+// NewTracesHandler returns the handlers of the traces payloads, passing them to the next consumer.
+func NewTracesHandler(nextConsumer consumer.Traces) NewHandlerFunc {
+	return func(host component.Host, encoding *component.ID, formatType string) (Handler, error) {
+		unmarshaler, err := loadEncoding(host, encoding, formatType, tracesUnmarshalers, "unmarshal", SignalTraces)
+		if err != nil {
+			return nil, err
+		}
+		format := formatName(encoding, formatType)
+		return func(ctx context.Context, obsrecv *receiverhelper.ObsReport, payload []byte) error {
+			td, err := unmarshaler.UnmarshalTraces(payload)
+			if err != nil {
+				return consumererror.NewPermanent(fmt.Errorf("failed to unmarshal the traces: %w", err))
+			}
+			ctx = obsrecv.StartTracesOp(ctx)
+			err = nextConsumer.ConsumeTraces(ctx, td)
+			obsrecv.EndTracesOp(ctx, format, td.SpanCount(), err)
+			return err
+		}, nil
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package messaging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging/messagingtest"
+)
+
+func newTestObsReport(t *testing.T) *receiverhelper.ObsReport {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             component.MustNewID("test"),
+		Transport:              "test",
+		ReceiverCreateSettings: receivertest.NewNopSettings(),
+	})
+	require.NoError(t, err)
+	return obsrecv
+}
+
+func TestLogsHandler(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	handle, err := NewLogsHandler(sink)(componenttest.NewNopHost(), nil, FormatTypeProto)
+	require.NoError(t, err)
+	obsrecv := newTestObsReport(t)
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("door opened")
+	payload, err := (&plog.ProtoMarshaler{}).MarshalLogs(ld)
+	require.NoError(t, err)
+	require.NoError(t, handle(context.Background(), obsrecv, payload))
+	assert.Equal(t, []plog.Logs{ld}, sink.AllLogs())
+
+	// The payloads failing to decode are never consumed.
+	err = handle(context.Background(), obsrecv, []byte("not OTLP"))
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Len(t, sink.AllLogs(), 1)
+}
+
+// nopExtension is an extension which is neither a marshaler nor an unmarshaler.
+type nopExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+func TestHandlerEncodingErrors(t *testing.T) {
+	id := component.MustNewID("encoding")
+	host := messagingtest.NewHost(map[component.ID]component.Component{id: nopExtension{}})
+
+	_, err := NewTracesHandler(consumertest.NewNop())(host, &id, FormatTypeJSON)
+	assert.EqualError(t, err, `encoding "encoding" can't unmarshal traces`)
+
+	missing := component.MustNewIDWithName("encoding", "missing")
+	_, err = NewMetricsHandler(consumertest.NewNop())(host, &missing, FormatTypeJSON)
+	assert.EqualError(t, err, `unknown encoding "encoding/missing"`)
+}
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package messagingtest holds the helpers of the tests of the message broker receivers and exporters.
+package messagingtest // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging/messagingtest"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+type hostWithExtensions struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h hostWithExtensions) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+// NewHost returns a host providing the extensions.
+func NewHost(extensions map[component.ID]component.Component) component.Host {
+	return hostWithExtensions{Host: componenttest.NewNopHost(), extensions: extensions}
+}
+
+// Start starts the component, and shuts it down at the end of the test.
+func Start(t *testing.T, c component.Component, host component.Host) {
+	require.NoError(t, c.Start(context.Background(), host))
+	t.Cleanup(func() {
+		assert.NoError(t, c.Shutdown(context.Background()))
+	})
+}
+
+// Export exports the data once the exporter is connected, the exporters connect in the
+// background and refuse the data until then.
+func Export(t *testing.T, consume func() error) {
+	require.Eventually(t, func() bool { return consume() == nil }, 10*time.Second, 10*time.Millisecond)
+}
+
+// Receive returns the next message published to the broker.
+func Receive[T any](t *testing.T, messages <-chan T) T {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "no message published")
+		var msg T
+		return msg
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package messaging // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// PublishFunc publishes a payload to the topic or subject of the name.
+type PublishFunc func(ctx context.Context, name string, payload []byte) error
+
+var (
+	logsMarshalers = map[string]plog.Marshaler{
+		FormatTypeJSON:  &plog.JSONMarshaler{},
+		FormatTypeProto: &plog.ProtoMarshaler{},
+	}
+	metricsMarshalers = map[string]pmetric.Marshaler{
+		FormatTypeJSON:  &pmetric.JSONMarshaler{},
+		FormatTypeProto: &pmetric.ProtoMarshaler{},
+	}
+	tracesMarshalers = map[string]ptrace.Marshaler{
+		FormatTypeJSON:  &ptrace.JSONMarshaler{},
+		FormatTypeProto: &ptrace.ProtoMarshaler{},
+	}
+)
+
+// LoadLogsMarshaler returns the logs marshaler of the encoding extension if set, or the OTLP one of the format.
+func LoadLogsMarshaler(host component.Host, encoding *component.ID, formatType string) (plog.Marshaler, error) {
+	return loadEncoding(host, encoding, formatType, logsMarshalers, "marshal", SignalLogs)
+}
+
+// LoadMetricsMarshaler returns the metrics marshaler of the encoding extension if set, or the OTLP one of the format.
+func LoadMetricsMarshaler(host component.Host, encoding *component.ID, formatType string) (pmetric.Marshaler, error) {
+	return loadEncoding(host, encoding, formatType, metricsMarshalers, "marshal", SignalMetrics)
+}
+
+// LoadTracesMarshaler returns the traces marshaler of the encoding extension if set, or the OTLP one of the format.
+func LoadTracesMarshaler(host component.Host, encoding *component.ID, formatType string) (ptrace.Marshaler, error) {
+	return loadEncoding(host, encoding, formatType, tracesMarshalers, "marshal", SignalTraces)
+}
+
+// PublishLogs publishes the logs of the resources rendering the same name in a single message.
+func PublishLogs(ctx context.Context, ld plog.Logs, template *Template, marshaler plog.Marshaler, publish PublishFunc) error {
+	groups := make(map[string]plog.Logs)
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rLogs := ld.ResourceLogs().At(i)
+		name := template.Render(rLogs.Resource(), SignalLogs)
+		logs, ok := groups[name]
+		if !ok {
+			logs = plog.NewLogs()
+			groups[name] = logs
+		}
+		rLogs.CopyTo(logs.ResourceLogs().AppendEmpty())
+	}
+
+	var errs error
+	for name, logs := range groups {
+		payload, err := marshaler.MarshalLogs(logs)
+		if err != nil {
+			errs = errors.Join(errs, consumererror.NewPermanent(err))
+			continue
+		}
+		errs = errors.Join(errs, publish(ctx, name, payload))
+	}
+	return errs
+}
+
+// PublishMetrics publishes the metrics of the resources rendering the same name in a single message.
+func PublishMetrics(ctx context.Context, md pmetric.Metrics, template *Template, marshaler pmetric.Marshaler, publish PublishFunc) error {
+	groups := make(map[string]pmetric.Metrics)
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rMetrics := md.ResourceMetrics().At(i)
+		name := template.Render(rMetrics.Resource(), SignalMetrics)
+		metrics, ok := groups[name]
+		if !ok {
+			metrics = pmetric.NewMetrics()
+			groups[name] = metrics
+		}
+		rMetrics.CopyTo(metrics.ResourceMetrics().AppendEmpty())
+	}
+
+	var errs error
+	for name, metrics := range groups {
+		payload, err := marshaler.MarshalMetrics(metrics)
+		if err != nil {
+			errs = errors.Join(errs, consumererror.NewPermanent(err))
+			continue
+		}
+		errs = errors.Join(errs, publish(ctx, name, payload))
+	}
+	return errs
+}
+
+// PublishTraces publishes the traces of the resources rendering the same name in a single message.
+func PublishTraces(ctx context.Context, td ptrace.Traces, template *Template, marshaler ptrace.Marshaler, publish PublishFunc) error {
+	groups := make(map[string]ptrace.Traces)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rSpans := td.ResourceSpans().At(i)
+		name := template.Render(rSpans.Resource(), SignalTraces)
+		traces, ok := groups[name]
+		if !ok {
+			traces = ptrace.NewTraces()
+			groups[name] = traces
+		}
+		rSpans.CopyTo(traces.ResourceSpans().AppendEmpty())
+	}
+
+	var errs error
+	for name, traces := range groups {
+		payload, err := marshaler.MarshalTraces(traces)
+		if err != nil {
+			errs = errors.Join(errs, consumererror.NewPermanent(err))
+			continue
+		}
+		errs = errors.Join(errs, publish(ctx, name, payload))
+	}
+	return errs
+}
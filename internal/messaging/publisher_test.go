@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestPublishTraces(t *testing.T) {
+	template, err := ParseTemplate("services/{resource:service.name}/{signal}", testSyntax)
+	require.NoError(t, err)
+	marshaler, err := LoadTracesMarshaler(componenttest.NewNopHost(), nil, FormatTypeJSON)
+	require.NoError(t, err)
+
+	// The resources rendering the same name are published in a single message.
+	td := ptrace.NewTraces()
+	for _, service := range []string{"cart", "checkout", "cart"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(service)
+	}
+	published := map[string]ptrace.Traces{}
+	require.NoError(t, PublishTraces(context.Background(), td, template, marshaler, func(_ context.Context, name string, payload []byte) error {
+		traces, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(payload)
+		require.NoError(t, err)
+		published[name] = traces
+		return nil
+	}))
+	require.Len(t, published, 2)
+	assert.Equal(t, 2, published["services/cart/traces"].ResourceSpans().Len())
+	assert.Equal(t, 1, published["services/checkout/traces"].ResourceSpans().Len())
+
+	// The failures to publish are retryable.
+	err = PublishTraces(context.Background(), td, template, marshaler, func(context.Context, string, []byte) error {
+		return errors.New("not connected")
+	})
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package messaging // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const (
+	// missingAttributeValue replaces resource attributes missing from the resource in names.
+	missingAttributeValue = "unknown"
+
+	resourceAttributePrefix = "resource:"
+)
+
+// Syntax describes the names of the broker messages are published to, such as MQTT topics or NATS subjects.
+type Syntax struct {
+	// Kind is the kind of the names in the errors, e.g. "topic".
+	Kind string
+	// ValidateLiteral returns an error if the literal text of a template can't contain the character,
+	// such as a wildcard.
+	ValidateLiteral func(c byte) error
+	// Sanitize prevents attribute values from changing the structure of the names, such as
+	// adding levels or wildcards.
+	Sanitize func(value string) string
+}
+
+type templatePartKind int
+
+const (
+	templatePartLiteral templatePartKind = iota
+	templatePartResourceAttribute
+	templatePartSignal
+)
+
+type templatePart struct {
+	kind templatePartKind
+	// value is the literal text or the resource attribute name.
+	value string
+}
+
+// Template renders the names of the messages. It supports the following placeholders:
+// - {resource:<name>} for the value of a resource attribute.
+// - {signal} for the signal type: traces, metrics or logs.
+type Template struct {
+	parts    []templatePart
+	sanitize func(value string) string
+}
+
+// ParseTemplate parses the template of the names, checking its literal text with the syntax.
+func ParseTemplate(template string, syntax Syntax) (*Template, error) {
+	if template == "" {
+		return nil, fmt.Errorf("%s must be specified", syntax.Kind)
+	}
+	t := &Template{sanitize: syntax.Sanitize}
+	var literal strings.Builder
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			t.parts = append(t.parts, templatePart{kind: templatePartLiteral, value: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if c != '{' {
+			if err := syntax.ValidateLiteral(c); err != nil {
+				return nil, err
+			}
+			literal.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(template[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated placeholder in %s at position %d", syntax.Kind, i)
+		}
+		placeholder := template[i+1 : i+end]
+		i += end
+		flushLiteral()
+		switch {
+		case placeholder == "signal":
+			t.parts = append(t.parts, templatePart{kind: templatePartSignal})
+		case strings.HasPrefix(placeholder, resourceAttributePrefix) && len(placeholder) > len(resourceAttributePrefix):
+			t.parts = append(t.parts, templatePart{kind: templatePartResourceAttribute, value: strings.TrimPrefix(placeholder, resourceAttributePrefix)})
+		default:
+			return nil, fmt.Errorf("unsupported placeholder {%s} in %s", placeholder, syntax.Kind)
+		}
+	}
+	flushLiteral()
+	return t, nil
+}
+
+// Render returns the name for the given resource and signal type.
+func (t *Template) Render(resource pcommon.Resource, signal string) string {
+	var sb strings.Builder
+	for _, part := range t.parts {
+		switch part.kind {
+		case templatePartLiteral:
+			sb.WriteString(part.value)
+		case templatePartSignal:
+			sb.WriteString(signal)
+		case templatePartResourceAttribute:
+			value := missingAttributeValue
+			if v, ok := resource.Attributes().Get(part.value); ok {
+				value = v.AsString()
+			}
+			sb.WriteString(t.sanitize(value))
+		}
+	}
+	return sb.String()
+}
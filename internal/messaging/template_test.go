@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package messaging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+var testSyntax = Syntax{
+	Kind: "path",
+	ValidateLiteral: func(c byte) error {
+		if c == '*' {
+			return fmt.Errorf("path can't contain the wildcard %q", c)
+		}
+		return nil
+	},
+	Sanitize: func(value string) string {
+		return strings.ReplaceAll(value, "/", "_")
+	},
+}
+
+func TestTemplateRender(t *testing.T) {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "checkout/v2")
+	resource.Attributes().PutInt("device.id", 42)
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "otlp/{signal}", expected: "otlp/metrics"},
+		{template: "{resource:service.name}/{resource:device.id}", expected: "checkout_v2/42"},
+		{template: "devices/{resource:device.name}", expected: "devices/unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			template, err := ParseTemplate(tt.template, testSyntax)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, template.Render(resource, SignalMetrics))
+		})
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "", expected: "path must be specified"},
+		{template: "otlp/*", expected: "path can't contain the wildcard '*'"},
+		{template: "otlp/{signal", expected: "unterminated placeholder in path at position 5"},
+		{template: "otlp/{resource:}", expected: "unsupported placeholder {resource:} in path"},
+		{template: "otlp/{time}", expected: "unsupported placeholder {time} in path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := ParseTemplate(tt.template, testSyntax)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metricnaming normalizes and sanitizes the metric names and tags sent to the
+// time series databases which restrict their characters, such as Graphite and OpenTSDB.
+package metricnaming // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/metricnaming"
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+)
+
+// Normalization is the normalization applied to the metric names and tag keys.
+type Normalization string
+
+const (
+	// NormalizationNone keeps the metric names and tag keys as they are.
+	NormalizationNone Normalization = "none"
+	// NormalizationPrometheus follows the Prometheus naming conventions, as the Prometheus
+	// exporters do.
+	NormalizationPrometheus Normalization = "prometheus"
+)
+
+// Config configures the normalization of the metric names and tag keys.
+type Config struct {
+	// Normalization is the normalization applied to the metric names and tag keys before
+	// they are sanitized. Either "none", the default, or "prometheus".
+	Normalization Normalization `mapstructure:"normalization"`
+
+	// AddMetricSuffixes adds the type and unit suffixes to the metric names with the
+	// "prometheus" normalization. It defaults to true.
+	AddMetricSuffixes bool `mapstructure:"add_metric_suffixes"`
+}
+
+// NewDefaultConfig returns the default naming configuration, keeping the metric names and
+// tag keys as they are.
+func NewDefaultConfig() Config {
+	return Config{
+		Normalization:     NormalizationNone,
+		AddMetricSuffixes: true,
+	}
+}
+
+// Validate checks the normalization is supported.
+func (c Config) Validate() error {
+	switch c.Normalization {
+	case "", NormalizationNone, NormalizationPrometheus:
+		return nil
+	default:
+		return fmt.Errorf("unsupported normalization %q, must be %q or %q", c.Normalization, NormalizationNone, NormalizationPrometheus)
+	}
+}
+
+// Rules are the functions replacing the characters a backend doesn't allow in the metric
+// names, tag keys and tag values.
+type Rules struct {
+	Name     func(string) string
+	TagKey   func(string) string
+	TagValue func(string) string
+}
+
+// ReplaceFunc returns a function replacing the runes which are not allowed with the
+// replacement rune.
+func ReplaceFunc(allowed func(r rune) bool, replacement rune) func(string) string {
+	return func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if allowed(r) {
+				return r
+			}
+			return replacement
+		}, s)
+	}
+}
+
+// Sanitizer normalizes the metric names and tag keys according to its configuration, and
+// then sanitizes them with the rules of the backend.
+type Sanitizer struct {
+	config Config
+	rules  Rules
+}
+
+// NewSanitizer returns a sanitizer applying the normalization of the configuration and the
+// rules of a backend.
+func NewSanitizer(config Config, rules Rules) *Sanitizer {
+	return &Sanitizer{config: config, rules: rules}
+}
+
+// MetricName returns the normalized and sanitized name of a metric.
+func (s *Sanitizer) MetricName(metric pmetric.Metric) string {
+	name := metric.Name()
+	if s.config.Normalization == NormalizationPrometheus {
+		name = prometheus.BuildCompliantName(metric, "", s.config.AddMetricSuffixes)
+	}
+	return s.rules.Name(name)
+}
+
+// TagKey returns the normalized and sanitized tag key.
+func (s *Sanitizer) TagKey(key string) string {
+	if s.config.Normalization == NormalizationPrometheus {
+		key = prometheus.NormalizeLabel(key)
+	}
+	return s.rules.TagKey(key)
+}
+
+// TagValue returns the sanitized tag value.
+func (s *Sanitizer) TagValue(value string) string {
+	return s.rules.TagValue(value)
+}
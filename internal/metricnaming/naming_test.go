@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metricnaming
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+var testRules = Rules{
+	Name:     ReplaceFunc(func(r rune) bool { return r != ' ' }, '_'),
+	TagKey:   ReplaceFunc(func(r rune) bool { return r != '=' }, '_'),
+	TagValue: ReplaceFunc(unicode.IsPrint, '?'),
+}
+
+func newMetric(name string, unit string) pmetric.Metric {
+	metric := pmetric.NewMetric()
+	metric.SetName(name)
+	metric.SetUnit(unit)
+	sum := metric.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	return metric
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, NewDefaultConfig().Validate())
+	assert.NoError(t, Config{Normalization: NormalizationPrometheus}.Validate())
+	assert.EqualError(t, Config{Normalization: "graphite"}.Validate(), `unsupported normalization "graphite", must be "none" or "prometheus"`)
+}
+
+func TestSanitizer(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		wantName  string
+		wantKey   string
+		wantValue string
+	}{
+		{
+			name:      "none",
+			config:    NewDefaultConfig(),
+			wantName:  "http.server.request_duration",
+			wantKey:   "http.status_code",
+			wantValue: "2?0",
+		},
+		{
+			name:      "prometheus",
+			config:    Config{Normalization: NormalizationPrometheus, AddMetricSuffixes: true},
+			wantName:  "http_server_request_duration_seconds_total",
+			wantKey:   "http_status_code",
+			wantValue: "2?0",
+		},
+		{
+			name:      "prometheus_without_suffixes",
+			config:    Config{Normalization: NormalizationPrometheus},
+			wantName:  "http_server_request_duration",
+			wantKey:   "http_status_code",
+			wantValue: "2?0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSanitizer(tt.config, testRules)
+			assert.Equal(t, tt.wantName, s.MetricName(newMetric("http.server.request duration", "s")))
+			assert.Equal(t, tt.wantKey, s.TagKey("http.status=code"))
+			assert.Equal(t, tt.wantValue, s.TagValue("2\n0"))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mqtt holds the settings of the MQTT clients shared by the MQTT receiver and exporter.
+package mqtt // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const (
+	defaultBroker         = "tcp://localhost:1883"
+	defaultKeepAlive      = 30 * time.Second
+	defaultConnectTimeout = 10 * time.Second
+
+	// disconnectQuiesce is the time left to the client to complete its work when it disconnects, in milliseconds.
+	disconnectQuiesce = 250
+)
+
+// The schemes of the broker URLs, the TLS settings only apply to the TLS schemes.
+var (
+	plainSchemes = map[string]bool{"tcp": true, "mqtt": true, "ws": true, "unix": true}
+	tlsSchemes   = map[string]bool{"ssl": true, "tls": true, "mqtts": true, "mqtt+ssl": true, "tcps": true, "wss": true}
+)
+
+// ClientConfig configures the connection of a client to the MQTT brokers.
+type ClientConfig struct {
+	// Brokers are the URLs of the brokers, such as tcp://localhost:1883 or ssl://localhost:8883.
+	// The client connects to the first available broker.
+	Brokers []string `mapstructure:"brokers"`
+
+	// ClientID identifies the client to the brokers, a random ID is generated if it is empty.
+	// The brokers disconnect the client whose ID is already used by a new client.
+	ClientID string `mapstructure:"client_id"`
+
+	// Username and Password authenticate the client.
+	Username string              `mapstructure:"username"`
+	Password configopaque.String `mapstructure:"password"`
+
+	// TLS configures the connections to the brokers with a TLS scheme: ssl, tls, mqtts, tcps or wss.
+	TLS configtls.ClientConfig `mapstructure:"tls"`
+
+	// KeepAlive is the interval at which the client pings the broker when idle.
+	KeepAlive time.Duration `mapstructure:"keep_alive"`
+
+	// ConnectTimeout is the timeout of the connection attempts.
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
+
+	// CleanSession starts a new session on each connection. Otherwise the brokers resume the
+	// session of the client ID, with its subscriptions and the messages queued while disconnected.
+	CleanSession bool `mapstructure:"clean_session"`
+}
+
+// NewDefaultClientConfig returns the default settings of the MQTT clients.
+func NewDefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		Brokers:        []string{defaultBroker},
+		KeepAlive:      defaultKeepAlive,
+		ConnectTimeout: defaultConnectTimeout,
+		CleanSession:   true,
+	}
+}
+
+// Validate checks the client settings are valid.
+func (cfg *ClientConfig) Validate() error {
+	var errs error
+	if len(cfg.Brokers) == 0 {
+		errs = errors.Join(errs, errors.New("at least one broker must be specified"))
+	}
+	for _, broker := range cfg.Brokers {
+		u, err := url.Parse(broker)
+		switch {
+		case err != nil:
+			errs = errors.Join(errs, fmt.Errorf("invalid broker %q: %w", broker, err))
+		case !plainSchemes[u.Scheme] && !tlsSchemes[u.Scheme]:
+			errs = errors.Join(errs, fmt.Errorf("broker %q has unsupported scheme %q", broker, u.Scheme))
+		}
+	}
+	if !cfg.CleanSession && cfg.ClientID == "" {
+		errs = errors.Join(errs, errors.New("client_id must be specified to resume the sessions when clean_session is false"))
+	}
+	if cfg.KeepAlive < 0 {
+		errs = errors.Join(errs, errors.New("keep_alive can't be negative"))
+	}
+	if cfg.ConnectTimeout < 0 {
+		errs = errors.Join(errs, errors.New("connect_timeout can't be negative"))
+	}
+	return errs
+}
+
+// NewClientOptions returns the options of a paho client connecting with the settings. The
+// client reconnects automatically once connected, see Connection for the first connection.
+func (cfg *ClientConfig) NewClientOptions(ctx context.Context) (*paho.ClientOptions, error) {
+	opts := paho.NewClientOptions()
+	for _, broker := range cfg.Brokers {
+		opts.AddBroker(broker)
+	}
+	clientID := cfg.ClientID
+	if clientID == "" {
+		var err error
+		if clientID, err = randomClientID(); err != nil {
+			return nil, err
+		}
+	}
+	opts.SetClientID(clientID).
+		SetUsername(cfg.Username).
+		SetPassword(string(cfg.Password)).
+		SetKeepAlive(cfg.KeepAlive).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetCleanSession(cfg.CleanSession).
+		SetAutoReconnect(true)
+
+	tlsConfig, err := cfg.TLS.LoadTLSConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS settings: %w", err)
+	}
+	opts.SetTLSConfig(tlsConfig)
+	return opts, nil
+}
+
+// Disconnect disconnects the client, leaving it a short time to complete its work.
+func Disconnect(client paho.Client) {
+	client.Disconnect(disconnectQuiesce)
+}
+
+// randomClientID returns a client ID unlikely to be used by other clients, MQTT 3.1 brokers
+// accept client IDs of at most 23 characters.
+func randomClientID() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate the client ID: %w", err)
+	}
+	return "otelcol-" + hex.EncodeToString(b), nil
+}
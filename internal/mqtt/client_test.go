@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt/mqtttest"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*ClientConfig)
+		wantErr   string
+	}{
+		{
+			name:      "default",
+			configure: func(*ClientConfig) {},
+		},
+		{
+			name: "tls and websocket brokers",
+			configure: func(cfg *ClientConfig) {
+				cfg.Brokers = []string{"ssl://broker:8883", "wss://broker/mqtt"}
+			},
+		},
+		{
+			name: "no broker",
+			configure: func(cfg *ClientConfig) {
+				cfg.Brokers = nil
+			},
+			wantErr: "at least one broker must be specified",
+		},
+		{
+			name: "unsupported scheme",
+			configure: func(cfg *ClientConfig) {
+				cfg.Brokers = []string{"http://broker:1883"}
+			},
+			wantErr: `broker "http://broker:1883" has unsupported scheme "http"`,
+		},
+		{
+			name: "persistent session without client ID",
+			configure: func(cfg *ClientConfig) {
+				cfg.CleanSession = false
+			},
+			wantErr: "client_id must be specified",
+		},
+		{
+			name: "negative durations",
+			configure: func(cfg *ClientConfig) {
+				cfg.KeepAlive = -time.Second
+				cfg.ConnectTimeout = -time.Second
+			},
+			wantErr: "keep_alive can't be negative\nconnect_timeout can't be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultClientConfig()
+			tt.configure(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewClientOptionsClientID(t *testing.T) {
+	cfg := NewDefaultClientConfig()
+	opts, err := cfg.NewClientOptions(context.Background())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(opts.ClientID, "otelcol-"))
+	assert.LessOrEqual(t, len(opts.ClientID), 23)
+
+	other, err := cfg.NewClientOptions(context.Background())
+	require.NoError(t, err)
+	assert.NotEqual(t, opts.ClientID, other.ClientID)
+
+	cfg.ClientID = "gateway-1"
+	opts, err = cfg.NewClientOptions(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "gateway-1", opts.ClientID)
+}
+
+// connect connects a client with the settings, it returns the error of the connection attempt.
+func connect(t *testing.T, cfg ClientConfig) error {
+	opts, err := cfg.NewClientOptions(context.Background())
+	require.NoError(t, err)
+	client := paho.NewClient(opts)
+	token := client.Connect()
+	require.True(t, token.WaitTimeout(10*time.Second))
+	if token.Error() == nil {
+		Disconnect(client)
+	}
+	return token.Error()
+}
+
+func TestConnectAuthentication(t *testing.T) {
+	broker := mqtttest.NewBroker(t, mqtttest.WithUser("device", "secret"))
+	cfg := NewDefaultClientConfig()
+	cfg.Brokers = []string{broker.Endpoint}
+
+	assert.Error(t, connect(t, cfg))
+	cfg.Username, cfg.Password = "device", "wrong"
+	assert.Error(t, connect(t, cfg))
+	cfg.Password = "secret"
+	assert.NoError(t, connect(t, cfg))
+}
+
+func TestConnectTLS(t *testing.T) {
+	caFile, serverCert := generateCertificate(t)
+	broker := mqtttest.NewBroker(t, mqtttest.WithTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		MinVersion:   tls.VersionTLS12,
+	}))
+	assert.True(t, strings.HasPrefix(broker.Endpoint, "ssl://"))
+	cfg := NewDefaultClientConfig()
+	cfg.Brokers = []string{broker.Endpoint}
+
+	// The certificate of the broker isn't trusted without its CA.
+	assert.Error(t, connect(t, cfg))
+	cfg.TLS.CAFile = caFile
+	assert.NoError(t, connect(t, cfg))
+}
+
+// generateCertificate generates a self-signed certificate of 127.0.0.1, it returns the path of
+// the certificate and the certificate with its key.
+func generateCertificate(t *testing.T) (string, tls.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "broker"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return caFile, tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+
+import (
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.uber.org/zap"
+)
+
+// defaultConnectRetryInterval is the interval between the attempts to connect to the brokers.
+const defaultConnectRetryInterval = 10 * time.Second
+
+// Connection connects a client to the brokers in the background, so the components start while
+// the brokers are unavailable. Unlike the connection retries of paho, the attempts stop as soon as
+// the connection is closed.
+type Connection struct {
+	paho.Client
+
+	logger        *zap.Logger
+	retryInterval time.Duration
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// NewConnection returns a connection of the client, the client must not be connected.
+func NewConnection(client paho.Client, logger *zap.Logger) *Connection {
+	return &Connection{
+		Client:        client,
+		logger:        logger,
+		retryInterval: defaultConnectRetryInterval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start starts connecting the client, it retries until the client is connected or the
+// connection is closed. It must be called once, before Close.
+func (c *Connection) Start() {
+	go c.connect()
+}
+
+func (c *Connection) connect() {
+	defer close(c.done)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-timer.C:
+		}
+		token := c.Client.Connect()
+		token.Wait()
+		if token.Error() == nil {
+			return
+		}
+		c.logger.Warn("Failed to connect to the MQTT broker, retrying",
+			zap.Error(token.Error()), zap.Duration("interval", c.retryInterval))
+		timer.Reset(c.retryInterval)
+	}
+}
+
+// Close stops connecting the client and disconnects it. It waits for the connection attempt in
+// progress, which completes within the connect timeout.
+func (c *Connection) Close() {
+	close(c.stop)
+	<-c.done
+	Disconnect(c.Client)
+}
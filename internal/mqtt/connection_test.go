@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt/mqtttest"
+)
+
+func newConnection(t *testing.T, broker string) (*Connection, *observer.ObservedLogs) {
+	cfg := NewDefaultClientConfig()
+	cfg.Brokers = []string{broker}
+	opts, err := cfg.NewClientOptions(context.Background())
+	require.NoError(t, err)
+	core, logs := observer.New(zap.WarnLevel)
+	return NewConnection(paho.NewClient(opts), zap.New(core)), logs
+}
+
+func TestConnection(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	conn, logs := newConnection(t, broker.Endpoint)
+	conn.Start()
+	assert.Eventually(t, conn.IsConnected, 10*time.Second, 10*time.Millisecond)
+	conn.Close()
+	assert.False(t, conn.IsConnected())
+	assert.Zero(t, logs.Len())
+}
+
+func TestConnectionRetry(t *testing.T) {
+	// The port of the closed listener refuses the connections.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	conn, logs := newConnection(t, "tcp://"+ln.Addr().String())
+	conn.retryInterval = 10 * time.Millisecond
+	conn.Start()
+	assert.Eventually(t, func() bool {
+		return logs.FilterMessage("Failed to connect to the MQTT broker, retrying").Len() >= 2
+	}, 10*time.Second, 10*time.Millisecond)
+	conn.Close()
+	assert.False(t, conn.IsConnected())
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mqtttest runs an embedded MQTT broker for the tests of the MQTT components.
+package mqtttest // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt/mqtttest"
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/require"
+)
+
+// Broker is an embedded MQTT broker listening on a random local port.
+type Broker struct {
+	// Endpoint is the URL of the broker, with the ssl scheme if it uses TLS.
+	Endpoint string
+
+	server *mochi.Server
+}
+
+// Message is a message published to the broker.
+type Message struct {
+	Topic   string
+	Payload []byte
+	QoS     byte
+	Retain  bool
+}
+
+type brokerConfig struct {
+	tlsConfig *tls.Config
+	username  string
+	password  string
+}
+
+// BrokerOption configures the broker.
+type BrokerOption func(*brokerConfig)
+
+// WithTLS makes the broker accept TLS connections only.
+func WithTLS(tlsConfig *tls.Config) BrokerOption {
+	return func(cfg *brokerConfig) {
+		cfg.tlsConfig = tlsConfig
+	}
+}
+
+// WithUser makes the broker accept the clients authenticated with the username and password only.
+func WithUser(username, password string) BrokerOption {
+	return func(cfg *brokerConfig) {
+		cfg.username = username
+		cfg.password = password
+	}
+}
+
+// NewBroker starts a broker, it is closed at the end of the test.
+func NewBroker(t testing.TB, opts ...BrokerOption) *Broker {
+	var cfg brokerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	server := mochi.New(&mochi.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if cfg.username != "" {
+		require.NoError(t, server.AddHook(new(auth.Hook), &auth.Options{
+			Ledger: &auth.Ledger{
+				Auth: auth.AuthRules{
+					{Username: auth.RString(cfg.username), Password: auth.RString(cfg.password), Allow: true},
+				},
+			},
+		}))
+	} else {
+		require.NoError(t, server.AddHook(new(auth.AllowHook), nil))
+	}
+
+	listener := listeners.NewTCP(listeners.Config{ID: "test", Address: "127.0.0.1:0", TLSConfig: cfg.tlsConfig})
+	require.NoError(t, server.AddListener(listener))
+	require.NoError(t, server.Serve())
+	t.Cleanup(func() {
+		require.NoError(t, server.Close())
+	})
+
+	scheme := "tcp"
+	if cfg.tlsConfig != nil {
+		scheme = "ssl"
+	}
+	return &Broker{
+		Endpoint: scheme + "://" + listener.Address(),
+		server:   server,
+	}
+}
+
+// Publish publishes a message to the subscribers of its topic.
+func (b *Broker) Publish(topic string, payload []byte, qos byte) error {
+	return b.server.Publish(topic, payload, false, qos)
+}
+
+// Subscribe returns the messages published to the topics matching the filter.
+func (b *Broker) Subscribe(t testing.TB, filter string) <-chan Message {
+	messages := make(chan Message, 100)
+	require.NoError(t, b.server.Subscribe(filter, 1, func(_ *mochi.Client, _ packets.Subscription, pk packets.Packet) {
+		messages <- Message{
+			Topic:   pk.TopicName,
+			Payload: pk.Payload,
+			QoS:     pk.FixedHeader.Qos,
+			Retain:  pk.FixedHeader.Retain,
+		}
+	}))
+	return messages
+}
+
+// WaitForSubscription waits until a connected client, other than the inline client of the
+// tests, subscribes to the filter.
+func (b *Broker) WaitForSubscription(t testing.TB, filter string) {
+	require.Eventually(t, func() bool {
+		for _, cl := range b.server.Clients.GetAll() {
+			if cl.ID == mochi.InlineClientId || cl.Closed() {
+				continue
+			}
+			if _, ok := cl.State.Subscriptions.Get(filter); ok {
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+// DisconnectClients closes the connections of the clients, as a broker restart would.
+func (b *Broker) DisconnectClients(t testing.TB) {
+	var clients []*mochi.Client
+	for _, cl := range b.server.Clients.GetAll() {
+		if cl.ID != mochi.InlineClientId {
+			cl.Stop(errors.New("disconnected by the test broker"))
+			clients = append(clients, cl)
+		}
+	}
+	require.Eventually(t, func() bool {
+		for _, cl := range clients {
+			if !cl.Closed() {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+}
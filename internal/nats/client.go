@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats holds the settings of the NATS connections shared by the NATS receiver and exporter.
+package nats // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/nats"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultServer         = "nats://localhost:4222"
+	defaultConnectTimeout = 5 * time.Second
+	defaultReconnectWait  = 2 * time.Second
+)
+
+// supportedSchemes are the schemes of the server URLs, the tls and wss schemes require TLS.
+var supportedSchemes = map[string]bool{"nats": true, "tls": true, "ws": true, "wss": true}
+
+// ClientConfig configures the connection to the NATS servers.
+type ClientConfig struct {
+	// Servers are the URLs of the servers of the cluster.
+	Servers []string `mapstructure:"servers"`
+
+	// Name is the name of the connection reported to the servers.
+	Name string `mapstructure:"name"`
+
+	// Auth configures the authentication of the connection.
+	Auth AuthConfig `mapstructure:"auth"`
+
+	// TLS configures the TLS connections, used with the tls and wss servers and the
+	// servers requiring TLS.
+	TLS configtls.ClientConfig `mapstructure:"tls"`
+
+	// ConnectTimeout is the timeout of the connection attempts.
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
+
+	// ReconnectWait is the time waited before reconnecting to a server.
+	ReconnectWait time.Duration `mapstructure:"reconnect_wait"`
+}
+
+// AuthConfig configures the authentication of the connection, at most one method can be used.
+type AuthConfig struct {
+	// Username and Password authenticate the connection with a user.
+	Username string              `mapstructure:"username"`
+	Password configopaque.String `mapstructure:"password"`
+
+	// Token authenticates the connection with a token.
+	Token configopaque.String `mapstructure:"token"`
+
+	// NKeyFile is the path of the file holding the seed of an NKey authenticating the connection.
+	NKeyFile string `mapstructure:"nkey_file"`
+
+	// CredentialsFile is the path of the credentials file holding the user JWT and NKey seed
+	// authenticating the connection, for the decentralized authentication.
+	CredentialsFile string `mapstructure:"credentials_file"`
+}
+
+// NewDefaultClientConfig returns the default settings of the connection.
+func NewDefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		Servers:        []string{defaultServer},
+		ConnectTimeout: defaultConnectTimeout,
+		ReconnectWait:  defaultReconnectWait,
+	}
+}
+
+// Validate checks the connection settings are valid.
+func (cfg *ClientConfig) Validate() error {
+	var errs error
+	if len(cfg.Servers) == 0 {
+		errs = errors.Join(errs, errors.New("at least one server must be specified"))
+	}
+	for _, server := range cfg.Servers {
+		u, err := url.Parse(server)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("invalid server %q: %w", server, err))
+			continue
+		}
+		if !supportedSchemes[u.Scheme] {
+			errs = errors.Join(errs, fmt.Errorf("server %q has unsupported scheme %q", server, u.Scheme))
+		}
+	}
+	if err := cfg.Auth.validate(); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.ConnectTimeout < 0 {
+		errs = errors.Join(errs, errors.New("connect_timeout can't be negative"))
+	}
+	if cfg.ReconnectWait < 0 {
+		errs = errors.Join(errs, errors.New("reconnect_wait can't be negative"))
+	}
+	return errs
+}
+
+func (cfg *AuthConfig) validate() error {
+	var methods []string
+	if cfg.Username != "" {
+		methods = append(methods, "username")
+	} else if cfg.Password != "" {
+		return errors.New("auth password requires a username")
+	}
+	if cfg.Token != "" {
+		methods = append(methods, "token")
+	}
+	if cfg.NKeyFile != "" {
+		methods = append(methods, "nkey_file")
+	}
+	if cfg.CredentialsFile != "" {
+		methods = append(methods, "credentials_file")
+	}
+	if len(methods) > 1 {
+		return fmt.Errorf("only one auth method can be used, got %s", strings.Join(methods, ", "))
+	}
+	return nil
+}
+
+// Connect returns a connection to the servers. The connection is established in the background,
+// so it's returned while the servers are unavailable, and reconnects until it's closed.
+func (cfg *ClientConfig) Connect(ctx context.Context, logger *zap.Logger) (*nats.Conn, error) {
+	opts := []nats.Option{
+		nats.Name(cfg.Name),
+		nats.Timeout(cfg.ConnectTimeout),
+		nats.ReconnectWait(cfg.ReconnectWait),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+		nats.ConnectHandler(func(conn *nats.Conn) {
+			logger.Info("Connected to the NATS server", zap.String("server", conn.ConnectedUrlRedacted()))
+		}),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warn("Lost the connection to the NATS server", zap.Error(err))
+			}
+		}),
+		nats.ErrorHandler(func(_ *nats.Conn, sub *nats.Subscription, err error) {
+			fields := []zap.Field{zap.Error(err)}
+			if sub != nil {
+				fields = append(fields, zap.String("subject", sub.Subject))
+			}
+			logger.Error("NATS connection error", fields...)
+		}),
+	}
+	if cfg.usesTLS() {
+		tlsConfig, err := cfg.TLS.LoadTLSConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the TLS settings: %w", err)
+		}
+		// The TLS settings are used with the tls and wss servers, and the servers requiring TLS.
+		opts = append(opts, func(o *nats.Options) error {
+			o.TLSConfig = tlsConfig
+			return nil
+		})
+	}
+	switch {
+	case cfg.Auth.Username != "":
+		opts = append(opts, nats.UserInfo(cfg.Auth.Username, string(cfg.Auth.Password)))
+	case cfg.Auth.Token != "":
+		opts = append(opts, nats.Token(string(cfg.Auth.Token)))
+	case cfg.Auth.NKeyFile != "":
+		opt, err := nats.NkeyOptionFromSeed(cfg.Auth.NKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the NKey: %w", err)
+		}
+		opts = append(opts, opt)
+	case cfg.Auth.CredentialsFile != "":
+		opts = append(opts, nats.UserCredentials(cfg.Auth.CredentialsFile))
+	}
+	return nats.Connect(strings.Join(cfg.Servers, ","), opts...)
+}
+
+// usesTLS tells whether the TLS settings are configured, or a server URL requires TLS.
+func (cfg *ClientConfig) usesTLS() bool {
+	if !reflect.DeepEqual(cfg.TLS, configtls.ClientConfig{}) {
+		return true
+	}
+	for _, server := range cfg.Servers {
+		if strings.HasPrefix(server, "tls://") || strings.HasPrefix(server, "wss://") {
+			return true
+		}
+	}
+	return false
+}
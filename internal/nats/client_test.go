@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/nats/natstest"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*ClientConfig)
+		wantErr   string
+	}{
+		{
+			name:      "default",
+			configure: func(*ClientConfig) {},
+		},
+		{
+			name: "cluster",
+			configure: func(cfg *ClientConfig) {
+				cfg.Servers = []string{"tls://nats-1:4222", "tls://nats-2:4222"}
+				cfg.Auth.CredentialsFile = "collector.creds"
+			},
+		},
+		{
+			name: "no server",
+			configure: func(cfg *ClientConfig) {
+				cfg.Servers = nil
+			},
+			wantErr: "at least one server must be specified",
+		},
+		{
+			name: "unsupported scheme",
+			configure: func(cfg *ClientConfig) {
+				cfg.Servers = []string{"http://nats:4222"}
+			},
+			wantErr: `server "http://nats:4222" has unsupported scheme "http"`,
+		},
+		{
+			name: "password without username",
+			configure: func(cfg *ClientConfig) {
+				cfg.Auth.Password = "secret"
+			},
+			wantErr: "auth password requires a username",
+		},
+		{
+			name: "several auth methods",
+			configure: func(cfg *ClientConfig) {
+				cfg.Auth.Username = "collector"
+				cfg.Auth.Token = "token"
+				cfg.Auth.NKeyFile = "collector.nk"
+			},
+			wantErr: "only one auth method can be used, got username, token, nkey_file",
+		},
+		{
+			name: "negative durations",
+			configure: func(cfg *ClientConfig) {
+				cfg.ConnectTimeout = -time.Second
+				cfg.ReconnectWait = -time.Second
+			},
+			wantErr: "connect_timeout can't be negative\nreconnect_wait can't be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultClientConfig()
+			tt.configure(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func connect(t *testing.T, cfg ClientConfig) (*nats.Conn, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.WarnLevel)
+	conn, err := cfg.Connect(context.Background(), zap.New(core))
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	return conn, logs
+}
+
+func TestConnectAuthentication(t *testing.T) {
+	tests := []struct {
+		name      string
+		option    natstest.ServerOption
+		configure func(*AuthConfig)
+	}{
+		{
+			name:   "user",
+			option: natstest.WithUser("collector", "secret"),
+			configure: func(cfg *AuthConfig) {
+				cfg.Username, cfg.Password = "collector", "secret"
+			},
+		},
+		{
+			name:   "token",
+			option: natstest.WithToken("s3cr3t"),
+			configure: func(cfg *AuthConfig) {
+				cfg.Token = "s3cr3t"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := natstest.NewServer(t, tt.option)
+			cfg := NewDefaultClientConfig()
+			cfg.Servers = []string{server.URL}
+			cfg.ReconnectWait = 10 * time.Millisecond
+
+			// The connection keeps retrying without the credentials.
+			conn, _ := connect(t, cfg)
+			require.Eventually(t, func() bool { return conn.LastError() != nil }, 10*time.Second, 10*time.Millisecond)
+			assert.ErrorIs(t, conn.LastError(), nats.ErrAuthorization)
+			assert.False(t, conn.IsConnected())
+			conn.Close()
+
+			tt.configure(&cfg.Auth)
+			conn, _ = connect(t, cfg)
+			assert.Eventually(t, conn.IsConnected, 10*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestConnectTLS(t *testing.T) {
+	caFile, serverCert := generateCertificate(t)
+	server := natstest.NewServer(t, natstest.WithTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		MinVersion:   tls.VersionTLS12,
+	}))
+	cfg := NewDefaultClientConfig()
+	cfg.Servers = []string{server.URL}
+	cfg.TLS.CAFile = caFile
+	conn, _ := connect(t, cfg)
+	assert.Eventually(t, conn.IsConnected, 10*time.Second, 10*time.Millisecond)
+	assert.NotNil(t, conn.TLSConnectionState)
+}
+
+func TestConnectInBackground(t *testing.T) {
+	// The port of the closed listener refuses the connections.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	cfg := NewDefaultClientConfig()
+	cfg.Servers = []string{"nats://" + ln.Addr().String()}
+	// The reconnection in progress ends after the wait once the connection is closed.
+	cfg.ReconnectWait = 10 * time.Millisecond
+	conn, _ := connect(t, cfg)
+	assert.True(t, conn.IsReconnecting())
+	conn.Close()
+	assert.True(t, conn.IsClosed())
+}
+
+func TestConnectInvalidNKeyFile(t *testing.T) {
+	cfg := NewDefaultClientConfig()
+	cfg.Auth.NKeyFile = filepath.Join(t.TempDir(), "missing.nk")
+	_, err := cfg.Connect(context.Background(), zap.NewNop())
+	assert.ErrorContains(t, err, "failed to load the NKey")
+}
+
+// generateCertificate generates a self-signed certificate of 127.0.0.1, it returns the path of
+// the certificate and the certificate with its key.
+func generateCertificate(t *testing.T) (string, tls.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "nats"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return caFile, tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
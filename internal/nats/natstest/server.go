@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package natstest runs an embedded NATS server, with JetStream, for the tests of the NATS components.
+package natstest // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/nats/natstest"
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/require"
+)
+
+// Server is an embedded NATS server listening on a random local port.
+type Server struct {
+	// URL is the URL of the server for the clients.
+	URL string
+
+	server  *server.Server
+	options []nats.Option
+}
+
+// ServerOption configures the embedded server.
+type ServerOption func(opts *server.Options, s *Server)
+
+// WithTLS makes the server accept TLS connections only.
+func WithTLS(tlsConfig *tls.Config) ServerOption {
+	return func(opts *server.Options, _ *Server) {
+		opts.TLS = true
+		opts.TLSConfig = tlsConfig
+	}
+}
+
+// WithUser makes the server accept the connections of the user only.
+func WithUser(username, password string) ServerOption {
+	return func(opts *server.Options, s *Server) {
+		opts.Username = username
+		opts.Password = password
+		s.options = append(s.options, nats.UserInfo(username, password))
+	}
+}
+
+// WithToken makes the server accept the connections with the token only.
+func WithToken(token string) ServerOption {
+	return func(opts *server.Options, s *Server) {
+		opts.Authorization = token
+		s.options = append(s.options, nats.Token(token))
+	}
+}
+
+// NewServer starts a server, it's shut down at the end of the test.
+func NewServer(t testing.TB, opts ...ServerOption) *Server {
+	s := &Server{}
+	options := &server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	}
+	for _, opt := range opts {
+		opt(options, s)
+	}
+	srv, err := server.NewServer(options)
+	require.NoError(t, err)
+	srv.Start()
+	t.Cleanup(func() {
+		srv.Shutdown()
+		srv.WaitForShutdown()
+	})
+	require.True(t, srv.ReadyForConnections(10*time.Second), "the NATS server isn't ready")
+	s.server = srv
+	s.URL = srv.ClientURL()
+	return s
+}
+
+// Conn returns a connection of the tests to the server, authenticated as configured.
+func (s *Server) Conn(t testing.TB, opts ...nats.Option) *nats.Conn {
+	conn, err := nats.Connect(s.URL, append(s.options, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+// Subscribe returns the messages published to the subjects matching the subject filter.
+func (s *Server) Subscribe(t testing.TB, subject string, opts ...nats.Option) <-chan *nats.Msg {
+	conn := s.Conn(t, opts...)
+	messages := make(chan *nats.Msg, 100)
+	_, err := conn.ChanSubscribe(subject, messages)
+	require.NoError(t, err)
+	require.NoError(t, conn.Flush())
+	return messages
+}
+
+// CreateStream creates a JetStream stream storing the messages of the subjects.
+func (s *Server) CreateStream(t testing.TB, name string, subjects ...string) jetstream.Stream {
+	js, err := jetstream.New(s.Conn(t))
+	require.NoError(t, err)
+	stream, err := js.CreateStream(context.Background(), jetstream.StreamConfig{
+		Name:     name,
+		Subjects: subjects,
+	})
+	require.NoError(t, err)
+	return stream
+}
+
+// WaitForSubscription waits until a client subscribes to the subject.
+func (s *Server) WaitForSubscription(t testing.TB, subject string) {
+	require.Eventually(t, func() bool {
+		subs, err := s.server.Subsz(&server.SubszOptions{Subscriptions: true, Test: subject})
+		return err == nil && subs.Total > 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type ParseCEFArguments[K any] struct {
+	Target              ottl.StringGetter[K]
+	SemanticConventions ottl.Optional[bool]
+}
+
+func NewParseCEFFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ParseCEF", &ParseCEFArguments[K]{}, createParseCEFFunction[K])
+}
+
+func createParseCEFFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ParseCEFArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("ParseCEFFactory args must be of type *ParseCEFArguments[K]")
+	}
+
+	return parseCEF[K](args.Target, args.SemanticConventions), nil
+}
+
+func parseCEF[K any](target ottl.StringGetter[K], s ottl.Optional[bool]) ottl.ExprFunc[K] {
+	semconvCompliant := !s.IsEmpty() && s.Get()
+
+	return func(ctx context.Context, tCtx K) (any, error) {
+		source, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := parseutils.ParseCEF(source, semconvCompliant)
+		if err != nil {
+			return nil, err
+		}
+
+		result := pcommon.NewMap()
+		err = result.FromRaw(parsed)
+		return result, err
+	}
+}
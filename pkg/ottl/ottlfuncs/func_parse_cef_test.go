@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseCEF(t *testing.T) {
+	tests := []struct {
+		name                string
+		target              ottl.StringGetter[any]
+		semanticConventions ottl.Optional[bool]
+		expected            map[string]any
+	}{
+		{
+			name: "header and extension",
+			target: ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return `<134>Sep 19 08:26:10 host CEF:0|Security|threatmanager|1.0|100|worm stopped|10|src=10.0.0.1 spt=1232 msg=a \= b`, nil
+				},
+			},
+			expected: map[string]any{
+				"version":               int64(0),
+				"device_vendor":         "Security",
+				"device_product":        "threatmanager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "worm stopped",
+				"severity":              int64(10),
+				"extensions": map[string]any{
+					"src": "10.0.0.1",
+					"spt": int64(1232),
+					"msg": "a = b",
+				},
+			},
+		},
+		{
+			name: "semantic conventions",
+			target: ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return "CEF:0|Security|threatmanager|1.0|100|worm stopped|High|src=10.0.0.1 spt=1232 act=blocked", nil
+				},
+			},
+			semanticConventions: ottl.NewTestingOptional[bool](true),
+			expected: map[string]any{
+				"version":               int64(0),
+				"device_vendor":         "Security",
+				"device_product":        "threatmanager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "worm stopped",
+				"severity":              "High",
+				"source.address":        "10.0.0.1",
+				"source.port":           int64(1232),
+				"extensions": map[string]any{
+					"act": "blocked",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc := parseCEF[any](tt.target, tt.semanticConventions)
+
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+
+			actual, ok := result.(pcommon.Map)
+			require.True(t, ok)
+
+			expected := pcommon.NewMap()
+			require.NoError(t, expected.FromRaw(tt.expected))
+			assert.Equal(t, expected.AsRaw(), actual.AsRaw())
+		})
+	}
+}
+
+func Test_parseCEF_error(t *testing.T) {
+	target := ottl.StandardStringGetter[any]{
+		Getter: func(_ context.Context, _ any) (any, error) {
+			return "CEF:0|Security|threatmanager", nil
+		},
+	}
+	exprFunc := parseCEF[any](target, ottl.Optional[bool]{})
+
+	_, err := exprFunc(context.Background(), nil)
+	assert.EqualError(t, err, "invalid CEF header: expected 7 fields, got 3")
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseLEEF(t *testing.T) {
+	tests := []struct {
+		name                string
+		target              ottl.StringGetter[any]
+		semanticConventions ottl.Optional[bool]
+		expected            map[string]any
+	}{
+		{
+			name: "version 1.0",
+			target: ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=10.50.1.1\tsrcPort=1200\tmsg=mail delivered", nil
+				},
+			},
+			expected: map[string]any{
+				"version":         "1.0",
+				"vendor":          "Microsoft",
+				"product":         "MSExchange",
+				"product_version": "4.0 SP1",
+				"event_id":        "15345",
+				"extensions": map[string]any{
+					"src":     "10.50.1.1",
+					"srcPort": int64(1200),
+					"msg":     "mail delivered",
+				},
+			},
+		},
+		{
+			name: "version 2.0 with semantic conventions",
+			target: ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return "<13>Jan 18 11:07:53 host LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dstPort=443^cat=flow", nil
+				},
+			},
+			semanticConventions: ottl.NewTestingOptional[bool](true),
+			expected: map[string]any{
+				"version":          "2.0",
+				"vendor":           "Lancope",
+				"product":          "StealthWatch",
+				"product_version":  "1.0",
+				"event_id":         "41",
+				"source.address":   "10.0.1.8",
+				"destination.port": int64(443),
+				"extensions": map[string]any{
+					"cat": "flow",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc := parseLEEF[any](tt.target, tt.semanticConventions)
+
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+
+			actual, ok := result.(pcommon.Map)
+			require.True(t, ok)
+
+			expected := pcommon.NewMap()
+			require.NoError(t, expected.FromRaw(tt.expected))
+			assert.Equal(t, expected.AsRaw(), actual.AsRaw())
+		})
+	}
+}
+
+func Test_parseLEEF_error(t *testing.T) {
+	target := ottl.StandardStringGetter[any]{
+		Getter: func(_ context.Context, _ any) (any, error) {
+			return "not a LEEF message", nil
+		},
+	}
+	exprFunc := parseLEEF[any](target, ottl.Optional[bool]{})
+
+	_, err := exprFunc(context.Background(), nil)
+	assert.EqualError(t, err, "not a LEEF message: missing LEEF: prefix")
+}
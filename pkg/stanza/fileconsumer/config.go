@@ -7,6 +7,7 @@ package fileconsumer // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"bufio"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"runtime"
@@ -37,6 +38,18 @@ const (
 	defaultMaxConcurrentFiles = 1024
 	defaultEncoding           = "utf-8"
 	defaultPollInterval       = 200 * time.Millisecond
+	defaultWatchDebounce      = 50 * time.Millisecond
+	throttledRetryInterval    = 200 * time.Millisecond
+	defaultLengthPrefixSize   = 4
+)
+
+const (
+	recordFormatLines          = "lines"
+	recordFormatLengthPrefixed = "length_prefixed"
+	recordFormatJournalExport  = "journal_export"
+
+	byteOrderBigEndian    = "big_endian"
+	byteOrderLittleEndian = "little_endian"
 )
 
 var allowFileDeletion = featuregate.GlobalRegistry().MustRegister(
@@ -66,6 +79,16 @@ func NewConfig() *Config {
 		Resolver: attrs.Resolver{
 			IncludeFileName: true,
 		},
+		Watch: WatchConfig{
+			Debounce: defaultWatchDebounce,
+		},
+		Record: RecordConfig{
+			Format: recordFormatLines,
+			LengthPrefix: LengthPrefixConfig{
+				Size:      defaultLengthPrefixSize,
+				ByteOrder: byteOrderBigEndian,
+			},
+		},
 	}
 }
 
@@ -88,6 +111,117 @@ type Config struct {
 	IncludeFileRecordNumber bool            `mapstructure:"include_file_record_number,omitempty"`
 	Compression             string          `mapstructure:"compression,omitempty"`
 	AcquireFSLock           bool            `mapstructure:"acquire_fs_lock,omitempty"`
+	Watch                   WatchConfig     `mapstructure:"watch,omitempty"`
+	RateLimit               RateLimitConfig `mapstructure:"rate_limit,omitempty"`
+	Record                  RecordConfig    `mapstructure:"record,omitempty"`
+}
+
+// WatchConfig configures the discovery and reading of files driven by filesystem events
+type WatchConfig struct {
+	// Enabled watches the directories of the matched files with inotify, and reads files as
+	// they're written to, created or moved. The filesystem is still polled every poll_interval,
+	// to find the changes which couldn't be watched.
+	Enabled bool `mapstructure:"enabled,omitempty"`
+
+	// Debounce is the delay between a filesystem event and the read it triggers, during
+	// which further events are coalesced.
+	Debounce time.Duration `mapstructure:"debounce,omitempty"`
+}
+
+// RateLimitConfig limits the rate at which files are read, so that noisy files can't starve the others
+type RateLimitConfig struct {
+	// PerFile limits the rate at which each file is read.
+	PerFile RateLimit `mapstructure:"per_file,omitempty"`
+
+	// Total limits the rate at which all the files are read. It's shared equally
+	// between the files being read at the same time.
+	Total RateLimit `mapstructure:"total,omitempty"`
+
+	// MaxLag is the maximum number of unread bytes in a file. Files falling further
+	// behind skip ahead, dropping their oldest unread logs.
+	MaxLag helper.ByteSize `mapstructure:"max_lag,omitempty"`
+}
+
+// RecordConfig configures the format of the records in files which aren't made of lines
+type RecordConfig struct {
+	// Format is lines, length_prefixed for binary records preceded by their length, or
+	// journal_export for the systemd journal export format.
+	Format string `mapstructure:"format,omitempty"`
+
+	// LengthPrefix configures the length preceding the records of the length_prefixed format.
+	LengthPrefix LengthPrefixConfig `mapstructure:"length_prefix,omitempty"`
+}
+
+// LengthPrefixConfig is the encoding of the length preceding binary records
+type LengthPrefixConfig struct {
+	// Size is the number of bytes of the length, one of 1, 2, 4 or 8.
+	Size int `mapstructure:"size,omitempty"`
+
+	// ByteOrder is big_endian or little_endian.
+	ByteOrder string `mapstructure:"byte_order,omitempty"`
+}
+
+// RateLimit is a rate of bytes and lines per second. Zero values are unlimited.
+type RateLimit struct {
+	BytesPerSecond helper.ByteSize `mapstructure:"bytes_per_second,omitempty"`
+	LinesPerSecond int             `mapstructure:"lines_per_second,omitempty"`
+}
+
+func (l RateLimit) limit() reader.Limit {
+	return reader.Limit{
+		BytesPerSecond: int(l.BytesPerSecond),
+		LinesPerSecond: l.LinesPerSecond,
+	}
+}
+
+func (l RateLimit) validate(name string) error {
+	if l.BytesPerSecond < 0 {
+		return fmt.Errorf("'rate_limit.%s.bytes_per_second' must not be negative", name)
+	}
+	if l.LinesPerSecond < 0 {
+		return fmt.Errorf("'rate_limit.%s.lines_per_second' must not be negative", name)
+	}
+	return nil
+}
+
+// isLines reports whether the files are made of lines, split according to the multiline config
+func (c RecordConfig) isLines() bool {
+	return c.Format == "" || c.Format == recordFormatLines
+}
+
+func (c RecordConfig) splitFunc(maxLogSize int) (bufio.SplitFunc, error) {
+	switch c.Format {
+	case recordFormatLengthPrefixed:
+		var order binary.ByteOrder = binary.BigEndian
+		if c.LengthPrefix.ByteOrder == byteOrderLittleEndian {
+			order = binary.LittleEndian
+		}
+		return split.LengthPrefixedSplitFunc(c.LengthPrefix.Size, order, maxLogSize)
+	case recordFormatJournalExport:
+		return split.JournalExportSplitFunc(maxLogSize), nil
+	default:
+		return nil, fmt.Errorf("invalid 'record.format' %q", c.Format)
+	}
+}
+
+func (c RecordConfig) validate() error {
+	switch c.Format {
+	case "", recordFormatLines, recordFormatJournalExport:
+	case recordFormatLengthPrefixed:
+		switch c.LengthPrefix.Size {
+		case 1, 2, 4, 8:
+		default:
+			return errors.New("'record.length_prefix.size' must be one of 1, 2, 4 or 8")
+		}
+		switch c.LengthPrefix.ByteOrder {
+		case byteOrderBigEndian, byteOrderLittleEndian:
+		default:
+			return fmt.Errorf("'record.length_prefix.byte_order' must be %s or %s", byteOrderBigEndian, byteOrderLittleEndian)
+		}
+	default:
+		return fmt.Errorf("invalid 'record.format' %q, must be one of %s, %s or %s", c.Format, recordFormatLines, recordFormatLengthPrefixed, recordFormatJournalExport)
+	}
+	return nil
 }
 
 type HeaderConfig struct {
@@ -118,17 +252,28 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		return nil, fmt.Errorf("failed to find encoding: %w", err)
 	}
 
+	trimFunc := trim.Nop
+	if enc != encoding.Nop {
+		trimFunc = c.TrimConfig.Func()
+	}
+
+	flushPeriod := c.FlushPeriod
 	splitFunc := o.splitFunc
-	if splitFunc == nil {
+	switch {
+	case splitFunc != nil:
+	case c.Record.isLines():
 		splitFunc, err = c.SplitConfig.Func(enc, false, int(c.MaxLogSize))
 		if err != nil {
 			return nil, err
 		}
-	}
-
-	trimFunc := trim.Nop
-	if enc != encoding.Nop {
-		trimFunc = c.TrimConfig.Func()
+	default:
+		splitFunc, err = c.Record.splitFunc(int(c.MaxLogSize))
+		if err != nil {
+			return nil, err
+		}
+		// Records are emitted whole or not at all, so they're neither trimmed nor flushed
+		trimFunc = trim.Nop
+		flushPeriod = 0
 	}
 
 	var startAtBeginning bool
@@ -164,7 +309,7 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		Encoding:                enc,
 		SplitFunc:               splitFunc,
 		TrimFunc:                trimFunc,
-		FlushTimeout:            c.FlushPeriod,
+		FlushTimeout:            flushPeriod,
 		EmitFunc:                emit,
 		Attributes:              c.Resolver,
 		HeaderConfig:            hCfg,
@@ -172,6 +317,8 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		IncludeFileRecordNumber: c.IncludeFileRecordNumber,
 		Compression:             c.Compression,
 		AcquireFSLock:           c.AcquireFSLock,
+		FileLimit:               c.RateLimit.PerFile.limit(),
+		MaxLag:                  int64(c.RateLimit.MaxLag),
 	}
 
 	var t tracker.Tracker
@@ -192,6 +339,9 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		pollInterval:     c.PollInterval,
 		maxBatchFiles:    c.MaxConcurrentFiles / 2,
 		maxBatches:       c.MaxBatches,
+		watch:            c.Watch.Enabled,
+		watchDebounce:    c.Watch.Debounce,
+		totalLimiter:     reader.NewLimiter(c.RateLimit.Total.limit()),
 		tracker:          t,
 		telemetryBuilder: telemetryBuilder,
 	}, nil
@@ -245,6 +395,57 @@ func (c Config) validate() error {
 		}
 	}
 
+	if c.Watch.Enabled && runtime.GOOS != "linux" {
+		return errors.New("'watch' is only supported on linux")
+	}
+
+	if c.Watch.Debounce < 0 {
+		return errors.New("'watch.debounce' must not be negative")
+	}
+
+	if err := c.RateLimit.PerFile.validate("per_file"); err != nil {
+		return err
+	}
+
+	if err := c.RateLimit.Total.validate("total"); err != nil {
+		return err
+	}
+
+	if c.RateLimit.MaxLag < 0 {
+		return errors.New("'rate_limit.max_lag' must not be negative")
+	}
+
+	if c.RateLimit.MaxLag > 0 && c.Compression != "" {
+		return errors.New("'rate_limit.max_lag' cannot be used with 'compression'")
+	}
+
+	// Compressed files are read to the end in one go, as they can't be resumed in the middle
+	if c.Compression != "" && (c.RateLimit.PerFile != RateLimit{} || c.RateLimit.Total != RateLimit{}) {
+		return errors.New("'rate_limit' cannot be used with 'compression'")
+	}
+
+	switch c.Compression {
+	case "", "gzip", "zstd":
+	default:
+		return fmt.Errorf("invalid 'compression' %q, must be one of gzip or zstd", c.Compression)
+	}
+
+	if err := c.Record.validate(); err != nil {
+		return err
+	}
+
+	if !c.Record.isLines() {
+		if c.SplitConfig != (split.Config{}) {
+			return fmt.Errorf("'multiline' cannot be used with 'record.format: %s'", c.Record.Format)
+		}
+		if c.Header != nil {
+			return fmt.Errorf("'header' cannot be used with 'record.format: %s'", c.Record.Format)
+		}
+		if c.RateLimit.MaxLag > 0 {
+			return fmt.Errorf("'rate_limit.max_lag' cannot be used with 'record.format: %s'", c.Record.Format)
+		}
+	}
+
 	if runtime.GOOS == "windows" && (c.Resolver.IncludeFileOwnerName || c.Resolver.IncludeFileOwnerGroupName) {
 		return fmt.Errorf("'include_file_owner_name' or 'include_file_owner_group_name' it's not supported for windows: %w", err)
 	}
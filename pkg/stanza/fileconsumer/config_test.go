@@ -6,6 +6,7 @@ package fileconsumer
 import (
 	"fmt"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -42,6 +43,8 @@ func TestNewConfig(t *testing.T) {
 	assert.False(t, cfg.IncludeFileOwnerGroupName)
 	assert.False(t, cfg.IncludeFileRecordNumber)
 	assert.False(t, cfg.AcquireFSLock)
+	assert.False(t, cfg.Watch.Enabled)
+	assert.Equal(t, defaultWatchDebounce, cfg.Watch.Debounce)
 }
 
 func TestUnmarshal(t *testing.T) {
@@ -324,6 +327,14 @@ func TestUnmarshal(t *testing.T) {
 					return newMockOperatorConfig(cfg)
 				}(),
 			},
+			{
+				Name: "multiline_detector",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.SplitConfig.Detector = "java"
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
 			{
 				Name: "multiline_line_end_special",
 				Expect: func() *mockOperatorConfig {
@@ -430,6 +441,57 @@ func TestUnmarshal(t *testing.T) {
 					return newMockOperatorConfig(cfg)
 				}(),
 			},
+			{
+				Name: "rate_limit",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.RateLimit = RateLimitConfig{
+						PerFile: RateLimit{
+							BytesPerSecond: 1024 * 1024,
+							LinesPerSecond: 1000,
+						},
+						Total: RateLimit{
+							BytesPerSecond: 10 * 1024 * 1024,
+						},
+						MaxLag: 100 * 1024 * 1024,
+					}
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
+			{
+				Name: "record_length_prefixed",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.Encoding = "nop"
+					cfg.Record = RecordConfig{
+						Format: "length_prefixed",
+						LengthPrefix: LengthPrefixConfig{
+							Size:      2,
+							ByteOrder: "little_endian",
+						},
+					}
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
+			{
+				Name: "record_journal_export",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.Record.Format = "journal_export"
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
+			{
+				Name: "watch",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.Watch = WatchConfig{
+						Enabled:  true,
+						Debounce: 10 * time.Millisecond,
+					}
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
 		},
 	}.Run(t)
 }
@@ -500,6 +562,22 @@ func TestBuild(t *testing.T) {
 			require.NoError,
 			func(_ *testing.T, _ *Manager) {},
 		},
+		{
+			"MultilineConfiguredDetector",
+			func(cfg *Config) {
+				cfg.SplitConfig.Detector = "python"
+			},
+			require.NoError,
+			func(_ *testing.T, _ *Manager) {},
+		},
+		{
+			"MultilineUnknownDetector",
+			func(cfg *Config) {
+				cfg.SplitConfig.Detector = "cobol"
+			},
+			require.Error,
+			nil,
+		},
 		{
 			"InvalidEncoding",
 			func(cfg *Config) {
@@ -566,6 +644,170 @@ func TestBuild(t *testing.T) {
 				require.Equal(t, 6, m.maxBatches)
 			},
 		},
+		{
+			"Watch",
+			func(cfg *Config) {
+				cfg.Watch.Enabled = true
+			},
+			func(t require.TestingT, err error, _ ...any) {
+				if runtime.GOOS == "linux" {
+					require.NoError(t, err)
+				} else {
+					require.EqualError(t, err, "'watch' is only supported on linux")
+				}
+			},
+			func(t *testing.T, m *Manager) {
+				require.True(t, m.watch)
+				require.Equal(t, defaultWatchDebounce, m.watchDebounce)
+			},
+		},
+		{
+			"RateLimit",
+			func(cfg *Config) {
+				cfg.RateLimit.PerFile.LinesPerSecond = 10
+				cfg.RateLimit.Total.BytesPerSecond = 1024
+				cfg.RateLimit.MaxLag = 1024
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, 10, m.readerFactory.FileLimit.LinesPerSecond)
+				require.Equal(t, int64(1024), m.readerFactory.MaxLag)
+				require.NotNil(t, m.totalLimiter)
+			},
+		},
+		{
+			"NegativeRateLimit",
+			func(cfg *Config) {
+				cfg.RateLimit.Total.LinesPerSecond = -1
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"NegativeMaxLag",
+			func(cfg *Config) {
+				cfg.RateLimit.MaxLag = -1
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"MaxLagWithCompression",
+			func(cfg *Config) {
+				cfg.RateLimit.MaxLag = 1024
+				cfg.Compression = "gzip"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"PerFileRateLimitWithCompression",
+			func(cfg *Config) {
+				cfg.RateLimit.PerFile.LinesPerSecond = 2
+				cfg.Compression = "gzip"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"TotalRateLimitWithCompression",
+			func(cfg *Config) {
+				cfg.RateLimit.Total.BytesPerSecond = 1024
+				cfg.Compression = "zstd"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"Zstd",
+			func(cfg *Config) {
+				cfg.Compression = "zstd"
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, "zstd", m.readerFactory.Compression)
+			},
+		},
+		{
+			"InvalidCompression",
+			func(cfg *Config) {
+				cfg.Compression = "bzip2"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"RecordLengthPrefixed",
+			func(cfg *Config) {
+				cfg.Record.Format = "length_prefixed"
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, time.Duration(0), m.readerFactory.FlushTimeout)
+			},
+		},
+		{
+			"RecordJournalExport",
+			func(cfg *Config) {
+				cfg.Record.Format = "journal_export"
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, time.Duration(0), m.readerFactory.FlushTimeout)
+			},
+		},
+		{
+			"InvalidRecordFormat",
+			func(cfg *Config) {
+				cfg.Record.Format = "protobuf"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"InvalidLengthPrefixSize",
+			func(cfg *Config) {
+				cfg.Record.Format = "length_prefixed"
+				cfg.Record.LengthPrefix.Size = 3
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"InvalidLengthPrefixByteOrder",
+			func(cfg *Config) {
+				cfg.Record.Format = "length_prefixed"
+				cfg.Record.LengthPrefix.ByteOrder = "middle_endian"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"RecordWithMultiline",
+			func(cfg *Config) {
+				cfg.Record.Format = "journal_export"
+				cfg.SplitConfig.LineStartPattern = "^MESSAGE="
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"RecordWithMaxLag",
+			func(cfg *Config) {
+				cfg.Record.Format = "length_prefixed"
+				cfg.RateLimit.MaxLag = 1024
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"NegativeWatchDebounce",
+			func(cfg *Config) {
+				cfg.Watch.Debounce = -time.Second
+			},
+			require.Error,
+			nil,
+		},
 		{
 			"HeaderConfigNoFlag",
 			func(cfg *Config) {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/tracker"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/watcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 )
@@ -36,6 +38,14 @@ type Manager struct {
 	maxBatches    int
 	maxBatchFiles int
 
+	watch         bool
+	watchDebounce time.Duration
+	watcher       *watcher.Watcher
+
+	totalLimiter   *reader.Limiter
+	throttledMutex sync.Mutex
+	throttled      map[string]struct{}
+
 	telemetryBuilder *metadata.TelemetryBuilder
 }
 
@@ -60,6 +70,16 @@ func (m *Manager) Start(persister operator.Persister) error {
 		}
 	}
 
+	if m.watch {
+		w, err := watcher.New(m.set, m.fileMatcher.Include(), m.fileMatcher.Match)
+		if err == nil {
+			m.watcher = w
+			m.startWatcher(ctx)
+			return nil
+		}
+		m.set.Logger.Warn("Failed to watch files, falling back to polling", zap.Error(err))
+	}
+
 	// Start polling goroutine
 	m.startPoller(ctx)
 
@@ -73,6 +93,7 @@ func (m *Manager) Stop() error {
 		m.cancel = nil
 	}
 	m.wg.Wait()
+	m.watcher = nil
 	m.telemetryBuilder.FileconsumerOpenFiles.Add(context.TODO(), int64(0-m.tracker.ClosePreviousFiles()))
 	if m.persister != nil {
 		if err := checkpoint.Save(context.Background(), m.persister, m.tracker.GetMetadata()); err != nil {
@@ -103,6 +124,56 @@ func (m *Manager) startPoller(ctx context.Context) {
 	}()
 }
 
+// startWatcher kicks off a goroutine that will read files as the watcher reports changes in
+// them, still polling the filesystem periodically to find the changes which couldn't be watched
+func (m *Manager) startWatcher(ctx context.Context) {
+	m.wg.Add(2)
+	go func() {
+		defer m.wg.Done()
+		if err := m.watcher.Watch(ctx); err != nil {
+			m.set.Logger.Error("watching files", zap.Error(err))
+		}
+	}()
+	go func() {
+		defer m.wg.Done()
+		globTicker := time.NewTicker(m.pollInterval)
+		defer globTicker.Stop()
+
+		// Poll right away, to find the directories to watch
+		m.poll(ctx)
+		for {
+			// Files throttled by a rate limit aren't changed anymore once written to,
+			// so they're read again after a while, without waiting for the next poll
+			var retry <-chan time.Time
+			if m.hasThrottled() {
+				retry = time.After(throttledRetryInterval)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-globTicker.C:
+				m.poll(ctx)
+				continue
+			case <-retry:
+			case <-m.watcher.C:
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(m.watchDebounce):
+				}
+			}
+
+			paths, removed, full := m.watcher.Changes()
+			if full || m.fileMatcher.Filtered() {
+				m.poll(ctx)
+				continue
+			}
+			m.pollChanged(ctx, append(paths, m.popThrottled()...), removed)
+		}
+	}()
+}
+
 // poll checks all the watched paths for new entries
 func (m *Manager) poll(ctx context.Context) {
 	// Used to keep track of the number of batches processed in this poll cycle
@@ -114,9 +185,12 @@ func (m *Manager) poll(ctx context.Context) {
 		m.set.Logger.Debug("finding files", zap.Error(err))
 	}
 	m.set.Logger.Debug("matched files", zap.Strings("paths", matches))
+	if m.watcher != nil {
+		m.watcher.Sync(matches)
+	}
 
 	for len(matches) > m.maxBatchFiles {
-		m.consume(ctx, matches[:m.maxBatchFiles])
+		m.consume(ctx, matches[:m.maxBatchFiles], nil, false)
 
 		// If a maxBatches is set, check if we have hit the limit
 		if m.maxBatches != 0 {
@@ -128,42 +202,143 @@ func (m *Manager) poll(ctx context.Context) {
 
 		matches = matches[m.maxBatchFiles:]
 	}
-	m.consume(ctx, matches)
+	m.consume(ctx, matches, nil, false)
 
 	// Any new files that appear should be consumed entirely
 	m.readerFactory.FromBeginning = true
-	if m.persister != nil {
-		metadata := m.tracker.GetMetadata()
-		if metadata != nil {
-			if err := checkpoint.Save(context.Background(), m.persister, metadata); err != nil {
-				m.set.Logger.Error("save offsets", zap.Error(err))
-			}
-		}
-	}
+	m.saveCheckpoint()
 	// rotate at end of every poll()
 	m.tracker.EndPoll()
 }
 
-func (m *Manager) consume(ctx context.Context, paths []string) {
+// pollChanged reads the files the watcher reported changes in, without matching the include patterns
+// against the whole filesystem. The poll cycle isn't ended, and the readers of the files which weren't
+// changed are carried over as they are, so that they're only treated as lost by the full polls.
+// The files which were removed or moved away are treated as lost right away, to read them to the end.
+func (m *Manager) pollChanged(ctx context.Context, paths []string, removed []string) {
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+	for len(paths) > m.maxBatchFiles {
+		m.consume(ctx, paths[:m.maxBatchFiles], removed, true)
+		removed = nil
+		paths = paths[m.maxBatchFiles:]
+	}
+	m.consume(ctx, paths, removed, true)
+	m.saveCheckpoint()
+}
+
+func (m *Manager) saveCheckpoint() {
+	if m.persister == nil {
+		return
+	}
+	metadata := m.tracker.GetMetadata()
+	if metadata != nil {
+		if err := checkpoint.Save(context.Background(), m.persister, metadata); err != nil {
+			m.set.Logger.Error("save offsets", zap.Error(err))
+		}
+	}
+}
+
+// consume reads the files at the paths. When only the changed files are consumed, the readers of
+// the other files are neither read nor closed, but carried over to the next consume cycle, except
+// for the readers of the removed files.
+func (m *Manager) consume(ctx context.Context, paths []string, removed []string, changedOnly bool) {
 	m.set.Logger.Debug("Consuming files", zap.Strings("paths", paths))
 	m.makeReaders(ctx, paths)
 
-	m.readLostFiles(ctx)
+	var unchanged []*reader.Reader
+	if changedOnly {
+		changed := make(map[string]struct{}, len(paths)+len(removed))
+		for _, path := range paths {
+			changed[path] = struct{}{}
+		}
+		for _, path := range removed {
+			changed[path] = struct{}{}
+		}
+		unchanged = m.tracker.TakePreviousFiles(func(r *reader.Reader) bool {
+			_, ok := changed[r.GetFileName()]
+			return !ok
+		})
+	}
+
+	// Lost files throttled by a rate limit are kept open, to be read again by the next consume cycle
+	throttledLost := m.readLostFiles(ctx)
 
 	// read new readers to end
+	m.read(ctx, m.tracker.CurrentPollFiles())
+
+	for _, r := range unchanged {
+		m.tracker.Add(r)
+	}
+	for _, r := range throttledLost {
+		m.tracker.Add(r)
+	}
+
+	m.telemetryBuilder.FileconsumerOpenFiles.Add(ctx, int64(0-m.tracker.EndConsume()))
+}
+
+// read reads the files to the end concurrently, sharing the total rate limit equally between them,
+// and returns the readers throttled by a rate limit.
+func (m *Manager) read(ctx context.Context, readers []*reader.Reader) []*reader.Reader {
+	quota := m.totalLimiter.Share(len(readers))
+
 	var wg sync.WaitGroup
-	for _, r := range m.tracker.CurrentPollFiles() {
+	var throttledMutex sync.Mutex
+	var throttled []*reader.Reader
+	for _, r := range readers {
 		wg.Add(1)
 		go func(r *reader.Reader) {
 			defer wg.Done()
 			m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, 1)
-			r.ReadToEnd(ctx)
+			r.SetLimits(true, quota)
+			result := r.ReadToEnd(ctx)
 			m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, -1)
+
+			if result.SkippedBytes > 0 {
+				m.telemetryBuilder.FileconsumerSkippedBytes.Add(ctx, result.SkippedBytes)
+			}
+			if result.Throttled {
+				m.telemetryBuilder.FileconsumerThrottledFiles.Add(ctx, 1)
+				m.addThrottled(r.GetFileName())
+				throttledMutex.Lock()
+				throttled = append(throttled, r)
+				throttledMutex.Unlock()
+			}
 		}(r)
 	}
 	wg.Wait()
+	return throttled
+}
 
-	m.telemetryBuilder.FileconsumerOpenFiles.Add(ctx, int64(0-m.tracker.EndConsume()))
+// addThrottled keeps track of a file throttled by a rate limit, for it to be read again when
+// watching files. When polling, the next poll reads it anyway.
+func (m *Manager) addThrottled(path string) {
+	if m.watcher == nil {
+		return
+	}
+	m.throttledMutex.Lock()
+	defer m.throttledMutex.Unlock()
+	if m.throttled == nil {
+		m.throttled = make(map[string]struct{})
+	}
+	m.throttled[path] = struct{}{}
+}
+
+func (m *Manager) hasThrottled() bool {
+	m.throttledMutex.Lock()
+	defer m.throttledMutex.Unlock()
+	return len(m.throttled) > 0
+}
+
+func (m *Manager) popThrottled() []string {
+	m.throttledMutex.Lock()
+	defer m.throttledMutex.Unlock()
+	paths := make([]string, 0, len(m.throttled))
+	for path := range m.throttled {
+		paths = append(paths, path)
+	}
+	clear(m.throttled)
+	return paths
 }
 
 func (m *Manager) makeFingerprint(path string) (*fingerprint.Fingerprint, *os.File) {
@@ -7,7 +7,7 @@ package fileconsumer // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"context"
-	"sync"
+	"slices"
 
 	"go.uber.org/zap"
 
@@ -16,12 +16,14 @@ import (
 
 // Take care of files which disappeared from the pattern since the last poll cycle
 // this can mean either files which were removed, or rotated into a name not matching the pattern
-// we do this before reading existing files to ensure we emit older log lines before newer ones
-func (m *Manager) readLostFiles(ctx context.Context) {
+// we do this before reading existing files to ensure we emit older log lines before newer ones.
+// The lost files are read within the rate limits, and the readers throttled by a rate limit are
+// taken out of the previous poll cycle and returned, so that they're read again rather than closed.
+func (m *Manager) readLostFiles(ctx context.Context) []*reader.Reader {
 	if m.readerFactory.DeleteAtEOF {
 		// Lost files are not expected when delete_at_eof is enabled
 		// since we are deleting the files before they can become lost.
-		return
+		return nil
 	}
 	previousPollFiles := m.tracker.PreviousPollFiles()
 	lostReaders := make([]*reader.Reader, 0, len(previousPollFiles))
@@ -51,16 +53,14 @@ OUTER:
 		lostReaders = append(lostReaders, oldReader)
 	}
 
-	var lostWG sync.WaitGroup
 	for _, lostReader := range lostReaders {
-		lostWG.Add(1)
 		m.set.Logger.Debug("Reading lost file", zap.String("path", lostReader.GetFileName()))
-		go func(r *reader.Reader) {
-			defer lostWG.Done()
-			m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, 1)
-			r.ReadToEnd(ctx)
-			m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, -1)
-		}(lostReader)
 	}
-	lostWG.Wait()
+	throttled := m.read(ctx, lostReaders)
+	if len(throttled) == 0 {
+		return nil
+	}
+	return m.tracker.TakePreviousFiles(func(r *reader.Reader) bool {
+		return slices.Contains(throttled, r)
+	})
 }
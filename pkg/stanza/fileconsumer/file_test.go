@@ -11,10 +11,12 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/featuregate"
@@ -1598,3 +1600,171 @@ func TestReadGzipCompressedLogsFromEnd(t *testing.T) {
 	operator.poll(context.TODO())
 	sink.ExpectToken(t, []byte("testlog4"))
 }
+
+// TestReadZstdCompressedLogsFromEnd tests that, when starting at the end of a zstd compressed file, we
+// read the lines of the frames appended afterward
+func TestReadZstdCompressedLogsFromEnd(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.Compression = "zstd"
+	cfg.StartAt = "end"
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTempWithPattern(t, tempDir, "*.zst")
+
+	appendToLog := func(t *testing.T, content string) {
+		writer, err := zstd.NewWriter(temp)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	}
+
+	appendToLog(t, "testlog1\ntestlog2\n")
+	operator.poll(context.TODO())
+
+	appendToLog(t, "testlog3\n")
+	appendToLog(t, "testlog4\n")
+	operator.poll(context.TODO())
+	sink.ExpectTokens(t, []byte("testlog3"), []byte("testlog4"))
+
+	appendToLog(t, "testlog5\n")
+	operator.poll(context.TODO())
+	sink.ExpectToken(t, []byte("testlog5"))
+}
+
+// TestReadLengthPrefixedRecords tests that binary records preceded by their length are read whole,
+// including when they're written in several parts
+func TestReadLengthPrefixedRecords(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Encoding = "nop"
+	cfg.Record.Format = "length_prefixed"
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	record := func(payload string) string {
+		return string([]byte{0, 0, 0, byte(len(payload))}) + payload
+	}
+
+	filetest.WriteString(t, temp, record("first\nrecord")+record("\x00\x01binary "))
+	operator.poll(context.TODO())
+	sink.ExpectTokens(t, []byte("first\nrecord"), []byte("\x00\x01binary "))
+
+	// The second half of the record is written later
+	third := record("third record")
+	filetest.WriteString(t, temp, third[:8])
+	operator.poll(context.TODO())
+	sink.ExpectNoCalls(t)
+
+	filetest.WriteString(t, temp, third[8:])
+	operator.poll(context.TODO())
+	sink.ExpectToken(t, []byte("third record"))
+}
+
+// TestSkipRecordsExceedingMaxLogSize tests that records which don't fit in max_log_size are skipped,
+// rather than stopping the reading of the file
+func TestSkipRecordsExceedingMaxLogSize(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Encoding = "nop"
+	cfg.MaxLogSize = 32
+	cfg.Record.Format = "length_prefixed"
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	record := func(payload string) string {
+		return string([]byte{0, 0, 0, byte(len(payload))}) + payload
+	}
+
+	filetest.WriteString(t, temp, record("first")+record(strings.Repeat("x", 100))+record("second"))
+	operator.poll(context.TODO())
+	sink.ExpectTokens(t, []byte("first"), []byte("second"))
+
+	// The rest of the long record is written later
+	long := record(strings.Repeat("y", 100))
+	filetest.WriteString(t, temp, long[:50])
+	operator.poll(context.TODO())
+	sink.ExpectNoCalls(t)
+
+	filetest.WriteString(t, temp, long[50:]+record("third"))
+	operator.poll(context.TODO())
+	sink.ExpectToken(t, []byte("third"))
+	sink.ExpectNoCalls(t)
+}
+
+// TestSkipJournalFieldsExceedingMaxLogSize tests that journal entries exceeding max_log_size are
+// split into several entries, and that fields which don't fit in max_log_size are skipped
+func TestSkipJournalFieldsExceedingMaxLogSize(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.MaxLogSize = 64
+	cfg.Record.Format = "journal_export"
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "MESSAGE=first\nDATA="+strings.Repeat("x", 200)+"\n_PID=1\n\n")
+	filetest.WriteString(t, temp, "MESSAGE\n\xc8\x00\x00\x00\x00\x00\x00\x00"+strings.Repeat("y", 200)+"\n_PID=2\n\n")
+	filetest.WriteString(t, temp, "MESSAGE=last\n\n")
+
+	operator.poll(context.TODO())
+	sink.ExpectTokens(t,
+		[]byte(`{"MESSAGE":"first"}`),
+		[]byte(`{"_PID":"1"}`),
+		[]byte(`{"_PID":"2"}`),
+		[]byte(`{"MESSAGE":"last"}`),
+	)
+}
+
+// TestReadMultilineDetector tests that the lines of stack traces are read as a single log entry
+func TestReadMultilineDetector(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.SplitConfig.Detector = "java"
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "starting\njava.lang.IllegalStateException: boom\n\tat com.example.App.main(App.java:12)\nstopping\n")
+
+	operator.poll(context.TODO())
+	sink.ExpectTokens(t,
+		[]byte("starting"),
+		[]byte("java.lang.IllegalStateException: boom\n\tat com.example.App.main(App.java:12)"),
+		[]byte("stopping"),
+	)
+}
+
+// TestReadJournalExport tests that the entries of files in the journal export format are read as JSON objects
+func TestReadJournalExport(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Record.Format = "journal_export"
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "__REALTIME_TIMESTAMP=1700000000000000\nMESSAGE=first\n_PID=1\n\n")
+	filetest.WriteString(t, temp, "MESSAGE\n\x0b\x00\x00\x00\x00\x00\x00\x00line1\nline2\n_PID=2\n\n")
+
+	operator.poll(context.TODO())
+	sink.ExpectTokens(t,
+		[]byte(`{"MESSAGE":"first","_PID":"1","__REALTIME_TIMESTAMP":"1700000000000000"}`),
+		[]byte(`{"MESSAGE":"line1\nline2","_PID":"2"}`),
+	)
+}
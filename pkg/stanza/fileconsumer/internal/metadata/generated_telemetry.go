@@ -28,10 +28,12 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 // TelemetryBuilder provides an interface for components to report telemetry
 // as defined in metadata and user config.
 type TelemetryBuilder struct {
-	meter                    metric.Meter
-	FileconsumerOpenFiles    metric.Int64UpDownCounter
-	FileconsumerReadingFiles metric.Int64UpDownCounter
-	meters                   map[configtelemetry.Level]metric.Meter
+	meter                      metric.Meter
+	FileconsumerOpenFiles      metric.Int64UpDownCounter
+	FileconsumerReadingFiles   metric.Int64UpDownCounter
+	FileconsumerSkippedBytes   metric.Int64Counter
+	FileconsumerThrottledFiles metric.Int64Counter
+	meters                     map[configtelemetry.Level]metric.Meter
 }
 
 // telemetryBuilderOption applies changes to default builder.
@@ -58,5 +60,17 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.FileconsumerSkippedBytes, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_fileconsumer_skipped_bytes",
+		metric.WithDescription("Number of bytes skipped because files fell further behind than the maximum lag"),
+		metric.WithUnit("By"),
+	)
+	errs = errors.Join(errs, err)
+	builder.FileconsumerThrottledFiles, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_fileconsumer_throttled_files",
+		metric.WithDescription("Number of times reading a file was paused by a rate limit until the next poll"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
 	return &builder, errs
 }
@@ -45,6 +45,8 @@ type Factory struct {
 	IncludeFileRecordNumber bool
 	Compression             string
 	AcquireFSLock           bool
+	FileLimit               Limit
+	MaxLag                  int64
 }
 
 func (f *Factory) NewFingerprint(file *os.File) (*fingerprint.Fingerprint, error) {
@@ -79,6 +81,7 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		includeFileRecordNum: f.IncludeFileRecordNumber,
 		compression:          f.Compression,
 		acquireFSLock:        f.AcquireFSLock,
+		maxLag:               f.MaxLag,
 	}
 	r.set.Logger = r.set.Logger.With(zap.String("path", r.fileName))
 
@@ -102,6 +105,10 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		r.Offset = info.Size()
 	}
 
+	if m.limiter == nil {
+		m.limiter = NewLimiter(f.FileLimit)
+	}
+
 	flushFunc := m.FlushState.Func(f.SplitFunc, f.FlushTimeout)
 	r.lineSplitFunc = trim.WithFunc(trim.ToLength(flushFunc, f.MaxLogSize), f.TrimFunc)
 	r.emitFunc = f.EmitFunc
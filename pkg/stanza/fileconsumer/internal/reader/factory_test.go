@@ -61,6 +61,8 @@ func testFactory(t *testing.T, opts ...testFactoryOpt) (*Factory, *emittest.Sink
 		FlushTimeout:      cfg.flushPeriod,
 		EmitFunc:          sink.Callback,
 		Attributes:        cfg.attributes,
+		FileLimit:         cfg.fileLimit,
+		MaxLag:            cfg.maxLag,
 	}, sink
 }
 
@@ -77,6 +79,8 @@ type testFactoryCfg struct {
 	flushPeriod       time.Duration
 	sinkChanSize      int
 	attributes        attrs.Resolver
+	fileLimit         Limit
+	maxLag            int64
 }
 
 func withFingerprintSize(size int) testFactoryOpt {
@@ -115,6 +119,18 @@ func withSinkChanSize(n int) testFactoryOpt {
 	}
 }
 
+func withFileLimit(limit Limit) testFactoryOpt {
+	return func(c *testFactoryCfg) {
+		c.fileLimit = limit
+	}
+}
+
+func withMaxLag(maxLag int64) testFactoryOpt {
+	return func(c *testFactoryCfg) {
+		c.maxLag = maxLag
+	}
+}
+
 func fromEnd() testFactoryOpt {
 	return func(c *testFactoryCfg) {
 		c.fromBeginning = false
@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a rate of bytes and records per second. Zero values are unlimited.
+type Limit struct {
+	BytesPerSecond int
+	LinesPerSecond int
+}
+
+// Limiter limits the rate at which records are read, with token buckets holding up to one second
+// worth of tokens. A record is allowed as long as a token is left, even if it needs more than
+// are left, so that records larger than the bucket still get through. The resulting debt
+// is repaid before further records are allowed.
+type Limiter struct {
+	mu    sync.Mutex
+	bytes *bucket
+	lines *bucket
+}
+
+// NewLimiter creates a limiter for the limit, or returns nil if the limit is unlimited.
+// A nil limiter allows every record.
+func NewLimiter(limit Limit) *Limiter {
+	if limit.BytesPerSecond <= 0 && limit.LinesPerSecond <= 0 {
+		return nil
+	}
+	now := time.Now()
+	return &Limiter{
+		bytes: newBucket(limit.BytesPerSecond, now),
+		lines: newBucket(limit.LinesPerSecond, now),
+	}
+}
+
+// Share divides the tokens currently available between n readers, which read concurrently.
+// Readers taking their records from their own quota rather than from the limiter directly can't
+// starve each other, however faster than the others they can read.
+func (l *Limiter) Share(n int) Quota {
+	if l == nil || n <= 0 {
+		return Quota{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	return Quota{
+		limiter: l,
+		bytes:   l.bytes.share(now, n),
+		lines:   l.lines.share(now, n),
+	}
+}
+
+func (l *Limiter) allow(now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.bytes.allow(now) && l.lines.allow(now)
+}
+
+func (l *Limiter) take(now time.Time, n int) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.bytes.take(now, float64(n))
+	l.lines.take(now, 1)
+}
+
+// Quota is the part of the tokens of a limiter a reader may take during a read.
+// The zero value allows every record.
+type Quota struct {
+	limiter *Limiter
+	bytes   float64
+	lines   float64
+}
+
+// allow reports whether anything is left of the quota. Unlike the limiter, a fraction of a token is enough,
+// so that readers still get through when the tokens available are fewer than the readers sharing them.
+func (q *Quota) allow() bool {
+	return q.limiter == nil || (q.bytes > 0 && q.lines > 0)
+}
+
+func (q *Quota) take(now time.Time, n int) {
+	if q.limiter == nil {
+		return
+	}
+	q.bytes -= float64(n)
+	q.lines--
+	q.limiter.take(now, n)
+}
+
+// bucket is a token bucket refilled at rate tokens per second, holding up to rate tokens.
+// A nil bucket is unlimited.
+type bucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate int, now time.Time) *bucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &bucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   now,
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.rate, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+func (b *bucket) allow(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.refill(now)
+	return b.tokens >= 1
+}
+
+func (b *bucket) take(now time.Time, n float64) {
+	if b == nil {
+		return
+	}
+	b.refill(now)
+	b.tokens -= n
+}
+
+func (b *bucket) share(now time.Time, n int) float64 {
+	if b == nil {
+		return math.Inf(1)
+	}
+	b.refill(now)
+	return math.Max(b.tokens, 0) / float64(n)
+}
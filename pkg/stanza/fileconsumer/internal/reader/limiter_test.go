@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := NewLimiter(Limit{})
+	require.Nil(t, l)
+
+	now := time.Now()
+	assert.True(t, l.allow(now))
+	l.take(now, 100)
+	assert.True(t, l.allow(now))
+
+	q := l.Share(2)
+	assert.True(t, q.allow())
+}
+
+func TestLimiterLines(t *testing.T) {
+	l := NewLimiter(Limit{LinesPerSecond: 3})
+	now := l.lines.last
+
+	for i := 0; i < 3; i++ {
+		require.True(t, l.allow(now))
+		l.take(now, 1000)
+	}
+	assert.False(t, l.allow(now))
+
+	// A third of a second refills a token
+	assert.True(t, l.allow(now.Add(time.Second/3+time.Millisecond)))
+
+	// The bucket doesn't hold more than one second worth of tokens
+	later := now.Add(time.Hour)
+	assert.True(t, l.allow(later))
+	assert.Equal(t, float64(3), l.lines.tokens)
+}
+
+func TestLimiterBytesDebt(t *testing.T) {
+	l := NewLimiter(Limit{BytesPerSecond: 100})
+	now := l.bytes.last
+
+	// A record larger than the bucket is allowed while tokens are left,
+	// and the debt it leaves must be repaid before the next one
+	require.True(t, l.allow(now))
+	l.take(now, 250)
+	assert.False(t, l.allow(now))
+	assert.False(t, l.allow(now.Add(time.Second)))
+	assert.True(t, l.allow(now.Add(1510*time.Millisecond)))
+}
+
+func TestLimiterShare(t *testing.T) {
+	l := NewLimiter(Limit{LinesPerSecond: 100})
+
+	q := l.Share(4)
+	assert.InDelta(t, 25, q.lines, 1)
+	assert.True(t, math.IsInf(q.bytes, 1))
+
+	now := time.Now()
+	taken := 0
+	for q.allow() {
+		q.take(now, 10)
+		taken++
+	}
+	assert.InDelta(t, 25, taken, 1)
+	assert.InDelta(t, 100-taken, l.lines.tokens, 1)
+
+	// A quota doesn't get more than the tokens available
+	l.take(now, 1000)
+	l.lines.tokens = -10
+	q = l.Share(4)
+	assert.False(t, q.allow())
+}
@@ -10,7 +10,9 @@ import (
 	"errors"
 	"io"
 	"os"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
 
@@ -21,6 +23,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/header"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/scanner"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/flush"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 )
 
 type Metadata struct {
@@ -30,6 +33,10 @@ type Metadata struct {
 	FileAttributes  map[string]any
 	HeaderFinalized bool
 	FlushState      *flush.State
+
+	// limiter is kept with the metadata, so that the rate limit of the file
+	// applies across the readers created for it in successive polls.
+	limiter *Limiter
 }
 
 // Reader manages a single file
@@ -53,10 +60,27 @@ type Reader struct {
 	includeFileRecordNum   bool
 	compression            string
 	acquireFSLock          bool
+	maxLag                 int64
+	limited                bool
+	quota                  Quota
+}
+
+// ReadResult describes how a call to ReadToEnd went
+type ReadResult struct {
+	// Throttled is true if reading stopped before the end of the file because of a rate limit
+	Throttled bool
+	// SkippedBytes is the number of bytes skipped because the file fell too far behind
+	SkippedBytes int64
+}
+
+// SetLimits sets whether the next read is rate limited, and the part of the total rate limit it may use
+func (r *Reader) SetLimits(limited bool, q Quota) {
+	r.limited = limited
+	r.quota = q
 }
 
 // ReadToEnd will read until the end of the file
-func (r *Reader) ReadToEnd(ctx context.Context) {
+func (r *Reader) ReadToEnd(ctx context.Context) (result ReadResult) {
 	if r.acquireFSLock {
 		if !r.tryLockFile() {
 			return
@@ -65,8 +89,8 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 	}
 
 	switch r.compression {
-	case "gzip":
-		// We need to create a gzip reader each time ReadToEnd is called because the underlying
+	case "gzip", "zstd":
+		// We need to create a decompressing reader each time ReadToEnd is called because the underlying
 		// SectionReader can only read a fixed window (from previous offset to EOF).
 		info, err := r.file.Stat()
 		if err != nil {
@@ -75,17 +99,18 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		}
 		currentEOF := info.Size()
 
-		// use a gzip Reader with an underlying SectionReader to pick up at the last
-		// offset of a gzip compressed file
-		gzipReader, err := gzip.NewReader(io.NewSectionReader(r.file, r.Offset, currentEOF))
+		// use a decompressing Reader with an underlying SectionReader to pick up at the last
+		// offset of a compressed file. Files may be made of several concatenated gzip members
+		// or zstd frames, such as those appended by rotation.
+		decompressor, err := newDecompressor(r.compression, io.NewSectionReader(r.file, r.Offset, currentEOF))
 		if err != nil {
 			if !errors.Is(err, io.EOF) {
-				r.set.Logger.Error("Failed to create gzip reader", zap.Error(err))
+				r.set.Logger.Error("Failed to create decompressing reader", zap.String("compression", r.compression), zap.Error(err))
 			}
 			return
-		} else {
-			r.reader = gzipReader
 		}
+		defer decompressor.Close()
+		r.reader = decompressor
 		// Offset tracking in an uncompressed file is based on the length of emitted tokens, but in this case
 		// we need to set the offset to the end of the file.
 		defer func() {
@@ -95,6 +120,11 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		r.reader = r.file
 	}
 
+	skipPartial := false
+	if r.maxLag > 0 && r.compression == "" && r.headerReader == nil {
+		result.SkippedBytes, skipPartial = r.skipAhead()
+	}
+
 	if _, err := r.file.Seek(r.Offset, 0); err != nil {
 		r.set.Logger.Error("Failed to seek", zap.Error(err))
 		return
@@ -118,6 +148,17 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 
 		ok := s.Scan()
 		if !ok {
+			var tooLong *split.RecordTooLongError
+			if errors.As(s.Err(), &tooLong) && r.compression == "" {
+				// Move past the record or we may be stuck
+				r.set.Logger.Warn("Skipping record exceeding max_log_size", zap.Int64("offset", s.Pos()), zap.Error(tooLong))
+				if err := r.skipRecord(s.Pos(), tooLong); err != nil {
+					r.set.Logger.Error("Failed to skip record", zap.Error(err))
+					return
+				}
+				s = scanner.New(r, r.maxLogSize, r.initialBufferSize, r.Offset, r.splitFunc)
+				continue
+			}
 			if err := s.Error(); err != nil {
 				r.set.Logger.Error("Failed during scan", zap.Error(err))
 			} else if r.deleteAtEOF {
@@ -126,6 +167,19 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 			return
 		}
 
+		if skipPartial {
+			// The first record after skipping ahead is most likely partial
+			skipPartial = false
+			r.Offset = s.Pos()
+			continue
+		}
+
+		if !r.allow(len(s.Bytes())) {
+			// Stop here and pick up at the same offset in the next poll
+			result.Throttled = true
+			return
+		}
+
 		token, err := r.decoder.Decode(s.Bytes())
 		if err != nil {
 			r.set.Logger.Error("decode: %w", zap.Error(err))
@@ -172,6 +226,76 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 	}
 }
 
+// skipRecord moves the offset past a record which can't be read, starting at the given offset.
+// The offset may end up past the end of the file, if the record isn't completely written yet.
+func (r *Reader) skipRecord(offset int64, tooLong *split.RecordTooLongError) error {
+	r.Offset = offset + tooLong.Length
+	if _, err := r.file.Seek(r.Offset, 0); err != nil {
+		return err
+	}
+	if !tooLong.SkipLine {
+		return nil
+	}
+
+	br := bufio.NewReader(r.file)
+	for {
+		line, err := br.ReadSlice('\n')
+		r.Offset += int64(len(line))
+		if err == nil || errors.Is(err, io.EOF) {
+			break
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return err
+		}
+	}
+	_, err := r.file.Seek(r.Offset, 0)
+	return err
+}
+
+// allow takes the tokens to emit a record of n bytes from the rate limits, unless one of them is reached
+func (r *Reader) allow(n int) bool {
+	if !r.limited {
+		return true
+	}
+	now := time.Now()
+	if !r.limiter.allow(now) || !r.quota.allow() {
+		return false
+	}
+	r.limiter.take(now, n)
+	r.quota.take(now, n)
+	return true
+}
+
+// skipAhead moves the offset forward if more than maxLag bytes are left to read, so that only
+// the last maxLag bytes of the file are read. It returns the number of bytes skipped.
+func (r *Reader) skipAhead() (int64, bool) {
+	info, err := r.file.Stat()
+	if err != nil {
+		r.set.Logger.Error("Failed to stat", zap.Error(err))
+		return 0, false
+	}
+	skipped := info.Size() - r.maxLag - r.Offset
+	if skipped <= 0 {
+		return 0, false
+	}
+	r.set.Logger.Warn("File fell too far behind, skipping ahead", zap.Int64("skipped_bytes", skipped))
+	r.Offset += skipped
+	return skipped, true
+}
+
+// newDecompressor returns a reader of the decompressed content of r, which reads all the
+// concatenated gzip members or zstd frames of r.
+func newDecompressor(compression string, r io.Reader) (io.ReadCloser, error) {
+	if compression == "zstd" {
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}
+	return gzip.NewReader(r)
+}
+
 // Delete will close and delete the file
 func (r *Reader) delete() {
 	r.close()
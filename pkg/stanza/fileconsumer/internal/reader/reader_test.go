@@ -212,3 +212,86 @@ func TestFlushPeriodEOF(t *testing.T) {
 	r.ReadToEnd(context.Background())
 	sink.ExpectTokens(t, content[0:aContentLength], []byte{'b'})
 }
+
+func TestReadToEndFileLimit(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "testlog1\ntestlog2\ntestlog3\ntestlog4\n")
+
+	f, sink := testFactory(t, withFileLimit(Limit{LinesPerSecond: 2}))
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+
+	r, err := f.NewReader(temp, fp)
+	require.NoError(t, err)
+	defer r.Close()
+
+	r.SetLimits(true, Quota{})
+	result := r.ReadToEnd(context.Background())
+	assert.True(t, result.Throttled)
+
+	// The limit applies to the readers created from the metadata too
+	m := r.Close()
+	r, err = f.NewReaderFromMetadata(filetest.OpenFile(t, temp.Name()), m)
+	require.NoError(t, err)
+	r.SetLimits(true, Quota{})
+	assert.True(t, r.ReadToEnd(context.Background()).Throttled)
+	sink.ExpectTokens(t, []byte("testlog1"), []byte("testlog2"))
+	sink.ExpectNoCalls(t)
+
+	// Reads which aren't limited read to the end
+	r.SetLimits(false, Quota{})
+	assert.False(t, r.ReadToEnd(context.Background()).Throttled)
+	sink.ExpectTokens(t, []byte("testlog3"), []byte("testlog4"))
+}
+
+func TestReadToEndQuota(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "testlog1\ntestlog2\ntestlog3\n")
+
+	f, sink := testFactory(t)
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+
+	r, err := f.NewReader(temp, fp)
+	require.NoError(t, err)
+	defer r.Close()
+
+	// The quota holds a third of the three lines available
+	r.SetLimits(true, NewLimiter(Limit{LinesPerSecond: 3}).Share(3))
+	assert.True(t, r.ReadToEnd(context.Background()).Throttled)
+	sink.ExpectToken(t, []byte("testlog1"))
+	sink.ExpectNoCalls(t)
+}
+
+func TestReadToEndMaxLag(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "testlog1\ntestlog2\ntestlog3\ntestlog4\n")
+
+	f, sink := testFactory(t, withMaxLag(14))
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+
+	r, err := f.NewReader(temp, fp)
+	require.NoError(t, err)
+	defer r.Close()
+
+	// The last 14 bytes hold the end of testlog3, which is dropped
+	result := r.ReadToEnd(context.Background())
+	assert.Equal(t, int64(22), result.SkippedBytes)
+	sink.ExpectToken(t, []byte("testlog4"))
+	sink.ExpectNoCalls(t)
+
+	filetest.WriteString(t, temp, "testlog5\n")
+	result = r.ReadToEnd(context.Background())
+	assert.Zero(t, result.SkippedBytes)
+	sink.ExpectToken(t, []byte("testlog5"))
+}
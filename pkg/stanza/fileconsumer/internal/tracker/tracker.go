@@ -22,6 +22,7 @@ type Tracker interface {
 	LoadMetadata(metadata []*reader.Metadata)
 	CurrentPollFiles() []*reader.Reader
 	PreviousPollFiles() []*reader.Reader
+	TakePreviousFiles(take func(r *reader.Reader) bool) []*reader.Reader
 	ClosePreviousFiles() int
 	EndPoll()
 	EndConsume() int
@@ -101,6 +102,22 @@ func (t *fileTracker) PreviousPollFiles() []*reader.Reader {
 	return t.previousPollFiles.Get()
 }
 
+// TakePreviousFiles removes the readers of the previous poll cycle for which take returns true,
+// so that they're neither read as lost files nor closed at the end of the consume cycle.
+func (t *fileTracker) TakePreviousFiles(take func(r *reader.Reader) bool) []*reader.Reader {
+	var taken []*reader.Reader
+	kept := fileset.New[*reader.Reader](t.maxBatchFiles)
+	for r, _ := t.previousPollFiles.Pop(); r != nil; r, _ = t.previousPollFiles.Pop() {
+		if take(r) {
+			taken = append(taken, r)
+		} else {
+			kept.Add(r)
+		}
+	}
+	t.previousPollFiles = kept
+	return taken
+}
+
 func (t *fileTracker) ClosePreviousFiles() (filesClosed int) {
 	// t.previousPollFiles -> t.knownFiles[0]
 	for r, _ := t.previousPollFiles.Pop(); r != nil; r, _ = t.previousPollFiles.Pop() {
@@ -174,6 +191,10 @@ func (t *noStateTracker) LoadMetadata(_ []*reader.Metadata) {}
 
 func (t *noStateTracker) PreviousPollFiles() []*reader.Reader { return nil }
 
+func (t *noStateTracker) TakePreviousFiles(_ func(r *reader.Reader) bool) []*reader.Reader {
+	return nil
+}
+
 func (t *noStateTracker) ClosePreviousFiles() int { return 0 }
 
 func (t *noStateTracker) EndPoll() {}
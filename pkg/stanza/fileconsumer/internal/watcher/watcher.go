@@ -0,0 +1,275 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/watcher"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/fsnotify/fsnotify"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+)
+
+// Watcher watches the directories in which files matching the include patterns can be found,
+// and collects the paths of the matching files which are created, written to or moved into them,
+// as well as the paths of the ones which are removed or moved away from them.
+// A message is sent to C whenever changes are waiting to be collected with Changes.
+type Watcher struct {
+	C chan struct{}
+
+	set      component.TelemetrySettings
+	include  []pattern
+	match    func(path string) bool
+	notifier *fsnotify.Watcher
+
+	mu      sync.Mutex
+	dirs    map[string]struct{}
+	paths   map[string]struct{}
+	removed map[string]struct{}
+	full    bool
+	warned  bool
+}
+
+// pattern is an include pattern split into the static directory it starts from, and the
+// segments of the remainder of the pattern.
+type pattern struct {
+	base     string
+	segments []string
+}
+
+// New creates a watcher for the directories of the include patterns. Only the paths of the files
+// for which match returns true are collected.
+func New(set component.TelemetrySettings, include []string, match func(path string) bool) (*Watcher, error) {
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	}
+
+	w := &Watcher{
+		C:        make(chan struct{}, 1),
+		set:      set,
+		match:    match,
+		notifier: notifier,
+		dirs:     make(map[string]struct{}),
+		paths:    make(map[string]struct{}),
+		removed:  make(map[string]struct{}),
+	}
+	for _, glob := range include {
+		base, rest := doublestar.SplitPattern(filepath.ToSlash(filepath.Clean(glob)))
+		w.include = append(w.include, pattern{
+			base:     filepath.FromSlash(base),
+			segments: strings.Split(rest, "/"),
+		})
+	}
+	return w, nil
+}
+
+// Watch handles the filesystem events until the context is done, and closes the watcher.
+func (w *Watcher) Watch(ctx context.Context) error {
+	defer w.notifier.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.notifier.Events:
+			if !ok {
+				return nil
+			}
+			w.handle(event)
+		case err, ok := <-w.notifier.Errors:
+			if !ok {
+				return nil
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				// Events were dropped, so the changes can't be known without a full poll.
+				w.set.Logger.Debug("Filesystem events overflowed", zap.Error(err))
+				w.mu.Lock()
+				w.full = true
+				w.mu.Unlock()
+				w.notify()
+				continue
+			}
+			w.set.Logger.Debug("Filesystem watcher error", zap.Error(err))
+		}
+	}
+}
+
+// Sync watches the directories of the include patterns, and the directories leading to each of the
+// matched files. It's called with the result of every full poll, so that directories which couldn't
+// be watched earlier, e.g. because they didn't exist, are eventually watched.
+func (w *Watcher) Sync(matches []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, p := range w.include {
+		w.add(p.base)
+	}
+	for _, match := range matches {
+		for _, p := range w.include {
+			if !isWithin(p.base, match) {
+				continue
+			}
+			for dir := filepath.Dir(match); isWithin(p.base, dir); dir = filepath.Dir(dir) {
+				w.add(dir)
+				if dir == p.base {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Changes returns the paths of the files which changed, and of the files which were removed or moved
+// away, since it was last called. full is true when changes may have been missed, in which case a full
+// poll is required to find them.
+func (w *Watcher) Changes() (paths []string, removed []string, full bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	paths = make([]string, 0, len(w.paths))
+	for path := range w.paths {
+		paths = append(paths, path)
+	}
+	clear(w.paths)
+	removed = make([]string, 0, len(w.removed))
+	for path := range w.removed {
+		removed = append(removed, path)
+	}
+	clear(w.removed)
+	full, w.full = w.full, false
+	return paths, removed, full
+}
+
+func (w *Watcher) handle(event fsnotify.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	switch {
+	case event.Has(fsnotify.Create):
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			w.addTree(event.Name)
+			return
+		}
+		w.changed(event.Name)
+	case event.Has(fsnotify.Write):
+		w.changed(event.Name)
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		if _, ok := w.dirs[event.Name]; ok {
+			// The files of the directory are gone along with it, which only a full poll can tell.
+			delete(w.dirs, event.Name)
+			_ = w.notifier.Remove(event.Name)
+			w.full = true
+			w.notify()
+			return
+		}
+		// The file may have been rotated out of the pattern, in which case
+		// it must be read to the end, without waiting for the next full poll.
+		if w.match(event.Name) {
+			w.removed[event.Name] = struct{}{}
+			w.notify()
+		}
+	}
+}
+
+// addTree watches a directory created under a watched directory, as well as the directories
+// created in it before it was watched. The matching files found in them are changes.
+func (w *Watcher) addTree(root string) {
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return nil
+		case !d.IsDir():
+			w.changed(path)
+			return nil
+		case !w.relevant(path):
+			return filepath.SkipDir
+		}
+		w.add(path)
+		return nil
+	})
+}
+
+// add watches a directory, unless it's already watched.
+func (w *Watcher) add(dir string) {
+	if _, ok := w.dirs[dir]; ok {
+		return
+	}
+	if err := w.notifier.Add(dir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return
+		}
+		// Most likely the limit of watches is reached. Changes in the directory
+		// are still found by the full polls, but later than they'd be otherwise.
+		if !w.warned {
+			w.warned = true
+			w.set.Logger.Warn("Failed to watch directory, changes will be found by polling", zap.String("path", dir), zap.Error(err))
+		} else {
+			w.set.Logger.Debug("Failed to watch directory", zap.String("path", dir), zap.Error(err))
+		}
+		return
+	}
+	w.dirs[dir] = struct{}{}
+}
+
+func (w *Watcher) changed(path string) {
+	if !w.match(path) {
+		return
+	}
+	w.paths[path] = struct{}{}
+	w.notify()
+}
+
+func (w *Watcher) notify() {
+	select {
+	case w.C <- struct{}{}:
+	default:
+	}
+}
+
+// relevant returns true if files matching one of the include patterns can be found in the directory
+// or in its subdirectories.
+func (w *Watcher) relevant(dir string) bool {
+	for _, p := range w.include {
+		if !isWithin(p.base, dir) {
+			continue
+		}
+		rel, _ := filepath.Rel(p.base, dir)
+		if rel == "." || p.matchesDir(strings.Split(filepath.ToSlash(rel), "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDir returns true if the directory segments, relative to the base of the pattern, match
+// the leading segments of the pattern. The last segment of the pattern matches files only.
+func (p pattern) matchesDir(dir []string) bool {
+	for i, segment := range dir {
+		if i >= len(p.segments)-1 {
+			return false
+		}
+		if p.segments[i] == "**" {
+			return true
+		}
+		if ok, _ := doublestar.Match(p.segments[i], segment); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isWithin returns true if path is dir or is located in it.
+func isWithin(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
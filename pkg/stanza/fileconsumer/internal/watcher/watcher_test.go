@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func newTestWatcher(t *testing.T, include ...string) *Watcher {
+	w, err := New(componenttest.NewNopTelemetrySettings(), include, func(path string) bool {
+		return strings.HasSuffix(path, ".log")
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, w.Watch(ctx))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return w
+}
+
+// expectChanges waits for the watcher to notify, and returns the changes collected until then.
+func expectChanges(t *testing.T, w *Watcher, expected ...string) {
+	var paths []string
+	require.Eventually(t, func() bool {
+		select {
+		case <-w.C:
+		default:
+		}
+		changes, _, _ := w.Changes()
+		paths = append(paths, changes...)
+		return len(paths) >= len(expected)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, expected, paths)
+}
+
+func TestWatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	w := newTestWatcher(t, filepath.Join(dir, "*.log"))
+
+	existing := filepath.Join(dir, "existing.log")
+	require.NoError(t, os.WriteFile(existing, []byte("line\n"), 0o600))
+	w.Sync([]string{existing})
+
+	f, err := os.OpenFile(existing, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString("another line\n")
+	require.NoError(t, err)
+	expectChanges(t, w, existing)
+
+	created := filepath.Join(dir, "created.log")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("line\n"), 0o600))
+	require.NoError(t, os.WriteFile(created, []byte("line\n"), 0o600))
+	expectChanges(t, w, created)
+
+	// Moving a file away is reported, so that it's read to the end
+	require.NoError(t, os.Rename(created, filepath.Join(dir, "created.log.1")))
+	var removed []string
+	require.Eventually(t, func() bool {
+		select {
+		case <-w.C:
+		default:
+		}
+		_, paths, _ := w.Changes()
+		removed = append(removed, paths...)
+		return len(removed) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{created}, removed)
+}
+
+func TestWatchCreatedDirectories(t *testing.T) {
+	dir := t.TempDir()
+	w := newTestWatcher(t, filepath.Join(dir, "pods", "*", "*", "*.log"))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "pods"), 0o700))
+	w.Sync(nil)
+
+	// The nested directories are created at once, so some of them are found by walking the
+	// created directory rather than by their own events.
+	container := filepath.Join(dir, "pods", "namespace_pod", "container")
+	require.NoError(t, os.MkdirAll(container, 0o700))
+	first := filepath.Join(container, "0.log")
+	require.NoError(t, os.WriteFile(first, []byte("line\n"), 0o600))
+	expectChanges(t, w, first)
+
+	second := filepath.Join(container, "1.log")
+	require.NoError(t, os.WriteFile(second, []byte("line\n"), 0o600))
+	expectChanges(t, w, second)
+
+	// Directories which can't contain matching files aren't watched
+	require.NoError(t, os.MkdirAll(filepath.Join(container, "too", "deep"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(container, "too", "deep", "2.log"), []byte("line\n"), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	assert.NotContains(t, w.dirs, filepath.Join(container, "too"))
+}
+
+func TestSync(t *testing.T) {
+	dir := t.TempDir()
+	w := newTestWatcher(t, filepath.Join(dir, "**", "*.log"), filepath.Join(dir, "missing", "*.log"))
+
+	nested := filepath.Join(dir, "a", "b")
+	require.NoError(t, os.MkdirAll(nested, 0o700))
+	w.Sync([]string{filepath.Join(nested, "0.log")})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	assert.Len(t, w.dirs, 3)
+	assert.Contains(t, w.dirs, dir)
+	assert.Contains(t, w.dirs, filepath.Join(dir, "a"))
+	assert.Contains(t, w.dirs, nested)
+}
+
+func TestRelevant(t *testing.T) {
+	w, err := New(componenttest.NewNopTelemetrySettings(), []string{
+		filepath.Join("var", "log", "pods", "*", "*", "*.log"),
+		filepath.Join("opt", "app", "**", "*.log"),
+	}, func(string) bool { return true })
+	require.NoError(t, err)
+	defer w.notifier.Close()
+
+	cases := []struct {
+		dir      string
+		expected bool
+	}{
+		{dir: filepath.Join("var", "log", "pods"), expected: true},
+		{dir: filepath.Join("var", "log", "pods", "a"), expected: true},
+		{dir: filepath.Join("var", "log", "pods", "a", "b"), expected: true},
+		{dir: filepath.Join("var", "log", "pods", "a", "b", "c")},
+		{dir: filepath.Join("var", "log")},
+		{dir: filepath.Join("opt", "app", "a", "b", "c", "d"), expected: true},
+		{dir: filepath.Join("opt", "other")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.dir, func(t *testing.T) {
+			assert.Equal(t, tc.expected, w.relevant(tc.dir))
+		})
+	}
+}
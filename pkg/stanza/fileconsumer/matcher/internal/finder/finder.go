@@ -6,6 +6,7 @@ package finder // import "github.com/open-telemetry/opentelemetry-collector-cont
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"slices"
 
 	"github.com/bmatcuk/doublestar/v4"
@@ -51,3 +52,18 @@ func FindFiles(includes []string, excludes []string) ([]string, error) {
 	slices.Sort(keys)
 	return keys, errs
 }
+
+// Match reports whether the path matches any of the include patterns and none of the exclude patterns
+func Match(includes []string, excludes []string, path string) bool {
+	for _, exclude := range excludes {
+		if itMatches, _ := doublestar.PathMatch(filepath.Clean(exclude), path); itMatches {
+			return false
+		}
+	}
+	for _, include := range includes {
+		if itMatches, _ := doublestar.PathMatch(filepath.Clean(include), path); itMatches {
+			return true
+		}
+	}
+	return false
+}
@@ -287,3 +287,48 @@ func BenchmarkFind10kFiles(b *testing.B) {
 
 	benchResult = r
 }
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		path     string
+		expected bool
+	}{
+		{
+			name:     "Included",
+			include:  []string{filepath.Join("var", "log", "*.log")},
+			path:     filepath.Join("var", "log", "a.log"),
+			expected: true,
+		},
+		{
+			name:    "NotIncluded",
+			include: []string{filepath.Join("var", "log", "*.log")},
+			path:    filepath.Join("var", "log", "a.txt"),
+		},
+		{
+			name:     "DoubleStar",
+			include:  []string{filepath.Join("var", "log", "**", "*.log")},
+			path:     filepath.Join("var", "log", "pods", "a", "0.log"),
+			expected: true,
+		},
+		{
+			name:    "Excluded",
+			include: []string{filepath.Join("var", "log", "*.log")},
+			exclude: []string{filepath.Join("var", "log", "b*")},
+			path:    filepath.Join("var", "log", "b.log"),
+		},
+		{
+			name:     "UncleanPattern",
+			include:  []string{"." + string(filepath.Separator) + filepath.Join("log", "*.log")},
+			path:     filepath.Join("log", "a.log"),
+			expected: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Match(tc.include, tc.exclude, tc.path))
+		})
+	}
+}
@@ -180,3 +180,21 @@ func (m Matcher) MatchFiles() ([]string, error) {
 	}
 	return result, errs
 }
+
+// Match reports whether the path matches the include patterns and none of the exclude patterns.
+// The ordering criteria and the exclude_older_than filter select among all the matched files,
+// so they aren't applied, see Filtered.
+func (m Matcher) Match(path string) bool {
+	return finder.Match(m.include, m.exclude, path)
+}
+
+// Filtered reports whether ordering criteria or filters are applied to the matched files, in which case
+// whether a file is matched can't be decided by Match alone.
+func (m Matcher) Filtered() bool {
+	return len(m.filterOpts) > 0
+}
+
+// Include returns the include patterns.
+func (m Matcher) Include() []string {
+	return m.include
+}
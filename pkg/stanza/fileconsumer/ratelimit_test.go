@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileconsumer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/emittest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/filetest"
+)
+
+// testManagerWithTelemetry builds a manager recording its internal telemetry
+func testManagerWithTelemetry(t *testing.T, cfg *Config) (*Manager, *emittest.Sink, componentTestTelemetry) {
+	tt := setupTestTelemetry()
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	set := componenttest.NewNopTelemetrySettings()
+	set.MeterProvider = tt.meterProvider
+	set.LeveledMeterProvider = func(_ configtelemetry.Level) metric.MeterProvider {
+		return tt.meterProvider
+	}
+
+	sink := emittest.NewSink(emittest.WithCallBuffer(1000))
+	m, err := cfg.Build(set, sink.Callback)
+	require.NoError(t, err)
+	t.Cleanup(func() { m.tracker.ClosePreviousFiles() })
+	return m, sink, tt
+}
+
+func TestRateLimitTotalIsShared(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.RateLimit.Total.LinesPerSecond = 10
+	operator, sink, tt := testManagerWithTelemetry(t, cfg)
+
+	noisy := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, noisy, strings.Repeat("noisy\n", 1000))
+	quiet := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, quiet, "quiet\n")
+
+	// The quiet file gets its line through, although the noisy one has more than the limit to read
+	operator.poll(context.Background())
+	tokens := sink.NextTokens(t, 6)
+	assert.Contains(t, tokens, []byte("quiet"))
+	sink.ExpectNoCalls(t)
+
+	tt.assertMetrics(t, []metricdata.Metrics{
+		{
+			Name:        "otelcol_fileconsumer_open_files",
+			Description: "Number of open files",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: false,
+				DataPoints:  []metricdata.DataPoint[int64]{{Value: 2}},
+			},
+		},
+		{
+			Name:        "otelcol_fileconsumer_reading_files",
+			Description: "Number of open files that are being read",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: false,
+				DataPoints:  []metricdata.DataPoint[int64]{{Value: 0}},
+			},
+		},
+		{
+			Name:        "otelcol_fileconsumer_throttled_files",
+			Description: "Number of times reading a file was paused by a rate limit until the next poll",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints:  []metricdata.DataPoint[int64]{{Value: 1}},
+			},
+		},
+	})
+}
+
+func TestRateLimitPerFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.RateLimit.PerFile.LinesPerSecond = 2
+	operator, sink := testManager(t, cfg)
+
+	noisy := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, noisy, "noisy1\nnoisy2\nnoisy3\n")
+	quiet := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, quiet, "quiet1\nquiet2\n")
+
+	operator.poll(context.Background())
+	sink.ExpectTokens(t, []byte("noisy1"), []byte("noisy2"), []byte("quiet1"), []byte("quiet2"))
+	sink.ExpectNoCalls(t)
+}
+
+func TestRateLimitLostFile(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("Moving files while open is unsupported on Windows")
+	}
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.RateLimit.PerFile.LinesPerSecond = 2
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "testlog1\ntestlog2\ntestlog3\ntestlog4\ntestlog5\n")
+	temp.Close()
+
+	operator.poll(context.Background())
+	sink.ExpectTokens(t, []byte("testlog1"), []byte("testlog2"))
+
+	// The file moved out of the pattern is still read within the rate limit
+	require.NoError(t, os.Rename(temp.Name(), filepath.Join(t.TempDir(), "moved.log")))
+	operator.poll(context.Background())
+	sink.ExpectNoCalls(t)
+
+	// and kept open until it's read to the end
+	time.Sleep(1100 * time.Millisecond)
+	operator.poll(context.Background())
+	sink.ExpectTokens(t, []byte("testlog3"), []byte("testlog4"))
+	sink.ExpectNoCalls(t)
+}
+
+func TestRateLimitMaxLag(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.RateLimit.MaxLag = 20
+	operator, sink, tt := testManagerWithTelemetry(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	for i := 0; i < 10; i++ {
+		filetest.WriteString(t, temp, fmt.Sprintf("testlog%d\n", i))
+	}
+
+	// The last 20 bytes hold the end of testlog7, which is dropped
+	operator.poll(context.Background())
+	sink.ExpectTokens(t, []byte("testlog8"), []byte("testlog9"))
+	sink.ExpectNoCalls(t)
+
+	var md metricdata.ResourceMetrics
+	require.NoError(t, tt.reader.Collect(context.Background(), &md))
+	skipped := tt.getMetric("otelcol_fileconsumer_skipped_bytes", md)
+	require.NotNil(t, skipped.Data)
+	assert.Equal(t, int64(70), skipped.Data.(metricdata.Sum[int64]).DataPoints[0].Value)
+}
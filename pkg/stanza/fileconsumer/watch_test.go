@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package fileconsumer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/filetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+// withWatch is a builder-like helper for quickly setting up a config reading files on filesystem events,
+// with a poll interval long enough for the files to never be found by polling during the test
+func (c *Config) withWatch() *Config {
+	c.Watch.Enabled = true
+	c.PollInterval = time.Hour
+	return c
+}
+
+func TestWatchFiles(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir).withWatch()
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	// Files existing at start are found by the initial poll
+	idle := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, idle, "idle1\n")
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "testlog1\n")
+
+	require.NoError(t, operator.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+	sink.ExpectTokens(t, []byte("idle1"), []byte("testlog1"))
+
+	filetest.WriteString(t, temp, "testlog2\n")
+	sink.ExpectToken(t, []byte("testlog2"))
+
+	created := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, created, "created1\n")
+	sink.ExpectToken(t, []byte("created1"))
+
+	// Files which weren't changed since they were closed by the tracker are read from their offset
+	filetest.WriteString(t, temp, "testlog3\n")
+	sink.ExpectToken(t, []byte("testlog3"))
+	filetest.WriteString(t, idle, "idle2\n")
+	sink.ExpectToken(t, []byte("idle2"))
+	sink.ExpectNoCallsUntil(t, 200*time.Millisecond)
+}
+
+func TestWatchRotatedOutOfPattern(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().withWatch()
+	cfg.Include = append(cfg.Include, fmt.Sprintf("%s/*.log", tempDir))
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	require.NoError(t, operator.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	originalFile := filetest.OpenTempWithPattern(t, tempDir, "*.log")
+	originalFileName := originalFile.Name()
+	filetest.WriteString(t, originalFile, "testlog1\n")
+	sink.ExpectToken(t, []byte("testlog1"))
+
+	// write more log and move the file so it no longer matches
+	filetest.WriteString(t, originalFile, "testlog2\n")
+	require.NoError(t, os.Rename(originalFileName, originalFileName+".old"))
+
+	newFile := filetest.OpenFile(t, originalFileName)
+	filetest.WriteString(t, newFile, "testlog3\n")
+
+	sink.ExpectTokens(t, []byte("testlog2"), []byte("testlog3"))
+}
+
+func TestWatchMovedOutOfPattern(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().withWatch()
+	cfg.Include = append(cfg.Include, fmt.Sprintf("%s/*.log", tempDir))
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	require.NoError(t, operator.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	file := filetest.OpenTempWithPattern(t, tempDir, "*.log")
+	filetest.WriteString(t, file, "testlog1\n")
+	sink.ExpectToken(t, []byte("testlog1"))
+
+	// The tail written after the move, without any file taking its place, is read without waiting for the poll
+	require.NoError(t, os.Rename(file.Name(), file.Name()+".old"))
+	filetest.WriteString(t, file, "testlog2\n")
+	sink.ExpectToken(t, []byte("testlog2"))
+}
+
+func TestWatchCreatedDirectories(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().withWatch()
+	cfg.Include = append(cfg.Include, filepath.Join(tempDir, "*", "*", "*.log"))
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	require.NoError(t, operator.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	dir := filepath.Join(tempDir, "pod", "container")
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	temp := filetest.OpenFile(t, filepath.Join(dir, "0.log"))
+	filetest.WriteString(t, temp, "testlog1\n")
+	sink.ExpectToken(t, []byte("testlog1"))
+
+	filetest.WriteString(t, temp, "testlog2\n")
+	sink.ExpectToken(t, []byte("testlog2"))
+}
+
+func TestWatchWithOrderingCriteria(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().withWatch()
+	cfg.Include = append(cfg.Include, fmt.Sprintf("%s/*.log", tempDir))
+	cfg.OrderingCriteria.TopN = 1
+	cfg.OrderingCriteria.Regex = `(?P<value>\d+)\.log`
+	cfg.OrderingCriteria.SortBy = []matcher.Sort{{SortType: "numeric", RegexKey: "value"}}
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	require.NoError(t, operator.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	// Events trigger full polls, so that only the selected file is read
+	filetest.WriteString(t, filetest.OpenFile(t, filepath.Join(tempDir, "1.log")), "testlog1\n")
+	sink.ExpectToken(t, []byte("testlog1"))
+	filetest.WriteString(t, filetest.OpenFile(t, filepath.Join(tempDir, "2.log")), "testlog2\n")
+	sink.ExpectToken(t, []byte("testlog2"))
+
+	filetest.WriteString(t, filetest.OpenFile(t, filepath.Join(tempDir, "1.log")), "testlog3\n")
+	sink.ExpectNoCallsUntil(t, 200*time.Millisecond)
+}
+
+func TestWatchThrottledFilesAreReadAgain(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir).withWatch()
+	cfg.StartAt = "beginning"
+	cfg.RateLimit.PerFile.LinesPerSecond = 5
+	operator, sink := testManager(t, cfg)
+
+	require.NoError(t, operator.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	// The file isn't written to after the first lines are read, but it's read again until the end
+	temp := filetest.OpenTemp(t, tempDir)
+	for i := 0; i < 8; i++ {
+		filetest.WriteString(t, temp, fmt.Sprintf("testlog%d\n", i))
+	}
+	for i := 0; i < 8; i++ {
+		sink.ExpectToken(t, []byte(fmt.Sprintf("testlog%d", i)))
+	}
+}
+
+func TestPollChangedKeepsUnchangedReaders(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	idle := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, idle, "idle1\n")
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "testlog1\n")
+
+	operator.poll(context.Background())
+	sink.ExpectTokens(t, []byte("idle1"), []byte("testlog1"))
+	previous := operator.tracker.PreviousPollFiles()
+	require.Len(t, previous, 2)
+	idleReader := previous[slices.IndexFunc(previous, func(r *reader.Reader) bool { return r.GetFileName() == idle.Name() })]
+
+	// The reader of the file which wasn't changed is kept open as it is, rather than read as a lost file
+	for i := 2; i < 5; i++ {
+		filetest.WriteString(t, temp, fmt.Sprintf("testlog%d\n", i))
+		operator.pollChanged(context.Background(), []string{temp.Name()}, nil)
+		sink.ExpectToken(t, []byte(fmt.Sprintf("testlog%d", i)))
+
+		previous = operator.tracker.PreviousPollFiles()
+		require.Len(t, previous, 2)
+		require.Contains(t, previous, idleReader)
+	}
+
+	filetest.WriteString(t, idle, "idle2\n")
+	operator.pollChanged(context.Background(), []string{idle.Name()}, nil)
+	sink.ExpectToken(t, []byte("idle2"))
+	sink.ExpectNoCalls(t)
+	require.Len(t, operator.tracker.PreviousPollFiles(), 2)
+	require.Equal(t, 2, operator.tracker.ClosePreviousFiles())
+}
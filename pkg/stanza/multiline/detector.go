@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package multiline // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline"
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// State is the state of a detector between two lines. The zero value is Start.
+type State string
+
+// Start is the state of a detector outside of multiline entries, where no line continues the previous one.
+const Start State = ""
+
+// rule moves a detector from one of the from states to the to state, when a line matches the pattern.
+type rule struct {
+	from    []State
+	pattern *regexp.Regexp
+	to      State
+}
+
+func newRule(from []State, pattern string, to State) rule {
+	return rule{from: from, pattern: regexp.MustCompile(pattern), to: to}
+}
+
+// Detector is a state machine recognizing the lines of multiline log entries, such as stack traces.
+// It holds no state of its own, so it can be shared between sources, each keeping track of its own State.
+type Detector struct {
+	name  string
+	rules []rule
+}
+
+// Name returns the name of the detector
+func (d *Detector) Name() string {
+	return d.name
+}
+
+// Next returns the state following line, and whether line continues the entry of the previous lines,
+// given the state following them. The entry including line may continue on the next line unless the
+// returned state is Start.
+func (d *Detector) Next(state State, line []byte) (State, bool) {
+	if state != Start {
+		for _, r := range d.rules {
+			if slices.Contains(r.from, state) && r.pattern.Match(line) {
+				return r.to, true
+			}
+		}
+	}
+	for _, r := range d.rules {
+		if slices.Contains(r.from, Start) && r.pattern.Match(line) {
+			return r.to, false
+		}
+	}
+	return Start, false
+}
+
+var detectors = map[string]*Detector{
+	"dotnet": {name: "dotnet", rules: dotnetRules},
+	"go":     {name: "go", rules: goRules},
+	"java":   {name: "java", rules: javaRules},
+	"nodejs": {name: "nodejs", rules: nodejsRules},
+	"python": {name: "python", rules: pythonRules},
+}
+
+// Names returns the names of the built-in detectors
+func Names() []string {
+	names := make([]string, 0, len(detectors))
+	for name := range detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the built-in detector with the given name
+func Lookup(name string) (*Detector, error) {
+	d, ok := detectors[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown multiline detector %q, must be one of %s", name, strings.Join(Names(), ", "))
+	}
+	return d, nil
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package multiline
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline/multilinetest"
+)
+
+func TestDetectors(t *testing.T) {
+	for _, name := range Names() {
+		d, err := Lookup(name)
+		require.NoError(t, err)
+		require.Equal(t, name, d.Name())
+
+		fixtures, err := filepath.Glob(filepath.Join("testdata", name, "*.log"))
+		require.NoError(t, err)
+		require.NotEmpty(t, fixtures)
+
+		for _, fixture := range fixtures {
+			t.Run(name+"/"+filepath.Base(fixture), func(t *testing.T) {
+				input, expected := multilinetest.ReadFixture(t, fixture)
+
+				var entries []string
+				var entry [][]byte
+				state := Start
+				for _, line := range bytes.Split(bytes.TrimSuffix(input, []byte("\n")), []byte("\n")) {
+					var continues bool
+					state, continues = d.Next(state, line)
+					if !continues && entry != nil {
+						entries = append(entries, string(bytes.Join(entry, []byte("\n"))))
+						entry = nil
+					}
+					entry = append(entry, line)
+				}
+				entries = append(entries, string(bytes.Join(entry, []byte("\n"))))
+
+				assert.Equal(t, expected, entries)
+			})
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	_, err := Lookup("cobol")
+	assert.EqualError(t, err, `unknown multiline detector "cobol", must be one of dotnet, go, java, nodejs, python`)
+}
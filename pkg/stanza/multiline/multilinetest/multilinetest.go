@@ -0,0 +1,26 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package multilinetest // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline/multilinetest"
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// EntrySeparator is the line separating the expected entries of a fixture
+const EntrySeparator = "====="
+
+// ReadFixture reads a file of log entries separated by EntrySeparator lines. It returns the
+// entries as they are written to a log, one after the other, and the entries expected to be
+// recombined from them.
+func ReadFixture(t testing.TB, path string) ([]byte, []string) {
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	entries := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n"+EntrySeparator+"\n")
+	return []byte(strings.Join(entries, "\n") + "\n"), entries
+}
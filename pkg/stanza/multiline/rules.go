@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package multiline // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline"
+
+// The rules of each detector are tried in order, so more specific patterns come first.
+
+const (
+	dotnetException State = "dotnet_exception"
+	dotnetFrames    State = "dotnet_frames"
+)
+
+var dotnetRules = []rule{
+	newRule([]State{Start}, `(?:Exception|Error)(?::|$)`, dotnetException),
+	newRule([]State{dotnetException, dotnetFrames}, `^\s+at `, dotnetFrames),
+	newRule([]State{dotnetException, dotnetFrames}, `^\s*---> `, dotnetException),
+	newRule([]State{dotnetFrames}, `^\s*--- End of .* ---$`, dotnetFrames),
+}
+
+const (
+	goPanic State = "go_panic"
+	goBlank State = "go_blank"
+	goFunc  State = "go_func"
+	goFile  State = "go_file"
+)
+
+var goRules = []rule{
+	newRule([]State{Start}, `^(?:panic: |fatal error: |http: panic serving )`, goPanic),
+	newRule([]State{goPanic}, `^(?:\[signal |\s)`, goPanic),
+	newRule([]State{goPanic, goFunc}, `^$`, goBlank),
+	newRule([]State{goBlank}, `^goroutine \d+ \[[^\]]+\]:$`, goFunc),
+	newRule([]State{goFunc}, `^\.\.\.additional frames elided\.\.\.$`, goFunc),
+	newRule([]State{goFunc}, `^exit status \d+$`, Start),
+	newRule([]State{goFunc}, `^(?:created by \S+.*|\S+\(.*\))$`, goFile),
+	newRule([]State{goFile}, `^\t`, goFunc),
+}
+
+const (
+	javaException State = "java_exception"
+	javaFrames    State = "java_frames"
+)
+
+var javaRules = []rule{
+	newRule([]State{Start}, `(?:Exception|Error|Throwable)(?::|$)`, javaException),
+	newRule([]State{javaException, javaFrames}, `^\s+(?:eval )?at `, javaFrames),
+	newRule([]State{javaException, javaFrames}, `^\s*\.\.\. \d+ (?:more|common frames omitted)$`, javaFrames),
+	newRule([]State{javaException, javaFrames}, `^\s*(?:Caused by|Suppressed): `, javaException),
+}
+
+const (
+	nodejsSource    State = "nodejs_source"
+	nodejsSourceEnd State = "nodejs_source_end"
+	nodejsError     State = "nodejs_error"
+	nodejsFrames    State = "nodejs_frames"
+	nodejsProps     State = "nodejs_props"
+	nodejsTrailer   State = "nodejs_trailer"
+)
+
+const nodejsErrorPattern = `^(?:Uncaught )?[\w$.]*(?:Error|Exception)(?: \[\w+\])?(?::|$)`
+
+var nodejsRules = []rule{
+	// Uncaught errors are preceded by the line of code throwing them
+	newRule([]State{Start}, `^(?:file://|node:)?(?:/|[A-Za-z]:\\)?\S+\.[cm]?[jt]s:\d+$`, nodejsSource),
+	newRule([]State{nodejsSource}, `^\s+\S`, nodejsSource),
+	newRule([]State{nodejsSource}, `^$`, nodejsSourceEnd),
+	newRule([]State{nodejsSourceEnd}, nodejsErrorPattern, nodejsError),
+	newRule([]State{Start}, nodejsErrorPattern, nodejsError),
+	// Errors having properties are followed by them, between braces opened on the last frame
+	newRule([]State{nodejsError, nodejsFrames}, `^\s+at .* \{$`, nodejsProps),
+	newRule([]State{nodejsError, nodejsFrames}, `^\s+at `, nodejsFrames),
+	newRule([]State{nodejsFrames}, `^\s+\.\.\. \d+ lines matching cause stack trace \.\.\.$`, nodejsFrames),
+	newRule([]State{nodejsProps}, `^\s+`, nodejsProps),
+	newRule([]State{nodejsProps}, `^\}$`, nodejsTrailer),
+	newRule([]State{nodejsError, nodejsFrames, nodejsTrailer}, `^$`, nodejsTrailer),
+	newRule([]State{nodejsTrailer}, `^Node\.js v\d`, Start),
+}
+
+const (
+	pythonTraceback      State = "python_traceback"
+	pythonFrames         State = "python_frames"
+	pythonException      State = "python_exception"
+	pythonChainSeparator State = "python_chain_separator"
+	pythonChain          State = "python_chain"
+	pythonChainEnd       State = "python_chain_end"
+)
+
+const pythonTracebackPattern = `^Traceback \(most recent call last\):$`
+
+var pythonRules = []rule{
+	newRule([]State{Start}, pythonTracebackPattern, pythonTraceback),
+	newRule([]State{pythonTraceback, pythonFrames}, `^\s+\S`, pythonFrames),
+	newRule([]State{pythonFrames}, `^[\w.]+(?::.*)?$`, pythonException),
+	// Chained exceptions are separated by a message between empty lines
+	newRule([]State{pythonException}, `^$`, pythonChainSeparator),
+	newRule([]State{pythonChainSeparator}, `^(?:During handling of the above exception, another exception occurred|The above exception was the direct cause of the following exception):$`, pythonChain),
+	newRule([]State{pythonChain}, `^$`, pythonChainEnd),
+	newRule([]State{pythonChainEnd}, pythonTracebackPattern, pythonTraceback),
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gelf // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/gelf"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/udp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/gelf"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
+)
+
+const operatorType = "gelf_input"
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
+}
+
+// NewConfig creates a new input config with default values
+func NewConfig() *Config {
+	return NewConfigWithID(operatorType)
+}
+
+// NewConfigWithID creates a new input config with default values
+func NewConfigWithID(operatorID string) *Config {
+	return &Config{
+		InputConfig: helper.NewInputConfig(operatorID, operatorType),
+		BaseConfig:  gelf.NewConfig().BaseConfig,
+	}
+}
+
+type Config struct {
+	helper.InputConfig `mapstructure:",squash"`
+	gelf.BaseConfig    `mapstructure:",squash"`
+	TCP                *tcp.BaseConfig `mapstructure:"tcp"`
+	UDP                *udp.BaseConfig `mapstructure:"udp"`
+}
+
+func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error) {
+	inputBase, err := c.InputConfig.Build(set)
+	if err != nil {
+		return nil, err
+	}
+
+	gelfParserCfg := gelf.NewConfigWithID(inputBase.ID() + "_internal_parser")
+	gelfParserCfg.BaseConfig = c.BaseConfig
+	gelfParserCfg.OutputIDs = c.OutputIDs
+	gelfParser, err := gelfParserCfg.Build(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve gelf config: %w", err)
+	}
+
+	if c.TCP != nil {
+		tcpInputCfg := tcp.NewConfigWithID(inputBase.ID() + "_internal_tcp")
+		tcpInputCfg.InputConfig.AttributerConfig = c.InputConfig.AttributerConfig
+		tcpInputCfg.InputConfig.IdentifierConfig = c.InputConfig.IdentifierConfig
+		tcpInputCfg.BaseConfig = *c.TCP
+		// GELF messages sent over TCP are uncompressed and delimited by null bytes.
+		tcpInputCfg.SplitFuncBuilder = NullSplitFuncBuilder
+
+		tcpInput, err := tcpInputCfg.Build(set)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tcp config: %w", err)
+		}
+
+		tcpInput.SetOutputIDs([]string{gelfParser.ID()})
+		if err := tcpInput.SetOutputs([]operator.Operator{gelfParser}); err != nil {
+			return nil, fmt.Errorf("failed to set outputs")
+		}
+
+		return &Input{
+			InputOperator: inputBase,
+			tcp:           tcpInput.(*tcp.Input),
+			parser:        gelfParser.(*gelf.Parser),
+		}, nil
+	}
+
+	if c.UDP != nil {
+		udpInputCfg := udp.NewConfigWithID(inputBase.ID() + "_internal_udp")
+		udpInputCfg.InputConfig.AttributerConfig = c.InputConfig.AttributerConfig
+		udpInputCfg.InputConfig.IdentifierConfig = c.InputConfig.IdentifierConfig
+		udpInputCfg.BaseConfig = *c.UDP
+
+		// GELF messages sent over UDP are one per packet, and may be chunked and compressed:
+		// the packets are passed untouched to the parser.
+		udpInputCfg.OneLogPerPacket = true
+		udpInputCfg.Encoding = "nop"
+		udpInputCfg.SplitConfig = split.Config{}
+		udpInputCfg.TrimConfig.PreserveLeading = true
+		udpInputCfg.TrimConfig.PreserveTrailing = true
+		udpInputCfg.PreserveTrailingBytes = true
+
+		udpInput, err := udpInputCfg.Build(set)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve udp config: %w", err)
+		}
+
+		udpInput.SetOutputIDs([]string{gelfParser.ID()})
+		if err := udpInput.SetOutputs([]operator.Operator{gelfParser}); err != nil {
+			return nil, fmt.Errorf("failed to set outputs")
+		}
+
+		return &Input{
+			InputOperator: inputBase,
+			udp:           udpInput.(*udp.Input),
+			parser:        gelfParser.(*gelf.Parser),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("need tcp config or udp config")
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gelf
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/udp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/operatortest"
+)
+
+func TestUnmarshal(t *testing.T) {
+	operatortest.ConfigUnmarshalTests{
+		DefaultConfig: NewConfig(),
+		TestsFile:     filepath.Join(".", "testdata", "config.yaml"),
+		Tests: []operatortest.ConfigUnmarshalTest{
+			{
+				Name:      "default",
+				ExpectErr: false,
+				Expect:    NewConfig(),
+			},
+			{
+				Name:      "tcp",
+				ExpectErr: false,
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.ChunkTimeout = 10 * time.Second
+					cfg.TCP = &tcp.BaseConfig{
+						MaxLogSize:    1000000,
+						ListenAddress: "10.0.0.1:12201",
+						AddAttributes: true,
+					}
+					return cfg
+				}(),
+			},
+			{
+				Name:      "udp",
+				ExpectErr: false,
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.ChunkTimeout = 10 * time.Second
+					cfg.MaxMessageSize = helper.ByteSize(64 * 1024)
+					cfg.UDP = &udp.BaseConfig{
+						ListenAddress: "10.0.0.1:12201",
+						AddAttributes: true,
+					}
+					return cfg
+				}(),
+			},
+		},
+	}.Run(t)
+}
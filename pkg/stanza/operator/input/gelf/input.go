@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gelf // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/gelf"
+
+import (
+	"bufio"
+	"bytes"
+
+	"golang.org/x/text/encoding"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/udp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/gelf"
+)
+
+// Input is an operator that listens for GELF messages over tcp or udp.
+type Input struct {
+	helper.InputOperator
+	tcp    *tcp.Input
+	udp    *udp.Input
+	parser *gelf.Parser
+}
+
+// Start will start listening for GELF messages over tcp or udp.
+func (i *Input) Start(p operator.Persister) error {
+	if i.tcp != nil {
+		return i.tcp.Start(p)
+	}
+	return i.udp.Start(p)
+}
+
+// Stop will stop listening for messages.
+func (i *Input) Stop() error {
+	if i.tcp != nil {
+		return i.tcp.Stop()
+	}
+	return i.udp.Stop()
+}
+
+// SetOutputs will set the outputs of the internal GELF parser.
+func (i *Input) SetOutputs(operators []operator.Operator) error {
+	i.parser.SetOutputIDs(i.GetOutputIDs())
+	return i.parser.SetOutputs(operators)
+}
+
+// NullSplitFuncBuilder builds a split func for the GELF messages sent over tcp, which are
+// delimited by null bytes.
+func NullSplitFuncBuilder(_ encoding.Encoding) (bufio.SplitFunc, error) {
+	return newNullSplitFunc(true), nil
+}
+
+func newNullSplitFunc(flushAtEOF bool) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, 0); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		// Flush if no more data is expected
+		if len(data) != 0 && atEOF && flushAtEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
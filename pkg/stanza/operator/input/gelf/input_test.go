@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/udp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/pipeline"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split/splittest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+const testMessage = `{"version":"1.1","host":"web-1","short_message":"Backend timeout","level":3,"_request_id":"abc"}`
+
+func expectEntry(t *testing.T, fake *testutil.FakeOutput) {
+	select {
+	case e := <-fake.Received:
+		require.Equal(t, "Backend timeout", e.Body)
+		require.Equal(t, entry.Error, e.Severity)
+		require.Equal(t, map[string]any{"host": "web-1", "request_id": "abc"}, e.Attributes)
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for entry to be processed")
+	}
+}
+
+func startPipeline(t *testing.T, cfg *Config) *testutil.FakeOutput {
+	set := componenttest.NewNopTelemetrySettings()
+	op, err := cfg.Build(set)
+	require.NoError(t, err)
+
+	fake := testutil.NewFakeOutput(t)
+	p, err := pipeline.NewDirectedPipeline([]operator.Operator{op, fake})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(testutil.NewUnscopedMockPersister()))
+	t.Cleanup(func() {
+		require.NoError(t, p.Stop())
+	})
+	return fake
+}
+
+func TestInputTCP(t *testing.T) {
+	cfg := NewConfigWithID("test_gelf")
+	cfg.TCP = &tcp.NewConfigWithID("test_gelf_tcp").BaseConfig
+	cfg.TCP.ListenAddress = ":12202"
+	cfg.OutputIDs = []string{"fake"}
+	fake := startPipeline(t, cfg)
+
+	conn, err := net.Dial("tcp", cfg.TCP.ListenAddress)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte(testMessage + "\x00" + testMessage + "\x00"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	expectEntry(t, fake)
+	expectEntry(t, fake)
+}
+
+func TestInputUDP(t *testing.T) {
+	cfg := NewConfigWithID("test_gelf")
+	cfg.UDP = &udp.NewConfigWithID("test_gelf_udp").BaseConfig
+	cfg.UDP.ListenAddress = ":12203"
+	cfg.OutputIDs = []string{"fake"}
+	fake := startPipeline(t, cfg)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(testMessage))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	compressed := buf.Bytes()
+
+	conn, err := net.Dial("udp", cfg.UDP.ListenAddress)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// An uncompressed message, then a gzip message split in two chunks sent out of order.
+	_, err = conn.Write([]byte(testMessage))
+	require.NoError(t, err)
+	expectEntry(t, fake)
+
+	half := len(compressed) / 2
+	id := []byte("abcdefgh")
+	second := append(append([]byte{0x1e, 0x0f}, id...), 1, 2)
+	_, err = conn.Write(append(second, compressed[half:]...))
+	require.NoError(t, err)
+	first := append(append([]byte{0x1e, 0x0f}, id...), 0, 2)
+	_, err = conn.Write(append(first, compressed[:half]...))
+	require.NoError(t, err)
+	expectEntry(t, fake)
+}
+
+func TestGELFIDs(t *testing.T) {
+	t.Run("TCP", func(t *testing.T) {
+		cfg := NewConfigWithID("test_gelf")
+		cfg.TCP = &tcp.NewConfigWithID("test_gelf_tcp").BaseConfig
+		cfg.TCP.ListenAddress = ":12202"
+		cfg.OutputIDs = []string{"fake"}
+		op, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+		require.NoError(t, err)
+		gelfInputOp := op.(*Input)
+		require.Equal(t, "test_gelf_internal_tcp", gelfInputOp.tcp.ID())
+		require.Equal(t, "test_gelf_internal_parser", gelfInputOp.parser.ID())
+		require.Equal(t, []string{gelfInputOp.parser.ID()}, gelfInputOp.tcp.GetOutputIDs())
+		require.Equal(t, []string{"fake"}, gelfInputOp.parser.GetOutputIDs())
+	})
+	t.Run("UDP", func(t *testing.T) {
+		cfg := NewConfigWithID("test_gelf")
+		cfg.UDP = &udp.NewConfigWithID("test_gelf_udp").BaseConfig
+		cfg.UDP.ListenAddress = ":12203"
+		cfg.OutputIDs = []string{"fake"}
+		op, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+		require.NoError(t, err)
+		gelfInputOp := op.(*Input)
+		require.Equal(t, "test_gelf_internal_udp", gelfInputOp.udp.ID())
+		require.Equal(t, "test_gelf_internal_parser", gelfInputOp.parser.ID())
+		require.Equal(t, []string{gelfInputOp.parser.ID()}, gelfInputOp.udp.GetOutputIDs())
+		require.Equal(t, []string{"fake"}, gelfInputOp.parser.GetOutputIDs())
+	})
+	t.Run("NoListener", func(t *testing.T) {
+		_, err := NewConfigWithID("test_gelf").Build(componenttest.NewNopTelemetrySettings())
+		require.EqualError(t, err, "need tcp config or udp config")
+	})
+}
+
+func TestNullSplitFunc(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input []byte
+		steps []splittest.Step
+	}{
+		{
+			name:  "OneMessage",
+			input: []byte("{\"short_message\":\"a\"}\x00"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(22, `{"short_message":"a"}`),
+			},
+		},
+		{
+			name:  "TwoMessages",
+			input: []byte("{\"short_message\":\"a\"}\x00{\"short_message\":\"b\"}\x00"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(22, `{"short_message":"a"}`),
+				splittest.ExpectAdvanceToken(22, `{"short_message":"b"}`),
+			},
+		},
+		{
+			name:  "NoDelimiter",
+			input: []byte(`{"short_message":"a"}`),
+			steps: []splittest.Step{
+				splittest.ExpectToken(`{"short_message":"a"}`),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		splitFunc, err := NullSplitFuncBuilder(nil)
+		require.NoError(t, err)
+		t.Run(tc.name, splittest.New(splitFunc, tc.input, tc.steps...))
+	}
+}
@@ -72,6 +72,11 @@ type BaseConfig struct {
 	SplitConfig     split.Config `mapstructure:"multiline,omitempty"`
 	TrimConfig      trim.Config  `mapstructure:",squash"`
 	AsyncConfig     *AsyncConfig `mapstructure:"async,omitempty"`
+
+	// PreserveTrailingBytes disables the removal of trailing control characters and NULs
+	// from received packets. It is meant for operators built on top of the udp input
+	// which receive binary payloads, and cannot be set through the configuration.
+	PreserveTrailingBytes bool `mapstructure:"-"`
 }
 
 // Build will build a udp input operator.
@@ -129,6 +134,8 @@ func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error
 		resolver:        resolver,
 		OneLogPerPacket: c.OneLogPerPacket,
 		AsyncConfig:     c.AsyncConfig,
+
+		preserveTrailingBytes: c.PreserveTrailingBytes,
 	}
 
 	if c.AsyncConfig != nil {
@@ -41,6 +41,8 @@ type Input struct {
 	messageQueue   chan messageAndAddress
 	readBufferPool sync.Pool
 	stopOnce       sync.Once
+
+	preserveTrailingBytes bool
 }
 
 type messageAndAddress struct {
@@ -236,8 +238,12 @@ func (i *Input) readMessage(buffer []byte) ([]byte, net.Addr, int, error) {
 	return buffer, addr, n, nil
 }
 
-// This will remove trailing characters and NULs from the buffer
+// This will remove trailing characters and NULs from the buffer, unless they must be preserved
 func (i *Input) removeTrailingCharactersAndNULsFromBuffer(buffer []byte, n int) []byte {
+	if i.preserveTrailingBytes {
+		return buffer[:n]
+	}
+
 	// Remove trailing characters and NULs
 	for ; (n > 0) && (buffer[n-1] < 32); n-- { // nolint
 	}
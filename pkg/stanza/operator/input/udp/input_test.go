@@ -16,6 +16,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
 
@@ -154,6 +155,18 @@ func TestInput(t *testing.T) {
 	t.Run("SimpleAsync", udpInputTest([]byte("message1"), []string{"message1"}, cfg))
 }
 
+func TestInputPreserveTrailingBytes(t *testing.T) {
+	cfg := NewConfigWithID("test_input")
+	cfg.ListenAddress = ":0"
+	cfg.OneLogPerPacket = true
+	cfg.Encoding = "nop"
+	cfg.SplitConfig = split.Config{}
+	cfg.PreserveTrailingBytes = true
+
+	t.Run("TrailingNewlines", udpInputTest([]byte("message1\n"), []string{"message1\n"}, cfg))
+	t.Run("TrailingBinary", udpInputTest([]byte{0x1f, 0x8b, 0x00, 0x01}, []string{"\x1f\x8b\x00\x01"}, cfg))
+}
+
 func TestInputAttributes(t *testing.T) {
 	t.Run("Simple", udpInputAttributesTest([]byte("message1"), []string{"message1"}))
 	t.Run("TrailingNewlines", udpInputAttributesTest([]byte("message1\n"), []string{"message1"}))
@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cef
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+)
+
+const testMessage = "<134>Sep 19 08:26:10 host CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dpt=443 msg=blocked the \\= sign"
+
+func newTestParser(t *testing.T) *Parser {
+	cfg := NewConfigWithID("test")
+	set := componenttest.NewNopTelemetrySettings()
+	op, err := cfg.Build(set)
+	require.NoError(t, err)
+	return op.(*Parser)
+}
+
+func TestInit(t *testing.T) {
+	builder, ok := operator.DefaultRegistry.Lookup("cef_parser")
+	require.True(t, ok, "expected cef_parser to be registered")
+	require.Equal(t, "cef_parser", builder().Type())
+}
+
+func TestParserBuildFailure(t *testing.T) {
+	cfg := NewConfigWithID("test")
+	cfg.OnError = "invalid_on_error"
+	set := componenttest.NewNopTelemetrySettings()
+	_, err := cfg.Build(set)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid `on_error` field")
+}
+
+func TestParserInvalidType(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parse([]int{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "type '[]int' cannot be parsed as CEF")
+}
+
+func TestParserInvalidMessage(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parse("not a cef message")
+	require.EqualError(t, err, "not a CEF message: missing CEF: prefix")
+}
+
+func TestProcess(t *testing.T) {
+	cases := []struct {
+		name   string
+		op     func() (operator.Operator, error)
+		input  *entry.Entry
+		expect *entry.Entry
+	}{
+		{
+			"default",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Body: testMessage,
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"version":               int64(0),
+					"device_vendor":         "Security",
+					"device_product":        "threatmanager",
+					"device_version":        "1.0",
+					"device_event_class_id": "100",
+					"name":                  "worm successfully stopped",
+					"severity":              int64(10),
+					"extensions": map[string]any{
+						"src": "10.0.0.1",
+						"dpt": int64(443),
+						"msg": "blocked the = sign",
+					},
+				},
+				Body: testMessage,
+			},
+		},
+		{
+			"semantic_conventions",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.SemanticConventions = true
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Body: testMessage,
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"version":               int64(0),
+					"device_vendor":         "Security",
+					"device_product":        "threatmanager",
+					"device_version":        "1.0",
+					"device_event_class_id": "100",
+					"name":                  "worm successfully stopped",
+					"severity":              int64(10),
+					"source.address":        "10.0.0.1",
+					"destination.port":      int64(443),
+					"extensions": map[string]any{
+						"msg": "blocked the = sign",
+					},
+				},
+				Body: testMessage,
+			},
+		},
+		{
+			"parse_from_and_to",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.ParseFrom = entry.NewAttributeField("message")
+				cfg.ParseTo = entry.RootableField{Field: entry.NewBodyField()}
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"message": "CEF:0|Vendor|Product|1.0|100|name|Low|",
+				},
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"message": "CEF:0|Vendor|Product|1.0|100|name|Low|",
+				},
+				Body: map[string]any{
+					"version":               int64(0),
+					"device_vendor":         "Vendor",
+					"device_product":        "Product",
+					"device_version":        "1.0",
+					"device_event_class_id": "100",
+					"name":                  "name",
+					"severity":              "Low",
+					"extensions":            map[string]any{},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, err := tc.op()
+			require.NoError(t, err, "did not expect operator function to return an error, this is a bug with the test case")
+
+			err = op.Process(context.Background(), tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, tc.input)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gelf // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/gelf"
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const (
+	operatorType = "gelf_parser"
+
+	// DefaultChunkTimeout is the time Graylog waits for the chunks of a message.
+	DefaultChunkTimeout = 5 * time.Second
+
+	// DefaultMaxMessageSize is the maximum size of a decompressed message.
+	DefaultMaxMessageSize = 1024 * 1024
+)
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
+}
+
+// NewConfig creates a new GELF parser config with default values.
+func NewConfig() *Config {
+	return NewConfigWithID(operatorType)
+}
+
+// NewConfigWithID creates a new GELF parser config with default values.
+func NewConfigWithID(operatorID string) *Config {
+	return &Config{
+		TransformerConfig: helper.NewTransformerConfig(operatorID, operatorType),
+		BaseConfig: BaseConfig{
+			ChunkTimeout:   DefaultChunkTimeout,
+			MaxMessageSize: DefaultMaxMessageSize,
+		},
+	}
+}
+
+// Config is the configuration of a GELF parser operator.
+type Config struct {
+	helper.TransformerConfig `mapstructure:",squash"`
+	BaseConfig               `mapstructure:",squash"`
+}
+
+// BaseConfig is the detailed configuration of a GELF parser operator.
+type BaseConfig struct {
+	// ChunkTimeout is the time the chunks of a message are kept, the messages missing chunks
+	// after this time are dropped.
+	ChunkTimeout time.Duration `mapstructure:"chunk_timeout,omitempty"`
+
+	// MaxMessageSize is the maximum size of a message, once its chunks are reassembled and
+	// it's decompressed.
+	MaxMessageSize helper.ByteSize `mapstructure:"max_message_size,omitempty"`
+}
+
+// Build will build a GELF parser operator.
+func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error) {
+	transformerOperator, err := c.TransformerConfig.Build(set)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ChunkTimeout <= 0 {
+		return nil, fmt.Errorf("invalid value for parameter 'chunk_timeout', must be positive")
+	}
+	if c.MaxMessageSize <= 0 {
+		return nil, fmt.Errorf("invalid value for parameter 'max_message_size', must be positive")
+	}
+
+	return &Parser{
+		TransformerOperator: transformerOperator,
+		chunkTimeout:        c.ChunkTimeout,
+		maxMessageSize:      int(c.MaxMessageSize),
+		messages:            make(map[string]*chunkedMessage),
+		now:                 time.Now,
+	}, nil
+}
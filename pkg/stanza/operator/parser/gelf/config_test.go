@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gelf
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/operatortest"
+)
+
+func TestParserGoldenConfig(t *testing.T) {
+	operatortest.ConfigUnmarshalTests{
+		DefaultConfig: NewConfig(),
+		TestsFile:     filepath.Join(".", "testdata", "config.yaml"),
+		Tests: []operatortest.ConfigUnmarshalTest{
+			{
+				Name:   "default",
+				Expect: NewConfig(),
+			},
+			{
+				Name: "on_error_drop",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.OnError = "drop"
+					return cfg
+				}(),
+			},
+			{
+				Name: "chunk_timeout",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.ChunkTimeout = 10 * time.Second
+					return cfg
+				}(),
+			},
+			{
+				Name: "max_message_size",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.MaxMessageSize = 64 * 1024
+					return cfg
+				}(),
+			},
+		},
+	}.Run(t)
+}
@@ -0,0 +1,327 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gelf // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/gelf"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const (
+	// chunkHeaderSize is the size of the header of the chunks: the magic bytes, the message ID,
+	// the sequence number and the sequence count.
+	chunkHeaderSize = 12
+	// maxChunks is the maximum number of chunks of a message allowed by the GELF specification.
+	maxChunks = 128
+)
+
+var (
+	chunkMagic = []byte{0x1e, 0x0f}
+	gzipMagic  = []byte{0x1f, 0x8b}
+)
+
+// standardFields are the fields of the GELF specification copied to the attributes.
+var standardFields = map[string]bool{
+	"host":         true,
+	"full_message": true,
+	"facility":     true,
+	"line":         true,
+	"file":         true,
+}
+
+// chunkedMessage holds the chunks of a message until all of them are received.
+type chunkedMessage struct {
+	chunks     [][]byte
+	received   int
+	size       int
+	expiration time.Time
+}
+
+// Parser is an operator that parses Graylog Extended Log Format messages. It reassembles the
+// chunked messages, and decompresses the gzip and zlib messages.
+type Parser struct {
+	helper.TransformerOperator
+	chunkTimeout   time.Duration
+	maxMessageSize int
+	now            func() time.Time
+
+	mu       sync.Mutex
+	messages map[string]*chunkedMessage
+}
+
+// Process will parse an entry as a GELF message. The chunks are held until the last chunk of
+// their message is received, which is then written in place of the entry of the last chunk.
+func (p *Parser) Process(ctx context.Context, e *entry.Entry) error {
+	// Short circuit if the "if" condition does not match
+	skip, err := p.Skip(ctx, e)
+	if err != nil {
+		return p.HandleEntryError(ctx, e, err)
+	}
+	if skip {
+		return p.Write(ctx, e)
+	}
+
+	payload, err := toBytes(e.Body)
+	if err != nil {
+		return p.HandleEntryError(ctx, e, err)
+	}
+	if bytes.HasPrefix(payload, chunkMagic) {
+		payload, err = p.addChunk(payload)
+		if err != nil {
+			return p.HandleEntryError(ctx, e, err)
+		}
+		if payload == nil {
+			// The message is waiting for its other chunks.
+			return nil
+		}
+	}
+	if err := p.parse(e, payload); err != nil {
+		return p.HandleEntryError(ctx, e, err)
+	}
+	return p.Write(ctx, e)
+}
+
+// addChunk adds a chunk to its message, and returns the payload of the message once all of
+// its chunks are received.
+func (p *Parser) addChunk(chunk []byte) ([]byte, error) {
+	if len(chunk) < chunkHeaderSize {
+		return nil, fmt.Errorf("GELF chunk of %d bytes is shorter than its header", len(chunk))
+	}
+	id := string(chunk[2:10])
+	seq, count := int(chunk[10]), int(chunk[11])
+	if count == 0 || count > maxChunks {
+		return nil, fmt.Errorf("GELF chunk count %d must be between 1 and %d", count, maxChunks)
+	}
+	if seq >= count {
+		return nil, fmt.Errorf("GELF chunk sequence number %d is out of the %d chunks of the message", seq, count)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	p.expireMessages(now)
+	msg, ok := p.messages[id]
+	if !ok {
+		msg = &chunkedMessage{
+			chunks:     make([][]byte, count),
+			expiration: now.Add(p.chunkTimeout),
+		}
+		p.messages[id] = msg
+	}
+	if len(msg.chunks) != count {
+		return nil, fmt.Errorf("GELF chunk count %d doesn't match the %d chunks of the message", count, len(msg.chunks))
+	}
+	if msg.chunks[seq] != nil {
+		// The chunk was already received, the duplicate is ignored.
+		return nil, nil
+	}
+	data := chunk[chunkHeaderSize:]
+	msg.size += len(data)
+	if msg.size > p.maxMessageSize {
+		delete(p.messages, id)
+		return nil, fmt.Errorf("GELF message exceeds the maximum size of %d bytes", p.maxMessageSize)
+	}
+	msg.chunks[seq] = bytes.Clone(data)
+	msg.received++
+	if msg.received < count {
+		return nil, nil
+	}
+	delete(p.messages, id)
+	return bytes.Join(msg.chunks, nil), nil
+}
+
+// expireMessages drops the messages whose chunks weren't all received within the chunk timeout.
+func (p *Parser) expireMessages(now time.Time) {
+	for id, msg := range p.messages {
+		if now.Before(msg.expiration) {
+			continue
+		}
+		delete(p.messages, id)
+		p.Logger().Warn("Dropped GELF message missing chunks",
+			zap.Int("received", msg.received), zap.Int("count", len(msg.chunks)))
+	}
+}
+
+// decompress decompresses the gzip and zlib payloads, the other payloads are returned as is.
+func (p *Parser) decompress(payload []byte) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+	switch {
+	case bytes.HasPrefix(payload, gzipMagic):
+		reader, err = gzip.NewReader(bytes.NewReader(payload))
+	case isZlib(payload):
+		reader, err = zlib.NewReader(bytes.NewReader(payload))
+	default:
+		if len(payload) > p.maxMessageSize {
+			return nil, fmt.Errorf("GELF message exceeds the maximum size of %d bytes", p.maxMessageSize)
+		}
+		return payload, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the GELF message: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, int64(p.maxMessageSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the GELF message: %w", err)
+	}
+	if len(data) > p.maxMessageSize {
+		return nil, fmt.Errorf("GELF message exceeds the maximum size of %d bytes", p.maxMessageSize)
+	}
+	return data, nil
+}
+
+// isZlib checks the payload starts with a zlib header using the deflate method.
+func isZlib(payload []byte) bool {
+	return len(payload) >= 2 && payload[0] == 0x78 && (uint16(payload[0])<<8|uint16(payload[1]))%31 == 0
+}
+
+// parse sets the body, timestamp, severity and attributes of the entry from a GELF message.
+func (p *Parser) parse(e *entry.Entry, payload []byte) error {
+	data, err := p.decompress(payload)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return fmt.Errorf("failed to decode the GELF message: %w", err)
+	}
+	shortMessage, ok := fields["short_message"].(string)
+	if !ok {
+		return errors.New("GELF message is missing the short_message field")
+	}
+
+	if e.Attributes == nil {
+		e.Attributes = make(map[string]any, len(fields))
+	}
+	for key, value := range fields {
+		switch {
+		case key == "level":
+			if err := setSeverity(e, value); err != nil {
+				return err
+			}
+		case key == "timestamp":
+			if err := setTimestamp(e, value); err != nil {
+				return err
+			}
+		case standardFields[key]:
+			e.Attributes[key] = convertNumbers(value)
+		case strings.HasPrefix(key, "_") && len(key) > 1:
+			// The standard fields take precedence over the additional fields of the same name.
+			name := key[1:]
+			if _, ok := fields[name]; ok && standardFields[name] {
+				continue
+			}
+			e.Attributes[name] = convertNumbers(value)
+		}
+	}
+	e.Body = shortMessage
+	return nil
+}
+
+func setSeverity(e *entry.Entry, value any) error {
+	number, ok := value.(json.Number)
+	if !ok {
+		return fmt.Errorf("GELF level %v is not a number", value)
+	}
+	level, err := number.Int64()
+	if err != nil || level < 0 || level >= int64(len(severityMapping)) {
+		return fmt.Errorf("GELF level %s must be a syslog severity between 0 and 7", number)
+	}
+	e.Severity = severityMapping[level]
+	e.SeverityText = severityText[level]
+	return nil
+}
+
+func setTimestamp(e *entry.Entry, value any) error {
+	number, ok := value.(json.Number)
+	if !ok {
+		return fmt.Errorf("GELF timestamp %v is not a number", value)
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return fmt.Errorf("GELF timestamp %s is invalid: %w", number, err)
+	}
+	// The timestamps have a precision of milliseconds at best, the rounding to microseconds
+	// avoids the errors of the floating point representation.
+	e.Timestamp = time.UnixMicro(int64(math.Round(seconds * 1e6)))
+	return nil
+}
+
+// convertNumbers converts the JSON numbers to integers when possible, or floats.
+func convertNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, item := range v {
+			v[key] = convertNumbers(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func toBytes(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("type '%T' cannot be parsed as GELF", value)
+	}
+}
+
+var severityMapping = [...]entry.Severity{
+	0: entry.Fatal,
+	1: entry.Error3,
+	2: entry.Error2,
+	3: entry.Error,
+	4: entry.Warn,
+	5: entry.Info2,
+	6: entry.Info,
+	7: entry.Debug,
+}
+
+var severityText = [...]string{
+	0: "emerg",
+	1: "alert",
+	2: "crit",
+	3: "err",
+	4: "warning",
+	5: "notice",
+	6: "info",
+	7: "debug",
+}
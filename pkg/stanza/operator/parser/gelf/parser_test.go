@@ -0,0 +1,270 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+const testMessage = `{"version":"1.1","host":"web-1","short_message":"Backend timeout","full_message":"Backend timeout\nat handler.go:42",` +
+	`"timestamp":1385053862.3072,"level":3,"_user_id":9001,"_request":"GET /","_latency":0.25}`
+
+func newTestParser(t *testing.T, cfg *Config) (*Parser, *testutil.FakeOutput) {
+	cfg.OutputIDs = []string{"fake"}
+	op, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+	return op.(*Parser), fake
+}
+
+func newEntry(body string) *entry.Entry {
+	e := entry.New()
+	e.Body = body
+	return e
+}
+
+func expectedEntry(e *entry.Entry) *entry.Entry {
+	return &entry.Entry{
+		ObservedTimestamp: e.ObservedTimestamp,
+		Timestamp:         time.UnixMicro(1385053862307200),
+		Severity:          entry.Error,
+		SeverityText:      "err",
+		Attributes: map[string]any{
+			"host":         "web-1",
+			"full_message": "Backend timeout\nat handler.go:42",
+			"user_id":      int64(9001),
+			"request":      "GET /",
+			"latency":      0.25,
+		},
+		Body: "Backend timeout",
+	}
+}
+
+func compress(t *testing.T, newWriter func(w *bytes.Buffer) interface {
+	Write([]byte) (int, error)
+	Close() error
+}, data string,
+) string {
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+func gzipCompress(t *testing.T, data string) string {
+	return compress(t, func(w *bytes.Buffer) interface {
+		Write([]byte) (int, error)
+		Close() error
+	} {
+		return gzip.NewWriter(w)
+	}, data)
+}
+
+func zlibCompress(t *testing.T, data string) string {
+	return compress(t, func(w *bytes.Buffer) interface {
+		Write([]byte) (int, error)
+		Close() error
+	} {
+		return zlib.NewWriter(w)
+	}, data)
+}
+
+// chunks splits a payload in the given number of chunks of the message.
+func chunks(id string, payload string, count int) []string {
+	size := (len(payload) + count - 1) / count
+	var result []string
+	for seq := 0; seq < count; seq++ {
+		end := min((seq+1)*size, len(payload))
+		header := append([]byte{0x1e, 0x0f}, id...)
+		header = append(header, byte(seq), byte(count))
+		result = append(result, string(header)+payload[seq*size:end])
+	}
+	return result
+}
+
+func TestInit(t *testing.T) {
+	builder, ok := operator.DefaultRegistry.Lookup("gelf_parser")
+	require.True(t, ok, "expected gelf_parser to be registered")
+	require.Equal(t, "gelf_parser", builder().Type())
+}
+
+func TestBuildInvalidConfig(t *testing.T) {
+	cfg := NewConfigWithID("test")
+	cfg.ChunkTimeout = 0
+	_, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+	require.EqualError(t, err, "invalid value for parameter 'chunk_timeout', must be positive")
+
+	cfg = NewConfigWithID("test")
+	cfg.MaxMessageSize = 0
+	_, err = cfg.Build(componenttest.NewNopTelemetrySettings())
+	require.EqualError(t, err, "invalid value for parameter 'max_message_size', must be positive")
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name    string
+		payload string
+	}{
+		{name: "uncompressed", payload: testMessage},
+		{name: "gzip", payload: gzipCompress(t, testMessage)},
+		{name: "zlib", payload: zlibCompress(t, testMessage)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser, fake := newTestParser(t, NewConfigWithID("test"))
+			e := newEntry(tc.payload)
+			e.AddAttribute("net.peer.ip", "10.0.0.1")
+			expected := expectedEntry(e)
+			expected.Attributes["net.peer.ip"] = "10.0.0.1"
+
+			require.NoError(t, parser.Process(context.Background(), e))
+			fake.ExpectEntry(t, expected)
+		})
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	parser, fake := newTestParser(t, NewConfigWithID("test"))
+	e := entry.New()
+	e.Body = []byte(gzipCompress(t, testMessage))
+	expected := expectedEntry(e)
+	require.NoError(t, parser.Process(context.Background(), e))
+	fake.ExpectEntry(t, expected)
+}
+
+func TestParseMinimalMessage(t *testing.T) {
+	parser, fake := newTestParser(t, NewConfigWithID("test"))
+	e := newEntry(`{"version":"1.1","host":"web-1","short_message":"started","_host":"ignored","_":"ignored","_nested":{"count":2}}`)
+	expected := &entry.Entry{
+		ObservedTimestamp: e.ObservedTimestamp,
+		Attributes: map[string]any{
+			"host":   "web-1",
+			"nested": map[string]any{"count": int64(2)},
+		},
+		Body: "started",
+	}
+
+	// The severity and timestamp are kept when the message doesn't have a level and timestamp.
+	require.NoError(t, parser.Process(context.Background(), e))
+	fake.ExpectEntry(t, expected)
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     any
+		expected string
+	}{
+		{name: "type", body: 42, expected: "type 'int' cannot be parsed as GELF"},
+		{name: "json", body: "not gelf", expected: "failed to decode the GELF message: invalid character 'o' in literal null (expecting 'u')"},
+		{name: "short_message", body: `{"host":"web-1"}`, expected: "GELF message is missing the short_message field"},
+		{name: "level_type", body: `{"short_message":"a","level":"error"}`, expected: "GELF level error is not a number"},
+		{name: "level_range", body: `{"short_message":"a","level":8}`, expected: "GELF level 8 must be a syslog severity between 0 and 7"},
+		{name: "timestamp", body: `{"short_message":"a","timestamp":"now"}`, expected: "GELF timestamp now is not a number"},
+		{name: "gzip", body: "\x1f\x8bcorrupted", expected: "failed to decompress the GELF message: gzip: invalid header"},
+		{name: "chunk_header", body: "\x1e\x0f\x01\x02", expected: "GELF chunk of 4 bytes is shorter than its header"},
+		{name: "chunk_count", body: "\x1e\x0f12345678\x00\x81{}", expected: "GELF chunk count 129 must be between 1 and 128"},
+		{name: "chunk_seq", body: "\x1e\x0f12345678\x02\x02{}", expected: "GELF chunk sequence number 2 is out of the 2 chunks of the message"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfigWithID("test")
+			cfg.OnError = "drop"
+			parser, fake := newTestParser(t, cfg)
+			e := entry.New()
+			e.Body = tc.body
+			assert.EqualError(t, parser.Process(context.Background(), e), tc.expected)
+			fake.ExpectNoEntry(t, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestParseMaxMessageSize(t *testing.T) {
+	cfg := NewConfigWithID("test")
+	cfg.OnError = "drop"
+	cfg.MaxMessageSize = 64
+	parser, fake := newTestParser(t, cfg)
+	expected := "GELF message exceeds the maximum size of 64 bytes"
+
+	large := `{"short_message":"` + strings.Repeat("a", 100) + `"}`
+	assert.EqualError(t, parser.Process(context.Background(), newEntry(large)), expected)
+	// The size is checked once decompressed.
+	assert.EqualError(t, parser.Process(context.Background(), newEntry(gzipCompress(t, large))), expected)
+	// And once the chunks are reassembled.
+	for _, chunk := range chunks("abcdefgh", large, 2) {
+		err := parser.Process(context.Background(), newEntry(chunk))
+		if err != nil {
+			assert.EqualError(t, err, expected)
+		}
+	}
+	assert.Empty(t, parser.messages)
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+}
+
+func TestParseChunks(t *testing.T) {
+	parser, fake := newTestParser(t, NewConfigWithID("test"))
+	first := chunks("message1", gzipCompress(t, testMessage), 3)
+	second := chunks("message2", testMessage, 2)
+
+	// The chunks of different messages are interleaved, out of order and duplicated. The
+	// message is written with the entry of its last chunk.
+	for _, chunk := range []string{first[2], second[1], first[0], first[0]} {
+		require.NoError(t, parser.Process(context.Background(), newEntry(chunk)))
+	}
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+	last := newEntry(second[0])
+	require.NoError(t, parser.Process(context.Background(), last))
+	fake.ExpectEntry(t, expectedEntry(last))
+
+	last = newEntry(first[1])
+
+	require.NoError(t, parser.Process(context.Background(), last))
+	fake.ExpectEntry(t, expectedEntry(last))
+	assert.Empty(t, parser.messages)
+}
+
+func TestParseChunkCountMismatch(t *testing.T) {
+	cfg := NewConfigWithID("test")
+	cfg.OnError = "drop"
+	parser, fake := newTestParser(t, cfg)
+	require.NoError(t, parser.Process(context.Background(), newEntry(chunks("message1", testMessage, 3)[0])))
+	err := parser.Process(context.Background(), newEntry(chunks("message1", testMessage, 2)[1]))
+	assert.EqualError(t, err, "GELF chunk count 2 doesn't match the 3 chunks of the message")
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+}
+
+func TestParseChunksTimeout(t *testing.T) {
+	parser, fake := newTestParser(t, NewConfigWithID("test"))
+	now := time.Now()
+	parser.now = func() time.Time { return now }
+
+	expired := chunks("message1", testMessage, 2)
+	require.NoError(t, parser.Process(context.Background(), newEntry(expired[0])))
+
+	// The incomplete message is dropped once the chunk timeout elapsed, its last chunk starts
+	// a new message.
+	now = now.Add(DefaultChunkTimeout)
+	require.NoError(t, parser.Process(context.Background(), newEntry(chunks("message2", testMessage, 2)[0])))
+	assert.Len(t, parser.messages, 1)
+	require.NoError(t, parser.Process(context.Background(), newEntry(expired[1])))
+	assert.Len(t, parser.messages, 2)
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+}
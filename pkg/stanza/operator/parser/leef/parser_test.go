@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package leef
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+)
+
+const testMessage = "<13>Jan 18 11:07:53 host LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dstPort=443^sev=5^cat=flow"
+
+func newTestParser(t *testing.T) *Parser {
+	cfg := NewConfigWithID("test")
+	set := componenttest.NewNopTelemetrySettings()
+	op, err := cfg.Build(set)
+	require.NoError(t, err)
+	return op.(*Parser)
+}
+
+func TestInit(t *testing.T) {
+	builder, ok := operator.DefaultRegistry.Lookup("leef_parser")
+	require.True(t, ok, "expected leef_parser to be registered")
+	require.Equal(t, "leef_parser", builder().Type())
+}
+
+func TestParserBuildFailure(t *testing.T) {
+	cfg := NewConfigWithID("test")
+	cfg.OnError = "invalid_on_error"
+	set := componenttest.NewNopTelemetrySettings()
+	_, err := cfg.Build(set)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid `on_error` field")
+}
+
+func TestParserInvalidType(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parse([]int{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "type '[]int' cannot be parsed as LEEF")
+}
+
+func TestParserInvalidMessage(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parse("not a leef message")
+	require.EqualError(t, err, "not a LEEF message: missing LEEF: prefix")
+}
+
+func TestProcess(t *testing.T) {
+	cases := []struct {
+		name   string
+		op     func() (operator.Operator, error)
+		input  *entry.Entry
+		expect *entry.Entry
+	}{
+		{
+			"default",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Body: testMessage,
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"version":         "2.0",
+					"vendor":          "Lancope",
+					"product":         "StealthWatch",
+					"product_version": "1.0",
+					"event_id":        "41",
+					"extensions": map[string]any{
+						"src":     "10.0.1.8",
+						"dstPort": int64(443),
+						"sev":     int64(5),
+						"cat":     "flow",
+					},
+				},
+				Body: testMessage,
+			},
+		},
+		{
+			"semantic_conventions",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.SemanticConventions = true
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Body: testMessage,
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"version":          "2.0",
+					"vendor":           "Lancope",
+					"product":          "StealthWatch",
+					"product_version":  "1.0",
+					"event_id":         "41",
+					"source.address":   "10.0.1.8",
+					"destination.port": int64(443),
+					"extensions": map[string]any{
+						"sev": int64(5),
+						"cat": "flow",
+					},
+				},
+				Body: testMessage,
+			},
+		},
+		{
+			"parse_from_and_to",
+			func() (operator.Operator, error) {
+				cfg := NewConfigWithID("test_id")
+				cfg.ParseFrom = entry.NewAttributeField("message")
+				cfg.ParseTo = entry.RootableField{Field: entry.NewBodyField()}
+				set := componenttest.NewNopTelemetrySettings()
+				return cfg.Build(set)
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"message": "LEEF:1.0|Vendor|Product|1.0|100|",
+				},
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"message": "LEEF:1.0|Vendor|Product|1.0|100|",
+				},
+				Body: map[string]any{
+					"version":         "1.0",
+					"vendor":          "Vendor",
+					"product":         "Product",
+					"product_version": "1.0",
+					"event_id":        "100",
+					"extensions":      map[string]any{},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, err := tc.op()
+			require.NoError(t, err, "did not expect operator function to return an error, this is a bug with the test case")
+
+			err = op.Process(context.Background(), tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, tc.input)
+		})
+	}
+}
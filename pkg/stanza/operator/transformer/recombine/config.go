@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 )
@@ -50,6 +51,7 @@ type Config struct {
 	helper.TransformerConfig `mapstructure:",squash"`
 	IsFirstEntry             string          `mapstructure:"is_first_entry"`
 	IsLastEntry              string          `mapstructure:"is_last_entry"`
+	Detector                 string          `mapstructure:"detector"`
 	MaxBatchSize             int             `mapstructure:"max_batch_size"`
 	MaxUnmatchedBatchSize    int             `mapstructure:"max_unmatched_batch_size"`
 	CombineField             entry.Field     `mapstructure:"combine_field"`
@@ -68,28 +70,40 @@ func (c *Config) Build(set component.TelemetrySettings) (operator.Operator, erro
 		return nil, fmt.Errorf("failed to build transformer config: %w", err)
 	}
 
-	if c.IsLastEntry != "" && c.IsFirstEntry != "" {
-		return nil, fmt.Errorf("only one of is_first_entry and is_last_entry can be set")
+	matchers := 0
+	for _, matcher := range []string{c.IsFirstEntry, c.IsLastEntry, c.Detector} {
+		if matcher != "" {
+			matchers++
+		}
 	}
-
-	if c.IsLastEntry == "" && c.IsFirstEntry == "" {
-		return nil, fmt.Errorf("one of is_first_entry and is_last_entry must be set")
+	if matchers > 1 {
+		return nil, fmt.Errorf("only one of is_first_entry, is_last_entry and detector can be set")
+	}
+	if matchers == 0 {
+		return nil, fmt.Errorf("one of is_first_entry, is_last_entry and detector must be set")
 	}
 
 	var matchesFirst bool
 	var prog *vm.Program
-	if c.IsFirstEntry != "" {
+	var detector *multiline.Detector
+	switch {
+	case c.IsFirstEntry != "":
 		matchesFirst = true
 		prog, err = helper.ExprCompileBool(c.IsFirstEntry)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile is_first_entry: %w", err)
 		}
-	} else {
+	case c.IsLastEntry != "":
 		matchesFirst = false
 		prog, err = helper.ExprCompileBool(c.IsLastEntry)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile is_last_entry: %w", err)
 		}
+	default:
+		detector, err = multiline.Lookup(c.Detector)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if c.CombineField.FieldInterface == nil {
@@ -110,6 +124,7 @@ func (c *Config) Build(set component.TelemetrySettings) (operator.Operator, erro
 		TransformerOperator:   transformer,
 		matchFirstLine:        matchesFirst,
 		prog:                  prog,
+		detector:              detector,
 		maxBatchSize:          c.MaxBatchSize,
 		maxUnmatchedBatchSize: c.MaxUnmatchedBatchSize,
 		maxSources:            c.MaxSources,
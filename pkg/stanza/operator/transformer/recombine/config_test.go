@@ -93,6 +93,15 @@ func TestUnmarshal(t *testing.T) {
 					return cfg
 				}(),
 			},
+			{
+				Name:      "detector",
+				ExpectErr: false,
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.Detector = "java"
+					return cfg
+				}(),
+			},
 		},
 	}.Run(t)
 }
@@ -15,6 +15,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 )
@@ -26,6 +27,7 @@ type Transformer struct {
 	helper.TransformerOperator
 	matchFirstLine        bool
 	prog                  *vm.Program
+	detector              *multiline.Detector
 	maxBatchSize          int
 	maxUnmatchedBatchSize int
 	maxSources            int
@@ -50,6 +52,7 @@ type sourceBatch struct {
 	recombined             *bytes.Buffer
 	firstEntryObservedTime time.Time
 	matchDetected          bool
+	state                  multiline.State
 }
 
 func (t *Transformer) Start(_ operator.Persister) error {
@@ -99,6 +102,10 @@ func (t *Transformer) Process(ctx context.Context, e *entry.Entry) error {
 	t.Lock()
 	defer t.Unlock()
 
+	if t.detector != nil {
+		return t.processDetected(ctx, e)
+	}
+
 	// Get the environment for executing the expression.
 	// In the future, we may want to provide access to the currently
 	// batched entries so users can do comparisons to other entries
@@ -113,16 +120,7 @@ func (t *Transformer) Process(ctx context.Context, e *entry.Entry) error {
 
 	// this is guaranteed to be a boolean because of expr.AsBool
 	matches := m.(bool)
-	var s string
-	err = e.Read(t.sourceIdentifier, &s)
-	if err != nil {
-		t.Logger().Warn("entry does not contain the source_identifier, so it may be pooled with other sources")
-		s = DefaultSourceIdentifier
-	}
-
-	if s == "" {
-		s = DefaultSourceIdentifier
-	}
+	s := t.source(e)
 
 	switch {
 	// This is the first entry in the next batch
@@ -147,6 +145,55 @@ func (t *Transformer) Process(ctx context.Context, e *entry.Entry) error {
 	return nil
 }
 
+// processDetected combines the entries of each source recognized by the detector as part of the same multiline entry
+func (t *Transformer) processDetected(ctx context.Context, e *entry.Entry) error {
+	var line string
+	if err := e.Read(t.combineField, &line); err != nil {
+		return t.HandleEntryError(ctx, e, err)
+	}
+
+	s := t.source(e)
+	state := multiline.Start
+	if batch, ok := t.batchMap[s]; ok {
+		state = batch.state
+	}
+
+	state, continues := t.detector.Next(state, []byte(line))
+	if !continues {
+		// This is the first entry in the next batch
+		if err := t.flushSource(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	// Every batch is a detected multiline entry, so max_unmatched_batch_size doesn't apply
+	t.addToBatch(ctx, e, s, true)
+	if batch, ok := t.batchMap[s]; ok {
+		batch.state = state
+	}
+
+	if state == multiline.Start {
+		// This is the last entry in a complete batch
+		return t.flushSource(ctx, s)
+	}
+	return nil
+}
+
+// source returns the source identifier of the entry
+func (t *Transformer) source(e *entry.Entry) string {
+	var s string
+	err := e.Read(t.sourceIdentifier, &s)
+	if err != nil {
+		t.Logger().Warn("entry does not contain the source_identifier, so it may be pooled with other sources")
+		s = DefaultSourceIdentifier
+	}
+
+	if s == "" {
+		s = DefaultSourceIdentifier
+	}
+	return s
+}
+
 // addToBatch adds the current entry to the current batch of entries that will be combined
 func (t *Transformer) addToBatch(ctx context.Context, e *entry.Entry, source string, matches bool) {
 	batch, ok := t.batchMap[source]
@@ -236,6 +283,7 @@ func (t *Transformer) addNewBatch(source string, e *entry.Entry) *sourceBatch {
 	batch.recombined.Reset()
 	batch.firstEntryObservedTime = e.ObservedTimestamp
 	batch.matchDetected = false
+	batch.state = multiline.Start
 	t.batchMap[source] = batch
 	return batch
 }
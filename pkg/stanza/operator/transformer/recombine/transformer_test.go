@@ -6,6 +6,7 @@ package recombine
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +15,8 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline/multilinetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
@@ -666,6 +669,55 @@ func TestTransformer(t *testing.T) {
 				entryWithBody(t1, "test6\ntest7\ntest1"),
 			},
 		},
+		{
+			"Detector",
+			func() *Config {
+				cfg := NewConfig()
+				cfg.CombineField = entry.NewBodyField()
+				cfg.Detector = "java"
+				cfg.OutputIDs = []string{"fake"}
+				return cfg
+			}(),
+			[]*entry.Entry{
+				entryWithBody(t1, "starting"),
+				entryWithBody(t1, "java.lang.IllegalStateException: boom"),
+				entryWithBody(t1, "\tat com.example.App.main(App.java:12)"),
+				entryWithBody(t2, "Caused by: java.io.IOException: closed"),
+				entryWithBody(t2, "\t... 1 more"),
+				entryWithBody(t2, "stopping"),
+			},
+			[]*entry.Entry{
+				entryWithBody(t1, "starting"),
+				entryWithBody(t1, "java.lang.IllegalStateException: boom\n\tat com.example.App.main(App.java:12)\nCaused by: java.io.IOException: closed\n\t... 1 more"),
+				entryWithBody(t2, "stopping"),
+			},
+		},
+		{
+			"DetectorMultipleSources",
+			func() *Config {
+				cfg := NewConfig()
+				cfg.CombineField = entry.NewBodyField()
+				cfg.Detector = "python"
+				cfg.OutputIDs = []string{"fake"}
+				return cfg
+			}(),
+			[]*entry.Entry{
+				entryWithBodyAttr(t1, "Traceback (most recent call last):", map[string]string{"file.path": "file1"}),
+				entryWithBodyAttr(t1, "Traceback (most recent call last):", map[string]string{"file.path": "file2"}),
+				entryWithBodyAttr(t1, "  File \"a.py\", line 1, in <module>", map[string]string{"file.path": "file1"}),
+				entryWithBodyAttr(t1, "  File \"b.py\", line 2, in <module>", map[string]string{"file.path": "file2"}),
+				entryWithBodyAttr(t1, "ValueError: a", map[string]string{"file.path": "file1"}),
+				entryWithBodyAttr(t1, "ValueError: b", map[string]string{"file.path": "file2"}),
+				entryWithBodyAttr(t2, "done", map[string]string{"file.path": "file1"}),
+				entryWithBodyAttr(t2, "done", map[string]string{"file.path": "file2"}),
+			},
+			[]*entry.Entry{
+				entryWithBodyAttr(t1, "Traceback (most recent call last):\n  File \"a.py\", line 1, in <module>\nValueError: a", map[string]string{"file.path": "file1"}),
+				entryWithBodyAttr(t1, "Traceback (most recent call last):\n  File \"b.py\", line 2, in <module>\nValueError: b", map[string]string{"file.path": "file2"}),
+				entryWithBodyAttr(t2, "done", map[string]string{"file.path": "file1"}),
+				entryWithBodyAttr(t2, "done", map[string]string{"file.path": "file2"}),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -817,6 +869,59 @@ func BenchmarkRecombineLimitTrigger(b *testing.B) {
 
 }
 
+func TestDetectorFixtures(t *testing.T) {
+	for _, name := range multiline.Names() {
+		fixtures, err := filepath.Glob(filepath.Join("..", "..", "..", "multiline", "testdata", name, "*.log"))
+		require.NoError(t, err)
+
+		for _, fixture := range fixtures {
+			t.Run(name+"/"+filepath.Base(fixture), func(t *testing.T) {
+				input, expected := multilinetest.ReadFixture(t, fixture)
+
+				cfg := NewConfig()
+				cfg.CombineField = entry.NewBodyField()
+				cfg.Detector = name
+				cfg.OutputIDs = []string{"fake"}
+				op, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+				require.NoError(t, err)
+				recombine := op.(*Transformer)
+
+				fake := testutil.NewFakeOutput(t)
+				require.NoError(t, recombine.SetOutputs([]operator.Operator{fake}))
+
+				ctx := context.Background()
+				for _, line := range strings.Split(strings.TrimSuffix(string(input), "\n"), "\n") {
+					e := entry.New()
+					e.Body = line
+					require.NoError(t, recombine.Process(ctx, e))
+				}
+
+				for _, body := range expected {
+					select {
+					case e := <-fake.Received:
+						require.Equal(t, body, e.Body)
+					case <-time.After(time.Second):
+						require.FailNow(t, "Timed out waiting for entry", body)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestBuildDetector(t *testing.T) {
+	cfg := NewConfig()
+	cfg.CombineField = entry.NewBodyField()
+	cfg.Detector = "cobol"
+	_, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+	require.EqualError(t, err, `unknown multiline detector "cobol", must be one of dotnet, go, java, nodejs, python`)
+
+	cfg.Detector = "java"
+	cfg.IsFirstEntry = MatchAll
+	_, err = cfg.Build(componenttest.NewNopTelemetrySettings())
+	require.EqualError(t, err, "only one of is_first_entry, is_last_entry and detector can be set")
+}
+
 func TestTimeout(t *testing.T) {
 	t.Parallel()
 
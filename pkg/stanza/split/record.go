@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package split // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// journalSizeLength is the length of the size preceding the binary field values of the journal export format
+const journalSizeLength = 8
+
+// RecordTooLongError is returned by the record split funcs when a record doesn't fit in max_log_size.
+// The record can't be returned as a token, so it is to be skipped by moving Length bytes past the
+// position of the data passed to the split func, and then past the end of the line if SkipLine is set.
+type RecordTooLongError struct {
+	Length   int64
+	SkipLine bool
+}
+
+func (e *RecordTooLongError) Error() string {
+	if e.SkipLine {
+		return "record exceeds max_log_size"
+	}
+	return fmt.Sprintf("record of %d bytes exceeds max_log_size", e.Length)
+}
+
+// LengthPrefixedSplitFunc creates a bufio.SplitFunc that splits an incoming stream into records
+// preceded by their length, encoded as an unsigned integer of size bytes in the given byte order.
+// The tokens don't include the length prefix.
+// Records longer than maxLogSize can't be truncated without losing track of the following
+// records, so they cause a RecordTooLongError for the caller to skip them.
+func LengthPrefixedSplitFunc(size int, order binary.ByteOrder, maxLogSize int) (bufio.SplitFunc, error) {
+	var readLength func([]byte) uint64
+	switch size {
+	case 1:
+		readLength = func(b []byte) uint64 { return uint64(b[0]) }
+	case 2:
+		readLength = func(b []byte) uint64 { return uint64(order.Uint16(b)) }
+	case 4:
+		readLength = func(b []byte) uint64 { return uint64(order.Uint32(b)) }
+	case 8:
+		readLength = order.Uint64
+	default:
+		return nil, fmt.Errorf("invalid length prefix size %d, must be one of 1, 2, 4 or 8", size)
+	}
+
+	return func(data []byte, _ bool) (advance int, token []byte, err error) {
+		if len(data) < size {
+			return 0, nil, nil // read more data and try again.
+		}
+		length := readLength(data)
+		if length > uint64(max(maxLogSize-size, 0)) {
+			return 0, nil, &RecordTooLongError{Length: int64(min(length, math.MaxInt64-uint64(size))) + int64(size)}
+		}
+		end := size + int(length)
+		if len(data) < end {
+			return 0, nil, nil // read more data and try again.
+		}
+		return end, data[size:end], nil
+	}, nil
+}
+
+// JournalExportSplitFunc creates a bufio.SplitFunc that splits an incoming stream in the systemd
+// journal export format into its entries. Each entry is returned as a JSON object, as journalctl
+// does with its json output: fields occurring more than once have an array of values, and values
+// which aren't valid UTF-8 are arrays of bytes.
+// Entries longer than maxLogSize are split into several entries, as long lines are, and fields
+// longer than maxLogSize cause a RecordTooLongError for the caller to skip them.
+func JournalExportSplitFunc(maxLogSize int) bufio.SplitFunc {
+	return func(data []byte, _ bool) (advance int, token []byte, err error) {
+		// Skip the empty lines separating the entries
+		start := 0
+		for start < len(data) && data[start] == '\n' {
+			start++
+		}
+		if start > 0 {
+			return start, nil, nil
+		}
+
+		// The buffer of the scanner may hold more than maxLogSize
+		data = data[:min(len(data), maxLogSize)]
+
+		fields := make(map[string]any)
+		pos := 0
+		for pos < len(data) {
+			if data[pos] == '\n' {
+				// An empty line terminates the entry
+				token, err = json.Marshal(fields)
+				if err != nil {
+					return 0, nil, err
+				}
+				return pos + 1, token, nil
+			}
+
+			n, name, value, err := readJournalField(data[pos:])
+			if err != nil {
+				return 0, nil, err
+			}
+			if n == 0 {
+				break
+			}
+			addJournalField(fields, name, value)
+			pos += n
+		}
+
+		if len(data) < maxLogSize {
+			return 0, nil, nil // read more data and try again.
+		}
+		if pos > 0 {
+			// The following fields are returned as another entry
+			token, err = json.Marshal(fields)
+			if err != nil {
+				return 0, nil, err
+			}
+			return pos, token, nil
+		}
+		return 0, nil, journalFieldTooLong(data)
+	}
+}
+
+// journalFieldTooLong returns the error to skip the field at the start of data, which doesn't fit in it
+func journalFieldTooLong(data []byte) error {
+	eol := bytes.IndexByte(data, '\n')
+	if eol < 0 || len(data) < eol+1+journalSizeLength {
+		// The value of a text field can't include a newline, so the field ends at the end of the line
+		return &RecordTooLongError{Length: int64(len(data)), SkipLine: true}
+	}
+	size := binary.LittleEndian.Uint64(data[eol+1 : eol+1+journalSizeLength])
+	prefix := uint64(eol + 1 + journalSizeLength + 1)
+	return &RecordTooLongError{Length: int64(min(size, math.MaxInt64-prefix) + prefix)}
+}
+
+// readJournalField reads the field at the start of data, either a NAME=value line or a NAME line
+// followed by the little endian size of the binary value, the value and a newline.
+// It returns the number of bytes of the field, or 0 if data doesn't hold the whole field.
+func readJournalField(data []byte) (int, string, []byte, error) {
+	eol := bytes.IndexByte(data, '\n')
+	if eol < 0 {
+		return 0, "", nil, nil
+	}
+	line := data[:eol]
+	if name, value, ok := bytes.Cut(line, []byte{'='}); ok {
+		if len(name) == 0 {
+			return 0, "", nil, fmt.Errorf("invalid journal export field %q", line)
+		}
+		return eol + 1, string(name), value, nil
+	}
+	if len(line) == 0 {
+		return 0, "", nil, fmt.Errorf("invalid journal export field %q", line)
+	}
+
+	start := eol + 1 + journalSizeLength
+	if len(data) < start {
+		return 0, "", nil, nil
+	}
+	size := binary.LittleEndian.Uint64(data[eol+1 : start])
+	if size > uint64(len(data)) {
+		return 0, "", nil, nil
+	}
+	end := start + int(size)
+	if len(data) <= end {
+		return 0, "", nil, nil
+	}
+	if data[end] != '\n' {
+		return 0, "", nil, fmt.Errorf("invalid journal export field %q: value isn't followed by a newline", line)
+	}
+	return end + 1, string(line), data[start:end], nil
+}
+
+// addJournalField adds the value of a field to the fields of an entry, collecting the values
+// of repeated fields in an array
+func addJournalField(fields map[string]any, name string, value []byte) {
+	var v any = string(value)
+	if !utf8.Valid(value) {
+		b := make([]int, len(value))
+		for i, c := range value {
+			b[i] = int(c)
+		}
+		v = b
+	}
+
+	switch existing := fields[name].(type) {
+	case nil:
+		fields[name] = v
+	case []any:
+		fields[name] = append(existing, v)
+	default:
+		fields[name] = []any{existing, v}
+	}
+}
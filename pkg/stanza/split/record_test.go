@@ -0,0 +1,221 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package split
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split/splittest"
+)
+
+func TestLengthPrefixedSplitFunc(t *testing.T) {
+	testCases := []struct {
+		name  string
+		size  int
+		order binary.ByteOrder
+		input []byte
+		steps []splittest.Step
+	}{
+		{
+			name:  "EmptyFile",
+			size:  4,
+			order: binary.BigEndian,
+			input: []byte{},
+		},
+		{
+			name:  "OneByte",
+			size:  1,
+			order: binary.BigEndian,
+			input: append([]byte{6}, "my log"...),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(7, "my log"),
+			},
+		},
+		{
+			name:  "TwoBytesLittleEndian",
+			size:  2,
+			order: binary.LittleEndian,
+			input: append([]byte{6, 0}, "my log"...),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(8, "my log"),
+			},
+		},
+		{
+			name:  "FourBytesBigEndian",
+			size:  4,
+			order: binary.BigEndian,
+			input: append(append([]byte{0, 0, 0, 4}, "log1"...), append([]byte{0, 0, 0, 5}, "log\n2"...)...),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(8, "log1"),
+				splittest.ExpectAdvanceToken(9, "log\n2"),
+			},
+		},
+		{
+			name:  "EightBytes",
+			size:  8,
+			order: binary.BigEndian,
+			input: append([]byte{0, 0, 0, 0, 0, 0, 0, 3}, 0x00, 0xff, 0x01),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(11, "\x00\xff\x01"),
+			},
+		},
+		{
+			name:  "EmptyRecord",
+			size:  2,
+			order: binary.BigEndian,
+			input: append([]byte{0, 0, 0, 3}, "log"...),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(2, ""),
+				splittest.ExpectAdvanceToken(5, "log"),
+			},
+		},
+		{
+			name:  "PartialRecord",
+			size:  4,
+			order: binary.BigEndian,
+			input: append(append([]byte{0, 0, 0, 4}, "log1"...), append([]byte{0, 0, 0, 10}, "log2"...)...),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(8, "log1"),
+			},
+		},
+		{
+			name:  "PartialPrefix",
+			size:  4,
+			order: binary.BigEndian,
+			input: []byte{0, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		splitFunc, err := LengthPrefixedSplitFunc(tc.size, tc.order, 100)
+		require.NoError(t, err)
+		t.Run(tc.name, splittest.New(splitFunc, tc.input, tc.steps...))
+	}
+}
+
+func TestLengthPrefixedSplitFuncErrors(t *testing.T) {
+	_, err := LengthPrefixedSplitFunc(3, binary.BigEndian, 100)
+	assert.EqualError(t, err, "invalid length prefix size 3, must be one of 1, 2, 4 or 8")
+
+	splitFunc, err := LengthPrefixedSplitFunc(2, binary.BigEndian, 100)
+	require.NoError(t, err)
+	_, _, err = splitFunc([]byte{0, 101}, false)
+	assert.Equal(t, &RecordTooLongError{Length: 103}, err)
+	assert.EqualError(t, err, "record of 103 bytes exceeds max_log_size")
+
+	// The length prefix counts towards max_log_size
+	_, _, err = splitFunc([]byte{0, 99}, false)
+	assert.Equal(t, &RecordTooLongError{Length: 101}, err)
+}
+
+func TestJournalExportSplitFunc(t *testing.T) {
+	binaryField := func(name string, value string) string {
+		size := make([]byte, 8)
+		binary.LittleEndian.PutUint64(size, uint64(len(value)))
+		return name + "\n" + string(size) + value + "\n"
+	}
+
+	testCases := []struct {
+		name  string
+		input []byte
+		steps []splittest.Step
+	}{
+		{
+			name:  "EmptyFile",
+			input: []byte{},
+		},
+		{
+			name:  "TextFields",
+			input: []byte("__CURSOR=s=1;i=1\n__REALTIME_TIMESTAMP=1700000000000000\nMESSAGE=hello world\n_PID=42\n\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(84, `{"MESSAGE":"hello world","_PID":"42","__CURSOR":"s=1;i=1","__REALTIME_TIMESTAMP":"1700000000000000"}`),
+			},
+		},
+		{
+			name:  "TwoEntries",
+			input: []byte("MESSAGE=first\n\nMESSAGE=second\n\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(15, `{"MESSAGE":"first"}`),
+				splittest.ExpectAdvanceToken(16, `{"MESSAGE":"second"}`),
+			},
+		},
+		{
+			name:  "LeadingEmptyLines",
+			input: []byte("\n\nMESSAGE=first\n\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceNil(1),
+				splittest.ExpectAdvanceNil(1),
+				splittest.ExpectAdvanceToken(15, `{"MESSAGE":"first"}`),
+			},
+		},
+		{
+			name:  "BinaryFields",
+			input: []byte(binaryField("MESSAGE", "line1\nline2") + binaryField("DATA", "\xff\x00") + "\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(45, `{"DATA":[255,0],"MESSAGE":"line1\nline2"}`),
+			},
+		},
+		{
+			name:  "RepeatedFields",
+			input: []byte("TAG=a\nTAG=b\nTAG=c\n\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(19, `{"TAG":["a","b","c"]}`),
+			},
+		},
+		{
+			name:  "PartialEntry",
+			input: []byte("MESSAGE=first\n\nMESSAGE=second\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(15, `{"MESSAGE":"first"}`),
+			},
+		},
+		{
+			name:  "PartialBinaryField",
+			input: []byte(binaryField("MESSAGE", "line1\nline2")[:20]),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, splittest.New(JournalExportSplitFunc(100), tc.input, tc.steps...))
+	}
+}
+
+func TestJournalExportSplitFuncErrors(t *testing.T) {
+	splitFunc := JournalExportSplitFunc(100)
+
+	_, _, err := splitFunc([]byte("=value\n\n"), false)
+	assert.EqualError(t, err, `invalid journal export field "=value"`)
+
+	_, _, err = splitFunc([]byte("MESSAGE\n\x02\x00\x00\x00\x00\x00\x00\x00abc\n"), false)
+	assert.EqualError(t, err, `invalid journal export field "MESSAGE": value isn't followed by a newline`)
+
+	_, _, err = splitFunc(append([]byte("MESSAGE="), splittest.GenerateBytes(100)...), false)
+	assert.Equal(t, &RecordTooLongError{Length: 100, SkipLine: true}, err)
+	assert.EqualError(t, err, "record exceeds max_log_size")
+
+	size := make([]byte, 8)
+	binary.LittleEndian.PutUint64(size, 200)
+	_, _, err = splitFunc(append([]byte("MESSAGE\n"+string(size)), splittest.GenerateBytes(100)...), false)
+	assert.Equal(t, &RecordTooLongError{Length: 217}, err)
+}
+
+func TestJournalExportSplitFuncLongEntry(t *testing.T) {
+	splitFunc := JournalExportSplitFunc(100)
+
+	// The fields of an entry exceeding max_log_size are returned as several entries
+	data := []byte("MESSAGE=first\nDATA=" + string(splittest.GenerateBytes(90)) + "\n\n")
+	advance, token, err := splitFunc(data[:100], false)
+	require.NoError(t, err)
+	assert.Equal(t, 14, advance)
+	assert.Equal(t, `{"MESSAGE":"first"}`, string(token))
+
+	advance, token, err = splitFunc(data[advance:], false)
+	require.NoError(t, err)
+	assert.Equal(t, 97, advance)
+	assert.Equal(t, `{"DATA":"`+string(splittest.GenerateBytes(90))+`"}`, string(token))
+}
@@ -10,12 +10,15 @@ import (
 	"regexp"
 
 	"golang.org/x/text/encoding"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline"
 )
 
 // Config is the configuration for a split func
 type Config struct {
 	LineStartPattern string `mapstructure:"line_start_pattern"`
 	LineEndPattern   string `mapstructure:"line_end_pattern"`
+	Detector         string `mapstructure:"detector"`
 	OmitPattern      bool   `mapstructure:"omit_pattern"`
 }
 
@@ -28,9 +31,23 @@ func (c Config) Func(enc encoding.Encoding, flushAtEOF bool, maxLogSize int) (bu
 		if c.LineStartPattern != "" {
 			return nil, fmt.Errorf("line_start_pattern should not be set when using nop encoding")
 		}
+		if c.Detector != "" {
+			return nil, fmt.Errorf("detector should not be set when using nop encoding")
+		}
 		return NoSplitFunc(maxLogSize), nil
 	}
 
+	if c.Detector != "" {
+		if c.LineEndPattern != "" || c.LineStartPattern != "" {
+			return nil, fmt.Errorf("detector cannot be set with line_start_pattern or line_end_pattern")
+		}
+		d, err := multiline.Lookup(c.Detector)
+		if err != nil {
+			return nil, err
+		}
+		return DetectorSplitFunc(d, flushAtEOF), nil
+	}
+
 	if c.LineEndPattern == "" && c.LineStartPattern == "" {
 		return NewlineSplitFunc(enc, flushAtEOF)
 	}
@@ -136,6 +153,45 @@ func LineEndSplitFunc(re *regexp.Regexp, omitPattern bool, flushAtEOF bool) bufi
 	}
 }
 
+// DetectorSplitFunc creates a bufio.SplitFunc that splits an incoming stream into tokens made of the
+// lines of multiline entries recognized by the detector, such as stack traces. Lines which aren't part
+// of a multiline entry are tokens of their own.
+func DetectorSplitFunc(d *multiline.Detector, flushAtEOF bool) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		// The detector is run from the start of the token every time, since the
+		// split func can't keep its state between calls
+		state := multiline.Start
+		end := 0
+		for pos := 0; ; {
+			i := bytes.IndexByte(data[pos:], '\n')
+			if i < 0 {
+				break
+			}
+			line := bytes.TrimSuffix(data[pos:pos+i], []byte{'\r'})
+
+			var continues bool
+			state, continues = d.Next(state, line)
+			if pos > 0 && !continues {
+				// The line starts the next token
+				return pos, data[:end], nil
+			}
+			end = pos + len(line)
+			pos += i + 1
+
+			if state == multiline.Start {
+				// The next line can't continue the token
+				return pos, data[:end], nil
+			}
+		}
+
+		// Flush if no more data is expected
+		if len(data) != 0 && atEOF && flushAtEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil // read more data and try again
+	}
+}
+
 // NewlineSplitFunc splits log lines by newline, just as bufio.ScanLines, but
 // never returning an token using EOF as a terminator
 func NewlineSplitFunc(enc encoding.Encoding, flushAtEOF bool) (bufio.SplitFunc, error) {
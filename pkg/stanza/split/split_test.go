@@ -5,6 +5,7 @@ package split
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,6 +14,8 @@ import (
 	"golang.org/x/text/encoding/traditionalchinese"
 	"golang.org/x/text/encoding/unicode"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/multiline/multilinetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split/splittest"
 )
 
@@ -45,6 +48,33 @@ func TestConfigFunc(t *testing.T) {
 		startCfg := Config{LineStartPattern: "\n"}
 		_, err = startCfg.Func(encoding.Nop, false, 0)
 		require.Equal(t, err, fmt.Errorf("line_start_pattern should not be set when using nop encoding"))
+
+		detectorCfg := Config{Detector: "java"}
+		_, err = detectorCfg.Func(encoding.Nop, false, 0)
+		require.Equal(t, err, fmt.Errorf("detector should not be set when using nop encoding"))
+	})
+
+	t.Run("Detector", func(t *testing.T) {
+		cfg := Config{Detector: "java"}
+		f, err := cfg.Func(unicode.UTF8, false, maxLogSize)
+		assert.NoError(t, err)
+
+		advance, token, err := f([]byte("java.lang.Exception: boom\n\tat A.b(A.java:1)\nfoo\n"), false)
+		assert.NoError(t, err)
+		assert.Equal(t, 44, advance)
+		assert.Equal(t, []byte("java.lang.Exception: boom\n\tat A.b(A.java:1)"), token)
+	})
+
+	t.Run("DetectorWithPattern", func(t *testing.T) {
+		cfg := Config{Detector: "java", LineStartPattern: "foo"}
+		_, err := cfg.Func(unicode.UTF8, false, maxLogSize)
+		assert.EqualError(t, err, "detector cannot be set with line_start_pattern or line_end_pattern")
+	})
+
+	t.Run("UnknownDetector", func(t *testing.T) {
+		cfg := Config{Detector: "cobol"}
+		_, err := cfg.Func(unicode.UTF8, false, maxLogSize)
+		assert.EqualError(t, err, `unknown multiline detector "cobol", must be one of dotnet, go, java, nodejs, python`)
 	})
 
 	t.Run("Newline", func(t *testing.T) {
@@ -602,6 +632,77 @@ func TestNewlineSplitFunc(t *testing.T) {
 	}
 }
 
+func TestDetectorSplitFunc(t *testing.T) {
+	java, err := multiline.Lookup("java")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		input      []byte
+		flushAtEOF bool
+		steps      []splittest.Step
+	}{
+		{
+			name:  "EmptyFile",
+			input: []byte(""),
+		},
+		{
+			name:  "SingleLines",
+			input: []byte("log1\nlog2\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(5, "log1"),
+				splittest.ExpectAdvanceToken(5, "log2"),
+			},
+		},
+		{
+			name:  "CarriageReturns",
+			input: []byte("java.lang.Exception: boom\r\n\tat A.b(A.java:1)\r\nlog\r\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(46, "java.lang.Exception: boom\r\n\tat A.b(A.java:1)"),
+				splittest.ExpectAdvanceToken(5, "log"),
+			},
+		},
+		{
+			name:  "OpenEntry",
+			input: []byte("log\njava.lang.Exception: boom\n\tat A.b(A.java:1)\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(4, "log"),
+			},
+		},
+		{
+			name:       "OpenEntryFlushAtEOF",
+			input:      []byte("java.lang.Exception: boom\n\tat A.b(A.java:1)\n"),
+			flushAtEOF: true,
+			steps: []splittest.Step{
+				splittest.ExpectToken("java.lang.Exception: boom\n\tat A.b(A.java:1)\n"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, splittest.New(DetectorSplitFunc(java, tc.flushAtEOF), tc.input, tc.steps...))
+	}
+}
+
+func TestDetectorSplitFuncFixtures(t *testing.T) {
+	for _, name := range multiline.Names() {
+		d, err := multiline.Lookup(name)
+		require.NoError(t, err)
+
+		fixtures, err := filepath.Glob(filepath.Join("..", "multiline", "testdata", name, "*.log"))
+		require.NoError(t, err)
+
+		for _, fixture := range fixtures {
+			input, entries := multilinetest.ReadFixture(t, fixture)
+			steps := make([]splittest.Step, 0, len(entries))
+			for _, entry := range entries {
+				steps = append(steps, splittest.ExpectAdvanceToken(len(entry)+1, entry))
+			}
+			t.Run(name+"/"+filepath.Base(fixture), splittest.New(DetectorSplitFunc(d, false), input, steps...))
+		}
+	}
+}
+
 func TestNoSplitFunc(t *testing.T) {
 	const largeLogSize = 100
 	testCases := []struct {
@@ -39,6 +39,7 @@ const (
 	// https://github.com/OpenObservability/OpenMetrics/blob/main/specification/OpenMetrics.md#exemplars
 	maxExemplarRunes = 128
 	infoType         = "info"
+	targetInfoHelp   = "Target metadata"
 )
 
 type bucketBoundsData struct {
@@ -206,6 +207,7 @@ func (c *prometheusConverter) addHistogramDataPoints(dataPoints pmetric.Histogra
 		pt := dataPoints.At(x)
 		timestamp := convertTimeStamp(pt.Timestamp())
 		baseLabels := createAttributes(resource, pt.Attributes(), settings.ExternalLabels, nil, false)
+		c.current.createdTimestamp = convertTimeStamp(pt.StartTimestamp())
 
 		// If the sum is unset, it indicates the _sum metric point should be
 		// omitted
@@ -389,6 +391,7 @@ func (c *prometheusConverter) addSummaryDataPoints(dataPoints pmetric.SummaryDat
 		pt := dataPoints.At(x)
 		timestamp := convertTimeStamp(pt.Timestamp())
 		baseLabels := createAttributes(resource, pt.Attributes(), settings.ExternalLabels, nil, false)
+		c.current.createdTimestamp = convertTimeStamp(pt.StartTimestamp())
 
 		// treat sum as a sample in an individual TimeSeries
 		sum := &prompb.Sample{
@@ -478,6 +481,7 @@ func (c *prometheusConverter) getOrCreateTimeSeries(lbls []prompb.Label) (*promp
 			Labels: lbls,
 		}
 		c.conflicts[h] = append(c.conflicts[h], ts)
+		c.addMetadata(ts)
 		return ts, true
 	}
 
@@ -486,9 +490,17 @@ func (c *prometheusConverter) getOrCreateTimeSeries(lbls []prompb.Label) (*promp
 		Labels: lbls,
 	}
 	c.unique[h] = ts
+	c.addMetadata(ts)
 	return ts, true
 }
 
+// addMetadata records the metadata of a new time series, if converting to remote write 2.0.
+func (c *prometheusConverter) addMetadata(ts *prompb.TimeSeries) {
+	if c.metadata != nil {
+		c.metadata[ts] = c.current
+	}
+}
+
 // addTimeSeriesIfNeeded adds a corresponding time series if it doesn't already exist.
 // If the time series doesn't already exist, it gets added with startTimestamp for its value and timestamp for its timestamp,
 // both converted to milliseconds.
@@ -29,6 +29,7 @@ func (c *prometheusConverter) addExponentialHistogramDataPoints(dataPoints pmetr
 			model.MetricNameLabel,
 			baseName,
 		)
+		c.current.createdTimestamp = convertTimeStamp(pt.StartTimestamp())
 		ts, _ := c.getOrCreateTimeSeries(lbls)
 
 		histogram, err := exponentialToNativeHistogram(pt)
@@ -43,6 +43,11 @@ func FromMetrics(md pmetric.Metrics, settings Settings) (map[string]*prompb.Time
 type prometheusConverter struct {
 	unique    map[uint64]*prompb.TimeSeries
 	conflicts map[uint64][]*prompb.TimeSeries
+	// metadata holds the metadata of each series when converting to remote write 2.0,
+	// which sends it along with the series. It is nil otherwise.
+	metadata map[*prompb.TimeSeries]seriesMetadata
+	// current is the metadata of the series created for the data point being converted.
+	current seriesMetadata
 }
 
 func newPrometheusConverter() *prometheusConverter {
@@ -76,6 +81,11 @@ func (c *prometheusConverter) fromMetrics(md pmetric.Metrics, settings Settings)
 				}
 
 				promName := prometheustranslator.BuildCompliantName(metric, settings.Namespace, settings.AddMetricSuffixes)
+				c.current = seriesMetadata{
+					metricType: otelMetricTypeToPromMetricType(metric),
+					help:       metric.Description(),
+					unit:       metric.Unit(),
+				}
 
 				// handle individual metrics based on type
 				//exhaustive:enforce
@@ -125,6 +135,7 @@ func (c *prometheusConverter) fromMetrics(md pmetric.Metrics, settings Settings)
 				}
 			}
 		}
+		c.current = seriesMetadata{metricType: prompb.MetricMetadata_GAUGE, help: targetInfoHelp}
 		addResourceTargetInfo(resource, settings, mostRecentTimestamp, c)
 	}
 
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"strconv"
+
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// seriesMetadata is the metadata sent along with each series of remote write 2.0 requests.
+type seriesMetadata struct {
+	metricType       prompb.MetricMetadata_MetricType
+	help             string
+	unit             string
+	createdTimestamp int64
+}
+
+// FromMetricsV2 converts pmetric.Metrics to Prometheus remote write 2.0 time series, returning
+// the symbols referenced by the series. The metadata and the start timestamp of the metrics are
+// sent with each series, so Settings.SendMetadata and Settings.ExportCreatedMetric are ignored.
+func FromMetricsV2(md pmetric.Metrics, settings Settings) (map[string]*writev2.TimeSeries, []string, error) {
+	c := newPrometheusConverter()
+	c.metadata = map[*prompb.TimeSeries]seriesMetadata{}
+	settings.ExportCreatedMetric = false
+	errs := c.fromMetrics(md, settings)
+
+	symbols := writev2.NewSymbolTable()
+	out := make(map[string]*writev2.TimeSeries, len(c.metadata))
+	for _, ts := range c.unique {
+		out[strconv.Itoa(len(out))] = c.toTimeSeriesV2(ts, &symbols)
+	}
+	for _, cTS := range c.conflicts {
+		for _, ts := range cTS {
+			out[strconv.Itoa(len(out))] = c.toTimeSeriesV2(ts, &symbols)
+		}
+	}
+
+	return out, symbols.Symbols(), errs
+}
+
+// toTimeSeriesV2 converts a time series to remote write 2.0, adding its strings to symbols.
+func (c *prometheusConverter) toTimeSeriesV2(ts *prompb.TimeSeries, symbols *writev2.SymbolsTable) *writev2.TimeSeries {
+	m := c.metadata[ts]
+	out := &writev2.TimeSeries{
+		LabelsRefs: symbolizeLabels(ts.Labels, symbols),
+		Metadata: writev2.Metadata{
+			// The metric types of both protocols have the same values.
+			Type:    writev2.Metadata_MetricType(m.metricType),
+			HelpRef: symbols.Symbolize(m.help),
+			UnitRef: symbols.Symbolize(m.unit),
+		},
+		CreatedTimestamp: m.createdTimestamp,
+	}
+
+	if len(ts.Samples) > 0 {
+		out.Samples = make([]writev2.Sample, 0, len(ts.Samples))
+		for _, s := range ts.Samples {
+			out.Samples = append(out.Samples, writev2.Sample{Value: s.Value, Timestamp: s.Timestamp})
+		}
+	}
+	if len(ts.Histograms) > 0 {
+		out.Histograms = make([]writev2.Histogram, 0, len(ts.Histograms))
+		for _, h := range ts.Histograms {
+			if h.IsFloatHistogram() {
+				out.Histograms = append(out.Histograms, writev2.FromFloatHistogram(h.Timestamp, h.ToFloatHistogram()))
+			} else {
+				out.Histograms = append(out.Histograms, writev2.FromIntHistogram(h.Timestamp, h.ToIntHistogram()))
+			}
+		}
+	}
+	if len(ts.Exemplars) > 0 {
+		out.Exemplars = make([]writev2.Exemplar, 0, len(ts.Exemplars))
+		for _, e := range ts.Exemplars {
+			out.Exemplars = append(out.Exemplars, writev2.Exemplar{
+				LabelsRefs: symbolizeLabels(e.Labels, symbols),
+				Value:      e.Value,
+				Timestamp:  e.Timestamp,
+			})
+		}
+	}
+	return out
+}
+
+func symbolizeLabels(lbls []prompb.Label, symbols *writev2.SymbolsTable) []uint32 {
+	refs := make([]uint32, 0, 2*len(lbls))
+	for _, l := range lbls {
+		refs = append(refs, symbols.Symbolize(l.Name), symbols.Symbolize(l.Value))
+	}
+	return refs
+}
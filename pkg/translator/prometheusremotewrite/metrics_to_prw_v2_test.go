@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	conventions "go.opentelemetry.io/collector/semconv/v1.25.0"
+)
+
+func TestFromMetricsV2(t *testing.T) {
+	start := time.Date(2024, 9, 1, 11, 0, 0, 0, time.UTC)
+	ts := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr(conventions.AttributeServiceName, "api")
+	rm.Resource().Attributes().PutStr(conventions.AttributeServiceInstanceID, "host:8080")
+	rm.Resource().Attributes().PutStr("host.name", "host")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	counter := metrics.AppendEmpty()
+	counter.SetName("http.server.requests")
+	counter.SetDescription("Number of requests")
+	counter.SetUnit("1")
+	sum := counter.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	dp.SetIntValue(42)
+	dp.Attributes().PutStr("method", "GET")
+	exemplar := dp.Exemplars().AppendEmpty()
+	exemplar.SetDoubleValue(1)
+	exemplar.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	exemplar.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("memory.usage")
+	gauge.SetDescription("Memory in use")
+	gauge.SetUnit("By")
+	gdp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	gdp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	gdp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	gdp.SetDoubleValue(1024)
+
+	latency := metrics.AppendEmpty()
+	latency.SetName("latency")
+	latency.SetUnit("s")
+	expHist := latency.SetEmptyExponentialHistogram()
+	expHist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	edp := expHist.DataPoints().AppendEmpty()
+	edp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	edp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	edp.SetScale(1)
+	edp.SetCount(5)
+	edp.SetSum(3)
+	edp.SetZeroCount(1)
+	edp.Positive().SetOffset(-1)
+	edp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 1})
+
+	tsMap, symbols, err := FromMetricsV2(md, Settings{AddMetricSuffixes: true, ExportCreatedMetric: true, SendMetadata: true})
+	require.NoError(t, err)
+	assert.Equal(t, "", symbols[0])
+
+	b := labels.NewScratchBuilder(0)
+	series := map[string]*writev2.TimeSeries{}
+	for _, s := range tsMap {
+		series[s.ToLabels(&b, symbols).Get(model.MetricNameLabel)] = s
+	}
+	require.Len(t, series, 4, "the created timestamps replace the _created series")
+
+	requests := series["http_server_requests_total"]
+	require.NotNil(t, requests)
+	assert.Equal(t, labels.FromStrings(
+		model.MetricNameLabel, "http_server_requests_total",
+		model.JobLabel, "api",
+		model.InstanceLabel, "host:8080",
+		"method", "GET",
+	), requests.ToLabels(&b, symbols))
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_COUNTER, requests.Metadata.Type)
+	assert.Equal(t, "Number of requests", symbols[requests.Metadata.HelpRef])
+	assert.Equal(t, "1", symbols[requests.Metadata.UnitRef])
+	assert.Equal(t, start.UnixMilli(), requests.CreatedTimestamp)
+	assert.Equal(t, []writev2.Sample{{Value: 42, Timestamp: ts.UnixMilli()}}, requests.Samples)
+	require.Len(t, requests.Exemplars, 1)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", requests.Exemplars[0].ToExemplar(&b, symbols).Labels.Get("trace_id"))
+
+	memory := series["memory_usage_bytes"]
+	require.NotNil(t, memory)
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_GAUGE, memory.Metadata.Type)
+	assert.Equal(t, "By", symbols[memory.Metadata.UnitRef])
+	assert.Zero(t, memory.CreatedTimestamp, "gauges have no created timestamp")
+
+	hist := series["latency_seconds"]
+	require.NotNil(t, hist)
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_HISTOGRAM, hist.Metadata.Type)
+	assert.Equal(t, start.UnixMilli(), hist.CreatedTimestamp)
+	require.Len(t, hist.Histograms, 1)
+	h := hist.Histograms[0].ToIntHistogram()
+	assert.Equal(t, int32(1), h.Schema)
+	assert.Equal(t, uint64(5), h.Count)
+	assert.Equal(t, uint64(1), h.ZeroCount)
+	assert.Equal(t, ts.UnixMilli(), hist.Histograms[0].Timestamp)
+
+	targetInfo := series["target_info"]
+	require.NotNil(t, targetInfo)
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_GAUGE, targetInfo.Metadata.Type)
+	assert.Equal(t, targetInfoHelp, symbols[targetInfo.Metadata.HelpRef])
+
+	t.Run("roundtrip", func(t *testing.T) {
+		req := &writev2.Request{Symbols: symbols}
+		for _, s := range tsMap {
+			req.Timeseries = append(req.Timeseries, *s)
+		}
+		got, stats, err := ToMetricsV2(req, ToMetricsSettings{})
+		require.NoError(t, err)
+		assert.Equal(t, WriteStats{Samples: 3, Histograms: 1, Exemplars: 1}, stats)
+
+		require.Equal(t, 1, got.ResourceMetrics().Len())
+		resource := got.ResourceMetrics().At(0).Resource().Attributes().AsRaw()
+		assert.Equal(t, "host", resource["host_name"])
+		gotMetrics := got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		byName := map[string]pmetric.Metric{}
+		for i := 0; i < gotMetrics.Len(); i++ {
+			byName[gotMetrics.At(i).Name()] = gotMetrics.At(i)
+		}
+		gotCounter := byName["http_server_requests_total"]
+		require.Equal(t, pmetric.MetricTypeSum, gotCounter.Type())
+		assert.Equal(t, "Number of requests", gotCounter.Description())
+		assert.Equal(t, pcommon.NewTimestampFromTime(start), gotCounter.Sum().DataPoints().At(0).StartTimestamp())
+		assert.Equal(t, "By", byName["memory_usage_bytes"].Unit())
+		assert.Equal(t, pmetric.MetricTypeExponentialHistogram, byName["latency_seconds"].Type())
+	})
+}
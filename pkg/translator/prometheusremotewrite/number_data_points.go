@@ -69,6 +69,9 @@ func (c *prometheusConverter) addSumNumberDataPoints(dataPoints pmetric.NumberDa
 		if pt.Flags().NoRecordedValue() {
 			sample.Value = math.Float64frombits(value.StaleNaN)
 		}
+		if metric.Sum().IsMonotonic() {
+			c.current.createdTimestamp = convertTimeStamp(pt.StartTimestamp())
+		}
 		ts := c.addSample(sample, lbls)
 		if ts != nil {
 			exemplars := getPromExemplars[pmetric.NumberDataPoint](pt)
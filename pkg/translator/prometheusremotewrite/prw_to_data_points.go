@@ -0,0 +1,285 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"encoding/hex"
+	"math"
+	"sort"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	conventions "go.opentelemetry.io/collector/semconv/v1.25.0"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+)
+
+// metrics builds the pmetric.Metrics of the converted series.
+func (c *metricsConverter) metrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for _, r := range c.resources {
+		rm := md.ResourceMetrics().AppendEmpty()
+		attrs := rm.Resource().Attributes()
+		if targetInfo, ok := c.targetInfo[r.key]; ok {
+			targetInfo.Range(func(l labels.Label) {
+				switch l.Name {
+				case model.MetricNameLabel, model.JobLabel, model.InstanceLabel:
+				default:
+					attrs.PutStr(l.Name, l.Value)
+				}
+			})
+		}
+		if r.key.job != "" {
+			attrs.PutStr(conventions.AttributeServiceName, r.key.job)
+		}
+		if r.key.instance != "" {
+			attrs.PutStr(conventions.AttributeServiceInstanceID, r.key.instance)
+		}
+
+		for _, s := range r.scopes {
+			sm := rm.ScopeMetrics().AppendEmpty()
+			sm.Scope().SetName(s.key.name)
+			sm.Scope().SetVersion(s.key.version)
+			for _, f := range s.families {
+				f.appendMetric(sm.Metrics(), c.settings)
+			}
+		}
+	}
+	return md
+}
+
+func (f *metricFamily) appendMetric(metrics pmetric.MetricSlice, settings ToMetricsSettings) {
+	metric := metrics.AppendEmpty()
+	metric.SetDescription(f.metadata.Help)
+	metric.SetUnit(f.metadata.Unit)
+
+	switch f.kind {
+	case gaugeFamily:
+		dataPoints := metric.SetEmptyGauge().DataPoints()
+		for _, p := range f.points {
+			p.toNumberDataPoint(dataPoints.AppendEmpty())
+		}
+	case counterFamily:
+		sum := metric.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		for _, p := range f.points {
+			p.toNumberDataPoint(sum.DataPoints().AppendEmpty())
+		}
+	case histogramFamily:
+		hist := metric.SetEmptyHistogram()
+		hist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		for _, p := range f.points {
+			p.toHistogramDataPoint(hist.DataPoints().AppendEmpty())
+		}
+	case summaryFamily:
+		dataPoints := metric.SetEmptySummary().DataPoints()
+		for _, p := range f.points {
+			p.toSummaryDataPoint(dataPoints.AppendEmpty())
+		}
+	case exponentialHistogramFamily:
+		hist := metric.SetEmptyExponentialHistogram()
+		hist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		for _, p := range f.points {
+			p.toExponentialHistogramDataPoint(hist.DataPoints().AppendEmpty())
+		}
+	}
+
+	name := f.name
+	if settings.TrimMetricSuffixes {
+		name = prometheustranslator.TrimPromSuffixes(name, metric.Type(), f.metadata.Unit)
+	}
+	metric.SetName(name)
+}
+
+type dataPointWithAttributes interface {
+	Attributes() pcommon.Map
+	SetStartTimestamp(pcommon.Timestamp)
+	SetTimestamp(pcommon.Timestamp)
+	SetFlags(pmetric.DataPointFlags)
+}
+
+func (p *dataPoint) copyCommonFields(dp dataPointWithAttributes) {
+	attrs := dp.Attributes()
+	attrs.EnsureCapacity(p.labels.Len())
+	p.labels.Range(func(l labels.Label) {
+		attrs.PutStr(l.Name, l.Value)
+	})
+	if p.startTimestamp != 0 {
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(timestamp.Time(p.startTimestamp)))
+	}
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(timestamp.Time(p.timestamp)))
+	if p.stale {
+		dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+	}
+}
+
+func (p *dataPoint) toNumberDataPoint(dp pmetric.NumberDataPoint) {
+	p.copyCommonFields(dp)
+	dp.SetDoubleValue(p.value)
+	copyExemplars(p.exemplars, dp.Exemplars())
+}
+
+// toHistogramDataPoint converts the cumulative bucket counts of a classic histogram
+// to the bucket counts of an explicit bucket histogram.
+func (p *dataPoint) toHistogramDataPoint(dp pmetric.HistogramDataPoint) {
+	p.copyCommonFields(dp)
+	sort.SliceStable(p.buckets, func(i, j int) bool {
+		return p.buckets[i].bound < p.buckets[j].bound
+	})
+
+	count := p.count
+	var previous float64
+	for _, b := range p.buckets {
+		if math.IsInf(b.bound, 1) {
+			if !p.hasCount {
+				count = b.value
+			}
+			break
+		}
+		dp.ExplicitBounds().Append(b.bound)
+		dp.BucketCounts().Append(toCount(b.value - previous))
+		previous = b.value
+	}
+	if !p.hasCount && (len(p.buckets) == 0 || !math.IsInf(p.buckets[len(p.buckets)-1].bound, 1)) {
+		count = previous
+	}
+	dp.BucketCounts().Append(toCount(count - previous))
+	dp.SetCount(toCount(count))
+	if p.hasSum {
+		dp.SetSum(p.sum)
+	}
+	copyExemplars(p.exemplars, dp.Exemplars())
+}
+
+func (p *dataPoint) toSummaryDataPoint(dp pmetric.SummaryDataPoint) {
+	p.copyCommonFields(dp)
+	sort.SliceStable(p.buckets, func(i, j int) bool {
+		return p.buckets[i].bound < p.buckets[j].bound
+	})
+	for _, b := range p.buckets {
+		q := dp.QuantileValues().AppendEmpty()
+		q.SetQuantile(b.bound)
+		q.SetValue(b.value)
+	}
+	dp.SetCount(toCount(p.count))
+	dp.SetSum(p.sum)
+}
+
+// toExponentialHistogramDataPoint converts a native histogram with an exponential schema.
+// The schema of native histograms is the scale of exponential histograms, but the
+// Prometheus bucket index is one more than the OpenTelemetry one for the same bucket.
+func (p *dataPoint) toExponentialHistogramDataPoint(dp pmetric.ExponentialHistogramDataPoint) {
+	p.copyCommonFields(dp)
+	copyExemplars(p.exemplars, dp.Exemplars())
+	if p.stale {
+		return
+	}
+	h := p.histogram
+	dp.SetScale(h.Schema)
+	dp.SetZeroThreshold(h.ZeroThreshold)
+	dp.SetZeroCount(toCount(h.ZeroCount))
+	dp.SetCount(toCount(h.Count))
+	dp.SetSum(h.Sum)
+	copyBuckets(h.PositiveSpans, h.PositiveBuckets, dp.Positive())
+	copyBuckets(h.NegativeSpans, h.NegativeBuckets, dp.Negative())
+}
+
+func copyBuckets(spans []histogram.Span, counts []float64, buckets pmetric.ExponentialHistogramDataPointBuckets) {
+	if len(spans) == 0 {
+		return
+	}
+	buckets.SetOffset(spans[0].Offset - 1)
+	bucketCounts := buckets.BucketCounts()
+	i := 0
+	for s, span := range spans {
+		if s > 0 {
+			for j := int32(0); j < span.Offset; j++ {
+				bucketCounts.Append(0)
+			}
+		}
+		for j := uint32(0); j < span.Length; j++ {
+			bucketCounts.Append(toCount(counts[i]))
+			i++
+		}
+	}
+}
+
+// customBuckets returns the cumulative bucket counts of a native histogram with custom
+// buckets, which are equivalent to the buckets of a classic histogram.
+func customBuckets(h *histogram.FloatHistogram) []bucket {
+	counts := make([]float64, len(h.CustomValues)+1)
+	index, i := int32(0), 0
+	for _, span := range h.PositiveSpans {
+		index += span.Offset
+		for j := uint32(0); j < span.Length; j++ {
+			if int(index) < len(counts) {
+				counts[index] += h.PositiveBuckets[i]
+			}
+			index++
+			i++
+		}
+	}
+
+	buckets := make([]bucket, 0, len(counts))
+	var cumulative float64
+	for j, count := range counts {
+		cumulative += count
+		bound := math.Inf(1)
+		if j < len(h.CustomValues) {
+			bound = h.CustomValues[j]
+		}
+		buckets = append(buckets, bucket{bound: bound, value: cumulative})
+	}
+	return buckets
+}
+
+func copyExemplars(exemplars []exemplar.Exemplar, dest pmetric.ExemplarSlice) {
+	for _, e := range exemplars {
+		ex := dest.AppendEmpty()
+		ex.SetDoubleValue(e.Value)
+		if e.HasTs {
+			ex.SetTimestamp(pcommon.NewTimestampFromTime(timestamp.Time(e.Ts)))
+		}
+		e.Labels.Range(func(l labels.Label) {
+			switch l.Name {
+			case prometheustranslator.ExemplarTraceIDKey:
+				var traceID pcommon.TraceID
+				if decodeID(traceID[:], l.Value) {
+					ex.SetTraceID(traceID)
+					return
+				}
+			case prometheustranslator.ExemplarSpanIDKey:
+				var spanID pcommon.SpanID
+				if decodeID(spanID[:], l.Value) {
+					ex.SetSpanID(spanID)
+					return
+				}
+			}
+			ex.FilteredAttributes().PutStr(l.Name, l.Value)
+		})
+	}
+}
+
+// decodeID decodes a hex encoded trace or span ID, returning false if it isn't valid.
+func decodeID(dst []byte, id string) bool {
+	if hex.DecodedLen(len(id)) != len(dst) {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(id))
+	return err == nil
+}
+
+// toCount rounds a Prometheus float count to an OpenTelemetry count.
+func toCount(v float64) uint64 {
+	if !(v > 0) || math.IsInf(v, 1) {
+		return 0
+	}
+	return uint64(math.Round(v))
+}
@@ -0,0 +1,627 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+)
+
+// ToMetricsSettings configures the conversion of Prometheus remote write requests to pmetric.Metrics.
+type ToMetricsSettings struct {
+	// TrimMetricSuffixes removes the type and unit suffixes from the metric names,
+	// as added by FromMetrics when AddMetricSuffixes is set.
+	TrimMetricSuffixes bool
+}
+
+// WriteStats counts the samples, native histogram samples and exemplars of a
+// remote write request that were converted to metrics.
+type WriteStats struct {
+	Samples    int
+	Histograms int
+	Exemplars  int
+}
+
+// ToMetrics converts a Prometheus remote write 1.0 request to pmetric.Metrics.
+//
+// The metric types are taken from the metadata of the request, or guessed from the
+// metric names and labels when the metadata is missing. Series are grouped into
+// resources by their job and instance labels, and the labels of the target_info
+// series are added to the attributes of the resource with the same job and instance.
+// Invalid series are skipped, and reported in the returned error along with the
+// metrics converted from the valid ones.
+func ToMetrics(req *prompb.WriteRequest, settings ToMetricsSettings) (pmetric.Metrics, WriteStats, error) {
+	c := newMetricsConverter(settings)
+
+	familyMetadata := make(map[string]metadata.Metadata, len(req.Metadata))
+	for _, md := range req.Metadata {
+		familyMetadata[md.MetricFamilyName] = metadata.Metadata{
+			Type: metricTypeFromV1(md.Type),
+			Unit: md.Unit,
+			Help: md.Help,
+		}
+	}
+
+	var b labels.ScratchBuilder
+	series := make([]promSeries, 0, len(req.Timeseries))
+	for i := range req.Timeseries {
+		ts := &req.Timeseries[i]
+		s := promSeries{
+			labels:     ts.ToLabels(&b, nil),
+			samples:    make([]sample, 0, len(ts.Samples)),
+			histograms: make([]nativeHistogram, 0, len(ts.Histograms)),
+			exemplars:  make([]exemplar.Exemplar, 0, len(ts.Exemplars)),
+		}
+		s.metadata = lookupFamilyMetadata(familyMetadata, s.labels.Get(model.MetricNameLabel))
+		for _, smpl := range ts.Samples {
+			s.samples = append(s.samples, sample{timestamp: smpl.Timestamp, value: smpl.Value})
+		}
+		for _, h := range ts.Histograms {
+			s.histograms = append(s.histograms, nativeHistogram{timestamp: h.Timestamp, histogram: h.ToFloatHistogram()})
+		}
+		for _, e := range ts.Exemplars {
+			s.exemplars = append(s.exemplars, e.ToExemplar(&b, nil))
+		}
+		series = append(series, s)
+	}
+
+	return c.convert(series)
+}
+
+// ToMetricsV2 converts a Prometheus remote write 2.0 request to pmetric.Metrics.
+//
+// The metric types, units and descriptions are taken from the metadata of each
+// series, and the created timestamps of the series are used as start timestamps.
+// Resources are built as in ToMetrics.
+func ToMetricsV2(req *writev2.Request, settings ToMetricsSettings) (pmetric.Metrics, WriteStats, error) {
+	c := newMetricsConverter(settings)
+
+	var b labels.ScratchBuilder
+	series := make([]promSeries, 0, len(req.Timeseries))
+	for i := range req.Timeseries {
+		ts := &req.Timeseries[i]
+		s, err := seriesFromV2(&b, ts, req.Symbols)
+		if err != nil {
+			c.errs = multierr.Append(c.errs, fmt.Errorf("invalid series at index %d: %w", i, err))
+			continue
+		}
+		series = append(series, s)
+	}
+
+	return c.convert(series)
+}
+
+func seriesFromV2(b *labels.ScratchBuilder, ts *writev2.TimeSeries, symbols []string) (promSeries, error) {
+	lbls, err := desymbolizeLabels(b, ts.LabelsRefs, symbols)
+	if err != nil {
+		return promSeries{}, err
+	}
+	if int(ts.Metadata.HelpRef) >= len(symbols) || int(ts.Metadata.UnitRef) >= len(symbols) {
+		return promSeries{}, errors.New("metadata references a symbol out of the symbols table")
+	}
+
+	s := promSeries{
+		labels:           lbls,
+		metadata:         ts.ToMetadata(symbols),
+		createdTimestamp: ts.CreatedTimestamp,
+		samples:          make([]sample, 0, len(ts.Samples)),
+		histograms:       make([]nativeHistogram, 0, len(ts.Histograms)),
+		exemplars:        make([]exemplar.Exemplar, 0, len(ts.Exemplars)),
+	}
+	for _, smpl := range ts.Samples {
+		s.samples = append(s.samples, sample{timestamp: smpl.Timestamp, value: smpl.Value})
+	}
+	for _, h := range ts.Histograms {
+		s.histograms = append(s.histograms, nativeHistogram{timestamp: h.Timestamp, histogram: h.ToFloatHistogram()})
+	}
+	for _, e := range ts.Exemplars {
+		exemplarLabels, err := desymbolizeLabels(b, e.LabelsRefs, symbols)
+		if err != nil {
+			return promSeries{}, fmt.Errorf("invalid exemplar: %w", err)
+		}
+		s.exemplars = append(s.exemplars, exemplar.Exemplar{
+			Labels: exemplarLabels,
+			Value:  e.Value,
+			Ts:     e.Timestamp,
+			HasTs:  e.Timestamp != 0,
+		})
+	}
+	return s, nil
+}
+
+// desymbolizeLabels decodes the label references of a remote write 2.0 series or exemplar,
+// checking that they are within the symbols table.
+func desymbolizeLabels(b *labels.ScratchBuilder, refs []uint32, symbols []string) (labels.Labels, error) {
+	if len(refs)%2 != 0 {
+		return labels.EmptyLabels(), fmt.Errorf("odd number of label references: %d", len(refs))
+	}
+	b.Reset()
+	for i := 0; i < len(refs); i += 2 {
+		if int(refs[i]) >= len(symbols) || int(refs[i+1]) >= len(symbols) {
+			return labels.EmptyLabels(), errors.New("label references a symbol out of the symbols table")
+		}
+		b.Add(symbols[refs[i]], symbols[refs[i+1]])
+	}
+	b.Sort()
+	return b.Labels(), nil
+}
+
+func metricTypeFromV1(t prompb.MetricMetadata_MetricType) model.MetricType {
+	switch t {
+	case prompb.MetricMetadata_COUNTER:
+		return model.MetricTypeCounter
+	case prompb.MetricMetadata_GAUGE:
+		return model.MetricTypeGauge
+	case prompb.MetricMetadata_HISTOGRAM:
+		return model.MetricTypeHistogram
+	case prompb.MetricMetadata_GAUGEHISTOGRAM:
+		return model.MetricTypeGaugeHistogram
+	case prompb.MetricMetadata_SUMMARY:
+		return model.MetricTypeSummary
+	case prompb.MetricMetadata_INFO:
+		return model.MetricTypeInfo
+	case prompb.MetricMetadata_STATESET:
+		return model.MetricTypeStateset
+	default:
+		return model.MetricTypeUnknown
+	}
+}
+
+// familySuffixes are the suffixes of the series that belong to a metric family
+// with a different name, such as the buckets of a histogram.
+var familySuffixes = []string{bucketStr, sumStr, countStr, "_gsum", "_gcount", "_total", createdSuffix}
+
+// lookupFamilyMetadata returns the metadata of the metric family of a remote write 1.0 series,
+// which is keyed by the family name rather than the series name.
+func lookupFamilyMetadata(familyMetadata map[string]metadata.Metadata, name string) metadata.Metadata {
+	if md, ok := familyMetadata[name]; ok && md.Type != model.MetricTypeUnknown {
+		return md
+	}
+	for _, suffix := range familySuffixes {
+		if base, ok := strings.CutSuffix(name, suffix); ok {
+			if md, ok := familyMetadata[base]; ok {
+				return md
+			}
+		}
+	}
+	return familyMetadata[name]
+}
+
+type sample struct {
+	timestamp int64
+	value     float64
+}
+
+type nativeHistogram struct {
+	timestamp int64
+	histogram *histogram.FloatHistogram
+}
+
+// promSeries is a series of a remote write request of either version.
+type promSeries struct {
+	labels           labels.Labels
+	metadata         metadata.Metadata
+	createdTimestamp int64
+	samples          []sample
+	histograms       []nativeHistogram
+	exemplars        []exemplar.Exemplar
+}
+
+type familyKind int
+
+const (
+	gaugeFamily familyKind = iota
+	counterFamily
+	histogramFamily
+	summaryFamily
+	exponentialHistogramFamily
+)
+
+// seriesRole tells how the samples of a series contribute to the data points of its family.
+type seriesRole int
+
+const (
+	valueRole seriesRole = iota
+	bucketRole
+	quantileRole
+	sumRole
+	countRole
+)
+
+// histogramSuffixes are the suffixes of the sum and count series of histograms,
+// including the gauge histogram ones.
+var histogramSuffixes = []struct {
+	suffix string
+	role   seriesRole
+}{
+	{sumStr, sumRole},
+	{countStr, countRole},
+	{"_gsum", sumRole},
+	{"_gcount", countRole},
+}
+
+type resourceKey struct {
+	job      string
+	instance string
+}
+
+type scopeKey struct {
+	name    string
+	version string
+}
+
+type familyKey struct {
+	name string
+	kind familyKind
+}
+
+type dataPointKey struct {
+	labels    string
+	timestamp int64
+}
+
+// metricsConverter groups the series of a remote write request into resources,
+// scopes and metric families, keeping the order in which they were first seen.
+type metricsConverter struct {
+	settings ToMetricsSettings
+
+	resources     []*resourceGroup
+	resourceIndex map[resourceKey]*resourceGroup
+	targetInfo    map[resourceKey]labels.Labels
+
+	// histogramFamilies and summaryFamilies are the names of the families that have
+	// bucket or quantile series, used to guess the type of series without metadata.
+	histogramFamilies map[string]bool
+	summaryFamilies   map[string]bool
+
+	stats WriteStats
+	errs  error
+}
+
+type resourceGroup struct {
+	key        resourceKey
+	scopes     []*scopeGroup
+	scopeIndex map[scopeKey]*scopeGroup
+}
+
+type scopeGroup struct {
+	key         scopeKey
+	families    []*metricFamily
+	familyIndex map[familyKey]*metricFamily
+}
+
+type metricFamily struct {
+	name       string
+	kind       familyKind
+	metadata   metadata.Metadata
+	points     []*dataPoint
+	pointIndex map[dataPointKey]*dataPoint
+}
+
+// dataPoint accumulates the samples of the series of a family that share the same
+// labels, besides the bucket and quantile labels, and timestamp.
+type dataPoint struct {
+	labels         labels.Labels
+	timestamp      int64
+	startTimestamp int64
+	stale          bool
+
+	value     float64
+	buckets   []bucket
+	sum       float64
+	hasSum    bool
+	count     float64
+	hasCount  bool
+	histogram *histogram.FloatHistogram
+
+	exemplars []exemplar.Exemplar
+}
+
+// bucket is the cumulative count of a histogram bucket, or the value of a summary quantile.
+type bucket struct {
+	bound float64
+	value float64
+}
+
+func newMetricsConverter(settings ToMetricsSettings) *metricsConverter {
+	return &metricsConverter{
+		settings:          settings,
+		resourceIndex:     map[resourceKey]*resourceGroup{},
+		targetInfo:        map[resourceKey]labels.Labels{},
+		histogramFamilies: map[string]bool{},
+		summaryFamilies:   map[string]bool{},
+	}
+}
+
+func (c *metricsConverter) convert(series []promSeries) (pmetric.Metrics, WriteStats, error) {
+	for i := range series {
+		lbls := series[i].labels
+		name := lbls.Get(model.MetricNameLabel)
+		if base, ok := strings.CutSuffix(name, bucketStr); ok && lbls.Has(model.BucketLabel) {
+			c.histogramFamilies[base] = true
+		}
+		if lbls.Has(model.QuantileLabel) {
+			c.summaryFamilies[name] = true
+		}
+	}
+
+	for i := range series {
+		if err := c.addSeries(&series[i]); err != nil {
+			c.errs = multierr.Append(c.errs, err)
+		}
+	}
+
+	return c.metrics(), c.stats, c.errs
+}
+
+func (c *metricsConverter) addSeries(s *promSeries) error {
+	name := s.labels.Get(model.MetricNameLabel)
+	if name == "" {
+		return fmt.Errorf("series %s has no metric name", s.labels)
+	}
+
+	rk := resourceKey{job: s.labels.Get(model.JobLabel), instance: s.labels.Get(model.InstanceLabel)}
+	if name == prometheustranslator.TargetInfoMetricName {
+		c.targetInfo[rk] = s.labels
+		c.stats.Samples += len(s.samples)
+		return nil
+	}
+
+	scope := c.resource(rk).scope(scopeKey{
+		name:    s.labels.Get(prometheustranslator.ScopeNameLabelKey),
+		version: s.labels.Get(prometheustranslator.ScopeVersionLabelKey),
+	})
+
+	points, err := c.addNativeHistograms(scope, name, s)
+	if err != nil {
+		return err
+	}
+	samplePoints, err := c.addSamples(scope, name, s)
+	if err != nil {
+		return err
+	}
+	c.addExemplars(append(points, samplePoints...), s.exemplars)
+	return nil
+}
+
+func (c *metricsConverter) addSamples(scope *scopeGroup, name string, s *promSeries) ([]*dataPoint, error) {
+	if len(s.samples) == 0 {
+		return nil, nil
+	}
+
+	familyName, kind, role := c.classify(name, s)
+	var bound float64
+	var boundLabel string
+	switch role {
+	case bucketRole:
+		boundLabel = model.BucketLabel
+	case quantileRole:
+		boundLabel = model.QuantileLabel
+	}
+	if boundLabel != "" {
+		var err error
+		if bound, err = strconv.ParseFloat(s.labels.Get(boundLabel), 64); err != nil {
+			return nil, fmt.Errorf("series %s has an invalid %s label: %w", s.labels, boundLabel, err)
+		}
+	}
+
+	family := scope.family(familyName, kind, s.metadata)
+	lbls := dataPointLabels(s.labels, boundLabel)
+	points := make([]*dataPoint, 0, len(s.samples))
+	for _, smpl := range s.samples {
+		p := family.dataPoint(lbls, smpl.timestamp, s.createdTimestamp)
+		if value.IsStaleNaN(smpl.value) {
+			p.stale = true
+		}
+		switch role {
+		case valueRole:
+			p.value = smpl.value
+		case bucketRole, quantileRole:
+			p.buckets = append(p.buckets, bucket{bound: bound, value: smpl.value})
+		case sumRole:
+			p.sum, p.hasSum = smpl.value, true
+		case countRole:
+			p.count, p.hasCount = smpl.value, true
+		}
+		points = append(points, p)
+	}
+	c.stats.Samples += len(s.samples)
+	return points, nil
+}
+
+func (c *metricsConverter) addNativeHistograms(scope *scopeGroup, name string, s *promSeries) ([]*dataPoint, error) {
+	if len(s.histograms) == 0 {
+		return nil, nil
+	}
+
+	for _, h := range s.histograms {
+		if err := h.histogram.Validate(); err != nil {
+			return nil, fmt.Errorf("series %s has an invalid native histogram: %w", s.labels, err)
+		}
+		if !h.histogram.UsesCustomBuckets() && (h.histogram.Schema < -4 || h.histogram.Schema > 8) {
+			return nil, fmt.Errorf("series %s has a native histogram with unsupported schema %d", s.labels, h.histogram.Schema)
+		}
+	}
+
+	lbls := dataPointLabels(s.labels, "")
+	points := make([]*dataPoint, 0, len(s.histograms))
+	for _, h := range s.histograms {
+		kind := exponentialHistogramFamily
+		if h.histogram.UsesCustomBuckets() {
+			kind = histogramFamily
+		}
+		p := scope.family(name, kind, s.metadata).dataPoint(lbls, h.timestamp, s.createdTimestamp)
+		if value.IsStaleNaN(h.histogram.Sum) {
+			p.stale = true
+		}
+		if kind == histogramFamily {
+			p.buckets = customBuckets(h.histogram)
+			p.sum, p.hasSum = h.histogram.Sum, true
+			p.count, p.hasCount = h.histogram.Count, true
+		} else {
+			p.histogram = h.histogram
+		}
+		points = append(points, p)
+	}
+	c.stats.Histograms += len(s.histograms)
+	return points, nil
+}
+
+// addExemplars attaches each exemplar to the last data point of the series that is
+// not more recent than the exemplar, or to the first data point if there is none.
+func (c *metricsConverter) addExemplars(points []*dataPoint, exemplars []exemplar.Exemplar) {
+	if len(points) == 0 {
+		return
+	}
+	for _, e := range exemplars {
+		target := points[len(points)-1]
+		if e.HasTs {
+			target = points[0]
+			for _, p := range points {
+				if p.timestamp <= e.Ts && p.timestamp >= target.timestamp {
+					target = p
+				}
+			}
+		}
+		target.exemplars = append(target.exemplars, e)
+		c.stats.Exemplars++
+	}
+}
+
+// classify returns the name and kind of the metric family of a series with samples,
+// and how its samples contribute to the family's data points.
+func (c *metricsConverter) classify(name string, s *promSeries) (string, familyKind, seriesRole) {
+	switch s.metadata.Type {
+	case model.MetricTypeCounter:
+		return name, counterFamily, valueRole
+	case model.MetricTypeHistogram, model.MetricTypeGaugeHistogram:
+		if base, ok := strings.CutSuffix(name, bucketStr); ok && s.labels.Has(model.BucketLabel) {
+			return base, histogramFamily, bucketRole
+		}
+		for _, suffix := range histogramSuffixes {
+			if base, ok := strings.CutSuffix(name, suffix.suffix); ok {
+				return base, histogramFamily, suffix.role
+			}
+		}
+		return name, gaugeFamily, valueRole
+	case model.MetricTypeSummary:
+		if s.labels.Has(model.QuantileLabel) {
+			return name, summaryFamily, quantileRole
+		}
+		if base, ok := strings.CutSuffix(name, sumStr); ok {
+			return base, summaryFamily, sumRole
+		}
+		if base, ok := strings.CutSuffix(name, countStr); ok {
+			return base, summaryFamily, countRole
+		}
+		return name, gaugeFamily, valueRole
+	case model.MetricTypeUnknown, "":
+		return c.guess(name, s)
+	default:
+		return name, gaugeFamily, valueRole
+	}
+}
+
+// guess returns the metric family of a series without metadata from its name and labels.
+func (c *metricsConverter) guess(name string, s *promSeries) (string, familyKind, seriesRole) {
+	if base, ok := strings.CutSuffix(name, bucketStr); ok && s.labels.Has(model.BucketLabel) {
+		return base, histogramFamily, bucketRole
+	}
+	if s.labels.Has(model.QuantileLabel) {
+		return name, summaryFamily, quantileRole
+	}
+	for _, suffix := range histogramSuffixes[:2] {
+		if base, ok := strings.CutSuffix(name, suffix.suffix); ok {
+			switch {
+			case c.histogramFamilies[base]:
+				return base, histogramFamily, suffix.role
+			case c.summaryFamilies[base]:
+				return base, summaryFamily, suffix.role
+			}
+		}
+	}
+	if strings.HasSuffix(name, "_total") {
+		return name, counterFamily, valueRole
+	}
+	return name, gaugeFamily, valueRole
+}
+
+func (c *metricsConverter) resource(key resourceKey) *resourceGroup {
+	if r, ok := c.resourceIndex[key]; ok {
+		return r
+	}
+	r := &resourceGroup{key: key, scopeIndex: map[scopeKey]*scopeGroup{}}
+	c.resources = append(c.resources, r)
+	c.resourceIndex[key] = r
+	return r
+}
+
+func (r *resourceGroup) scope(key scopeKey) *scopeGroup {
+	if s, ok := r.scopeIndex[key]; ok {
+		return s
+	}
+	s := &scopeGroup{key: key, familyIndex: map[familyKey]*metricFamily{}}
+	r.scopes = append(r.scopes, s)
+	r.scopeIndex[key] = s
+	return s
+}
+
+func (s *scopeGroup) family(name string, kind familyKind, md metadata.Metadata) *metricFamily {
+	key := familyKey{name: name, kind: kind}
+	if f, ok := s.familyIndex[key]; ok {
+		if f.metadata.Help == "" && f.metadata.Unit == "" {
+			f.metadata = md
+		}
+		return f
+	}
+	f := &metricFamily{name: name, kind: kind, metadata: md, pointIndex: map[dataPointKey]*dataPoint{}}
+	s.families = append(s.families, f)
+	s.familyIndex[key] = f
+	return f
+}
+
+func (f *metricFamily) dataPoint(lbls labels.Labels, timestamp, startTimestamp int64) *dataPoint {
+	key := dataPointKey{labels: lbls.String(), timestamp: timestamp}
+	p, ok := f.pointIndex[key]
+	if !ok {
+		p = &dataPoint{labels: lbls, timestamp: timestamp}
+		f.points = append(f.points, p)
+		f.pointIndex[key] = p
+	}
+	if startTimestamp != 0 {
+		p.startTimestamp = startTimestamp
+	}
+	return p
+}
+
+// dataPointLabels returns the labels of a series that become attributes of its data points.
+func dataPointLabels(lbls labels.Labels, boundLabel string) labels.Labels {
+	b := labels.NewBuilder(lbls)
+	b.Del(
+		model.MetricNameLabel,
+		model.JobLabel,
+		model.InstanceLabel,
+		prometheustranslator.ScopeNameLabelKey,
+		prometheustranslator.ScopeVersionLabelKey,
+	)
+	if boundLabel != "" {
+		b.Del(boundLabel)
+	}
+	return b.Labels()
+}
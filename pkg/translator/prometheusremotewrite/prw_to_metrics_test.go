@@ -0,0 +1,326 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	conventions "go.opentelemetry.io/collector/semconv/v1.25.0"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+)
+
+func TestToMetrics(t *testing.T) {
+	ts := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	msTs := ts.UnixMilli()
+
+	t.Run("gauge grouped by job and instance with target_info", func(t *testing.T) {
+		req := &prompb.WriteRequest{
+			Timeseries: []prompb.TimeSeries{
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "temperature", model.JobLabel, "sensors", model.InstanceLabel, "host:9100", "room", "a"), getSample(21.5, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "temperature", model.JobLabel, "sensors", model.InstanceLabel, "other:9100", "room", "b"), getSample(19, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, prometheustranslator.TargetInfoMetricName, model.JobLabel, "sensors", model.InstanceLabel, "host:9100", "host_name", "host"), getSample(1, msTs)),
+			},
+		}
+
+		md, stats, err := ToMetrics(req, ToMetricsSettings{})
+		require.NoError(t, err)
+		assert.Equal(t, WriteStats{Samples: 3}, stats)
+		require.Equal(t, 2, md.ResourceMetrics().Len())
+
+		rm := md.ResourceMetrics().At(0)
+		assert.Equal(t, map[string]any{
+			conventions.AttributeServiceName:       "sensors",
+			conventions.AttributeServiceInstanceID: "host:9100",
+			"host_name":                            "host",
+		}, rm.Resource().Attributes().AsRaw())
+		metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, "temperature", metric.Name())
+		require.Equal(t, pmetric.MetricTypeGauge, metric.Type())
+		dp := metric.Gauge().DataPoints().At(0)
+		assert.Equal(t, 21.5, dp.DoubleValue())
+		assert.Equal(t, pcommon.NewTimestampFromTime(ts), dp.Timestamp())
+		assert.Equal(t, map[string]any{"room": "a"}, dp.Attributes().AsRaw())
+
+		assert.Equal(t, map[string]any{
+			conventions.AttributeServiceName:       "sensors",
+			conventions.AttributeServiceInstanceID: "other:9100",
+		}, md.ResourceMetrics().At(1).Resource().Attributes().AsRaw())
+	})
+
+	t.Run("counter from metadata and name suffix", func(t *testing.T) {
+		req := &prompb.WriteRequest{
+			Timeseries: []prompb.TimeSeries{
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "requests_total", "code", "200"), getSample(10, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "processed_bytes"), getSample(1024, msTs)),
+			},
+			Metadata: []prompb.MetricMetadata{
+				{MetricFamilyName: "processed_bytes", Type: prompb.MetricMetadata_COUNTER, Help: "Processed bytes", Unit: "bytes"},
+			},
+		}
+
+		md, _, err := ToMetrics(req, ToMetricsSettings{TrimMetricSuffixes: true})
+		require.NoError(t, err)
+		metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		require.Equal(t, 2, metrics.Len())
+
+		requests := metrics.At(0)
+		assert.Equal(t, "requests", requests.Name())
+		require.Equal(t, pmetric.MetricTypeSum, requests.Type())
+		assert.True(t, requests.Sum().IsMonotonic())
+		assert.Equal(t, pmetric.AggregationTemporalityCumulative, requests.Sum().AggregationTemporality())
+
+		processed := metrics.At(1)
+		assert.Equal(t, "processed", processed.Name())
+		assert.Equal(t, "Processed bytes", processed.Description())
+		assert.Equal(t, "bytes", processed.Unit())
+		require.Equal(t, pmetric.MetricTypeSum, processed.Type())
+		assert.Equal(t, 1024.0, processed.Sum().DataPoints().At(0).DoubleValue())
+	})
+
+	t.Run("classic histogram with exemplar", func(t *testing.T) {
+		exemplar := getExemplar(0.3, msTs)
+		exemplar.Labels = append(exemplar.Labels, getLabel(prometheustranslator.ExemplarSpanIDKey, spanIDValue1), getLabel("user", "u1"))
+		req := &prompb.WriteRequest{
+			Timeseries: []prompb.TimeSeries{
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "latency_bucket", model.BucketLabel, "0.1"), getSample(2, msTs)),
+				*getTimeSeriesWithSamplesAndExemplars(getPromLabels(model.MetricNameLabel, "latency_bucket", model.BucketLabel, "0.5"), []prompb.Sample{getSample(5, msTs)}, []prompb.Exemplar{exemplar}),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "latency_bucket", model.BucketLabel, "+Inf"), getSample(6, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "latency_sum"), getSample(1.7, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "latency_count"), getSample(6, msTs)),
+			},
+		}
+
+		md, stats, err := ToMetrics(req, ToMetricsSettings{})
+		require.NoError(t, err)
+		assert.Equal(t, WriteStats{Samples: 5, Exemplars: 1}, stats)
+		metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		require.Equal(t, 1, metrics.Len())
+		assert.Equal(t, "latency", metrics.At(0).Name())
+		require.Equal(t, pmetric.MetricTypeHistogram, metrics.At(0).Type())
+
+		dp := metrics.At(0).Histogram().DataPoints().At(0)
+		assert.Equal(t, []float64{0.1, 0.5}, dp.ExplicitBounds().AsRaw())
+		assert.Equal(t, []uint64{2, 3, 1}, dp.BucketCounts().AsRaw())
+		assert.Equal(t, uint64(6), dp.Count())
+		assert.Equal(t, 1.7, dp.Sum())
+		assert.Equal(t, 0, dp.Attributes().Len())
+
+		require.Equal(t, 1, dp.Exemplars().Len())
+		ex := dp.Exemplars().At(0)
+		assert.Equal(t, 0.3, ex.DoubleValue())
+		assert.Equal(t, traceIDValue1, ex.TraceID().String())
+		assert.Equal(t, spanIDValue1, ex.SpanID().String())
+		assert.Equal(t, map[string]any{"user": "u1"}, ex.FilteredAttributes().AsRaw())
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		req := &prompb.WriteRequest{
+			Timeseries: []prompb.TimeSeries{
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "rpc_duration", model.QuantileLabel, "0.99"), getSample(0.9, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "rpc_duration", model.QuantileLabel, "0.5"), getSample(0.2, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "rpc_duration_sum"), getSample(12, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "rpc_duration_count"), getSample(40, msTs)),
+			},
+		}
+
+		md, _, err := ToMetrics(req, ToMetricsSettings{})
+		require.NoError(t, err)
+		metric := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, "rpc_duration", metric.Name())
+		require.Equal(t, pmetric.MetricTypeSummary, metric.Type())
+		dp := metric.Summary().DataPoints().At(0)
+		assert.Equal(t, uint64(40), dp.Count())
+		assert.Equal(t, 12.0, dp.Sum())
+		require.Equal(t, 2, dp.QuantileValues().Len())
+		assert.Equal(t, 0.5, dp.QuantileValues().At(0).Quantile())
+		assert.Equal(t, 0.2, dp.QuantileValues().At(0).Value())
+		assert.Equal(t, 0.99, dp.QuantileValues().At(1).Quantile())
+	})
+
+	t.Run("scope labels and stale marker", func(t *testing.T) {
+		req := &prompb.WriteRequest{
+			Timeseries: []prompb.TimeSeries{
+				*getTimeSeries(
+					getPromLabels(model.MetricNameLabel, "queue_size", prometheustranslator.ScopeNameLabelKey, "queue", prometheustranslator.ScopeVersionLabelKey, "1.0"),
+					getSample(math.Float64frombits(value.StaleNaN), msTs),
+				),
+			},
+		}
+
+		md, _, err := ToMetrics(req, ToMetricsSettings{})
+		require.NoError(t, err)
+		sm := md.ResourceMetrics().At(0).ScopeMetrics().At(0)
+		assert.Equal(t, "queue", sm.Scope().Name())
+		assert.Equal(t, "1.0", sm.Scope().Version())
+		dp := sm.Metrics().At(0).Gauge().DataPoints().At(0)
+		assert.True(t, dp.Flags().NoRecordedValue())
+		assert.Equal(t, 0, dp.Attributes().Len())
+	})
+
+	t.Run("native histogram", func(t *testing.T) {
+		want := pmetric.NewExponentialHistogramDataPoint()
+		want.SetScale(1)
+		want.SetCount(10)
+		want.SetSum(42)
+		want.SetZeroCount(1)
+		want.Positive().SetOffset(2)
+		want.Positive().BucketCounts().FromRaw([]uint64{3, 0, 0, 0, 0, 4})
+		want.Negative().SetOffset(-1)
+		want.Negative().BucketCounts().FromRaw([]uint64{2})
+		want.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		h, err := exponentialToNativeHistogram(want)
+		require.NoError(t, err)
+
+		req := &prompb.WriteRequest{
+			Timeseries: []prompb.TimeSeries{
+				{
+					Labels:     getPromLabels(model.MetricNameLabel, "request_size"),
+					Histograms: []prompb.Histogram{h},
+				},
+			},
+		}
+
+		md, stats, err := ToMetrics(req, ToMetricsSettings{})
+		require.NoError(t, err)
+		assert.Equal(t, WriteStats{Histograms: 1}, stats)
+		metric := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		require.Equal(t, pmetric.MetricTypeExponentialHistogram, metric.Type())
+		dp := metric.ExponentialHistogram().DataPoints().At(0)
+		assert.Equal(t, want.Scale(), dp.Scale())
+		assert.Equal(t, want.Count(), dp.Count())
+		assert.Equal(t, want.Sum(), dp.Sum())
+		assert.Equal(t, want.ZeroCount(), dp.ZeroCount())
+		assert.Equal(t, want.Timestamp(), dp.Timestamp())
+		assert.Equal(t, want.Positive().Offset(), dp.Positive().Offset())
+		assert.Equal(t, want.Positive().BucketCounts().AsRaw(), dp.Positive().BucketCounts().AsRaw())
+		assert.Equal(t, want.Negative().Offset(), dp.Negative().Offset())
+		assert.Equal(t, want.Negative().BucketCounts().AsRaw(), dp.Negative().BucketCounts().AsRaw())
+	})
+
+	t.Run("invalid series are skipped", func(t *testing.T) {
+		req := &prompb.WriteRequest{
+			Timeseries: []prompb.TimeSeries{
+				*getTimeSeries(getPromLabels("room", "a"), getSample(1, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "latency_bucket", model.BucketLabel, "fast"), getSample(1, msTs)),
+				*getTimeSeries(getPromLabels(model.MetricNameLabel, "temperature"), getSample(1, msTs)),
+			},
+		}
+
+		md, stats, err := ToMetrics(req, ToMetricsSettings{})
+		assert.ErrorContains(t, err, "has no metric name")
+		assert.ErrorContains(t, err, "invalid le label")
+		assert.Equal(t, WriteStats{Samples: 1}, stats)
+		assert.Equal(t, 1, md.DataPointCount())
+	})
+}
+
+func TestToMetricsV2(t *testing.T) {
+	ts := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	msTs := ts.UnixMilli()
+
+	t.Run("series with metadata and created timestamp", func(t *testing.T) {
+		st := writev2.NewSymbolTable()
+		req := &writev2.Request{
+			Timeseries: []writev2.TimeSeries{
+				{
+					LabelsRefs: []uint32{
+						st.Symbolize(model.MetricNameLabel), st.Symbolize("http_requests_total"),
+						st.Symbolize(model.JobLabel), st.Symbolize("api"),
+						st.Symbolize("method"), st.Symbolize("GET"),
+					},
+					Samples: []writev2.Sample{{Value: 7, Timestamp: msTs}},
+					Exemplars: []writev2.Exemplar{{
+						LabelsRefs: []uint32{st.Symbolize(prometheustranslator.ExemplarTraceIDKey), st.Symbolize(traceIDValue1)},
+						Value:      1,
+						Timestamp:  msTs,
+					}},
+					Metadata: writev2.Metadata{
+						Type:    writev2.Metadata_METRIC_TYPE_COUNTER,
+						HelpRef: st.Symbolize("Total HTTP requests"),
+					},
+					CreatedTimestamp: msTs - 60000,
+				},
+			},
+		}
+		req.Symbols = st.Symbols()
+
+		md, stats, err := ToMetricsV2(req, ToMetricsSettings{})
+		require.NoError(t, err)
+		assert.Equal(t, WriteStats{Samples: 1, Exemplars: 1}, stats)
+		rm := md.ResourceMetrics().At(0)
+		assert.Equal(t, map[string]any{conventions.AttributeServiceName: "api"}, rm.Resource().Attributes().AsRaw())
+		metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, "http_requests_total", metric.Name())
+		assert.Equal(t, "Total HTTP requests", metric.Description())
+		require.Equal(t, pmetric.MetricTypeSum, metric.Type())
+		dp := metric.Sum().DataPoints().At(0)
+		assert.Equal(t, 7.0, dp.DoubleValue())
+		assert.Equal(t, pcommon.NewTimestampFromTime(ts.Add(-time.Minute)), dp.StartTimestamp())
+		assert.Equal(t, map[string]any{"method": "GET"}, dp.Attributes().AsRaw())
+		assert.Equal(t, traceIDValue1, dp.Exemplars().At(0).TraceID().String())
+	})
+
+	t.Run("native histogram with custom buckets", func(t *testing.T) {
+		st := writev2.NewSymbolTable()
+		req := &writev2.Request{
+			Timeseries: []writev2.TimeSeries{
+				{
+					LabelsRefs: []uint32{st.Symbolize(model.MetricNameLabel), st.Symbolize("latency")},
+					Histograms: []writev2.Histogram{{
+						Count:          &writev2.Histogram_CountInt{CountInt: 6},
+						Sum:            3,
+						Schema:         -53,
+						PositiveSpans:  []writev2.BucketSpan{{Offset: 0, Length: 1}, {Offset: 1, Length: 1}},
+						PositiveDeltas: []int64{2, 2},
+						CustomValues:   []float64{0.1, 0.5, 1},
+						Timestamp:      msTs,
+					}},
+					Metadata: writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+				},
+			},
+		}
+		req.Symbols = st.Symbols()
+
+		md, stats, err := ToMetricsV2(req, ToMetricsSettings{})
+		require.NoError(t, err)
+		assert.Equal(t, WriteStats{Histograms: 1}, stats)
+		metric := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		require.Equal(t, pmetric.MetricTypeHistogram, metric.Type())
+		dp := metric.Histogram().DataPoints().At(0)
+		assert.Equal(t, []float64{0.1, 0.5, 1}, dp.ExplicitBounds().AsRaw())
+		assert.Equal(t, []uint64{2, 0, 4, 0}, dp.BucketCounts().AsRaw())
+		assert.Equal(t, uint64(6), dp.Count())
+		assert.Equal(t, 3.0, dp.Sum())
+	})
+
+	t.Run("series with invalid symbol references are skipped", func(t *testing.T) {
+		req := &writev2.Request{
+			Symbols: []string{"", model.MetricNameLabel, "up"},
+			Timeseries: []writev2.TimeSeries{
+				{LabelsRefs: []uint32{1, 5}, Samples: []writev2.Sample{{Value: 1, Timestamp: msTs}}},
+				{LabelsRefs: []uint32{1}, Samples: []writev2.Sample{{Value: 1, Timestamp: msTs}}},
+				{LabelsRefs: []uint32{1, 2}, Samples: []writev2.Sample{{Value: 1, Timestamp: msTs}}, Metadata: writev2.Metadata{HelpRef: 9}},
+				{LabelsRefs: []uint32{1, 2}, Samples: []writev2.Sample{{Value: 1, Timestamp: msTs}}},
+			},
+		}
+
+		md, stats, err := ToMetricsV2(req, ToMetricsSettings{})
+		assert.ErrorContains(t, err, "invalid series at index 0")
+		assert.ErrorContains(t, err, "invalid series at index 1")
+		assert.ErrorContains(t, err, "invalid series at index 2")
+		assert.Equal(t, WriteStats{Samples: 1}, stats)
+		assert.Equal(t, 1, md.DataPointCount())
+	})
+}
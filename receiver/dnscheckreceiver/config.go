@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnscheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnscheckreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnscheckreceiver/internal/metadata"
+)
+
+const (
+	protocolUDP = "udp"
+	protocolTCP = "tcp"
+)
+
+// Predefined error responses for configuration validation failures
+var (
+	errNoTargets         = errors.New("no targets configured")
+	errMissingServer     = errors.New(`"server" must be specified`)
+	errInvalidServer     = errors.New(`"server" must be in the form of <hostname>:<port>`)
+	errMissingName       = errors.New(`"name" must be specified`)
+	errInvalidRecordType = errors.New(`"record_type" is not a known DNS record type`)
+	errInvalidProtocol   = errors.New(`"protocol" must be either "udp" or "tcp"`)
+	errInvalidTimeout    = errors.New(`"timeout" must not be negative`)
+)
+
+// Config defines the configuration for the various elements of the receiver agent.
+type Config struct {
+	scraperhelper.ControllerConfig `mapstructure:",squash"`
+	metadata.MetricsBuilderConfig  `mapstructure:",squash"`
+	Targets                        []*targetConfig `mapstructure:"targets"`
+}
+
+type targetConfig struct {
+	// Server is the address of the resolver to query, in the form of <hostname>:<port>.
+	Server string `mapstructure:"server"`
+	// Name is the domain name to query.
+	Name string `mapstructure:"name"`
+	// RecordType is the record type to query, e.g. A, AAAA, CNAME, MX or TXT. Defaults to A.
+	RecordType string `mapstructure:"record_type"`
+	// Protocol is the transport used for the query, either udp or tcp. Defaults to udp.
+	Protocol string `mapstructure:"protocol"`
+	// Timeout bounds the query. Defaults to 5s when unset.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// ExpectedAnswers lists answers that must all be present in the response for the check to succeed.
+	ExpectedAnswers []string `mapstructure:"expected_answers"`
+}
+
+// Validate validates the configuration by checking for missing or invalid fields
+func (cfg *targetConfig) Validate() error {
+	var err error
+
+	if cfg.Server == "" {
+		err = multierr.Append(err, errMissingServer)
+	} else if _, _, splitErr := net.SplitHostPort(cfg.Server); splitErr != nil {
+		err = multierr.Append(err, fmt.Errorf("%s: %w", errInvalidServer.Error(), splitErr))
+	}
+
+	if cfg.Name == "" {
+		err = multierr.Append(err, errMissingName)
+	}
+
+	if cfg.RecordType != "" {
+		if _, ok := dns.StringToType[strings.ToUpper(cfg.RecordType)]; !ok {
+			err = multierr.Append(err, fmt.Errorf("%s: %q", errInvalidRecordType.Error(), cfg.RecordType))
+		}
+	}
+
+	switch strings.ToLower(cfg.Protocol) {
+	case "", protocolUDP, protocolTCP:
+	default:
+		err = multierr.Append(err, errInvalidProtocol)
+	}
+
+	if cfg.Timeout < 0 {
+		err = multierr.Append(err, errInvalidTimeout)
+	}
+
+	return err
+}
+
+// Validate validates the configuration by checking for missing or invalid fields
+func (cfg *Config) Validate() error {
+	var err error
+
+	if len(cfg.Targets) == 0 {
+		err = multierr.Append(err, errNoTargets)
+	}
+
+	for _, target := range cfg.Targets {
+		err = multierr.Append(err, target.Validate())
+	}
+
+	return err
+}
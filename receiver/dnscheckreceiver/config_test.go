@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnscheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnscheckreceiver"
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnscheckreceiver/internal/metadata"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		cfg         *Config
+		expectedErr error
+	}{
+		{
+			desc: "missing targets",
+			cfg: &Config{
+				Targets:          []*targetConfig{},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+			expectedErr: errNoTargets,
+		},
+		{
+			desc: "missing server and name",
+			cfg: &Config{
+				Targets:          []*targetConfig{{}},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+			expectedErr: multierr.Combine(errMissingServer, errMissingName),
+		},
+		{
+			desc: "invalid server",
+			cfg: &Config{
+				Targets:          []*targetConfig{{Server: "8.8.8.8", Name: "example.com"}},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+			expectedErr: errInvalidServer,
+		},
+		{
+			desc: "invalid record type, protocol and timeout",
+			cfg: &Config{
+				Targets: []*targetConfig{
+					{
+						Server:     "8.8.8.8:53",
+						Name:       "example.com",
+						RecordType: "BOGUS",
+						Protocol:   "quic",
+						Timeout:    -time.Second,
+					},
+				},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+			expectedErr: multierr.Combine(errInvalidRecordType, errInvalidProtocol, errInvalidTimeout),
+		},
+		{
+			desc: "valid config",
+			cfg: &Config{
+				Targets: []*targetConfig{
+					{
+						Server:          "8.8.8.8:53",
+						Name:            "example.com",
+						RecordType:      "aaaa",
+						Protocol:        "TCP",
+						ExpectedAnswers: []string{"::1"},
+					},
+				},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			actualErr := tc.cfg.Validate()
+			if tc.expectedErr == nil {
+				require.NoError(t, actualErr)
+				return
+			}
+			for _, expected := range multierr.Errors(tc.expectedErr) {
+				require.ErrorContains(t, actualErr, expected.Error())
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id       component.ID
+		expected component.Config
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "all"),
+			expected: &Config{
+				ControllerConfig: scraperhelper.ControllerConfig{
+					CollectionInterval: 30 * time.Second,
+					InitialDelay:       time.Second,
+				},
+				MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+				Targets: []*targetConfig{
+					{
+						Server: "1.1.1.1:53",
+						Name:   "example.com",
+					},
+					{
+						Server:          "10.0.0.53:53",
+						Name:            "example.com",
+						RecordType:      "MX",
+						Protocol:        "tcp",
+						Timeout:         2 * time.Second,
+						ExpectedAnswers: []string{"10 mail.example.com."},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			require.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// MetricConfig provides common config for a particular metric.
+type MetricConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	enabledSetByUser bool
+}
+
+func (ms *MetricConfig) Unmarshal(parser *confmap.Conf) error {
+	if parser == nil {
+		return nil
+	}
+	err := parser.Unmarshal(ms)
+	if err != nil {
+		return err
+	}
+	ms.enabledSetByUser = parser.IsSet("enabled")
+	return nil
+}
+
+// MetricsConfig provides config for dnscheck metrics.
+type MetricsConfig struct {
+	DnscheckAnswers  MetricConfig `mapstructure:"dnscheck.answers"`
+	DnscheckDuration MetricConfig `mapstructure:"dnscheck.duration"`
+	DnscheckError    MetricConfig `mapstructure:"dnscheck.error"`
+	DnscheckStatus   MetricConfig `mapstructure:"dnscheck.status"`
+}
+
+func DefaultMetricsConfig() MetricsConfig {
+	return MetricsConfig{
+		DnscheckAnswers: MetricConfig{
+			Enabled: false,
+		},
+		DnscheckDuration: MetricConfig{
+			Enabled: true,
+		},
+		DnscheckError: MetricConfig{
+			Enabled: true,
+		},
+		DnscheckStatus: MetricConfig{
+			Enabled: true,
+		},
+	}
+}
+
+// MetricsBuilderConfig is a configuration for dnscheck metrics builder.
+type MetricsBuilderConfig struct {
+	Metrics MetricsConfig `mapstructure:"metrics"`
+}
+
+func DefaultMetricsBuilderConfig() MetricsBuilderConfig {
+	return MetricsBuilderConfig{
+		Metrics: DefaultMetricsConfig(),
+	}
+}
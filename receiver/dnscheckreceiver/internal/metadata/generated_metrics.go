@@ -0,0 +1,375 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+type metricDnscheckAnswers struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills dnscheck.answers metric with initial data.
+func (m *metricDnscheckAnswers) init() {
+	m.data.SetName("dnscheck.answers")
+	m.data.SetDescription("Number of records of the queried type in the answer section of the response.")
+	m.data.SetUnit("{record}")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricDnscheckAnswers) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, dnsServerAttributeValue string, dnsQuestionNameAttributeValue string, dnsQuestionTypeAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("dns.server", dnsServerAttributeValue)
+	dp.Attributes().PutStr("dns.question.name", dnsQuestionNameAttributeValue)
+	dp.Attributes().PutStr("dns.question.type", dnsQuestionTypeAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricDnscheckAnswers) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricDnscheckAnswers) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricDnscheckAnswers(cfg MetricConfig) metricDnscheckAnswers {
+	m := metricDnscheckAnswers{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricDnscheckDuration struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills dnscheck.duration metric with initial data.
+func (m *metricDnscheckDuration) init() {
+	m.data.SetName("dnscheck.duration")
+	m.data.SetDescription("Measures the duration of the DNS query.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricDnscheckDuration) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, dnsServerAttributeValue string, dnsQuestionNameAttributeValue string, dnsQuestionTypeAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("dns.server", dnsServerAttributeValue)
+	dp.Attributes().PutStr("dns.question.name", dnsQuestionNameAttributeValue)
+	dp.Attributes().PutStr("dns.question.type", dnsQuestionTypeAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricDnscheckDuration) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricDnscheckDuration) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricDnscheckDuration(cfg MetricConfig) metricDnscheckDuration {
+	m := metricDnscheckDuration{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricDnscheckError struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills dnscheck.error metric with initial data.
+func (m *metricDnscheckError) init() {
+	m.data.SetName("dnscheck.error")
+	m.data.SetDescription("Records errors occurring during DNS check.")
+	m.data.SetUnit("{error}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricDnscheckError) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, dnsServerAttributeValue string, dnsQuestionNameAttributeValue string, dnsQuestionTypeAttributeValue string, errorMessageAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("dns.server", dnsServerAttributeValue)
+	dp.Attributes().PutStr("dns.question.name", dnsQuestionNameAttributeValue)
+	dp.Attributes().PutStr("dns.question.type", dnsQuestionTypeAttributeValue)
+	dp.Attributes().PutStr("error.message", errorMessageAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricDnscheckError) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricDnscheckError) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricDnscheckError(cfg MetricConfig) metricDnscheckError {
+	m := metricDnscheckError{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricDnscheckStatus struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills dnscheck.status metric with initial data.
+func (m *metricDnscheckStatus) init() {
+	m.data.SetName("dnscheck.status")
+	m.data.SetDescription("1 if the server answered with NOERROR and all expected answers were present, otherwise 0.")
+	m.data.SetUnit("1")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricDnscheckStatus) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, dnsServerAttributeValue string, dnsQuestionNameAttributeValue string, dnsQuestionTypeAttributeValue string, dnsResponseCodeAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("dns.server", dnsServerAttributeValue)
+	dp.Attributes().PutStr("dns.question.name", dnsQuestionNameAttributeValue)
+	dp.Attributes().PutStr("dns.question.type", dnsQuestionTypeAttributeValue)
+	dp.Attributes().PutStr("dns.response_code", dnsResponseCodeAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricDnscheckStatus) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricDnscheckStatus) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricDnscheckStatus(cfg MetricConfig) metricDnscheckStatus {
+	m := metricDnscheckStatus{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+// MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
+// required to produce metric representation defined in metadata and user config.
+type MetricsBuilder struct {
+	config                 MetricsBuilderConfig // config of the metrics builder.
+	startTime              pcommon.Timestamp    // start time that will be applied to all recorded data points.
+	metricsCapacity        int                  // maximum observed number of metrics per resource.
+	metricsBuffer          pmetric.Metrics      // accumulates metrics data before emitting.
+	buildInfo              component.BuildInfo  // contains version information.
+	metricDnscheckAnswers  metricDnscheckAnswers
+	metricDnscheckDuration metricDnscheckDuration
+	metricDnscheckError    metricDnscheckError
+	metricDnscheckStatus   metricDnscheckStatus
+}
+
+// metricBuilderOption applies changes to default metrics builder.
+type metricBuilderOption func(*MetricsBuilder)
+
+// WithStartTime sets startTime on the metrics builder.
+func WithStartTime(startTime pcommon.Timestamp) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.startTime = startTime
+	}
+}
+
+func NewMetricsBuilder(mbc MetricsBuilderConfig, settings receiver.Settings, options ...metricBuilderOption) *MetricsBuilder {
+	mb := &MetricsBuilder{
+		config:                 mbc,
+		startTime:              pcommon.NewTimestampFromTime(time.Now()),
+		metricsBuffer:          pmetric.NewMetrics(),
+		buildInfo:              settings.BuildInfo,
+		metricDnscheckAnswers:  newMetricDnscheckAnswers(mbc.Metrics.DnscheckAnswers),
+		metricDnscheckDuration: newMetricDnscheckDuration(mbc.Metrics.DnscheckDuration),
+		metricDnscheckError:    newMetricDnscheckError(mbc.Metrics.DnscheckError),
+		metricDnscheckStatus:   newMetricDnscheckStatus(mbc.Metrics.DnscheckStatus),
+	}
+
+	for _, op := range options {
+		op(mb)
+	}
+	return mb
+}
+
+// updateCapacity updates max length of metrics and resource attributes that will be used for the slice capacity.
+func (mb *MetricsBuilder) updateCapacity(rm pmetric.ResourceMetrics) {
+	if mb.metricsCapacity < rm.ScopeMetrics().At(0).Metrics().Len() {
+		mb.metricsCapacity = rm.ScopeMetrics().At(0).Metrics().Len()
+	}
+}
+
+// ResourceMetricsOption applies changes to provided resource metrics.
+type ResourceMetricsOption func(pmetric.ResourceMetrics)
+
+// WithResource sets the provided resource on the emitted ResourceMetrics.
+// It's recommended to use ResourceBuilder to create the resource.
+func WithResource(res pcommon.Resource) ResourceMetricsOption {
+	return func(rm pmetric.ResourceMetrics) {
+		res.CopyTo(rm.Resource())
+	}
+}
+
+// WithStartTimeOverride overrides start time for all the resource metrics data points.
+// This option should be only used if different start time has to be set on metrics coming from different resources.
+func WithStartTimeOverride(start pcommon.Timestamp) ResourceMetricsOption {
+	return func(rm pmetric.ResourceMetrics) {
+		var dps pmetric.NumberDataPointSlice
+		metrics := rm.ScopeMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			switch metrics.At(i).Type() {
+			case pmetric.MetricTypeGauge:
+				dps = metrics.At(i).Gauge().DataPoints()
+			case pmetric.MetricTypeSum:
+				dps = metrics.At(i).Sum().DataPoints()
+			}
+			for j := 0; j < dps.Len(); j++ {
+				dps.At(j).SetStartTimestamp(start)
+			}
+		}
+	}
+}
+
+// EmitForResource saves all the generated metrics under a new resource and updates the internal state to be ready for
+// recording another set of data points as part of another resource. This function can be helpful when one scraper
+// needs to emit metrics from several resources. Otherwise calling this function is not required,
+// just `Emit` function can be called instead.
+// Resource attributes should be provided as ResourceMetricsOption arguments.
+func (mb *MetricsBuilder) EmitForResource(rmo ...ResourceMetricsOption) {
+	rm := pmetric.NewResourceMetrics()
+	ils := rm.ScopeMetrics().AppendEmpty()
+	ils.Scope().SetName("github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnscheckreceiver")
+	ils.Scope().SetVersion(mb.buildInfo.Version)
+	ils.Metrics().EnsureCapacity(mb.metricsCapacity)
+	mb.metricDnscheckAnswers.emit(ils.Metrics())
+	mb.metricDnscheckDuration.emit(ils.Metrics())
+	mb.metricDnscheckError.emit(ils.Metrics())
+	mb.metricDnscheckStatus.emit(ils.Metrics())
+
+	for _, op := range rmo {
+		op(rm)
+	}
+
+	if ils.Metrics().Len() > 0 {
+		mb.updateCapacity(rm)
+		rm.MoveTo(mb.metricsBuffer.ResourceMetrics().AppendEmpty())
+	}
+}
+
+// Emit returns all the metrics accumulated by the metrics builder and updates the internal state to be ready for
+// recording another set of metrics. This function will be responsible for applying all the transformations required to
+// produce metric representation defined in metadata and user config, e.g. delta or cumulative.
+func (mb *MetricsBuilder) Emit(rmo ...ResourceMetricsOption) pmetric.Metrics {
+	mb.EmitForResource(rmo...)
+	metrics := mb.metricsBuffer
+	mb.metricsBuffer = pmetric.NewMetrics()
+	return metrics
+}
+
+// RecordDnscheckAnswersDataPoint adds a data point to dnscheck.answers metric.
+func (mb *MetricsBuilder) RecordDnscheckAnswersDataPoint(ts pcommon.Timestamp, val int64, dnsServerAttributeValue string, dnsQuestionNameAttributeValue string, dnsQuestionTypeAttributeValue string) {
+	mb.metricDnscheckAnswers.recordDataPoint(mb.startTime, ts, val, dnsServerAttributeValue, dnsQuestionNameAttributeValue, dnsQuestionTypeAttributeValue)
+}
+
+// RecordDnscheckDurationDataPoint adds a data point to dnscheck.duration metric.
+func (mb *MetricsBuilder) RecordDnscheckDurationDataPoint(ts pcommon.Timestamp, val int64, dnsServerAttributeValue string, dnsQuestionNameAttributeValue string, dnsQuestionTypeAttributeValue string) {
+	mb.metricDnscheckDuration.recordDataPoint(mb.startTime, ts, val, dnsServerAttributeValue, dnsQuestionNameAttributeValue, dnsQuestionTypeAttributeValue)
+}
+
+// RecordDnscheckErrorDataPoint adds a data point to dnscheck.error metric.
+func (mb *MetricsBuilder) RecordDnscheckErrorDataPoint(ts pcommon.Timestamp, val int64, dnsServerAttributeValue string, dnsQuestionNameAttributeValue string, dnsQuestionTypeAttributeValue string, errorMessageAttributeValue string) {
+	mb.metricDnscheckError.recordDataPoint(mb.startTime, ts, val, dnsServerAttributeValue, dnsQuestionNameAttributeValue, dnsQuestionTypeAttributeValue, errorMessageAttributeValue)
+}
+
+// RecordDnscheckStatusDataPoint adds a data point to dnscheck.status metric.
+func (mb *MetricsBuilder) RecordDnscheckStatusDataPoint(ts pcommon.Timestamp, val int64, dnsServerAttributeValue string, dnsQuestionNameAttributeValue string, dnsQuestionTypeAttributeValue string, dnsResponseCodeAttributeValue string) {
+	mb.metricDnscheckStatus.recordDataPoint(mb.startTime, ts, val, dnsServerAttributeValue, dnsQuestionNameAttributeValue, dnsQuestionTypeAttributeValue, dnsResponseCodeAttributeValue)
+}
+
+// Reset resets metrics builder to its initial state. It should be used when external metrics source is restarted,
+// and metrics builder should update its startTime and reset it's internal state accordingly.
+func (mb *MetricsBuilder) Reset(options ...metricBuilderOption) {
+	mb.startTime = pcommon.NewTimestampFromTime(time.Now())
+	for _, op := range options {
+		op(mb)
+	}
+}
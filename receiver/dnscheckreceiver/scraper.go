@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnscheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnscheckreceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnscheckreceiver/internal/metadata"
+)
+
+var errTargetsNotInit = errors.New("targets not initialized")
+
+// target holds the resolved settings of a single configured target.
+type target struct {
+	cfg        *targetConfig
+	client     *dns.Client
+	name       string
+	recordType uint16
+	expected   []string
+}
+
+type dnscheckScraper struct {
+	targets  []*target
+	cfg      *Config
+	settings component.TelemetrySettings
+	mb       *metadata.MetricsBuilder
+	mux      sync.Mutex
+}
+
+// start creates a DNS client for each target
+func (s *dnscheckScraper) start(_ context.Context, _ component.Host) error {
+	for _, cfg := range s.cfg.Targets {
+		recordType := dns.TypeA
+		if cfg.RecordType != "" {
+			recordType = dns.StringToType[strings.ToUpper(cfg.RecordType)]
+		}
+		protocol := strings.ToLower(cfg.Protocol)
+		if protocol == "" {
+			protocol = protocolUDP
+		}
+		timeout := cfg.Timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+
+		expected := make([]string, 0, len(cfg.ExpectedAnswers))
+		for _, answer := range cfg.ExpectedAnswers {
+			expected = append(expected, normalizeAnswer(answer))
+		}
+
+		s.targets = append(s.targets, &target{
+			cfg:        cfg,
+			client:     &dns.Client{Net: protocol, Timeout: timeout},
+			name:       dns.Fqdn(cfg.Name),
+			recordType: recordType,
+			expected:   expected,
+		})
+	}
+	return nil
+}
+
+// scrape queries every target concurrently and produces metrics based on the responses
+func (s *dnscheckScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+	if len(s.targets) == 0 {
+		return pmetric.NewMetrics(), errTargetsNotInit
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(s.targets))
+	for _, t := range s.targets {
+		go func(t *target) {
+			defer wg.Done()
+			s.check(ctx, t)
+		}(t)
+	}
+	wg.Wait()
+
+	return s.mb.Emit(), nil
+}
+
+func (s *dnscheckScraper) check(ctx context.Context, t *target) {
+	server := t.cfg.Server
+	name := t.cfg.Name
+	recordType := dns.TypeToString[t.recordType]
+	now := pcommon.NewTimestampFromTime(time.Now())
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(t.name, t.recordType)
+	msg.RecursionDesired = true
+
+	start := time.Now()
+	resp, _, err := t.client.ExchangeContext(ctx, msg, server)
+	duration := time.Since(start)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.mb.RecordDnscheckDurationDataPoint(now, duration.Milliseconds(), server, name, recordType)
+	if err != nil {
+		s.mb.RecordDnscheckStatusDataPoint(now, int64(0), server, name, recordType, "")
+		s.mb.RecordDnscheckErrorDataPoint(now, int64(1), server, name, recordType, err.Error())
+		return
+	}
+
+	rcode := dns.RcodeToString[resp.Rcode]
+	answers := answersOfType(resp, t.recordType)
+	s.mb.RecordDnscheckAnswersDataPoint(now, int64(len(answers)), server, name, recordType)
+
+	err = verifyResponse(resp, answers, t.expected)
+	if err != nil {
+		s.mb.RecordDnscheckStatusDataPoint(now, int64(0), server, name, recordType, rcode)
+		s.mb.RecordDnscheckErrorDataPoint(now, int64(1), server, name, recordType, err.Error())
+		return
+	}
+	s.mb.RecordDnscheckStatusDataPoint(now, int64(1), server, name, recordType, rcode)
+}
+
+// verifyResponse checks that the response was successful and contains every expected answer.
+func verifyResponse(resp *dns.Msg, answers, expected []string) error {
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("server responded with %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	present := make(map[string]struct{}, len(answers))
+	for _, answer := range answers {
+		present[answer] = struct{}{}
+	}
+	var missing []string
+	for _, answer := range expected {
+		if _, ok := present[answer]; !ok {
+			missing = append(missing, answer)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("expected answers not found in response: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// answersOfType returns the normalized data of all answer records of the given type.
+// Records of other types, such as the CNAMEs leading to the queried records, are skipped.
+func answersOfType(resp *dns.Msg, recordType uint16) []string {
+	var answers []string
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype != recordType {
+			continue
+		}
+		answers = append(answers, normalizeAnswer(recordData(rr)))
+	}
+	return answers
+}
+
+// recordData renders the data part of a resource record, without its header.
+func recordData(rr dns.RR) string {
+	switch r := rr.(type) {
+	case *dns.A:
+		return r.A.String()
+	case *dns.AAAA:
+		return r.AAAA.String()
+	case *dns.CNAME:
+		return r.Target
+	case *dns.NS:
+		return r.Ns
+	case *dns.PTR:
+		return r.Ptr
+	case *dns.MX:
+		return strconv.Itoa(int(r.Preference)) + " " + r.Mx
+	case *dns.TXT:
+		return strings.Join(r.Txt, "")
+	case *dns.SRV:
+		return fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, r.Target)
+	default:
+		return strings.TrimPrefix(rr.String(), rr.Header().String())
+	}
+}
+
+// normalizeAnswer makes answers comparable regardless of letter case and trailing dots of domain names.
+func normalizeAnswer(answer string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(answer)), ".")
+}
+
+func newScraper(conf *Config, settings receiver.Settings) *dnscheckScraper {
+	return &dnscheckScraper{
+		cfg:      conf,
+		settings: settings.TelemetrySettings,
+		mb:       metadata.NewMetricsBuilder(conf.MetricsBuilderConfig, settings),
+	}
+}
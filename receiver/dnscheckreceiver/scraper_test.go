@@ -0,0 +1,239 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnscheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnscheckreceiver"
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+// testZone is served by the in-process DNS server used in tests.
+var testZone = map[uint16]map[string][]string{
+	dns.TypeA: {
+		"example.com.":     {"example.com. 300 IN A 192.0.2.1", "example.com. 300 IN A 192.0.2.2"},
+		"www.example.com.": {"www.example.com. 300 IN CNAME example.com.", "example.com. 300 IN A 192.0.2.1"},
+	},
+	dns.TypeMX: {
+		"example.com.": {"example.com. 300 IN MX 10 mail.example.com."},
+	},
+	dns.TypeTXT: {
+		"example.com.": {`example.com. 300 IN TXT "v=spf1 -all"`},
+	},
+}
+
+func newDNSServer(t *testing.T, network string) string {
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		q := req.Question[0]
+		records, ok := testZone[q.Qtype][q.Name]
+		if !ok {
+			resp.SetRcode(req, dns.RcodeNameError)
+		}
+		for _, record := range records {
+			rr, err := dns.NewRR(record)
+			if err != nil {
+				t.Errorf("invalid test record %q: %v", record, err)
+				return
+			}
+			resp.Answer = append(resp.Answer, rr)
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	started := make(chan struct{})
+	server := &dns.Server{Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	switch network {
+	case "tcp":
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		server.Listener = ln
+	default:
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		server.PacketConn = pc
+	}
+
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	if server.Listener != nil {
+		return server.Listener.Addr().String()
+	}
+	return server.PacketConn.LocalAddr().String()
+}
+
+type dataPoint struct {
+	value int64
+	attrs map[string]any
+}
+
+func dataPoints(t *testing.T, metrics pmetric.Metrics, name string) []dataPoint {
+	var points []dataPoint
+	rms := metrics.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			ms := sms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := ms.At(k)
+				if m.Name() != name {
+					continue
+				}
+				var dps pmetric.NumberDataPointSlice
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					dps = m.Gauge().DataPoints()
+				case pmetric.MetricTypeSum:
+					dps = m.Sum().DataPoints()
+				default:
+					t.Fatalf("unexpected metric type %s for %s", m.Type(), name)
+				}
+				for l := 0; l < dps.Len(); l++ {
+					points = append(points, dataPoint{value: dps.At(l).IntValue(), attrs: dps.At(l).Attributes().AsRaw()})
+				}
+			}
+		}
+	}
+	return points
+}
+
+func TestScraperNotStarted(t *testing.T) {
+	s := newScraper(createDefaultConfig().(*Config), receivertest.NewNopSettings())
+	_, err := s.scrape(context.Background())
+	require.ErrorIs(t, err, errTargetsNotInit)
+}
+
+func TestScrape(t *testing.T) {
+	udpServer := newDNSServer(t, "udp")
+	tcpServer := newDNSServer(t, "tcp")
+
+	testCases := []struct {
+		desc           string
+		target         *targetConfig
+		expectedStatus int64
+		expectedRcode  string
+		expectedError  string
+		expectedCount  int64
+	}{
+		{
+			desc:           "A record over udp",
+			target:         &targetConfig{Server: udpServer, Name: "example.com", ExpectedAnswers: []string{"192.0.2.2"}},
+			expectedStatus: 1,
+			expectedRcode:  "NOERROR",
+			expectedCount:  2,
+		},
+		{
+			desc:           "CNAME chain only counts records of the queried type",
+			target:         &targetConfig{Server: udpServer, Name: "www.example.com", RecordType: "A", ExpectedAnswers: []string{"192.0.2.1"}},
+			expectedStatus: 1,
+			expectedRcode:  "NOERROR",
+			expectedCount:  1,
+		},
+		{
+			desc:           "MX record over tcp",
+			target:         &targetConfig{Server: tcpServer, Name: "example.com", RecordType: "mx", Protocol: "tcp", ExpectedAnswers: []string{"10 MAIL.example.com"}},
+			expectedStatus: 1,
+			expectedRcode:  "NOERROR",
+			expectedCount:  1,
+		},
+		{
+			desc:           "TXT record",
+			target:         &targetConfig{Server: udpServer, Name: "example.com", RecordType: "TXT", ExpectedAnswers: []string{"v=spf1 -all"}},
+			expectedStatus: 1,
+			expectedRcode:  "NOERROR",
+			expectedCount:  1,
+		},
+		{
+			desc:           "unexpected answer",
+			target:         &targetConfig{Server: udpServer, Name: "example.com", ExpectedAnswers: []string{"192.0.2.3"}},
+			expectedStatus: 0,
+			expectedRcode:  "NOERROR",
+			expectedError:  "expected answers not found in response: 192.0.2.3",
+			expectedCount:  2,
+		},
+		{
+			desc:           "NXDOMAIN",
+			target:         &targetConfig{Server: udpServer, Name: "missing.example.com"},
+			expectedStatus: 0,
+			expectedRcode:  "NXDOMAIN",
+			expectedError:  "server responded with NXDOMAIN",
+			expectedCount:  0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.MetricsBuilderConfig.Metrics.DnscheckAnswers.Enabled = true
+			cfg.Targets = []*targetConfig{tc.target}
+
+			s := newScraper(cfg, receivertest.NewNopSettings())
+			require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+			metrics, err := s.scrape(context.Background())
+			require.NoError(t, err)
+
+			status := dataPoints(t, metrics, "dnscheck.status")
+			require.Len(t, status, 1)
+			assert.Equal(t, tc.expectedStatus, status[0].value)
+			assert.Equal(t, tc.target.Name, status[0].attrs["dns.question.name"])
+			assert.Equal(t, tc.target.Server, status[0].attrs["dns.server"])
+			assert.Equal(t, tc.expectedRcode, status[0].attrs["dns.response_code"])
+
+			require.Len(t, dataPoints(t, metrics, "dnscheck.duration"), 1)
+
+			answers := dataPoints(t, metrics, "dnscheck.answers")
+			require.Len(t, answers, 1)
+			assert.Equal(t, tc.expectedCount, answers[0].value)
+
+			errs := dataPoints(t, metrics, "dnscheck.error")
+			if tc.expectedError == "" {
+				assert.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.Equal(t, tc.expectedError, errs[0].attrs["error.message"])
+		})
+	}
+}
+
+func TestScrapeUnreachableServer(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Targets = []*targetConfig{
+		{
+			Server:  pc.LocalAddr().String(),
+			Name:    "example.com",
+			Timeout: 100 * time.Millisecond,
+		},
+	}
+
+	s := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	status := dataPoints(t, metrics, "dnscheck.status")
+	require.Len(t, status, 1)
+	assert.Equal(t, int64(0), status[0].value)
+	assert.Equal(t, "", status[0].attrs["dns.response_code"])
+	assert.Equal(t, "A", status[0].attrs["dns.question.type"])
+	assert.Len(t, dataPoints(t, metrics, "dnscheck.error"), 1)
+	assert.Empty(t, dataPoints(t, metrics, "dnscheck.answers"))
+}
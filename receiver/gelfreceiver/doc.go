@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package gelfreceiver receives Graylog Extended Log Format (GELF) messages over UDP or TCP.
+package gelfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gelfreceiver"
@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gelfreceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/consumerretry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/gelf"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/udp"
+)
+
+func testMessage(i int) string {
+	return fmt.Sprintf(`{"version":"1.1","host":"web-1","short_message":"test msg %d","timestamp":%d.5,"level":4,"_request_id":"req-%d"}`, i, 1614470402+i, i)
+}
+
+// chunks splits a payload in GELF chunks, in reverse order.
+func chunks(id string, payload []byte, count int) [][]byte {
+	size := (len(payload) + count - 1) / count
+	var result [][]byte
+	for seq := count - 1; seq >= 0; seq-- {
+		chunk := append([]byte{0x1e, 0x0f}, id...)
+		chunk = append(chunk, byte(seq), byte(count))
+		result = append(result, append(chunk, payload[seq*size:min((seq+1)*size, len(payload))]...))
+	}
+	return result
+}
+
+func TestGELFWithUDP(t *testing.T) {
+	cfg := testdataConfigYaml()
+	testGELF(t, cfg, func(t *testing.T, i int) [][]byte {
+		payload := []byte(testMessage(i))
+		if i%2 == 0 {
+			return [][]byte{payload}
+		}
+		// Every other message is compressed and chunked.
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write(payload)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return chunks(fmt.Sprintf("message%d", i), buf.Bytes(), 3)
+	})
+}
+
+func TestGELFWithTCP(t *testing.T) {
+	cfg := testdataConfigYaml()
+	cfg.InputConfig.UDP = nil
+	cfg.InputConfig.TCP = &tcp.NewConfig().BaseConfig
+	cfg.InputConfig.TCP.ListenAddress = "127.0.0.1:29019"
+	testGELF(t, cfg, func(_ *testing.T, i int) [][]byte {
+		return [][]byte{append([]byte(testMessage(i)), 0)}
+	})
+}
+
+func testGELF(t *testing.T, cfg *GELFConfig, packets func(t *testing.T, i int) [][]byte) {
+	numLogs := 5
+
+	f := NewFactory()
+	sink := new(consumertest.LogsSink)
+	rcvr, err := f.CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+
+	var conn net.Conn
+	if cfg.InputConfig.TCP != nil {
+		conn, err = net.Dial("tcp", "127.0.0.1:29019")
+		require.NoError(t, err)
+	} else {
+		conn, err = net.Dial("udp", "127.0.0.1:29019")
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < numLogs; i++ {
+		for _, packet := range packets(t, i) {
+			_, err = conn.Write(packet)
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, expectNLogs(sink, numLogs), 2*time.Second, time.Millisecond)
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+
+	var logs []plog.LogRecord
+	for _, ld := range sink.AllLogs() {
+		records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for j := 0; j < records.Len(); j++ {
+			logs = append(logs, records.At(j))
+		}
+	}
+	require.Len(t, logs, numLogs)
+
+	for i, log := range logs {
+		assert.Equal(t, fmt.Sprintf("test msg %d", i), log.Body().Str())
+		assert.Equal(t, pcommon.Timestamp((1614470402+int64(i))*1e9+5e8), log.Timestamp())
+		assert.Equal(t, plog.SeverityNumberWarn, log.SeverityNumber())
+		assert.Equal(t, map[string]any{"host": "web-1", "request_id": fmt.Sprintf("req-%d", i)}, log.Attributes().AsRaw())
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub("gelf")
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	assert.NoError(t, component.ValidateConfig(cfg))
+	assert.Equal(t, testdataConfigYaml(), cfg)
+}
+
+func testdataConfigYaml() *GELFConfig {
+	return &GELFConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators:      []operator.Config{},
+			RetryOnFailure: consumerretry.NewDefaultConfig(),
+		},
+		InputConfig: func() gelf.Config {
+			c := gelf.NewConfig()
+			c.UDP = &udp.NewConfig().BaseConfig
+			c.UDP.ListenAddress = "127.0.0.1:29019"
+			c.ChunkTimeout = 10 * time.Second
+			return *c
+		}(),
+	}
+}
+
+func TestDecodeInputConfigFailure(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	factory := NewFactory()
+	badCfg := &GELFConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: func() gelf.Config {
+			c := gelf.NewConfig()
+			c.UDP = &udp.NewConfig().BaseConfig
+			c.ChunkTimeout = 0
+			return *c
+		}(),
+	}
+	receiver, err := factory.CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), badCfg, sink)
+	require.Error(t, err, "receiver creation should fail if input config isn't valid")
+	require.Nil(t, receiver, "receiver creation should fail if input config isn't valid")
+}
+
+func expectNLogs(sink *consumertest.LogsSink, expected int) func() bool {
+	return func() bool {
+		return sink.LogRecordCount() == expected
+	}
+}
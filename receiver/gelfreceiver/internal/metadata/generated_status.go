@@ -0,0 +1,16 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/component"
+)
+
+var (
+	Type      = component.MustNewType("gelf")
+	ScopeName = "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gelfreceiver"
+)
+
+const (
+	LogsStability = component.StabilityLevelDevelopment
+)
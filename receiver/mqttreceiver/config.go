@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+// Config defines configuration for the MQTT receiver.
+type Config struct {
+	mqtt.ClientConfig `mapstructure:",squash"`
+
+	// Topics are the topic filters the receiver subscribes to.
+	Topics []TopicConfig `mapstructure:"topics"`
+
+	// FormatType is the OTLP encoding of the payloads of the messages, json or proto.
+	FormatType string `mapstructure:"format"`
+
+	// Encoding is the ID of the encoding extension decoding the payloads of the messages.
+	// If specified, it overrides `FormatType`.
+	Encoding *component.ID `mapstructure:"encoding"`
+}
+
+// TopicConfig configures the subscription to a topic filter.
+type TopicConfig struct {
+	// Filter is the topic filter, it may contain the `+` and `#` wildcards.
+	Filter string `mapstructure:"filter"`
+
+	// QoS is the maximum quality of service of the messages delivered to the receiver, 0, 1 or 2.
+	QoS byte `mapstructure:"qos"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	var errs error
+	if len(cfg.Topics) == 0 {
+		errs = errors.Join(errs, errors.New("at least one topic must be specified"))
+	}
+	seen := map[string]bool{}
+	for _, topic := range cfg.Topics {
+		if seen[topic.Filter] {
+			errs = errors.Join(errs, fmt.Errorf("topic %q is duplicated", topic.Filter))
+			continue
+		}
+		seen[topic.Filter] = true
+		if err := validateFilter(topic.Filter); err != nil {
+			errs = errors.Join(errs, err)
+		}
+		if topic.QoS > 2 {
+			errs = errors.Join(errs, fmt.Errorf("qos of topic %q must be 0, 1 or 2", topic.Filter))
+		}
+	}
+	if cfg.FormatType != messaging.FormatTypeJSON && cfg.FormatType != messaging.FormatTypeProto {
+		errs = errors.Join(errs, fmt.Errorf("format %q is not supported", cfg.FormatType))
+	}
+	return errs
+}
+
+// validateFilter checks the wildcards of a topic filter occupy entire levels, and the
+// multi-level wildcard is the last level.
+func validateFilter(filter string) error {
+	if filter == "" {
+		return errors.New("topic filter can't be empty")
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.ContainsAny(level, "+#") && len(level) > 1 {
+			return fmt.Errorf("topic filter %q has a wildcard not occupying an entire level", filter)
+		}
+		if level == "#" && i != len(levels)-1 {
+			return fmt.Errorf("topic filter %q has a multi-level wildcard before its last level", filter)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	encoding := component.MustNewID("text_encoding")
+	tests := []struct {
+		id          component.ID
+		expected    component.Config
+		expectedErr string
+	}{
+		{
+			id: component.NewID(metadata.Type),
+			expected: &Config{
+				ClientConfig: mqtt.NewDefaultClientConfig(),
+				Topics:       []TopicConfig{{Filter: "telemetry/#"}},
+				FormatType:   messaging.FormatTypeJSON,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expected: &Config{
+				ClientConfig: mqtt.ClientConfig{
+					Brokers:  []string{"ssl://broker-1:8883", "ssl://broker-2:8883"},
+					ClientID: "collector-1",
+					Username: "collector",
+					Password: "secret",
+					TLS: configtls.ClientConfig{
+						Config: configtls.Config{CAFile: "ca.pem"},
+					},
+					KeepAlive:      time.Minute,
+					ConnectTimeout: 5 * time.Second,
+					CleanSession:   false,
+				},
+				Topics: []TopicConfig{
+					{Filter: "sensors/+/logs", QoS: 1},
+					{Filter: "gateways/#", QoS: 2},
+				},
+				FormatType: messaging.FormatTypeJSON,
+				Encoding:   &encoding,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "invalid"),
+			expectedErr: "topic filter \"sensors/#/logs\" has a multi-level wildcard before its last level\n" +
+				"qos of topic \"sensors/#/logs\" must be 0, 1 or 2\n" +
+				"topic filter \"sensors/temp+\" has a wildcard not occupying an entire level\n" +
+				"topic \"sensors/temp+\" is duplicated\n" +
+				"format \"xml\" is not supported; " +
+				"broker \"http://broker:1883\" has unsupported scheme \"http\"",
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "no_topics"),
+			expectedErr: "at least one topic must be specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
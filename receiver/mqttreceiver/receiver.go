@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver"
+
+import (
+	"context"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+const (
+	transport = "mqtt"
+
+	// subscriptionFailure is the return code of the topic filters the broker refused to subscribe to.
+	subscriptionFailure = 0x80
+)
+
+// mqttReceiver subscribes to the topics of the configuration, and passes the payloads of the
+// messages to its handler.
+type mqttReceiver struct {
+	settings   receiver.Settings
+	config     *Config
+	newHandler messaging.NewHandlerFunc
+
+	handle  messaging.Handler
+	obsrecv *receiverhelper.ObsReport
+	conn    *mqtt.Connection
+}
+
+func newMQTTReceiver(set receiver.Settings, cfg *Config, newHandler messaging.NewHandlerFunc) *mqttReceiver {
+	return &mqttReceiver{
+		settings:   set,
+		config:     cfg,
+		newHandler: newHandler,
+	}
+}
+
+func (r *mqttReceiver) Start(ctx context.Context, host component.Host) error {
+	var err error
+	if r.handle, err = r.newHandler(host, r.config.Encoding, r.config.FormatType); err != nil {
+		return err
+	}
+	r.obsrecv, err = receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             r.settings.ID,
+		Transport:              transport,
+		ReceiverCreateSettings: r.settings,
+	})
+	if err != nil {
+		return err
+	}
+	opts, err := r.config.NewClientOptions(ctx)
+	if err != nil {
+		return err
+	}
+	// The topics are subscribed to on every connection, the broker doesn't keep the subscriptions
+	// of the clean sessions.
+	opts.SetOnConnectHandler(r.subscribe).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			r.settings.Logger.Warn("Lost the connection to the MQTT broker", zap.Error(err))
+		})
+	r.conn = mqtt.NewConnection(paho.NewClient(opts), r.settings.Logger)
+	r.conn.Start()
+	return nil
+}
+
+func (r *mqttReceiver) Shutdown(context.Context) error {
+	if r.conn != nil {
+		r.conn.Close()
+	}
+	return nil
+}
+
+func (r *mqttReceiver) subscribe(client paho.Client) {
+	filters := make(map[string]byte, len(r.config.Topics))
+	for _, topic := range r.config.Topics {
+		filters[topic.Filter] = topic.QoS
+	}
+	token := client.SubscribeMultiple(filters, r.handleMessage)
+	token.Wait()
+	if err := token.Error(); err != nil {
+		r.settings.Logger.Error("Failed to subscribe to the topics", zap.Error(err))
+		return
+	}
+	for filter, code := range token.(*paho.SubscribeToken).Result() {
+		if code == subscriptionFailure {
+			r.settings.Logger.Error("The broker refused the subscription to the topic", zap.String("filter", filter))
+		}
+	}
+}
+
+// handleMessage handles the messages of the subscriptions, the messages are acknowledged to the
+// broker once they are consumed.
+func (r *mqttReceiver) handleMessage(_ paho.Client, msg paho.Message) {
+	if err := r.handle(context.Background(), r.obsrecv, msg.Payload()); err != nil {
+		r.settings.Logger.Error("Failed to process the message", zap.String("topic", msg.Topic()), zap.Error(err))
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/textencodingextension"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging/messagingtest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt/mqtttest"
+)
+
+func newTestConfig(broker *mqtttest.Broker, filters ...string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{broker.Endpoint}
+	for _, filter := range filters {
+		cfg.Topics = append(cfg.Topics, TopicConfig{Filter: filter, QoS: 1})
+	}
+	return cfg
+}
+
+func TestReceiveLogs(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	cfg := newTestConfig(broker, "sensors/+/logs")
+	sink := new(consumertest.LogsSink)
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, componenttest.NewNopHost())
+	broker.WaitForSubscription(t, "sensors/+/logs")
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("door opened")
+	payload, err := (&plog.JSONMarshaler{}).MarshalLogs(ld)
+	require.NoError(t, err)
+
+	// The payloads that can't be decoded are dropped, and the next messages are still received.
+	require.NoError(t, broker.Publish("sensors/door/logs", []byte("not OTLP"), 1))
+	require.NoError(t, broker.Publish("sensors/other/metrics", payload, 1))
+	require.NoError(t, broker.Publish("sensors/door/logs", payload, 1))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, ld, sink.AllLogs()[0])
+}
+
+func TestReceiveMetricsProto(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	cfg := newTestConfig(broker, "metrics/#")
+	cfg.FormatType = messaging.FormatTypeProto
+	sink := new(consumertest.MetricsSink)
+	rcv, err := NewFactory().CreateMetricsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, componenttest.NewNopHost())
+	broker.WaitForSubscription(t, "metrics/#")
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("temperature")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(21.5)
+	payload, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(md)
+	require.NoError(t, err)
+
+	require.NoError(t, broker.Publish("metrics/room/1", payload, 2))
+	require.Eventually(t, func() bool { return sink.DataPointCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, md, sink.AllMetrics()[0])
+}
+
+func TestReceiveTraces(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	cfg := newTestConfig(broker, "traces", "spans/#")
+	sink := new(consumertest.TracesSink)
+	rcv, err := NewFactory().CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, componenttest.NewNopHost())
+	broker.WaitForSubscription(t, "traces")
+	broker.WaitForSubscription(t, "spans/#")
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("checkout")
+	payload, err := (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+
+	require.NoError(t, broker.Publish("traces", payload, 0))
+	require.NoError(t, broker.Publish("spans/shop", payload, 1))
+	require.Eventually(t, func() bool { return sink.SpanCount() == 2 }, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestReceiveWithEncodingExtension(t *testing.T) {
+	factory := textencodingextension.NewFactory()
+	ext, err := factory.CreateExtension(context.Background(), extensiontest.NewNopSettings(), factory.CreateDefaultConfig())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, ext.Shutdown(context.Background()))
+	})
+	id := component.MustNewID("text_encoding")
+	host := messagingtest.NewHost(map[component.ID]component.Component{id: ext})
+
+	broker := mqtttest.NewBroker(t)
+	cfg := newTestConfig(broker, "syslog")
+	cfg.Encoding = &id
+	sink := new(consumertest.LogsSink)
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, host)
+	broker.WaitForSubscription(t, "syslog")
+
+	require.NoError(t, broker.Publish("syslog", []byte("<34>Oct 11 22:14:15 gateway su: 'su root' failed"), 1))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+	record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "<34>Oct 11 22:14:15 gateway su: 'su root' failed", record.Body().Str())
+
+	// The text encoding doesn't unmarshal metrics.
+	rcvMetrics, err := NewFactory().CreateMetricsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.EqualError(t, rcvMetrics.Start(context.Background(), host), `encoding "text_encoding" can't unmarshal metrics`)
+	assert.NoError(t, rcvMetrics.Shutdown(context.Background()))
+}
+
+func TestStartUnknownEncoding(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Topics = []TopicConfig{{Filter: "logs"}}
+	id := component.MustNewIDWithName("text_encoding", "missing")
+	cfg.Encoding = &id
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.EqualError(t, rcv.Start(context.Background(), componenttest.NewNopHost()), `unknown encoding "text_encoding/missing"`)
+	assert.NoError(t, rcv.Shutdown(context.Background()))
+}
+
+func TestResubscribeOnReconnect(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	cfg := newTestConfig(broker, "logs")
+	sink := new(consumertest.LogsSink)
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, componenttest.NewNopHost())
+	broker.WaitForSubscription(t, "logs")
+
+	// The broker drops the session of the client, the subscription is restored when it reconnects.
+	broker.DisconnectClients(t)
+	broker.WaitForSubscription(t, "logs")
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	payload, err := (&plog.JSONMarshaler{}).MarshalLogs(ld)
+	require.NoError(t, err)
+	require.NoError(t, broker.Publish("logs", payload, 1))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+}
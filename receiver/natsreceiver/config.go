@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/natsreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	internalnats "github.com/open-telemetry/opentelemetry-collector-contrib/internal/nats"
+)
+
+// deliverPolicies are the supported deliver policies of the JetStream consumers.
+var deliverPolicies = map[string]jetstream.DeliverPolicy{
+	"all":  jetstream.DeliverAllPolicy,
+	"last": jetstream.DeliverLastPolicy,
+	"new":  jetstream.DeliverNewPolicy,
+}
+
+// Config defines configuration for the NATS receiver.
+type Config struct {
+	internalnats.ClientConfig `mapstructure:",squash"`
+
+	// Subject is the subject the receiver subscribes to, it may contain the `*` and `>` wildcards.
+	Subject string `mapstructure:"subject"`
+
+	// QueueGroup is the queue group of the core NATS subscription, the messages are
+	// distributed between the receivers of the same group.
+	QueueGroup string `mapstructure:"queue_group"`
+
+	// JetStream configures the consumption of the messages of a JetStream stream.
+	JetStream JetStreamConfig `mapstructure:"jetstream"`
+
+	// FormatType is the OTLP encoding of the payloads of the messages, json or proto.
+	FormatType string `mapstructure:"format"`
+
+	// Encoding is the ID of the encoding extension decoding the payloads of the messages.
+	// If specified, it overrides `FormatType`.
+	Encoding *component.ID `mapstructure:"encoding"`
+}
+
+// JetStreamConfig configures the durable consumer of a JetStream stream. The messages are
+// acknowledged once consumed by the pipeline, for an at-least-once delivery.
+type JetStreamConfig struct {
+	// Enabled consumes the messages of the stream instead of subscribing to the subject.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Stream is the name of the stream storing the messages of the subject.
+	Stream string `mapstructure:"stream"`
+
+	// Consumer is the name of the durable consumer, it's created if it doesn't exist. The
+	// receivers of the same consumer share its messages.
+	Consumer string `mapstructure:"consumer"`
+
+	// DeliverPolicy is the first message delivered to a new consumer, all, last or new.
+	DeliverPolicy string `mapstructure:"deliver_policy"`
+
+	// AckWait is the time the server waits for the acknowledgement of a message before
+	// delivering it again.
+	AckWait time.Duration `mapstructure:"ack_wait"`
+
+	// MaxDeliver is the maximum number of deliveries of a message, unlimited if 0.
+	MaxDeliver int `mapstructure:"max_deliver"`
+
+	// NakDelay is the delay of the redelivery of the messages the pipeline failed to consume.
+	NakDelay time.Duration `mapstructure:"nak_delay"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	var errs error
+	if err := validateSubject(cfg.Subject); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.JetStream.Enabled {
+		if cfg.QueueGroup != "" {
+			errs = errors.Join(errs, errors.New("queue_group can't be used with jetstream, the receivers share the messages of their consumer"))
+		}
+		if err := cfg.JetStream.validate(); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	if cfg.FormatType != messaging.FormatTypeJSON && cfg.FormatType != messaging.FormatTypeProto {
+		errs = errors.Join(errs, fmt.Errorf("format %q is not supported", cfg.FormatType))
+	}
+	return errs
+}
+
+func (cfg *JetStreamConfig) validate() error {
+	var errs error
+	if cfg.Stream == "" {
+		errs = errors.Join(errs, errors.New("jetstream stream must be specified"))
+	}
+	if cfg.Consumer == "" {
+		errs = errors.Join(errs, errors.New("jetstream consumer must be specified"))
+	} else if strings.ContainsAny(cfg.Consumer, ".*> \t") {
+		errs = errors.Join(errs, fmt.Errorf("jetstream consumer %q can't contain '.', '*', '>' or whitespaces", cfg.Consumer))
+	}
+	if _, ok := deliverPolicies[cfg.DeliverPolicy]; !ok {
+		errs = errors.Join(errs, fmt.Errorf("jetstream deliver_policy %q is not supported", cfg.DeliverPolicy))
+	}
+	if cfg.AckWait <= 0 {
+		errs = errors.Join(errs, errors.New("jetstream ack_wait must be positive"))
+	}
+	if cfg.MaxDeliver < 0 {
+		errs = errors.Join(errs, errors.New("jetstream max_deliver can't be negative"))
+	}
+	if cfg.NakDelay < 0 {
+		errs = errors.Join(errs, errors.New("jetstream nak_delay can't be negative"))
+	}
+	return errs
+}
+
+// validateSubject checks the tokens of a subject aren't empty, and its wildcards occupy entire
+// tokens, the full wildcard being the last one.
+func validateSubject(subject string) error {
+	if subject == "" {
+		return errors.New("subject must be specified")
+	}
+	if strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("subject %q can't contain whitespaces", subject)
+	}
+	tokens := strings.Split(subject, ".")
+	for i, token := range tokens {
+		switch {
+		case token == "":
+			return fmt.Errorf("subject %q has an empty token", subject)
+		case strings.ContainsAny(token, "*>") && len(token) > 1:
+			return fmt.Errorf("subject %q has a wildcard not occupying an entire token", subject)
+		case token == ">" && i != len(tokens)-1:
+			return fmt.Errorf("subject %q has a full wildcard before its last token", subject)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsreceiver
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	internalnats "github.com/open-telemetry/opentelemetry-collector-contrib/internal/nats"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/natsreceiver/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	encoding := component.MustNewID("text_encoding")
+	defaultJetStream := JetStreamConfig{
+		DeliverPolicy: defaultDeliverPolicy,
+		AckWait:       defaultAckWait,
+		NakDelay:      defaultNakDelay,
+	}
+	tests := []struct {
+		id          component.ID
+		expected    component.Config
+		expectedErr string
+	}{
+		{
+			id: component.NewID(metadata.Type),
+			expected: &Config{
+				ClientConfig: internalnats.NewDefaultClientConfig(),
+				Subject:      "telemetry.>",
+				JetStream:    defaultJetStream,
+				FormatType:   messaging.FormatTypeJSON,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expected: &Config{
+				ClientConfig: internalnats.ClientConfig{
+					Servers: []string{"tls://nats-1:4222", "tls://nats-2:4222"},
+					Name:    "collector-1",
+					Auth: internalnats.AuthConfig{
+						Username: "collector",
+						Password: "secret",
+					},
+					TLS: configtls.ClientConfig{
+						Config: configtls.Config{CAFile: "ca.pem"},
+					},
+					ConnectTimeout: 10 * time.Second,
+					ReconnectWait:  time.Second,
+				},
+				Subject:    "sensors.*.logs",
+				QueueGroup: "collectors",
+				JetStream:  defaultJetStream,
+				FormatType: messaging.FormatTypeJSON,
+				Encoding:   &encoding,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "jetstream"),
+			expected: &Config{
+				ClientConfig: internalnats.NewDefaultClientConfig(),
+				Subject:      "telemetry.logs",
+				JetStream: JetStreamConfig{
+					Enabled:       true,
+					Stream:        "TELEMETRY",
+					Consumer:      "collector",
+					DeliverPolicy: "new",
+					AckWait:       time.Minute,
+					MaxDeliver:    5,
+					NakDelay:      10 * time.Second,
+				},
+				FormatType: messaging.FormatTypeProto,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "invalid"),
+			expectedErr: "subject \"sensors.>.logs\" has a full wildcard before its last token\n" +
+				"queue_group can't be used with jetstream, the receivers share the messages of their consumer\n" +
+				"jetstream stream must be specified\n" +
+				"jetstream consumer \"collector.1\" can't contain '.', '*', '>' or whitespaces\n" +
+				"jetstream deliver_policy \"first\" is not supported\n" +
+				"jetstream ack_wait must be positive\n" +
+				"format \"xml\" is not supported; " +
+				"server \"http://nats:4222\" has unsupported scheme \"http\"",
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "no_subject"),
+			expectedErr: "subject must be specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestValidateSubject(t *testing.T) {
+	tests := []struct {
+		subject     string
+		expectedErr string
+	}{
+		{subject: "logs"},
+		{subject: "sensors.*.logs"},
+		{subject: "sensors.>"},
+		{subject: ">"},
+		{subject: "sensors..logs", expectedErr: `subject "sensors..logs" has an empty token`},
+		{subject: ".logs", expectedErr: `subject ".logs" has an empty token`},
+		{subject: "sensors.temp*", expectedErr: `subject "sensors.temp*" has a wildcard not occupying an entire token`},
+		{subject: "sensors.>.logs", expectedErr: `subject "sensors.>.logs" has a full wildcard before its last token`},
+		{subject: "sensors logs", expectedErr: `subject "sensors logs" can't contain whitespaces`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			err := validateSubject(tt.subject)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/natsreceiver"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+)
+
+const (
+	transport = "nats"
+
+	// consumerRetryInterval is the interval between the attempts to create the JetStream consumer.
+	consumerRetryInterval = 5 * time.Second
+	// consumerTimeout is the timeout of an attempt to create the JetStream consumer.
+	consumerTimeout = 10 * time.Second
+)
+
+// natsReceiver subscribes to the subject of the configuration, or consumes the messages of the
+// JetStream stream, and passes the payloads of the messages to its handler.
+type natsReceiver struct {
+	settings   receiver.Settings
+	config     *Config
+	newHandler messaging.NewHandlerFunc
+
+	handle  messaging.Handler
+	obsrecv *receiverhelper.ObsReport
+	conn    *nats.Conn
+	sub     *nats.Subscription
+
+	// consumeCtx is set by the consuming goroutine before done is closed.
+	consumeCtx jetstream.ConsumeContext
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+func newNATSReceiver(set receiver.Settings, cfg *Config, newHandler messaging.NewHandlerFunc) *natsReceiver {
+	return &natsReceiver{
+		settings:   set,
+		config:     cfg,
+		newHandler: newHandler,
+	}
+}
+
+func (r *natsReceiver) Start(ctx context.Context, host component.Host) error {
+	var err error
+	if r.handle, err = r.newHandler(host, r.config.Encoding, r.config.FormatType); err != nil {
+		return err
+	}
+	r.obsrecv, err = receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             r.settings.ID,
+		Transport:              transport,
+		ReceiverCreateSettings: r.settings,
+	})
+	if err != nil {
+		return err
+	}
+	if r.conn, err = r.config.Connect(ctx, r.settings.Logger); err != nil {
+		return err
+	}
+	if !r.config.JetStream.Enabled {
+		// The subscription is sent to the server once connected, and again on every reconnection.
+		r.sub, err = r.conn.QueueSubscribe(r.config.Subject, r.config.QueueGroup, r.handleMessage)
+		return err
+	}
+	js, err := jetstream.New(r.conn)
+	if err != nil {
+		return err
+	}
+	var consumeCtx context.Context
+	consumeCtx, r.cancel = context.WithCancel(context.Background())
+	r.done = make(chan struct{})
+	go r.consume(consumeCtx, js)
+	return nil
+}
+
+func (r *natsReceiver) Shutdown(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+		<-r.done
+	}
+	var err error
+	if r.consumeCtx != nil {
+		r.consumeCtx.Stop()
+		select {
+		case <-r.consumeCtx.Closed():
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	if r.sub != nil {
+		err = errors.Join(err, r.sub.Unsubscribe())
+	}
+	if r.conn != nil {
+		r.conn.Close()
+	}
+	return err
+}
+
+// consume creates the durable consumer of the stream and consumes its messages. The consumer is
+// created again until it succeeds, the stream may not exist yet, or the server be unavailable.
+func (r *natsReceiver) consume(ctx context.Context, js jetstream.JetStream) {
+	defer close(r.done)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		consumeCtx, err := r.startConsumer(ctx, js)
+		if err == nil {
+			r.consumeCtx = consumeCtx
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		r.settings.Logger.Warn("Failed to create the JetStream consumer, retrying",
+			zap.String("stream", r.config.JetStream.Stream),
+			zap.String("consumer", r.config.JetStream.Consumer),
+			zap.Duration("interval", consumerRetryInterval),
+			zap.Error(err))
+		timer.Reset(consumerRetryInterval)
+	}
+}
+
+func (r *natsReceiver) startConsumer(ctx context.Context, js jetstream.JetStream) (jetstream.ConsumeContext, error) {
+	ctx, cancel := context.WithTimeout(ctx, consumerTimeout)
+	defer cancel()
+	cfg := r.config.JetStream
+	cons, err := js.CreateOrUpdateConsumer(ctx, cfg.Stream, jetstream.ConsumerConfig{
+		Durable:       cfg.Consumer,
+		FilterSubject: r.config.Subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       cfg.AckWait,
+		MaxDeliver:    cfg.MaxDeliver,
+		DeliverPolicy: deliverPolicies[cfg.DeliverPolicy],
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cons.Consume(r.handleJetStreamMessage, jetstream.ConsumeErrHandler(func(_ jetstream.ConsumeContext, err error) {
+		r.settings.Logger.Warn("Failed to consume the JetStream messages", zap.Error(err))
+	}))
+}
+
+// handleMessage handles the messages of the core NATS subscription, the messages failing to be
+// consumed are lost.
+func (r *natsReceiver) handleMessage(msg *nats.Msg) {
+	if err := r.handle(context.Background(), r.obsrecv, msg.Data); err != nil {
+		r.settings.Logger.Error("Failed to process the message", zap.String("subject", msg.Subject), zap.Error(err))
+	}
+}
+
+// handleJetStreamMessage handles the messages of the JetStream consumer. The messages are
+// acknowledged once consumed, and redelivered after the nak delay when the pipeline fails to
+// consume them, unless the error is permanent.
+func (r *natsReceiver) handleJetStreamMessage(msg jetstream.Msg) {
+	err := r.handle(context.Background(), r.obsrecv, msg.Data())
+	switch {
+	case err == nil:
+		err = msg.Ack()
+	case consumererror.IsPermanent(err):
+		r.settings.Logger.Error("Failed to process the message, dropping it", zap.String("subject", msg.Subject()), zap.Error(err))
+		err = msg.Term()
+	default:
+		r.settings.Logger.Warn("Failed to process the message, it will be redelivered", zap.String("subject", msg.Subject()), zap.Error(err))
+		err = msg.NakWithDelay(r.config.JetStream.NakDelay)
+	}
+	if err != nil {
+		r.settings.Logger.Error("Failed to acknowledge the message", zap.String("subject", msg.Subject()), zap.Error(err))
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package natsreceiver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/textencodingextension"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/messaging/messagingtest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/nats/natstest"
+)
+
+func newTestConfig(server *natstest.Server, subject string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Servers = []string{server.URL}
+	cfg.Subject = subject
+	return cfg
+}
+
+func newJetStreamTestConfig(server *natstest.Server, subject string) *Config {
+	cfg := newTestConfig(server, subject)
+	cfg.JetStream.Enabled = true
+	cfg.JetStream.Stream = "TELEMETRY"
+	cfg.JetStream.Consumer = "collector"
+	cfg.JetStream.NakDelay = 10 * time.Millisecond
+	return cfg
+}
+
+func newLogsPayload(t *testing.T, body string) (plog.Logs, []byte) {
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr(body)
+	payload, err := (&plog.JSONMarshaler{}).MarshalLogs(ld)
+	require.NoError(t, err)
+	return ld, payload
+}
+
+func TestReceiveLogs(t *testing.T) {
+	server := natstest.NewServer(t)
+	cfg := newTestConfig(server, "sensors.*.logs")
+	sink := new(consumertest.LogsSink)
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, componenttest.NewNopHost())
+	server.WaitForSubscription(t, "sensors.door.logs")
+
+	ld, payload := newLogsPayload(t, "door opened")
+	// The payloads that can't be decoded are dropped, and the next messages are still received.
+	conn := server.Conn(t)
+	require.NoError(t, conn.Publish("sensors.door.logs", []byte("not OTLP")))
+	require.NoError(t, conn.Publish("sensors.door.metrics", payload))
+	require.NoError(t, conn.Publish("sensors.door.logs", payload))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, ld, sink.AllLogs()[0])
+}
+
+func TestReceiveMetricsProto(t *testing.T) {
+	server := natstest.NewServer(t)
+	cfg := newTestConfig(server, "metrics.>")
+	cfg.FormatType = messaging.FormatTypeProto
+	sink := new(consumertest.MetricsSink)
+	rcv, err := NewFactory().CreateMetricsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, componenttest.NewNopHost())
+	server.WaitForSubscription(t, "metrics.room.1")
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("temperature")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(21.5)
+	payload, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(md)
+	require.NoError(t, err)
+
+	require.NoError(t, server.Conn(t).Publish("metrics.room.1", payload))
+	require.Eventually(t, func() bool { return sink.DataPointCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, md, sink.AllMetrics()[0])
+}
+
+func TestReceiveQueueGroup(t *testing.T) {
+	server := natstest.NewServer(t)
+	sinks := []*consumertest.TracesSink{new(consumertest.TracesSink), new(consumertest.TracesSink)}
+	for _, sink := range sinks {
+		cfg := newTestConfig(server, "traces")
+		cfg.QueueGroup = "collectors"
+		rcv, err := NewFactory().CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+		require.NoError(t, err)
+		messagingtest.Start(t, rcv, componenttest.NewNopHost())
+	}
+	server.WaitForSubscription(t, "traces")
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("checkout")
+	payload, err := (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+
+	// Every message is received by a single receiver of the group.
+	conn := server.Conn(t)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, conn.Publish("traces", payload))
+	}
+	require.Eventually(t, func() bool {
+		return sinks[0].SpanCount()+sinks[1].SpanCount() == 10
+	}, 10*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 10, sinks[0].SpanCount()+sinks[1].SpanCount())
+}
+
+// failingLogsConsumer fails to consume the first logs, with a retryable error.
+type failingLogsConsumer struct {
+	*consumertest.LogsSink
+	failures atomic.Int32
+}
+
+func (c *failingLogsConsumer) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	if c.failures.Add(-1) >= 0 {
+		return errors.New("pipeline is busy")
+	}
+	return c.LogsSink.ConsumeLogs(ctx, ld)
+}
+
+func (c *failingLogsConsumer) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{}
+}
+
+func TestReceiveJetStream(t *testing.T) {
+	server := natstest.NewServer(t)
+	stream := server.CreateStream(t, "TELEMETRY", "telemetry.>")
+	cfg := newJetStreamTestConfig(server, "telemetry.logs")
+
+	// The messages published before the receiver starts are delivered.
+	js, err := jetstream.New(server.Conn(t))
+	require.NoError(t, err)
+	ld, payload := newLogsPayload(t, "stored")
+	_, err = js.Publish(context.Background(), "telemetry.logs", payload)
+	require.NoError(t, err)
+	_, err = js.Publish(context.Background(), "telemetry.metrics", payload)
+	require.NoError(t, err)
+
+	sink := &failingLogsConsumer{LogsSink: new(consumertest.LogsSink)}
+	sink.failures.Store(2)
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, componenttest.NewNopHost())
+
+	// The message is redelivered until the pipeline consumes it, and acknowledged once consumed.
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, ld, sink.AllLogs()[0])
+	cons, err := stream.Consumer(context.Background(), "collector")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		info, err := cons.Info(context.Background())
+		return err == nil && info.Delivered.Consumer == 3 && info.NumAckPending == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	info, err := cons.Info(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, info.NumPending, "the metrics message doesn't match the subject of the consumer")
+
+	// The payloads that can't be decoded are terminated, they aren't redelivered.
+	_, err = js.Publish(context.Background(), "telemetry.logs", []byte("not OTLP"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		info, err := cons.Info(context.Background())
+		return err == nil && info.Delivered.Consumer == 4 && info.NumAckPending == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	info, err = cons.Info(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, info.NumRedelivered)
+	assert.Equal(t, 1, sink.LogRecordCount())
+}
+
+func TestReceiveJetStreamResumesConsumer(t *testing.T) {
+	server := natstest.NewServer(t)
+	server.CreateStream(t, "TELEMETRY", "telemetry.>")
+	cfg := newJetStreamTestConfig(server, "telemetry.logs")
+	js, err := jetstream.New(server.Conn(t))
+	require.NoError(t, err)
+
+	sink := new(consumertest.LogsSink)
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	_, payload := newLogsPayload(t, "first")
+	_, err = js.Publish(context.Background(), "telemetry.logs", payload)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, rcv.Shutdown(context.Background()))
+
+	// The durable consumer keeps its position, a new receiver only gets the messages it hasn't acknowledged.
+	_, payload = newLogsPayload(t, "second")
+	_, err = js.Publish(context.Background(), "telemetry.logs", payload)
+	require.NoError(t, err)
+	sink.Reset()
+	rcv, err = NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, componenttest.NewNopHost())
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+	record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "second", record.Body().Str())
+}
+
+func TestReceiveWithEncodingExtension(t *testing.T) {
+	factory := textencodingextension.NewFactory()
+	ext, err := factory.CreateExtension(context.Background(), extensiontest.NewNopSettings(), factory.CreateDefaultConfig())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, ext.Shutdown(context.Background()))
+	})
+	id := component.MustNewID("text_encoding")
+	host := messagingtest.NewHost(map[component.ID]component.Component{id: ext})
+
+	server := natstest.NewServer(t)
+	cfg := newTestConfig(server, "syslog")
+	cfg.Encoding = &id
+	sink := new(consumertest.LogsSink)
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	messagingtest.Start(t, rcv, host)
+	server.WaitForSubscription(t, "syslog")
+
+	require.NoError(t, server.Conn(t).Publish("syslog", []byte("<34>Oct 11 22:14:15 gateway su: 'su root' failed")))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 10*time.Second, 10*time.Millisecond)
+	record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "<34>Oct 11 22:14:15 gateway su: 'su root' failed", record.Body().Str())
+
+	// The text encoding doesn't unmarshal metrics.
+	rcvMetrics, err := NewFactory().CreateMetricsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.EqualError(t, rcvMetrics.Start(context.Background(), host), `encoding "text_encoding" can't unmarshal metrics`)
+	assert.NoError(t, rcvMetrics.Shutdown(context.Background()))
+}
+
+func TestStartUnknownEncoding(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Subject = "logs"
+	id := component.MustNewIDWithName("text_encoding", "missing")
+	cfg.Encoding = &id
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.EqualError(t, rcv.Start(context.Background(), componenttest.NewNopHost()), `unknown encoding "text_encoding/missing"`)
+	assert.NoError(t, rcv.Shutdown(context.Background()))
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines configuration for the NetFlow receiver.
+type Config struct {
+	// Endpoint is the UDP address on which the NetFlow v5, NetFlow v9, IPFIX and sFlow v5
+	// datagrams are received, the protocol of each datagram is detected from its version.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Workers is the number of goroutines reading and decoding the datagrams.
+	Workers int `mapstructure:"workers"`
+
+	// ReadBufferSize is the size in bytes of the receive buffer of the socket, the default
+	// of the operating system is used if zero. Large buffers avoid dropping the bursts of
+	// datagrams sent by busy exporters.
+	ReadBufferSize int `mapstructure:"read_buffer_size"`
+
+	// Aggregation configures the byte and packet count metrics of the flows, they are
+	// produced if the receiver is used in a metrics pipeline.
+	Aggregation AggregationConfig `mapstructure:"aggregation"`
+}
+
+// AggregationConfig configures the aggregation of the flows into metrics.
+type AggregationConfig struct {
+	// Interval is the interval at which the aggregated metrics are emitted.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Attributes are the attributes of the flow logs the flows are aggregated by,
+	// they are the attributes of the metrics.
+	Attributes []string `mapstructure:"attributes"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	var errs error
+	if cfg.Endpoint == "" {
+		errs = errors.Join(errs, errors.New("endpoint must be specified"))
+	}
+	if cfg.Workers < 1 {
+		errs = errors.Join(errs, errors.New("workers must be at least 1"))
+	}
+	if cfg.ReadBufferSize < 0 {
+		errs = errors.Join(errs, errors.New("read_buffer_size can't be negative"))
+	}
+	if cfg.Aggregation.Interval <= 0 {
+		errs = errors.Join(errs, errors.New("aggregation interval must be positive"))
+	}
+	seen := map[string]bool{}
+	for _, attr := range cfg.Aggregation.Attributes {
+		if !aggregationAttributes[attr] {
+			errs = errors.Join(errs, fmt.Errorf("aggregation attribute %q is not supported", attr))
+		}
+		if seen[attr] {
+			errs = errors.Join(errs, fmt.Errorf("aggregation attribute %q is duplicated", attr))
+		}
+		seen[attr] = true
+	}
+	return errs
+}
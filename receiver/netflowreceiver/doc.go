@@ -0,0 +1,8 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package netflowreceiver receives the NetFlow, IPFIX and sFlow datagrams of routers and
+// switches, and emits their flow records as logs and aggregated metrics.
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/metadata"
+)
+
+const (
+	defaultEndpoint            = "localhost:2055"
+	defaultAggregationInterval = time.Minute
+)
+
+// NewFactory creates a factory for the NetFlow receiver.
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability),
+		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Endpoint: defaultEndpoint,
+		Workers:  1,
+		Aggregation: AggregationConfig{
+			Interval: defaultAggregationInterval,
+			Attributes: []string{
+				attributeExporterAddress,
+				attributeSourceAddress,
+				attributeDestinationAddress,
+				attributeNetworkTransport,
+			},
+		},
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	set receiver.Settings,
+	cfg component.Config,
+	nextConsumer consumer.Logs,
+) (receiver.Logs, error) {
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		return newNetFlowReceiver(set, cfg.(*Config))
+	})
+	r.Unwrap().(*netflowReceiver).registerLogsConsumer(nextConsumer)
+	return r, nil
+}
+
+func createMetricsReceiver(
+	_ context.Context,
+	set receiver.Settings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (receiver.Metrics, error) {
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		return newNetFlowReceiver(set, cfg.(*Config))
+	})
+	r.Unwrap().(*netflowReceiver).registerMetricsConsumer(nextConsumer)
+	return r, nil
+}
+
+// receivers shares the socket of a receiver between its logs and metrics pipelines.
+var receivers = sharedcomponent.NewSharedComponents()
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/flow"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+var errTruncated = errors.New("truncated datagram")
+
+// Decoder decodes the datagrams of all the supported protocols. The templates of NetFlow v9 and
+// IPFIX are cached per exporter and observation domain, so the data records of a datagram can be
+// decoded with the templates received in earlier datagrams. A Decoder is safe for concurrent use.
+type Decoder struct {
+	mu        sync.Mutex
+	templates map[templateKey]*template
+}
+
+// NewDecoder returns a Decoder without cached templates.
+func NewDecoder() *Decoder {
+	return &Decoder{templates: map[templateKey]*template{}}
+}
+
+// Decode decodes the flow records of a datagram sent by the exporter. The records decoded before
+// an error, or in the other sets of the datagram than the data sets whose template is unknown,
+// are returned along with the error.
+func (d *Decoder) Decode(exporter netip.Addr, data []byte) ([]Record, error) {
+	if len(data) < 4 {
+		return nil, errTruncated
+	}
+	// The version of NetFlow and IPFIX is a 16 bits integer, that of sFlow is a 32 bits integer.
+	switch version := binary.BigEndian.Uint16(data); version {
+	case 0:
+		if v := binary.BigEndian.Uint32(data); v != 5 {
+			return nil, fmt.Errorf("unsupported sFlow version %d", v)
+		}
+		return decodeSFlowV5(data)
+	case 5:
+		return decodeNetFlowV5(exporter, data)
+	case 9:
+		return d.decodeNetFlowV9(exporter, data)
+	case 10:
+		return d.decodeIPFIX(exporter, data)
+	default:
+		return nil, fmt.Errorf("unsupported NetFlow version %d", version)
+	}
+}
+
+// TemplateCount returns the number of cached templates.
+func (d *Decoder) TemplateCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.templates)
+}
+
+func (d *Decoder) template(key templateKey) *template {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.templates[key]
+}
+
+func (d *Decoder) setTemplate(key templateKey, t *template) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if t == nil {
+		delete(d.templates, key)
+		return
+	}
+	d.templates[key] = t
+}
+
+// reader reads the big endian integers of a datagram, it records the first out of bounds read
+// and returns zero values from then on.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.data) {
+		r.err = errTruncated
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uint8() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) ipv4() netip.Addr {
+	if b := r.bytes(4); b != nil {
+		return netip.AddrFrom4([4]byte(b))
+	}
+	return netip.Addr{}
+}
+
+func (r *reader) ipv6() netip.Addr {
+	if b := r.bytes(16); b != nil {
+		return netip.AddrFrom16([16]byte(b))
+	}
+	return netip.Addr{}
+}
+
+// uptimeTime returns the time of a system uptime in milliseconds, given the uptime at the export time.
+func uptimeTime(exportTime time.Time, sysUptime uint32, uptime uint32) time.Time {
+	// The uptimes are unsigned 32 bits integers wrapping around after about 49 days, their signed
+	// difference is right as long as the flow is younger than 24 days.
+	return exportTime.Add(-time.Duration(int32(sysUptime-uptime)) * time.Millisecond)
+}
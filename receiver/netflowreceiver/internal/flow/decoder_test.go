@@ -0,0 +1,285 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	exporterA = netip.MustParseAddr("192.0.2.10")
+	exporterB = netip.MustParseAddr("192.0.2.11")
+)
+
+// readPacket reads a captured datagram of the testdata directory of the receiver.
+func readPacket(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+func TestDecodeNetFlowV5(t *testing.T) {
+	records, err := NewDecoder().Decode(exporterA, readPacket(t, "netflow_v5.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, []Record{
+		{
+			Type:            TypeNetFlowV5,
+			Exporter:        exporterA,
+			Start:           time.Unix(1699999940, 0),
+			End:             time.Unix(1699999990, 0),
+			SrcAddr:         netip.MustParseAddr("10.0.0.1"),
+			DstAddr:         netip.MustParseAddr("10.0.0.2"),
+			NextHop:         netip.MustParseAddr("10.0.0.254"),
+			SrcPort:         49152,
+			DstPort:         443,
+			Protocol:        ProtocolTCP,
+			TCPFlags:        0x1b,
+			Bytes:           1500,
+			Packets:         10,
+			InputInterface:  1,
+			OutputInterface: 2,
+			SrcAS:           65001,
+			DstAS:           65002,
+			SamplingRate:    100,
+		},
+		{
+			Type:            TypeNetFlowV5,
+			Exporter:        exporterA,
+			Start:           time.Unix(1699999999, 0),
+			End:             time.Unix(1699999999, 0),
+			SrcAddr:         netip.MustParseAddr("10.0.0.3"),
+			DstAddr:         netip.MustParseAddr("8.8.8.8"),
+			NextHop:         netip.MustParseAddr("10.0.0.254"),
+			SrcPort:         53000,
+			DstPort:         53,
+			Protocol:        ProtocolUDP,
+			Bytes:           80,
+			Packets:         1,
+			InputInterface:  1,
+			OutputInterface: 3,
+			DstAS:           15169,
+			SamplingRate:    100,
+		},
+	}, records)
+}
+
+func TestDecodeNetFlowV9(t *testing.T) {
+	d := NewDecoder()
+	records, err := d.Decode(exporterA, readPacket(t, "netflow_v9.bin"))
+	require.NoError(t, err)
+	// The records of the options template describe the exporter, they aren't flows.
+	assert.Equal(t, []Record{
+		{
+			Type:                TypeNetFlowV9,
+			Exporter:            exporterA,
+			ObservationDomainID: 7,
+			Start:               time.Unix(1699999980, 0),
+			End:                 time.Unix(1699999995, 0),
+			SrcAddr:             netip.MustParseAddr("192.168.1.10"),
+			DstAddr:             netip.MustParseAddr("192.168.1.20"),
+			SrcPort:             51000,
+			DstPort:             22,
+			Protocol:            ProtocolTCP,
+			TCPFlags:            0x1a,
+			Bytes:               4200,
+			Packets:             30,
+			InputInterface:      4,
+			OutputInterface:     5,
+		},
+		{
+			Type:                TypeNetFlowV9,
+			Exporter:            exporterA,
+			ObservationDomainID: 7,
+			Start:               time.Unix(1699999996, 0),
+			End:                 time.UnixMilli(1699999996500),
+			SrcAddr:             netip.MustParseAddr("192.168.1.11"),
+			DstAddr:             netip.MustParseAddr("1.1.1.1"),
+			SrcPort:             33000,
+			DstPort:             53,
+			Protocol:            ProtocolUDP,
+			Bytes:               120,
+			Packets:             2,
+			InputInterface:      4,
+			OutputInterface:     6,
+		},
+	}, records)
+	assert.Equal(t, 2, d.TemplateCount())
+
+	// The data records of the next datagrams are decoded with the cached template.
+	records, err = d.Decode(exporterA, readPacket(t, "netflow_v9_data.bin"))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, netip.MustParseAddr("192.168.1.12"), records[0].SrcAddr)
+	assert.Equal(t, time.Unix(1700000010, 0), records[0].End)
+}
+
+func TestDecodeIPFIX(t *testing.T) {
+	d := NewDecoder()
+	records, err := d.Decode(exporterA, readPacket(t, "ipfix.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, []Record{
+		{
+			Type:                TypeIPFIX,
+			Exporter:            exporterA,
+			ObservationDomainID: 42,
+			Start:               time.UnixMilli(1699999990000),
+			End:                 time.UnixMilli(1699999999500),
+			SrcAddr:             netip.MustParseAddr("2001:db8::1"),
+			DstAddr:             netip.MustParseAddr("2001:db8::2"),
+			SrcPort:             50000,
+			DstPort:             443,
+			Protocol:            ProtocolTCP,
+			Bytes:               123456,
+			Packets:             100,
+		},
+	}, records)
+
+	// The variable length fields of the records are skipped according to their own length.
+	records, err = d.Decode(exporterA, readPacket(t, "ipfix_data.bin"))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, netip.MustParseAddr("2001:db8::3"), records[0].SrcAddr)
+	assert.Equal(t, uint64(2000), records[0].Bytes)
+	assert.Equal(t, netip.MustParseAddr("2001:db8::5"), records[1].SrcAddr)
+	assert.Equal(t, uint16(53), records[1].DstPort)
+	assert.Equal(t, time.UnixMilli(1700000003000), records[1].End)
+}
+
+func TestDecodeTemplatesPerExporter(t *testing.T) {
+	d := NewDecoder()
+	_, err := d.Decode(exporterA, readPacket(t, "ipfix.bin"))
+	require.NoError(t, err)
+
+	// The templates of an exporter aren't used for the data records of the others.
+	records, err := d.Decode(exporterB, readPacket(t, "ipfix_data.bin"))
+	assert.ErrorContains(t, err, "unknown template 300 of exporter 192.0.2.11 and observation domain 42")
+	assert.Empty(t, records)
+
+	records, err = d.Decode(exporterB, readPacket(t, "netflow_v9_data.bin"))
+	assert.ErrorContains(t, err, "unknown template 256 of exporter 192.0.2.11 and observation domain 7")
+	assert.Empty(t, records)
+}
+
+func TestDecodeTemplateWithdrawal(t *testing.T) {
+	d := NewDecoder()
+	_, err := d.Decode(exporterA, readPacket(t, "ipfix.bin"))
+	require.NoError(t, err)
+	require.Equal(t, 1, d.TemplateCount())
+
+	withdrawal := []byte{
+		0x00, 0x0a, 0x00, 0x18, 0x65, 0x53, 0xf1, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x2a, // header
+		0x00, 0x02, 0x00, 0x08, 0x01, 0x2c, 0x00, 0x00, // template set withdrawing the template 300
+	}
+	_, err = d.Decode(exporterA, withdrawal)
+	require.NoError(t, err)
+	assert.Equal(t, 0, d.TemplateCount())
+}
+
+func TestDecodeSFlowV5(t *testing.T) {
+	records, err := NewDecoder().Decode(exporterA, readPacket(t, "sflow_v5.bin"))
+	require.NoError(t, err)
+	// The counter sample is skipped, the exporter is the agent address of the datagram.
+	assert.Equal(t, []Record{
+		{
+			Type:            TypeSFlowV5,
+			Exporter:        netip.MustParseAddr("192.0.2.1"),
+			SrcAddr:         netip.MustParseAddr("10.1.1.1"),
+			DstAddr:         netip.MustParseAddr("10.2.2.2"),
+			NextHop:         netip.MustParseAddr("10.1.1.254"),
+			SrcPort:         40000,
+			DstPort:         80,
+			Protocol:        ProtocolTCP,
+			TCPFlags:        0x18,
+			ToS:             0x10,
+			Bytes:           1518,
+			Packets:         1,
+			InputInterface:  5,
+			OutputInterface: 6,
+			SamplingRate:    1000,
+		},
+		{
+			Type:            TypeSFlowV5,
+			Exporter:        netip.MustParseAddr("192.0.2.1"),
+			SrcAddr:         netip.MustParseAddr("2001:db8::10"),
+			DstAddr:         netip.MustParseAddr("ff02::fb"),
+			SrcPort:         5353,
+			DstPort:         5353,
+			Protocol:        ProtocolUDP,
+			ToS:             0x20,
+			Bytes:           100,
+			Packets:         1,
+			InputInterface:  7,
+			OutputInterface: 8,
+			SamplingRate:    512,
+		},
+	}, records)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		// file is the captured datagram truncated to its first length bytes, if data is nil.
+		file    string
+		length  int
+		wantErr string
+	}{
+		{
+			name:    "too short",
+			data:    []byte{0x00},
+			wantErr: "truncated datagram",
+		},
+		{
+			name:    "unsupported NetFlow version",
+			data:    []byte{0x00, 0x07, 0x00, 0x00},
+			wantErr: "unsupported NetFlow version 7",
+		},
+		{
+			name:    "unsupported sFlow version",
+			data:    []byte{0x00, 0x00, 0x00, 0x04},
+			wantErr: "unsupported sFlow version 4",
+		},
+		{
+			name:    "truncated NetFlow v5",
+			file:    "netflow_v5.bin",
+			length:  100,
+			wantErr: "too short for 2 records",
+		},
+		{
+			name:    "truncated NetFlow v9 set",
+			file:    "netflow_v9.bin",
+			length:  150,
+			wantErr: "set 256: truncated datagram",
+		},
+		{
+			name:    "IPFIX length",
+			file:    "ipfix.bin",
+			length:  100,
+			wantErr: "IPFIX message length 154 doesn't match the datagram length 100",
+		},
+		{
+			name:    "truncated sFlow sample",
+			file:    "sflow_v5.bin",
+			length:  100,
+			wantErr: "sample 0: truncated datagram",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			if data == nil {
+				data = readPacket(t, tt.file)[:tt.length]
+			}
+			_, err := NewDecoder().Decode(exporterA, data)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
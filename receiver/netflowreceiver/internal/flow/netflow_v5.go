@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/flow"
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+)
+
+const (
+	netFlowV5HeaderLength = 24
+	netFlowV5RecordLength = 48
+)
+
+// decodeNetFlowV5 decodes a NetFlow v5 datagram, made of a header and fixed length records.
+// See https://www.cisco.com/c/en/us/td/docs/net_mgmt/netflow_collection_engine/3-6/user/guide/format.html.
+func decodeNetFlowV5(exporter netip.Addr, data []byte) ([]Record, error) {
+	if len(data) < netFlowV5HeaderLength {
+		return nil, errTruncated
+	}
+	r := &reader{data: data}
+	r.uint16() // version
+	count := int(r.uint16())
+	sysUptime := r.uint32()
+	exportTime := time.Unix(int64(r.uint32()), int64(r.uint32()))
+	r.uint32() // flow_sequence
+	r.uint8()  // engine_type
+	r.uint8()  // engine_id
+	// The first two bits of the sampling interval are the sampling mode.
+	samplingRate := uint32(r.uint16() & 0x3fff)
+
+	if len(r.data) < count*netFlowV5RecordLength {
+		return nil, fmt.Errorf("NetFlow v5 datagram of %d bytes is too short for %d records", len(data), count)
+	}
+	records := make([]Record, 0, count)
+	for i := 0; i < count; i++ {
+		rec := Record{
+			Type:         TypeNetFlowV5,
+			Exporter:     exporter,
+			SamplingRate: samplingRate,
+		}
+		rec.SrcAddr = r.ipv4()
+		rec.DstAddr = r.ipv4()
+		rec.NextHop = r.ipv4()
+		rec.InputInterface = uint32(r.uint16())
+		rec.OutputInterface = uint32(r.uint16())
+		rec.Packets = uint64(r.uint32())
+		rec.Bytes = uint64(r.uint32())
+		rec.Start = uptimeTime(exportTime, sysUptime, r.uint32())
+		rec.End = uptimeTime(exportTime, sysUptime, r.uint32())
+		rec.SrcPort = r.uint16()
+		rec.DstPort = r.uint16()
+		r.uint8() // pad1
+		rec.TCPFlags = r.uint8()
+		rec.Protocol = r.uint8()
+		rec.ToS = r.uint8()
+		rec.SrcAS = uint32(r.uint16())
+		rec.DstAS = uint32(r.uint16())
+		r.bytes(4) // src_mask, dst_mask and pad2
+		records = append(records, rec)
+	}
+	return records, r.err
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package flow decodes the NetFlow v5, NetFlow v9, IPFIX and sFlow v5 datagrams
+// sent by routers and switches into flow records.
+package flow // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/flow"
+
+import (
+	"net/netip"
+	"time"
+)
+
+// The types of the flow records, after the protocol of the datagram they were decoded from.
+const (
+	TypeNetFlowV5 = "netflow_v5"
+	TypeNetFlowV9 = "netflow_v9"
+	TypeIPFIX     = "ipfix"
+	TypeSFlowV5   = "sflow_v5"
+)
+
+// The IP protocol numbers of the transports with ports.
+const (
+	ProtocolTCP = 6
+	ProtocolUDP = 17
+)
+
+// Record is a flow record, the fields that are missing from the datagram are left to their zero value.
+type Record struct {
+	// Type is the protocol of the datagram, one of the Type constants.
+	Type string
+	// Exporter is the address of the device that exported the flow: the sender of the datagram
+	// for NetFlow and IPFIX, the agent address of the datagram for sFlow.
+	Exporter netip.Addr
+	// ObservationDomainID is the observation domain of IPFIX and the source ID of NetFlow v9.
+	ObservationDomainID uint32
+
+	// Start and End are the times of the first and last packets of the flow, they are
+	// zero for sFlow whose samples are single packets.
+	Start time.Time
+	End   time.Time
+
+	SrcAddr  netip.Addr
+	DstAddr  netip.Addr
+	NextHop  netip.Addr
+	SrcPort  uint16
+	DstPort  uint16
+	Protocol uint8
+	TCPFlags uint8
+	ToS      uint8
+
+	Bytes   uint64
+	Packets uint64
+
+	InputInterface  uint32
+	OutputInterface uint32
+	SrcAS           uint32
+	DstAS           uint32
+
+	// SamplingRate is the number of packets represented by each sampled packet, zero if unknown.
+	SamplingRate uint32
+}
@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/flow"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+)
+
+// The formats of the sFlow v5 samples and flow records, with the enterprise 0.
+// See https://sflow.org/sflow_version_5.txt.
+const (
+	sflowAddressIPv4 = 1
+	sflowAddressIPv6 = 2
+
+	sflowFlowSample         = 1
+	sflowExpandedFlowSample = 3
+
+	sflowRawPacketHeader = 1
+	sflowSampledIPv4     = 3
+	sflowSampledIPv6     = 4
+	sflowExtendedRouter  = 1002
+
+	sflowHeaderProtocolEthernet = 1
+	sflowHeaderProtocolIPv4     = 11
+	sflowHeaderProtocolIPv6     = 12
+
+	// sflowInterfaceMask masks the format bits of the interfaces of the compact flow samples.
+	sflowInterfaceMask = 0x3fffffff
+)
+
+// The EtherTypes of the sampled packet headers.
+const (
+	etherTypeIPv4   = 0x0800
+	etherTypeIPv6   = 0x86dd
+	etherTypeVLAN   = 0x8100
+	etherTypeQinQ   = 0x88a8
+	ethernetAddrLen = 6
+)
+
+// decodeSFlowV5 decodes an sFlow v5 datagram, each flow sample is decoded into a record of a single
+// packet. The counter samples and the unsupported flow records are skipped.
+func decodeSFlowV5(data []byte) ([]Record, error) {
+	r := &reader{data: data}
+	r.uint32() // version
+	var agent netip.Addr
+	switch addressType := r.uint32(); addressType {
+	case sflowAddressIPv4:
+		agent = r.ipv4()
+	case sflowAddressIPv6:
+		agent = r.ipv6()
+	default:
+		return nil, fmt.Errorf("unsupported sFlow agent address type %d", addressType)
+	}
+	r.uint32() // sub_agent_id
+	r.uint32() // sequence_number
+	r.uint32() // uptime
+	count := int(r.uint32())
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var records []Record
+	for i := 0; i < count; i++ {
+		format := r.uint32()
+		sample := &reader{data: r.bytes(int(r.uint32()))}
+		if r.err != nil {
+			return records, fmt.Errorf("sample %d: %w", i, r.err)
+		}
+		switch format {
+		case sflowFlowSample, sflowExpandedFlowSample:
+			rec, err := decodeSFlowFlowSample(sample, format == sflowExpandedFlowSample)
+			if err != nil {
+				return records, fmt.Errorf("sample %d: %w", i, err)
+			}
+			rec.Exporter = agent
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+func decodeSFlowFlowSample(r *reader, expanded bool) (Record, error) {
+	rec := Record{Type: TypeSFlowV5, Packets: 1}
+	r.uint32() // sequence_number
+	if expanded {
+		r.uint32() // source_id_type
+		r.uint32() // source_id_index
+	} else {
+		r.uint32() // source_id
+	}
+	rec.SamplingRate = r.uint32()
+	r.uint32() // sample_pool
+	r.uint32() // drops
+	if expanded {
+		r.uint32() // input format
+		rec.InputInterface = r.uint32()
+		r.uint32() // output format
+		rec.OutputInterface = r.uint32()
+	} else {
+		rec.InputInterface = r.uint32() & sflowInterfaceMask
+		rec.OutputInterface = r.uint32() & sflowInterfaceMask
+	}
+	count := int(r.uint32())
+	if r.err != nil {
+		return rec, r.err
+	}
+
+	for i := 0; i < count; i++ {
+		format := r.uint32()
+		fr := &reader{data: r.bytes(int(r.uint32()))}
+		if r.err != nil {
+			return rec, fmt.Errorf("flow record %d: %w", i, r.err)
+		}
+		switch format {
+		case sflowRawPacketHeader:
+			protocol := fr.uint32()
+			rec.Bytes = uint64(fr.uint32()) // frame_length
+			fr.uint32()                     // stripped
+			header := fr.bytes(int(fr.uint32()))
+			if fr.err != nil {
+				return rec, fmt.Errorf("raw packet header: %w", fr.err)
+			}
+			decodePacketHeader(&rec, protocol, header)
+		case sflowSampledIPv4, sflowSampledIPv6:
+			rec.Bytes = uint64(fr.uint32())
+			rec.Protocol = uint8(fr.uint32())
+			if format == sflowSampledIPv4 {
+				rec.SrcAddr, rec.DstAddr = fr.ipv4(), fr.ipv4()
+			} else {
+				rec.SrcAddr, rec.DstAddr = fr.ipv6(), fr.ipv6()
+			}
+			rec.SrcPort = uint16(fr.uint32())
+			rec.DstPort = uint16(fr.uint32())
+			rec.TCPFlags = uint8(fr.uint32())
+			rec.ToS = uint8(fr.uint32())
+		case sflowExtendedRouter:
+			switch fr.uint32() {
+			case sflowAddressIPv4:
+				rec.NextHop = fr.ipv4()
+			case sflowAddressIPv6:
+				rec.NextHop = fr.ipv6()
+			}
+		}
+		if fr.err != nil {
+			return rec, fmt.Errorf("flow record %d: %w", i, fr.err)
+		}
+	}
+	return rec, nil
+}
+
+// decodePacketHeader decodes the addresses, protocol and ports of the sampled packet header. The
+// headers are truncated by the agents, the fields beyond the end of the header are left unset.
+func decodePacketHeader(rec *Record, protocol uint32, header []byte) {
+	var etherType uint16
+	switch protocol {
+	case sflowHeaderProtocolEthernet:
+		if len(header) < 2*ethernetAddrLen+2 {
+			return
+		}
+		etherType = binary.BigEndian.Uint16(header[2*ethernetAddrLen:])
+		header = header[2*ethernetAddrLen+2:]
+		for (etherType == etherTypeVLAN || etherType == etherTypeQinQ) && len(header) >= 4 {
+			etherType = binary.BigEndian.Uint16(header[2:])
+			header = header[4:]
+		}
+	case sflowHeaderProtocolIPv4:
+		etherType = etherTypeIPv4
+	case sflowHeaderProtocolIPv6:
+		etherType = etherTypeIPv6
+	default:
+		return
+	}
+
+	var transport []byte
+	switch etherType {
+	case etherTypeIPv4:
+		if len(header) < 20 {
+			return
+		}
+		rec.ToS = header[1]
+		rec.Protocol = header[9]
+		rec.SrcAddr = netip.AddrFrom4([4]byte(header[12:16]))
+		rec.DstAddr = netip.AddrFrom4([4]byte(header[16:20]))
+		headerLength := int(header[0]&0x0f) * 4
+		if headerLength < 20 || headerLength > len(header) {
+			return
+		}
+		// The fragments other than the first one have no transport header.
+		if binary.BigEndian.Uint16(header[6:])&0x1fff != 0 {
+			return
+		}
+		transport = header[headerLength:]
+	case etherTypeIPv6:
+		if len(header) < 40 {
+			return
+		}
+		rec.ToS = uint8(binary.BigEndian.Uint16(header) >> 4)
+		rec.Protocol = header[6]
+		rec.SrcAddr = netip.AddrFrom16([16]byte(header[8:24]))
+		rec.DstAddr = netip.AddrFrom16([16]byte(header[24:40]))
+		// The extension headers aren't decoded, the ports are only set if the next header is the transport.
+		transport = header[40:]
+	default:
+		return
+	}
+
+	switch rec.Protocol {
+	case ProtocolTCP:
+		if len(transport) >= 14 {
+			rec.TCPFlags = transport[13]
+		}
+		fallthrough
+	case ProtocolUDP:
+		if len(transport) >= 4 {
+			rec.SrcPort = binary.BigEndian.Uint16(transport)
+			rec.DstPort = binary.BigEndian.Uint16(transport[2:])
+		}
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/flow"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+)
+
+const (
+	netFlowV9HeaderLength = 20
+	ipfixHeaderLength     = 16
+
+	// The IDs of the sets, or flowsets, of templates. The IDs from 256 are those of data sets.
+	netFlowV9TemplateSetID        = 0
+	netFlowV9OptionsTemplateSetID = 1
+	ipfixTemplateSetID            = 2
+	ipfixOptionsTemplateSetID     = 3
+	minDataSetID                  = 256
+
+	// variableLength is the length of the IPFIX fields whose length is encoded in the data records.
+	variableLength = 65535
+	// enterpriseBit marks the IPFIX fields followed by an enterprise number.
+	enterpriseBit = 0x8000
+)
+
+// The information elements of the flow records, their IDs are the same in NetFlow v9 and IPFIX.
+// See https://www.iana.org/assignments/ipfix/ipfix.xhtml.
+const (
+	ieOctetDeltaCount            = 1
+	iePacketDeltaCount           = 2
+	ieProtocolIdentifier         = 4
+	ieIPClassOfService           = 5
+	ieTCPControlBits             = 6
+	ieSourceTransportPort        = 7
+	ieSourceIPv4Address          = 8
+	ieIngressInterface           = 10
+	ieDestinationTransportPort   = 11
+	ieDestinationIPv4Address     = 12
+	ieEgressInterface            = 14
+	ieIPNextHopIPv4Address       = 15
+	ieBGPSourceASNumber          = 16
+	ieBGPDestinationASNumber     = 17
+	ieFlowEndSysUpTime           = 21
+	ieFlowStartSysUpTime         = 22
+	ieSourceIPv6Address          = 27
+	ieDestinationIPv6Address     = 28
+	ieSamplingInterval           = 34
+	ieIPNextHopIPv6Address       = 62
+	ieOctetTotalCount            = 85
+	iePacketTotalCount           = 86
+	ieFlowStartSeconds           = 150
+	ieFlowEndSeconds             = 151
+	ieFlowStartMilliseconds      = 152
+	ieFlowEndMilliseconds        = 153
+	ieSystemInitTimeMilliseconds = 160
+	ieSamplingPacketInterval     = 305
+)
+
+// templateKey identifies a template, the IDs of the templates are only unique per exporter and
+// observation domain.
+type templateKey struct {
+	exporter netip.Addr
+	version  uint16
+	domain   uint32
+	id       uint16
+}
+
+type templateField struct {
+	id         uint16
+	enterprise uint32
+	length     uint16
+}
+
+type template struct {
+	fields []templateField
+	// options is true for the options templates, whose data records describe the exporter
+	// rather than flows.
+	options bool
+}
+
+// minRecordLength returns the length of the shortest data record of the template, the remaining
+// bytes of a data set shorter than that are padding.
+func (t *template) minRecordLength() int {
+	n := 0
+	for _, f := range t.fields {
+		if f.length == variableLength {
+			n++
+			continue
+		}
+		n += int(f.length)
+	}
+	return n
+}
+
+// messageHeader holds the fields of the NetFlow v9 and IPFIX headers needed to decode their sets.
+type messageHeader struct {
+	version    uint16
+	typ        string
+	exporter   netip.Addr
+	domain     uint32
+	exportTime time.Time
+	// sysUptime is the uptime of the exporter in milliseconds at the export time, only NetFlow v9
+	// headers have it.
+	sysUptime    uint32
+	hasSysUptime bool
+}
+
+func (h *messageHeader) key(id uint16) templateKey {
+	return templateKey{exporter: h.exporter, version: h.version, domain: h.domain, id: id}
+}
+
+// decodeNetFlowV9 decodes a NetFlow v9 datagram.
+// See https://www.rfc-editor.org/rfc/rfc3954.
+func (d *Decoder) decodeNetFlowV9(exporter netip.Addr, data []byte) ([]Record, error) {
+	if len(data) < netFlowV9HeaderLength {
+		return nil, errTruncated
+	}
+	r := &reader{data: data}
+	r.uint16() // version
+	r.uint16() // count
+	h := &messageHeader{
+		version:      9,
+		typ:          TypeNetFlowV9,
+		exporter:     exporter,
+		sysUptime:    r.uint32(),
+		hasSysUptime: true,
+	}
+	h.exportTime = time.Unix(int64(r.uint32()), 0)
+	r.uint32() // sequence
+	h.domain = r.uint32()
+	return d.decodeSets(h, r.data)
+}
+
+// decodeIPFIX decodes an IPFIX message.
+// See https://www.rfc-editor.org/rfc/rfc7011.
+func (d *Decoder) decodeIPFIX(exporter netip.Addr, data []byte) ([]Record, error) {
+	if len(data) < ipfixHeaderLength {
+		return nil, errTruncated
+	}
+	r := &reader{data: data}
+	r.uint16() // version
+	length := int(r.uint16())
+	if length < ipfixHeaderLength || length > len(data) {
+		return nil, fmt.Errorf("IPFIX message length %d doesn't match the datagram length %d", length, len(data))
+	}
+	h := &messageHeader{
+		version:  10,
+		typ:      TypeIPFIX,
+		exporter: exporter,
+	}
+	h.exportTime = time.Unix(int64(r.uint32()), 0)
+	r.uint32() // sequence
+	h.domain = r.uint32()
+	return d.decodeSets(h, data[ipfixHeaderLength:length])
+}
+
+// decodeSets decodes the template and data sets following the header. The sets are decoded
+// independently, the records of the valid data sets are returned with the errors of the others.
+func (d *Decoder) decodeSets(h *messageHeader, data []byte) ([]Record, error) {
+	var records []Record
+	var errs error
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return records, errors.Join(errs, errTruncated)
+		}
+		id := binary.BigEndian.Uint16(data)
+		length := int(binary.BigEndian.Uint16(data[2:]))
+		if length < 4 || length > len(data) {
+			return records, errors.Join(errs, fmt.Errorf("set %d: %w", id, errTruncated))
+		}
+		body := data[4:length]
+		data = data[length:]
+
+		var err error
+		switch {
+		case h.version == 9 && id == netFlowV9TemplateSetID, h.version == 10 && id == ipfixTemplateSetID:
+			err = d.decodeTemplateSet(h, body, false)
+		case h.version == 9 && id == netFlowV9OptionsTemplateSetID:
+			err = d.decodeNetFlowV9OptionsTemplateSet(h, body)
+		case h.version == 10 && id == ipfixOptionsTemplateSetID:
+			err = d.decodeTemplateSet(h, body, true)
+		case id >= minDataSetID:
+			var setRecords []Record
+			setRecords, err = d.decodeDataSet(h, id, body)
+			records = append(records, setRecords...)
+		}
+		// The other sets are reserved, they are ignored.
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("set %d: %w", id, err))
+		}
+	}
+	return records, errs
+}
+
+// decodeTemplateSet decodes a set of templates, or of IPFIX options templates.
+func (d *Decoder) decodeTemplateSet(h *messageHeader, body []byte, options bool) error {
+	r := &reader{data: body}
+	// The set may be padded with less bytes than the shortest template.
+	for len(r.data) >= 4 {
+		id := r.uint16()
+		count := int(r.uint16())
+		if count == 0 {
+			// Templates without fields withdraw the template of the same ID.
+			d.setTemplate(h.key(id), nil)
+			continue
+		}
+		if options {
+			r.uint16() // scope field count
+		}
+		t := &template{fields: make([]templateField, 0, count), options: options}
+		for i := 0; i < count; i++ {
+			f := templateField{id: r.uint16(), length: r.uint16()}
+			if h.version == 10 && f.id&enterpriseBit != 0 {
+				f.id &^= enterpriseBit
+				f.enterprise = r.uint32()
+			}
+			t.fields = append(t.fields, f)
+		}
+		if r.err != nil {
+			return fmt.Errorf("template %d: %w", id, r.err)
+		}
+		if id < minDataSetID {
+			return fmt.Errorf("invalid template ID %d", id)
+		}
+		d.setTemplate(h.key(id), t)
+	}
+	return nil
+}
+
+// decodeNetFlowV9OptionsTemplateSet decodes a set of NetFlow v9 options templates, whose scope and
+// option fields are given by their length in bytes.
+func (d *Decoder) decodeNetFlowV9OptionsTemplateSet(h *messageHeader, body []byte) error {
+	r := &reader{data: body}
+	for len(r.data) >= 6 {
+		id := r.uint16()
+		scopeLength := int(r.uint16())
+		optionLength := int(r.uint16())
+		count := (scopeLength + optionLength) / 4
+		if count == 0 {
+			// The remaining bytes are padding.
+			return nil
+		}
+		t := &template{fields: make([]templateField, 0, count), options: true}
+		for i := 0; i < count; i++ {
+			t.fields = append(t.fields, templateField{id: r.uint16(), length: r.uint16()})
+		}
+		if r.err != nil {
+			return fmt.Errorf("options template %d: %w", id, r.err)
+		}
+		if id < minDataSetID {
+			return fmt.Errorf("invalid template ID %d", id)
+		}
+		d.setTemplate(h.key(id), t)
+	}
+	return nil
+}
+
+// decodeDataSet decodes the data records of a set with the template of the same ID.
+func (d *Decoder) decodeDataSet(h *messageHeader, id uint16, body []byte) ([]Record, error) {
+	t := d.template(h.key(id))
+	if t == nil {
+		return nil, fmt.Errorf("unknown template %d of exporter %s and observation domain %d", id, h.exporter, h.domain)
+	}
+	if t.options {
+		return nil, nil
+	}
+	minLength := t.minRecordLength()
+	if minLength == 0 {
+		return nil, fmt.Errorf("template %d has no data", id)
+	}
+
+	var records []Record
+	r := &reader{data: body}
+	for len(r.data) >= minLength {
+		rec := Record{
+			Type:                h.typ,
+			Exporter:            h.exporter,
+			ObservationDomainID: h.domain,
+		}
+		var fields recordFields
+		for _, f := range t.fields {
+			length := int(f.length)
+			if f.length == variableLength {
+				length = int(r.uint8())
+				if length == 255 {
+					length = int(r.uint16())
+				}
+			}
+			value := r.bytes(length)
+			if r.err != nil {
+				return records, fmt.Errorf("data record of template %d: %w", id, r.err)
+			}
+			if f.enterprise == 0 {
+				fields.set(&rec, f.id, value)
+			}
+		}
+		fields.finish(h, &rec)
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// recordFields holds the fields of a data record that are only set once all of them are decoded.
+type recordFields struct {
+	octetTotalCount  uint64
+	packetTotalCount uint64
+
+	startUptime    uint32
+	endUptime      uint32
+	hasStartUptime bool
+	hasEndUptime   bool
+	systemInitTime time.Time
+}
+
+func (fs *recordFields) set(rec *Record, id uint16, value []byte) {
+	switch id {
+	case ieOctetDeltaCount:
+		rec.Bytes = uintValue(value)
+	case iePacketDeltaCount:
+		rec.Packets = uintValue(value)
+	case ieOctetTotalCount:
+		fs.octetTotalCount = uintValue(value)
+	case iePacketTotalCount:
+		fs.packetTotalCount = uintValue(value)
+	case ieProtocolIdentifier:
+		rec.Protocol = uint8(uintValue(value))
+	case ieIPClassOfService:
+		rec.ToS = uint8(uintValue(value))
+	case ieTCPControlBits:
+		// The TCP control bits are 16 bits in IPFIX, the flags of NetFlow are the lower 8 bits.
+		rec.TCPFlags = uint8(uintValue(value))
+	case ieSourceTransportPort:
+		rec.SrcPort = uint16(uintValue(value))
+	case ieDestinationTransportPort:
+		rec.DstPort = uint16(uintValue(value))
+	case ieSourceIPv4Address, ieSourceIPv6Address:
+		rec.SrcAddr = addrValue(value)
+	case ieDestinationIPv4Address, ieDestinationIPv6Address:
+		rec.DstAddr = addrValue(value)
+	case ieIPNextHopIPv4Address, ieIPNextHopIPv6Address:
+		rec.NextHop = addrValue(value)
+	case ieIngressInterface:
+		rec.InputInterface = uint32(uintValue(value))
+	case ieEgressInterface:
+		rec.OutputInterface = uint32(uintValue(value))
+	case ieBGPSourceASNumber:
+		rec.SrcAS = uint32(uintValue(value))
+	case ieBGPDestinationASNumber:
+		rec.DstAS = uint32(uintValue(value))
+	case ieSamplingInterval, ieSamplingPacketInterval:
+		rec.SamplingRate = uint32(uintValue(value))
+	case ieFlowStartSysUpTime:
+		fs.startUptime, fs.hasStartUptime = uint32(uintValue(value)), true
+	case ieFlowEndSysUpTime:
+		fs.endUptime, fs.hasEndUptime = uint32(uintValue(value)), true
+	case ieSystemInitTimeMilliseconds:
+		fs.systemInitTime = time.UnixMilli(int64(uintValue(value)))
+	case ieFlowStartSeconds:
+		rec.Start = time.Unix(int64(uintValue(value)), 0)
+	case ieFlowEndSeconds:
+		rec.End = time.Unix(int64(uintValue(value)), 0)
+	case ieFlowStartMilliseconds:
+		rec.Start = time.UnixMilli(int64(uintValue(value)))
+	case ieFlowEndMilliseconds:
+		rec.End = time.UnixMilli(int64(uintValue(value)))
+	}
+}
+
+// finish sets the fields of the record depending on several fields or on the header.
+func (fs *recordFields) finish(h *messageHeader, rec *Record) {
+	if rec.Bytes == 0 {
+		rec.Bytes = fs.octetTotalCount
+	}
+	if rec.Packets == 0 {
+		rec.Packets = fs.packetTotalCount
+	}
+	// The uptimes are relative to the uptime of the NetFlow v9 header, or to the
+	// system init time of the IPFIX records.
+	uptime := func(ms uint32) time.Time {
+		if h.hasSysUptime {
+			return uptimeTime(h.exportTime, h.sysUptime, ms)
+		}
+		return fs.systemInitTime.Add(time.Duration(ms) * time.Millisecond)
+	}
+	hasBase := h.hasSysUptime || !fs.systemInitTime.IsZero()
+	if fs.hasStartUptime && hasBase && rec.Start.IsZero() {
+		rec.Start = uptime(fs.startUptime)
+	}
+	if fs.hasEndUptime && hasBase && rec.End.IsZero() {
+		rec.End = uptime(fs.endUptime)
+	}
+}
+
+// uintValue decodes the unsigned integers, which may be encoded with less bytes than their type.
+func uintValue(value []byte) uint64 {
+	var v uint64
+	for _, b := range value {
+		v = v<<8 | uint64(b)
+	}
+	return v
+}
+
+func addrValue(value []byte) netip.Addr {
+	addr, _ := netip.AddrFromSlice(value)
+	return addr
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/flow"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/metadata"
+)
+
+// The attributes of the flow logs, the network ones follow the semantic conventions.
+const (
+	attributeFlowType            = "flow.type"
+	attributeExporterAddress     = "flow.exporter.address"
+	attributeObservationDomainID = "flow.observation_domain_id"
+	attributeNetworkType         = "network.type"
+	attributeNetworkTransport    = "network.transport"
+	attributeProtocol            = "flow.protocol"
+	attributeSourceAddress       = "source.address"
+	attributeSourcePort          = "source.port"
+	attributeDestinationAddress  = "destination.address"
+	attributeDestinationPort     = "destination.port"
+	attributeNextHopAddress      = "flow.next_hop.address"
+	attributeInputInterface      = "flow.input_interface"
+	attributeOutputInterface     = "flow.output_interface"
+	attributeSourceAS            = "flow.source_as"
+	attributeDestinationAS       = "flow.destination_as"
+	attributeToS                 = "flow.tos"
+	attributeTCPFlags            = "flow.tcp_flags"
+	attributeBytes               = "flow.bytes"
+	attributePackets             = "flow.packets"
+	attributeSamplingRate        = "flow.sampling_rate"
+	attributeStart               = "flow.start"
+)
+
+// aggregationAttributes are the attributes the flows can be aggregated by, the counters and
+// times of the flows are excluded.
+var aggregationAttributes = map[string]bool{
+	attributeFlowType:            true,
+	attributeExporterAddress:     true,
+	attributeObservationDomainID: true,
+	attributeNetworkType:         true,
+	attributeNetworkTransport:    true,
+	attributeProtocol:            true,
+	attributeSourceAddress:       true,
+	attributeSourcePort:          true,
+	attributeDestinationAddress:  true,
+	attributeDestinationPort:     true,
+	attributeNextHopAddress:      true,
+	attributeInputInterface:      true,
+	attributeOutputInterface:     true,
+	attributeSourceAS:            true,
+	attributeDestinationAS:       true,
+	attributeToS:                 true,
+}
+
+// recordsToLogs converts the flow records of a datagram received at the given time to logs,
+// the time of the logs is the end of their flow if known.
+func recordsToLogs(records []flow.Record, received time.Time) plog.Logs {
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(metadata.ScopeName)
+	lrs := sl.LogRecords()
+	lrs.EnsureCapacity(len(records))
+	observed := pcommon.NewTimestampFromTime(received)
+	for i := range records {
+		lr := lrs.AppendEmpty()
+		lr.SetObservedTimestamp(observed)
+		if !records[i].End.IsZero() {
+			lr.SetTimestamp(pcommon.NewTimestampFromTime(records[i].End))
+		}
+		putRecordAttributes(&records[i], lr.Attributes())
+	}
+	return ld
+}
+
+// putRecordAttributes sets the attributes of a flow record, the fields that aren't set are omitted.
+func putRecordAttributes(rec *flow.Record, attrs pcommon.Map) {
+	attrs.EnsureCapacity(20)
+	attrs.PutStr(attributeFlowType, rec.Type)
+	if rec.Exporter.IsValid() {
+		attrs.PutStr(attributeExporterAddress, rec.Exporter.Unmap().String())
+	}
+	if rec.Type == flow.TypeNetFlowV9 || rec.Type == flow.TypeIPFIX {
+		attrs.PutInt(attributeObservationDomainID, int64(rec.ObservationDomainID))
+	}
+
+	switch {
+	case rec.SrcAddr.Is4():
+		attrs.PutStr(attributeNetworkType, "ipv4")
+	case rec.SrcAddr.Is6():
+		attrs.PutStr(attributeNetworkType, "ipv6")
+	}
+	attrs.PutInt(attributeProtocol, int64(rec.Protocol))
+	hasPorts := false
+	switch rec.Protocol {
+	case flow.ProtocolTCP:
+		attrs.PutStr(attributeNetworkTransport, "tcp")
+		attrs.PutInt(attributeTCPFlags, int64(rec.TCPFlags))
+		hasPorts = true
+	case flow.ProtocolUDP:
+		attrs.PutStr(attributeNetworkTransport, "udp")
+		hasPorts = true
+	}
+	if rec.SrcAddr.IsValid() {
+		attrs.PutStr(attributeSourceAddress, rec.SrcAddr.String())
+	}
+	if rec.DstAddr.IsValid() {
+		attrs.PutStr(attributeDestinationAddress, rec.DstAddr.String())
+	}
+	if hasPorts {
+		attrs.PutInt(attributeSourcePort, int64(rec.SrcPort))
+		attrs.PutInt(attributeDestinationPort, int64(rec.DstPort))
+	}
+	if rec.NextHop.IsValid() && !rec.NextHop.IsUnspecified() {
+		attrs.PutStr(attributeNextHopAddress, rec.NextHop.String())
+	}
+
+	putNonZero(attrs, attributeInputInterface, rec.InputInterface)
+	putNonZero(attrs, attributeOutputInterface, rec.OutputInterface)
+	putNonZero(attrs, attributeSourceAS, rec.SrcAS)
+	putNonZero(attrs, attributeDestinationAS, rec.DstAS)
+	attrs.PutInt(attributeToS, int64(rec.ToS))
+	attrs.PutInt(attributeBytes, int64(rec.Bytes))
+	attrs.PutInt(attributePackets, int64(rec.Packets))
+	putNonZero(attrs, attributeSamplingRate, rec.SamplingRate)
+	if !rec.Start.IsZero() {
+		attrs.PutStr(attributeStart, rec.Start.UTC().Format(time.RFC3339Nano))
+	}
+}
+
+func putNonZero(attrs pcommon.Map, key string, value uint32) {
+	if value != 0 {
+		attrs.PutInt(key, int64(value))
+	}
+}
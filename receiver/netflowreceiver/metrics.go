@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/flow"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/metadata"
+)
+
+const (
+	metricBytes   = "network.flow.bytes"
+	metricPackets = "network.flow.packets"
+	metricFlows   = "network.flow.count"
+)
+
+// aggregator sums the bytes, packets and number of the flows with the same attributes.
+// The sampled flows are scaled by their sampling rate, so the metrics estimate the traffic.
+type aggregator struct {
+	attributes []string
+
+	mu     sync.Mutex
+	start  time.Time
+	groups map[string]*flowGroup
+}
+
+type flowGroup struct {
+	attributes pcommon.Map
+	bytes      uint64
+	packets    uint64
+	flows      uint64
+}
+
+func newAggregator(attributes []string, start time.Time) *aggregator {
+	return &aggregator{
+		attributes: attributes,
+		start:      start,
+		groups:     map[string]*flowGroup{},
+	}
+}
+
+// add adds the flow records to the groups of their attributes.
+func (a *aggregator) add(records []flow.Record) {
+	attrs := pcommon.NewMap()
+	var key strings.Builder
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := range records {
+		rec := &records[i]
+		attrs.Clear()
+		putRecordAttributes(rec, attrs)
+
+		key.Reset()
+		for _, name := range a.attributes {
+			// The missing attributes are told apart from the empty ones.
+			if v, ok := attrs.Get(name); ok {
+				key.WriteString(v.AsString())
+				key.WriteByte(0)
+			} else {
+				key.WriteByte(1)
+			}
+		}
+		g, ok := a.groups[key.String()]
+		if !ok {
+			g = &flowGroup{attributes: pcommon.NewMap()}
+			for _, name := range a.attributes {
+				if v, ok := attrs.Get(name); ok {
+					v.CopyTo(g.attributes.PutEmpty(name))
+				}
+			}
+			a.groups[key.String()] = g
+		}
+
+		rate := uint64(max(rec.SamplingRate, 1))
+		g.bytes += rec.Bytes * rate
+		g.packets += rec.Packets * rate
+		g.flows++
+	}
+}
+
+// flush returns the delta metrics of the flows added since the previous flush, it returns empty
+// metrics if no flow was added.
+func (a *aggregator) flush(now time.Time) pmetric.Metrics {
+	a.mu.Lock()
+	groups, start := a.groups, a.start
+	a.groups, a.start = map[string]*flowGroup{}, now
+	a.mu.Unlock()
+
+	md := pmetric.NewMetrics()
+	if len(groups) == 0 {
+		return md
+	}
+	ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	ms.Scope().SetName(metadata.ScopeName)
+	bytes := newDeltaSum(ms.Metrics(), metricBytes, "Bytes of the flows, scaled by their sampling rate.", "By")
+	packets := newDeltaSum(ms.Metrics(), metricPackets, "Packets of the flows, scaled by their sampling rate.", "{packet}")
+	flows := newDeltaSum(ms.Metrics(), metricFlows, "Number of flow records.", "{flow}")
+	startTimestamp, timestamp := pcommon.NewTimestampFromTime(start), pcommon.NewTimestampFromTime(now)
+	for _, g := range groups {
+		for _, dp := range []struct {
+			sum   pmetric.Sum
+			value uint64
+		}{{bytes, g.bytes}, {packets, g.packets}, {flows, g.flows}} {
+			p := dp.sum.DataPoints().AppendEmpty()
+			p.SetStartTimestamp(startTimestamp)
+			p.SetTimestamp(timestamp)
+			p.SetIntValue(int64(dp.value))
+			g.attributes.CopyTo(p.Attributes())
+		}
+	}
+	return md
+}
+
+func newDeltaSum(metrics pmetric.MetricSlice, name, description, unit string) pmetric.Sum {
+	m := metrics.AppendEmpty()
+	m.SetName(name)
+	m.SetDescription(description)
+	m.SetUnit(unit)
+	sum := m.SetEmptySum()
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	sum.SetIsMonotonic(true)
+	return sum
+}
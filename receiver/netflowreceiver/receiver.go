@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/flow"
+)
+
+const (
+	// maxDatagramSize is the maximum size of the payload of a UDP datagram.
+	maxDatagramSize = 65535
+	obsreportFormat = "netflow"
+)
+
+// netflowReceiver receives the flow datagrams on a UDP socket. The flows are emitted as logs if the
+// receiver is in a logs pipeline, and aggregated into metrics if it is in a metrics pipeline.
+type netflowReceiver struct {
+	settings receiver.Settings
+	config   *Config
+	decoder  *flow.Decoder
+
+	nextLogs    consumer.Logs
+	nextMetrics consumer.Metrics
+
+	obsrecv    *receiverhelper.ObsReport
+	aggregator *aggregator
+	conn       net.PacketConn
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func newNetFlowReceiver(set receiver.Settings, cfg *Config) *netflowReceiver {
+	return &netflowReceiver{
+		settings: set,
+		config:   cfg,
+		decoder:  flow.NewDecoder(),
+	}
+}
+
+func (r *netflowReceiver) registerLogsConsumer(next consumer.Logs) {
+	r.nextLogs = next
+}
+
+func (r *netflowReceiver) registerMetricsConsumer(next consumer.Metrics) {
+	r.nextMetrics = next
+}
+
+// Start listens on the endpoint and starts the goroutines reading the datagrams.
+func (r *netflowReceiver) Start(_ context.Context, host component.Host) error {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             r.settings.ID,
+		Transport:              "udp",
+		ReceiverCreateSettings: r.settings,
+	})
+	if err != nil {
+		return err
+	}
+	r.obsrecv = obsrecv
+
+	conn, err := net.ListenPacket("udp", r.config.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", r.config.Endpoint, err)
+	}
+	if udpConn, ok := conn.(*net.UDPConn); ok && r.config.ReadBufferSize > 0 {
+		if err = udpConn.SetReadBuffer(r.config.ReadBufferSize); err != nil {
+			_ = conn.Close()
+			return fmt.Errorf("failed to set the read buffer size: %w", err)
+		}
+	}
+	r.conn = conn
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	if r.nextMetrics != nil {
+		r.aggregator = newAggregator(r.config.Aggregation.Attributes, time.Now())
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.aggregate(ctx)
+		}()
+	}
+	for i := 0; i < r.config.Workers; i++ {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.serve(host)
+		}()
+	}
+	return nil
+}
+
+// Shutdown closes the socket and emits the metrics of the flows aggregated since the last interval.
+func (r *netflowReceiver) Shutdown(context.Context) error {
+	if r.conn == nil {
+		return nil
+	}
+	err := r.conn.Close()
+	r.cancel()
+	r.wg.Wait()
+	return err
+}
+
+// serve reads and handles the datagrams until the socket is closed.
+func (r *netflowReceiver) serve(host component.Host) {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, addr, err := r.conn.ReadFrom(buf)
+		if n > 0 {
+			r.handleDatagram(addr, buf[:n])
+		}
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(err))
+			return
+		}
+	}
+}
+
+func (r *netflowReceiver) handleDatagram(addr net.Addr, data []byte) {
+	received := time.Now()
+	var exporter netip.Addr
+	if udpAddr, ok := addr.(*net.UDPAddr); ok {
+		exporter = udpAddr.AddrPort().Addr().Unmap()
+	}
+
+	// The data records received before their template are dropped, the other records of the
+	// datagram are still emitted.
+	records, err := r.decoder.Decode(exporter, data)
+	if err != nil {
+		r.settings.Logger.Debug("Failed to decode flow datagram", zap.Stringer("exporter", exporter), zap.Error(err))
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	if r.aggregator != nil {
+		r.aggregator.add(records)
+	}
+	if r.nextLogs != nil {
+		ctx := r.obsrecv.StartLogsOp(context.Background())
+		err = r.nextLogs.ConsumeLogs(ctx, recordsToLogs(records, received))
+		r.obsrecv.EndLogsOp(ctx, obsreportFormat, len(records), err)
+	}
+}
+
+// aggregate emits the aggregated metrics at each interval, and once more when ctx is done.
+func (r *netflowReceiver) aggregate(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Aggregation.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			r.emitMetrics(now)
+		case <-ctx.Done():
+			r.emitMetrics(time.Now())
+			return
+		}
+	}
+}
+
+func (r *netflowReceiver) emitMetrics(now time.Time) {
+	md := r.aggregator.flush(now)
+	if md.DataPointCount() == 0 {
+		return
+	}
+	ctx := r.obsrecv.StartMetricsOp(context.Background())
+	err := r.nextMetrics.ConsumeMetrics(ctx, md)
+	r.obsrecv.EndMetricsOp(ctx, obsreportFormat, md.DataPointCount(), err)
+}
@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func newTestReceiver(t *testing.T, logsSink *consumertest.LogsSink, metricsSink *consumertest.MetricsSink) (*netflowReceiver, net.Conn) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "127.0.0.1:0"
+	cfg.Aggregation.Interval = time.Hour
+	r := newNetFlowReceiver(receivertest.NewNopSettings(), cfg)
+	if logsSink != nil {
+		r.registerLogsConsumer(logsSink)
+	}
+	if metricsSink != nil {
+		r.registerMetricsConsumer(metricsSink)
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	})
+
+	conn, err := net.Dial("udp", r.conn.LocalAddr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+	return r, conn
+}
+
+// replay sends the captured datagrams of the testdata directory in order.
+func replay(t *testing.T, conn net.Conn, names ...string) {
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		_, err = conn.Write(data)
+		require.NoError(t, err)
+	}
+}
+
+func TestReceiverLogs(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	_, conn := newTestReceiver(t, sink, nil)
+
+	replay(t, conn, "netflow_v5.bin", "netflow_v9.bin", "netflow_v9_data.bin", "ipfix.bin", "ipfix_data.bin", "sflow_v5.bin")
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 10
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ld := sink.AllLogs()[0]
+	require.Equal(t, 2, ld.LogRecordCount())
+	lr := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, time.Unix(1699999990, 0).UTC(), lr.Timestamp().AsTime())
+	assert.NotZero(t, lr.ObservedTimestamp())
+	assert.Equal(t, map[string]any{
+		"flow.type":             "netflow_v5",
+		"flow.exporter.address": "127.0.0.1",
+		"network.type":          "ipv4",
+		"network.transport":     "tcp",
+		"flow.protocol":         int64(6),
+		"flow.tcp_flags":        int64(0x1b),
+		"source.address":        "10.0.0.1",
+		"source.port":           int64(49152),
+		"destination.address":   "10.0.0.2",
+		"destination.port":      int64(443),
+		"flow.next_hop.address": "10.0.0.254",
+		"flow.input_interface":  int64(1),
+		"flow.output_interface": int64(2),
+		"flow.source_as":        int64(65001),
+		"flow.destination_as":   int64(65002),
+		"flow.tos":              int64(0),
+		"flow.bytes":            int64(1500),
+		"flow.packets":          int64(10),
+		"flow.sampling_rate":    int64(100),
+		"flow.start":            "2023-11-14T22:12:20Z",
+	}, lr.Attributes().AsRaw())
+
+	// The flows of IPFIX have an observation domain, the exporter of sFlow is its agent.
+	ipfix := sink.AllLogs()[3].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, map[string]any{
+		"flow.type":                  "ipfix",
+		"flow.exporter.address":      "127.0.0.1",
+		"flow.observation_domain_id": int64(42),
+		"network.type":               "ipv6",
+		"network.transport":          "tcp",
+		"flow.protocol":              int64(6),
+		"flow.tcp_flags":             int64(0),
+		"source.address":             "2001:db8::1",
+		"source.port":                int64(50000),
+		"destination.address":        "2001:db8::2",
+		"destination.port":           int64(443),
+		"flow.tos":                   int64(0),
+		"flow.bytes":                 int64(123456),
+		"flow.packets":               int64(100),
+		"flow.start":                 "2023-11-14T22:13:10Z",
+	}, ipfix.Attributes().AsRaw())
+	sflow := sink.AllLogs()[5].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(1)
+	assert.Zero(t, sflow.Timestamp())
+	exporter, _ := sflow.Attributes().Get("flow.exporter.address")
+	assert.Equal(t, "192.0.2.1", exporter.Str())
+}
+
+func TestReceiverMetrics(t *testing.T) {
+	logsSink := new(consumertest.LogsSink)
+	metricsSink := new(consumertest.MetricsSink)
+	r, conn := newTestReceiver(t, logsSink, metricsSink)
+
+	replay(t, conn, "netflow_v5.bin", "netflow_v5.bin", "sflow_v5.bin")
+	require.Eventually(t, func() bool {
+		return logsSink.LogRecordCount() == 6
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, metricsSink.AllMetrics())
+
+	// The aggregated metrics are emitted at each interval and on shutdown.
+	r.emitMetrics(time.Now())
+	require.Len(t, metricsSink.AllMetrics(), 1)
+	md := metricsSink.AllMetrics()[0]
+	assert.Equal(t, 3, md.MetricCount())
+
+	type group struct {
+		bytes, packets, flows int64
+	}
+	groups := map[string]group{}
+	ms := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		assert.Equal(t, pmetric.AggregationTemporalityDelta, m.Sum().AggregationTemporality())
+		for j := 0; j < m.Sum().DataPoints().Len(); j++ {
+			dp := m.Sum().DataPoints().At(j)
+			src, _ := dp.Attributes().Get("source.address")
+			exporter, _ := dp.Attributes().Get("flow.exporter.address")
+			key := exporter.Str() + " " + src.Str()
+			g := groups[key]
+			switch m.Name() {
+			case "network.flow.bytes":
+				g.bytes = dp.IntValue()
+			case "network.flow.packets":
+				g.packets = dp.IntValue()
+			case "network.flow.count":
+				g.flows = dp.IntValue()
+			}
+			groups[key] = g
+		}
+	}
+	// The bytes and packets are scaled by the sampling rate of the flows.
+	assert.Equal(t, map[string]group{
+		"127.0.0.1 10.0.0.1":     {bytes: 2 * 1500 * 100, packets: 2 * 10 * 100, flows: 2},
+		"127.0.0.1 10.0.0.3":     {bytes: 2 * 80 * 100, packets: 2 * 100, flows: 2},
+		"192.0.2.1 10.1.1.1":     {bytes: 1518 * 1000, packets: 1000, flows: 1},
+		"192.0.2.1 2001:db8::10": {bytes: 100 * 512, packets: 512, flows: 1},
+	}, groups)
+
+	// The aggregation restarts after each interval.
+	r.emitMetrics(time.Now())
+	assert.Len(t, metricsSink.AllMetrics(), 1)
+}
+
+func TestReceiverUnknownTemplate(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	r, conn := newTestReceiver(t, sink, nil)
+
+	// The data records received before their template are dropped.
+	replay(t, conn, "netflow_v9_data.bin", "netflow_v9.bin")
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, r.decoder.TemplateCount())
+}
@@ -5,22 +5,34 @@ package otlpjsonfilereceiver // import "github.com/open-telemetry/opentelemetry-
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/receiverprofiles"
+	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/otlpjsonfilereceiver/internal/metadata"
 )
 
 const (
 	transport = "file"
+
+	// profilesStability is declared here as metadata.yaml doesn't support the profiles signal yet.
+	profilesStability = component.StabilityLevelDevelopment
 )
 
 // NewFactory creates a factory for file receiver
@@ -30,28 +42,89 @@ func NewFactory() receiver.Factory {
 		createDefaultConfig,
 		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
 		receiver.WithLogs(createLogsReceiver, metadata.LogsStability),
-		receiver.WithTraces(createTracesReceiver, metadata.TracesStability))
+		receiver.WithTraces(createTracesReceiver, metadata.TracesStability),
+		receiverprofiles.WithProfiles(createProfilesReceiver, profilesStability))
 }
 
 type Config struct {
 	fileconsumer.Config `mapstructure:",squash"`
 	StorageID           *component.ID `mapstructure:"storage"`
 	ReplayFile          bool          `mapstructure:"replay_file"`
+
+	// FormatType is the format the files were written in by the file exporter.
+	// Options:
+	// - json[default]: OTLP json bytes.
+	// - proto: OTLP binary protobuf bytes.
+	FormatType string `mapstructure:"format"`
+
+	// MessageCompression is the codec used by the file exporter to compress every message.
+	// Options:
+	// - zstd
+	MessageCompression string `mapstructure:"message_compression"`
+
+	// EncodingExtension defines the encoding of the telemetry data.
+	// If specified, it overrides `FormatType` and applies an encoding extension.
+	EncodingExtension *component.ID `mapstructure:"encoding_extension"`
+}
+
+var _ component.ConfigValidator = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.FormatType != formatTypeJSON && cfg.FormatType != formatTypeProto {
+		return errors.New("format type is not supported")
+	}
+	if cfg.MessageCompression != "" && cfg.MessageCompression != compressionZSTD {
+		return errors.New("message_compression is not supported")
+	}
+	return nil
 }
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		Config: *fileconsumer.NewConfig(),
+		Config:     *fileconsumer.NewConfig(),
+		FormatType: formatTypeJSON,
 	}
 }
 
+// buildInput builds the file consumer reading the messages framed as written by the file exporter.
+func (cfg *Config) buildInput(settings component.TelemetrySettings, callback emit.Callback) (*fileconsumer.Manager, error) {
+	opts := make([]fileconsumer.Option, 0)
+	if cfg.ReplayFile {
+		opts = append(opts, fileconsumer.WithNoTracking())
+	}
+	consumerCfg := cfg.Config
+	if cfg.isLengthPrefixed() {
+		// Binary messages must be passed on untouched and can't be flushed before they were read completely.
+		consumerCfg.Encoding = "nop"
+		consumerCfg.FlushPeriod = 0
+		splitFunc, err := split.LengthPrefixedSplitFunc(frameHeaderSize, binary.BigEndian, int(cfg.MaxLogSize))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, fileconsumer.WithSplitFunc(splitFunc))
+	}
+	return consumerCfg.Build(settings, callback, opts...)
+}
+
 type otlpjsonfilereceiver struct {
-	input     *fileconsumer.Manager
-	id        component.ID
-	storageID *component.ID
+	input       *fileconsumer.Manager
+	id          component.ID
+	storageID   *component.ID
+	encodingID  *component.ID
+	setEncoding func(component.Component) error
 }
 
 func (f *otlpjsonfilereceiver) Start(ctx context.Context, host component.Host) error {
+	if f.encodingID != nil {
+		encoding, ok := host.GetExtensions()[*f.encodingID]
+		if !ok {
+			return fmt.Errorf("unknown encoding %q", f.encodingID)
+		}
+		if err := f.setEncoding(encoding); err != nil {
+			return err
+		}
+	}
 	storageClient, err := adapter.GetStorageClient(ctx, host, f.storageID, f.id)
 	if err != nil {
 		return err
@@ -64,7 +137,6 @@ func (f *otlpjsonfilereceiver) Shutdown(_ context.Context) error {
 }
 
 func createLogsReceiver(_ context.Context, settings receiver.Settings, configuration component.Config, logs consumer.Logs) (receiver.Logs, error) {
-	logsUnmarshaler := &plog.JSONUnmarshaler{}
 	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
 		ReceiverID:             settings.ID,
 		Transport:              transport,
@@ -74,14 +146,16 @@ func createLogsReceiver(_ context.Context, settings receiver.Settings, configura
 		return nil, err
 	}
 	cfg := configuration.(*Config)
-	opts := make([]fileconsumer.Option, 0)
-	if cfg.ReplayFile {
-		opts = append(opts, fileconsumer.WithNoTracking())
-	}
-	input, err := cfg.Config.Build(settings.TelemetrySettings, func(ctx context.Context, token []byte, _ map[string]any) error {
+	logsUnmarshaler := logsUnmarshalers[cfg.FormatType]
+	decompress := buildDecompressor(cfg.MessageCompression)
+	input, err := cfg.buildInput(settings.TelemetrySettings, func(ctx context.Context, token []byte, _ map[string]any) error {
 		ctx = obsrecv.StartLogsOp(ctx)
 		var l plog.Logs
-		l, err = logsUnmarshaler.UnmarshalLogs(token)
+		var buf []byte
+		buf, err = decompress(token)
+		if err == nil {
+			l, err = logsUnmarshaler.UnmarshalLogs(buf)
+		}
 		if err != nil {
 			obsrecv.EndLogsOp(ctx, metadata.Type.String(), 0, err)
 		} else {
@@ -92,16 +166,28 @@ func createLogsReceiver(_ context.Context, settings receiver.Settings, configura
 			obsrecv.EndLogsOp(ctx, metadata.Type.String(), logRecordCount, err)
 		}
 		return nil
-	}, opts...)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &otlpjsonfilereceiver{input: input, id: settings.ID, storageID: cfg.StorageID}, nil
+	return &otlpjsonfilereceiver{
+		input:      input,
+		id:         settings.ID,
+		storageID:  cfg.StorageID,
+		encodingID: cfg.EncodingExtension,
+		setEncoding: func(encoding component.Component) error {
+			unmarshaler, ok := encoding.(plog.Unmarshaler)
+			if !ok {
+				return fmt.Errorf("extension %q is not a logs unmarshaler", cfg.EncodingExtension)
+			}
+			logsUnmarshaler = unmarshaler
+			return nil
+		},
+	}, nil
 }
 
 func createMetricsReceiver(_ context.Context, settings receiver.Settings, configuration component.Config, metrics consumer.Metrics) (receiver.Metrics, error) {
-	metricsUnmarshaler := &pmetric.JSONUnmarshaler{}
 	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
 		ReceiverID:             settings.ID,
 		Transport:              transport,
@@ -111,14 +197,16 @@ func createMetricsReceiver(_ context.Context, settings receiver.Settings, config
 		return nil, err
 	}
 	cfg := configuration.(*Config)
-	opts := make([]fileconsumer.Option, 0)
-	if cfg.ReplayFile {
-		opts = append(opts, fileconsumer.WithNoTracking())
-	}
-	input, err := cfg.Config.Build(settings.TelemetrySettings, func(ctx context.Context, token []byte, _ map[string]any) error {
+	metricsUnmarshaler := metricsUnmarshalers[cfg.FormatType]
+	decompress := buildDecompressor(cfg.MessageCompression)
+	input, err := cfg.buildInput(settings.TelemetrySettings, func(ctx context.Context, token []byte, _ map[string]any) error {
 		ctx = obsrecv.StartMetricsOp(ctx)
 		var m pmetric.Metrics
-		m, err = metricsUnmarshaler.UnmarshalMetrics(token)
+		var buf []byte
+		buf, err = decompress(token)
+		if err == nil {
+			m, err = metricsUnmarshaler.UnmarshalMetrics(buf)
+		}
 		if err != nil {
 			obsrecv.EndMetricsOp(ctx, metadata.Type.String(), 0, err)
 		} else {
@@ -128,16 +216,28 @@ func createMetricsReceiver(_ context.Context, settings receiver.Settings, config
 			obsrecv.EndMetricsOp(ctx, metadata.Type.String(), m.MetricCount(), err)
 		}
 		return nil
-	}, opts...)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &otlpjsonfilereceiver{input: input, id: settings.ID, storageID: cfg.StorageID}, nil
+	return &otlpjsonfilereceiver{
+		input:      input,
+		id:         settings.ID,
+		storageID:  cfg.StorageID,
+		encodingID: cfg.EncodingExtension,
+		setEncoding: func(encoding component.Component) error {
+			unmarshaler, ok := encoding.(pmetric.Unmarshaler)
+			if !ok {
+				return fmt.Errorf("extension %q is not a metrics unmarshaler", cfg.EncodingExtension)
+			}
+			metricsUnmarshaler = unmarshaler
+			return nil
+		},
+	}, nil
 }
 
 func createTracesReceiver(_ context.Context, settings receiver.Settings, configuration component.Config, traces consumer.Traces) (receiver.Traces, error) {
-	tracesUnmarshaler := &ptrace.JSONUnmarshaler{}
 	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
 		ReceiverID:             settings.ID,
 		Transport:              transport,
@@ -147,14 +247,16 @@ func createTracesReceiver(_ context.Context, settings receiver.Settings, configu
 		return nil, err
 	}
 	cfg := configuration.(*Config)
-	opts := make([]fileconsumer.Option, 0)
-	if cfg.ReplayFile {
-		opts = append(opts, fileconsumer.WithNoTracking())
-	}
-	input, err := cfg.Config.Build(settings.TelemetrySettings, func(ctx context.Context, token []byte, _ map[string]any) error {
+	tracesUnmarshaler := tracesUnmarshalers[cfg.FormatType]
+	decompress := buildDecompressor(cfg.MessageCompression)
+	input, err := cfg.buildInput(settings.TelemetrySettings, func(ctx context.Context, token []byte, _ map[string]any) error {
 		ctx = obsrecv.StartTracesOp(ctx)
 		var t ptrace.Traces
-		t, err = tracesUnmarshaler.UnmarshalTraces(token)
+		var buf []byte
+		buf, err = decompress(token)
+		if err == nil {
+			t, err = tracesUnmarshaler.UnmarshalTraces(buf)
+		}
 		if err != nil {
 			obsrecv.EndTracesOp(ctx, metadata.Type.String(), 0, err)
 		} else {
@@ -164,10 +266,66 @@ func createTracesReceiver(_ context.Context, settings receiver.Settings, configu
 			obsrecv.EndTracesOp(ctx, metadata.Type.String(), t.SpanCount(), err)
 		}
 		return nil
-	}, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &otlpjsonfilereceiver{
+		input:      input,
+		id:         settings.ID,
+		storageID:  cfg.StorageID,
+		encodingID: cfg.EncodingExtension,
+		setEncoding: func(encoding component.Component) error {
+			unmarshaler, ok := encoding.(ptrace.Unmarshaler)
+			if !ok {
+				return fmt.Errorf("extension %q is not a traces unmarshaler", cfg.EncodingExtension)
+			}
+			tracesUnmarshaler = unmarshaler
+			return nil
+		},
+	}, nil
+}
+
+func createProfilesReceiver(_ context.Context, settings receiver.Settings, configuration component.Config, profiles consumerprofiles.Profiles) (receiverprofiles.Profiles, error) {
+	cfg := configuration.(*Config)
+	profilesUnmarshaler := profilesUnmarshalers[cfg.FormatType]
+	decompress := buildDecompressor(cfg.MessageCompression)
+	logger := settings.Logger
+	input, err := cfg.buildInput(settings.TelemetrySettings, func(ctx context.Context, token []byte, _ map[string]any) error {
+		var p pprofile.Profiles
+		buf, err := decompress(token)
+		if err == nil {
+			p, err = profilesUnmarshaler.UnmarshalProfiles(buf)
+		}
+		if err != nil {
+			// the obsreport doesn't support profiles yet, report the error in the logs instead.
+			logger.Warn("failed to unmarshal profiles", zap.Error(err))
+			return nil
+		}
+		if p.ResourceProfiles().Len() != 0 {
+			if err = profiles.ConsumeProfiles(ctx, p); err != nil {
+				logger.Warn("failed to consume profiles", zap.Error(err))
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &otlpjsonfilereceiver{input: input, id: settings.ID, storageID: cfg.StorageID}, nil
+	return &otlpjsonfilereceiver{
+		input:      input,
+		id:         settings.ID,
+		storageID:  cfg.StorageID,
+		encodingID: cfg.EncodingExtension,
+		setEncoding: func(encoding component.Component) error {
+			unmarshaler, ok := encoding.(pprofile.Unmarshaler)
+			if !ok {
+				return fmt.Errorf("extension %q is not a profiles unmarshaler", cfg.EncodingExtension)
+			}
+			profilesUnmarshaler = unmarshaler
+			return nil
+		},
+	}, nil
 }
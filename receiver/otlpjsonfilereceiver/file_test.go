@@ -5,11 +5,14 @@ package otlpjsonfilereceiver // import "github.com/open-telemetry/opentelemetry-
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -18,6 +21,8 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/pprofile/pprofileotlp"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/testdata"
 	"go.opentelemetry.io/collector/receiver/receivertest"
@@ -25,6 +30,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/otlpjsonfilereceiver/internal/metadata"
 )
 
@@ -170,11 +176,17 @@ func testdataConfigYamlAsMap() *Config {
 			MaxLogSize:         1024 * 1024,
 			MaxConcurrentFiles: 1024,
 			FlushPeriod:        500 * time.Millisecond,
+			Watch:              fileconsumer.WatchConfig{Debounce: 50 * time.Millisecond},
+			Record: fileconsumer.RecordConfig{
+				Format:       "lines",
+				LengthPrefix: fileconsumer.LengthPrefixConfig{Size: 4, ByteOrder: "big_endian"},
+			},
 			Criteria: matcher.Criteria{
 				Include: []string{"/var/log/*.log"},
 				Exclude: []string{"/var/log/example.log"},
 			},
 		},
+		FormatType: formatTypeJSON,
 	}
 }
 
@@ -248,3 +260,288 @@ func TestFileMixedSignals(t *testing.T) {
 	err = lr.Shutdown(context.Background())
 	assert.NoError(t, err)
 }
+
+func TestLoadConfigProto(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "proto").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	expected := testdataConfigYamlAsMap()
+	expected.FormatType = formatTypeProto
+	expected.MessageCompression = compressionZSTD
+	assert.Equal(t, expected, cfg)
+	assert.NoError(t, component.ValidateConfig(cfg))
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		errMsg string
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name: "proto with zstd",
+			modify: func(cfg *Config) {
+				cfg.FormatType = formatTypeProto
+				cfg.MessageCompression = compressionZSTD
+			},
+		},
+		{
+			name: "unknown format",
+			modify: func(cfg *Config) {
+				cfg.FormatType = "text"
+			},
+			errMsg: "format type is not supported",
+		},
+		{
+			name: "unknown compression",
+			modify: func(cfg *Config) {
+				cfg.MessageCompression = "gzip"
+			},
+			errMsg: "message_compression is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+// writeLengthPrefixed writes the messages the same way the file exporter does for the proto format
+// or when compression is enabled.
+func writeLengthPrefixed(t *testing.T, path string, compress bool, messages ...[]byte) {
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	var b []byte
+	for _, msg := range messages {
+		if compress {
+			msg = encoder.EncodeAll(msg, nil)
+		}
+		b = binary.BigEndian.AppendUint32(b, uint32(len(msg)))
+		b = append(b, msg...)
+	}
+	require.NoError(t, os.WriteFile(path, b, 0600))
+}
+
+func TestFileProtoReceiver(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compressed=%v", compress), func(t *testing.T) {
+			tempFolder := t.TempDir()
+			factory := NewFactory()
+			cfg := createDefaultConfig().(*Config)
+			cfg.Config.Include = []string{filepath.Join(tempFolder, "*")}
+			cfg.Config.StartAt = "beginning"
+			cfg.FormatType = formatTypeProto
+			if compress {
+				cfg.MessageCompression = compressionZSTD
+			}
+			ts := new(consumertest.TracesSink)
+			tr, err := factory.CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, ts)
+			require.NoError(t, err)
+			require.NoError(t, tr.Start(context.Background(), nil))
+
+			td := testdata.GenerateTraces(2)
+			b1, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+			require.NoError(t, err)
+			td2 := testdata.GenerateTraces(3)
+			b2, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td2)
+			require.NoError(t, err)
+			writeLengthPrefixed(t, filepath.Join(tempFolder, "traces.proto"), compress, b1, b2)
+
+			require.Eventually(t, func() bool {
+				return len(ts.AllTraces()) == 2
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.EqualValues(t, td, ts.AllTraces()[0])
+			assert.EqualValues(t, td2, ts.AllTraces()[1])
+			require.NoError(t, tr.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestFileProtoReceiverSkipsTooLongMessages(t *testing.T) {
+	tempFolder := t.TempDir()
+	factory := NewFactory()
+	cfg := createDefaultConfig().(*Config)
+	cfg.Config.Include = []string{filepath.Join(tempFolder, "*")}
+	cfg.Config.StartAt = "beginning"
+	cfg.FormatType = formatTypeProto
+
+	td := testdata.GenerateTraces(1)
+	b, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+	tooLong, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testdata.GenerateTraces(5))
+	require.NoError(t, err)
+	require.Greater(t, len(tooLong), len(b))
+	cfg.Config.MaxLogSize = helper.ByteSize(len(b) + frameHeaderSize)
+	writeLengthPrefixed(t, filepath.Join(tempFolder, "traces.proto"), false, tooLong, b)
+
+	ts := new(consumertest.TracesSink)
+	tr, err := factory.CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, ts)
+	require.NoError(t, err)
+	require.NoError(t, tr.Start(context.Background(), nil))
+
+	// the message exceeding max_log_size is skipped, and the following one is still read
+	require.Eventually(t, func() bool {
+		return len(ts.AllTraces()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.EqualValues(t, td, ts.AllTraces()[0])
+	require.NoError(t, tr.Shutdown(context.Background()))
+}
+
+func TestFileJSONCompressedLogsReceiver(t *testing.T) {
+	tempFolder := t.TempDir()
+	factory := NewFactory()
+	cfg := createDefaultConfig().(*Config)
+	cfg.Config.Include = []string{filepath.Join(tempFolder, "*")}
+	cfg.Config.StartAt = "beginning"
+	cfg.MessageCompression = compressionZSTD
+	sink := new(consumertest.LogsSink)
+	receiver, err := factory.CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), nil))
+
+	ld := testdata.GenerateLogs(5)
+	b, err := (&plog.JSONMarshaler{}).MarshalLogs(ld)
+	require.NoError(t, err)
+	writeLengthPrefixed(t, filepath.Join(tempFolder, "logs.zst"), true, b)
+
+	require.Eventually(t, func() bool {
+		return len(sink.AllLogs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.EqualValues(t, ld, sink.AllLogs()[0])
+	require.NoError(t, receiver.Shutdown(context.Background()))
+}
+
+func TestFileProfilesReceiver(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		write  func(t *testing.T, path string, pd pprofile.Profiles)
+	}{
+		{
+			name:   "json",
+			format: formatTypeJSON,
+			write: func(t *testing.T, path string, pd pprofile.Profiles) {
+				b, err := (&pprofile.JSONMarshaler{}).MarshalProfiles(pd)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, append(b, '\n'), 0600))
+			},
+		},
+		{
+			name:   "proto",
+			format: formatTypeProto,
+			write: func(t *testing.T, path string, pd pprofile.Profiles) {
+				b, err := pprofileotlp.NewExportRequestFromProfiles(pd).MarshalProto()
+				require.NoError(t, err)
+				writeLengthPrefixed(t, path, false, b)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFolder := t.TempDir()
+			factory := NewFactory()
+			assert.Equal(t, component.StabilityLevelDevelopment, factory.ProfilesReceiverStability())
+			cfg := createDefaultConfig().(*Config)
+			cfg.Config.Include = []string{filepath.Join(tempFolder, "*")}
+			cfg.Config.StartAt = "beginning"
+			cfg.FormatType = tt.format
+			sink := new(consumertest.ProfilesSink)
+			receiver, err := factory.CreateProfilesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+			require.NoError(t, err)
+			require.NoError(t, receiver.Start(context.Background(), nil))
+
+			pd := testdata.GenerateProfiles(2)
+			tt.write(t, filepath.Join(tempFolder, "profiles"), pd)
+
+			require.Eventually(t, func() bool {
+				return len(sink.AllProfiles()) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.EqualValues(t, pd, sink.AllProfiles()[0])
+			require.NoError(t, receiver.Shutdown(context.Background()))
+		})
+	}
+}
+
+// testLogsEncoding unmarshals every message into a log record holding the message as its body.
+type testLogsEncoding struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+func (testLogsEncoding) UnmarshalLogs(buf []byte) (plog.Logs, error) {
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr(string(buf))
+	return ld, nil
+}
+
+type extensionsHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h extensionsHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func TestFileLogsReceiverWithEncodingExtension(t *testing.T) {
+	encodingID := component.MustNewID("test_encoding")
+	host := extensionsHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{encodingID: testLogsEncoding{}},
+	}
+
+	tempFolder := t.TempDir()
+	factory := NewFactory()
+	cfg := createDefaultConfig().(*Config)
+	cfg.Config.Include = []string{filepath.Join(tempFolder, "*")}
+	cfg.Config.StartAt = "beginning"
+	cfg.EncodingExtension = &encodingID
+	sink := new(consumertest.LogsSink)
+	receiver, err := factory.CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), host))
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempFolder, "logs.txt"), []byte("first\nsecond\n"), 0600))
+
+	require.Eventually(t, func() bool {
+		return len(sink.AllLogs()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "first", sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	assert.Equal(t, "second", sink.AllLogs()[1].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	require.NoError(t, receiver.Shutdown(context.Background()))
+}
+
+func TestEncodingExtensionErrors(t *testing.T) {
+	encodingID := component.MustNewID("test_encoding")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Config.Include = []string{filepath.Join(t.TempDir(), "*")}
+	cfg.EncodingExtension = &encodingID
+
+	receiver, err := NewFactory().CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, new(consumertest.TracesSink))
+	require.NoError(t, err)
+	assert.EqualError(t, receiver.Start(context.Background(), componenttest.NewNopHost()), `unknown encoding "test_encoding"`)
+
+	host := extensionsHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{encodingID: testLogsEncoding{}},
+	}
+	assert.EqualError(t, receiver.Start(context.Background(), host), `extension "test_encoding" is not a traces unmarshaler`)
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpjsonfilereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/otlpjsonfilereceiver"
+
+import (
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/pprofile/pprofileotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// The formats and compression algorithms match the ones written by the file exporter.
+const (
+	formatTypeJSON  = "json"
+	formatTypeProto = "proto"
+
+	compressionZSTD = "zstd"
+
+	// frameHeaderSize is the size of the big endian unsigned 32 bit integer preceding each message
+	// in length prefixed files.
+	frameHeaderSize = 4
+)
+
+var tracesUnmarshalers = map[string]ptrace.Unmarshaler{
+	formatTypeJSON:  &ptrace.JSONUnmarshaler{},
+	formatTypeProto: &ptrace.ProtoUnmarshaler{},
+}
+var metricsUnmarshalers = map[string]pmetric.Unmarshaler{
+	formatTypeJSON:  &pmetric.JSONUnmarshaler{},
+	formatTypeProto: &pmetric.ProtoUnmarshaler{},
+}
+var logsUnmarshalers = map[string]plog.Unmarshaler{
+	formatTypeJSON:  &plog.JSONUnmarshaler{},
+	formatTypeProto: &plog.ProtoUnmarshaler{},
+}
+var profilesUnmarshalers = map[string]pprofile.Unmarshaler{
+	formatTypeJSON:  &pprofile.JSONUnmarshaler{},
+	formatTypeProto: profilesProtoUnmarshaler{},
+}
+
+// profilesProtoUnmarshaler unmarshals OTLP protobuf encoded profiles.
+type profilesProtoUnmarshaler struct{}
+
+func (profilesProtoUnmarshaler) UnmarshalProfiles(buf []byte) (pprofile.Profiles, error) {
+	req := pprofileotlp.NewExportRequest()
+	if err := req.UnmarshalProto(buf); err != nil {
+		return pprofile.NewProfiles(), err
+	}
+	return req.Profiles(), nil
+}
+
+// decompressFunc defines how to decompress a single message read from a file.
+type decompressFunc func(src []byte) ([]byte, error)
+
+var decoder, _ = zstd.NewReader(nil)
+
+var decompressors = map[string]decompressFunc{
+	compressionZSTD: zstdDecompress,
+}
+
+func buildDecompressor(compression string) decompressFunc {
+	if compression == "" {
+		return noneDecompress
+	}
+	return decompressors[compression]
+}
+
+// zstdDecompress decompresses a zstd compressed buffer
+func zstdDecompress(src []byte) ([]byte, error) {
+	return decoder.DecodeAll(src, nil)
+}
+
+// noneDecompress returns src
+func noneDecompress(src []byte) ([]byte, error) {
+	return src, nil
+}
+
+// isLengthPrefixed tells whether messages are framed by a length prefix rather than by new lines.
+// The file exporter writes length prefixed messages for the proto format and whenever compression is enabled.
+func (cfg *Config) isLengthPrefixed() bool {
+	return cfg.FormatType == formatTypeProto || cfg.MessageCompression != ""
+}
@@ -0,0 +1,17 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// Config defines configuration for the Prometheus remote write receiver.
+type Config struct {
+	confighttp.ServerConfig `mapstructure:",squash"`
+
+	// TrimMetricSuffixes removes the type and unit suffixes, such as _total and
+	// _seconds, from the names of the received metrics.
+	TrimMetricSuffixes bool `mapstructure:"trim_metric_suffixes"`
+}
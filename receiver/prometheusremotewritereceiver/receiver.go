@@ -0,0 +1,228 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+)
+
+const (
+	writePath  = "/api/v1/write"
+	dataFormat = "prometheus_remote_write"
+
+	protoContentType = "application/x-protobuf"
+	snappyEncoding   = "snappy"
+
+	// Names of the protobuf messages of the remote write 1.0 and 2.0 protocols,
+	// given by the proto parameter of the content type.
+	protoMessageV1 = "prometheus.WriteRequest"
+	protoMessageV2 = "io.prometheus.write.v2.Request"
+
+	// Headers of the remote write 2.0 responses telling how much of the request was written.
+	writtenSamplesHeader    = "X-Prometheus-Remote-Write-Samples-Written"
+	writtenHistogramsHeader = "X-Prometheus-Remote-Write-Histograms-Written"
+	writtenExemplarsHeader  = "X-Prometheus-Remote-Write-Exemplars-Written"
+)
+
+type metricsReceiver struct {
+	nextConsumer       consumer.Metrics
+	httpServerSettings *confighttp.ServerConfig
+	translatorSettings prometheusremotewrite.ToMetricsSettings
+
+	server *http.Server
+	wg     sync.WaitGroup
+
+	obsrecv *receiverhelper.ObsReport
+
+	settings component.TelemetrySettings
+}
+
+func newMetricsReceiver(config *Config, settings receiver.Settings, nextConsumer consumer.Metrics) (*metricsReceiver, error) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             settings.ID,
+		Transport:              "http",
+		ReceiverCreateSettings: settings,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &metricsReceiver{
+		nextConsumer:       nextConsumer,
+		httpServerSettings: &config.ServerConfig,
+		translatorSettings: prometheusremotewrite.ToMetricsSettings{
+			TrimMetricSuffixes: config.TrimMetricSuffixes,
+		},
+		obsrecv:  obsrecv,
+		settings: settings.TelemetrySettings,
+	}, nil
+}
+
+func (r *metricsReceiver) Start(ctx context.Context, host component.Host) error {
+	ln, err := r.httpServerSettings.ToListener(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", r.httpServerSettings.Endpoint, err)
+	}
+
+	router := http.NewServeMux()
+	router.HandleFunc(writePath, r.handleWrite)
+
+	// Remote write requests use the snappy block format rather than the framed format decoded
+	// by the server, they are decompressed by the handler instead. The encoding is optional
+	// for remote write 1.0 senders, and the server rejects the encodings it doesn't support.
+	r.server, err = r.httpServerSettings.ToServer(ctx, host, r.settings, router,
+		confighttp.WithDecoder(snappyEncoding, func(body io.ReadCloser) (io.ReadCloser, error) { return body, nil }))
+	if err != nil {
+		return err
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if errHTTP := r.server.Serve(ln); !errors.Is(errHTTP, http.ErrServerClosed) && errHTTP != nil {
+			componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(errHTTP))
+		}
+	}()
+
+	return nil
+}
+
+func (r *metricsReceiver) Shutdown(_ context.Context) error {
+	if r.server == nil {
+		return nil
+	}
+	if err := r.server.Close(); err != nil {
+		return err
+	}
+	r.wg.Wait()
+	return nil
+}
+
+func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request) {
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed, supported: [POST]", http.StatusMethodNotAllowed)
+		return
+	}
+
+	protoMessage, err := parseProtoMessage(req.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read the request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	buf, err := snappy.Decode(nil, body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decompress the request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var md pmetric.Metrics
+	var stats prometheusremotewrite.WriteStats
+	var convErr error
+	switch protoMessage {
+	case protoMessageV1:
+		var wr prompb.WriteRequest
+		if err = wr.Unmarshal(buf); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode the remote write 1.0 request: %v", err), http.StatusBadRequest)
+			return
+		}
+		md, stats, convErr = prometheusremotewrite.ToMetrics(&wr, r.translatorSettings)
+	case protoMessageV2:
+		var wr writev2.Request
+		if err = wr.Unmarshal(buf); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode the remote write 2.0 request: %v", err), http.StatusBadRequest)
+			return
+		}
+		md, stats, convErr = prometheusremotewrite.ToMetricsV2(&wr, r.translatorSettings)
+	}
+
+	ctx := r.obsrecv.StartMetricsOp(req.Context())
+	dataPointCount := md.DataPointCount()
+	if dataPointCount > 0 {
+		err = r.nextConsumer.ConsumeMetrics(ctx, md)
+	}
+	r.obsrecv.EndMetricsOp(ctx, dataFormat, dataPointCount, err)
+	if err != nil {
+		if protoMessage == protoMessageV2 {
+			setWrittenHeaders(w, prometheusremotewrite.WriteStats{})
+		}
+		r.settings.Logger.Debug("failed to pass metrics to next consumer", zap.Error(err))
+		if consumererror.IsPermanent(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if protoMessage == protoMessageV2 {
+		setWrittenHeaders(w, stats)
+	}
+	if convErr != nil {
+		// The valid series were written, the invalid ones must not be retried.
+		r.settings.Logger.Debug("invalid series in remote write request", zap.Error(convErr))
+		http.Error(w, convErr.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseProtoMessage returns the remote write protobuf message of a request from its content type.
+// Remote write 1.0 senders may omit the content type, or the proto parameter.
+func parseProtoMessage(contentType string) (string, error) {
+	if contentType == "" {
+		return protoMessageV1, nil
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+	if mediaType != protoContentType {
+		return "", fmt.Errorf("unsupported content type %q, expected %q", mediaType, protoContentType)
+	}
+	switch msg := params["proto"]; msg {
+	case "", protoMessageV1:
+		return protoMessageV1, nil
+	case protoMessageV2:
+		return protoMessageV2, nil
+	default:
+		return "", fmt.Errorf("unsupported protobuf message %q, expected %q or %q", msg, protoMessageV1, protoMessageV2)
+	}
+}
+
+func setWrittenHeaders(w http.ResponseWriter, stats prometheusremotewrite.WriteStats) {
+	w.Header().Set(writtenSamplesHeader, strconv.Itoa(stats.Samples))
+	w.Header().Set(writtenHistogramsHeader, strconv.Itoa(stats.Histograms))
+	w.Header().Set(writtenExemplarsHeader, strconv.Itoa(stats.Exemplars))
+}
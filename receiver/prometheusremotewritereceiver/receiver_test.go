@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+func startReceiver(t *testing.T, nextConsumer consumer.Metrics) string {
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := &Config{
+		ServerConfig: confighttp.ServerConfig{
+			Endpoint: addr,
+		},
+	}
+	receiver, err := NewFactory().CreateMetricsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, nextConsumer)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, receiver.Shutdown(context.Background()))
+	})
+	return "http://" + addr + writePath
+}
+
+type marshaler interface {
+	Marshal() ([]byte, error)
+}
+
+func send(t *testing.T, url string, contentType string, msg marshaler) *http.Response {
+	buf, err := msg.Marshal()
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(snappy.Encode(nil, buf)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Content-Encoding", "snappy")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func v1Request(ts int64) *prompb.WriteRequest {
+	return &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "up"},
+					{Name: "instance", Value: "localhost:9100"},
+					{Name: "job", Value: "node"},
+				},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: ts}},
+			},
+		},
+	}
+}
+
+func v2Request(ts int64, labelRefs ...uint32) *writev2.Request {
+	return &writev2.Request{
+		Symbols: []string{"", "__name__", "requests_total", "job", "api", "up"},
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 2, 3, 4},
+				Samples:    []writev2.Sample{{Value: 5, Timestamp: ts}},
+				Exemplars:  []writev2.Exemplar{{Value: 1, Timestamp: ts}},
+				Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER},
+			},
+			{
+				LabelsRefs: labelRefs,
+				Samples:    []writev2.Sample{{Value: 1, Timestamp: ts}},
+			},
+		},
+	}
+}
+
+func TestReceiveRemoteWrite(t *testing.T) {
+	ts := time.Now().UnixMilli()
+
+	t.Run("remote write 1.0", func(t *testing.T) {
+		sink := new(consumertest.MetricsSink)
+		url := startReceiver(t, sink)
+
+		resp := send(t, url, "application/x-protobuf", v1Request(ts))
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(writtenSamplesHeader))
+
+		require.Len(t, sink.AllMetrics(), 1)
+		md := sink.AllMetrics()[0]
+		assert.Equal(t, 1, md.DataPointCount())
+		serviceName, ok := md.ResourceMetrics().At(0).Resource().Attributes().Get("service.name")
+		require.True(t, ok)
+		assert.Equal(t, "node", serviceName.Str())
+		assert.Equal(t, "up", md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+	})
+
+	t.Run("remote write 2.0", func(t *testing.T) {
+		sink := new(consumertest.MetricsSink)
+		url := startReceiver(t, sink)
+
+		resp := send(t, url, "application/x-protobuf;proto=io.prometheus.write.v2.Request", v2Request(ts, 1, 5, 3, 4))
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get(writtenSamplesHeader))
+		assert.Equal(t, "0", resp.Header.Get(writtenHistogramsHeader))
+		assert.Equal(t, "1", resp.Header.Get(writtenExemplarsHeader))
+
+		require.Len(t, sink.AllMetrics(), 1)
+		assert.Equal(t, 2, sink.AllMetrics()[0].DataPointCount())
+	})
+
+	t.Run("remote write 2.0 partial write", func(t *testing.T) {
+		sink := new(consumertest.MetricsSink)
+		url := startReceiver(t, sink)
+
+		resp := send(t, url, "application/x-protobuf;proto=io.prometheus.write.v2.Request", v2Request(ts, 1, 42))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "1", resp.Header.Get(writtenSamplesHeader))
+
+		require.Len(t, sink.AllMetrics(), 1)
+		assert.Equal(t, 1, sink.AllMetrics()[0].DataPointCount())
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		sink := new(consumertest.MetricsSink)
+		url := startReceiver(t, sink)
+
+		resp := send(t, url, "application/x-protobuf;proto=io.prometheus.write.v3.Request", v1Request(ts))
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+		resp = send(t, url, "application/json", v1Request(ts))
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+		assert.Empty(t, sink.AllMetrics())
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		url := startReceiver(t, consumertest.NewNop())
+
+		resp, err := http.Post(url, "application/x-protobuf", bytes.NewReader([]byte("not snappy")))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		url := startReceiver(t, consumertest.NewNop())
+
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+
+	t.Run("consumer errors", func(t *testing.T) {
+		url := startReceiver(t, consumertest.NewErr(errors.New("temporary failure")))
+		resp := send(t, url, "application/x-protobuf;proto=io.prometheus.write.v2.Request", v2Request(ts, 1, 5, 3, 4))
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "0", resp.Header.Get(writtenSamplesHeader))
+
+		url = startReceiver(t, consumertest.NewErr(consumererror.NewPermanent(errors.New("permanent failure"))))
+		resp = send(t, url, "application/x-protobuf", v1Request(ts))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
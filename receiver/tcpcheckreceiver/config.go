@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcpcheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcpcheckreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcpcheckreceiver/internal/metadata"
+)
+
+// Predefined error responses for configuration validation failures
+var (
+	errNoTargets       = errors.New("no targets configured")
+	errMissingEndpoint = errors.New(`"endpoint" must be specified`)
+	errInvalidEndpoint = errors.New(`"endpoint" must be in the form of <hostname>:<port>`)
+	errInvalidExpect   = errors.New(`"expect" must be a valid regular expression`)
+	errInvalidTimeout  = errors.New(`"timeout" must not be negative`)
+)
+
+// Config defines the configuration for the various elements of the receiver agent.
+type Config struct {
+	scraperhelper.ControllerConfig `mapstructure:",squash"`
+	metadata.MetricsBuilderConfig  `mapstructure:",squash"`
+	Targets                        []*targetConfig `mapstructure:"targets"`
+}
+
+type targetConfig struct {
+	// Endpoint is the address to connect to, in the form of <hostname>:<port>.
+	Endpoint string `mapstructure:"endpoint"`
+	// Timeout bounds the whole check: connecting, the TLS handshake and the banner exchange.
+	// Defaults to 10s when unset.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Send is an optional payload written to the connection once it is established.
+	Send string `mapstructure:"send"`
+	// Expect is an optional regular expression the data read from the connection must match.
+	Expect string `mapstructure:"expect"`
+	// TLSSetting enables a TLS handshake on top of the TCP connection when set.
+	TLSSetting *configtls.ClientConfig `mapstructure:"tls"`
+}
+
+// Validate validates the configuration by checking for missing or invalid fields
+func (cfg *targetConfig) Validate() error {
+	var err error
+
+	if cfg.Endpoint == "" {
+		err = multierr.Append(err, errMissingEndpoint)
+	} else if _, _, splitErr := net.SplitHostPort(cfg.Endpoint); splitErr != nil {
+		err = multierr.Append(err, fmt.Errorf("%s: %w", errInvalidEndpoint.Error(), splitErr))
+	}
+
+	if cfg.Timeout < 0 {
+		err = multierr.Append(err, errInvalidTimeout)
+	}
+
+	if cfg.Expect != "" {
+		if _, reErr := regexp.Compile(cfg.Expect); reErr != nil {
+			err = multierr.Append(err, fmt.Errorf("%s: %w", errInvalidExpect.Error(), reErr))
+		}
+	}
+
+	return err
+}
+
+// Validate validates the configuration by checking for missing or invalid fields
+func (cfg *Config) Validate() error {
+	var err error
+
+	if len(cfg.Targets) == 0 {
+		err = multierr.Append(err, errNoTargets)
+	}
+
+	for _, target := range cfg.Targets {
+		err = multierr.Append(err, target.Validate())
+	}
+
+	return err
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcpcheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcpcheckreceiver"
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcpcheckreceiver/internal/metadata"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		cfg         *Config
+		expectedErr error
+	}{
+		{
+			desc: "missing targets",
+			cfg: &Config{
+				Targets:          []*targetConfig{},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+			expectedErr: errNoTargets,
+		},
+		{
+			desc: "missing endpoint",
+			cfg: &Config{
+				Targets:          []*targetConfig{{}},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+			expectedErr: errMissingEndpoint,
+		},
+		{
+			desc: "missing port",
+			cfg: &Config{
+				Targets:          []*targetConfig{{Endpoint: "localhost"}},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+			expectedErr: errInvalidEndpoint,
+		},
+		{
+			desc: "negative timeout and invalid expect",
+			cfg: &Config{
+				Targets: []*targetConfig{
+					{
+						Endpoint: "localhost:80",
+						Timeout:  -time.Second,
+						Expect:   "(unclosed",
+					},
+				},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+			expectedErr: multierr.Combine(errInvalidTimeout, errInvalidExpect),
+		},
+		{
+			desc: "valid config",
+			cfg: &Config{
+				Targets: []*targetConfig{
+					{
+						Endpoint: "localhost:80",
+						Send:     "PING\r\n",
+						Expect:   "^PONG",
+					},
+				},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			actualErr := tc.cfg.Validate()
+			if tc.expectedErr == nil {
+				require.NoError(t, actualErr)
+				return
+			}
+			for _, expected := range multierr.Errors(tc.expectedErr) {
+				require.ErrorContains(t, actualErr, expected.Error())
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id       component.ID
+		expected component.Config
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "all"),
+			expected: &Config{
+				ControllerConfig: scraperhelper.ControllerConfig{
+					CollectionInterval: 30 * time.Second,
+					InitialDelay:       time.Second,
+				},
+				MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+				Targets: []*targetConfig{
+					{
+						Endpoint: "localhost:6379",
+						Timeout:  5 * time.Second,
+						Send:     "PING\r\n",
+						Expect:   `^\+PONG`,
+					},
+					{
+						Endpoint:   "example.com:443",
+						TLSSetting: &configtls.ClientConfig{},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			require.Equal(t, tt.expected, cfg)
+		})
+	}
+}
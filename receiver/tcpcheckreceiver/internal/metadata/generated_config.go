@@ -0,0 +1,74 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// MetricConfig provides common config for a particular metric.
+type MetricConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	enabledSetByUser bool
+}
+
+func (ms *MetricConfig) Unmarshal(parser *confmap.Conf) error {
+	if parser == nil {
+		return nil
+	}
+	err := parser.Unmarshal(ms)
+	if err != nil {
+		return err
+	}
+	ms.enabledSetByUser = parser.IsSet("enabled")
+	return nil
+}
+
+// MetricsConfig provides config for tcpcheck metrics.
+type MetricsConfig struct {
+	TcpcheckDuration             MetricConfig `mapstructure:"tcpcheck.duration"`
+	TcpcheckError                MetricConfig `mapstructure:"tcpcheck.error"`
+	TcpcheckExpectDuration       MetricConfig `mapstructure:"tcpcheck.expect_duration"`
+	TcpcheckExpectStatus         MetricConfig `mapstructure:"tcpcheck.expect_status"`
+	TcpcheckStatus               MetricConfig `mapstructure:"tcpcheck.status"`
+	TcpcheckTLSCertTimeLeft      MetricConfig `mapstructure:"tcpcheck.tls.cert_time_left"`
+	TcpcheckTLSHandshakeDuration MetricConfig `mapstructure:"tcpcheck.tls.handshake_duration"`
+}
+
+func DefaultMetricsConfig() MetricsConfig {
+	return MetricsConfig{
+		TcpcheckDuration: MetricConfig{
+			Enabled: true,
+		},
+		TcpcheckError: MetricConfig{
+			Enabled: true,
+		},
+		TcpcheckExpectDuration: MetricConfig{
+			Enabled: true,
+		},
+		TcpcheckExpectStatus: MetricConfig{
+			Enabled: true,
+		},
+		TcpcheckStatus: MetricConfig{
+			Enabled: true,
+		},
+		TcpcheckTLSCertTimeLeft: MetricConfig{
+			Enabled: true,
+		},
+		TcpcheckTLSHandshakeDuration: MetricConfig{
+			Enabled: true,
+		},
+	}
+}
+
+// MetricsBuilderConfig is a configuration for tcpcheck metrics builder.
+type MetricsBuilderConfig struct {
+	Metrics MetricsConfig `mapstructure:"metrics"`
+}
+
+func DefaultMetricsBuilderConfig() MetricsBuilderConfig {
+	return MetricsBuilderConfig{
+		Metrics: DefaultMetricsConfig(),
+	}
+}
@@ -0,0 +1,545 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+type metricTcpcheckDuration struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills tcpcheck.duration metric with initial data.
+func (m *metricTcpcheckDuration) init() {
+	m.data.SetName("tcpcheck.duration")
+	m.data.SetDescription("Measures the duration of the TCP connection.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricTcpcheckDuration) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("tcp.endpoint", tcpEndpointAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricTcpcheckDuration) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricTcpcheckDuration) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricTcpcheckDuration(cfg MetricConfig) metricTcpcheckDuration {
+	m := metricTcpcheckDuration{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricTcpcheckError struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills tcpcheck.error metric with initial data.
+func (m *metricTcpcheckError) init() {
+	m.data.SetName("tcpcheck.error")
+	m.data.SetDescription("Records errors occurring during TCP check.")
+	m.data.SetUnit("{error}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricTcpcheckError) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string, errorMessageAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("tcp.endpoint", tcpEndpointAttributeValue)
+	dp.Attributes().PutStr("error.message", errorMessageAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricTcpcheckError) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricTcpcheckError) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricTcpcheckError(cfg MetricConfig) metricTcpcheckError {
+	m := metricTcpcheckError{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricTcpcheckExpectDuration struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills tcpcheck.expect_duration metric with initial data.
+func (m *metricTcpcheckExpectDuration) init() {
+	m.data.SetName("tcpcheck.expect_duration")
+	m.data.SetDescription("Measures the time from sending the payload until the expected pattern was read. Only recorded when `expect` is configured.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricTcpcheckExpectDuration) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("tcp.endpoint", tcpEndpointAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricTcpcheckExpectDuration) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricTcpcheckExpectDuration) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricTcpcheckExpectDuration(cfg MetricConfig) metricTcpcheckExpectDuration {
+	m := metricTcpcheckExpectDuration{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricTcpcheckExpectStatus struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills tcpcheck.expect_status metric with initial data.
+func (m *metricTcpcheckExpectStatus) init() {
+	m.data.SetName("tcpcheck.expect_status")
+	m.data.SetDescription("1 if the response read from the endpoint matched the expected pattern, otherwise 0. Only recorded when `expect` is configured.")
+	m.data.SetUnit("1")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricTcpcheckExpectStatus) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("tcp.endpoint", tcpEndpointAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricTcpcheckExpectStatus) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricTcpcheckExpectStatus) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricTcpcheckExpectStatus(cfg MetricConfig) metricTcpcheckExpectStatus {
+	m := metricTcpcheckExpectStatus{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricTcpcheckStatus struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills tcpcheck.status metric with initial data.
+func (m *metricTcpcheckStatus) init() {
+	m.data.SetName("tcpcheck.status")
+	m.data.SetDescription("1 if the TCP client successfully connected, otherwise 0.")
+	m.data.SetUnit("1")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricTcpcheckStatus) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("tcp.endpoint", tcpEndpointAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricTcpcheckStatus) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricTcpcheckStatus) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricTcpcheckStatus(cfg MetricConfig) metricTcpcheckStatus {
+	m := metricTcpcheckStatus{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricTcpcheckTLSCertTimeLeft struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills tcpcheck.tls.cert_time_left metric with initial data.
+func (m *metricTcpcheckTLSCertTimeLeft) init() {
+	m.data.SetName("tcpcheck.tls.cert_time_left")
+	m.data.SetDescription("Time in seconds until the certificate presented by the endpoint expires. Negative if already expired. Only recorded when `tls` is configured.")
+	m.data.SetUnit("s")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricTcpcheckTLSCertTimeLeft) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("tcp.endpoint", tcpEndpointAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricTcpcheckTLSCertTimeLeft) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricTcpcheckTLSCertTimeLeft) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricTcpcheckTLSCertTimeLeft(cfg MetricConfig) metricTcpcheckTLSCertTimeLeft {
+	m := metricTcpcheckTLSCertTimeLeft{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricTcpcheckTLSHandshakeDuration struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills tcpcheck.tls.handshake_duration metric with initial data.
+func (m *metricTcpcheckTLSHandshakeDuration) init() {
+	m.data.SetName("tcpcheck.tls.handshake_duration")
+	m.data.SetDescription("Measures the duration of the TLS handshake. Only recorded when `tls` is configured.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricTcpcheckTLSHandshakeDuration) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("tcp.endpoint", tcpEndpointAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricTcpcheckTLSHandshakeDuration) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricTcpcheckTLSHandshakeDuration) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricTcpcheckTLSHandshakeDuration(cfg MetricConfig) metricTcpcheckTLSHandshakeDuration {
+	m := metricTcpcheckTLSHandshakeDuration{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+// MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
+// required to produce metric representation defined in metadata and user config.
+type MetricsBuilder struct {
+	config                             MetricsBuilderConfig // config of the metrics builder.
+	startTime                          pcommon.Timestamp    // start time that will be applied to all recorded data points.
+	metricsCapacity                    int                  // maximum observed number of metrics per resource.
+	metricsBuffer                      pmetric.Metrics      // accumulates metrics data before emitting.
+	buildInfo                          component.BuildInfo  // contains version information.
+	metricTcpcheckDuration             metricTcpcheckDuration
+	metricTcpcheckError                metricTcpcheckError
+	metricTcpcheckExpectDuration       metricTcpcheckExpectDuration
+	metricTcpcheckExpectStatus         metricTcpcheckExpectStatus
+	metricTcpcheckStatus               metricTcpcheckStatus
+	metricTcpcheckTLSCertTimeLeft      metricTcpcheckTLSCertTimeLeft
+	metricTcpcheckTLSHandshakeDuration metricTcpcheckTLSHandshakeDuration
+}
+
+// metricBuilderOption applies changes to default metrics builder.
+type metricBuilderOption func(*MetricsBuilder)
+
+// WithStartTime sets startTime on the metrics builder.
+func WithStartTime(startTime pcommon.Timestamp) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.startTime = startTime
+	}
+}
+
+func NewMetricsBuilder(mbc MetricsBuilderConfig, settings receiver.Settings, options ...metricBuilderOption) *MetricsBuilder {
+	mb := &MetricsBuilder{
+		config:                             mbc,
+		startTime:                          pcommon.NewTimestampFromTime(time.Now()),
+		metricsBuffer:                      pmetric.NewMetrics(),
+		buildInfo:                          settings.BuildInfo,
+		metricTcpcheckDuration:             newMetricTcpcheckDuration(mbc.Metrics.TcpcheckDuration),
+		metricTcpcheckError:                newMetricTcpcheckError(mbc.Metrics.TcpcheckError),
+		metricTcpcheckExpectDuration:       newMetricTcpcheckExpectDuration(mbc.Metrics.TcpcheckExpectDuration),
+		metricTcpcheckExpectStatus:         newMetricTcpcheckExpectStatus(mbc.Metrics.TcpcheckExpectStatus),
+		metricTcpcheckStatus:               newMetricTcpcheckStatus(mbc.Metrics.TcpcheckStatus),
+		metricTcpcheckTLSCertTimeLeft:      newMetricTcpcheckTLSCertTimeLeft(mbc.Metrics.TcpcheckTLSCertTimeLeft),
+		metricTcpcheckTLSHandshakeDuration: newMetricTcpcheckTLSHandshakeDuration(mbc.Metrics.TcpcheckTLSHandshakeDuration),
+	}
+
+	for _, op := range options {
+		op(mb)
+	}
+	return mb
+}
+
+// updateCapacity updates max length of metrics and resource attributes that will be used for the slice capacity.
+func (mb *MetricsBuilder) updateCapacity(rm pmetric.ResourceMetrics) {
+	if mb.metricsCapacity < rm.ScopeMetrics().At(0).Metrics().Len() {
+		mb.metricsCapacity = rm.ScopeMetrics().At(0).Metrics().Len()
+	}
+}
+
+// ResourceMetricsOption applies changes to provided resource metrics.
+type ResourceMetricsOption func(pmetric.ResourceMetrics)
+
+// WithResource sets the provided resource on the emitted ResourceMetrics.
+// It's recommended to use ResourceBuilder to create the resource.
+func WithResource(res pcommon.Resource) ResourceMetricsOption {
+	return func(rm pmetric.ResourceMetrics) {
+		res.CopyTo(rm.Resource())
+	}
+}
+
+// WithStartTimeOverride overrides start time for all the resource metrics data points.
+// This option should be only used if different start time has to be set on metrics coming from different resources.
+func WithStartTimeOverride(start pcommon.Timestamp) ResourceMetricsOption {
+	return func(rm pmetric.ResourceMetrics) {
+		var dps pmetric.NumberDataPointSlice
+		metrics := rm.ScopeMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			switch metrics.At(i).Type() {
+			case pmetric.MetricTypeGauge:
+				dps = metrics.At(i).Gauge().DataPoints()
+			case pmetric.MetricTypeSum:
+				dps = metrics.At(i).Sum().DataPoints()
+			}
+			for j := 0; j < dps.Len(); j++ {
+				dps.At(j).SetStartTimestamp(start)
+			}
+		}
+	}
+}
+
+// EmitForResource saves all the generated metrics under a new resource and updates the internal state to be ready for
+// recording another set of data points as part of another resource. This function can be helpful when one scraper
+// needs to emit metrics from several resources. Otherwise calling this function is not required,
+// just `Emit` function can be called instead.
+// Resource attributes should be provided as ResourceMetricsOption arguments.
+func (mb *MetricsBuilder) EmitForResource(rmo ...ResourceMetricsOption) {
+	rm := pmetric.NewResourceMetrics()
+	ils := rm.ScopeMetrics().AppendEmpty()
+	ils.Scope().SetName("github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcpcheckreceiver")
+	ils.Scope().SetVersion(mb.buildInfo.Version)
+	ils.Metrics().EnsureCapacity(mb.metricsCapacity)
+	mb.metricTcpcheckDuration.emit(ils.Metrics())
+	mb.metricTcpcheckError.emit(ils.Metrics())
+	mb.metricTcpcheckExpectDuration.emit(ils.Metrics())
+	mb.metricTcpcheckExpectStatus.emit(ils.Metrics())
+	mb.metricTcpcheckStatus.emit(ils.Metrics())
+	mb.metricTcpcheckTLSCertTimeLeft.emit(ils.Metrics())
+	mb.metricTcpcheckTLSHandshakeDuration.emit(ils.Metrics())
+
+	for _, op := range rmo {
+		op(rm)
+	}
+
+	if ils.Metrics().Len() > 0 {
+		mb.updateCapacity(rm)
+		rm.MoveTo(mb.metricsBuffer.ResourceMetrics().AppendEmpty())
+	}
+}
+
+// Emit returns all the metrics accumulated by the metrics builder and updates the internal state to be ready for
+// recording another set of metrics. This function will be responsible for applying all the transformations required to
+// produce metric representation defined in metadata and user config, e.g. delta or cumulative.
+func (mb *MetricsBuilder) Emit(rmo ...ResourceMetricsOption) pmetric.Metrics {
+	mb.EmitForResource(rmo...)
+	metrics := mb.metricsBuffer
+	mb.metricsBuffer = pmetric.NewMetrics()
+	return metrics
+}
+
+// RecordTcpcheckDurationDataPoint adds a data point to tcpcheck.duration metric.
+func (mb *MetricsBuilder) RecordTcpcheckDurationDataPoint(ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	mb.metricTcpcheckDuration.recordDataPoint(mb.startTime, ts, val, tcpEndpointAttributeValue)
+}
+
+// RecordTcpcheckErrorDataPoint adds a data point to tcpcheck.error metric.
+func (mb *MetricsBuilder) RecordTcpcheckErrorDataPoint(ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string, errorMessageAttributeValue string) {
+	mb.metricTcpcheckError.recordDataPoint(mb.startTime, ts, val, tcpEndpointAttributeValue, errorMessageAttributeValue)
+}
+
+// RecordTcpcheckExpectDurationDataPoint adds a data point to tcpcheck.expect_duration metric.
+func (mb *MetricsBuilder) RecordTcpcheckExpectDurationDataPoint(ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	mb.metricTcpcheckExpectDuration.recordDataPoint(mb.startTime, ts, val, tcpEndpointAttributeValue)
+}
+
+// RecordTcpcheckExpectStatusDataPoint adds a data point to tcpcheck.expect_status metric.
+func (mb *MetricsBuilder) RecordTcpcheckExpectStatusDataPoint(ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	mb.metricTcpcheckExpectStatus.recordDataPoint(mb.startTime, ts, val, tcpEndpointAttributeValue)
+}
+
+// RecordTcpcheckStatusDataPoint adds a data point to tcpcheck.status metric.
+func (mb *MetricsBuilder) RecordTcpcheckStatusDataPoint(ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	mb.metricTcpcheckStatus.recordDataPoint(mb.startTime, ts, val, tcpEndpointAttributeValue)
+}
+
+// RecordTcpcheckTLSCertTimeLeftDataPoint adds a data point to tcpcheck.tls.cert_time_left metric.
+func (mb *MetricsBuilder) RecordTcpcheckTLSCertTimeLeftDataPoint(ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	mb.metricTcpcheckTLSCertTimeLeft.recordDataPoint(mb.startTime, ts, val, tcpEndpointAttributeValue)
+}
+
+// RecordTcpcheckTLSHandshakeDurationDataPoint adds a data point to tcpcheck.tls.handshake_duration metric.
+func (mb *MetricsBuilder) RecordTcpcheckTLSHandshakeDurationDataPoint(ts pcommon.Timestamp, val int64, tcpEndpointAttributeValue string) {
+	mb.metricTcpcheckTLSHandshakeDuration.recordDataPoint(mb.startTime, ts, val, tcpEndpointAttributeValue)
+}
+
+// Reset resets metrics builder to its initial state. It should be used when external metrics source is restarted,
+// and metrics builder should update its startTime and reset it's internal state accordingly.
+func (mb *MetricsBuilder) Reset(options ...metricBuilderOption) {
+	mb.startTime = pcommon.NewTimestampFromTime(time.Now())
+	for _, op := range options {
+		op(mb)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcpcheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcpcheckreceiver"
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcpcheckreceiver/internal/metadata"
+)
+
+// maxExpectBytes limits how much data is read from an endpoint while waiting for the expected response.
+const maxExpectBytes = 64 * 1024
+
+var (
+	errTargetsNotInit = errors.New("targets not initialized")
+	errNoPeerCerts    = errors.New("no certificates presented by peer")
+	errExpectEOF      = errors.New("connection closed before expected response was received")
+)
+
+// target holds the resolved settings of a single configured target.
+type target struct {
+	cfg       *targetConfig
+	timeout   time.Duration
+	tlsConfig *tls.Config
+	expect    *regexp.Regexp
+}
+
+type tcpcheckScraper struct {
+	targets  []*target
+	cfg      *Config
+	settings component.TelemetrySettings
+	mb       *metadata.MetricsBuilder
+	mux      sync.Mutex
+}
+
+// start resolves the TLS configuration and expected patterns of each target
+func (s *tcpcheckScraper) start(ctx context.Context, _ component.Host) (err error) {
+	for _, cfg := range s.cfg.Targets {
+		t := &target{cfg: cfg, timeout: cfg.Timeout}
+		if t.timeout == 0 {
+			t.timeout = defaultTimeout
+		}
+		if cfg.TLSSetting != nil {
+			tlsCfg, tlsErr := cfg.TLSSetting.LoadTLSConfig(ctx)
+			if tlsErr != nil {
+				err = multierr.Append(err, fmt.Errorf("failed to load TLS config for %q: %w", cfg.Endpoint, tlsErr))
+				continue
+			}
+			if tlsCfg.ServerName == "" {
+				if host, _, splitErr := net.SplitHostPort(cfg.Endpoint); splitErr == nil {
+					tlsCfg.ServerName = host
+				}
+			}
+			t.tlsConfig = tlsCfg
+		}
+		if cfg.Expect != "" {
+			re, reErr := regexp.Compile(cfg.Expect)
+			if reErr != nil {
+				err = multierr.Append(err, reErr)
+				continue
+			}
+			t.expect = re
+		}
+		s.targets = append(s.targets, t)
+	}
+	return
+}
+
+// scrape checks every target concurrently and produces metrics based on the outcome
+func (s *tcpcheckScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+	if len(s.targets) == 0 {
+		return pmetric.NewMetrics(), errTargetsNotInit
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(s.targets))
+	for _, t := range s.targets {
+		go func(t *target) {
+			defer wg.Done()
+			s.check(ctx, t)
+		}(t)
+	}
+	wg.Wait()
+
+	return s.mb.Emit(), nil
+}
+
+// check runs all configured stages against a single target. Each stage is only
+// attempted when the previous one succeeded.
+func (s *tcpcheckScraper) check(ctx context.Context, t *target) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	endpoint := t.cfg.Endpoint
+	now := pcommon.NewTimestampFromTime(time.Now())
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	connectDuration := time.Since(start)
+
+	s.mux.Lock()
+	s.mb.RecordTcpcheckDurationDataPoint(now, connectDuration.Milliseconds(), endpoint)
+	if err != nil {
+		s.mb.RecordTcpcheckStatusDataPoint(now, int64(0), endpoint)
+		s.mb.RecordTcpcheckErrorDataPoint(now, int64(1), endpoint, err.Error())
+		s.mux.Unlock()
+		return
+	}
+	s.mb.RecordTcpcheckStatusDataPoint(now, int64(1), endpoint)
+	s.mux.Unlock()
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if t.tlsConfig != nil {
+		tlsConn, handshakeErr := s.checkTLS(ctx, now, t, conn)
+		if handshakeErr != nil {
+			return
+		}
+		conn = tlsConn
+	}
+
+	if t.cfg.Send != "" || t.expect != nil {
+		s.checkExpect(now, t, conn)
+	}
+}
+
+// checkTLS performs the TLS handshake on conn and records its duration and the remaining
+// validity of the leaf certificate.
+func (s *tcpcheckScraper) checkTLS(ctx context.Context, now pcommon.Timestamp, t *target, conn net.Conn) (net.Conn, error) {
+	endpoint := t.cfg.Endpoint
+	tlsConn := tls.Client(conn, t.tlsConfig)
+
+	start := time.Now()
+	err := tlsConn.HandshakeContext(ctx)
+	handshakeDuration := time.Since(start)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.mb.RecordTcpcheckTLSHandshakeDurationDataPoint(now, handshakeDuration.Milliseconds(), endpoint)
+	if err != nil {
+		s.mb.RecordTcpcheckErrorDataPoint(now, int64(1), endpoint, err.Error())
+		return nil, err
+	}
+
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		s.mb.RecordTcpcheckErrorDataPoint(now, int64(1), endpoint, errNoPeerCerts.Error())
+		return tlsConn, nil
+	}
+	s.mb.RecordTcpcheckTLSCertTimeLeftDataPoint(now, int64(time.Until(certs[0].NotAfter).Seconds()), endpoint)
+	return tlsConn, nil
+}
+
+// checkExpect writes the configured payload and reads from conn until the expected
+// pattern matches, the peer closes the connection or the deadline expires.
+func (s *tcpcheckScraper) checkExpect(now pcommon.Timestamp, t *target, conn net.Conn) {
+	endpoint := t.cfg.Endpoint
+	start := time.Now()
+
+	var err error
+	if t.cfg.Send != "" {
+		_, err = io.WriteString(conn, t.cfg.Send)
+	}
+
+	matched := t.expect == nil
+	if err == nil && t.expect != nil {
+		matched, err = readUntilMatch(conn, t.expect)
+	}
+	expectDuration := time.Since(start)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err != nil {
+		s.mb.RecordTcpcheckErrorDataPoint(now, int64(1), endpoint, err.Error())
+	}
+	if t.expect == nil {
+		return
+	}
+	s.mb.RecordTcpcheckExpectDurationDataPoint(now, expectDuration.Milliseconds(), endpoint)
+	if matched {
+		s.mb.RecordTcpcheckExpectStatusDataPoint(now, int64(1), endpoint)
+	} else {
+		s.mb.RecordTcpcheckExpectStatusDataPoint(now, int64(0), endpoint)
+	}
+}
+
+// readUntilMatch accumulates data read from r until re matches it.
+func readUntilMatch(r io.Reader, re *regexp.Regexp) (bool, error) {
+	buf := make([]byte, 0, 4096)
+	chunk := make([]byte, 4096)
+	for len(buf) < maxExpectBytes {
+		n, err := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if re.Match(buf) {
+			return true, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return false, errExpectEOF
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return false, fmt.Errorf("expected response not found in the first %d bytes", maxExpectBytes)
+}
+
+func newScraper(conf *Config, settings receiver.Settings) *tcpcheckScraper {
+	return &tcpcheckScraper{
+		cfg:      conf,
+		settings: settings.TelemetrySettings,
+		mb:       metadata.NewMetricsBuilder(conf.MetricsBuilderConfig, settings),
+	}
+}
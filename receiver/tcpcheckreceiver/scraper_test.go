@@ -0,0 +1,229 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tcpcheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcpcheckreceiver"
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+// newPingServer starts a listener answering "+PONG" to every "PING" line.
+func newPingServer(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					if strings.TrimSpace(scanner.Text()) == "PING" {
+						_, _ = conn.Write([]byte("+PONG\r\n"))
+					}
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// closedEndpoint returns an address nothing is listening on.
+func closedEndpoint(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	endpoint := ln.Addr().String()
+	require.NoError(t, ln.Close())
+	return endpoint
+}
+
+// gaugeOrSumValues maps the tcp.endpoint attribute to the value of each data point of the named metric.
+func gaugeOrSumValues(t *testing.T, metrics pmetric.Metrics, name string) map[string]int64 {
+	values := map[string]int64{}
+	rms := metrics.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			ms := sms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := ms.At(k)
+				if m.Name() != name {
+					continue
+				}
+				var dps pmetric.NumberDataPointSlice
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					dps = m.Gauge().DataPoints()
+				case pmetric.MetricTypeSum:
+					dps = m.Sum().DataPoints()
+				default:
+					t.Fatalf("unexpected metric type %s for %s", m.Type(), name)
+				}
+				for l := 0; l < dps.Len(); l++ {
+					endpoint, ok := dps.At(l).Attributes().Get("tcp.endpoint")
+					require.True(t, ok)
+					values[endpoint.Str()] = dps.At(l).IntValue()
+				}
+			}
+		}
+	}
+	return values
+}
+
+func TestScraperStart(t *testing.T) {
+	s := newScraper(&Config{
+		Targets: []*targetConfig{
+			{
+				Endpoint: "localhost:443",
+				TLSSetting: &configtls.ClientConfig{
+					Config: configtls.Config{
+						CAFile: "/non/existent",
+					},
+				},
+			},
+		},
+	}, receivertest.NewNopSettings())
+	require.Error(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+	s = newScraper(&Config{
+		Targets: []*targetConfig{
+			{
+				Endpoint:   "localhost:443",
+				Expect:     "^OK",
+				TLSSetting: &configtls.ClientConfig{},
+			},
+		},
+	}, receivertest.NewNopSettings())
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	require.Len(t, s.targets, 1)
+	assert.Equal(t, defaultTimeout, s.targets[0].timeout)
+	assert.Equal(t, "localhost", s.targets[0].tlsConfig.ServerName)
+	assert.NotNil(t, s.targets[0].expect)
+}
+
+func TestScraperNotStarted(t *testing.T) {
+	s := newScraper(createDefaultConfig().(*Config), receivertest.NewNopSettings())
+	_, err := s.scrape(context.Background())
+	require.ErrorIs(t, err, errTargetsNotInit)
+}
+
+func TestScrape(t *testing.T) {
+	pingEndpoint := newPingServer(t)
+	unreachableEndpoint := closedEndpoint(t)
+
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+	tlsEndpoint := tlsServer.Listener.Addr().String()
+	tlsServerCert := tlsServer.Certificate()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Targets = []*targetConfig{
+		{
+			Endpoint: pingEndpoint,
+			Send:     "PING\r\n",
+			Expect:   `^\+PONG`,
+		},
+		{
+			Endpoint: unreachableEndpoint,
+		},
+		{
+			Endpoint: tlsEndpoint,
+			TLSSetting: &configtls.ClientConfig{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+
+	s := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{
+		pingEndpoint:        1,
+		unreachableEndpoint: 0,
+		tlsEndpoint:         1,
+	}, gaugeOrSumValues(t, metrics, "tcpcheck.status"))
+	assert.Len(t, gaugeOrSumValues(t, metrics, "tcpcheck.duration"), 3)
+	assert.Equal(t, map[string]int64{
+		unreachableEndpoint: 1,
+	}, gaugeOrSumValues(t, metrics, "tcpcheck.error"))
+	assert.Equal(t, map[string]int64{
+		pingEndpoint: 1,
+	}, gaugeOrSumValues(t, metrics, "tcpcheck.expect_status"))
+	assert.Contains(t, gaugeOrSumValues(t, metrics, "tcpcheck.expect_duration"), pingEndpoint)
+	assert.Contains(t, gaugeOrSumValues(t, metrics, "tcpcheck.tls.handshake_duration"), tlsEndpoint)
+
+	timeLeft := gaugeOrSumValues(t, metrics, "tcpcheck.tls.cert_time_left")
+	require.Contains(t, timeLeft, tlsEndpoint)
+	assert.InDelta(t, time.Until(tlsServerCert.NotAfter).Seconds(), float64(timeLeft[tlsEndpoint]), 60)
+}
+
+func TestScrapeExpectMismatch(t *testing.T) {
+	pingEndpoint := newPingServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Targets = []*targetConfig{
+		{
+			Endpoint: pingEndpoint,
+			Timeout:  200 * time.Millisecond,
+			Send:     "PING\r\n",
+			Expect:   `^-ERR`,
+		},
+	}
+
+	s := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{pingEndpoint: 1}, gaugeOrSumValues(t, metrics, "tcpcheck.status"))
+	assert.Equal(t, map[string]int64{pingEndpoint: 0}, gaugeOrSumValues(t, metrics, "tcpcheck.expect_status"))
+	assert.Equal(t, map[string]int64{pingEndpoint: 1}, gaugeOrSumValues(t, metrics, "tcpcheck.error"))
+}
+
+func TestScrapeTLSVerificationFailure(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+	tlsEndpoint := tlsServer.Listener.Addr().String()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Targets = []*targetConfig{
+		{
+			Endpoint:   tlsEndpoint,
+			TLSSetting: &configtls.ClientConfig{},
+		},
+	}
+
+	s := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{tlsEndpoint: 1}, gaugeOrSumValues(t, metrics, "tcpcheck.status"))
+	assert.Equal(t, map[string]int64{tlsEndpoint: 1}, gaugeOrSumValues(t, metrics, "tcpcheck.error"))
+	assert.Contains(t, gaugeOrSumValues(t, metrics, "tcpcheck.tls.handshake_duration"), tlsEndpoint)
+	assert.Empty(t, gaugeOrSumValues(t, metrics, "tcpcheck.tls.cert_time_left"))
+}
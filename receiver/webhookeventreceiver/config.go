@@ -5,9 +5,11 @@ package webhookeventreceiver // import "github.com/open-telemetry/opentelemetry-
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.uber.org/multierr"
 )
 
@@ -16,6 +18,20 @@ var (
 	errReadTimeoutExceedsMaxValue  = errors.New("the duration specified for read_timeout exceeds the maximum allowed value of 10s")
 	errWriteTimeoutExceedsMaxValue = errors.New("the duration specified for write_timeout exceeds the maximum allowed value of 10s")
 	errRequiredHeader              = errors.New("both key and value are required to assign a required_header")
+	errMissingPath                 = errors.New("path must be specified")
+	errDuplicatePath               = errors.New("path is configured more than once")
+	errInvalidFormat               = errors.New("format must be one of text, json or ndjson")
+	errMissingSignatureHeader      = errors.New("header is required to verify signatures")
+	errMissingSignatureSecret      = errors.New("secret is required to verify signatures")
+	errInvalidSignatureAlgorithm   = errors.New("signature algorithm must be one of sha1, sha256 or sha512")
+	errInvalidSignatureEncoding    = errors.New("signature encoding must be either hex or base64")
+	errToleranceWithoutTimestamp   = errors.New("signature tolerance requires either timestamp_header or timestamp_prefix")
+)
+
+const (
+	formatText   = "text"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
 )
 
 // Config defines configuration for the Generic Webhook receiver.
@@ -26,6 +42,10 @@ type Config struct {
 	Path                    string                   `mapstructure:"path"`            // path for data collection. Default is /events
 	HealthPath              string                   `mapstructure:"health_path"`     // path for health check api. Default is /health_check
 	RequiredHeader          RequiredHeader           `mapstructure:"required_header"` // optional setting to set a required header for all requests to have
+	Signature               *SignatureConfig         `mapstructure:"signature"`       // optional HMAC signature verification of requests received on path
+	Format                  string                   `mapstructure:"format"`          // how request bodies received on path are turned into logs. Default is text
+	Attributes              map[string]string        `mapstructure:"attributes"`      // resource attributes added to logs received on path
+	Paths                   []PathConfig             `mapstructure:"paths"`           // additional paths, each with its own settings
 }
 
 type RequiredHeader struct {
@@ -33,6 +53,111 @@ type RequiredHeader struct {
 	Value string `mapstructure:"value"`
 }
 
+// PathConfig defines an additional path the receiver accepts events on.
+type PathConfig struct {
+	Path           string            `mapstructure:"path"`            // path for data collection
+	RequiredHeader RequiredHeader    `mapstructure:"required_header"` // optional setting to set a required header for all requests to have
+	Signature      *SignatureConfig  `mapstructure:"signature"`       // optional HMAC signature verification
+	Format         string            `mapstructure:"format"`          // how request bodies are turned into logs. Default is text
+	Attributes     map[string]string `mapstructure:"attributes"`      // resource attributes added to logs received on this path
+}
+
+// SignatureConfig defines how the HMAC signature sent along with a request is verified.
+type SignatureConfig struct {
+	// Header is the request header carrying the signature, e.g. X-Hub-Signature-256.
+	Header string `mapstructure:"header"`
+	// Secret is the shared secret the HMAC is keyed with.
+	Secret configopaque.String `mapstructure:"secret"`
+	// Algorithm is the hash function of the HMAC, one of sha1, sha256 or sha512. Default is sha256.
+	Algorithm string `mapstructure:"algorithm"`
+	// Encoding of the signature in the header, either hex or base64. Default is hex.
+	Encoding string `mapstructure:"encoding"`
+	// Prefix selects the signatures among the comma separated elements of the header, e.g. "sha256=" or "v1=".
+	// Any of the selected signatures may match, which allows secrets to be rotated.
+	Prefix string `mapstructure:"prefix"`
+	// TimestampHeader is the request header carrying the unix timestamp the request was signed at.
+	TimestampHeader string `mapstructure:"timestamp_header"`
+	// TimestampPrefix selects the element of the signature header carrying the unix timestamp, e.g. "t=".
+	TimestampPrefix string `mapstructure:"timestamp_prefix"`
+	// Payload is the template of the signed content. {body} is replaced by the request body and
+	// {timestamp} by the timestamp. Default is "{body}".
+	Payload string `mapstructure:"payload"`
+	// Tolerance is the maximum difference between the timestamp and the time the request is received.
+	// Zero disables the check.
+	Tolerance time.Duration `mapstructure:"tolerance"`
+}
+
+func (cfg *SignatureConfig) Validate() error {
+	var errs error
+
+	if cfg.Header == "" {
+		errs = multierr.Append(errs, errMissingSignatureHeader)
+	}
+
+	if cfg.Secret == "" {
+		errs = multierr.Append(errs, errMissingSignatureSecret)
+	}
+
+	if _, ok := hashFuncs[cfg.Algorithm]; !ok && cfg.Algorithm != "" {
+		errs = multierr.Append(errs, errInvalidSignatureAlgorithm)
+	}
+
+	switch cfg.Encoding {
+	case "", encodingHex, encodingBase64:
+	default:
+		errs = multierr.Append(errs, errInvalidSignatureEncoding)
+	}
+
+	if cfg.Tolerance != 0 && cfg.TimestampHeader == "" && cfg.TimestampPrefix == "" {
+		errs = multierr.Append(errs, errToleranceWithoutTimestamp)
+	}
+
+	return errs
+}
+
+func (cfg *PathConfig) Validate() error {
+	var errs error
+
+	if cfg.Path == "" {
+		errs = multierr.Append(errs, errMissingPath)
+	}
+
+	if (cfg.RequiredHeader.Key != "" && cfg.RequiredHeader.Value == "") || (cfg.RequiredHeader.Value != "" && cfg.RequiredHeader.Key == "") {
+		errs = multierr.Append(errs, errRequiredHeader)
+	}
+
+	if err := validateFormat(cfg.Format); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+
+	if cfg.Signature != nil {
+		errs = multierr.Append(errs, cfg.Signature.Validate())
+	}
+
+	return errs
+}
+
+func validateFormat(format string) error {
+	switch format {
+	case "", formatText, formatJSON, formatNDJSON:
+		return nil
+	default:
+		return errInvalidFormat
+	}
+}
+
+// pathConfigs returns the settings of every path the receiver accepts events on,
+// starting with the one configured at the top level.
+func (cfg *Config) pathConfigs() []PathConfig {
+	return append([]PathConfig{{
+		Path:           cfg.Path,
+		RequiredHeader: cfg.RequiredHeader,
+		Signature:      cfg.Signature,
+		Format:         cfg.Format,
+		Attributes:     cfg.Attributes,
+	}}, cfg.Paths...)
+}
+
 func (cfg *Config) Validate() error {
 	var errs error
 
@@ -70,5 +195,24 @@ func (cfg *Config) Validate() error {
 		errs = multierr.Append(errs, errRequiredHeader)
 	}
 
+	if err := validateFormat(cfg.Format); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+
+	if cfg.Signature != nil {
+		errs = multierr.Append(errs, cfg.Signature.Validate())
+	}
+
+	seen := map[string]bool{cfg.Path: true}
+	for i, pathCfg := range cfg.Paths {
+		if err := pathCfg.Validate(); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("paths[%d]: %w", i, err))
+		}
+		if seen[pathCfg.Path] {
+			errs = multierr.Append(errs, fmt.Errorf("paths[%d]: %w: %q", i, errDuplicatePath, pathCfg.Path))
+		}
+		seen[pathCfg.Path] = true
+	}
+
 	return errs
 }
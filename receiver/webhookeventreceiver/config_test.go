@@ -6,6 +6,7 @@ package webhookeventreceiver
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -86,6 +87,92 @@ func TestValidateConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:   "Invalid format",
+			expect: errInvalidFormat,
+			conf: Config{
+				ServerConfig: confighttp.ServerConfig{
+					Endpoint: "localhost:0",
+				},
+				Format: "xml",
+			},
+		},
+		{
+			desc:   "Signature without secret",
+			expect: errMissingSignatureSecret,
+			conf: Config{
+				ServerConfig: confighttp.ServerConfig{
+					Endpoint: "localhost:0",
+				},
+				Signature: &SignatureConfig{
+					Header: "X-Hub-Signature-256",
+				},
+			},
+		},
+		{
+			desc:   "Signature with invalid algorithm",
+			expect: errInvalidSignatureAlgorithm,
+			conf: Config{
+				ServerConfig: confighttp.ServerConfig{
+					Endpoint: "localhost:0",
+				},
+				Signature: &SignatureConfig{
+					Header:    "X-Signature",
+					Secret:    "secret",
+					Algorithm: "md5",
+				},
+			},
+		},
+		{
+			desc:   "Signature tolerance without timestamp",
+			expect: errToleranceWithoutTimestamp,
+			conf: Config{
+				ServerConfig: confighttp.ServerConfig{
+					Endpoint: "localhost:0",
+				},
+				Signature: &SignatureConfig{
+					Header:    "X-Signature",
+					Secret:    "secret",
+					Tolerance: time.Minute,
+				},
+			},
+		},
+		{
+			desc:   "Additional path without path",
+			expect: errMissingPath,
+			conf: Config{
+				ServerConfig: confighttp.ServerConfig{
+					Endpoint: "localhost:0",
+				},
+				Path:  "/events",
+				Paths: []PathConfig{{Format: formatJSON}},
+			},
+		},
+		{
+			desc:   "Duplicate path",
+			expect: errDuplicatePath,
+			conf: Config{
+				ServerConfig: confighttp.ServerConfig{
+					Endpoint: "localhost:0",
+				},
+				Path:  "/events",
+				Paths: []PathConfig{{Path: "/github"}, {Path: "/events"}},
+			},
+		},
+		{
+			desc:   "Additional path with invalid settings",
+			expect: errRequiredHeader,
+			conf: Config{
+				ServerConfig: confighttp.ServerConfig{
+					Endpoint: "localhost:0",
+				},
+				Path: "/events",
+				Paths: []PathConfig{{
+					Path:           "/github",
+					RequiredHeader: RequiredHeader{Key: "X-Token"},
+				}},
+			},
+		},
 		{
 			desc:   "Multiple invalid configs",
 			expect: errs,
@@ -145,3 +232,42 @@ func TestLoadConfig(t *testing.T) {
 
 	require.Equal(t, expect, conf)
 }
+
+func TestLoadConfigPaths(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "paths").String())
+	require.NoError(t, err)
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	require.Equal(t, formatNDJSON, cfg.Format)
+	require.Equal(t, []PathConfig{
+		{
+			Path:       "/github",
+			Format:     formatJSON,
+			Attributes: map[string]string{"webhook.source": "github"},
+			Signature: &SignatureConfig{
+				Header: "X-Hub-Signature-256",
+				Secret: "github-secret",
+				Prefix: "sha256=",
+			},
+		},
+		{
+			Path:   "/stripe",
+			Format: formatJSON,
+			Signature: &SignatureConfig{
+				Header:          "Stripe-Signature",
+				Secret:          "stripe-secret",
+				Prefix:          "v1=",
+				TimestampPrefix: "t=",
+				Payload:         "{timestamp}.{body}",
+				Tolerance:       5 * time.Minute,
+			},
+		},
+	}, cfg.Paths)
+}
@@ -4,19 +4,25 @@
 package webhookeventreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/webhookeventreceiver"
 
 import (
-	"bufio"
+	"bytes"
 	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/golang/snappy"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/julienschmidt/httprouter"
+	"github.com/klauspost/compress/zstd"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
@@ -32,10 +38,20 @@ var (
 	errInvalidEncodingType   = errors.New("invalid encoding type")
 	errEmptyResponseBody     = errors.New("request body content length is zero")
 	errMissingRequiredHeader = errors.New("request was missing required header or incorrect header value")
+	errInvalidRequestBody    = errors.New("request body could not be converted to logs")
 )
 
 const healthyResponse = `{"text": "Webhookevent receiver is healthy"}`
 
+// defaultMaxRequestBodySize is the maximum size of request bodies when max_request_body_size isn't set,
+// the same as the default of the HTTP server settings.
+const defaultMaxRequestBodySize = 20 * 1024 * 1024
+
+// passThroughEncodings are the content encodings the HTTP server would otherwise decompress before the
+// requests are handled. When signatures are verified, the server passes them through, and the receiver
+// decompresses the bodies once their signatures are verified over the bodies as sent.
+var passThroughEncodings = []string{"gzip", "zstd", "zlib", "snappy", "deflate"}
+
 type eventReceiver struct {
 	settings    receiver.Settings
 	cfg         *Config
@@ -44,6 +60,17 @@ type eventReceiver struct {
 	shutdownWG  sync.WaitGroup
 	obsrecv     *receiverhelper.ObsReport
 	gzipPool    *sync.Pool
+	routes      []*route
+	maxBodySize int64
+	// passThrough holds the content encodings the server passes through to the receiver, none unless
+	// one of the paths verifies signatures.
+	passThrough map[string]bool
+}
+
+// route holds the settings of a single path events are accepted on.
+type route struct {
+	cfg      PathConfig
+	verifier *signatureVerifier
 }
 
 func newLogsReceiver(params receiver.Settings, cfg Config, consumer consumer.Logs) (receiver.Logs, error) {
@@ -77,6 +104,28 @@ func newLogsReceiver(params receiver.Settings, cfg Config, consumer consumer.Log
 		logConsumer: consumer,
 		obsrecv:     obsrecv,
 		gzipPool:    &sync.Pool{New: func() any { return new(gzip.Reader) }},
+		maxBodySize: cfg.MaxRequestBodySize,
+	}
+	if er.maxBodySize <= 0 {
+		er.maxBodySize = defaultMaxRequestBodySize
+	}
+
+	for _, pathCfg := range cfg.pathConfigs() {
+		rt := &route{cfg: pathCfg}
+		if pathCfg.Signature != nil {
+			rt.verifier = newSignatureVerifier(pathCfg.Signature)
+		}
+		er.routes = append(er.routes, rt)
+	}
+
+	// The server decompresses the bodies for all the paths, it can only be bypassed for all of them.
+	if slices.ContainsFunc(er.routes, func(rt *route) bool { return rt.verifier != nil }) {
+		er.passThrough = map[string]bool{}
+		for _, encoding := range passThroughEncodings {
+			if cfg.CompressionAlgorithms == nil || slices.Contains(cfg.CompressionAlgorithms, encoding) {
+				er.passThrough[encoding] = true
+			}
+		}
 	}
 
 	return er, nil
@@ -99,10 +148,19 @@ func (er *eventReceiver) Start(ctx context.Context, host component.Host) error {
 	router := httprouter.New()
 
 	router.POST(er.cfg.Path, er.handleReq)
+	for _, rt := range er.routes[1:] {
+		router.POST(rt.cfg.Path, er.routeHandler(rt))
+	}
 	router.GET(er.cfg.HealthPath, er.handleHealthCheck)
 
 	// webhook server standup and configuration
-	er.server, err = er.cfg.ServerConfig.ToServer(ctx, host, er.settings.TelemetrySettings, router)
+	var opts []confighttp.ToServerOption
+	for encoding := range er.passThrough {
+		opts = append(opts, confighttp.WithDecoder(encoding, func(io.ReadCloser) (io.ReadCloser, error) {
+			return nil, nil
+		}))
+	}
+	er.server, err = er.cfg.ServerConfig.ToServer(ctx, host, er.settings.TelemetrySettings, router, opts...)
 	if err != nil {
 		return err
 	}
@@ -145,8 +203,20 @@ func (er *eventReceiver) Shutdown(_ context.Context) error {
 	return err
 }
 
-// handleReq handles incoming request from webhook. On success returns a 200 response code to the webhook
+// handleReq handles incoming request from webhook on the top level path. On success returns a 200 response code to the webhook
 func (er *eventReceiver) handleReq(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	er.handleRouteReq(er.routes[0], w, r)
+}
+
+// routeHandler returns the handler for requests received on one of the additional paths.
+func (er *eventReceiver) routeHandler(rt *route) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		er.handleRouteReq(rt, w, r)
+	}
+}
+
+// handleRouteReq handles incoming request from webhook using the settings of the route. On success returns a 200 response code to the webhook
+func (er *eventReceiver) handleRouteReq(rt *route, w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx = er.obsrecv.StartLogsOp(ctx)
 
@@ -155,17 +225,18 @@ func (er *eventReceiver) handleReq(w http.ResponseWriter, r *http.Request, _ htt
 		return
 	}
 
-	if er.cfg.RequiredHeader.Key != "" {
-		requiredHeaderValue := r.Header.Get(er.cfg.RequiredHeader.Key)
-		if requiredHeaderValue != er.cfg.RequiredHeader.Value {
+	if rt.cfg.RequiredHeader.Key != "" {
+		requiredHeaderValue := r.Header.Get(rt.cfg.RequiredHeader.Key)
+		if requiredHeaderValue != rt.cfg.RequiredHeader.Value {
 			er.failBadReq(ctx, w, http.StatusUnauthorized, errMissingRequiredHeader)
 			return
 		}
 	}
 
+	// the server already decompressed the bodies, unless it passes the encoding through. gzip is
+	// always supported if the encoding header is set.
 	encoding := r.Header.Get("Content-Encoding")
-	// only support gzip if encoding header is set.
-	if encoding != "" && encoding != "gzip" {
+	if encoding != "" && encoding != "gzip" && !er.passThrough[encoding] {
 		er.failBadReq(ctx, w, http.StatusUnsupportedMediaType, errInvalidEncodingType)
 		return
 	}
@@ -173,31 +244,40 @@ func (er *eventReceiver) handleReq(w http.ResponseWriter, r *http.Request, _ htt
 	if r.ContentLength == 0 {
 		er.obsrecv.EndLogsOp(ctx, metadata.Type.String(), 0, nil)
 		er.failBadReq(ctx, w, http.StatusBadRequest, errEmptyResponseBody)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, er.maxBodySize))
+	_ = r.Body.Close()
+	if err != nil {
+		er.failBadReq(ctx, w, readErrorStatus(err), err)
+		return
 	}
 
-	bodyReader := r.Body
-	// gzip encoded case
-	if encoding == "gzip" || encoding == "x-gzip" {
-		reader := er.gzipPool.Get().(*gzip.Reader)
-		err := reader.Reset(bodyReader)
+	// the signature is computed over the request body as sent by the webhook, before decompressing it
+	if rt.verifier != nil {
+		if err = rt.verifier.verify(r.Header, body); err != nil {
+			er.failBadReq(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+	}
 
-		if err != nil {
-			er.failBadReq(ctx, w, http.StatusBadRequest, err)
-			_, _ = io.ReadAll(r.Body)
-			_ = r.Body.Close()
+	if encoding != "" {
+		if body, err = er.decompress(w, encoding, body); err != nil {
+			er.failBadReq(ctx, w, readErrorStatus(err), err)
 			return
 		}
-		bodyReader = reader
-		defer er.gzipPool.Put(reader)
 	}
 
 	// finish reading the body into a log
-	sc := bufio.NewScanner(bodyReader)
-	ld, numLogs := reqToLog(sc, r.URL.Query(), er.cfg, er.settings)
+	ld, numLogs, err := reqToLog(body, r.URL.Query(), &rt.cfg, er.settings)
+	if err != nil {
+		er.failBadReq(ctx, w, http.StatusBadRequest, fmt.Errorf("%w: %w", errInvalidRequestBody, err))
+		er.obsrecv.EndLogsOp(ctx, metadata.Type.String(), 0, err)
+		return
+	}
 	consumerErr := er.logConsumer.ConsumeLogs(ctx, ld)
 
-	_ = bodyReader.Close()
-
 	if consumerErr != nil {
 		er.failBadReq(ctx, w, http.StatusInternalServerError, consumerErr)
 		er.obsrecv.EndLogsOp(ctx, metadata.Type.String(), numLogs, nil)
@@ -207,6 +287,49 @@ func (er *eventReceiver) handleReq(w http.ResponseWriter, r *http.Request, _ htt
 	}
 }
 
+// decompress decompresses the request body passed through by the server, up to the maximum body size.
+// The encodings are decoded the same way as the server would have.
+func (er *eventReceiver) decompress(w http.ResponseWriter, encoding string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	switch encoding {
+	case "gzip":
+		gr := er.gzipPool.Get().(*gzip.Reader)
+		defer er.gzipPool.Put(gr)
+		if err := gr.Reset(bytes.NewReader(body)); err != nil {
+			return nil, err
+		}
+		reader = gr
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		reader = zr
+	case "zlib", "deflate":
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		reader = zr
+	case "snappy":
+		reader = snappy.NewReader(bytes.NewReader(body))
+	default:
+		return nil, errInvalidEncodingType
+	}
+	return io.ReadAll(http.MaxBytesReader(w, io.NopCloser(reader), er.maxBodySize))
+}
+
+// readErrorStatus returns the response code of a request whose body couldn't be read.
+func readErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
 // Simple healthcheck endpoint.
 func (er *eventReceiver) handleHealthCheck(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	w.Header().Add("Content-Type", "application/json")
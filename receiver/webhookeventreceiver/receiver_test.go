@@ -7,6 +7,8 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -15,12 +17,15 @@ import (
 	"testing"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
 )
 
 func TestCreateNewLogReceiver(t *testing.T) {
@@ -240,3 +245,171 @@ func TestHealthCheck(t *testing.T) {
 	response := w.Result()
 	require.Equal(t, http.StatusOK, response.StatusCode)
 }
+
+func TestMultiplePaths(t *testing.T) {
+	const secret = "webhook-secret"
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.Paths = []PathConfig{
+		{
+			Path:   "/github",
+			Format: formatJSON,
+			Signature: &SignatureConfig{
+				Header: "X-Hub-Signature-256",
+				Secret: secret,
+				Prefix: "sha256=",
+			},
+			Attributes: map[string]string{"webhook.source": "github"},
+		},
+		{
+			Path:           "/pagerduty",
+			Format:         formatNDJSON,
+			RequiredHeader: RequiredHeader{Key: "X-Token", Value: "token"},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopSettings(), *cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	}()
+
+	post := func(path, body string, header http.Header) int {
+		req, err := http.NewRequest(http.MethodPost, "http://"+cfg.Endpoint+path, strings.NewReader(body))
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	githubBody := `[{"action":"opened"},{"action":"closed"}]`
+	githubSignature := "sha256=" + hex.EncodeToString(sign(sha256.New, secret, githubBody))
+
+	require.Equal(t, http.StatusOK, post("/events", "line1\nline2", nil))
+	require.Equal(t, http.StatusUnauthorized, post("/github", githubBody, http.Header{"X-Hub-Signature-256": {"sha256=00"}}))
+	require.Equal(t, http.StatusOK, post("/github", githubBody, http.Header{"X-Hub-Signature-256": {githubSignature}}))
+	require.Equal(t, http.StatusUnauthorized, post("/pagerduty", `{"id":"1"}`, nil))
+	require.Equal(t, http.StatusOK, post("/pagerduty", "{\"id\":\"1\"}\n{\"id\":\"2\"}\n{\"id\":\"3\"}", http.Header{"X-Token": {"token"}}))
+	require.Equal(t, http.StatusBadRequest, post("/pagerduty", "not json", http.Header{"X-Token": {"token"}}))
+
+	// the signature of a compressed request is computed over the body as sent
+	gzipBody := gzipString(t, githubBody)
+	require.Equal(t, http.StatusUnauthorized, post("/github", gzipBody, http.Header{"Content-Encoding": {"gzip"}, "X-Hub-Signature-256": {githubSignature}}))
+	gzipSignature := "sha256=" + hex.EncodeToString(sign(sha256.New, secret, gzipBody))
+	require.Equal(t, http.StatusOK, post("/github", gzipBody, http.Header{"Content-Encoding": {"gzip"}, "X-Hub-Signature-256": {gzipSignature}}))
+
+	// the other encodings the server passes through are decompressed by the receiver, with or without signatures
+	zstdBody := zstdString(t, githubBody)
+	zstdSignature := "sha256=" + hex.EncodeToString(sign(sha256.New, secret, zstdBody))
+	require.Equal(t, http.StatusOK, post("/github", zstdBody, http.Header{"Content-Encoding": {"zstd"}, "X-Hub-Signature-256": {zstdSignature}}))
+	require.Equal(t, http.StatusOK, post("/pagerduty", zstdString(t, `{"id":"4"}`), http.Header{"Content-Encoding": {"zstd"}, "X-Token": {"token"}}))
+
+	logs := sink.AllLogs()
+	require.Len(t, logs, 6)
+
+	require.Equal(t, 2, logs[0].LogRecordCount())
+	require.Equal(t, "line1", logs[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+
+	require.Equal(t, 2, logs[1].LogRecordCount())
+	source, ok := logs[1].ResourceLogs().At(0).Resource().Attributes().Get("webhook.source")
+	require.True(t, ok)
+	require.Equal(t, "github", source.Str())
+	require.Equal(t, map[string]any{"action": "closed"}, logs[1].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(1).Body().AsRaw())
+
+	require.Equal(t, 3, logs[2].LogRecordCount())
+
+	require.Equal(t, 2, logs[3].LogRecordCount())
+	require.Equal(t, map[string]any{"action": "opened"}, logs[3].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().AsRaw())
+
+	require.Equal(t, 2, logs[4].LogRecordCount())
+	require.Equal(t, 1, logs[5].LogRecordCount())
+	require.Equal(t, map[string]any{"id": "4"}, logs[5].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().AsRaw())
+}
+
+func TestCompressedRequestsWithoutSignatures(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopSettings(), *cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	}()
+
+	// without signatures to verify, the server decompresses the bodies
+	for encoding, body := range map[string]string{"gzip": gzipString(t, "event"), "zstd": zstdString(t, "event")} {
+		req, err := http.NewRequest(http.MethodPost, "http://"+cfg.Endpoint+"/events", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Encoding", encoding)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode, encoding)
+	}
+
+	logs := sink.AllLogs()
+	require.Len(t, logs, 2)
+	for _, ld := range logs {
+		require.Equal(t, "event", ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	}
+}
+
+func TestMaxRequestBodySize(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.MaxRequestBodySize = 1024
+
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopSettings(), *cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	}()
+
+	post := func(body string, header http.Header) int {
+		req, err := http.NewRequest(http.MethodPost, "http://"+cfg.Endpoint+"/events", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header = header
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, post(strings.Repeat("a", 1024), nil))
+	require.Equal(t, http.StatusRequestEntityTooLarge, post(strings.Repeat("a", 1025), nil))
+	// the limit applies to the decompressed body too
+	require.Equal(t, http.StatusOK, post(gzipString(t, strings.Repeat("a", 1024)), http.Header{"Content-Encoding": {"gzip"}}))
+	require.Equal(t, http.StatusRequestEntityTooLarge, post(gzipString(t, strings.Repeat("a", 10000)), http.Header{"Content-Encoding": {"gzip"}}))
+	require.Len(t, sink.AllLogs(), 2)
+}
+
+func gzipString(t *testing.T, s string) string {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	_, err := gzipWriter.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+	return buf.String()
+}
+
+func zstdString(t *testing.T, s string) string {
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer encoder.Close()
+	return string(encoder.EncodeAll([]byte(s), nil))
+}
@@ -5,9 +5,12 @@ package webhookeventreceiver // import "github.com/open-telemetry/opentelemetry-
 
 import (
 	"bufio"
+	"bytes"
+	"fmt"
 	"net/url"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/receiver"
@@ -15,13 +18,13 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/webhookeventreceiver/internal/metadata"
 )
 
-func reqToLog(sc *bufio.Scanner,
+func reqToLog(body []byte,
 	query url.Values,
-	_ *Config,
-	settings receiver.Settings) (plog.Logs, int) {
+	cfg *PathConfig,
+	settings receiver.Settings) (plog.Logs, int, error) {
 	log := plog.NewLogs()
 	resourceLog := log.ResourceLogs().AppendEmpty()
-	appendMetadata(resourceLog, query)
+	appendMetadata(resourceLog, query, cfg.Attributes)
 	scopeLog := resourceLog.ScopeLogs().AppendEmpty()
 
 	scopeLog.Scope().SetName(scopeLogName)
@@ -29,18 +32,90 @@ func reqToLog(sc *bufio.Scanner,
 	scopeLog.Scope().Attributes().PutStr("source", settings.ID.String())
 	scopeLog.Scope().Attributes().PutStr("receiver", metadata.Type.String())
 
-	for sc.Scan() {
+	var err error
+	switch cfg.Format {
+	case formatJSON:
+		err = appendJSON(scopeLog, body)
+	case formatNDJSON:
+		err = appendNDJSON(scopeLog, body)
+	default:
+		appendLines(scopeLog, body)
+	}
+	if err != nil {
+		return plog.NewLogs(), 0, err
+	}
+
+	return log, scopeLog.LogRecords().Len(), nil
+}
+
+// appendLines turns every line of the body into a log record with a string body.
+func appendLines(scopeLog plog.ScopeLogs, body []byte) {
+	for _, line := range scanLines(body) {
 		logRecord := scopeLog.LogRecords().AppendEmpty()
 		logRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
-		line := sc.Text()
-		logRecord.Body().SetStr(line)
+		logRecord.Body().SetStr(string(line))
+	}
+}
+
+// appendJSON parses the body as a single JSON document. A top level array is split into
+// one log record per element, any other value becomes a single log record.
+func appendJSON(scopeLog plog.ScopeLogs, body []byte) error {
+	var doc any
+	if err := jsoniter.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("failed to parse request body as json: %w", err)
 	}
 
-	return log, scopeLog.LogRecords().Len()
+	if elements, ok := doc.([]any); ok {
+		for _, element := range elements {
+			if err := appendJSONRecord(scopeLog, element); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return appendJSONRecord(scopeLog, doc)
 }
 
-// append query parameters and webhook source as resource attributes
-func appendMetadata(resourceLog plog.ResourceLogs, query url.Values) {
+// appendNDJSON parses every non-empty line of the body as a JSON document and turns it into a log record.
+func appendNDJSON(scopeLog plog.ScopeLogs, body []byte) error {
+	for i, line := range scanLines(body) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var doc any
+		if err := jsoniter.Unmarshal(line, &doc); err != nil {
+			return fmt.Errorf("failed to parse line %d of request body as json: %w", i+1, err)
+		}
+		if err := appendJSONRecord(scopeLog, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendJSONRecord(scopeLog plog.ScopeLogs, doc any) error {
+	logRecord := scopeLog.LogRecords().AppendEmpty()
+	logRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	return logRecord.Body().FromRaw(doc)
+}
+
+// scanLines splits the body into lines the same way a bufio.Scanner does, without limiting the line length.
+func scanLines(body []byte) [][]byte {
+	var lines [][]byte
+	for len(body) > 0 {
+		advance, line, _ := bufio.ScanLines(body, true)
+		lines = append(lines, line)
+		body = body[advance:]
+	}
+	return lines
+}
+
+// append query parameters, configured attributes and webhook source as resource attributes
+func appendMetadata(resourceLog plog.ResourceLogs, query url.Values, attributes map[string]string) {
+	for k, v := range attributes {
+		resourceLog.Resource().Attributes().PutStr(k, v)
+	}
+
 	for k := range query {
 		if query.Get(k) != "" {
 			resourceLog.Resource().Attributes().PutStr(k, query.Get(k))
@@ -4,9 +4,6 @@
 package webhookeventreceiver
 
 import (
-	"bufio"
-	"bytes"
-	"io"
 	"log"
 	"net/url"
 	"testing"
@@ -22,16 +19,13 @@ func TestReqToLog(t *testing.T) {
 
 	tests := []struct {
 		desc  string
-		sc    *bufio.Scanner
+		body  []byte
 		query url.Values
 		tt    func(t *testing.T, reqLog plog.Logs, reqLen int, settings receiver.Settings)
 	}{
 		{
 			desc: "Valid query valid event",
-			sc: func() *bufio.Scanner {
-				reader := io.NopCloser(bytes.NewReader([]byte("this is a: log")))
-				return bufio.NewScanner(reader)
-			}(),
+			body: []byte("this is a: log"),
 			query: func() url.Values {
 				v, err := url.ParseQuery(`qparam1=hello&qparam2=world`)
 				if err != nil {
@@ -62,10 +56,7 @@ func TestReqToLog(t *testing.T) {
 		},
 		{
 			desc: "Query is empty",
-			sc: func() *bufio.Scanner {
-				reader := io.NopCloser(bytes.NewReader([]byte("this is a: log")))
-				return bufio.NewScanner(reader)
-			}(),
+			body: []byte("this is a: log"),
 			tt: func(t *testing.T, reqLog plog.Logs, reqLen int, _ receiver.Settings) {
 				require.Equal(t, 1, reqLen)
 
@@ -80,8 +71,84 @@ func TestReqToLog(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			reqLog, reqLen := reqToLog(test.sc, test.query, defaultConfig, receivertest.NewNopSettings())
+			reqLog, reqLen, err := reqToLog(test.body, test.query, &defaultConfig.pathConfigs()[0], receivertest.NewNopSettings())
+			require.NoError(t, err)
 			test.tt(t, reqLog, reqLen, receivertest.NewNopSettings())
 		})
 	}
 }
+
+func TestReqToLogFormats(t *testing.T) {
+	tests := []struct {
+		desc       string
+		cfg        PathConfig
+		body       string
+		expectErr  bool
+		expectBody []any
+	}{
+		{
+			desc:       "text splits lines",
+			cfg:        PathConfig{Format: formatText},
+			body:       "first line\r\nsecond line\n",
+			expectBody: []any{"first line", "second line"},
+		},
+		{
+			desc:       "json object becomes a map",
+			cfg:        PathConfig{Format: formatJSON},
+			body:       `{"action":"opened","number":1,"labels":["bug"]}`,
+			expectBody: []any{map[string]any{"action": "opened", "number": float64(1), "labels": []any{"bug"}}},
+		},
+		{
+			desc:       "json array is split",
+			cfg:        PathConfig{Format: formatJSON},
+			body:       `[{"id":"a"},{"id":"b"},"c"]`,
+			expectBody: []any{map[string]any{"id": "a"}, map[string]any{"id": "b"}, "c"},
+		},
+		{
+			desc:      "invalid json",
+			cfg:       PathConfig{Format: formatJSON},
+			body:      `{"id":`,
+			expectErr: true,
+		},
+		{
+			desc:       "ndjson skips empty lines",
+			cfg:        PathConfig{Format: formatNDJSON},
+			body:       "{\"id\":\"a\"}\n\n{\"id\":\"b\"}\n",
+			expectBody: []any{map[string]any{"id": "a"}, map[string]any{"id": "b"}},
+		},
+		{
+			desc:      "invalid ndjson line",
+			cfg:       PathConfig{Format: formatNDJSON},
+			body:      "{\"id\":\"a\"}\nnot json\n",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			reqLog, reqLen, err := reqToLog([]byte(test.body), nil, &test.cfg, receivertest.NewNopSettings())
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, len(test.expectBody), reqLen)
+
+			records := reqLog.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+			for i, expected := range test.expectBody {
+				require.Equal(t, expected, records.At(i).Body().AsRaw())
+			}
+		})
+	}
+}
+
+func TestReqToLogAttributes(t *testing.T) {
+	query, err := url.ParseQuery("source=query")
+	require.NoError(t, err)
+
+	cfg := &PathConfig{Attributes: map[string]string{"source": "github", "team": "platform"}}
+	reqLog, _, err := reqToLog([]byte("event"), query, cfg, receivertest.NewNopSettings())
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]any{"source": "query", "team": "platform"}, reqLog.ResourceLogs().At(0).Resource().Attributes().AsRaw())
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookeventreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/webhookeventreceiver"
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- sha1 is still used by webhook providers to sign requests
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	encodingHex    = "hex"
+	encodingBase64 = "base64"
+
+	defaultSignatureAlgorithm = "sha256"
+	defaultSignaturePayload   = "{body}"
+)
+
+var (
+	errMissingSignature   = errors.New("request was missing a signature")
+	errInvalidSignature   = errors.New("request signature did not match")
+	errMissingTimestamp   = errors.New("request was missing a signature timestamp")
+	errInvalidTimestamp   = errors.New("request signature timestamp is not a unix timestamp")
+	errTimestampTolerance = errors.New("request signature timestamp is outside of the tolerance")
+)
+
+var hashFuncs = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// signatureVerifier checks the HMAC signature of requests as configured by a SignatureConfig.
+type signatureVerifier struct {
+	cfg     *SignatureConfig
+	hash    func() hash.Hash
+	decode  func(string) ([]byte, error)
+	payload string
+	now     func() time.Time
+}
+
+func newSignatureVerifier(cfg *SignatureConfig) *signatureVerifier {
+	algorithm := cfg.Algorithm
+	if algorithm == "" {
+		algorithm = defaultSignatureAlgorithm
+	}
+
+	decode := hex.DecodeString
+	if cfg.Encoding == encodingBase64 {
+		decode = base64.StdEncoding.DecodeString
+	}
+
+	payload := cfg.Payload
+	if payload == "" {
+		payload = defaultSignaturePayload
+	}
+
+	return &signatureVerifier{
+		cfg:     cfg,
+		hash:    hashFuncs[algorithm],
+		decode:  decode,
+		payload: payload,
+		now:     time.Now,
+	}
+}
+
+// verify returns an error unless one of the signatures sent in the request header
+// matches the HMAC of the signed payload.
+func (v *signatureVerifier) verify(header http.Header, body []byte) error {
+	signatures, timestamp := v.parseHeader(header.Get(v.cfg.Header))
+	if len(signatures) == 0 {
+		return errMissingSignature
+	}
+
+	if v.cfg.TimestampHeader != "" {
+		timestamp = header.Get(v.cfg.TimestampHeader)
+	}
+	if err := v.checkTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	mac := hmac.New(v.hash, []byte(v.cfg.Secret))
+	before, after, hasBody := strings.Cut(v.payload, "{body}")
+	_, _ = mac.Write([]byte(strings.ReplaceAll(before, "{timestamp}", timestamp)))
+	if hasBody {
+		_, _ = mac.Write(body)
+		_, _ = mac.Write([]byte(strings.ReplaceAll(after, "{timestamp}", timestamp)))
+	}
+	expected := mac.Sum(nil)
+
+	for _, signature := range signatures {
+		decoded, err := v.decode(signature)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return errInvalidSignature
+}
+
+// parseHeader splits the signature header into its comma separated elements and returns
+// the signatures selected by the configured prefix as well as the embedded timestamp, if any.
+func (v *signatureVerifier) parseHeader(value string) (signatures []string, timestamp string) {
+	if value == "" {
+		return nil, ""
+	}
+	for _, element := range strings.Split(value, ",") {
+		element = strings.TrimSpace(element)
+		switch {
+		case v.cfg.TimestampPrefix != "" && strings.HasPrefix(element, v.cfg.TimestampPrefix):
+			timestamp = strings.TrimPrefix(element, v.cfg.TimestampPrefix)
+		case strings.HasPrefix(element, v.cfg.Prefix):
+			signatures = append(signatures, strings.TrimPrefix(element, v.cfg.Prefix))
+		}
+	}
+	return signatures, timestamp
+}
+
+func (v *signatureVerifier) checkTimestamp(timestamp string) error {
+	if v.cfg.TimestampHeader == "" && v.cfg.TimestampPrefix == "" {
+		return nil
+	}
+	if timestamp == "" {
+		return errMissingTimestamp
+	}
+	if v.cfg.Tolerance == 0 {
+		return nil
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errInvalidTimestamp
+	}
+	diff := v.now().Sub(time.Unix(seconds, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > v.cfg.Tolerance {
+		return errTimestampTolerance
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookeventreceiver
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- used to sign test requests
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sign(h func() hash.Hash, secret, payload string) []byte {
+	mac := hmac.New(h, []byte(secret))
+	_, _ = mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func TestSignatureVerifier(t *testing.T) {
+	const (
+		secret = "It's a Secret to Everybody"
+		body   = `{"action":"opened"}`
+	)
+	now := time.Unix(1700000000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		desc      string
+		cfg       SignatureConfig
+		header    http.Header
+		expectErr error
+	}{
+		{
+			desc: "github sha256",
+			cfg:  SignatureConfig{Header: "X-Hub-Signature-256", Secret: secret, Prefix: "sha256="},
+			header: http.Header{
+				"X-Hub-Signature-256": {"sha256=" + hex.EncodeToString(sign(sha256.New, secret, body))},
+			},
+		},
+		{
+			desc: "github sha1",
+			cfg:  SignatureConfig{Header: "X-Hub-Signature", Secret: secret, Algorithm: "sha1", Prefix: "sha1="},
+			header: http.Header{
+				"X-Hub-Signature": {"sha1=" + hex.EncodeToString(sign(sha1.New, secret, body))},
+			},
+		},
+		{
+			desc: "wrong secret",
+			cfg:  SignatureConfig{Header: "X-Hub-Signature-256", Secret: secret, Prefix: "sha256="},
+			header: http.Header{
+				"X-Hub-Signature-256": {"sha256=" + hex.EncodeToString(sign(sha256.New, "other", body))},
+			},
+			expectErr: errInvalidSignature,
+		},
+		{
+			desc:      "missing signature",
+			cfg:       SignatureConfig{Header: "X-Hub-Signature-256", Secret: secret, Prefix: "sha256="},
+			header:    http.Header{},
+			expectErr: errMissingSignature,
+		},
+		{
+			desc: "pagerduty with rotated secrets",
+			cfg:  SignatureConfig{Header: "X-PagerDuty-Signature", Secret: secret, Prefix: "v1="},
+			header: http.Header{
+				"X-Pagerduty-Signature": {"v1=" + hex.EncodeToString(sign(sha256.New, "old", body)) + ",v1=" + hex.EncodeToString(sign(sha256.New, secret, body))},
+			},
+		},
+		{
+			desc: "stripe with embedded timestamp",
+			cfg: SignatureConfig{
+				Header:          "Stripe-Signature",
+				Secret:          secret,
+				Prefix:          "v1=",
+				TimestampPrefix: "t=",
+				Payload:         "{timestamp}.{body}",
+				Tolerance:       5 * time.Minute,
+			},
+			header: http.Header{
+				"Stripe-Signature": {"t=" + ts + ",v1=" + hex.EncodeToString(sign(sha256.New, secret, ts+"."+body)) + ",v0=deadbeef"},
+			},
+		},
+		{
+			desc: "stripe with expired timestamp",
+			cfg: SignatureConfig{
+				Header:          "Stripe-Signature",
+				Secret:          secret,
+				Prefix:          "v1=",
+				TimestampPrefix: "t=",
+				Payload:         "{timestamp}.{body}",
+				Tolerance:       5 * time.Minute,
+			},
+			header: http.Header{
+				"Stripe-Signature": {"t=1600000000,v1=" + hex.EncodeToString(sign(sha256.New, secret, "1600000000."+body))},
+			},
+			expectErr: errTimestampTolerance,
+		},
+		{
+			desc: "slack with timestamp header",
+			cfg: SignatureConfig{
+				Header:          "X-Slack-Signature",
+				Secret:          secret,
+				Prefix:          "v0=",
+				TimestampHeader: "X-Slack-Request-Timestamp",
+				Payload:         "v0:{timestamp}:{body}",
+				Tolerance:       5 * time.Minute,
+			},
+			header: http.Header{
+				"X-Slack-Signature":         {"v0=" + hex.EncodeToString(sign(sha256.New, secret, "v0:"+ts+":"+body))},
+				"X-Slack-Request-Timestamp": {ts},
+			},
+		},
+		{
+			desc: "missing timestamp header",
+			cfg: SignatureConfig{
+				Header:          "X-Slack-Signature",
+				Secret:          secret,
+				Prefix:          "v0=",
+				TimestampHeader: "X-Slack-Request-Timestamp",
+				Payload:         "v0:{timestamp}:{body}",
+			},
+			header: http.Header{
+				"X-Slack-Signature": {"v0=" + hex.EncodeToString(sign(sha256.New, secret, "v0::"+body))},
+			},
+			expectErr: errMissingTimestamp,
+		},
+		{
+			desc: "base64 encoded signature without prefix",
+			cfg:  SignatureConfig{Header: "X-Signature", Secret: secret, Encoding: encodingBase64},
+			header: http.Header{
+				"X-Signature": {base64.StdEncoding.EncodeToString(sign(sha256.New, secret, body))},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			require.NoError(t, test.cfg.Validate())
+			v := newSignatureVerifier(&test.cfg)
+			v.now = func() time.Time { return now }
+
+			err := v.verify(test.header, []byte(body))
+			if test.expectErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, test.expectErr)
+			}
+		})
+	}
+}